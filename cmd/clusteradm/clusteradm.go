@@ -3,8 +3,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -23,26 +25,56 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"open-cluster-management.io/clusteradm"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clierror"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/i18n"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+	"open-cluster-management.io/clusteradm/pkg/helpers/readonly"
+	"open-cluster-management.io/clusteradm/pkg/helpers/restconfig"
+	"open-cluster-management.io/clusteradm/pkg/helpers/stamp"
+	"open-cluster-management.io/clusteradm/pkg/helpers/tlspolicy"
+	"open-cluster-management.io/clusteradm/pkg/helpers/tracing"
 
 	// commands
 	acceptclusters "open-cluster-management.io/clusteradm/pkg/cmd/accept"
 	addon "open-cluster-management.io/clusteradm/pkg/cmd/addon"
+	"open-cluster-management.io/clusteradm/pkg/cmd/attach"
+	"open-cluster-management.io/clusteradm/pkg/cmd/claim"
 	clean "open-cluster-management.io/clusteradm/pkg/cmd/clean"
+	clustercmd "open-cluster-management.io/clusteradm/pkg/cmd/cluster"
 	"open-cluster-management.io/clusteradm/pkg/cmd/clusterset"
 	"open-cluster-management.io/clusteradm/pkg/cmd/create"
 	deletecmd "open-cluster-management.io/clusteradm/pkg/cmd/delete"
+	"open-cluster-management.io/clusteradm/pkg/cmd/dev"
+	"open-cluster-management.io/clusteradm/pkg/cmd/doctor"
+	"open-cluster-management.io/clusteradm/pkg/cmd/generate"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get"
+	"open-cluster-management.io/clusteradm/pkg/cmd/history"
+	"open-cluster-management.io/clusteradm/pkg/cmd/hub"
 	inithub "open-cluster-management.io/clusteradm/pkg/cmd/init"
 	install "open-cluster-management.io/clusteradm/pkg/cmd/install"
 	joinhub "open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/cmd/login"
+	"open-cluster-management.io/clusteradm/pkg/cmd/patch"
+	"open-cluster-management.io/clusteradm/pkg/cmd/placement"
 	"open-cluster-management.io/clusteradm/pkg/cmd/proxy"
+	"open-cluster-management.io/clusteradm/pkg/cmd/renew"
+	"open-cluster-management.io/clusteradm/pkg/cmd/rollout"
+	"open-cluster-management.io/clusteradm/pkg/cmd/serve"
 	unjoin "open-cluster-management.io/clusteradm/pkg/cmd/unjoin"
 	"open-cluster-management.io/clusteradm/pkg/cmd/upgrade"
 	"open-cluster-management.io/clusteradm/pkg/cmd/version"
+	"open-cluster-management.io/clusteradm/pkg/cmd/work"
 )
 
 func main() {
+	endCommandSpan := func() {}
+	shutdownTracing := func(context.Context) error { return nil }
 	root :=
 		&cobra.Command{
 			Use: "clusteradm",
@@ -53,6 +85,8 @@ func main() {
 				https://github.com/open-cluster-management-io/clusteradm/blob/main/README.md
 			`),
 			Run: runHelp,
+			// the errors are printed with their code and remediation once classified
+			SilenceErrors: true,
 		}
 
 	flags := root.PersistentFlags()
@@ -60,6 +94,11 @@ func main() {
 	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
 
 	kubeConfigFlags := genericclioptions.NewConfigFlags(true).WithDeprecatedPasswordFlag()
+	kubeConfigFlags.WrapConfigFn = restconfig.Wrap
+	// the discovery and hub metadata are cached under the user cache directory
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		*kubeConfigFlags.CacheDir = filepath.Join(cacheDir, "clusteradm")
+	}
 	kubeConfigFlags.AddFlags(flags)
 	matchVersionKubeConfigFlags := cmdutil.NewMatchVersionFlags(kubeConfigFlags)
 	matchVersionKubeConfigFlags.AddFlags(flags)
@@ -74,6 +113,53 @@ func main() {
 	clusteradmFlags := genericclioptionsclusteradm.NewClusteradmFlags(f)
 	clusteradmFlags.AddFlags(flags)
 	clusteradmFlags.SetContext(kubeConfigFlags.Context)
+	clusteradmFlags.SetCacheDir(kubeConfigFlags.CacheDir)
+
+	// the tracing is set up once the flags are parsed
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// the rbac of the command is printed without any call to the hub
+		if clusteradmFlags.ShowRequiredRBAC {
+			return rbac.ShowRequired(cmd, streams.Out)
+		}
+
+		shutdown, err := tracing.Setup(context.Background(), clusteradmFlags.OtelEndpoint)
+		if err != nil {
+			return err
+		}
+		shutdownTracing = shutdown
+		endCommandSpan = tracing.StartCommand(cmd.CommandPath())
+
+		if err := i18n.Setup(clusteradmFlags.Lang); err != nil {
+			return err
+		}
+		printer.SetupColor(clusteradmFlags.NoColor)
+		readonly.Setup(clusteradmFlags.ReadOnly)
+		// the labels and annotations of init, join and addon
+		if err := stamp.Setup(); err != nil {
+			return err
+		}
+
+		if err := tlspolicy.Setup(clusteradmFlags.TLSMinVersion, clusteradmFlags.TLSCipherSuites); err != nil {
+			return err
+		}
+
+		// target the named or current hub before any client is built
+		if err := clusteradmFlags.UseHub(cmd, kubeConfigFlags); err != nil {
+			return err
+		}
+		clusteradmFlags.DetectInCluster(kubeConfigFlags)
+
+		// the objects changed by the command are annotated with it
+		audit.Setup(cmd, kubeConfigFlags.ToRawKubeConfigLoader(), *kubeConfigFlags.Context, clusteradm.GetVersion())
+
+		// refresh the cached discovery, the hub metadata cache is skipped by the commands
+		if clusteradmFlags.NoCache {
+			if discoveryClient, err := f.ToDiscoveryClient(); err == nil {
+				discoveryClient.Invalidate()
+			}
+		}
+		return nil
+	}
 
 	// From this point and forward we get warnings on flags that contain "_" separators
 
@@ -89,8 +175,17 @@ func main() {
 			Commands: []*cobra.Command{
 				create.NewCmd(clusteradmFlags, streams),
 				deletecmd.NewCmd(clusteradmFlags, streams),
+				dev.NewCmd(clusteradmFlags, streams),
+				doctor.NewCmd(clusteradmFlags, streams),
+				generate.NewCmd(clusteradmFlags, streams),
 				get.NewCmd(clusteradmFlags, streams),
+				history.NewCmd(clusteradmFlags, streams),
+				hub.NewCmd(clusteradmFlags, streams),
 				install.NewCmd(clusteradmFlags, streams),
+				login.NewCmd(clusteradmFlags, streams),
+				patch.NewCmd(clusteradmFlags, streams),
+				rollout.NewCmd(clusteradmFlags, streams),
+				serve.NewCmd(clusteradmFlags, streams),
 				upgrade.NewCmd(clusteradmFlags, streams),
 				version.NewCmd(clusteradmFlags, streams),
 			},
@@ -99,9 +194,11 @@ func main() {
 			Message: "Registration commands:",
 			Commands: []*cobra.Command{
 				acceptclusters.NewCmd(clusteradmFlags, streams),
+				attach.NewCmd(clusteradmFlags, streams),
 				clean.NewCmd(clusteradmFlags, streams),
 				inithub.NewCmd(clusteradmFlags, streams),
 				joinhub.NewCmd(clusteradmFlags, streams),
+				renew.NewCmd(clusteradmFlags, streams),
 				unjoin.NewCmd(clusteradmFlags, streams),
 			},
 		},
@@ -109,8 +206,12 @@ func main() {
 			Message: "Cluster Management commands:",
 			Commands: []*cobra.Command{
 				addon.NewCmd(clusteradmFlags, streams),
+				claim.NewCmd(clusteradmFlags, streams),
+				clustercmd.NewCmd(clusteradmFlags, streams),
 				clusterset.NewCmd(clusteradmFlags, streams),
+				placement.NewCmd(clusteradmFlags, streams),
 				proxy.NewCmd(clusteradmFlags, streams),
+				work.NewCmd(clusteradmFlags, streams),
 			},
 		},
 	}
@@ -123,10 +224,16 @@ func main() {
 	err := root.Execute()
 	if err != nil {
 		klog.V(1).ErrorS(err, "Error:")
+		err = clierror.Classify(err)
+		clierror.Print(streams.ErrOut, err, helpers.GetExampleHeader())
+	}
+	endCommandSpan()
+	if err := shutdownTracing(context.Background()); err != nil {
+		klog.Warningf("failed to export the spans: %v", err)
 	}
 	klog.Flush()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(int(exit.CodeOf(err)))
 	}
 }
 
@@ -0,0 +1,82 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package accept is the Go API of 'clusteradm accept', it approves the CSRs of the managed clusters and
+// accepts them on the hub, for the controllers and provisioning services embedding clusteradm.
+package accept
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	acceptcmd "open-cluster-management.io/clusteradm/pkg/cmd/accept"
+	"open-cluster-management.io/clusteradm/pkg/helpers/runner"
+)
+
+// Options are the options of the accept, the zero values are the defaults of the CLI
+type Options struct {
+	// Clusters are the names of the managed clusters to accept
+	Clusters []string
+	// Wait waits for the CSRs of the clusters until they are approved or the timeout
+	Wait bool
+	// Timeout of Wait, the time left to the deadline of the context if not set, else 300s
+	Timeout time.Duration
+	// SkipApproveCheck approves the CSRs without checking they are requested by the bootstrap identities
+	SkipApproveCheck bool
+	// DryRun reports the CSRs and clusters which would be changed in the Result without changing them
+	DryRun bool
+	// Out and ErrOut receive the messages of the CLI, they are discarded if nil
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// Result is what the accept changed, or would change with DryRun
+type Result struct {
+	// ApprovedCSRs are the names of the approved CSRs
+	ApprovedCSRs []string
+	// AcceptedClusters are the managed clusters set to hubAcceptsClient=true, the clusters which were
+	// already accepted are not included
+	AcceptedClusters []string
+}
+
+// Validate checks the options without connecting to the hub
+func (o Options) Validate() error {
+	if len(o.Clusters) == 0 {
+		return fmt.Errorf("the clusters to accept must be specified")
+	}
+	for _, cluster := range o.Clusters {
+		if errs := validation.IsDNS1123Label(cluster); len(errs) > 0 {
+			return fmt.Errorf("invalid cluster name %q: %v", cluster, errs)
+		}
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("the timeout should not be negative")
+	}
+	return nil
+}
+
+// Run accepts the clusters on the hub of the getter. The result is returned with the error if only some
+// of the clusters are accepted.
+func Run(ctx context.Context, getter genericclioptions.RESTClientGetter, o Options) (*Result, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	flags := runner.NewFlags(getter, o.DryRun, runner.Timeout(ctx, o.Timeout))
+	options := acceptcmd.NewOptions(flags, runner.Streams(o.Out, o.ErrOut))
+	options.Values.Clusters = o.Clusters
+	options.Wait = o.Wait
+	options.SkipApproveCheck = o.SkipApproveCheck
+	options.MaxLeaseDuration = 5 * time.Minute
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	err := options.Run()
+	csrs, clusters := options.Approved()
+	return &Result{ApprovedCSRs: csrs, AcceptedClusters: clusters}, err
+}
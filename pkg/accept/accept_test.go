@@ -0,0 +1,35 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		options     Options
+		expectedErr bool
+	}{
+		{name: "valid", options: Options{Clusters: []string{"cluster1", "cluster2"}}},
+		{name: "no cluster", options: Options{}, expectedErr: true},
+		{name: "invalid cluster", options: Options{Clusters: []string{"Cluster_1"}}, expectedErr: true},
+		{name: "negative timeout", options: Options{Clusters: []string{"cluster1"}, Timeout: -1}, expectedErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.options.Validate(); (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, nil, Options{Clusters: []string{"cluster1"}}); err != context.Canceled {
+		t.Errorf("expected the canceled context error, got %v", err)
+	}
+}
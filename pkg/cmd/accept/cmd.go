@@ -3,9 +3,11 @@ package accept
 
 import (
 	"fmt"
+	"time"
 
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -16,6 +18,16 @@ var example = `
 %[1]s accept --clusters <cluster_1>,<cluster_2>,...
 # Accept clusters in foreground
 %[1]s accept --clusters <cluster_1>,<cluster_2>,... --wait
+# Accept clusters and let a team target them with placements in its namespace
+%[1]s accept --clusters <cluster_1> --grant-namespace team-ns --grant-group dev-team
+# Accept clusters and run a script then install a GitOps agent once they are available
+%[1]s accept --clusters <cluster_1> --post-accept-exec ./onboard.sh --post-accept-work gitops-agent/
+# Accept clusters only if their CSR and their API server match the expected ones
+%[1]s accept --clusters <cluster_1> --verify-agent-identity --expected-endpoints expected.yaml
+# Accept clusters and post the accepted and rejected ones to a Slack channel
+%[1]s accept --clusters <cluster_1> --verify-agent-identity --expected-endpoints expected.yaml --notify-url https://hooks.slack.com/services/<id>
+# Report the CSRs and the clusters which would be accepted without changing them
+%[1]s accept --clusters <cluster_1>,<cluster_2> --dry-run
 `
 
 // NewCmd ...
@@ -34,10 +46,10 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.complete(c, args); err != nil {
-				return err
+				return exit.Validation(err)
 			}
 			if err := o.Validate(); err != nil {
-				return err
+				return exit.Validation(err)
 			}
 			if err := o.Run(); err != nil {
 				return err
@@ -50,5 +62,24 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd.Flags().StringVar(&o.Clusters, "clusters", "", "Names of the cluster to accept (comma separated)")
 	cmd.Flags().BoolVar(&o.Wait, "wait", false, "If set, wait for the managedcluster and CSR in foreground.")
 	cmd.Flags().BoolVar(&o.SkipApproveCheck, "skip-approve-check", false, "If set, then skip check and approve csr directly.")
+	cmd.Flags().StringVar(&o.GrantNamespace, "grant-namespace", "",
+		"If set, the clusterset of the accepted clusters is bound to this namespace, which is created if missing")
+	cmd.Flags().StringVar(&o.GrantGroup, "grant-group", "",
+		"The group granted the admin role of --grant-namespace and the view role of the clusterset, to target the clusters with placements")
+	cmd.Flags().StringVar(&o.BootstrapNamespace, "bootstrap-namespace", "",
+		"The namespace of the bootstrap service account requesting the csr, discovered from the bootstrap cluster role binding if not set")
+	cmd.Flags().StringVar(&o.PostAcceptExec, "post-accept-exec", "",
+		"An executable run once an accepted cluster is joined and available, with the cluster name as argument and "+
+			"the CLUSTER_NAME, CLUSTER_SET and CLUSTER_API_SERVER environment variables")
+	cmd.Flags().StringVar(&o.PostAcceptWork, "post-accept-work", "",
+		fmt.Sprintf("A file or directory of manifests applied in the work %s once an accepted cluster is joined and available", postAcceptWorkName))
+	cmd.Flags().BoolVar(&o.VerifyAgentIdentity, "verify-agent-identity", false,
+		"If set, the CSR must be a client certificate request of the registration agent of the cluster without subject alternative names, "+
+			"and the cluster must report the API server URLs and the cluster id of --expected-endpoints, or it is not accepted")
+	cmd.Flags().StringVar(&o.ExpectedEndpoints, "expected-endpoints", "",
+		"A file of the expected identities of the clusters: clusters: [{name, apiServerURLs: [...], clusterID}]")
+	cmd.Flags().DurationVar(&o.MaxLeaseDuration, "max-lease-duration", 5*time.Minute,
+		"A warning is printed if the lease duration of the managed cluster exceeds it, as the hub detects an unavailable cluster after 5 lease durations.")
+	o.notify.AddFlags(cmd.Flags())
 	return cmd
 }
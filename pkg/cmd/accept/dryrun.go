@@ -0,0 +1,70 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+)
+
+// approvalReport is what accept changed, or would change without --dry-run
+type approvalReport struct {
+	// csrs are the CSRs which are, or would be, approved
+	csrs []certificatesv1.CertificateSigningRequest
+	// acceptedClusters are the managed clusters which get, or would get, hubAcceptsClient=true
+	acceptedClusters []string
+}
+
+// Approved returns the names of the CSRs approved and of the managed clusters accepted by Run, the ones
+// which would be with --dry-run
+func (o *Options) Approved() (csrs []string, clusters []string) {
+	csrs = []string{}
+	for _, csr := range o.report.csrs {
+		csrs = append(csrs, csr.Name)
+	}
+	return csrs, append([]string{}, o.report.acceptedClusters...)
+}
+
+// addCSR adds the csr once, --wait may check the same csr again
+func (r *approvalReport) addCSR(csr certificatesv1.CertificateSigningRequest) {
+	for _, reported := range r.csrs {
+		if reported.Name == csr.Name {
+			return
+		}
+	}
+	r.csrs = append(r.csrs, csr)
+}
+
+func (r *approvalReport) addAcceptedCluster(clusterName string) {
+	for _, reported := range r.acceptedClusters {
+		if reported == clusterName {
+			return
+		}
+	}
+	r.acceptedClusters = append(r.acceptedClusters, clusterName)
+}
+
+// print writes a table of the CSRs with their requester identity, then the clusters which would be accepted
+func (r *approvalReport) print(out io.Writer) error {
+	fmt.Fprintln(out, "\nCSRs which would be approved:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCLUSTER\tREQUESTER\tGROUPS\tSIGNER")
+	for _, csr := range r.csrs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", csr.Name, csr.Labels[clusterLabel], csr.Spec.Username,
+			strings.Join(csr.Spec.Groups, ","), csr.Spec.SignerName)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\nManaged clusters which would get hubAcceptsClient=true:")
+	if len(r.acceptedClusters) == 0 {
+		fmt.Fprintln(out, "<none>")
+	}
+	for _, clusterName := range r.acceptedClusters {
+		fmt.Fprintln(out, clusterName)
+	}
+	return nil
+}
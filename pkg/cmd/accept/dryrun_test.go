@@ -0,0 +1,53 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApprovalReport(t *testing.T) {
+	csr := certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1-abc", Labels: map[string]string{clusterLabel: "cluster1"}},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Username:   "system:serviceaccount:open-cluster-management:cluster-bootstrap",
+			Groups:     []string{"system:serviceaccounts", "system:serviceaccounts:open-cluster-management"},
+			SignerName: certificatesv1.KubeAPIServerClientSignerName,
+		},
+	}
+	report := &approvalReport{}
+	report.addCSR(csr)
+	report.addCSR(csr)
+	report.addAcceptedCluster("cluster1")
+	report.addAcceptedCluster("cluster1")
+
+	out := &bytes.Buffer{}
+	if err := report.print(out); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "cluster1-abc"); n != 1 {
+		t.Errorf("expected the csr to be reported once, got %d times in %q", n, out.String())
+	}
+	for _, want := range []string{
+		"system:serviceaccount:open-cluster-management:cluster-bootstrap",
+		"system:serviceaccounts,system:serviceaccounts:open-cluster-management",
+		certificatesv1.KubeAPIServerClientSignerName,
+		"hubAcceptsClient=true:\ncluster1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the report to contain %q, got %q", want, out.String())
+		}
+	}
+
+	empty := &bytes.Buffer{}
+	if err := (&approvalReport{}).print(empty); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(empty.String(), "<none>") {
+		t.Errorf("expected no cluster to be reported, got %q", empty.String())
+	}
+}
@@ -3,7 +3,9 @@ package accept
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"os/exec"
 	"strings"
 	"time"
 
@@ -19,14 +21,20 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/i18n"
+	"open-cluster-management.io/clusteradm/pkg/helpers/notify"
 )
 
 const (
 	groupNameBootstrap               = "system:bootstrappers:managedcluster"
 	userNameSignatureBootstrapPrefix = "system:bootstrap:"
-	userNameSignatureSA              = "system:serviceaccount:open-cluster-management:cluster-bootstrap"
-	groupNameSA                      = "system:serviceaccounts:open-cluster-management"
+	userNameSignatureSAFormat        = "system:serviceaccount:%s:%s"
+	groupNameSAFormat                = "system:serviceaccounts:%s"
 	clusterLabel                     = "open-cluster-management.io/cluster-name"
 )
 
@@ -55,6 +63,35 @@ func (o *Options) Validate() error {
 	if err != nil {
 		return err
 	}
+	if err := o.notify.Validate(); err != nil {
+		return err
+	}
+	if (len(o.GrantNamespace) == 0) != (len(o.GrantGroup) == 0) {
+		return fmt.Errorf("--grant-namespace and --grant-group should be set together")
+	}
+	if len(o.PostAcceptExec) > 0 {
+		if _, err := exec.LookPath(o.PostAcceptExec); err != nil {
+			return fmt.Errorf("--post-accept-exec %s is not executable: %v", o.PostAcceptExec, err)
+		}
+	}
+	if o.VerifyAgentIdentity {
+		if len(o.ExpectedEndpoints) == 0 {
+			return fmt.Errorf("--expected-endpoints is required with --verify-agent-identity")
+		}
+		if o.expectedIdentities, err = loadExpectedIdentities(o.ExpectedEndpoints); err != nil {
+			return fmt.Errorf("failed to read --expected-endpoints %s: %v", o.ExpectedEndpoints, err)
+		}
+		for _, clusterName := range o.Values.Clusters {
+			if _, ok := o.expectedIdentities[clusterName]; !ok {
+				return fmt.Errorf("cluster %s is not in --expected-endpoints %s", clusterName, o.ExpectedEndpoints)
+			}
+		}
+	}
+	if len(o.PostAcceptWork) > 0 {
+		if o.postAcceptManifests, err = readPostAcceptManifests(o.PostAcceptWork); err != nil {
+			return fmt.Errorf("failed to read --post-accept-work %s: %v", o.PostAcceptWork, err)
+		}
+	}
 
 	return nil
 }
@@ -72,23 +109,45 @@ func (o *Options) Run() error {
 	if err != nil {
 		return err
 	}
-	return o.runWithClient(kubeClient, clusterClient)
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	o.hubHost = restConfig.Host
+	return o.runWithClient(kubeClient, clusterClient, workClient)
 }
 
-func (o *Options) runWithClient(kubeClient *kubernetes.Clientset, clusterClient *clusterclientset.Clientset) error {
+func (o *Options) runWithClient(kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface, workClient workclientset.Interface) error {
+	if len(o.BootstrapNamespace) == 0 {
+		var err error
+		if o.BootstrapNamespace, err = helpers.GetBootstrapSANamespace(context.TODO(), kubeClient); err != nil {
+			return err
+		}
+	}
 	var errs []error
 	for _, clusterName := range o.Values.Clusters {
 		if !o.Wait {
 			approved, err := o.accept(kubeClient, clusterClient, clusterName, false)
+			if approved || err != nil {
+				o.notifyResult(clusterName, err)
+			}
 			if err != nil {
 				errs = append(errs, err)
 			}
 			if !approved {
 				errs = append(errs, fmt.Errorf("no csr is approved yet for cluster %s", clusterName))
 			}
+			if err == nil && approved {
+				errs = append(errs, o.postAccept(clusterClient, workClient, clusterName))
+			}
 		} else {
 			err := wait.PollImmediate(1*time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
 				approved, err := o.accept(kubeClient, clusterClient, clusterName, true)
+				// a cluster without the expected identity is never accepted
+				var identityErr *identityError
+				if stderrors.As(err, &identityErr) {
+					return false, err
+				}
 				if !approved {
 					return false, nil
 				}
@@ -97,26 +156,68 @@ func (o *Options) runWithClient(kubeClient *kubernetes.Clientset, clusterClient
 				}
 				return true, err
 			})
+			o.notifyResult(clusterName, err)
+			if err == nil {
+				err = o.postAccept(clusterClient, workClient, clusterName)
+			}
 			errs = append(errs, err)
 		}
 	}
-	return utilerrors.NewAggregate(errs)
+	if o.ClusteradmFlags.DryRun {
+		if err := o.report.print(o.Streams.Out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := utilerrors.NewAggregate(errs)
+	// some of the clusters are accepted
+	if err != nil && len(err.Errors()) < len(o.Values.Clusters) {
+		return exit.Partial(err)
+	}
+	return err
 }
 
-func (o *Options) accept(kubeClient *kubernetes.Clientset, clusterClient *clusterclientset.Clientset, clusterName string, waitMode bool) (bool, error) {
+func (o *Options) accept(kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface, clusterName string, waitMode bool) (bool, error) {
+	if o.VerifyAgentIdentity {
+		if err := o.verifyIdentity(clusterClient, clusterName); err != nil {
+			return false, err
+		}
+	}
 	approved, err := o.approveCSR(kubeClient, clusterName, waitMode)
 	if err != nil {
-		return approved, fmt.Errorf("fail to approve the csr for cluster %s: %v", clusterName, err)
+		return approved, fmt.Errorf("fail to approve the csr for cluster %s: %w", clusterName, err)
 	}
 	err = o.updateManagedCluster(clusterClient, clusterName)
 	if err != nil {
 		return approved, err
 	}
-	fmt.Fprintf(o.Streams.Out, "\n Your managed cluster %s has joined the Hub successfully. Visit https://open-cluster-management.io/scenarios or https://github.com/open-cluster-management-io/OCM/tree/main/solutions for next steps.\n", clusterName)
+	if len(o.GrantNamespace) > 0 {
+		if err := o.grantNamespace(kubeClient, clusterClient, clusterName); err != nil {
+			return approved, fmt.Errorf("fail to grant namespace %s access to cluster %s: %v", o.GrantNamespace, clusterName, err)
+		}
+	}
+	if o.ClusteradmFlags.DryRun {
+		return approved, nil
+	}
+	fmt.Fprint(o.Streams.Out, i18n.T("accept.joined", clusterName))
 	return approved, nil
 }
 
-func (o *Options) approveCSR(kubeClient *kubernetes.Clientset, clusterName string, waitMode bool) (bool, error) {
+// notifyResult notifies that the cluster is accepted, or rejected if it does not match its expected identity,
+// the other errors are not notified as the cluster may be accepted by the next run
+func (o *Options) notifyResult(clusterName string, err error) {
+	if o.ClusteradmFlags.DryRun {
+		return
+	}
+	var identityErr *identityError
+	switch {
+	case err == nil:
+		o.notify.Notify(notify.NewEvent(notify.EventAccepted, clusterName, o.hubHost, ""), o.Streams.ErrOut)
+	case stderrors.As(err, &identityErr):
+		o.notify.Notify(notify.NewEvent(notify.EventRejected, clusterName, o.hubHost, strings.Join(identityErr.reasons, ", ")), o.Streams.ErrOut)
+	}
+}
+
+func (o *Options) approveCSR(kubeClient kubernetes.Interface, clusterName string, waitMode bool) (bool, error) {
 	var hasApproved bool
 	csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(context.TODO(),
 		metav1.ListOptions{
@@ -131,6 +232,8 @@ func (o *Options) approveCSR(kubeClient *kubernetes.Clientset, clusterName strin
 	if o.SkipApproveCheck {
 		passedCSRs = csrs.Items
 	} else {
+		userNameSignatureSA := fmt.Sprintf(userNameSignatureSAFormat, o.BootstrapNamespace, config.BootstrapSAName)
+		groupNameSA := fmt.Sprintf(groupNameSAFormat, o.BootstrapNamespace)
 		for _, item := range csrs.Items {
 			//Does not have the correct name prefix
 			if !strings.HasPrefix(item.Spec.Username, userNameSignatureBootstrapPrefix) &&
@@ -163,6 +266,11 @@ func (o *Options) approveCSR(kubeClient *kubernetes.Clientset, clusterName strin
 			hasApproved = true
 			continue
 		}
+		if o.VerifyAgentIdentity {
+			if err := verifyCSR(&passedCSR, clusterName); err != nil {
+				return hasApproved, err
+			}
+		}
 		csrToApprove = append(csrToApprove, passedCSR)
 	}
 
@@ -174,9 +282,13 @@ func (o *Options) approveCSR(kubeClient *kubernetes.Clientset, clusterName strin
 
 		return hasApproved, nil
 	}
-	//if dry-run don't approve
+	//if dry-run don't approve, report the csrs which would be approved
 	if o.ClusteradmFlags.DryRun {
-		return hasApproved, nil
+		for _, csr := range csrToApprove {
+			fmt.Fprintf(o.Streams.Out, "CSR %s requested by %s would be approved\n", csr.Name, csr.Spec.Username)
+			o.report.addCSR(csr)
+		}
+		return true, nil
 	}
 
 	var errs []error
@@ -198,24 +310,46 @@ func (o *Options) approveCSR(kubeClient *kubernetes.Clientset, clusterName strin
 			errs = append(errs, err)
 		} else {
 			fmt.Fprintf(o.Streams.Out, "CSR %s approved\n", csr.Name)
+			o.report.addCSR(csr)
 			hasApproved = true
 		}
 	}
 	return hasApproved, utilerrors.NewAggregate(errs)
 }
 
-func (o *Options) updateManagedCluster(clusterClient *clusterclientset.Clientset, clusterName string) error {
+// verifyIdentity checks the managed cluster reports its expected endpoints before its CSR is approved
+func (o *Options) verifyIdentity(clusterClient clusterclientset.Interface, clusterName string) error {
+	mc, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	expected := o.expectedIdentities[clusterName]
+	verifiedID, err := verifyManagedCluster(mc, expected)
+	if err != nil {
+		return err
+	}
+	if len(expected.ClusterID) > 0 && !verifiedID {
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: cluster %s reports its cluster id once accepted, "+
+			"run accept --verify-agent-identity again to verify it\n", clusterName)
+	}
+	return nil
+}
+
+func (o *Options) updateManagedCluster(clusterClient clusterclientset.Interface, clusterName string) error {
 	mc, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(),
 		clusterName,
 		metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
+	o.checkLeaseDuration(mc)
 	if mc.Spec.HubAcceptsClient {
 		fmt.Fprintf(o.Streams.Out, "hubAcceptsClient already set for managed cluster %s\n", clusterName)
 		return nil
 	}
 	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "hubAcceptsClient would be set to true for managed cluster %s\n", clusterName)
+		o.report.addAcceptedCluster(clusterName)
 		return nil
 	}
 	if !mc.Spec.HubAcceptsClient {
@@ -225,10 +359,21 @@ func (o *Options) updateManagedCluster(clusterClient *clusterclientset.Clientset
 			return err
 		}
 		fmt.Fprintf(o.Streams.Out, "set hubAcceptsClient to true for managed cluster %s\n", clusterName)
+		o.report.addAcceptedCluster(clusterName)
 	}
 	return nil
 }
 
+// checkLeaseDuration warns if the lease duration set by the cluster at join exceeds the hub expectation
+func (o *Options) checkLeaseDuration(mc *clusterv1.ManagedCluster) {
+	leaseDuration := time.Duration(mc.Spec.LeaseDurationSeconds) * time.Second
+	if o.MaxLeaseDuration > 0 && leaseDuration > o.MaxLeaseDuration {
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: the lease duration %s of managed cluster %s exceeds %s, "+
+			"the hub takes %s to detect the cluster is unavailable\n",
+			leaseDuration, mc.Name, o.MaxLeaseDuration, 5*leaseDuration)
+	}
+}
+
 func GetCertApprovalCondition(status *certificatesv1.CertificateSigningRequestStatus) (approved bool, denied bool) {
 	for _, c := range status.Conditions {
 		if c.Type == certificatesv1.CertificateApproved {
@@ -0,0 +1,109 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterset"
+)
+
+// grantAdminRoleBinding is the name of the role binding granting the namespace admin role to the group
+const grantAdminRoleBinding = "open-cluster-management:clusterset-admin"
+
+// grantNamespace lets the group target the accepted cluster with placements in the grant namespace:
+// the clusterset of the cluster is bound to the namespace, the group is admin of the namespace
+// and can view the clusterset.
+func (o *Options) grantNamespace(kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface, clusterName string) error {
+	mc, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	clusterSet := mc.Labels[clusterset.ClusterSetLabel]
+	if len(clusterSet) == 0 {
+		return fmt.Errorf("managed cluster %s is not in a clusterset, add it with `clusteradm clusterset set` to grant access", clusterName)
+	}
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "clusterset %s would be bound to namespace %s for group %s\n", clusterSet, o.GrantNamespace, o.GrantGroup)
+		return nil
+	}
+
+	// namespace
+	_, err = kubeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: o.GrantNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	// clusterset binding
+	_, err = clusterClient.ClusterV1beta1().ManagedClusterSetBindings(o.GrantNamespace).Create(context.TODO(), &clusterv1beta1.ManagedClusterSetBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterSet,
+			Namespace: o.GrantNamespace,
+		},
+		Spec: clusterv1beta1.ManagedClusterSetBindingSpec{
+			ClusterSet: clusterSet,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	// namespace admin
+	subject, err := clusterset.Subject(rbacv1.GroupKind, o.GrantGroup)
+	if err != nil {
+		return err
+	}
+	roleBinding, err := kubeClient.RbacV1().RoleBindings(o.GrantNamespace).Get(context.TODO(), grantAdminRoleBinding, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = kubeClient.RbacV1().RoleBindings(o.GrantNamespace).Create(context.TODO(), &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      grantAdminRoleBinding,
+				Namespace: o.GrantNamespace,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     "admin",
+			},
+			Subjects: []rbacv1.Subject{subject},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case !hasSubject(roleBinding.Subjects, subject):
+		roleBinding.Subjects = append(roleBinding.Subjects, subject)
+		if _, err := kubeClient.RbacV1().RoleBindings(o.GrantNamespace).Update(context.TODO(), roleBinding, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	// clusterset view
+	if _, err := clusterset.Grant(context.TODO(), kubeClient, clusterSet, clusterset.RoleView, subject); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Streams.Out, "clusterset %s is bound to namespace %s, group %s can target managed cluster %s with placements\n",
+		clusterSet, o.GrantNamespace, o.GrantGroup, clusterName)
+	return nil
+}
+
+func hasSubject(subjects []rbacv1.Subject, subject rbacv1.Subject) bool {
+	for _, s := range subjects {
+		if s.Kind == subject.Kind && s.Name == subject.Name && s.Namespace == subject.Namespace {
+			return true
+		}
+	}
+	return false
+}
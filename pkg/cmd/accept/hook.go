@@ -0,0 +1,142 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// postAcceptWorkName is the name of the work applied to the accepted clusters with --post-accept-work
+const postAcceptWorkName = "post-accept"
+
+// readPostAcceptManifests reads the manifests of the work applied to the accepted clusters
+func readPostAcceptManifests(path string) ([]workapiv1.Manifest, error) {
+	result := resource.NewLocalBuilder().
+		Unstructured().
+		FilenameParam(false, &resource.FilenameOptions{Filenames: []string{path}, Recursive: true}).
+		Flatten().
+		Do()
+	infos, err := result.Infos()
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("no manifest found in %s", path)
+	}
+	manifests := []workapiv1.Manifest{}
+	for _, info := range infos {
+		manifests = append(manifests, workapiv1.Manifest{RawExtension: runtime.RawExtension{Object: info.Object}})
+	}
+	return manifests, nil
+}
+
+// isClusterReady returns true once the cluster is joined and available
+func isClusterReady(mc *clusterv1.ManagedCluster) bool {
+	return meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionJoined) &&
+		meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+}
+
+// hookEnv returns the environment variables describing the cluster to the post accept hook
+func hookEnv(mc *clusterv1.ManagedCluster) []string {
+	env := []string{
+		"CLUSTER_NAME=" + mc.Name,
+		"CLUSTER_SET=" + mc.Labels[clusterv1beta1.ClusterSetLabel],
+	}
+	if len(mc.Spec.ManagedClusterClientConfigs) > 0 {
+		env = append(env, "CLUSTER_API_SERVER="+mc.Spec.ManagedClusterClientConfigs[0].URL)
+	}
+	return env
+}
+
+// postAccept waits for the accepted cluster to be joined and available, then runs the hook and applies the work
+func (o *Options) postAccept(clusterClient clusterclientset.Interface, workClient workclientset.Interface, clusterName string) error {
+	if len(o.PostAcceptExec) == 0 && len(o.postAcceptManifests) == 0 {
+		return nil
+	}
+	if o.ClusteradmFlags.DryRun {
+		if len(o.PostAcceptExec) > 0 {
+			fmt.Fprintf(o.Streams.Out, "%s would run once cluster %s is available\n", o.PostAcceptExec, clusterName)
+		}
+		if len(o.postAcceptManifests) > 0 {
+			fmt.Fprintf(o.Streams.Out, "work %s would be applied once cluster %s is available\n", postAcceptWorkName, clusterName)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(o.Streams.Out, "Waiting for the managed cluster %s to be available...\n", clusterName)
+	var mc *clusterv1.ManagedCluster
+	err := wait.PollImmediate(2*time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		var err error
+		mc, err = clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return isClusterReady(mc), nil
+	})
+	if err != nil {
+		return fmt.Errorf("the post accept hooks of cluster %s are not run, the cluster is not available: %v", clusterName, err)
+	}
+
+	if len(o.PostAcceptExec) > 0 {
+		cmd := exec.Command(o.PostAcceptExec, clusterName)
+		cmd.Env = append(os.Environ(), hookEnv(mc)...)
+		cmd.Stdout = o.Streams.Out
+		cmd.Stderr = o.Streams.ErrOut
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("the post accept hook %s failed for cluster %s: %v", o.PostAcceptExec, clusterName, err)
+		}
+	}
+	if len(o.postAcceptManifests) > 0 {
+		if err := o.applyPostAcceptWork(workClient, clusterName); err != nil {
+			return fmt.Errorf("failed to apply work %s to cluster %s: %v", postAcceptWorkName, clusterName, err)
+		}
+	}
+	return nil
+}
+
+func (o *Options) applyPostAcceptWork(workClient workclientset.Interface, clusterName string) error {
+	work, err := workClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), postAcceptWorkName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		work = &workapiv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      postAcceptWorkName,
+				Namespace: clusterName,
+			},
+			Spec: workapiv1.ManifestWorkSpec{
+				Workload: workapiv1.ManifestsTemplate{
+					Manifests: o.postAcceptManifests,
+				},
+			},
+		}
+		if _, err := workClient.WorkV1().ManifestWorks(clusterName).Create(context.TODO(), work, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "create work %s in cluster %s\n", postAcceptWorkName, clusterName)
+		return nil
+	case err != nil:
+		return err
+	}
+
+	work.Spec.Workload.Manifests = o.postAcceptManifests
+	if _, err := workClient.WorkV1().ManifestWorks(clusterName).Update(context.TODO(), work, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "update work %s in cluster %s\n", postAcceptWorkName, clusterName)
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+func TestIsClusterReady(t *testing.T) {
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		want       bool
+	}{
+		{
+			name: "no conditions",
+		},
+		{
+			name:       "joined",
+			conditions: []metav1.Condition{condition(clusterv1.ManagedClusterConditionJoined, metav1.ConditionTrue)},
+		},
+		{
+			name: "joined and unavailable",
+			conditions: []metav1.Condition{
+				condition(clusterv1.ManagedClusterConditionJoined, metav1.ConditionTrue),
+				condition(clusterv1.ManagedClusterConditionAvailable, metav1.ConditionUnknown),
+			},
+		},
+		{
+			name: "joined and available",
+			conditions: []metav1.Condition{
+				condition(clusterv1.ManagedClusterConditionJoined, metav1.ConditionTrue),
+				condition(clusterv1.ManagedClusterConditionAvailable, metav1.ConditionTrue),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &clusterv1.ManagedCluster{Status: clusterv1.ManagedClusterStatus{Conditions: tt.conditions}}
+			if got := isClusterReady(mc); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cluster1",
+			Labels: map[string]string{clusterv1beta1.ClusterSetLabel: "dev"},
+		},
+		Spec: clusterv1.ManagedClusterSpec{
+			ManagedClusterClientConfigs: []clusterv1.ClientConfig{{URL: "https://cluster1:6443"}},
+		},
+	}
+	want := []string{"CLUSTER_NAME=cluster1", "CLUSTER_SET=dev", "CLUSTER_API_SERVER=https://cluster1:6443"}
+	if got := hookEnv(mc); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestReadPostAcceptManifests(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := readPostAcceptManifests(dir); err == nil {
+		t.Errorf("expected an error for a directory without manifests")
+	}
+
+	configMaps := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+  namespace: default
+`
+	if err := os.WriteFile(filepath.Join(dir, "configmaps.yaml"), []byte(configMaps), 0600); err != nil {
+		t.Fatal(err)
+	}
+	manifests, err := readPostAcceptManifests(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 {
+		t.Errorf("expected 2 manifests, got %d", len(manifests))
+	}
+}
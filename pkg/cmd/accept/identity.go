@@ -0,0 +1,135 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// clusterIDClaim is the claim of the unique identifier of the cluster reported by the agent
+	clusterIDClaim = "id.k8s.io"
+	// agentUserPrefix prefixes the common name and the organization of the CSR of the registration agent
+	agentUserPrefix = "system:open-cluster-management:"
+)
+
+// ExpectedIdentities is the file of --expected-endpoints
+type ExpectedIdentities struct {
+	Clusters []ExpectedIdentity `json:"clusters"`
+}
+
+// ExpectedIdentity is the identity a cluster must report to be accepted with --verify-agent-identity
+type ExpectedIdentity struct {
+	Name string `json:"name"`
+	// APIServerURLs are the URLs the cluster may report in its client configs
+	APIServerURLs []string `json:"apiServerURLs"`
+	// ClusterID is the id.k8s.io claim of the cluster, not verified if empty
+	ClusterID string `json:"clusterID,omitempty"`
+}
+
+// identityError is returned if a cluster does not report the expected identity, the cluster is not accepted
+type identityError struct {
+	cluster string
+	reasons []string
+}
+
+func (e *identityError) Error() string {
+	return fmt.Sprintf("cluster %s does not match its expected identity: %s", e.cluster, strings.Join(e.reasons, ", "))
+}
+
+// loadExpectedIdentities reads the expected identities of the clusters by name
+func loadExpectedIdentities(path string) (map[string]ExpectedIdentity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	identities := &ExpectedIdentities{}
+	if err := yaml.UnmarshalStrict(data, identities); err != nil {
+		return nil, err
+	}
+	expected := map[string]ExpectedIdentity{}
+	for _, identity := range identities.Clusters {
+		if len(identity.Name) == 0 {
+			return nil, fmt.Errorf("a cluster has no name")
+		}
+		if len(identity.APIServerURLs) == 0 {
+			return nil, fmt.Errorf("cluster %s has no apiServerURLs", identity.Name)
+		}
+		expected[identity.Name] = identity
+	}
+	return expected, nil
+}
+
+// verifyCSR checks the CSR is a client certificate request of the registration agent of the cluster,
+// signed by its key and without any subject alternative name
+func verifyCSR(csr *certificatesv1.CertificateSigningRequest, clusterName string) error {
+	var reasons []string
+	if csr.Spec.SignerName != certificatesv1.KubeAPIServerClientSignerName {
+		reasons = append(reasons, fmt.Sprintf("csr %s has the signer %s", csr.Name, csr.Spec.SignerName))
+	}
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return &identityError{cluster: clusterName, reasons: append(reasons, fmt.Sprintf("csr %s has no PEM certificate request", csr.Name))}
+	}
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return &identityError{cluster: clusterName, reasons: append(reasons, fmt.Sprintf("csr %s can not be parsed: %v", csr.Name, err))}
+	}
+	if err := request.CheckSignature(); err != nil {
+		reasons = append(reasons, fmt.Sprintf("csr %s has an invalid signature: %v", csr.Name, err))
+	}
+	if !strings.HasPrefix(request.Subject.CommonName, agentUserPrefix+clusterName+":") {
+		reasons = append(reasons, fmt.Sprintf("csr %s has the common name %s", csr.Name, request.Subject.CommonName))
+	}
+	if !sets.NewString(request.Subject.Organization...).Has(agentUserPrefix + clusterName) {
+		reasons = append(reasons, fmt.Sprintf("csr %s is not requested for the group %s%s", csr.Name, agentUserPrefix, clusterName))
+	}
+	if len(request.DNSNames)+len(request.IPAddresses)+len(request.URIs)+len(request.EmailAddresses) > 0 {
+		reasons = append(reasons, fmt.Sprintf("csr %s has subject alternative names", csr.Name))
+	}
+	if len(reasons) > 0 {
+		return &identityError{cluster: clusterName, reasons: reasons}
+	}
+	return nil
+}
+
+// verifyManagedCluster checks the cluster reports the expected API server URLs and cluster id.
+// The cluster id is reported once the cluster is accepted, it is only verified if it is reported.
+func verifyManagedCluster(mc *clusterv1.ManagedCluster, expected ExpectedIdentity) (verifiedID bool, err error) {
+	var reasons []string
+	expectedURLs := sets.NewString()
+	for _, url := range expected.APIServerURLs {
+		expectedURLs.Insert(strings.TrimSuffix(url, "/"))
+	}
+	if len(mc.Spec.ManagedClusterClientConfigs) == 0 {
+		reasons = append(reasons, "no API server URL is reported")
+	}
+	for _, clientConfig := range mc.Spec.ManagedClusterClientConfigs {
+		if !expectedURLs.Has(strings.TrimSuffix(clientConfig.URL, "/")) {
+			reasons = append(reasons, fmt.Sprintf("the API server URL %s is not expected", clientConfig.URL))
+		}
+	}
+	if len(expected.ClusterID) > 0 {
+		for _, claim := range mc.Status.ClusterClaims {
+			if claim.Name != clusterIDClaim {
+				continue
+			}
+			verifiedID = true
+			if claim.Value != expected.ClusterID {
+				reasons = append(reasons, fmt.Sprintf("the cluster id %s is not %s", claim.Value, expected.ClusterID))
+			}
+		}
+	}
+	if len(reasons) > 0 {
+		return verifiedID, &identityError{cluster: mc.Name, reasons: reasons}
+	}
+	return verifiedID, nil
+}
@@ -0,0 +1,160 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func newCSR(t *testing.T, template *x509.CertificateRequest) *certificatesv1.CertificateSigningRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "csr1"},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			SignerName: certificatesv1.KubeAPIServerClientSignerName,
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+		},
+	}
+}
+
+func TestVerifyCSR(t *testing.T) {
+	agentSubject := pkix.Name{
+		CommonName:   "system:open-cluster-management:cluster1:agent1",
+		Organization: []string{"system:open-cluster-management:cluster1", "system:open-cluster-management:managed-clusters"},
+	}
+	cases := []struct {
+		name        string
+		template    *x509.CertificateRequest
+		signer      string
+		expectedErr bool
+	}{
+		{
+			name:     "agent csr",
+			template: &x509.CertificateRequest{Subject: agentSubject},
+		},
+		{
+			name:        "other cluster",
+			template:    &x509.CertificateRequest{Subject: pkix.Name{CommonName: "system:open-cluster-management:cluster2:agent1"}},
+			expectedErr: true,
+		},
+		{
+			name:        "subject alternative names",
+			template:    &x509.CertificateRequest{Subject: agentSubject, DNSNames: []string{"rogue.example.com"}},
+			expectedErr: true,
+		},
+		{
+			name:        "serving signer",
+			template:    &x509.CertificateRequest{Subject: agentSubject},
+			signer:      certificatesv1.KubeletServingSignerName,
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			csr := newCSR(t, c.template)
+			if len(c.signer) > 0 {
+				csr.Spec.SignerName = c.signer
+			}
+			err := verifyCSR(csr, "cluster1")
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+		})
+	}
+
+	if err := verifyCSR(&certificatesv1.CertificateSigningRequest{}, "cluster1"); err == nil {
+		t.Errorf("expected an error for a csr without request")
+	}
+}
+
+func TestVerifyManagedCluster(t *testing.T) {
+	expected := ExpectedIdentity{Name: "cluster1", APIServerURLs: []string{"https://api.cluster1:6443/"}, ClusterID: "uid1"}
+	cluster := func(url string, claims ...clusterv1.ManagedClusterClaim) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+			Spec:       clusterv1.ManagedClusterSpec{ManagedClusterClientConfigs: []clusterv1.ClientConfig{{URL: url}}},
+			Status:     clusterv1.ManagedClusterStatus{ClusterClaims: claims},
+		}
+	}
+	cases := []struct {
+		name             string
+		cluster          *clusterv1.ManagedCluster
+		expectedVerified bool
+		expectedErr      bool
+	}{
+		{
+			name:    "id not reported",
+			cluster: cluster("https://api.cluster1:6443"),
+		},
+		{
+			name:             "id reported",
+			cluster:          cluster("https://api.cluster1:6443", clusterv1.ManagedClusterClaim{Name: clusterIDClaim, Value: "uid1"}),
+			expectedVerified: true,
+		},
+		{
+			name:        "unexpected url",
+			cluster:     cluster("https://rogue:6443"),
+			expectedErr: true,
+		},
+		{
+			name:             "unexpected id",
+			cluster:          cluster("https://api.cluster1:6443", clusterv1.ManagedClusterClaim{Name: clusterIDClaim, Value: "uid2"}),
+			expectedVerified: true,
+			expectedErr:      true,
+		},
+		{
+			name:        "no url",
+			cluster:     &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			verified, err := verifyManagedCluster(c.cluster, expected)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+			if verified != c.expectedVerified {
+				t.Errorf("expected verified %v, got %v", c.expectedVerified, verified)
+			}
+		})
+	}
+}
+
+func TestLoadExpectedIdentities(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expected.yaml")
+	if err := os.WriteFile(path, []byte("clusters:\n- name: cluster1\n  apiServerURLs: [https://api.cluster1:6443]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	identities, err := loadExpectedIdentities(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identities["cluster1"].APIServerURLs) != 1 {
+		t.Errorf("expected the URLs of cluster1, got %+v", identities)
+	}
+	if err := os.WriteFile(path, []byte("clusters:\n- name: cluster1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadExpectedIdentities(path); err == nil {
+		t.Errorf("expected an error for a cluster without URLs")
+	}
+}
@@ -2,8 +2,12 @@
 package accept
 
 import (
+	"time"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	workapiv1 "open-cluster-management.io/api/work/v1"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/notify"
 )
 
 type Options struct {
@@ -15,6 +19,34 @@ type Options struct {
 	Wait bool
 	//If true the csr will approve directly and check of requester will skip.
 	SkipApproveCheck bool
+	//The lease duration of the managed cluster above which a warning is printed
+	MaxLeaseDuration time.Duration
+	//The namespace of the bootstrap service account, discovered from the bootstrap cluster role binding if not set
+	BootstrapNamespace string
+	//The namespace the clusterset of the accepted clusters is bound to
+	GrantNamespace string
+	//The group granted the admin role of the grant namespace and the view role of the clusterset
+	GrantGroup string
+	//The executable run after a cluster is accepted and available, with the cluster name as argument
+	PostAcceptExec string
+	//The file or directory of the manifests applied to a cluster in a work after it is accepted and available
+	PostAcceptWork string
+
+	//If true the CSR and the endpoints of the clusters are verified against ExpectedEndpoints before they are accepted
+	VerifyAgentIdentity bool
+	//The file of the expected API server URLs and cluster ids of the clusters
+	ExpectedEndpoints string
+
+	//The manifests read from PostAcceptWork
+	postAcceptManifests []workapiv1.Manifest
+	//The identities read from ExpectedEndpoints by cluster name
+	expectedIdentities map[string]ExpectedIdentity
+	//The CSRs and clusters which would be changed, reported with --dry-run
+	report approvalReport
+	//Posts the accepted and rejected clusters to the notify url if set
+	notify notify.Options
+	//The API server of the hub, in the notifications
+	hubHost string
 
 	Values Values
 
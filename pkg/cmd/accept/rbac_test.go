@@ -0,0 +1,104 @@
+// Copyright Contributors to the Open Cluster Management project
+package accept
+
+import (
+	"context"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	workfake "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+	testinghelper "open-cluster-management.io/clusteradm/pkg/helpers/testing"
+)
+
+// TestAcceptRules runs accept with the hub clients restricted to the rbac rules of accept
+func TestAcceptRules(t *testing.T) {
+	cases := []struct {
+		name      string
+		namespace string
+		rules     []rbacv1.PolicyRule
+		forbidden bool
+	}{
+		{name: "bootstrap serviceaccount in the default namespace", rules: rbac.RulesOf([]string{"accept"})},
+		{name: "bootstrap serviceaccount in another namespace", namespace: "bootstrap", rules: rbac.RulesOf([]string{"accept"})},
+		{name: "bootstrap binding not readable", rules: withoutResource(rbac.RulesOf([]string{"accept"}), "clusterrolebindings"), forbidden: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			namespace := c.namespace
+			if len(namespace) == 0 {
+				namespace = config.OpenClusterManagementNamespace
+			}
+			kubeClient := kubefake.NewSimpleClientset(
+				&rbacv1.ClusterRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: config.BootstrapClusterRoleBindingSAName},
+					Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: config.BootstrapSAName, Namespace: namespace}},
+				},
+				&certificatesv1.CertificateSigningRequest{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster1-abc", Labels: map[string]string{clusterLabel: "cluster1"}},
+					Spec: certificatesv1.CertificateSigningRequestSpec{
+						Username:   "system:serviceaccount:" + namespace + ":" + config.BootstrapSAName,
+						Groups:     []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace},
+						SignerName: certificatesv1.KubeAPIServerClientSignerName,
+					},
+				},
+			)
+			clusterClient := clusterfake.NewSimpleClientset(&clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+			})
+			workClient := workfake.NewSimpleClientset()
+			testinghelper.RestrictToRules(&kubeClient.Fake, c.rules)
+			testinghelper.RestrictToRules(&clusterClient.Fake, c.rules)
+			testinghelper.RestrictToRules(&workClient.Fake, c.rules)
+
+			o := NewOptions(genericclioptionsclusteradm.NewClusteradmFlags(nil), genericclioptions.NewTestIOStreamsDiscard())
+			o.Values.Clusters = []string{"cluster1"}
+			err := o.runWithClient(kubeClient, clusterClient, workClient)
+			if c.forbidden {
+				if !errors.IsForbidden(err) {
+					t.Fatalf("expected a forbidden error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.BootstrapNamespace != namespace {
+				t.Errorf("expected the bootstrap namespace %s, got %s", namespace, o.BootstrapNamespace)
+			}
+			csr, err := kubeClient.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), "cluster1-abc", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if approved, _ := GetCertApprovalCondition(&csr.Status); !approved {
+				t.Errorf("expected the csr to be approved")
+			}
+			mc, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), "cluster1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !mc.Spec.HubAcceptsClient {
+				t.Errorf("expected the cluster to be accepted")
+			}
+		})
+	}
+}
+
+func withoutResource(rules []rbacv1.PolicyRule, resource string) []rbacv1.PolicyRule {
+	var kept []rbacv1.PolicyRule
+	for _, rule := range rules {
+		if len(rule.Resources) != 1 || rule.Resources[0] != resource {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
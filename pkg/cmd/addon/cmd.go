@@ -4,8 +4,10 @@ package addon
 import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/addon/createtemplate"
 	"open-cluster-management.io/clusteradm/pkg/cmd/addon/disable"
 	"open-cluster-management.io/clusteradm/pkg/cmd/addon/enable"
+	"open-cluster-management.io/clusteradm/pkg/cmd/addon/upgrade"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 )
 
@@ -14,11 +16,13 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd := &cobra.Command{
 		Use:   "addon",
 		Short: "addon options",
-		Long:  "there are 2 addon options: enable and disable",
+		Long:  "there are 4 addon options: enable, disable, upgrade and create-template",
 	}
 
 	cmd.AddCommand(enable.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(disable.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(upgrade.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(createtemplate.NewCmd(clusteradmFlags, streams))
 
 	return cmd
 }
@@ -0,0 +1,65 @@
+// Copyright Contributors to the Open Cluster Management project
+package createtemplate
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Scaffold an addon project in the ./my-addon directory
+%[1]s addon create-template --name my-addon
+
+# Scaffold an addon whose agent registers to the hub and is installed on every managed cluster
+%[1]s addon create-template --name my-addon --enable-registration --install-strategy all --output-dir /tmp/my-addon
+
+# Print the generated files without writing them
+%[1]s addon create-template --name my-addon --dry-run
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "create-template",
+		Short:        "scaffold an addon project",
+		Long:         "scaffold an addon project based on the addon-framework, including main.go, RBAC, the ClusterManagementAddOn manifest and a Makefile",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(clusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.name, "name", "", "The name of the addon")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "The directory where the project is generated, defaults to ./<name>")
+	cmd.Flags().StringVar(&o.module, "module", "", "The go module path of the project, defaults to github.com/example/<name>")
+	cmd.Flags().StringVar(&o.image, "image", "", "The image of the addon, defaults to quay.io/example/<name>:latest")
+	cmd.Flags().StringVar(&o.installNamespace, "install-namespace", "open-cluster-management-agent-addon", "The namespace where the addon agent is deployed on the managed clusters")
+	cmd.Flags().StringVar(&o.managerNamespace, "manager-namespace", "open-cluster-management", "The namespace where the addon manager is deployed on the hub")
+	cmd.Flags().StringVar(&o.installStrategy, "install-strategy", installStrategyManual,
+		fmt.Sprintf("How the agent is deployed to the managed clusters, %q requires the addon to be enabled per cluster, %q deploys it to every managed cluster",
+			installStrategyManual, installStrategyAll))
+	cmd.Flags().BoolVar(&o.enableRegistration, "enable-registration", false, "Register the addon agent to the hub so that it can access the hub with its own identity")
+	cmd.Flags().BoolVar(&o.overwrite, "overwrite", false, "Overwrite the existing files in the output directory")
+
+	return cmd
+}
@@ -0,0 +1,131 @@
+// Copyright Contributors to the Open Cluster Management project
+package createtemplate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/applier/pkg/apply"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/cmd/addon/createtemplate/scenario"
+)
+
+const (
+	installStrategyManual = "manual"
+	installStrategyAll    = "all"
+
+	templateDir    = "template"
+	templateSuffix = ".tmpl"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("addon create-template options:", "dry-run", o.ClusteradmFlags.DryRun, "name", o.name, "output-dir", o.outputDir,
+		"install-strategy", o.installStrategy, "enable-registration", o.enableRegistration)
+
+	if len(o.outputDir) == 0 {
+		o.outputDir = o.name
+	}
+	if len(o.module) == 0 {
+		o.module = fmt.Sprintf("github.com/example/%s", o.name)
+	}
+	if len(o.image) == 0 {
+		o.image = fmt.Sprintf("quay.io/example/%s:latest", o.name)
+	}
+
+	o.values = Values{
+		Name:               o.name,
+		Module:             o.module,
+		Image:              o.image,
+		InstallNamespace:   o.installNamespace,
+		ManagerNamespace:   o.managerNamespace,
+		InstallStrategy:    o.installStrategy,
+		EnableRegistration: o.enableRegistration,
+	}
+	return nil
+}
+
+func (o *Options) validate() error {
+	if len(o.name) == 0 {
+		return fmt.Errorf("--name is missing")
+	}
+	if errs := validation.IsDNS1123Label(o.name); len(errs) > 0 {
+		return fmt.Errorf("invalid addon name %q: %s", o.name, strings.Join(errs, ", "))
+	}
+	if o.installStrategy != installStrategyManual && o.installStrategy != installStrategyAll {
+		return fmt.Errorf("--install-strategy should be %s or %s", installStrategyManual, installStrategyAll)
+	}
+	if o.ClusteradmFlags.DryRun || o.overwrite {
+		return nil
+	}
+	entries, err := os.ReadDir(o.outputDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("the directory %s is not empty, use --overwrite to overwrite the existing files", o.outputDir)
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	files, err := o.render()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if o.ClusteradmFlags.DryRun {
+			fmt.Fprintf(o.Streams.Out, "--- %s\n%s\n", f.path, f.content)
+			continue
+		}
+		path := filepath.Join(o.outputDir, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, f.content, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "created %s\n", path)
+	}
+	if o.ClusteradmFlags.DryRun {
+		return nil
+	}
+
+	fmt.Fprintf(o.Streams.Out, "\nThe addon %s is scaffolded in %s, run the following commands to build and deploy it on the hub:\n\n", o.name, o.outputDir)
+	fmt.Fprintf(o.Streams.Out, "    cd %s && make build image push deploy\n\n", o.outputDir)
+	if o.installStrategy == installStrategyManual {
+		fmt.Fprintf(o.Streams.Out, "Then enable the addon on the managed clusters:\n\n    clusteradm addon enable --names %s --clusters <cluster_name>\n\n", o.name)
+	}
+	return nil
+}
+
+type renderedFile struct {
+	path    string
+	content []byte
+}
+
+// render renders the embedded templates, the generated files keep the layout of
+// the template directory without the template suffix.
+func (o *Options) render() ([]renderedFile, error) {
+	reader := scenario.GetScenarioResourcesReader()
+	names, err := reader.AssetNames(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	applier := apply.NewApplierBuilder().Build()
+	files := make([]renderedFile, 0, len(names))
+	for _, name := range names {
+		content, err := applier.MustTemplateAsset(reader, o.values, "", name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %v", name, err)
+		}
+		path := strings.TrimSuffix(strings.TrimPrefix(name, templateDir+"/"), templateSuffix)
+		files = append(files, renderedFile{path: path, content: content})
+	}
+	return files, nil
+}
@@ -0,0 +1,107 @@
+// Copyright Contributors to the Open Cluster Management project
+package createtemplate
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		name               string
+		installStrategy    string
+		enableRegistration bool
+	}{
+		{name: "manual without registration", installStrategy: installStrategyManual},
+		{name: "manual with registration", installStrategy: installStrategyManual, enableRegistration: true},
+		{name: "all without registration", installStrategy: installStrategyAll},
+		{name: "all with registration", installStrategy: installStrategyAll, enableRegistration: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &Options{
+				values: Values{
+					Name:               "test-addon",
+					Module:             "github.com/example/test-addon",
+					Image:              "quay.io/example/test-addon:latest",
+					InstallNamespace:   "open-cluster-management-agent-addon",
+					ManagerNamespace:   "open-cluster-management",
+					InstallStrategy:    c.installStrategy,
+					EnableRegistration: c.enableRegistration,
+				},
+			}
+			files, err := o.render()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			found := map[string]bool{}
+			for _, f := range files {
+				found[f.path] = true
+				// the manifests in manifests/templates are rendered by the addon-framework, so
+				// only the manifests applied to the hub are validated here
+				switch {
+				case f.path == "main.go":
+					if _, err := parser.ParseFile(token.NewFileSet(), f.path, f.content, parser.AllErrors); err != nil {
+						t.Errorf("generated main.go is invalid: %v", err)
+					}
+					// the manager and the agent deployments run the subcommands of the same binary
+					for _, command := range []string{`Use:   "manager"`, `Use:   "agent"`, `"cluster-name"`} {
+						if !strings.Contains(string(f.content), command) {
+							t.Errorf("expected main.go to define %s", command)
+						}
+					}
+				case f.path == "manifests/templates/deployment.yaml":
+					if !strings.Contains(string(f.content), `- "agent"`) {
+						t.Errorf("expected the agent to run the agent subcommand")
+					}
+				case strings.HasPrefix(f.path, "deploy/"):
+					var obj interface{}
+					if err := yaml.Unmarshal(f.content, &obj); err != nil {
+						t.Errorf("generated %s is invalid: %v", f.path, err)
+					}
+					if f.path == "deploy/deployment.yaml" && !strings.Contains(string(f.content), `- "manager"`) {
+						t.Errorf("expected the manager to run the manager subcommand")
+					}
+				}
+			}
+			for _, expected := range []string{"main.go", "Makefile", "deploy/clustermanagementaddon.yaml", "deploy/rbac.yaml"} {
+				if !found[expected] {
+					t.Errorf("expected %s to be generated", expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRunFileMode(t *testing.T) {
+	dir := t.TempDir()
+	o := &Options{
+		ClusteradmFlags: genericclioptionsclusteradm.NewClusteradmFlags(nil),
+		Streams:         genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}},
+		name:            "test-addon",
+		outputDir:       dir,
+		installStrategy: installStrategyManual,
+		values:          Values{Name: "test-addon", Module: "github.com/example/test-addon", InstallStrategy: installStrategyManual},
+	}
+	if err := o.run(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("expected the generated source to be readable, got %v", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+package createtemplate
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	// ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	// The name of the addon
+	name string
+	// The directory where the project is generated
+	outputDir string
+	// The go module path of the project
+	module string
+	// The image of the addon manager and agent
+	image string
+	// The namespace of the agent on the managed clusters
+	installNamespace string
+	// The namespace of the addon manager on the hub
+	managerNamespace string
+	// The install strategy of the agent, manual or all
+	installStrategy string
+	// Whether the agent registers to the hub
+	enableRegistration bool
+	// Overwrite the existing files
+	overwrite bool
+
+	values Values
+
+	Streams genericclioptions.IOStreams
+}
+
+// Values: The values used to render the project templates
+type Values struct {
+	Name               string
+	Module             string
+	Image              string
+	InstallNamespace   string
+	ManagerNamespace   string
+	InstallStrategy    string
+	EnableRegistration bool
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
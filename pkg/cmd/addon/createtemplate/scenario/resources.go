@@ -0,0 +1,15 @@
+// Copyright Contributors to the Open Cluster Management project
+package scenario
+
+import (
+	"embed"
+
+	"github.com/stolostron/applier/pkg/asset"
+)
+
+//go:embed template
+var files embed.FS
+
+func GetScenarioResourcesReader() *asset.ScenarioResourcesReader {
+	return asset.NewScenarioResourcesReader(&files)
+}
@@ -0,0 +1,36 @@
+// Copyright Contributors to the Open Cluster Management project
+package enable
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+func TestAvailability(t *testing.T) {
+	addon := &addonv1alpha1.ManagedClusterAddOn{}
+	if status, message := availability(addon); status != "Unknown" || message != "waiting for the add-on agent" {
+		t.Errorf("unexpected availability %s %q", status, message)
+	}
+	addon.Status.Conditions = []metav1.Condition{{
+		Type:    addonv1alpha1.ManagedClusterAddOnConditionAvailable,
+		Status:  metav1.ConditionFalse,
+		Message: "Addon lease is not updated",
+	}}
+	if status, message := availability(addon); status != "False" || message != "Addon lease is not updated" {
+		t.Errorf("unexpected availability %s %q", status, message)
+	}
+}
+
+func TestDeployError(t *testing.T) {
+	err := &deployError{failed: map[string]error{
+		key("cluster2", "application-manager"): errors.New("timeout"),
+		key("cluster1", "application-manager"): errors.New("forbidden"),
+	}}
+	expected := "failed to deploy 2 add-ons: cluster1/application-manager: forbidden; cluster2/application-manager: timeout"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
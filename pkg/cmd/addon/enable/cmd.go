@@ -6,6 +6,7 @@ import (
 
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/stamp"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -32,6 +33,11 @@ var example = `
 %[1]s addon enable --names config-policy-controller --namespace namespace --clusters cluster1,cluster2
 # Enable config-policy-controller addon for specified clusters
 %[1]s addon enable --names config-policy-controller --clusters cluster1,cluster2
+
+## Clusterset
+
+# Enable application-manager addon on the members of the clusterset prod and wait until it is available
+%[1]s addon enable application-manager --clusterset prod --wait
 `
 
 // NewCmd...
@@ -39,7 +45,7 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	o := NewOptions(clusteradmFlags, streams)
 
 	cmd := &cobra.Command{
-		Use:          "enable",
+		Use:          "enable [NAME]",
 		Short:        "enable specified addon",
 		Long:         "enable specific add-on(s) agent deployment to the given managed clusters",
 		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
@@ -69,6 +75,10 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd.Flags().StringSliceVar(&o.Clusters, "clusters", []string{}, "Names of the managed cluster to deploy the add-on to (comma separated)")
 	cmd.Flags().StringVar(&o.OutputFile, "output-file", "", "The generated resources will be copied in the specified file")
 	cmd.Flags().StringSliceVar(&o.Annotate, "annotate", []string{}, "Annotations to add to the ManagedClusterAddon (eg. key1=value1,key2=value2)")
+	cmd.Flags().StringVar(&o.ClusterSet, "clusterset", "", "Name of the clusterset whose member clusters the add-on is deployed to")
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait until the add-on is available on each cluster, showing the progress of each cluster")
+	cmd.Flags().IntVar(&o.Retries, "retries", 3, "The number of times the deployment of the add-on to a cluster is retried if it fails")
+	stamp.AddFlags(cmd.Flags())
 
 	return cmd
 }
@@ -3,22 +3,42 @@ package enable
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stolostron/applier/pkg/apply"
+	"github.com/stolostron/applier/pkg/asset"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	"open-cluster-management.io/clusteradm/pkg/cmd/addon/enable/scenario"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
 )
 
+const (
+	// maxConcurrency is the number of clusters the add-ons are deployed to at once
+	maxConcurrency = 10
+	pollInterval   = 2 * time.Second
+)
+
+var retryBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1}
+
 type ClusterAddonInfo struct {
 	ClusterName string
 	NameSpace   string
@@ -46,8 +66,14 @@ func NewClusterAddonInfo(cn string, o *Options, an string) (ClusterAddonInfo, er
 }
 
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
-	klog.V(1).InfoS("enable options:", "dry-run", o.ClusteradmFlags.DryRun, "names", o.Names, "clusters", o.Clusters, "output-file", o.OutputFile)
+	klog.V(1).InfoS("enable options:", "dry-run", o.ClusteradmFlags.DryRun, "names", o.Names, "clusters", o.Clusters,
+		"clusterset", o.ClusterSet, "wait", o.Wait, "retries", o.Retries, "output-file", o.OutputFile)
 
+	o.Names = append(o.Names, args...)
+	// the clusterset set by default for the hub does not extend the clusters set explicitly
+	if len(o.Clusters) > 0 && !cmd.Flags().Changed("clusterset") {
+		o.ClusterSet = ""
+	}
 	return nil
 }
 
@@ -61,8 +87,12 @@ func (o *Options) Validate() (err error) {
 		return fmt.Errorf("names is missing")
 	}
 
-	if len(o.Clusters) == 0 {
-		return fmt.Errorf("clusters is missing")
+	if len(o.Clusters) == 0 && len(o.ClusterSet) == 0 {
+		return fmt.Errorf("clusters or clusterset is missing")
+	}
+
+	if o.Retries < 0 {
+		return fmt.Errorf("retries can not be negative")
 	}
 
 	return nil
@@ -72,8 +102,6 @@ func (o *Options) Run() error {
 	addons := sets.NewString(o.Names...)
 	clusters := sets.NewString(o.Clusters...)
 
-	klog.V(3).InfoS("values:", "addon", addons, "clusters", clusters)
-
 	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
 	if err != nil {
 		return err
@@ -82,13 +110,45 @@ func (o *Options) Run() error {
 	if err != nil {
 		return err
 	}
+	addonClient, err := addonclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(o.ClusterSet) > 0 {
+		members, err := clusterSetMembers(clusterClient, o.ClusterSet)
+		if err != nil {
+			return err
+		}
+		clusters.Insert(members...)
+	}
+
+	klog.V(3).InfoS("values:", "addon", addons, "clusters", clusters)
 
 	kubeClient, apiExtensionsClient, dynamicClient, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
 	if err != nil {
 		return err
 	}
 
-	return o.runWithClient(clusterClient, kubeClient, apiExtensionsClient, dynamicClient, o.ClusteradmFlags.DryRun, addons.List(), clusters.List())
+	dryRun := o.ClusteradmFlags.DryRun
+	if o.Wait && !dryRun {
+		o.progress = printer.NewProgress(o.Streams.Out, "CLUSTER", "ADDON", "DEPLOYED", "AVAILABLE", "MESSAGE")
+	}
+	err = o.runWithClient(clusterClient, kubeClient, apiExtensionsClient, dynamicClient, dryRun, addons.List(), clusters.List())
+	if o.progress == nil {
+		return err
+	}
+
+	// the addons deployed are waited for even if others failed to deploy
+	failed := &deployError{}
+	if err != nil && !errors.As(err, &failed) {
+		return err
+	}
+	timeout := time.Duration(o.ClusteradmFlags.Timeout) * time.Second
+	if waitErr := o.waitForAddons(addonClient, addons.List(), clusters.List(), failed.failed, timeout); waitErr != nil {
+		return waitErr
+	}
+	return err
 }
 
 func (o *Options) runWithClient(clusterClient clusterclientset.Interface,
@@ -99,6 +159,7 @@ func (o *Options) runWithClient(clusterClient clusterclientset.Interface,
 	addons []string,
 	clusters []string) error {
 
+	clusters = sets.NewString(clusters...).List()
 	for _, clusterName := range clusters {
 		_, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(),
 			clusterName,
@@ -108,27 +169,188 @@ func (o *Options) runWithClient(clusterClient clusterclientset.Interface,
 		}
 	}
 
-	output := make([]string, 0)
 	reader := scenario.GetScenarioResourcesReader()
 
-	applierBuilder := apply.NewApplierBuilder()
-	applier := applierBuilder.WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
-
-	for _, addon := range addons {
-		for _, clusterName := range clusters {
+	// the add-ons are deployed to the clusters concurrently, the output is kept in order
+	outputs := make([][]string, len(addons)*len(clusters))
+	failed := map[string]error{}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, maxConcurrency)
+	for i, addon := range addons {
+		for j, clusterName := range clusters {
 			cai, err := NewClusterAddonInfo(clusterName, o, addon)
 			if err != nil {
 				return err
 			}
-			out, err := applier.ApplyCustomResources(reader, cai, dryRun, "", "addons/addon.yaml")
-			if err != nil {
-				return err
+			index := i*len(clusters) + j
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				workers <- struct{}{}
+				defer func() { <-workers }()
+
+				// each deployment has its applier, the cache of an applier is not safe for concurrent use
+				applier := apply.NewApplierBuilder().WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
+				out, err := o.deploy(applier, reader, cai, dryRun)
+				lock.Lock()
+				defer lock.Unlock()
+				if err != nil {
+					failed[key(cai.ClusterName, cai.AddonName)] = err
+					return
+				}
+				outputs[index] = out
+			}()
+		}
+	}
+	wg.Wait()
+
+	output := make([]string, 0)
+	for i, addon := range addons {
+		for j, clusterName := range clusters {
+			if _, ok := failed[key(clusterName, addon)]; ok {
+				continue
 			}
-			output = append(output, out...)
+			output = append(output, outputs[i*len(clusters)+j]...)
+			if o.progress == nil {
+				fmt.Fprintf(o.Streams.Out, "Deploying %s add-on to namespaces %s of managed cluster: %s.\n", addon, o.Namespace, clusterName)
+			}
+		}
+	}
+
+	if err := apply.WriteOutput(o.OutputFile, output); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return &deployError{failed: failed}
+	}
+	return nil
+}
+
+// deploy applies the addon to the cluster, retried on failure
+func (o *Options) deploy(applier apply.Applier, reader asset.ScenarioReader, cai ClusterAddonInfo, dryRun bool) ([]string, error) {
+	backoff := retryBackoff
+	backoff.Steps = o.Retries + 1
+	attempt := 0
+	var out []string
+	err := retry.OnError(backoff, func(error) bool { return true }, func() error {
+		if attempt == 0 {
+			o.report(cai, "Deploying", "", "")
+		} else {
+			o.report(cai, fmt.Sprintf("Retrying %d/%d", attempt, o.Retries), "", "")
+		}
+		attempt++
+		var err error
+		out, err = applier.ApplyCustomResources(reader, cai, dryRun, "", "addons/addon.yaml")
+		return err
+	})
+	if err != nil {
+		o.report(cai, "Failed", "", err.Error())
+		return nil, err
+	}
+	o.report(cai, "Deployed", string(metav1.ConditionUnknown), "")
+	return out, nil
+}
 
-			fmt.Fprintf(o.Streams.Out, "Deploying %s add-on to namespaces %s of managed cluster: %s.\n", addon, o.Namespace, clusterName)
+// report updates the row of the addon of the cluster in the progress table, if the progress is shown
+func (o *Options) report(cai ClusterAddonInfo, deployed, available, message string) {
+	if o.progress == nil {
+		return
+	}
+	o.progress.Update(key(cai.ClusterName, cai.AddonName), cai.ClusterName, cai.AddonName, deployed, available, message)
+}
+
+// waitForAddons updates the progress table with the availability of the addons deployed until they are all available
+func (o *Options) waitForAddons(addonClient addonclientset.Interface, addons, clusters []string, failed map[string]error,
+	timeout time.Duration) error {
+	pending := sets.NewString()
+	for _, addon := range addons {
+		for _, cluster := range clusters {
+			if _, ok := failed[key(cluster, addon)]; !ok {
+				pending.Insert(key(cluster, addon))
+			}
+		}
+	}
+	err := wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		for _, addon := range addons {
+			list, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+				FieldSelector: fmt.Sprintf("metadata.name=%s", addon),
+			})
+			if err != nil {
+				return false, err
+			}
+			for i := range list.Items {
+				mca := &list.Items[i]
+				if !pending.Has(key(mca.Namespace, mca.Name)) {
+					continue
+				}
+				status, message := availability(mca)
+				o.report(ClusterAddonInfo{ClusterName: mca.Namespace, AddonName: mca.Name}, "Deployed", status, message)
+				if status == string(metav1.ConditionTrue) {
+					pending.Delete(key(mca.Namespace, mca.Name))
+				}
+			}
 		}
+		return pending.Len() == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("the add-ons %s are not available after %s", strings.Join(pending.List(), ", "), timeout)
 	}
+	return err
+}
+
+// clusterSetMembers returns the names of the clusters selected by the clusterset
+func clusterSetMembers(clusterClient clusterclientset.Interface, name string) ([]string, error) {
+	clusterSet, err := clusterClient.ClusterV1beta1().ManagedClusterSets().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	selector, err := clusterv1beta1.BuildClusterSelector(clusterSet)
+	if err != nil {
+		return nil, err
+	}
+	list, err := clusterClient.ClusterV1().ManagedClusters().List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("the clusterset %s has no member cluster", name)
+	}
+	members := []string{}
+	for _, cluster := range list.Items {
+		members = append(members, cluster.Name)
+	}
+	return members, nil
+}
+
+// availability returns the status and the message of the available condition of the addon
+func availability(addon *addonv1alpha1.ManagedClusterAddOn) (string, string) {
+	condition := meta.FindStatusCondition(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable)
+	if condition == nil {
+		return string(metav1.ConditionUnknown), "waiting for the add-on agent"
+	}
+	return string(condition.Status), condition.Message
+}
+
+// deployError is returned if the addons failed to deploy to some clusters
+type deployError struct {
+	failed map[string]error
+}
+
+func (e *deployError) Error() string {
+	keys := []string{}
+	for k := range e.failed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	messages := []string{}
+	for _, k := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %v", k, e.failed[k]))
+	}
+	return fmt.Sprintf("failed to deploy %d add-ons: %s", len(keys), strings.Join(messages, "; "))
+}
 
-	return apply.WriteOutput(o.OutputFile, output)
+// key identifies the addon of a cluster
+func key(cluster, addon string) string {
+	return cluster + "/" + addon
 }
@@ -4,6 +4,7 @@ package enable
 import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
 )
 
 type Options struct {
@@ -19,8 +20,16 @@ type Options struct {
 	OutputFile string
 	//Annotations to add to the addon
 	Annotate []string
+	//The clusterset whose members the addons are enabled on
+	ClusterSet string
+	//Wait until the addons are available
+	Wait bool
+	//The number of times the enablement of an addon on a cluster is retried
+	Retries int
 	//
 	Streams genericclioptions.IOStreams
+	//The progress table of the deployments, shown with --wait
+	progress *printer.Progress
 }
 
 func NewOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
@@ -0,0 +1,66 @@
+// Copyright Contributors to the Open Cluster Management project
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
+	"open-cluster-management.io/clusteradm/pkg/helpers/stamp"
+)
+
+var example = `
+# Upgrade the application-manager addon to 2.7.0, 5 clusters at a time
+%[1]s addon upgrade application-manager --version 2.7.0 --rollout progressive --max-concurrency 5
+# Upgrade the addon on the members of the clusterset prod, one clusterset group at a time
+%[1]s addon upgrade application-manager --version 2.7.0 --clusterset prod --rollout ProgressivePerGroup --group-label region
+# Resume the upgrade paused on a failure
+%[1]s addon upgrade application-manager --version 2.7.0 --rollout progressive --max-concurrency 5 --resume
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade NAME",
+		Short: "upgrade an addon progressively",
+		Long: "upgrade an addon to a version on the clusters batch after batch following a rollout strategy. The version is set in an " +
+			"AddOnDeploymentConfig referenced by the ManagedClusterAddOns of each batch, the next batch is upgraded once the addons " +
+			"have applied it and are available. The rollout is paused on the ClusterManagementAddOn if an addon fails, and the " +
+			"ClusterManagementAddOn defaults to the version once all its clusters are upgraded.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.version, "version", "", "The version the addon is upgraded to")
+	cmd.Flags().StringVar(&o.strategy.Type, "rollout", rollout.TypeProgressive, fmt.Sprintf("The rollout strategy, one of %s", strings.Join(rollout.Types, ", ")))
+	cmd.Flags().StringVar(&o.maxConcurrency, "max-concurrency", "", "The number or percentage of the clusters upgraded at a time")
+	cmd.Flags().StringVar(&o.strategy.GroupLabel, "group-label", "", "The label of the clusters grouping them with the ProgressivePerGroup strategy, the clusterset label by default")
+	cmd.Flags().StringSliceVar(&o.clusters, "clusters", []string{}, "Names of the managed clusters (comma separated) to upgrade, all the clusters of the addon by default")
+	cmd.Flags().StringVar(&o.clusterSet, "clusterset", "", "Name of the clusterset whose member clusters are upgraded")
+	cmd.Flags().StringVar(&o.configNamespace, "config-namespace", "open-cluster-management", "The namespace of the AddOnDeploymentConfig of the version")
+	cmd.Flags().BoolVar(&o.resume, "resume", false, "Resume the upgrade paused on a failure")
+	stamp.AddFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,403 @@
+// Copyright Contributors to the Open Cluster Management project
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
+)
+
+const (
+	// versionVariable is the customized variable of the AddOnDeploymentConfig holding the version
+	versionVariable = "Version"
+	pollInterval    = 2 * time.Second
+)
+
+const (
+	statePending   = "Pending"
+	stateUpgrading = "Upgrading"
+	stateUpgraded  = "Upgraded"
+	stateFailed    = "Failed"
+)
+
+// deploymentConfig is the group and resource of the AddOnDeploymentConfigs
+var deploymentConfig = addonv1alpha1.ConfigGroupResource{Group: addonv1alpha1.GroupName, Resource: "addondeploymentconfigs"}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("addon upgrade options:", "dry-run", o.ClusteradmFlags.DryRun, "args", args, "version", o.version,
+		"rollout", o.strategy.Type, "max-concurrency", o.maxConcurrency, "group-label", o.strategy.GroupLabel,
+		"clusters", o.clusters, "clusterset", o.clusterSet, "config-namespace", o.configNamespace, "resume", o.resume)
+
+	if len(args) != 1 {
+		return fmt.Errorf("the name of the addon must be specified")
+	}
+	o.addon = args[0]
+	// the strategy types are matched regardless of the case, as --rollout progressive
+	for _, t := range rollout.Types {
+		if strings.EqualFold(t, o.strategy.Type) {
+			o.strategy.Type = t
+		}
+	}
+	if len(o.maxConcurrency) > 0 {
+		o.strategy.MaxConcurrency = intstr.Parse(o.maxConcurrency)
+	}
+	// the clusterset set by default for the hub does not extend the clusters set explicitly
+	if len(o.clusters) > 0 && !cmd.Flags().Changed("clusterset") {
+		o.clusterSet = ""
+	}
+	return nil
+}
+
+func (o *Options) validate() error {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if len(o.version) == 0 {
+		return fmt.Errorf("the version must be specified in --version")
+	}
+	if len(configName(o.addon, o.version)) > 253 {
+		return fmt.Errorf("the version %s is too long", o.version)
+	}
+	if len(o.clusters) > 0 && len(o.clusterSet) > 0 {
+		return fmt.Errorf("--clusters and --clusterset can only specify one")
+	}
+	return o.strategy.Validate()
+}
+
+func (o *Options) run() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	addonClient, err := addonclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+
+	cma, err := addonClient.AddonV1alpha1().ClusterManagementAddOns().Get(ctx, o.addon, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("the addon %s is not installed on the hub", o.addon)
+	}
+	if err != nil {
+		return err
+	}
+	if rollout.IsPaused(cma) && !o.resume {
+		return fmt.Errorf("the upgrade of addon %s is paused, run again with --resume to resume it", o.addon)
+	}
+
+	addons, clusters, err := o.targets(clusterClient, addonClient)
+	if err != nil {
+		return err
+	}
+	name := configName(o.addon, o.version)
+	var pending []clusterv1.ManagedCluster
+	for _, cluster := range clusters {
+		if state, _ := upgradeState(addons[cluster.Name], name, -1); state != stateUpgraded {
+			pending = append(pending, cluster)
+		}
+	}
+	batches := o.strategy.Batches(pending)
+	if o.ClusteradmFlags.DryRun {
+		for i, batch := range batches {
+			fmt.Fprintf(o.Streams.Out, "batch %d: addon %s would be upgraded to %s on %s\n", i+1, o.addon, o.version, strings.Join(batch, ","))
+		}
+		return nil
+	}
+
+	config, err := o.applyConfig(addonClient, name)
+	if err != nil {
+		return err
+	}
+	if err := o.updateClusterManagementAddOn(addonClient, nil, false); err != nil {
+		return err
+	}
+
+	o.progress = printer.NewProgress(o.Streams.Out, "CLUSTER", "BATCH", "VERSION", "AVAILABLE", "STATUS")
+	for i, batch := range batches {
+		for _, cluster := range batch {
+			o.report(addons[cluster], fmt.Sprintf("%d", i+1), name, config.Generation, statePending)
+		}
+	}
+	timeout := time.Duration(o.ClusteradmFlags.Timeout) * time.Second
+	for i, batch := range batches {
+		failed, err := o.upgradeBatch(addonClient, addons, batch, fmt.Sprintf("%d", i+1), name, config.Generation, timeout)
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			if err := o.updateClusterManagementAddOn(addonClient, nil, true); err != nil {
+				return err
+			}
+			return fmt.Errorf("the addon %s failed to upgrade on %s, the upgrade is paused. Run again with --resume once fixed",
+				o.addon, strings.Join(failed, ","))
+		}
+	}
+
+	// the clusters joining later get the version once all the clusters of the addon are upgraded
+	if len(o.clusters) == 0 && len(o.clusterSet) == 0 {
+		if err := o.updateClusterManagementAddOn(addonClient, &addonv1alpha1.ConfigReferent{Namespace: o.configNamespace, Name: name}, false); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(o.Streams.Out, "addon %s is upgraded to %s on %d clusters\n", o.addon, o.version, len(clusters))
+	return nil
+}
+
+// targets returns the addons of the clusters to upgrade, by cluster name, and the clusters
+func (o *Options) targets(clusterClient clusterclientset.Interface, addonClient addonclientset.Interface) (
+	map[string]*addonv1alpha1.ManagedClusterAddOn, []clusterv1.ManagedCluster, error) {
+	ctx := context.TODO()
+	list, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("metadata.name=%s", o.addon),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	addons := map[string]*addonv1alpha1.ManagedClusterAddOn{}
+	for i := range list.Items {
+		addons[list.Items[i].Namespace] = &list.Items[i]
+	}
+
+	selector := labels.Everything()
+	if len(o.clusterSet) > 0 {
+		clusterSet, err := clusterClient.ClusterV1beta1().ManagedClusterSets().Get(ctx, o.clusterSet, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if selector, err = clusterv1beta1.BuildClusterSelector(clusterSet); err != nil {
+			return nil, nil, err
+		}
+	}
+	clusterList, err := clusterClient.ClusterV1().ManagedClusters().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, nil, err
+	}
+	wanted := sets.NewString(o.clusters...)
+	found := sets.NewString()
+	var clusters []clusterv1.ManagedCluster
+	for _, cluster := range clusterList.Items {
+		if wanted.Len() > 0 && !wanted.Has(cluster.Name) {
+			continue
+		}
+		found.Insert(cluster.Name)
+		if _, ok := addons[cluster.Name]; ok {
+			clusters = append(clusters, cluster)
+		}
+	}
+	if missing := wanted.Difference(found); missing.Len() > 0 {
+		return nil, nil, fmt.Errorf("the managed clusters %s are not found", strings.Join(missing.List(), ", "))
+	}
+	if len(clusters) == 0 {
+		return nil, nil, fmt.Errorf("the addon %s is not enabled on any of the clusters", o.addon)
+	}
+	return addons, clusters, nil
+}
+
+// applyConfig creates or updates the AddOnDeploymentConfig of the version
+func (o *Options) applyConfig(addonClient addonclientset.Interface, name string) (*addonv1alpha1.AddOnDeploymentConfig, error) {
+	ctx := context.TODO()
+	configs := addonClient.AddonV1alpha1().AddOnDeploymentConfigs(o.configNamespace)
+	variables := []addonv1alpha1.CustomizedVariable{{Name: versionVariable, Value: o.version}}
+	config, err := configs.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return configs.Create(ctx, &addonv1alpha1.AddOnDeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.configNamespace},
+			Spec:       addonv1alpha1.AddOnDeploymentConfigSpec{CustomizedVariables: variables},
+		}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	config.Spec.CustomizedVariables = setVariable(config.Spec.CustomizedVariables, variables[0])
+	return configs.Update(ctx, config, metav1.UpdateOptions{})
+}
+
+// updateClusterManagementAddOn records the strategy and the pause of the upgrade in the annotations of the
+// ClusterManagementAddOn, as done for the works, and declares the AddOnDeploymentConfigs as supported. The
+// default config is set if not nil.
+func (o *Options) updateClusterManagementAddOn(addonClient addonclientset.Interface, defaultConfig *addonv1alpha1.ConfigReferent, paused bool) error {
+	ctx := context.TODO()
+	cma, err := addonClient.AddonV1alpha1().ClusterManagementAddOns().Get(ctx, o.addon, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if cma.Annotations == nil {
+		cma.Annotations = map[string]string{}
+	}
+	cma.Annotations[rollout.StrategyAnnotation] = o.strategy.Type
+	if len(o.maxConcurrency) > 0 {
+		cma.Annotations[rollout.MaxConcurrencyAnnotation] = o.maxConcurrency
+	}
+	if len(o.strategy.GroupLabel) > 0 {
+		cma.Annotations[rollout.GroupLabelAnnotation] = o.strategy.GroupLabel
+	}
+	delete(cma.Annotations, rollout.PausedAnnotation)
+	if paused {
+		cma.Annotations[rollout.PausedAnnotation] = "true"
+	}
+	cma.Spec.SupportedConfigs = supportConfig(cma.Spec.SupportedConfigs, defaultConfig)
+	_, err = addonClient.AddonV1alpha1().ClusterManagementAddOns().Update(ctx, cma, metav1.UpdateOptions{})
+	return err
+}
+
+// upgradeBatch points the addons of the clusters of the batch to the config and waits until they have applied it
+// and are available. It returns the clusters the addon failed to upgrade on.
+func (o *Options) upgradeBatch(addonClient addonclientset.Interface, addons map[string]*addonv1alpha1.ManagedClusterAddOn,
+	batch []string, batchName, name string, generation int64, timeout time.Duration) ([]string, error) {
+	ctx := context.TODO()
+	ref := addonv1alpha1.AddOnConfig{
+		ConfigGroupResource: deploymentConfig,
+		ConfigReferent:      addonv1alpha1.ConfigReferent{Namespace: o.configNamespace, Name: name},
+	}
+	for _, cluster := range batch {
+		addon := addons[cluster].DeepCopy()
+		addon.Spec.Configs = setConfig(addon.Spec.Configs, ref)
+		updated, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(cluster).Update(ctx, addon, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		addons[cluster] = updated
+		o.report(updated, batchName, name, generation, stateUpgrading)
+	}
+
+	pending := sets.NewString(batch...)
+	failed := sets.NewString()
+	err := wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		for _, cluster := range pending.List() {
+			addon, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(cluster).Get(ctx, o.addon, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			addons[cluster] = addon
+			state, _ := upgradeState(addon, name, generation)
+			o.report(addon, batchName, name, generation, state)
+			switch state {
+			case stateUpgraded:
+				pending.Delete(cluster)
+			case stateFailed:
+				pending.Delete(cluster)
+				failed.Insert(cluster)
+			}
+		}
+		return pending.Len() == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		for _, cluster := range pending.List() {
+			o.report(addons[cluster], batchName, name, generation, stateFailed)
+		}
+		return failed.Union(pending).List(), nil
+	}
+	return failed.List(), err
+}
+
+// report updates the row of the cluster of the addon in the progress table
+func (o *Options) report(addon *addonv1alpha1.ManagedClusterAddOn, batch, name string, generation int64, state string) {
+	_, version := upgradeState(addon, name, generation)
+	if version == name {
+		version = o.version
+	}
+	available := string(metav1.ConditionUnknown)
+	if condition := meta.FindStatusCondition(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable); condition != nil {
+		available = string(condition.Status)
+	}
+	o.progress.Update(addon.Namespace, addon.Namespace, batch, version, available, state)
+}
+
+// upgradeState returns the state of the upgrade of the addon to the config of the name and generation, and the name
+// of the deployment config the addon has applied. A negative generation matches any generation.
+func upgradeState(addon *addonv1alpha1.ManagedClusterAddOn, name string, generation int64) (string, string) {
+	applied := "<none>"
+	observed := false
+	for _, ref := range addon.Status.ConfigReferences {
+		if ref.ConfigGroupResource != deploymentConfig {
+			continue
+		}
+		applied = ref.Name
+		observed = ref.Name == name && (generation < 0 || ref.LastObservedGeneration == generation)
+	}
+	referenced := false
+	for _, config := range addon.Spec.Configs {
+		if config.ConfigGroupResource == deploymentConfig && config.Name == name {
+			referenced = true
+		}
+	}
+	switch {
+	case !referenced:
+		return statePending, applied
+	case meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded):
+		return stateFailed, applied
+	case !observed:
+		return stateUpgrading, applied
+	case meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable):
+		return stateUpgraded, applied
+	case meta.IsStatusConditionFalse(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable):
+		return stateFailed, applied
+	}
+	return stateUpgrading, applied
+}
+
+// configName returns the name of the AddOnDeploymentConfig of the version of the addon
+func configName(addon, version string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(addon+"-"+version), "-"), "-")
+}
+
+// setConfig replaces the deployment config of the configs by the ref
+func setConfig(configs []addonv1alpha1.AddOnConfig, ref addonv1alpha1.AddOnConfig) []addonv1alpha1.AddOnConfig {
+	result := []addonv1alpha1.AddOnConfig{}
+	for _, config := range configs {
+		if config.ConfigGroupResource != ref.ConfigGroupResource {
+			result = append(result, config)
+		}
+	}
+	return append(result, ref)
+}
+
+// setVariable replaces the variable of the name of the variables
+func setVariable(variables []addonv1alpha1.CustomizedVariable, variable addonv1alpha1.CustomizedVariable) []addonv1alpha1.CustomizedVariable {
+	for i := range variables {
+		if variables[i].Name == variable.Name {
+			variables[i].Value = variable.Value
+			return variables
+		}
+	}
+	return append(variables, variable)
+}
+
+// supportConfig adds the deployment configs to the supported configs, with the default config if not nil
+func supportConfig(supported []addonv1alpha1.ConfigMeta, defaultConfig *addonv1alpha1.ConfigReferent) []addonv1alpha1.ConfigMeta {
+	for i := range supported {
+		if supported[i].ConfigGroupResource == deploymentConfig {
+			if defaultConfig != nil {
+				supported[i].DefaultConfig = defaultConfig
+			}
+			return supported
+		}
+	}
+	return append(supported, addonv1alpha1.ConfigMeta{ConfigGroupResource: deploymentConfig, DefaultConfig: defaultConfig})
+}
@@ -0,0 +1,113 @@
+// Copyright Contributors to the Open Cluster Management project
+package upgrade
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+func TestConfigName(t *testing.T) {
+	if name := configName("application-manager", "v2.7.0+build_1"); name != "application-manager-v2-7-0-build-1" {
+		t.Errorf("unexpected config name %s", name)
+	}
+}
+
+func TestUpgradeState(t *testing.T) {
+	ref := addonv1alpha1.AddOnConfig{ConfigGroupResource: deploymentConfig, ConfigReferent: addonv1alpha1.ConfigReferent{Name: "addon-2-7-0"}}
+	observed := func(name string, generation int64) []addonv1alpha1.ConfigReference {
+		return []addonv1alpha1.ConfigReference{{
+			ConfigGroupResource:    deploymentConfig,
+			ConfigReferent:         addonv1alpha1.ConfigReferent{Name: name},
+			LastObservedGeneration: generation,
+		}}
+	}
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+	cases := []struct {
+		name            string
+		configs         []addonv1alpha1.AddOnConfig
+		references      []addonv1alpha1.ConfigReference
+		conditions      []metav1.Condition
+		expectedState   string
+		expectedApplied string
+	}{
+		{
+			name:            "not referenced",
+			references:      observed("addon-2-6-0", 1),
+			expectedState:   statePending,
+			expectedApplied: "addon-2-6-0",
+		},
+		{
+			name:            "not observed",
+			configs:         []addonv1alpha1.AddOnConfig{ref},
+			references:      observed("addon-2-7-0", 1),
+			conditions:      []metav1.Condition{condition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionTrue)},
+			expectedState:   stateUpgrading,
+			expectedApplied: "addon-2-7-0",
+		},
+		{
+			name:            "upgraded",
+			configs:         []addonv1alpha1.AddOnConfig{ref},
+			references:      observed("addon-2-7-0", 2),
+			conditions:      []metav1.Condition{condition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionTrue)},
+			expectedState:   stateUpgraded,
+			expectedApplied: "addon-2-7-0",
+		},
+		{
+			name:            "not available",
+			configs:         []addonv1alpha1.AddOnConfig{ref},
+			references:      observed("addon-2-7-0", 2),
+			conditions:      []metav1.Condition{condition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionFalse)},
+			expectedState:   stateFailed,
+			expectedApplied: "addon-2-7-0",
+		},
+		{
+			name:            "degraded",
+			configs:         []addonv1alpha1.AddOnConfig{ref},
+			conditions:      []metav1.Condition{condition(addonv1alpha1.ManagedClusterAddOnConditionDegraded, metav1.ConditionTrue)},
+			expectedState:   stateFailed,
+			expectedApplied: "<none>",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addon := &addonv1alpha1.ManagedClusterAddOn{
+				Spec:   addonv1alpha1.ManagedClusterAddOnSpec{Configs: c.configs},
+				Status: addonv1alpha1.ManagedClusterAddOnStatus{ConfigReferences: c.references, Conditions: c.conditions},
+			}
+			state, applied := upgradeState(addon, "addon-2-7-0", 2)
+			if state != c.expectedState || applied != c.expectedApplied {
+				t.Errorf("expected %s %s, got %s %s", c.expectedState, c.expectedApplied, state, applied)
+			}
+		})
+	}
+}
+
+func TestSetConfig(t *testing.T) {
+	other := addonv1alpha1.AddOnConfig{
+		ConfigGroupResource: addonv1alpha1.ConfigGroupResource{Group: "example.com", Resource: "configs"},
+		ConfigReferent:      addonv1alpha1.ConfigReferent{Name: "config"},
+	}
+	previous := addonv1alpha1.AddOnConfig{ConfigGroupResource: deploymentConfig, ConfigReferent: addonv1alpha1.ConfigReferent{Name: "addon-2-6-0"}}
+	ref := addonv1alpha1.AddOnConfig{ConfigGroupResource: deploymentConfig, ConfigReferent: addonv1alpha1.ConfigReferent{Name: "addon-2-7-0"}}
+	configs := setConfig([]addonv1alpha1.AddOnConfig{previous, other}, ref)
+	if expected := []addonv1alpha1.AddOnConfig{other, ref}; !reflect.DeepEqual(configs, expected) {
+		t.Errorf("expected %v, got %v", expected, configs)
+	}
+}
+
+func TestSupportConfig(t *testing.T) {
+	supported := supportConfig(nil, nil)
+	if len(supported) != 1 || supported[0].ConfigGroupResource != deploymentConfig || supported[0].DefaultConfig != nil {
+		t.Errorf("unexpected supported configs %v", supported)
+	}
+	defaultConfig := &addonv1alpha1.ConfigReferent{Namespace: "open-cluster-management", Name: "addon-2-7-0"}
+	supported = supportConfig(supported, defaultConfig)
+	if len(supported) != 1 || supported[0].DefaultConfig != defaultConfig {
+		t.Errorf("unexpected supported configs %v", supported)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright Contributors to the Open Cluster Management project
+package upgrade
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The name of the addon
+	addon string
+	//The version the addon is upgraded to
+	version string
+	//The rollout strategy of the upgrade
+	strategy rollout.Strategy
+	//The max concurrency of the strategy, as set in --max-concurrency
+	maxConcurrency string
+	//The clusters to upgrade, all the clusters of the addon if empty
+	clusters []string
+	//The clusterset whose members are upgraded
+	clusterSet string
+	//The namespace of the AddOnDeploymentConfig of the version
+	configNamespace string
+	//Resume the paused upgrade
+	resume bool
+
+	Streams genericclioptions.IOStreams
+	//The progress table of the clusters
+	progress *printer.Progress
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright Contributors to the Open Cluster Management project
+package attach
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Attach the clustermanager or klusterlet installed by helm or OLM on the cluster, then upgrade it
+%[1]s attach
+%[1]s upgrade clustermanager --bundle-version latest
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "attach",
+		Short: "attach the clustermanager or klusterlet installed by another tool",
+		Long: "attach the clustermanager or klusterlet of the cluster installed by another tool, e.g. helm or OLM. " +
+			"Its registration operator is discovered and recorded in an annotation, upgrade then upgrades that operator " +
+			"instead of installing the one of clusteradm, and clean removes it if it is not managed by helm or OLM.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	return cmd
+}
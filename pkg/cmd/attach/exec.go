@@ -0,0 +1,132 @@
+// Copyright Contributors to the Open Cluster Management project
+package attach
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) error {
+	klog.V(1).InfoS("attach options:", "dry-run", o.ClusteradmFlags.DryRun)
+	if len(args) > 0 {
+		return fmt.Errorf("attach takes no argument")
+	}
+	return nil
+}
+
+func (o *Options) validate() error {
+	return nil
+}
+
+func (o *Options) run() error {
+	ctx := context.TODO()
+	kubeClient, err := o.ClusteradmFlags.KubectlFactory.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	apiExtensionsClient, err := apiextensionsclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	attached := 0
+	if installed, err := helpers.IsClusterManagerInstalled(apiExtensionsClient); err != nil {
+		return err
+	} else if installed {
+		clusterManagers, err := operatorClient.OperatorV1().ClusterManagers().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, clusterManager := range clusterManagers.Items {
+			installation, err := attach.Discover(ctx, kubeClient, attach.ModeHub)
+			if err != nil {
+				return fmt.Errorf("failed to attach clustermanager %s: %v", clusterManager.Name, err)
+			}
+			if err := o.record("clustermanager", clusterManager.Name, installation, func(patch []byte) error {
+				_, err := operatorClient.OperatorV1().ClusterManagers().Patch(ctx, clusterManager.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+				return err
+			}); err != nil {
+				return err
+			}
+			attached++
+		}
+	}
+	if installed, err := helpers.IsKlusterletsInstalled(apiExtensionsClient); err != nil {
+		return err
+	} else if installed {
+		klusterlets, err := operatorClient.OperatorV1().Klusterlets().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, klusterlet := range klusterlets.Items {
+			installation, err := attach.Discover(ctx, kubeClient, attach.ModeKlusterlet)
+			if err != nil {
+				return fmt.Errorf("failed to attach klusterlet %s: %v", klusterlet.Name, err)
+			}
+			if err := o.record("klusterlet", klusterlet.Name, installation, func(patch []byte) error {
+				_, err := operatorClient.OperatorV1().Klusterlets().Patch(ctx, klusterlet.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+				return err
+			}); err != nil {
+				return err
+			}
+			attached++
+		}
+	}
+	if attached == 0 {
+		return fmt.Errorf("no clustermanager or klusterlet is found on the cluster")
+	}
+	return nil
+}
+
+// record annotates the clustermanager or klusterlet with its installation
+func (o *Options) record(kind, name string, installation *attach.Installation, patchFn func([]byte) error) error {
+	fmt.Fprintf(o.Streams.Out, "%s %s: operator %s/%s installed by %s\n", kind, name,
+		installation.OperatorNamespace, installation.OperatorName, describeInstaller(installation))
+	switch installation.Installer {
+	case attach.InstallerHelm:
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: the next upgrade of the helm release %s overwrites the upgrades of clusteradm, "+
+			"clean does not remove the operator\n", installation.Release)
+	case attach.InstallerOLM:
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: the operator is upgraded by its OLM subscription instead of clusteradm, "+
+			"clean does not remove it\n")
+	}
+	if o.ClusteradmFlags.DryRun {
+		return nil
+	}
+	patch, err := installation.Patch()
+	if err != nil {
+		return err
+	}
+	if err := patchFn(patch); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "%s %s attached\n", kind, name)
+	return nil
+}
+
+func describeInstaller(installation *attach.Installation) string {
+	switch {
+	case installation.Installer == attach.InstallerOther:
+		return "clusteradm or another tool"
+	case len(installation.Release) > 0:
+		return fmt.Sprintf("%s (%s)", installation.Installer, installation.Release)
+	}
+	return installation.Installer
+}
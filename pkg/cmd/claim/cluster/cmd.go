@@ -0,0 +1,60 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Claim a cluster from a hive cluster pool and set it to the clusterset of the pool
+%[1]s claim cluster --pool pool1 -n pools
+
+# Claim a cluster for 8 hours and set it to a clusterset
+%[1]s claim cluster claim1 --pool pool1 -n pools --clusterset clusterset1 --lifetime 8h
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "cluster [NAME]",
+		Short: "claim a cluster from a hive cluster pool",
+		Long: "create a hive cluster claim on a cluster pool and wait for a cluster to be assigned to it. " +
+			"The claimed cluster is set to the clusterset of the pool, or the one of --clusterset, once it is registered to the hub.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.pool, "pool", "", "The name of the cluster pool to claim the cluster from")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the cluster pool, the namespace of the kubeconfig if not set")
+	cmd.Flags().StringVar(&o.clusterSet, "clusterset", "", "The clusterset to set the claimed cluster to, the clusterset of the pool if not set")
+	cmd.Flags().StringVar(&o.lifetime, "lifetime", "", "The lifetime of the claim, the cluster is deleted after it (e.g. 8h)")
+	cmd.Flags().BoolVar(&o.wait, "wait", true, "Wait for a cluster to be assigned to the claim")
+
+	return cmd
+}
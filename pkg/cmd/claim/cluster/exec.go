@@ -0,0 +1,154 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hive"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("claim cluster options:", "pool", o.pool, "namespace", o.namespace, "clusterset", o.clusterSet, "lifetime", o.lifetime, "wait", o.wait)
+
+	if len(args) > 1 {
+		return fmt.Errorf("only one claim can be specified")
+	}
+	if len(args) == 1 {
+		o.claimName = args[0]
+	}
+
+	if len(o.namespace) == 0 {
+		o.namespace, _, err = o.ClusteradmFlags.KubectlFactory.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(o.pool) == 0 {
+		return fmt.Errorf("the cluster pool must be specified in --pool")
+	}
+
+	if len(o.lifetime) > 0 {
+		if _, err := time.ParseDuration(o.lifetime); err != nil {
+			return fmt.Errorf("invalid --lifetime %q: %v", o.lifetime, err)
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	pool, err := dynamicClient.Resource(hive.ClusterPoolGVR).Namespace(o.namespace).Get(context.TODO(), o.pool, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get cluster pool %s/%s: %v", o.namespace, o.pool, err)
+	}
+	if len(o.clusterSet) == 0 {
+		o.clusterSet = pool.GetLabels()[hive.ClusterSetLabel]
+	}
+
+	claim := hive.NewClaim(o.namespace, o.claimName, o.pool, o.lifetime)
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "Claim a cluster from cluster pool %s/%s\n", o.namespace, o.pool)
+		return nil
+	}
+
+	claim, err = dynamicClient.Resource(hive.ClusterClaimGVR).Namespace(o.namespace).Create(context.TODO(), claim, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "Cluster claim %s/%s is created\n", claim.GetNamespace(), claim.GetName())
+
+	if !o.wait {
+		return nil
+	}
+
+	clusterName, err := o.waitForCluster(dynamicClient, claim.GetName())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "Cluster %s is claimed\n", clusterName)
+
+	return o.setClusterSet(clusterName)
+}
+
+// waitForCluster waits for a cluster of the pool to be assigned to the claim and returns its name
+func (o *Options) waitForCluster(dynamicClient dynamic.Interface, claimName string) (string, error) {
+	var clusterName string
+	err := wait.PollImmediate(2*time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		claim, err := dynamicClient.Resource(hive.ClusterClaimGVR).Namespace(o.namespace).Get(context.TODO(), claimName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		clusterName = hive.ClaimedCluster(*claim)
+		return len(clusterName) > 0, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("no cluster is assigned to claim %s/%s: %v", o.namespace, claimName, err)
+	}
+	return clusterName, nil
+}
+
+// setClusterSet sets the claimed cluster to the clusterset if it is registered to the hub
+func (o *Options) setClusterSet(clusterName string) error {
+	if len(o.clusterSet) == 0 {
+		return nil
+	}
+
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		fmt.Fprintf(o.Streams.Out, "Cluster %s is not registered to the hub yet, join it then run \"clusteradm clusterset set %s --clusters %s\"\n",
+			clusterName, o.clusterSet, clusterName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if cluster.Labels[hive.ClusterSetLabel] == o.clusterSet {
+		fmt.Fprintf(o.Streams.Out, "Cluster %s is already in Clusterset %s\n", clusterName, o.clusterSet)
+		return nil
+	}
+	if len(cluster.Labels) == 0 {
+		cluster.Labels = map[string]string{}
+	}
+	cluster.Labels[hive.ClusterSetLabel] = o.clusterSet
+	_, err = clusterClient.ClusterV1().ManagedClusters().Update(context.TODO(), cluster, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "Cluster %s is set to Clusterset %s\n", clusterName, o.clusterSet)
+
+	return nil
+}
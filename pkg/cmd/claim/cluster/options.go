@@ -0,0 +1,33 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The name of the claim, generated from the pool if not set
+	claimName string
+	//The cluster pool to claim the cluster from
+	pool string
+	//The namespace of the cluster pool and the claim
+	namespace string
+	//The clusterset to set the claimed cluster to
+	clusterSet string
+	//The lifetime of the claim
+	lifetime string
+	//Wait for a cluster to be assigned to the claim
+	wait bool
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright Contributors to the Open Cluster Management project
+package claim
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/claim/cluster"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the commands claiming resources from a pool
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim",
+		Short: "claim a resource from a pool",
+	}
+
+	cmd.AddCommand(cluster.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
@@ -5,7 +5,10 @@ package init
 import (
 	"fmt"
 
+	"open-cluster-management.io/clusteradm/pkg/cmd/clean/orphans"
+	"open-cluster-management.io/clusteradm/pkg/cmd/clean/stuck"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -32,10 +35,10 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.complete(c, args); err != nil {
-				return err
+				return exit.Validation(err)
 			}
 			if err := o.Validate(); err != nil {
-				return err
+				return exit.Validation(err)
 			}
 			if err := o.Run(); err != nil {
 				return err
@@ -48,5 +51,9 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd.Flags().StringVar(&o.ClusterManageName, "name", "cluster-manager", "The name of the cluster manager resource")
 	cmd.Flags().StringVar(&o.OutputFile, "output-file", "", "The generated resources will be copied in the specified file")
 	cmd.Flags().BoolVar(&o.purgeOperator, "purge-operator", true, "Purge the operator")
+
+	cmd.AddCommand(orphans.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(stuck.NewCmd(clusteradmFlags, streams))
+	o.maintenance.AddFlags(cmd.Flags())
 	return cmd
 }
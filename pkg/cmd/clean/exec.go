@@ -19,6 +19,7 @@ import (
 	"k8s.io/klog/v2"
 	clustermanagerclient "open-cluster-management.io/api/client/operator/clientset/versioned"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
 )
 
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
@@ -33,6 +34,9 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 }
 
 func (o *Options) Validate() error {
+	if err := o.maintenance.Validate(time.Now(), o.Streams.ErrOut); err != nil {
+		return err
+	}
 	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
 	if err != nil {
 		return err
@@ -73,6 +77,14 @@ func (o *Options) Run() error {
 		return err
 	}
 
+	// the operator of the clustermanager attached by attach may be installed by another tool
+	var installation *attach.Installation
+	if clusterManager, err := clusterManagerClient.OperatorV1().ClusterManagers().Get(context.Background(), o.ClusterManageName, metav1.GetOptions{}); err == nil {
+		if installation, err = attach.Parse(clusterManager.Annotations); err != nil {
+			return err
+		}
+	}
+
 	err = clusterManagerClient.OperatorV1().ClusterManagers().Delete(context.Background(), o.ClusterManageName, metav1.DeleteOptions{})
 	if errors.IsNotFound(err) {
 		fmt.Fprintf(o.Streams.Out, "The multicluster hub control plane is cleand up already\n")
@@ -86,7 +98,18 @@ func (o *Options) Run() error {
 		return err
 	}
 
-	if o.purgeOperator {
+	switch {
+	case !o.purgeOperator:
+	case installation != nil && installation.ManagedExternally():
+		fmt.Fprintf(o.Streams.Out, "The operator %s/%s is installed by %s %s, remove it with %s\n",
+			installation.OperatorNamespace, installation.OperatorName, installation.Installer, installation.Release, installation.Installer)
+	default:
+		if installation != nil {
+			err := kubeClient.AppsV1().Deployments(installation.OperatorNamespace).Delete(context.Background(), installation.OperatorName, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
 		if err := puregeOperator(kubeClient, apiExtensionsClient); err != nil {
 			return err
 		}
@@ -125,7 +148,11 @@ func IsClusterManagerExist(cilent clustermanagerclient.Interface) bool {
 
 func (o *Options) removeBootStrapSecret(client kubernetes.Interface) error {
 	var errs []error
-	err := client.RbacV1().
+	saNamespace, err := helpers.GetBootstrapSANamespace(context.Background(), client)
+	if err != nil {
+		return err
+	}
+	err = client.RbacV1().
 		ClusterRoles().
 		Delete(context.Background(), "system:open-cluster-management:bootstrap", metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
@@ -150,7 +177,7 @@ func (o *Options) removeBootStrapSecret(client kubernetes.Interface) error {
 		errs = append(errs, err)
 	}
 	err = client.CoreV1().
-		ServiceAccounts("open-cluster-management").
+		ServiceAccounts(saNamespace).
 		Delete(context.Background(), "cluster-bootstrap", metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		errs = append(errs, err)
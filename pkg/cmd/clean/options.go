@@ -5,6 +5,7 @@ package init
 import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/maintenance"
 )
 
 //Options: The structure holding all the command-line options
@@ -20,6 +21,8 @@ type Options struct {
 	purgeOperator bool
 
 	Streams genericclioptions.IOStreams
+	//The maintenance window the command runs in
+	maintenance maintenance.Options
 }
 
 //Values: The values used in the template
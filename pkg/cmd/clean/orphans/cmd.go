@@ -0,0 +1,57 @@
+// Copyright Contributors to the Open Cluster Management project
+package orphans
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# List the resources left on the hub by the clusters which are gone
+%[1]s clean orphans
+
+# Delete them
+%[1]s clean orphans --confirm
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "clean the resources of the removed clusters on the hub",
+		Long: "find the cluster namespaces without managed cluster, the roles and bindings of the removed clusters, " +
+			"the manifestworks and addons left in their namespaces, the expired certificate signing requests and bootstrap tokens, " +
+			"and delete them with --confirm",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.confirm, "confirm", false, "Delete the orphaned resources found, they are only listed if not set")
+	cmd.Flags().DurationVar(&o.pendingCSRTTL, "pending-csr-ttl", 24*time.Hour,
+		"The age above which a certificate signing request of a cluster which is not approved is expired")
+	return cmd
+}
@@ -0,0 +1,315 @@
+// Copyright Contributors to the Open Cluster Management project
+package orphans
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/i18n"
+)
+
+const (
+	clusterLabel = "open-cluster-management.io/cluster-name"
+	// the prefix of the cluster roles and bindings created by the hub for each cluster
+	clusterRolePrefix = "open-cluster-management:managedcluster:"
+	// the namespace and the type of the bootstrap token secrets
+	tokenNamespace  = "kube-system"
+	tokenSecretType = corev1.SecretType("bootstrap.kubernetes.io/token")
+)
+
+const (
+	kindManifestWork        = "ManifestWork"
+	kindManagedClusterAddOn = "ManagedClusterAddOn"
+	kindCSR                 = "CertificateSigningRequest"
+	kindSecret              = "Secret"
+	kindClusterRoleBinding  = "ClusterRoleBinding"
+	kindClusterRole         = "ClusterRole"
+	kindNamespace           = "Namespace"
+)
+
+// sharedClusterRoles are the cluster roles with the cluster role prefix shared by all the clusters
+var sharedClusterRoles = sets.NewString("registration", "work", "bootstrap")
+
+// orphan is a resource left on the hub
+type orphan struct {
+	kind      string
+	namespace string
+	name      string
+	reason    string
+}
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("clean orphans options:", "dry-run", o.ClusteradmFlags.DryRun, "confirm", o.confirm, "pending-csr-ttl", o.pendingCSRTTL)
+	return nil
+}
+
+func (o *Options) validate() error {
+	return o.ClusteradmFlags.ValidateHub()
+}
+
+func (o *Options) run() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	addonClient, err := addonclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	managedClusters, err := clusterClient.ClusterV1().ManagedClusters().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	clusters := sets.NewString()
+	for _, cluster := range managedClusters.Items {
+		clusters.Insert(cluster.Name)
+	}
+
+	// the orphans are found in the order they are deleted, the namespaces last
+	var orphans []orphan
+	works, err := workClient.WorkV1().ManifestWorks(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, work := range works.Items {
+		if !clusters.Has(work.Namespace) {
+			orphans = append(orphans, orphan{kindManifestWork, work.Namespace, work.Name, fmt.Sprintf("managed cluster %s not found", work.Namespace)})
+		}
+	}
+	addons, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, addon := range addons.Items {
+		if !clusters.Has(addon.Namespace) {
+			orphans = append(orphans, orphan{kindManagedClusterAddOn, addon.Namespace, addon.Name, fmt.Sprintf("managed cluster %s not found", addon.Namespace)})
+		}
+	}
+	for _, find := range []func(kubernetes.Interface, sets.String, time.Time) ([]orphan, error){
+		o.expiredCSRs,
+		expiredTokens,
+		orphanClusterRoles,
+		orphanNamespaces,
+	} {
+		found, err := find(kubeClient, clusters, time.Now())
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, found...)
+	}
+
+	if len(orphans) == 0 {
+		fmt.Fprintf(o.Streams.Out, "No orphaned resource found\n")
+		return nil
+	}
+	w := tabwriter.NewWriter(o.Streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tREASON")
+	for _, r := range orphans {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.kind, r.namespace, r.name, r.reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if !o.confirm || o.ClusteradmFlags.DryRun {
+		fmt.Fprint(o.Streams.Out, i18n.T("clean.orphans.confirm", len(orphans)))
+		return nil
+	}
+
+	fmt.Fprintln(o.Streams.Out)
+	var failed int
+	for _, r := range orphans {
+		err := deleteOrphan(kubeClient, workClient, addonClient, r)
+		if err != nil && !errors.IsNotFound(err) {
+			failed++
+			fmt.Fprintf(o.Streams.ErrOut, "failed to delete %s %s: %v\n", r.kind, qualifiedName(r), err)
+			continue
+		}
+		fmt.Fprintf(o.Streams.Out, "%s %s is deleted\n", r.kind, qualifiedName(r))
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of the %d orphaned resources", failed, len(orphans))
+	}
+	return nil
+}
+
+// orphanNamespaces returns the cluster namespaces without managed cluster
+func orphanNamespaces(kubeClient kubernetes.Interface, clusters sets.String, _ time.Time) ([]orphan, error) {
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: clusterLabel})
+	if err != nil {
+		return nil, err
+	}
+	var orphans []orphan
+	for _, ns := range namespaces.Items {
+		cluster := ns.Labels[clusterLabel]
+		if !clusters.Has(cluster) {
+			orphans = append(orphans, orphan{kindNamespace, "", ns.Name, fmt.Sprintf("managed cluster %s not found", cluster)})
+		}
+	}
+	return orphans, nil
+}
+
+// orphanClusterRoles returns the cluster roles and bindings of the clusters without managed cluster
+func orphanClusterRoles(kubeClient kubernetes.Interface, clusters sets.String, _ time.Time) ([]orphan, error) {
+	clusterOf := func(name string) (string, bool) {
+		if !strings.HasPrefix(name, clusterRolePrefix) {
+			return "", false
+		}
+		cluster := strings.TrimPrefix(name, clusterRolePrefix)
+		if strings.Contains(cluster, ":") || sharedClusterRoles.Has(cluster) || clusters.Has(cluster) {
+			return "", false
+		}
+		return cluster, true
+	}
+
+	var orphans []orphan
+	bindings, err := kubeClient.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings.Items {
+		if cluster, ok := clusterOf(binding.Name); ok {
+			orphans = append(orphans, orphan{kindClusterRoleBinding, "", binding.Name, fmt.Sprintf("managed cluster %s not found", cluster)})
+		}
+	}
+	roles, err := kubeClient.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles.Items {
+		if cluster, ok := clusterOf(role.Name); ok {
+			orphans = append(orphans, orphan{kindClusterRole, "", role.Name, fmt.Sprintf("managed cluster %s not found", cluster)})
+		}
+	}
+	return orphans, nil
+}
+
+// expiredCSRs returns the certificate signing requests of the clusters which are expired, not approved
+// in time or of a cluster without managed cluster.
+func (o *Options) expiredCSRs(kubeClient kubernetes.Interface, clusters sets.String, now time.Time) ([]orphan, error) {
+	csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{LabelSelector: clusterLabel})
+	if err != nil {
+		return nil, err
+	}
+	var orphans []orphan
+	for _, csr := range csrs.Items {
+		if reason := csrExpiry(&csr, clusters, now, o.pendingCSRTTL); len(reason) > 0 {
+			orphans = append(orphans, orphan{kindCSR, "", csr.Name, reason})
+		}
+	}
+	return orphans, nil
+}
+
+// csrExpiry returns the reason the csr is expired, or an empty string if it is not
+func csrExpiry(csr *certificatesv1.CertificateSigningRequest, clusters sets.String, now time.Time, pendingTTL time.Duration) string {
+	if cluster := csr.Labels[clusterLabel]; !clusters.Has(cluster) {
+		return fmt.Sprintf("managed cluster %s not found", cluster)
+	}
+	if len(csr.Status.Certificate) > 0 {
+		block, _ := pem.Decode(csr.Status.Certificate)
+		if block == nil {
+			return ""
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || now.Before(cert.NotAfter) {
+			return ""
+		}
+		return fmt.Sprintf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+	if age := now.Sub(csr.CreationTimestamp.Time); age > pendingTTL {
+		return fmt.Sprintf("not issued after %s", age.Round(time.Minute))
+	}
+	return ""
+}
+
+// expiredTokens returns the bootstrap tokens which are expired
+func expiredTokens(kubeClient kubernetes.Interface, _ sets.String, now time.Time) ([]orphan, error) {
+	secrets, err := kubeClient.CoreV1().Secrets(tokenNamespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("type=%s", tokenSecretType),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var orphans []orphan
+	for _, secret := range secrets.Items {
+		if secret.Type != tokenSecretType {
+			continue
+		}
+		expiration, err := time.Parse(time.RFC3339, string(secret.Data["expiration"]))
+		if err != nil || now.Before(expiration) {
+			continue
+		}
+		orphans = append(orphans, orphan{kindSecret, secret.Namespace, secret.Name,
+			fmt.Sprintf("bootstrap token expired at %s", expiration.Format(time.RFC3339))})
+	}
+	return orphans, nil
+}
+
+// deleteOrphan deletes the resource, the finalizers of the manifestworks and addons are removed
+// as there is no agent left to remove them.
+func deleteOrphan(kubeClient kubernetes.Interface, workClient workclientset.Interface, addonClient addonclientset.Interface, r orphan) error {
+	ctx := context.TODO()
+	removeFinalizers := []byte(`{"metadata":{"finalizers":null}}`)
+	switch r.kind {
+	case kindManifestWork:
+		_, err := workClient.WorkV1().ManifestWorks(r.namespace).Patch(ctx, r.name, types.MergePatchType, removeFinalizers, metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+		return workClient.WorkV1().ManifestWorks(r.namespace).Delete(ctx, r.name, metav1.DeleteOptions{})
+	case kindManagedClusterAddOn:
+		_, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(r.namespace).Patch(ctx, r.name, types.MergePatchType, removeFinalizers, metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+		return addonClient.AddonV1alpha1().ManagedClusterAddOns(r.namespace).Delete(ctx, r.name, metav1.DeleteOptions{})
+	case kindCSR:
+		return kubeClient.CertificatesV1().CertificateSigningRequests().Delete(ctx, r.name, metav1.DeleteOptions{})
+	case kindSecret:
+		return kubeClient.CoreV1().Secrets(r.namespace).Delete(ctx, r.name, metav1.DeleteOptions{})
+	case kindClusterRoleBinding:
+		return kubeClient.RbacV1().ClusterRoleBindings().Delete(ctx, r.name, metav1.DeleteOptions{})
+	case kindClusterRole:
+		return kubeClient.RbacV1().ClusterRoles().Delete(ctx, r.name, metav1.DeleteOptions{})
+	case kindNamespace:
+		return kubeClient.CoreV1().Namespaces().Delete(ctx, r.name, metav1.DeleteOptions{})
+	}
+	return fmt.Errorf("unknown kind %s", r.kind)
+}
+
+func qualifiedName(r orphan) string {
+	if len(r.namespace) == 0 {
+		return r.name
+	}
+	return r.namespace + "/" + r.name
+}
@@ -0,0 +1,135 @@
+// Copyright Contributors to the Open Cluster Management project
+package orphans
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestOrphanNamespaces(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{clusterLabel: "cluster1"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster2", Labels: map[string]string{clusterLabel: "cluster2"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	)
+	orphans, err := orphanNamespaces(kubeClient, sets.NewString("cluster1"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []orphan{{kindNamespace, "", "cluster2", "managed cluster cluster2 not found"}}
+	if !reflect.DeepEqual(orphans, expected) {
+		t.Errorf("expected %v, got %v", expected, orphans)
+	}
+}
+
+func TestOrphanClusterRoles(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:managedcluster:cluster1"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:managedcluster:cluster2"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:managedcluster:registration"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:managedclusterset:admin:dev"}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:managedcluster:cluster2"}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:managedcluster:cluster2:addon"}},
+	)
+	orphans, err := orphanClusterRoles(kubeClient, sets.NewString("cluster1"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []orphan{
+		{kindClusterRoleBinding, "", "open-cluster-management:managedcluster:cluster2", "managed cluster cluster2 not found"},
+		{kindClusterRole, "", "open-cluster-management:managedcluster:cluster2", "managed cluster cluster2 not found"},
+	}
+	if !reflect.DeepEqual(orphans, expected) {
+		t.Errorf("expected %v, got %v", expected, orphans)
+	}
+}
+
+func TestExpiredTokens(t *testing.T) {
+	token := func(name, expiration string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tokenNamespace},
+			Type:       tokenSecretType,
+			Data:       map[string][]byte{"expiration": []byte(expiration)},
+		}
+	}
+	kubeClient := fake.NewSimpleClientset(
+		token("bootstrap-token-expired", now.Add(-time.Hour).Format(time.RFC3339)),
+		token("bootstrap-token-valid", now.Add(time.Hour).Format(time.RFC3339)),
+		token("bootstrap-token-forever", ""),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: tokenNamespace}},
+	)
+	orphans, err := expiredTokens(kubeClient, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []orphan{{kindSecret, tokenNamespace, "bootstrap-token-expired", "bootstrap token expired at 2022-12-31T23:00:00Z"}}
+	if !reflect.DeepEqual(orphans, expected) {
+		t.Errorf("expected %v, got %v", expected, orphans)
+	}
+}
+
+func TestCSRExpiry(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := func(notAfter time.Time) []byte {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "system:open-cluster-management:cluster1:agent"},
+			NotBefore:    notAfter.AddDate(0, -1, 0),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	csr := func(cluster string, created time.Time, certificate []byte) *certificatesv1.CertificateSigningRequest {
+		return &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:            map[string]string{clusterLabel: cluster},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: certificatesv1.CertificateSigningRequestStatus{Certificate: certificate},
+		}
+	}
+
+	clusters := sets.NewString("cluster1")
+	cases := []struct {
+		name     string
+		csr      *certificatesv1.CertificateSigningRequest
+		expected string
+	}{
+		{name: "cluster not found", csr: csr("cluster2", now, nil), expected: "managed cluster cluster2 not found"},
+		{name: "pending", csr: csr("cluster1", now.Add(-time.Hour), nil)},
+		{name: "pending too long", csr: csr("cluster1", now.Add(-48*time.Hour), nil), expected: "not issued after 48h0m0s"},
+		{name: "issued", csr: csr("cluster1", now.Add(-48*time.Hour), cert(now.AddDate(0, 1, 0)))},
+		{name: "certificate expired", csr: csr("cluster1", now.AddDate(0, -2, 0), cert(now.Add(-time.Hour))),
+			expected: "certificate expired at 2022-12-31T23:00:00Z"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := csrExpiry(c.csr, clusters, now, 24*time.Hour); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
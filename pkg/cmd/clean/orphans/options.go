@@ -0,0 +1,28 @@
+// Copyright Contributors to the Open Cluster Management project
+package orphans
+
+import (
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//Delete the orphaned resources found
+	confirm bool
+	//The age above which a pending certificate signing request is expired
+	pendingCSRTTL time.Duration
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+package stuck
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Explain why the managed cluster cluster1, its manifestworks or its namespace are stuck in Terminating
+%[1]s clean stuck --cluster cluster1
+
+# Remove the finalizers blocking their deletion
+%[1]s clean stuck --cluster cluster1 --force-remove-finalizers
+
+# Remove the finalizers set by other controllers too
+%[1]s clean stuck --cluster cluster1 --force-remove-finalizers --remove-unknown-finalizers
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "stuck",
+		Short: "explain and remove the finalizers blocking the deletion of a cluster",
+		Long: "find the managed cluster, the manifestworks, the addons and the namespace of a cluster which are stuck in " +
+			"Terminating, explain which finalizer blocks each of them and, with --force-remove-finalizers, remove the finalizers. " +
+			"The finalizers are removed by the agents once they have cleaned up the managed cluster, removing them " +
+			"leaves the resources of the cluster behind.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "The name of the managed cluster")
+	cmd.Flags().BoolVar(&o.forceRemoveFinalizers, "force-remove-finalizers", false,
+		"Remove the finalizers blocking the deletion, the resources they protect are left on the managed cluster. "+
+			"The finalizers of the agents are kept while the cluster is available")
+	cmd.Flags().BoolVar(&o.removeUnknownFinalizers, "remove-unknown-finalizers", false,
+		"Remove the finalizers set by other controllers than open-cluster-management too, their cleanup is skipped")
+	return cmd
+}
@@ -0,0 +1,362 @@
+// Copyright Contributors to the Open Cluster Management project
+package stuck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	kindManifestWork        = "ManifestWork"
+	kindManagedClusterAddOn = "ManagedClusterAddOn"
+	kindManagedCluster      = "ManagedCluster"
+	kindNamespace           = "Namespace"
+)
+
+// finalizer is a known finalizer set on the resources of a cluster
+type finalizer struct {
+	// removedBy explains what the finalizer waits for
+	removedBy string
+	// byAgent is true if the finalizer is removed by an agent of the managed cluster
+	byAgent bool
+	// leftBehind is what is left if the finalizer is removed by force
+	leftBehind string
+}
+
+var knownFinalizers = map[string]finalizer{
+	"cluster.open-cluster-management.io/manifest-work-cleanup": {
+		removedBy:  "removed by the work agent once the resources of the manifestwork are deleted from the managed cluster",
+		byAgent:    true,
+		leftBehind: "the resources applied by the manifestwork are left on the managed cluster",
+	},
+	"cluster.open-cluster-management.io/api-resource-cleanup": {
+		removedBy:  "removed by the registration controller of the hub once the manifestworks and the roles of the cluster are deleted",
+		leftBehind: "the roles and the bindings of the cluster may be left on the hub",
+	},
+	"addon.open-cluster-management.io/addon-pre-delete": {
+		removedBy:  "removed by the addon manager once the pre-delete job of the addon has completed on the managed cluster",
+		byAgent:    true,
+		leftBehind: "the pre-delete job of the addon is not run and the addon agent may be left on the managed cluster",
+	},
+}
+
+// stuck is a finalizer blocking the deletion of a resource
+type stuck struct {
+	kind      string
+	namespace string
+	name      string
+	finalizer string
+	reason    string
+	// removable is false for the finalizers only removed by the namespace controller
+	removable bool
+}
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("clean stuck options:", "dry-run", o.ClusteradmFlags.DryRun, "cluster", o.cluster,
+		"force-remove-finalizers", o.forceRemoveFinalizers, "remove-unknown-finalizers", o.removeUnknownFinalizers)
+	return nil
+}
+
+func (o *Options) validate() error {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if len(o.cluster) == 0 {
+		return fmt.Errorf("the name of the managed cluster must be specified in --cluster")
+	}
+	if o.removeUnknownFinalizers && !o.forceRemoveFinalizers {
+		return fmt.Errorf("--remove-unknown-finalizers is only used with --force-remove-finalizers")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	addonClient, err := addonclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(ctx, o.cluster, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		cluster = nil
+	case err != nil:
+		return err
+	}
+	works, err := workClient.WorkV1().ManifestWorks(o.cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	addons, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(o.cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	namespace, err := kubeClient.CoreV1().Namespaces().Get(ctx, o.cluster, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		namespace = nil
+	case err != nil:
+		return err
+	}
+
+	found := findStuck(o.cluster, cluster, works.Items, addons.Items, namespace)
+	if len(found) == 0 {
+		fmt.Fprintf(o.Streams.Out, "No resource of cluster %s is stuck in Terminating\n", o.cluster)
+		return nil
+	}
+	w := tabwriter.NewWriter(o.Streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tFINALIZER\tBLOCKED BY")
+	for _, s := range found {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.kind, s.namespace, s.name, s.finalizer, s.reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if !o.forceRemoveFinalizers || o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "\nRun with --force-remove-finalizers to remove the finalizers, "+
+			"only if the managed cluster is gone or its agents can not be recovered\n")
+		return nil
+	}
+
+	available := cluster != nil && meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	removals, refusals := selectFinalizers(found, available, o.removeUnknownFinalizers)
+	if len(refusals) > 0 {
+		fmt.Fprintf(o.Streams.ErrOut, "\nThe following finalizers are kept:\n")
+		for _, r := range refusals {
+			fmt.Fprintf(o.Streams.ErrOut, "  %s %s %s: %s\n", r.kind, qualifiedName(r.stuck), r.finalizer, r.why)
+		}
+	}
+	if len(removals) > 0 {
+		fmt.Fprintf(o.Streams.ErrOut, "\nWARNING: the finalizers are removed without the cleanup they wait for:\n")
+		for _, r := range removals {
+			for _, f := range r.remove {
+				leftBehind := "the cleanup of the controller which set it is skipped"
+				if known, ok := knownFinalizers[f]; ok {
+					leftBehind = known.leftBehind
+				}
+				fmt.Fprintf(o.Streams.ErrOut, "  %s %s %s: %s\n", r.kind, qualifiedName(r.stuck), f, leftBehind)
+			}
+		}
+	}
+	fmt.Fprintln(o.Streams.Out)
+	for _, s := range found {
+		if !s.removable {
+			fmt.Fprintf(o.Streams.Out, "%s %s is deleted by the namespace controller once the resources above are removed\n",
+				s.kind, qualifiedName(s))
+		}
+	}
+	var failed int
+	for _, r := range removals {
+		err := removeFinalizers(kubeClient, clusterClient, workClient, addonClient, r)
+		if err != nil && !errors.IsNotFound(err) {
+			failed++
+			fmt.Fprintf(o.Streams.ErrOut, "failed to remove the finalizers of %s %s: %v\n", r.kind, qualifiedName(r.stuck), err)
+			continue
+		}
+		fmt.Fprintf(o.Streams.Out, "The finalizers %s of %s %s are removed\n", strings.Join(r.remove, ", "), r.kind, qualifiedName(r.stuck))
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove the finalizers of %d resources", failed)
+	}
+	return nil
+}
+
+// removal is the finalizers removed from a resource, its other finalizers are kept
+type removal struct {
+	stuck
+	remove []string
+	keep   []string
+}
+
+// refusal is a finalizer which is not removed by force
+type refusal struct {
+	stuck
+	why string
+}
+
+// selectFinalizers returns the finalizers removed by force, grouped by resource in the order they are found, and the
+// ones which are kept: the finalizers of the agents while the cluster is available, as the agents are still cleaning up
+// the managed cluster, and the unknown finalizers unless removeUnknown is set.
+func selectFinalizers(found []stuck, available, removeUnknown bool) ([]removal, []refusal) {
+	var removals []removal
+	var refusals []refusal
+	index := map[string]int{}
+	for _, s := range found {
+		if !s.removable {
+			continue
+		}
+		key := s.kind + "/" + qualifiedName(s)
+		i, ok := index[key]
+		if !ok {
+			i = len(removals)
+			index[key] = i
+			removals = append(removals, removal{stuck: s})
+		}
+		known, ok := knownFinalizers[s.finalizer]
+		switch {
+		case !ok && !removeUnknown:
+			refusals = append(refusals, refusal{s, "unknown finalizer, set --remove-unknown-finalizers to remove it"})
+			removals[i].keep = append(removals[i].keep, s.finalizer)
+		case ok && known.byAgent && available:
+			refusals = append(refusals, refusal{s, "the agent of the cluster is available and still removes it"})
+			removals[i].keep = append(removals[i].keep, s.finalizer)
+		default:
+			removals[i].remove = append(removals[i].remove, s.finalizer)
+		}
+	}
+	// the resources without any finalizer to remove are not patched
+	selected := removals[:0]
+	for _, r := range removals {
+		if len(r.remove) > 0 {
+			selected = append(selected, r)
+		}
+	}
+	return selected, refusals
+}
+
+// findStuck returns the finalizers of the resources of the cluster being deleted, the manifestworks and the addons
+// first as they block the deletion of the cluster and of its namespace. The cluster and the namespace may be nil.
+func findStuck(name string, cluster *clusterv1.ManagedCluster, works []workapiv1.ManifestWork,
+	addons []addonv1alpha1.ManagedClusterAddOn, namespace *corev1.Namespace) []stuck {
+	// the finalizers removed by the agents are stuck as long as the agents are not available
+	agent := ""
+	switch {
+	case cluster == nil:
+		agent = fmt.Sprintf("managed cluster %s is not found", name)
+	case !meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable):
+		agent = fmt.Sprintf("the agent of cluster %s is not available", name)
+	}
+	explain := func(f string) string {
+		known, ok := knownFinalizers[f]
+		if !ok {
+			return "unknown finalizer, removed by the controller which set it"
+		}
+		if known.byAgent && len(agent) > 0 {
+			return fmt.Sprintf("%s, %s", known.removedBy, agent)
+		}
+		return known.removedBy
+	}
+
+	var found []stuck
+	for _, work := range works {
+		if work.DeletionTimestamp == nil {
+			continue
+		}
+		for _, f := range work.Finalizers {
+			found = append(found, stuck{kindManifestWork, work.Namespace, work.Name, f, explain(f), true})
+		}
+	}
+	for _, addon := range addons {
+		if addon.DeletionTimestamp == nil {
+			continue
+		}
+		for _, f := range addon.Finalizers {
+			found = append(found, stuck{kindManagedClusterAddOn, addon.Namespace, addon.Name, f, explain(f), true})
+		}
+	}
+	if cluster != nil && cluster.DeletionTimestamp != nil {
+		for _, f := range cluster.Finalizers {
+			found = append(found, stuck{kindManagedCluster, "", cluster.Name, f, explain(f), true})
+		}
+	}
+	if namespace != nil && namespace.DeletionTimestamp != nil {
+		for _, f := range namespace.Finalizers {
+			found = append(found, stuck{kindNamespace, "", namespace.Name, f, explain(f), true})
+		}
+		for _, f := range namespace.Spec.Finalizers {
+			found = append(found, stuck{kindNamespace, "", namespace.Name, string(f), namespaceRemaining(namespace), false})
+		}
+	}
+	return found
+}
+
+// namespaceRemaining returns the messages of the conditions set by the namespace controller on what is left
+func namespaceRemaining(namespace *corev1.Namespace) string {
+	var messages []string
+	for _, condition := range namespace.Status.Conditions {
+		switch condition.Type {
+		case corev1.NamespaceContentRemaining, corev1.NamespaceFinalizersRemaining, corev1.NamespaceDeletionContentFailure:
+			if condition.Status == corev1.ConditionTrue {
+				messages = append(messages, condition.Message)
+			}
+		}
+	}
+	if len(messages) == 0 {
+		return "removed by the namespace controller once the namespace is empty"
+	}
+	return strings.Join(messages, "; ")
+}
+
+func removeFinalizers(kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface, workClient workclientset.Interface,
+	addonClient addonclientset.Interface, s removal) error {
+	ctx := context.TODO()
+	patch, err := finalizersPatch(s.keep)
+	if err != nil {
+		return err
+	}
+	switch s.kind {
+	case kindManifestWork:
+		_, err = workClient.WorkV1().ManifestWorks(s.namespace).Patch(ctx, s.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case kindManagedClusterAddOn:
+		_, err = addonClient.AddonV1alpha1().ManagedClusterAddOns(s.namespace).Patch(ctx, s.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case kindManagedCluster:
+		_, err = clusterClient.ClusterV1().ManagedClusters().Patch(ctx, s.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case kindNamespace:
+		_, err = kubeClient.CoreV1().Namespaces().Patch(ctx, s.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	default:
+		err = fmt.Errorf("unknown kind %s", s.kind)
+	}
+	return err
+}
+
+// finalizersPatch returns the merge patch setting the finalizers to the kept ones
+func finalizersPatch(keep []string) ([]byte, error) {
+	var finalizers interface{}
+	if len(keep) > 0 {
+		finalizers = keep
+	}
+	return json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"finalizers": finalizers}})
+}
+
+func qualifiedName(s stuck) string {
+	if len(s.namespace) == 0 {
+		return s.name
+	}
+	return s.namespace + "/" + s.name
+}
@@ -0,0 +1,154 @@
+// Copyright Contributors to the Open Cluster Management project
+package stuck
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	workFinalizer    = "cluster.open-cluster-management.io/manifest-work-cleanup"
+	clusterFinalizer = "cluster.open-cluster-management.io/api-resource-cleanup"
+)
+
+func TestFindStuck(t *testing.T) {
+	deleted := metav1.Now()
+	terminating := func(name string, finalizers ...string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "cluster1", DeletionTimestamp: &deleted, Finalizers: finalizers}
+	}
+	works := []workapiv1.ManifestWork{
+		{ObjectMeta: terminating("work1", workFinalizer)},
+		{ObjectMeta: metav1.ObjectMeta{Name: "work2", Namespace: "cluster1", Finalizers: []string{workFinalizer}}},
+	}
+	addons := []addonv1alpha1.ManagedClusterAddOn{{ObjectMeta: terminating("addon1", "example.com/cleanup")}}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", DeletionTimestamp: &deleted},
+		Spec:       corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes}},
+		Status: corev1.NamespaceStatus{Conditions: []corev1.NamespaceCondition{
+			{Type: corev1.NamespaceContentRemaining, Status: corev1.ConditionTrue, Message: "Some resources are remaining: manifestworks.work.open-cluster-management.io has 1 resource instances"},
+			{Type: corev1.NamespaceDeletionDiscoveryFailure, Status: corev1.ConditionFalse, Message: "All resources successfully discovered"},
+		}},
+	}
+	cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", DeletionTimestamp: &deleted, Finalizers: []string{clusterFinalizer}}}
+
+	cases := []struct {
+		name     string
+		cluster  *clusterv1.ManagedCluster
+		expected []stuck
+	}{
+		{
+			name:    "agent not available",
+			cluster: cluster,
+			expected: []stuck{
+				{kindManifestWork, "cluster1", "work1", workFinalizer, knownFinalizers[workFinalizer].removedBy + ", the agent of cluster cluster1 is not available", true},
+				{kindManagedClusterAddOn, "cluster1", "addon1", "example.com/cleanup", "unknown finalizer, removed by the controller which set it", true},
+				{kindManagedCluster, "", "cluster1", clusterFinalizer, knownFinalizers[clusterFinalizer].removedBy, true},
+				{kindNamespace, "", "cluster1", "kubernetes", "Some resources are remaining: manifestworks.work.open-cluster-management.io has 1 resource instances", false},
+			},
+		},
+		{
+			name: "cluster not found",
+			expected: []stuck{
+				{kindManifestWork, "cluster1", "work1", workFinalizer, knownFinalizers[workFinalizer].removedBy + ", managed cluster cluster1 is not found", true},
+				{kindManagedClusterAddOn, "cluster1", "addon1", "example.com/cleanup", "unknown finalizer, removed by the controller which set it", true},
+				{kindNamespace, "", "cluster1", "kubernetes", "Some resources are remaining: manifestworks.work.open-cluster-management.io has 1 resource instances", false},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			found := findStuck("cluster1", c.cluster, works, addons, namespace)
+			if !reflect.DeepEqual(found, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, found)
+			}
+		})
+	}
+}
+
+func TestNamespaceRemaining(t *testing.T) {
+	namespace := &corev1.Namespace{}
+	if reason := namespaceRemaining(namespace); reason != "removed by the namespace controller once the namespace is empty" {
+		t.Errorf("unexpected reason %q", reason)
+	}
+}
+
+func TestSelectFinalizers(t *testing.T) {
+	found := []stuck{
+		{kindManifestWork, "cluster1", "work1", workFinalizer, "", true},
+		{kindManagedClusterAddOn, "cluster1", "addon1", "example.com/cleanup", "", true},
+		{kindManagedCluster, "", "cluster1", clusterFinalizer, "", true},
+		{kindManagedCluster, "", "cluster1", "example.com/cleanup", "", true},
+		{kindNamespace, "", "cluster1", "kubernetes", "", false},
+	}
+	cases := []struct {
+		name          string
+		available     bool
+		removeUnknown bool
+		removed       map[string][]string
+		kept          map[string][]string
+	}{
+		{
+			name:    "agent not available",
+			removed: map[string][]string{"ManifestWork/cluster1/work1": {workFinalizer}, "ManagedCluster/cluster1": {clusterFinalizer}},
+			kept:    map[string][]string{"ManagedClusterAddOn/cluster1/addon1": {"example.com/cleanup"}, "ManagedCluster/cluster1": {"example.com/cleanup"}},
+		},
+		{
+			name:      "agent available",
+			available: true,
+			removed:   map[string][]string{"ManagedCluster/cluster1": {clusterFinalizer}},
+			kept: map[string][]string{"ManifestWork/cluster1/work1": {workFinalizer}, "ManagedClusterAddOn/cluster1/addon1": {"example.com/cleanup"},
+				"ManagedCluster/cluster1": {"example.com/cleanup"}},
+		},
+		{
+			name:          "remove unknown",
+			removeUnknown: true,
+			removed: map[string][]string{"ManifestWork/cluster1/work1": {workFinalizer}, "ManagedClusterAddOn/cluster1/addon1": {"example.com/cleanup"},
+				"ManagedCluster/cluster1": {clusterFinalizer, "example.com/cleanup"}},
+			kept: map[string][]string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			removals, refusals := selectFinalizers(found, c.available, c.removeUnknown)
+			removed, kept := map[string][]string{}, map[string][]string{}
+			for _, r := range removals {
+				removed[r.kind+"/"+qualifiedName(r.stuck)] = r.remove
+			}
+			for _, r := range refusals {
+				key := r.kind + "/" + qualifiedName(r.stuck)
+				kept[key] = append(kept[key], r.finalizer)
+			}
+			if !reflect.DeepEqual(removed, c.removed) {
+				t.Errorf("expected the removed finalizers %v, got %v", c.removed, removed)
+			}
+			if !reflect.DeepEqual(kept, c.kept) {
+				t.Errorf("expected the kept finalizers %v, got %v", c.kept, kept)
+			}
+		})
+	}
+}
+
+func TestFinalizersPatch(t *testing.T) {
+	for keep, expected := range map[string]string{
+		"":                    `{"metadata":{"finalizers":null}}`,
+		"example.com/cleanup": `{"metadata":{"finalizers":["example.com/cleanup"]}}`,
+	} {
+		var finalizers []string
+		if len(keep) > 0 {
+			finalizers = []string{keep}
+		}
+		patch, err := finalizersPatch(finalizers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(patch) != expected {
+			t.Errorf("expected %s, got %s", expected, patch)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright Contributors to the Open Cluster Management project
+package stuck
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The name of the managed cluster
+	cluster string
+	//Remove the finalizers of the resources stuck in Terminating
+	forceRemoveFinalizers bool
+	//Remove the finalizers set by other controllers too
+	removeUnknownFinalizers bool
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/cluster/synclabels"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the commands managing the managed clusters
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "managed cluster options",
+		Long:  "there is 1 managed cluster option: sync-labels",
+	}
+
+	cmd.AddCommand(synclabels.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
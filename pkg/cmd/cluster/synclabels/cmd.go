@@ -0,0 +1,57 @@
+// Copyright Contributors to the Open Cluster Management project
+package synclabels
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Copy the region, platform and version claims of all the managed clusters into their labels
+%[1]s cluster sync-labels --from-claims region,platform,version
+# Copy the claims of some managed clusters
+%[1]s cluster sync-labels --from-claims id.k8s.io,platform --clusters cluster1,cluster2
+# Keep the labels in sync with the claims until interrupted
+%[1]s cluster sync-labels --from-claims region,platform --watch
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "sync-labels",
+		Short: "copy the claims of the managed clusters into their labels",
+		Long: "copy the values of the ClusterClaims reported by the managed clusters into labels of the same name, " +
+			"so the Placements can select the clusters by their claims. A claim name without domain is one of " +
+			"open-cluster-management.io, e.g. platform is platform.open-cluster-management.io. " +
+			"The labels of the claims no longer reported are kept.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			helpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(args); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run(c.Context())
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.claims, "from-claims", []string{}, "Names of the claims (comma separated) copied into the labels")
+	cmd.Flags().StringSliceVar(&o.clusters, "clusters", []string{}, "Names of the managed clusters (comma separated), defaults to all the managed clusters")
+	cmd.Flags().BoolVar(&o.watch, "watch", false, "If set the labels are kept in sync with the claims until interrupted")
+
+	return cmd
+}
@@ -0,0 +1,204 @@
+// Copyright Contributors to the Open Cluster Management project
+package synclabels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// defaultClaimDomain completes the names of the claims without domain
+const defaultClaimDomain = "open-cluster-management.io"
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("cluster sync-labels options:", "dry-run", o.ClusteradmFlags.DryRun, "from-claims", o.claims,
+		"clusters", o.clusters, "watch", o.watch)
+
+	o.claims = claimNames(o.claims)
+	return nil
+}
+
+func (o *Options) validate(args []string) (err error) {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return fmt.Errorf("there should be no argument")
+	}
+	if len(o.claims) == 0 {
+		return fmt.Errorf("the claims must be specified in --from-claims")
+	}
+	for _, claim := range o.claims {
+		if errs := validation.IsQualifiedName(claim); len(errs) > 0 {
+			return fmt.Errorf("the claim %s can not be a label name: %s", claim, strings.Join(errs, ", "))
+		}
+	}
+	if o.watch && o.ClusteradmFlags.DryRun {
+		return fmt.Errorf("--watch can not be set with --dry-run")
+	}
+	return nil
+}
+
+func (o *Options) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	clusterList, err := clusterClient.ClusterV1().ManagedClusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	wanted := sets.NewString(o.clusters...)
+	found := sets.NewString()
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if wanted.Len() > 0 && !wanted.Has(cluster.Name) {
+			continue
+		}
+		found.Insert(cluster.Name)
+		if err := o.sync(ctx, clusterClient, cluster); err != nil {
+			return err
+		}
+	}
+	if missing := wanted.Difference(found); missing.Len() > 0 {
+		return fmt.Errorf("the managed clusters %s are not found", strings.Join(missing.List(), ", "))
+	}
+	if !o.watch {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	watcher, err := watchtools.NewRetryWatcher(clusterList.ResourceVersion, &cache.ListWatch{
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return clusterClient.ClusterV1().ManagedClusters().Watch(ctx, options)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	fmt.Fprintf(o.Streams.Out, "Watching the claims of the managed clusters, press Ctrl+C to stop\n")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("the watch of the managed clusters is closed")
+			}
+			cluster, ok := event.Object.(*clusterapiv1.ManagedCluster)
+			if !ok || (event.Type != watch.Added && event.Type != watch.Modified) {
+				continue
+			}
+			if wanted.Len() > 0 && !wanted.Has(cluster.Name) {
+				continue
+			}
+			if err := o.sync(ctx, clusterClient, cluster); err != nil {
+				// the cluster is synced again on its next change
+				fmt.Fprintf(o.Streams.ErrOut, "failed to sync the labels of cluster %s: %v\n", cluster.Name, err)
+			}
+		}
+	}
+}
+
+// sync patches the labels of the cluster which differ from its claims
+func (o *Options) sync(ctx context.Context, clusterClient clusterclientset.Interface, cluster *clusterapiv1.ManagedCluster) error {
+	labels, skipped := labelsToSync(cluster, o.claims)
+	for _, reason := range skipped {
+		// the clusters are synced on each change with --watch, the warnings are printed once
+		if !o.warned.Has(reason) {
+			o.warned.Insert(reason)
+			fmt.Fprintf(o.Streams.ErrOut, "WARNING: %s\n", reason)
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	keys := []string{}
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	changes := []string{}
+	for _, key := range keys {
+		changes = append(changes, fmt.Sprintf("%s=%s", key, labels[key]))
+	}
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "Cluster %s would get the labels %s\n", cluster.Name, strings.Join(changes, ","))
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+	if err != nil {
+		return err
+	}
+	_, err = clusterClient.ClusterV1().ManagedClusters().Patch(ctx, cluster.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "Cluster %s labeled %s\n", cluster.Name, strings.Join(changes, ","))
+	return nil
+}
+
+// claimNames completes the names of the claims without domain with the default domain
+func claimNames(claims []string) []string {
+	names := []string{}
+	for _, claim := range claims {
+		claim = strings.TrimSpace(claim)
+		if len(claim) == 0 {
+			continue
+		}
+		if !strings.Contains(claim, ".") {
+			claim = claim + "." + defaultClaimDomain
+		}
+		names = append(names, claim)
+	}
+	return names
+}
+
+// labelsToSync returns the labels of the claims which are missing or differ on the cluster, and why the
+// claims which can not be labels are skipped
+func labelsToSync(cluster *clusterapiv1.ManagedCluster, claims []string) (labels map[string]string, skipped []string) {
+	labels = map[string]string{}
+	wanted := sets.NewString(claims...)
+	for _, claim := range cluster.Status.ClusterClaims {
+		if !wanted.Has(claim.Name) {
+			continue
+		}
+		if errs := validation.IsValidLabelValue(claim.Value); len(errs) > 0 {
+			skipped = append(skipped, fmt.Sprintf("the claim %s=%s of cluster %s can not be a label value: %s",
+				claim.Name, claim.Value, cluster.Name, strings.Join(errs, ", ")))
+			continue
+		}
+		if value, ok := cluster.Labels[claim.Name]; ok && value == claim.Value {
+			continue
+		}
+		labels[claim.Name] = claim.Value
+	}
+	return labels, skipped
+}
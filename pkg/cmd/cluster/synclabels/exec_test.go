@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+package synclabels
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestClaimNames(t *testing.T) {
+	names := claimNames([]string{"region", " platform", "id.k8s.io", ""})
+	expected := []string{"region.open-cluster-management.io", "platform.open-cluster-management.io", "id.k8s.io"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestLabelsToSync(t *testing.T) {
+	cluster := &clusterapiv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+			Labels: map[string]string{
+				"platform.open-cluster-management.io": "AWS",
+				"region.open-cluster-management.io":   "us-west-1",
+			},
+		},
+		Status: clusterapiv1.ManagedClusterStatus{
+			ClusterClaims: []clusterapiv1.ManagedClusterClaim{
+				{Name: "platform.open-cluster-management.io", Value: "AWS"},
+				{Name: "region.open-cluster-management.io", Value: "us-east-1"},
+				{Name: "version.open-cluster-management.io", Value: "2.6.1"},
+				{Name: "name.open-cluster-management.io", Value: "my cluster"},
+				{Name: "id.k8s.io", Value: "abc"},
+			},
+		},
+	}
+	labels, skipped := labelsToSync(cluster, []string{
+		"platform.open-cluster-management.io",
+		"region.open-cluster-management.io",
+		"version.open-cluster-management.io",
+		"name.open-cluster-management.io",
+		"product.open-cluster-management.io",
+	})
+	expected := map[string]string{
+		"region.open-cluster-management.io":  "us-east-1",
+		"version.open-cluster-management.io": "2.6.1",
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("expected the labels %v, got %v", expected, labels)
+	}
+	if len(skipped) != 1 {
+		t.Errorf("expected the claim with spaces to be skipped, got %v", skipped)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright Contributors to the Open Cluster Management project
+package synclabels
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//claims: the names of the claims copied into the labels, completed with the default domain
+	claims []string
+	//clusters: the names of the managed clusters, all the managed clusters if empty
+	clusters []string
+	//watch: keep the labels in sync until interrupted
+	watch bool
+	//warned are the warnings already printed
+	warned sets.String
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+		warned:          sets.NewString(),
+	}
+}
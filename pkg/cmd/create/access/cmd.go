@@ -0,0 +1,62 @@
+// Copyright Contributors to the Open Cluster Management project
+package access
+
+import (
+	"fmt"
+	"strings"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterset"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Allow a user to manage a clusterset and to add clusters to it
+%[1]s create access --clusterset clusterset1 --user u1 --role admin
+# Allow a group to bind a clusterset to its namespaces
+%[1]s create access --clusterset clusterset1 --group team1 --role bind
+# Allow a service account to read a clusterset
+%[1]s create access --clusterset clusterset1 --serviceaccount ns1/sa1 --role view
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "access",
+		Short:        "grant a role on a clusterset",
+		Long:         "create the cluster role and cluster role binding granting the admin, bind or view role on a clusterset to users, groups or service accounts",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.clusterSet, "clusterset", "", "The name of the clusterset")
+	cmd.Flags().StringVar(&o.role, "role", clusterset.RoleView, fmt.Sprintf("The role granted on the clusterset, one of %s", strings.Join(clusterset.Roles, ", ")))
+	cmd.Flags().StringSliceVar(&o.users, "user", []string{}, "Names of the users granted the role (comma separated)")
+	cmd.Flags().StringSliceVar(&o.groups, "group", []string{}, "Names of the groups granted the role (comma separated)")
+	cmd.Flags().StringSliceVar(&o.serviceAccounts, "serviceaccount", []string{}, "Service accounts granted the role (comma separated), in the format of namespace/name")
+
+	return cmd
+}
@@ -0,0 +1,107 @@
+// Copyright Contributors to the Open Cluster Management project
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterset"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("create access options:", "clusterset", o.clusterSet, "role", o.role, "users", o.users, "groups", o.groups, "serviceaccounts", o.serviceAccounts)
+
+	for _, subjects := range []struct {
+		kind  string
+		names []string
+	}{
+		{kind: rbacv1.UserKind, names: o.users},
+		{kind: rbacv1.GroupKind, names: o.groups},
+		{kind: rbacv1.ServiceAccountKind, names: o.serviceAccounts},
+	} {
+		for _, name := range subjects.names {
+			subject, err := clusterset.Subject(subjects.kind, name)
+			if err != nil {
+				return err
+			}
+			o.subjects = append(o.subjects, subject)
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(o.clusterSet) == 0 {
+		return fmt.Errorf("the name of the clusterset must be specified with --clusterset")
+	}
+	if _, err := clusterset.ClusterRole(o.clusterSet, o.role); err != nil {
+		return err
+	}
+	if len(o.subjects) == 0 {
+		return fmt.Errorf("at least one of --user, --group or --serviceaccount must be specified")
+	}
+
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	kubeClient, _, _, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	return o.runWithClient(kubeClient, clusterClient)
+}
+
+func (o *Options) runWithClient(kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface) error {
+	if _, err := clusterClient.ClusterV1beta1().ManagedClusterSets().Get(context.TODO(), o.clusterSet, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("failed to get clusterset %s: %v", o.clusterSet, err)
+	}
+
+	for _, subject := range o.subjects {
+		if o.ClusteradmFlags.DryRun {
+			fmt.Fprintf(o.Streams.Out, "%s %s would be granted the %s role on clusterset %s\n", subject.Kind, subjectName(subject), o.role, o.clusterSet)
+			continue
+		}
+		granted, err := clusterset.Grant(context.TODO(), kubeClient, o.clusterSet, o.role, subject)
+		if err != nil {
+			return err
+		}
+		if !granted {
+			fmt.Fprintf(o.Streams.Out, "%s %s is already granted the %s role on clusterset %s\n", subject.Kind, subjectName(subject), o.role, o.clusterSet)
+			continue
+		}
+		fmt.Fprintf(o.Streams.Out, "%s %s is granted the %s role on clusterset %s by cluster role binding %s\n",
+			subject.Kind, subjectName(subject), o.role, o.clusterSet, clusterset.ClusterRoleName(o.clusterSet, o.role))
+	}
+
+	return nil
+}
+
+func subjectName(subject rbacv1.Subject) string {
+	if len(subject.Namespace) > 0 {
+		return subject.Namespace + "/" + subject.Name
+	}
+	return subject.Name
+}
@@ -0,0 +1,33 @@
+// Copyright Contributors to the Open Cluster Management project
+package access
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The clusterset the role is granted on
+	clusterSet string
+	//The role granted on the clusterset
+	role string
+	//The users, groups and service accounts granted the role
+	users           []string
+	groups          []string
+	serviceAccounts []string
+
+	//The subjects built from the users, groups and service accounts
+	subjects []rbacv1.Subject
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Provision a cluster with the Cluster API, then join it to the hub and accept it
+%[1]s create cluster --provider capi --template capi-template.yaml --join
+
+# Provision a cluster in a namespace and register it under another name
+%[1]s create cluster --provider capi --template capi-template.yaml -n capi-clusters --join --cluster-name cluster1
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "provision a cluster",
+		Long: "create the resources of the template on the hub to provision a cluster with the Cluster API and wait for it to be ready. " +
+			"With --join the cluster is joined to the hub with its kubeconfig and accepted.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.provider, "provider", providerCAPI, "The provider provisioning the cluster, only capi is supported")
+	cmd.Flags().StringVar(&o.template, "template", "", "The file of the resources provisioning the cluster, it contains one Cluster of the Cluster API")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the resources without one, the namespace of the kubeconfig if not set")
+	cmd.Flags().BoolVar(&o.join, "join", false, "Join the cluster to the hub and accept it once it is ready")
+	cmd.Flags().StringVar(&o.clusterName, "cluster-name", "", "The name of the cluster on the hub, the name of the Cluster of the template if not set")
+	cmd.Flags().StringVar(&o.bundleVersion, "bundle-version", "default", "version of predefined compatible image versions used by the join")
+	cmd.Flags().StringVar(&o.registry, "image-registry", "quay.io/open-cluster-management", "The name of the image registry serving OCM images used by the join")
+
+	return cmd
+}
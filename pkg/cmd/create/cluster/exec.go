@@ -0,0 +1,301 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+)
+
+const (
+	providerCAPI = "capi"
+	// capiGroup is the api group of the Cluster of the Cluster API
+	capiGroup = "cluster.x-k8s.io"
+	// the key of the kubeconfig in the <cluster>-kubeconfig secret generated by the Cluster API
+	kubeconfigSecretKey = "value"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("create cluster options:", "dry-run", o.ClusteradmFlags.DryRun, "provider", o.provider, "template", o.template,
+		"namespace", o.namespace, "join", o.join, "cluster-name", o.clusterName)
+
+	if len(o.namespace) == 0 {
+		o.namespace, _, err = o.ClusteradmFlags.KubectlFactory.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if o.provider != providerCAPI {
+		return fmt.Errorf("unsupported provider %q, only %s is supported", o.provider, providerCAPI)
+	}
+	if len(o.template) == 0 {
+		return fmt.Errorf("the template must be specified in --template")
+	}
+	if _, err := os.Stat(o.template); err != nil {
+		return fmt.Errorf("failed to read the template: %v", err)
+	}
+
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	result := o.ClusteradmFlags.KubectlFactory.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.namespace).DefaultNamespace().
+		FilenameParam(false, &resource.FilenameOptions{Filenames: []string{o.template}}).
+		Flatten().
+		Do()
+	infos, err := result.Infos()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := capiCluster(infos)
+	if err != nil {
+		return err
+	}
+	if len(o.clusterName) == 0 {
+		o.clusterName = cluster.Name
+	}
+
+	for _, info := range infos {
+		if o.ClusteradmFlags.DryRun {
+			fmt.Fprintf(o.Streams.Out, "%s %s/%s is created\n", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name)
+			continue
+		}
+		_, err := resource.NewHelper(info.Client, info.Mapping).Create(info.Namespace, true, info.Object)
+		switch {
+		case errors.IsAlreadyExists(err):
+			fmt.Fprintf(o.Streams.Out, "%s %s/%s already exists\n", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name)
+		case err != nil:
+			return fmt.Errorf("failed to create %s %s/%s: %v", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name, err)
+		default:
+			fmt.Fprintf(o.Streams.Out, "%s %s/%s is created\n", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name)
+		}
+	}
+
+	if !o.ClusteradmFlags.DryRun {
+		if err := o.waitForCluster(cluster); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "Cluster %s/%s is ready, its kubeconfig is in the secret %s-kubeconfig\n", cluster.Namespace, cluster.Name, cluster.Name)
+	}
+
+	if !o.join {
+		return nil
+	}
+
+	return o.joinCluster(cluster)
+}
+
+// capiCluster returns the Cluster of the Cluster API of the template, there must be only one
+func capiCluster(infos []*resource.Info) (*resource.Info, error) {
+	var cluster *resource.Info
+	for _, info := range infos {
+		gvk := info.Mapping.GroupVersionKind
+		if gvk.Group != capiGroup || gvk.Kind != "Cluster" {
+			continue
+		}
+		if cluster != nil {
+			return nil, fmt.Errorf("the template contains more than one Cluster: %s and %s", cluster.Name, info.Name)
+		}
+		cluster = info
+	}
+	if cluster == nil {
+		return nil, fmt.Errorf("the template contains no Cluster of %s, is it a Cluster API template?", capiGroup)
+	}
+	return cluster, nil
+}
+
+// waitForCluster waits for the control plane of the cluster to be ready
+func (o *Options) waitForCluster(cluster *resource.Info) error {
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Streams.Out, "Waiting for cluster %s/%s to be provisioned...\n", cluster.Namespace, cluster.Name)
+	return wait.PollImmediate(5*time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		obj, err := dynamicClient.Resource(cluster.Mapping.Resource).Namespace(cluster.Namespace).Get(context.TODO(), cluster.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return isClusterReady(obj)
+	})
+}
+
+// isClusterReady returns true if the control plane of the cluster is ready, and an error
+// if the provisioning failed.
+func isClusterReady(cluster *unstructured.Unstructured) (bool, error) {
+	phase, _, _ := unstructured.NestedString(cluster.Object, "status", "phase")
+	if phase == "Failed" {
+		message, _, _ := unstructured.NestedString(cluster.Object, "status", "failureMessage")
+		return false, fmt.Errorf("cluster %s/%s failed to be provisioned: %s", cluster.GetNamespace(), cluster.GetName(), message)
+	}
+
+	controlPlaneReady, _, _ := unstructured.NestedBool(cluster.Object, "status", "controlPlaneReady")
+	if !controlPlaneReady {
+		return false, nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(cluster.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		return condition["status"] == string(metav1.ConditionTrue), nil
+	}
+	return phase == "Provisioned", nil
+}
+
+// joinCluster joins the cluster to the hub with its kubeconfig and accepts it, the hub
+// and the cluster are passed to the commands in temporary kubeconfig files.
+func (o *Options) joinCluster(cluster *resource.Info) error {
+	dir, err := os.MkdirTemp("", "clusteradm-create-cluster-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	hubKubeconfig := filepath.Join(dir, "hub.kubeconfig")
+	clusterKubeconfig := filepath.Join(dir, fmt.Sprintf("%s.kubeconfig", cluster.Name))
+	if !o.ClusteradmFlags.DryRun {
+		if err := o.writeHubKubeconfig(hubKubeconfig); err != nil {
+			return err
+		}
+		if err := o.writeClusterKubeconfig(cluster, clusterKubeconfig); err != nil {
+			return err
+		}
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	hubInfo := clusteradmjson.HubInfo{
+		HubToken:     "<hub_token>",
+		HubApiserver: "<hub_apiserver>",
+	}
+	tokenOutput := &bytes.Buffer{}
+	if err := o.execute(self, []string{
+		"get", "token",
+		"--kubeconfig", hubKubeconfig,
+		"--output", "json",
+	}, tokenOutput); err != nil {
+		return fmt.Errorf("failed to get the token of the hub: %v", err)
+	}
+	if !o.ClusteradmFlags.DryRun {
+		if err := json.Unmarshal(tokenOutput.Bytes(), &hubInfo); err != nil {
+			return fmt.Errorf("failed to parse the output of get token: %v", err)
+		}
+	}
+
+	if err := o.execute(self, []string{
+		"join",
+		"--kubeconfig", clusterKubeconfig,
+		"--hub-token", hubInfo.HubToken,
+		"--hub-apiserver", hubInfo.HubApiserver,
+		"--cluster-name", o.clusterName,
+		"--bundle-version", o.bundleVersion,
+		"--image-registry", o.registry,
+		"--timeout", strconv.Itoa(o.ClusteradmFlags.Timeout),
+		"--wait",
+	}, nil); err != nil {
+		return fmt.Errorf("failed to join cluster %s: %v", o.clusterName, err)
+	}
+
+	if err := o.execute(self, []string{
+		"accept",
+		"--kubeconfig", hubKubeconfig,
+		"--clusters", o.clusterName,
+		"--timeout", strconv.Itoa(o.ClusteradmFlags.Timeout),
+		"--wait",
+	}, nil); err != nil {
+		return fmt.Errorf("failed to accept cluster %s: %v", o.clusterName, err)
+	}
+
+	return nil
+}
+
+// writeHubKubeconfig writes the current context of the hub kubeconfig with its credentials inlined
+func (o *Options) writeHubKubeconfig(path string) error {
+	config, err := o.ClusteradmFlags.KubectlFactory.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return err
+	}
+	if len(o.ClusteradmFlags.Context) > 0 {
+		config.CurrentContext = o.ClusteradmFlags.Context
+	}
+	if err := clientcmdapi.MinifyConfig(&config); err != nil {
+		return err
+	}
+	if err := clientcmdapi.FlattenConfig(&config); err != nil {
+		return err
+	}
+	return clientcmd.WriteToFile(config, path)
+}
+
+// writeClusterKubeconfig writes the kubeconfig of the cluster generated by the Cluster API
+func (o *Options) writeClusterKubeconfig(cluster *resource.Info, path string) error {
+	kubeClient, err := o.ClusteradmFlags.KubectlFactory.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-kubeconfig", cluster.Name)
+	secret, err := kubeClient.CoreV1().Secrets(cluster.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the kubeconfig of cluster %s/%s: %v", cluster.Namespace, cluster.Name, err)
+	}
+	kubeconfig, ok := secret.Data[kubeconfigSecretKey]
+	if !ok {
+		return fmt.Errorf("secret %s/%s has no %s key", cluster.Namespace, name, kubeconfigSecretKey)
+	}
+	return os.WriteFile(path, kubeconfig, 0600)
+}
+
+// execute runs the command, if stdout is nil the output of the command is
+// redirected to the output stream. In dry-run mode the command is only printed.
+func (o *Options) execute(name string, args []string, stdout *bytes.Buffer) error {
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "%s %s\n", name, strings.Join(args, " "))
+		return nil
+	}
+	klog.V(1).InfoS("running:", "command", name, "args", args)
+	c := exec.Command(name, args...)
+	c.Stdin = o.Streams.In
+	c.Stderr = o.Streams.ErrOut
+	c.Stdout = o.Streams.Out
+	if stdout != nil {
+		c.Stdout = stdout
+	}
+	return c.Run()
+}
@@ -0,0 +1,98 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestIsClusterReady(t *testing.T) {
+	cases := []struct {
+		name          string
+		status        map[string]interface{}
+		expectedReady bool
+		expectedErr   bool
+	}{
+		{
+			name:   "provisioning",
+			status: map[string]interface{}{"phase": "Provisioning"},
+		},
+		{
+			name:        "failed",
+			status:      map[string]interface{}{"phase": "Failed", "failureMessage": "no quota"},
+			expectedErr: true,
+		},
+		{
+			name: "control plane not ready",
+			status: map[string]interface{}{
+				"phase":      "Provisioned",
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+			},
+		},
+		{
+			name: "ready condition false",
+			status: map[string]interface{}{
+				"phase":             "Provisioned",
+				"controlPlaneReady": true,
+				"conditions":        []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}},
+			},
+		},
+		{
+			name: "ready",
+			status: map[string]interface{}{
+				"phase":             "Provisioned",
+				"controlPlaneReady": true,
+				"conditions":        []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+			},
+			expectedReady: true,
+		},
+		{
+			name:          "provisioned without conditions",
+			status:        map[string]interface{}{"phase": "Provisioned", "controlPlaneReady": true},
+			expectedReady: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := &unstructured.Unstructured{Object: map[string]interface{}{"status": c.status}}
+			ready, err := isClusterReady(cluster)
+			if (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+			if ready != c.expectedReady {
+				t.Errorf("expected ready %v, got %v", c.expectedReady, ready)
+			}
+		})
+	}
+}
+
+func TestCapiCluster(t *testing.T) {
+	newInfo := func(group, kind, name string) *resource.Info {
+		return &resource.Info{
+			Name:    name,
+			Mapping: &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Group: group, Version: "v1beta1", Kind: kind}},
+		}
+	}
+
+	infos := []*resource.Info{
+		newInfo("infrastructure.cluster.x-k8s.io", "DockerCluster", "cluster1"),
+		newInfo(capiGroup, "Cluster", "cluster1"),
+		newInfo("controlplane.cluster.x-k8s.io", "KubeadmControlPlane", "cluster1-control-plane"),
+	}
+	cluster, err := capiCluster(infos)
+	if err != nil || cluster != infos[1] {
+		t.Errorf("expected the Cluster to be found, got %v %v", cluster, err)
+	}
+
+	if _, err := capiCluster(infos[:1]); err == nil {
+		t.Errorf("expected an error without Cluster")
+	}
+	if _, err := capiCluster(append(infos, newInfo(capiGroup, "Cluster", "cluster2"))); err == nil {
+		t.Errorf("expected an error with two Clusters")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	//The provider provisioning the cluster
+	provider string
+	//The file of the resources provisioning the cluster
+	template string
+	//The namespace of the resources without one
+	namespace string
+	//Join the cluster to the hub and accept it once it is ready
+	join bool
+	//The name of the cluster on the hub
+	clusterName string
+	//version of predefined compatible image versions
+	bundleVersion string
+	//Pulling image registry of OCM
+	registry string
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -4,6 +4,8 @@ package create
 import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/create/access"
+	"open-cluster-management.io/clusteradm/pkg/cmd/create/cluster"
 	"open-cluster-management.io/clusteradm/pkg/cmd/create/clusterset"
 	"open-cluster-management.io/clusteradm/pkg/cmd/create/sampleapp"
 	"open-cluster-management.io/clusteradm/pkg/cmd/create/work"
@@ -20,6 +22,8 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd.AddCommand(clusterset.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(work.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(sampleapp.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(access.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(cluster.NewCmd(clusteradmFlags, streams))
 
 	return cmd
 }
@@ -27,6 +27,15 @@ var example = `
 # For example, if placement1 update decision to cluster2 and cluster3, 
 # then the manifestwork will be deleted from cluster1 and created on cluster3.
 %[1]s create work work-example -f xxx.yaml --placement default/placement1 --overwrite
+
+# Preview the resources created, updated or deleted on each cluster before applying the work.
+%[1]s create work work-example -f xxx.yaml --placement default/placement1 --overwrite --preview
+
+# Create manifestwork from an embedded template, the embedded templates are namespace-deployment, configmap-sync and rbac-grant.
+%[1]s create work grant-alice --from-template rbac-grant --set user=alice --set clusterrole=edit --clusters cluster1
+
+# Create manifestwork from a template of a directory, the template is the file <directory>/<name>.yaml.
+%[1]s create work work-example --from-template my-template --template-dir ./templates --set key=value --clusters cluster1
 `
 
 // NewCmd...
@@ -62,6 +71,10 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd.Flags().StringVar(&o.Cluster, "clusters", "", "Names of the managed cluster to apply work")
 	cmd.Flags().StringVar(&o.Placement, "placement", "", "Specify an existing placement with format <namespace>/<name>")
 	cmd.Flags().BoolVar(&o.Overwrite, "overwrite", false, "Overwrite the existing work if it exists already")
+	cmd.Flags().BoolVar(&o.Preview, "preview", false, "List the resources created, updated or deleted on each cluster without applying the work")
+	cmd.Flags().StringVar(&o.FromTemplate, "from-template", "", "Name of the template the manifests are rendered from, instead of the manifest files")
+	cmd.Flags().StringVar(&o.TemplateDir, "template-dir", "", "Directory of the templates, they take precedence over the embedded templates of the same name")
+	cmd.Flags().StringArrayVar(&o.Values, "set", []string{}, "Values of the template in the format of key=value")
 	o.FileNameFlags.AddFlags(cmd.Flags())
 
 	return cmd
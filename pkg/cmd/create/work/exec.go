@@ -17,6 +17,8 @@ import (
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
 )
 
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
@@ -48,8 +50,14 @@ func (o *Options) validate() (err error) {
 	if len(o.Placement) > 0 && len(strings.Split(o.Placement, "/")) != 2 {
 		return fmt.Errorf("the name of the placement %s must be in the format of <namespace>/<name>", o.Placement)
 	}
-	if len(*o.FileNameFlags.Filenames) == 0 {
-		return fmt.Errorf("manifest files must be specified")
+	if len(*o.FileNameFlags.Filenames) == 0 && len(o.FromTemplate) == 0 {
+		return fmt.Errorf("manifest files or --from-template must be specified")
+	}
+	if len(*o.FileNameFlags.Filenames) > 0 && len(o.FromTemplate) > 0 {
+		return fmt.Errorf("manifest files and --from-template can only specify one")
+	}
+	if len(o.FromTemplate) == 0 && (len(o.TemplateDir) > 0 || len(o.Values) > 0) {
+		return fmt.Errorf("--template-dir and --set can only be used with --from-template")
 	}
 
 	return nil
@@ -79,6 +87,10 @@ func (o *Options) run() (err error) {
 		return err
 	}
 
+	if o.Preview {
+		return o.previewWork(workClient, manifests, addedClusters, deletedClusters)
+	}
+
 	err = o.applyWork(workClient, manifests, addedClusters, deletedClusters)
 	if err != nil {
 		return err
@@ -88,6 +100,18 @@ func (o *Options) run() (err error) {
 }
 
 func (o *Options) readManifests() ([]workapiv1.Manifest, error) {
+	if len(o.FromTemplate) > 0 {
+		values, err := parseValues(o.Values)
+		if err != nil {
+			return nil, err
+		}
+		content, err := readTemplate(o.FromTemplate, o.TemplateDir)
+		if err != nil {
+			return nil, err
+		}
+		return renderTemplate(o.FromTemplate, content, values)
+	}
+
 	opt := o.FileNameFlags.ToOptions()
 	builder := resource.NewLocalBuilder().
 		Unstructured().
@@ -138,6 +162,7 @@ func (o *Options) getWorkDepolyClusters(workClient workclientset.Interface) (set
 	for _, work := range works.Items {
 		if work.Name == o.Workname {
 			depolyClusters.Insert(work.Namespace)
+			o.paused = o.paused || rollout.IsPaused(&work)
 		}
 	}
 	return depolyClusters, nil
@@ -169,6 +194,12 @@ func (o *Options) getClusters(workClient workclientset.Interface, clusterClient
 }
 
 func (o *Options) applyWork(workClient workclientset.Interface, manifests []workapiv1.Manifest, addedClusters, deletedClusters sets.String) error {
+	if o.paused {
+		fmt.Fprintf(o.Streams.Out, "the rollout of work %s is paused, run '%s patch work %s --resume' to resume it\n",
+			o.Workname, clusteradmhelpers.GetExampleHeader(), o.Workname)
+		return nil
+	}
+
 	for clusterName := range deletedClusters {
 		if o.Overwrite {
 			if err := workClient.WorkV1().ManifestWorks(clusterName).Delete(context.TODO(), o.Workname, metav1.DeleteOptions{}); err != nil {
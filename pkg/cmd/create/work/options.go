@@ -21,6 +21,19 @@ type Options struct {
 	FileNameFlags genericclioptions.FileNameFlags
 
 	Overwrite bool
+
+	//Preview lists the resources created, updated or deleted on each cluster instead of applying the work
+	Preview bool
+
+	//The name of the template the manifests are rendered from
+	FromTemplate string
+	//The directory of the user templates, they take precedence over the embedded templates
+	TemplateDir string
+	//The values of the template in the format of key=value
+	Values []string
+
+	//The rollout of the work is paused on one of the clusters
+	paused bool
 }
 
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
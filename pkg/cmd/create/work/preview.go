@@ -0,0 +1,157 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	actionCreate    = "create"
+	actionUpdate    = "update"
+	actionDelete    = "delete"
+	actionUnchanged = "unchanged"
+)
+
+// change is what applying the work does to a resource on a cluster
+type change struct {
+	action   string
+	resource string
+}
+
+// previewWork prints per cluster the resources the work would create, update or delete, without applying it
+func (o *Options) previewWork(workClient workclientset.Interface, manifests []workapiv1.Manifest, addedClusters, deletedClusters sets.String) error {
+	w := tabwriter.NewWriter(o.Streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tACTION\tRESOURCE")
+	print := func(cluster string, changes []change) {
+		for _, c := range changes {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", cluster, c.action, c.resource)
+		}
+	}
+
+	for _, clusterName := range deletedClusters.List() {
+		if !o.Overwrite {
+			continue
+		}
+		work, err := workClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), o.Workname, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		changes, err := previewChanges(work, nil)
+		if err != nil {
+			return err
+		}
+		print(clusterName, changes)
+	}
+
+	for _, clusterName := range addedClusters.List() {
+		work, err := workClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), o.Workname, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			work = nil
+		case err != nil:
+			return err
+		case !o.Overwrite:
+			fmt.Fprintf(w, "%s\t%s\twork %s already exists, set --overwrite to update it\n", clusterName, actionUnchanged, o.Workname)
+			continue
+		}
+		changes, err := previewChanges(work, manifests)
+		if err != nil {
+			return err
+		}
+		print(clusterName, changes)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if o.paused {
+		fmt.Fprintf(o.Streams.Out, "the rollout of work %s is paused, the changes are not applied until it is resumed\n", o.Workname)
+	}
+	return nil
+}
+
+// previewChanges compares the manifests with the existing work, nil if it does not exist. The resources applied
+// are the ones reported in the status of the work by the agent, or its manifests if it has not reported them yet.
+func previewChanges(existing *workapiv1.ManifestWork, manifests []workapiv1.Manifest) ([]change, error) {
+	current := map[string]*unstructured.Unstructured{}
+	applied := sets.NewString()
+	if existing != nil {
+		for _, manifest := range existing.Spec.Workload.Manifests {
+			obj, err := manifestObject(manifest)
+			if err != nil {
+				return nil, err
+			}
+			current[resourceKey(obj.GroupVersionKind().Group, obj.GetKind(), obj.GetNamespace(), obj.GetName())] = obj
+		}
+		for _, condition := range existing.Status.ResourceStatus.Manifests {
+			meta := condition.ResourceMeta
+			applied.Insert(resourceKey(meta.Group, meta.Kind, meta.Namespace, meta.Name))
+		}
+		if applied.Len() == 0 {
+			for key := range current {
+				applied.Insert(key)
+			}
+		}
+	}
+
+	var changes []change
+	desired := sets.NewString()
+	for _, manifest := range manifests {
+		obj, err := manifestObject(manifest)
+		if err != nil {
+			return nil, err
+		}
+		key := resourceKey(obj.GroupVersionKind().Group, obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		desired.Insert(key)
+		switch {
+		case !applied.Has(key):
+			changes = append(changes, change{action: actionCreate, resource: key})
+		case current[key] != nil && equality.Semantic.DeepEqual(current[key].Object, obj.Object):
+			changes = append(changes, change{action: actionUnchanged, resource: key})
+		default:
+			changes = append(changes, change{action: actionUpdate, resource: key})
+		}
+	}
+	for _, key := range applied.Difference(desired).List() {
+		changes = append(changes, change{action: actionDelete, resource: key})
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].resource < changes[j].resource })
+	return changes, nil
+}
+
+// manifestObject returns the object of the manifest, decoded from its raw content if the object is not set
+func manifestObject(manifest workapiv1.Manifest) (*unstructured.Unstructured, error) {
+	if obj, ok := manifest.Object.(*unstructured.Unstructured); ok {
+		return obj, nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	return obj, nil
+}
+
+// resourceKey returns the resource in the format of <kind>.<group> <namespace>/<name>
+func resourceKey(group, kind, namespace, name string) string {
+	if len(group) > 0 {
+		kind = kind + "." + group
+	}
+	if len(namespace) > 0 {
+		name = namespace + "/" + name
+	}
+	return kind + " " + name
+}
@@ -0,0 +1,109 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newManifest(apiVersion, kind, namespace, name string, data map[string]interface{}) workapiv1.Manifest {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+	}}
+	if data != nil {
+		obj.Object["data"] = data
+	}
+	return workapiv1.Manifest{RawExtension: runtime.RawExtension{Object: obj}}
+}
+
+func rawManifest(t *testing.T, manifest workapiv1.Manifest) workapiv1.Manifest {
+	raw, err := manifest.Object.(*unstructured.Unstructured).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return workapiv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}}
+}
+
+func TestPreviewChanges(t *testing.T) {
+	config := newManifest("v1", "ConfigMap", "default", "config", map[string]interface{}{"key": "value"})
+	updated := newManifest("v1", "ConfigMap", "default", "config", map[string]interface{}{"key": "new"})
+	deploy := newManifest("apps/v1", "Deployment", "default", "nginx", nil)
+	namespace := newManifest("v1", "Namespace", "", "app", nil)
+
+	cases := []struct {
+		name      string
+		existing  func() *workapiv1.ManifestWork
+		manifests []workapiv1.Manifest
+		expected  []change
+	}{
+		{
+			name:      "new work",
+			existing:  func() *workapiv1.ManifestWork { return nil },
+			manifests: []workapiv1.Manifest{config, deploy},
+			expected: []change{
+				{action: actionCreate, resource: "ConfigMap default/config"},
+				{action: actionCreate, resource: "Deployment.apps default/nginx"},
+			},
+		},
+		{
+			name: "work not applied yet",
+			existing: func() *workapiv1.ManifestWork {
+				work := &workapiv1.ManifestWork{}
+				work.Spec.Workload.Manifests = []workapiv1.Manifest{rawManifest(t, config), rawManifest(t, namespace)}
+				return work
+			},
+			manifests: []workapiv1.Manifest{config, deploy},
+			expected: []change{
+				{action: actionUnchanged, resource: "ConfigMap default/config"},
+				{action: actionCreate, resource: "Deployment.apps default/nginx"},
+				{action: actionDelete, resource: "Namespace app"},
+			},
+		},
+		{
+			name: "work applied",
+			existing: func() *workapiv1.ManifestWork {
+				work := &workapiv1.ManifestWork{}
+				work.Spec.Workload.Manifests = []workapiv1.Manifest{rawManifest(t, config), rawManifest(t, deploy)}
+				work.Status.ResourceStatus.Manifests = []workapiv1.ManifestCondition{
+					{ResourceMeta: workapiv1.ManifestResourceMeta{Version: "v1", Kind: "ConfigMap", Namespace: "default", Name: "config"}},
+					{ResourceMeta: workapiv1.ManifestResourceMeta{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "default", Name: "nginx"}},
+				}
+				return work
+			},
+			manifests: []workapiv1.Manifest{updated},
+			expected: []change{
+				{action: actionUpdate, resource: "ConfigMap default/config"},
+				{action: actionDelete, resource: "Deployment.apps default/nginx"},
+			},
+		},
+		{
+			name: "work deleted",
+			existing: func() *workapiv1.ManifestWork {
+				work := &workapiv1.ManifestWork{}
+				work.Spec.Workload.Manifests = []workapiv1.Manifest{rawManifest(t, config)}
+				return work
+			},
+			expected: []change{
+				{action: actionDelete, resource: "ConfigMap default/config"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changes, err := previewChanges(c.existing(), c.manifests)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(changes, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, changes)
+			}
+		})
+	}
+}
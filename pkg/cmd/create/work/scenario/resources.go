@@ -0,0 +1,32 @@
+// Copyright Contributors to the Open Cluster Management project
+package scenario
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const templateDir = "templates"
+
+//go:embed templates
+var files embed.FS
+
+// GetTemplate returns the content of the embedded work template of the name
+func GetTemplate(name string) ([]byte, error) {
+	return files.ReadFile(path.Join(templateDir, name+".yaml"))
+}
+
+// TemplateNames returns the names of the embedded work templates
+func TemplateNames() ([]string, error) {
+	entries, err := fs.ReadDir(files, templateDir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	return names, nil
+}
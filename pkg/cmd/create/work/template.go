@@ -0,0 +1,114 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/create/work/scenario"
+)
+
+// parseValues parses the --set values in the format of key=value
+func parseValues(sets []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, set := range sets {
+		parts := strings.SplitN(set, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid value %q, it should be in the format of key=value", set)
+		}
+		values[parts[0]] = parts[1]
+	}
+	return values, nil
+}
+
+// readTemplate returns the template of the name, the templates of the template directory take
+// precedence over the embedded ones
+func readTemplate(name, templateDir string) ([]byte, error) {
+	if len(templateDir) > 0 {
+		content, err := os.ReadFile(filepath.Join(templateDir, name+".yaml"))
+		switch {
+		case err == nil:
+			return content, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+	content, err := scenario.GetTemplate(name)
+	if err == nil {
+		return content, nil
+	}
+	names, err := scenario.TemplateNames()
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("template %s is not found, the embedded templates are %s", name, strings.Join(names, ", "))
+}
+
+var templateFuncs = template.FuncMap{
+	// required fails the rendering if the value is not set
+	"required": func(name, value string) (string, error) {
+		if len(value) == 0 {
+			return "", fmt.Errorf("value %s is required, set it with --set %s=<value>", name, name)
+		}
+		return value, nil
+	},
+	"default": func(defaultValue, value string) string {
+		if len(value) == 0 {
+			return defaultValue
+		}
+		return value
+	},
+	"quote": strconv.Quote,
+	// keyValues parses a value in the format of key1=value1,key2=value2
+	"keyValues": func(value string) (map[string]string, error) {
+		return parseValues(strings.Split(value, ","))
+	},
+}
+
+// renderTemplate renders the template with the values into the manifests of a work, each yaml
+// document of the rendered template is a manifest.
+func renderTemplate(name string, content []byte, values map[string]string) ([]workapiv1.Manifest, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %v", name, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, values); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %v", name, err)
+	}
+
+	manifests := []workapiv1.Manifest{}
+	decoder := yaml.NewYAMLOrJSONDecoder(buf, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("template %s renders an invalid manifest: %v", name, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if len(obj.GetAPIVersion()) == 0 || len(obj.GetKind()) == 0 || len(obj.GetName()) == 0 {
+			return nil, fmt.Errorf("template %s renders a manifest without apiVersion, kind or name", name)
+		}
+		manifests = append(manifests, workapiv1.Manifest{RawExtension: runtime.RawExtension{Object: obj}})
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("template %s renders no manifest", name)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,107 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRenderEmbeddedTemplates(t *testing.T) {
+	cases := []struct {
+		template      string
+		values        []string
+		expectedKinds []string
+		expectedErr   string
+	}{
+		{
+			template:      "namespace-deployment",
+			values:        []string{"namespace=app", "name=nginx", "image=nginx:1.25"},
+			expectedKinds: []string{"Namespace", "Deployment"},
+		},
+		{
+			template:    "namespace-deployment",
+			values:      []string{"namespace=app", "name=nginx"},
+			expectedErr: "value image is required",
+		},
+		{
+			template:      "configmap-sync",
+			values:        []string{"namespace=app", "name=settings", "data=mode=debug,level=2"},
+			expectedKinds: []string{"ConfigMap"},
+		},
+		{
+			template:      "rbac-grant",
+			values:        []string{"user=alice"},
+			expectedKinds: []string{"ClusterRoleBinding"},
+		},
+		{
+			template:      "rbac-grant",
+			values:        []string{"user=alice", "namespace=app", "clusterrole=edit"},
+			expectedKinds: []string{"RoleBinding"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.template, func(t *testing.T) {
+			values, err := parseValues(c.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := readTemplate(c.template, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			manifests, err := renderTemplate(c.template, content, values)
+			if len(c.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+					t.Fatalf("expected error %q, got %v", c.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			kinds := []string{}
+			for _, manifest := range manifests {
+				kinds = append(kinds, manifest.Object.(*unstructured.Unstructured).GetKind())
+			}
+			if strings.Join(kinds, ",") != strings.Join(c.expectedKinds, ",") {
+				t.Errorf("expected kinds %v, got %v", c.expectedKinds, kinds)
+			}
+		})
+	}
+}
+
+func TestReadTemplate(t *testing.T) {
+	dir := t.TempDir()
+	custom := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .name }}\n"
+	if err := os.WriteFile(filepath.Join(dir, "rbac-grant.yaml"), []byte(custom), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := readTemplate("rbac-grant", dir)
+	if err != nil || string(content) != custom {
+		t.Errorf("expected the template of the directory to take precedence, got %s %v", content, err)
+	}
+	if _, err := readTemplate("configmap-sync", dir); err != nil {
+		t.Errorf("expected the embedded template, got %v", err)
+	}
+	if _, err := readTemplate("missing", dir); err == nil || !strings.Contains(err.Error(), "rbac-grant") {
+		t.Errorf("expected an error listing the embedded templates, got %v", err)
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	values, err := parseValues([]string{"user=alice", "data=a=b,c=d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["user"] != "alice" || values["data"] != "a=b,c=d" {
+		t.Errorf("unexpected values %v", values)
+	}
+	if _, err := parseValues([]string{"user"}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# List what is removed from the hub if cluster1 is detached
+%[1]s delete cluster cluster1 --detach-only
+# Detach cluster1, its agent and the resources of its manifestworks are kept on the cluster
+%[1]s delete cluster cluster1 --detach-only --confirm
+# Remove cluster1 from the hub and have its agent remove itself
+%[1]s delete cluster cluster1 --destroy-agent --confirm
+# Remove cluster1 from the hub hub2, the cluster keeps its registration to its other hubs
+%[1]s delete cluster cluster1 --destroy-agent --klusterlet-name hub2 --confirm
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "remove a managed cluster from the hub",
+		Long: "remove a managed cluster and its resources from the hub. With --detach-only the agent keeps running on the " +
+			"cluster and the resources of the manifestworks are orphaned, the cluster registers again once accepted. " +
+			"With --destroy-agent the addons and the manifestworks are removed from the cluster, then the agent is " +
+			"instructed to remove itself before the cluster is removed. The resources are only listed if --confirm is not set.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.detachOnly, "detach-only", false,
+		"Remove the cluster from the hub, its agent and the resources of its manifestworks are kept on the cluster")
+	cmd.Flags().BoolVar(&o.destroyAgent, "destroy-agent", false,
+		"Remove the addons and the manifestworks from the cluster and instruct its agent to remove itself, then remove the cluster from the hub")
+	cmd.Flags().StringVar(&o.klusterletName, "klusterlet-name", join.DefaultKlusterletName,
+		"The name of the klusterlet removed with --destroy-agent, a cluster registered to several hubs runs a klusterlet per hub")
+	cmd.Flags().BoolVar(&o.confirm, "confirm", false, "Remove the cluster, the resources are only listed if not set")
+
+	return cmd
+}
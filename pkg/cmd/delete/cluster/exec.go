@@ -0,0 +1,259 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/selfdestruct"
+)
+
+const (
+	// clusterLabel is set by the registration agent on the CSRs of its cluster
+	clusterLabel = "open-cluster-management.io/cluster-name"
+	pollInterval = 2 * time.Second
+)
+
+const (
+	kindManifestWork        = "ManifestWork"
+	kindManagedClusterAddOn = "ManagedClusterAddOn"
+	kindKlusterlet          = "Klusterlet"
+	kindCSR                 = "CertificateSigningRequest"
+	kindManagedCluster      = "ManagedCluster"
+	kindNamespace           = "Namespace"
+)
+
+// step is a resource of the cluster changed on the hub, in order
+type step struct {
+	kind      string
+	namespace string
+	name      string
+	action    string
+}
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("delete cluster options:", "dry-run", o.ClusteradmFlags.DryRun, "detach-only", o.detachOnly,
+		"destroy-agent", o.destroyAgent, "klusterlet", o.klusterletName, "confirm", o.confirm)
+	if len(args) != 1 {
+		return fmt.Errorf("the name of the managed cluster must be specified")
+	}
+	o.cluster = args[0]
+	return nil
+}
+
+func (o *Options) validate() error {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if o.detachOnly == o.destroyAgent {
+		return fmt.Errorf("either --detach-only or --destroy-agent must be set")
+	}
+	if err := join.ValidateKlusterletName(o.klusterletName); err != nil {
+		return err
+	}
+	if o.detachOnly && o.klusterletName != join.DefaultKlusterletName {
+		return fmt.Errorf("--klusterlet-name is only used with --destroy-agent")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	addonClient, err := addonclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), o.cluster, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("managed cluster %s is not found", o.cluster)
+	}
+	if err != nil {
+		return err
+	}
+	works, err := workClient.WorkV1().ManifestWorks(o.cluster).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	addons, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(o.cluster).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", clusterLabel, o.cluster),
+	})
+	if err != nil {
+		return err
+	}
+
+	steps := plan(o.cluster, o.klusterletName, works.Items, addons.Items, csrs.Items, o.destroyAgent)
+	w := tabwriter.NewWriter(o.Streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tACTION")
+	for _, s := range steps {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.kind, s.namespace, s.name, s.action)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !o.confirm || o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "\nRun with --confirm to remove the managed cluster %s\n", o.cluster)
+		return nil
+	}
+	if o.destroyAgent && !meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: managed cluster %s is not available, its agent may not remove itself\n", o.cluster)
+	}
+
+	fmt.Fprintln(o.Streams.Out)
+	timeout := time.Duration(o.ClusteradmFlags.Timeout) * time.Second
+	for _, s := range steps {
+		if err := o.execute(kubeClient, clusterClient, workClient, addonClient, s, timeout); err != nil {
+			return fmt.Errorf("failed to remove %s %s: %v", s.kind, qualifiedName(s), err)
+		}
+	}
+
+	if o.detachOnly {
+		fmt.Fprintf(o.Streams.Out, "\nThe agent of %s keeps running, it registers again with its bootstrap kubeconfig. "+
+			"To accept it again run:\n\n    %s accept --clusters %s\n\n", o.cluster, helpers.GetExampleHeader(), o.cluster)
+	}
+	return nil
+}
+
+// plan returns the steps removing the cluster, the manifestworks are orphaned without destroyAgent
+func plan(cluster, klusterletName string, works []workapiv1.ManifestWork, addons []addonv1alpha1.ManagedClusterAddOn,
+	csrs []certificatesv1.CertificateSigningRequest, destroyAgent bool) []step {
+	var steps []step
+	if destroyAgent {
+		// the addons are removed first, the addon manager would otherwise recreate their manifestworks
+		for _, addon := range addons {
+			steps = append(steps, step{kindManagedClusterAddOn, cluster, addon.Name, "removed with its agent"})
+		}
+	}
+	for _, work := range works {
+		if work.Name == selfdestruct.WorkName(klusterletName) {
+			continue
+		}
+		if destroyAgent {
+			steps = append(steps, step{kindManifestWork, cluster, work.Name, "removed with its resources"})
+		} else {
+			steps = append(steps, step{kindManifestWork, cluster, work.Name, "orphaned, its resources are kept on the cluster"})
+		}
+	}
+	if destroyAgent {
+		steps = append(steps, step{kindKlusterlet, "", klusterletName,
+			fmt.Sprintf("removed from the cluster by the manifestwork %s", selfdestruct.WorkName(klusterletName))})
+	}
+	for _, csr := range csrs {
+		steps = append(steps, step{kindCSR, "", csr.Name, "removed"})
+	}
+	steps = append(steps,
+		step{kindManagedCluster, "", cluster, "removed"},
+		step{kindNamespace, "", cluster, "removed by the hub with the resources left in it"},
+	)
+	return steps
+}
+
+func (o *Options) execute(kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface, workClient workclientset.Interface,
+	addonClient addonclientset.Interface, s step, timeout time.Duration) error {
+	ctx := context.TODO()
+	var err error
+	switch s.kind {
+	case kindManagedClusterAddOn:
+		err = addonClient.AddonV1alpha1().ManagedClusterAddOns(s.namespace).Delete(ctx, s.name, metav1.DeleteOptions{})
+	case kindManifestWork:
+		if !o.destroyAgent {
+			orphan := []byte(`{"spec":{"deleteOption":{"propagationPolicy":"Orphan","selectivelyOrphans":null}}}`)
+			_, err = workClient.WorkV1().ManifestWorks(s.namespace).Patch(ctx, s.name, types.MergePatchType, orphan, metav1.PatchOptions{})
+			break
+		}
+		err = workClient.WorkV1().ManifestWorks(s.namespace).Delete(ctx, s.name, metav1.DeleteOptions{})
+		if err == nil {
+			err = waitForDeletion(timeout, func() error {
+				_, err := workClient.WorkV1().ManifestWorks(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+				return err
+			})
+		}
+	case kindKlusterlet:
+		return o.destroyKlusterlet(workClient, timeout)
+	case kindCSR:
+		err = kubeClient.CertificatesV1().CertificateSigningRequests().Delete(ctx, s.name, metav1.DeleteOptions{})
+	case kindManagedCluster:
+		err = clusterClient.ClusterV1().ManagedClusters().Delete(ctx, s.name, metav1.DeleteOptions{})
+	case kindNamespace:
+		// the namespace is removed by the registration controller of the hub once the cluster is removed
+		fmt.Fprintf(o.Streams.Out, "%s %s is removed by the hub\n", s.kind, s.name)
+		return nil
+	default:
+		return fmt.Errorf("unknown kind %s", s.kind)
+	}
+	if errors.IsNotFound(err) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "%s %s is %s\n", s.kind, qualifiedName(s), s.action)
+	return nil
+}
+
+// destroyKlusterlet removes the klusterlet with a manifestwork, the klusterlet operator then removes the agents
+func (o *Options) destroyKlusterlet(workClient workclientset.Interface, timeout time.Duration) error {
+	err := selfdestruct.RemoveKlusterlet(context.TODO(), workClient, o.cluster, o.klusterletName, timeout, o.Streams.ErrOut)
+	if err != nil {
+		return fmt.Errorf("%v, run '%s unjoin' on the cluster", err, helpers.GetExampleHeader())
+	}
+	fmt.Fprintf(o.Streams.Out, "%s %s is removed from the cluster by the manifestwork %s\n", kindKlusterlet, o.klusterletName,
+		selfdestruct.WorkName(o.klusterletName))
+	return nil
+}
+
+// waitForDeletion waits until get returns not found
+func waitForDeletion(timeout time.Duration, get func() error) error {
+	return wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		err := get()
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+func qualifiedName(s step) string {
+	if len(s.namespace) == 0 {
+		return s.name
+	}
+	return s.namespace + "/" + s.name
+}
@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/selfdestruct"
+)
+
+func TestPlan(t *testing.T) {
+	works := []workapiv1.ManifestWork{
+		{ObjectMeta: metav1.ObjectMeta{Name: "work1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: selfdestruct.WorkName("hub2")}},
+	}
+	addons := []addonv1alpha1.ManagedClusterAddOn{{ObjectMeta: metav1.ObjectMeta{Name: "addon1"}}}
+	csrs := []certificatesv1.CertificateSigningRequest{{ObjectMeta: metav1.ObjectMeta{Name: "csr1"}}}
+
+	kinds := func(steps []step) []string {
+		kinds := []string{}
+		for _, s := range steps {
+			kinds = append(kinds, s.kind)
+		}
+		return kinds
+	}
+	cases := []struct {
+		name          string
+		destroyAgent  bool
+		expectedKinds []string
+	}{
+		{
+			name:          "detach only",
+			expectedKinds: []string{kindManifestWork, kindCSR, kindManagedCluster, kindNamespace},
+		},
+		{
+			name:         "destroy agent",
+			destroyAgent: true,
+			expectedKinds: []string{kindManagedClusterAddOn, kindManifestWork, kindKlusterlet, kindCSR,
+				kindManagedCluster, kindNamespace},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			steps := plan("cluster1", "hub2", works, addons, csrs, c.destroyAgent)
+			if !reflect.DeepEqual(kinds(steps), c.expectedKinds) {
+				t.Errorf("expected the steps %v, got %v", c.expectedKinds, kinds(steps))
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//cluster: the name of the managed cluster
+	cluster string
+	//detachOnly: the agent and the resources of the manifestworks are kept on the cluster
+	detachOnly bool
+	//destroyAgent: the addons, the manifestworks and the agent are removed from the cluster
+	destroyAgent bool
+	//klusterletName: the klusterlet removed with destroyAgent, a cluster registered to several hubs runs a klusterlet per hub
+	klusterletName string
+	//confirm: remove the cluster, the resources are only listed if not set
+	confirm bool
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -4,6 +4,7 @@ package get
 import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/delete/cluster"
 	"open-cluster-management.io/clusteradm/pkg/cmd/delete/clusterset"
 	"open-cluster-management.io/clusteradm/pkg/cmd/delete/token"
 	"open-cluster-management.io/clusteradm/pkg/cmd/delete/work"
@@ -20,6 +21,7 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd.AddCommand(token.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(work.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(clusterset.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(cluster.NewCmd(clusteradmFlags, streams))
 
 	return cmd
 }
@@ -53,8 +53,13 @@ func (o *Options) run() error {
 }
 
 func (o *Options) deleteToken(kubeClient *kubernetes.Clientset) error {
+	//Find the namespace of the service account before its binding is deleted
+	saNamespace, err := helpers.GetBootstrapSANamespace(context.TODO(), kubeClient)
+	if err != nil {
+		return err
+	}
 	//Delete bootstrap token bindings
-	err := kubeClient.RbacV1().ClusterRoleBindings().Delete(context.TODO(), config.BootstrapClusterRoleBindingName, metav1.DeleteOptions{})
+	err = kubeClient.RbacV1().ClusterRoleBindings().Delete(context.TODO(), config.BootstrapClusterRoleBindingName, metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
@@ -81,7 +86,7 @@ func (o *Options) deleteToken(kubeClient *kubernetes.Clientset) error {
 		return err
 	}
 	//Delete service account
-	err = kubeClient.CoreV1().ServiceAccounts(config.OpenClusterManagementNamespace).Delete(context.TODO(), config.BootstrapSAName, metav1.DeleteOptions{})
+	err = kubeClient.CoreV1().ServiceAccounts(saNamespace).Delete(context.TODO(), config.BootstrapSAName, metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
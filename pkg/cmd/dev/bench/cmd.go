@@ -0,0 +1,55 @@
+// Copyright Contributors to the Open Cluster Management project
+package bench
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Register 100 simulated managed clusters to the hub and report the latencies
+%[1]s dev bench --join 100
+# Register 1000 simulated managed clusters with 50 concurrent requests and keep them afterwards
+%[1]s dev bench --join 1000 --concurrency 50 --skip-cleanup
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "benchmark the registration throughput of the hub",
+		Long: "register simulated managed clusters to the hub, without any agent, and measure the latency of the " +
+			"apply of the managed clusters and of their acceptance by the hub registration controller, " +
+			"producing a latency histogram report for the capacity planning of the hub",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&o.join, "join", 10, "The number of simulated managed clusters to register")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", 10, "The number of managed clusters registered concurrently")
+	cmd.Flags().StringVar(&o.namePrefix, "name-prefix", "bench", "The prefix of the simulated managed cluster names")
+	cmd.Flags().BoolVar(&o.skipCleanup, "skip-cleanup", false, "If set, the simulated managed clusters are not deleted after the benchmark")
+	return cmd
+}
@@ -0,0 +1,146 @@
+// Copyright Contributors to the Open Cluster Management project
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// benchLabel is set on the simulated managed clusters with the name prefix as value
+const benchLabel = "clusteradm.open-cluster-management.io/bench"
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("dev bench options:", "dry-run", o.ClusteradmFlags.DryRun, "join", o.join,
+		"concurrency", o.concurrency, "name-prefix", o.namePrefix)
+
+	o.clusterNames = make([]string, 0, o.join)
+	for i := 1; i <= o.join; i++ {
+		o.clusterNames = append(o.clusterNames, fmt.Sprintf("%s-%d", o.namePrefix, i))
+	}
+	return nil
+}
+
+func (o *Options) validate() error {
+	if o.join < 1 {
+		return fmt.Errorf("--join should be at least 1")
+	}
+	if o.concurrency < 1 {
+		return fmt.Errorf("--concurrency should be at least 1")
+	}
+	if len(o.namePrefix) == 0 {
+		return fmt.Errorf("--name-prefix should not be empty")
+	}
+	return o.ClusteradmFlags.ValidateHub()
+}
+
+func (o *Options) run() error {
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "%d simulated managed clusters %s-1..%s-%d would be registered with %d concurrent requests\n",
+			o.join, o.namePrefix, o.namePrefix, o.join, o.concurrency)
+		return nil
+	}
+
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	applyLatencies, acceptLatencies, elapsed, errs := o.register(clusterClient)
+	printReport(o.Streams.Out, o.join, elapsed, applyLatencies, acceptLatencies)
+
+	if !o.skipCleanup {
+		if err := o.cleanup(clusterClient); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// register creates the simulated managed clusters with the configured concurrency,
+// it returns the apply and acceptance latencies of the registered clusters.
+func (o *Options) register(clusterClient clusterclientset.Interface) (applyLatencies, acceptLatencies []time.Duration, elapsed time.Duration, errs []error) {
+	names := make(chan string)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for i := 0; i < o.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				applyLatency, acceptLatency, err := o.registerCluster(clusterClient, name)
+				lock.Lock()
+				if err != nil {
+					errs = append(errs, err)
+				}
+				if applyLatency > 0 {
+					applyLatencies = append(applyLatencies, applyLatency)
+				}
+				if acceptLatency > 0 {
+					acceptLatencies = append(acceptLatencies, acceptLatency)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, name := range o.clusterNames {
+		names <- name
+	}
+	close(names)
+	wg.Wait()
+
+	return applyLatencies, acceptLatencies, time.Since(start), errs
+}
+
+// registerCluster creates an accepted managed cluster and waits for the hub to accept it.
+func (o *Options) registerCluster(clusterClient clusterclientset.Interface, name string) (applyLatency, acceptLatency time.Duration, err error) {
+	start := time.Now()
+	_, err = clusterClient.ClusterV1().ManagedClusters().Create(context.TODO(), &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{benchLabel: o.namePrefix},
+		},
+		Spec: clusterv1.ManagedClusterSpec{
+			HubAcceptsClient: true,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create the managed cluster %s: %v", name, err)
+	}
+	applyLatency = time.Since(start)
+
+	err = wait.PollImmediate(500*time.Millisecond, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		mc, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionHubAccepted), nil
+	})
+	if err != nil {
+		return applyLatency, 0, fmt.Errorf("the managed cluster %s is not accepted by the hub: %v", name, err)
+	}
+	return applyLatency, time.Since(start), nil
+}
+
+// cleanup deletes the simulated managed clusters, the hub removes their namespaces
+func (o *Options) cleanup(clusterClient clusterclientset.Interface) error {
+	fmt.Fprintf(o.Streams.Out, "Deleting the simulated managed clusters...\n")
+	return clusterClient.ClusterV1().ManagedClusters().DeleteCollection(context.TODO(), metav1.DeleteOptions{},
+		metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", benchLabel, o.namePrefix)})
+}
@@ -0,0 +1,34 @@
+// Copyright Contributors to the Open Cluster Management project
+package bench
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	//The number of simulated managed clusters to register
+	join int
+	//The number of managed clusters registered concurrently
+	concurrency int
+	//The prefix of the simulated managed cluster names
+	namePrefix string
+	//If true the simulated managed clusters are kept after the benchmark
+	skipCleanup bool
+
+	//The names of the simulated managed clusters
+	clusterNames []string
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+package bench
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// histogramBuckets are the upper bounds of the latency histogram buckets, the last bucket is unbounded
+var histogramBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// histogramWidth is the width of the bar of the most populated bucket
+const histogramWidth = 40
+
+// percentile returns the latency below which the percentage p of the sorted latencies falls
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted))*p/100+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+// histogram counts the latencies per bucket, the last count is for the latencies above the last bucket
+func histogram(latencies []time.Duration) []int {
+	counts := make([]int, len(histogramBuckets)+1)
+	for _, latency := range latencies {
+		i := sort.Search(len(histogramBuckets), func(i int) bool { return latency <= histogramBuckets[i] })
+		counts[i]++
+	}
+	return counts
+}
+
+func printReport(w io.Writer, total int, elapsed time.Duration, applyLatencies, acceptLatencies []time.Duration) {
+	fmt.Fprintf(w, "\nRegistered %d/%d managed clusters in %s", len(acceptLatencies), total, elapsed.Round(time.Millisecond))
+	if elapsed > 0 {
+		fmt.Fprintf(w, " (%.2f clusters/s)", float64(len(acceptLatencies))/elapsed.Seconds())
+	}
+	fmt.Fprintf(w, "\n")
+	printLatencies(w, "Apply latency (managed cluster created on the hub)", applyLatencies)
+	printLatencies(w, "Accept latency (managed cluster accepted by the hub)", acceptLatencies)
+}
+
+func printLatencies(w io.Writer, title string, latencies []time.Duration) {
+	fmt.Fprintf(w, "\n%s:\n", title)
+	if len(latencies) == 0 {
+		fmt.Fprintf(w, "  no samples\n")
+		return
+	}
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	fmt.Fprintf(w, "  min=%s p50=%s p90=%s p99=%s max=%s\n",
+		sorted[0].Round(time.Millisecond),
+		percentile(sorted, 50).Round(time.Millisecond),
+		percentile(sorted, 90).Round(time.Millisecond),
+		percentile(sorted, 99).Round(time.Millisecond),
+		sorted[len(sorted)-1].Round(time.Millisecond))
+
+	counts := histogram(sorted)
+	maxCount := 0
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+	for i, count := range counts {
+		label := "+Inf"
+		if i < len(histogramBuckets) {
+			label = histogramBuckets[i].String()
+		}
+		fmt.Fprintf(w, "  <= %-6s %6d %s\n", label, count, strings.Repeat("#", count*histogramWidth/maxCount))
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright Contributors to the Open Cluster Management project
+package bench
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	cases := map[float64]time.Duration{
+		50: 50 * time.Millisecond,
+		90: 90 * time.Millisecond,
+		99: 99 * time.Millisecond,
+	}
+	for p, expected := range cases {
+		if got := percentile(sorted, p); got != expected {
+			t.Errorf("p%v: expected %s, got %s", p, expected, got)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 without samples, got %s", got)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	counts := histogram([]time.Duration{
+		10 * time.Millisecond,
+		50 * time.Millisecond,
+		300 * time.Millisecond,
+		time.Minute,
+	})
+	expected := []int{2, 0, 0, 1, 0, 0, 0, 0, 0, 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected %v, got %v", expected, counts)
+	}
+}
+
+func TestPrintReport(t *testing.T) {
+	out := &bytes.Buffer{}
+	printReport(out, 3, 2*time.Second, []time.Duration{time.Millisecond, 2 * time.Millisecond}, nil)
+	for _, expected := range []string{"Registered 0/3 managed clusters in 2s", "min=1ms", "no samples"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in the report:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright Contributors to the Open Cluster Management project
+package dev
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/dev/bench"
+	"open-cluster-management.io/clusteradm/pkg/cmd/dev/env"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the developer helper commands
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "helper commands for addon and OCM developers",
+	}
+
+	cmd.AddCommand(env.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(bench.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
@@ -0,0 +1,55 @@
+// Copyright Contributors to the Open Cluster Management project
+package env
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Create a hub and two managed clusters with kind, and register them to the hub
+%[1]s dev env --clusters 3
+# Create the environment with a custom cluster name prefix and kind node image
+%[1]s dev env --clusters 2 --name-prefix ocm --kind-image kindest/node:v1.24.0
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "bootstrap a local multi-cluster environment",
+		Long: "create kind clusters, initialize the first one as the hub and join/accept the others as managed clusters, " +
+			"producing a ready multi-cluster sandbox with the kubeconfig contexts configured",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&o.clusters, "clusters", 3, "The total number of kind clusters to create, including the hub")
+	cmd.Flags().StringVar(&o.namePrefix, "name-prefix", "ocm-dev", "The prefix of the kind cluster names")
+	cmd.Flags().StringVar(&o.kindImage, "kind-image", "", "The kind node image used to create the clusters, defaulted to the kind built-in image")
+	cmd.Flags().StringVar(&o.bundleVersion, "bundle-version", "default", "version of predefined compatible image versions")
+	cmd.Flags().StringVar(&o.registry, "image-registry", "quay.io/open-cluster-management", "The name of the image registry serving OCM images.")
+	return cmd
+}
@@ -0,0 +1,172 @@
+// Copyright Contributors to the Open Cluster Management project
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+)
+
+const kindBinary = "kind"
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("dev env options:", "dry-run", o.ClusteradmFlags.DryRun, "clusters", o.clusters, "name-prefix", o.namePrefix)
+
+	o.hubName = fmt.Sprintf("%s-hub", o.namePrefix)
+	o.managedClusterNames = make([]string, 0, o.clusters)
+	for i := 1; i < o.clusters; i++ {
+		o.managedClusterNames = append(o.managedClusterNames, fmt.Sprintf("%s-c%d", o.namePrefix, i))
+	}
+	return nil
+}
+
+func (o *Options) validate() error {
+	if o.clusters < 2 {
+		return fmt.Errorf("--clusters should be at least 2, one hub and one managed cluster")
+	}
+	if len(o.namePrefix) == 0 {
+		return fmt.Errorf("--name-prefix should not be empty")
+	}
+	if _, err := exec.LookPath(kindBinary); err != nil && !o.ClusteradmFlags.DryRun {
+		return fmt.Errorf("%s is required to bootstrap the environment, see https://kind.sigs.k8s.io/docs/user/quick-start/#installation: %v", kindBinary, err)
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	// create the kind clusters
+	existing, err := o.existingKindClusters()
+	if err != nil {
+		return err
+	}
+	for _, name := range append([]string{o.hubName}, o.managedClusterNames...) {
+		if existing.Has(name) {
+			fmt.Fprintf(o.Streams.Out, "kind cluster %s already exists, reusing it\n", name)
+			continue
+		}
+		args := []string{"create", "cluster", "--name", name}
+		if len(o.kindImage) > 0 {
+			args = append(args, "--image", o.kindImage)
+		}
+		if err := o.execute(kindBinary, args, nil); err != nil {
+			return fmt.Errorf("failed to create kind cluster %s: %v", name, err)
+		}
+	}
+
+	// initialize the hub
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	hubInfo := clusteradmjson.HubInfo{
+		HubToken:     "<hub_token>",
+		HubApiserver: "<hub_apiserver>",
+	}
+	initOutput := &bytes.Buffer{}
+	if err := o.execute(self, []string{
+		"init",
+		"--context", kindContext(o.hubName),
+		"--bundle-version", o.bundleVersion,
+		"--image-registry", o.registry,
+		"--timeout", strconv.Itoa(o.ClusteradmFlags.Timeout),
+		"--output", "json",
+	}, initOutput); err != nil {
+		return fmt.Errorf("failed to initialize the hub %s: %v", o.hubName, err)
+	}
+	if !o.ClusteradmFlags.DryRun {
+		if err := json.Unmarshal(initOutput.Bytes(), &hubInfo); err != nil {
+			return fmt.Errorf("failed to parse the output of init: %v", err)
+		}
+	}
+
+	// join the managed clusters, the kind clusters reach the hub through its
+	// in-cluster endpoint instead of the localhost address in the kubeconfig.
+	for _, name := range o.managedClusterNames {
+		if err := o.execute(self, []string{
+			"join",
+			"--context", kindContext(name),
+			"--hub-token", hubInfo.HubToken,
+			"--hub-apiserver", hubInfo.HubApiserver,
+			"--cluster-name", name,
+			"--bundle-version", o.bundleVersion,
+			"--image-registry", o.registry,
+			"--timeout", strconv.Itoa(o.ClusteradmFlags.Timeout),
+			"--force-internal-endpoint-lookup",
+			"--wait",
+		}, nil); err != nil {
+			return fmt.Errorf("failed to join cluster %s: %v", name, err)
+		}
+	}
+
+	// accept the managed clusters on the hub
+	if err := o.execute(self, []string{
+		"accept",
+		"--context", kindContext(o.hubName),
+		"--clusters", strings.Join(o.managedClusterNames, ","),
+		"--timeout", strconv.Itoa(o.ClusteradmFlags.Timeout),
+		"--wait",
+	}, nil); err != nil {
+		return fmt.Errorf("failed to accept clusters %v: %v", o.managedClusterNames, err)
+	}
+	if o.ClusteradmFlags.DryRun {
+		return nil
+	}
+
+	fmt.Fprintf(o.Streams.Out, "\nThe multi-cluster environment is ready. The kubeconfig contexts are:\n\n")
+	fmt.Fprintf(o.Streams.Out, "    hub:\t%s\n", kindContext(o.hubName))
+	for _, name := range o.managedClusterNames {
+		fmt.Fprintf(o.Streams.Out, "    %s:\t%s\n", name, kindContext(name))
+	}
+	fmt.Fprintf(o.Streams.Out, "\nRun the following command to delete the environment:\n\n    %s delete clusters %s %s\n\n",
+		kindBinary, o.hubName, strings.Join(o.managedClusterNames, " "))
+	return nil
+}
+
+// existingKindClusters returns the names of the kind clusters already created
+func (o *Options) existingKindClusters() (sets.String, error) {
+	clusters := sets.NewString()
+	if o.ClusteradmFlags.DryRun {
+		return clusters, nil
+	}
+	out, err := exec.Command(kindBinary, "get", "clusters").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kind clusters: %v", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if name := strings.TrimSpace(line); len(name) > 0 {
+			clusters.Insert(name)
+		}
+	}
+	return clusters, nil
+}
+
+// execute runs the command, if stdout is nil the output of the command is
+// redirected to the output stream. In dry-run mode the command is only printed.
+func (o *Options) execute(name string, args []string, stdout *bytes.Buffer) error {
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "%s %s\n", name, strings.Join(args, " "))
+		return nil
+	}
+	klog.V(1).InfoS("running:", "command", name, "args", args)
+	c := exec.Command(name, args...)
+	c.Stdin = o.Streams.In
+	c.Stderr = o.Streams.ErrOut
+	c.Stdout = o.Streams.Out
+	if stdout != nil {
+		c.Stdout = stdout
+	}
+	return c.Run()
+}
+
+func kindContext(name string) string {
+	return fmt.Sprintf("kind-%s", name)
+}
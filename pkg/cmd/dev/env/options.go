@@ -0,0 +1,38 @@
+// Copyright Contributors to the Open Cluster Management project
+package env
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	//The total number of clusters, the first one is the hub
+	clusters int
+	//The prefix of the kind cluster names
+	namePrefix string
+	//The kind node image
+	kindImage string
+	//version of predefined compatible image versions
+	bundleVersion string
+	//Pulling image registry of OCM
+	registry string
+
+	//The name of the hub kind cluster
+	hubName string
+	//The names of the managed kind clusters
+	managedClusterNames []string
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright Contributors to the Open Cluster Management project
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+)
+
+const (
+	clientGoModule = "k8s.io/client-go"
+	pluginPrefix   = "clusteradm-"
+	dialTimeout    = 10 * time.Second
+)
+
+// KubeconfigCheck verifies the contexts of the kubeconfig and of the hubs added by 'hub add' resolve to a
+// cluster and a user, and the files they reference exist
+type KubeconfigCheck struct {
+	Config *clientcmdapi.Config
+	// HubsPath is the file of the hubs, it is skipped if it does not exist
+	HubsPath string
+}
+
+func (c KubeconfigCheck) Check() (warningList []string, errorList []error) {
+	if c.Config == nil || len(c.Config.Contexts) == 0 {
+		return nil, []error{fmt.Errorf("no kubeconfig context is found, set KUBECONFIG or --kubeconfig")}
+	}
+	if len(c.Config.CurrentContext) == 0 {
+		warningList = append(warningList, "the kubeconfig has no current context, the commands require --context")
+	} else if _, ok := c.Config.Contexts[c.Config.CurrentContext]; !ok {
+		errorList = append(errorList, fmt.Errorf("the current context %s does not exist", c.Config.CurrentContext))
+	}
+	errorList = append(errorList, contextErrors(c.Config)...)
+
+	config, err := hubs.Load(c.HubsPath)
+	if err != nil {
+		return warningList, append(errorList, fmt.Errorf("the hubs of %s are not readable: %v", c.HubsPath, err))
+	}
+	for _, hub := range config.Hubs {
+		if len(hub.Kubeconfig) == 0 {
+			continue
+		}
+		hubConfig, err := clientcmd.LoadFromFile(hub.Kubeconfig)
+		if err != nil {
+			errorList = append(errorList, fmt.Errorf("the kubeconfig %s of hub %s is not readable: %v", hub.Kubeconfig, hub.Name, err))
+			continue
+		}
+		context := hub.Context
+		if len(context) == 0 {
+			context = hubConfig.CurrentContext
+		}
+		if _, ok := hubConfig.Contexts[context]; !ok {
+			errorList = append(errorList, fmt.Errorf("the context %q of hub %s does not exist in %s", context, hub.Name, hub.Kubeconfig))
+		}
+	}
+	return warningList, errorList
+}
+
+func (c KubeconfigCheck) Name() string {
+	return "Kubeconfig check"
+}
+
+// contextErrors returns the contexts referencing a missing cluster or user, and the missing files of the
+// clusters and users
+func contextErrors(config *clientcmdapi.Config) []error {
+	errs := []error{}
+	for _, name := range sortedKeys(config.Contexts) {
+		context := config.Contexts[name]
+		if _, ok := config.Clusters[context.Cluster]; !ok {
+			errs = append(errs, fmt.Errorf("the cluster %q of context %s does not exist", context.Cluster, name))
+		}
+		if _, ok := config.AuthInfos[context.AuthInfo]; len(context.AuthInfo) > 0 && !ok {
+			errs = append(errs, fmt.Errorf("the user %q of context %s does not exist", context.AuthInfo, name))
+		}
+	}
+	for _, name := range sortedKeys(config.Clusters) {
+		if err := fileExists(config.Clusters[name].CertificateAuthority); err != nil {
+			errs = append(errs, fmt.Errorf("the certificate authority of cluster %s: %v", name, err))
+		}
+	}
+	for _, name := range sortedKeys(config.AuthInfos) {
+		user := config.AuthInfos[name]
+		for _, file := range []string{user.ClientCertificate, user.ClientKey, user.TokenFile} {
+			if err := fileExists(file); err != nil {
+				errs = append(errs, fmt.Errorf("the credentials of user %s: %v", name, err))
+			}
+		}
+	}
+	return errs
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch m := m.(type) {
+	case map[string]*clientcmdapi.Context:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*clientcmdapi.Cluster:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*clientcmdapi.AuthInfo:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fileExists(path string) error {
+	if len(path) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s is not readable", path)
+	}
+	return nil
+}
+
+// VersionSkewCheck verifies the Kubernetes version of the client libraries of clusteradm is within one minor
+// version of the API server, as for kubectl
+type VersionSkewCheck struct {
+	Discovery discovery.ServerVersionInterface
+}
+
+func (c VersionSkewCheck) Check() (warningList []string, errorList []error) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return []string{"the version of the client libraries is not known"}, nil
+	}
+	client, ok := clientMinor(info)
+	if !ok {
+		return []string{"the version of the client libraries is not known"}, nil
+	}
+	serverVersion, err := c.Discovery.ServerVersion()
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to get the version of the API server: %v", err)}
+	}
+	server, err := strconv.Atoi(strings.TrimSuffix(serverVersion.Minor, "+"))
+	if err != nil || serverVersion.Major != "1" {
+		return []string{fmt.Sprintf("the version %s of the API server is not known", serverVersion.GitVersion)}, nil
+	}
+	if skew := server - client; skew > 1 || skew < -1 {
+		warningList = append(warningList, fmt.Sprintf("clusteradm is built with the client libraries of Kubernetes 1.%d, "+
+			"the API server runs %s, only one minor version of skew is supported", client, serverVersion.GitVersion))
+	}
+	return warningList, nil
+}
+
+func (c VersionSkewCheck) Name() string {
+	return "VersionSkew check"
+}
+
+// clientMinor returns the Kubernetes minor version of the client-go module of the build
+func clientMinor(info *debug.BuildInfo) (int, bool) {
+	for _, dep := range info.Deps {
+		if dep.Path != clientGoModule {
+			continue
+		}
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Version
+		}
+		// client-go v0.x.y is Kubernetes 1.x
+		parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+		if len(parts) < 2 || parts[0] != "0" {
+			return 0, false
+		}
+		minor, err := strconv.Atoi(parts[1])
+		return minor, err == nil
+	}
+	return 0, false
+}
+
+// ReachabilityCheck dials the hosts the commands connect to besides the API server, e.g. the image registry,
+// through the proxy of the environment if any
+type ReachabilityCheck struct {
+	// Addresses are the host:port to dial
+	Addresses []string
+	// Proxy returns the proxy of a request, defaults to the proxy of the environment
+	Proxy func(*http.Request) (*url.URL, error)
+}
+
+func (c ReachabilityCheck) Check() (warningList []string, errorList []error) {
+	proxy := c.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	for _, address := range c.Addresses {
+		dialed, via := address, ""
+		if proxyURL, err := proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: address}}); err == nil && proxyURL != nil {
+			dialed, via = proxyURL.Host, " through the proxy "+proxyURL.Host
+		}
+		conn, err := net.DialTimeout("tcp", dialed, dialTimeout)
+		if err != nil {
+			errorList = append(errorList, fmt.Errorf("%s is not reachable%s, a proxy or a firewall rule may be required: %v", address, via, err))
+			continue
+		}
+		conn.Close()
+	}
+	return nil, errorList
+}
+
+func (c ReachabilityCheck) Name() string {
+	return "Reachability check"
+}
+
+// CacheCheck verifies the entries of the hub metadata cache are valid, the invalid entries are ignored by the
+// commands and refreshed from the hub on each run
+type CacheCheck struct {
+	Dir string
+}
+
+func (c CacheCheck) Check() (warningList []string, errorList []error) {
+	if len(c.Dir) == 0 {
+		return nil, nil
+	}
+	hubsDir := filepath.Join(c.Dir, "hubs")
+	err := filepath.Walk(hubsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		entry := struct {
+			Timestamp time.Time       `json:"timestamp"`
+			Value     json.RawMessage `json:"value"`
+		}{}
+		if err := json.Unmarshal(data, &entry); err != nil || entry.Timestamp.IsZero() {
+			warningList = append(warningList, fmt.Sprintf("the cache entry %s is invalid, delete it or run with --no-cache", path))
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		errorList = append(errorList, fmt.Errorf("the cache %s is not readable: %v", hubsDir, err))
+	}
+	return warningList, errorList
+}
+
+func (c CacheCheck) Name() string {
+	return "Cache check"
+}
+
+// PluginCheck verifies the clusteradm plugins of the PATH are executable and not shadowed by another
+// plugin of the same name earlier in the PATH
+type PluginCheck struct {
+	Path string
+}
+
+func (c PluginCheck) Check() (warningList []string, errorList []error) {
+	seen := map[string]string{}
+	for _, dir := range filepath.SplitList(c.Path) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), pluginPrefix) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if first, ok := seen[entry.Name()]; ok {
+				warningList = append(warningList, fmt.Sprintf("the plugin %s is shadowed by %s", path, first))
+				continue
+			}
+			seen[entry.Name()] = path
+			info, err := os.Stat(path)
+			if err != nil {
+				errorList = append(errorList, fmt.Errorf("the plugin %s is not readable: %v", path, err))
+				continue
+			}
+			if info.Mode()&0111 == 0 {
+				warningList = append(warningList, fmt.Sprintf("the plugin %s is not executable", path))
+			}
+		}
+	}
+	return warningList, errorList
+}
+
+func (c PluginCheck) Name() string {
+	return "Plugin check"
+}
+
+// WriteAccessCheck verifies the directories the commands write their output files and cache to are writable
+type WriteAccessCheck struct {
+	Dirs []string
+}
+
+func (c WriteAccessCheck) Check() (warningList []string, errorList []error) {
+	for _, dir := range c.Dirs {
+		if len(dir) == 0 {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			errorList = append(errorList, fmt.Errorf("the directory %s can not be created: %v", dir, err))
+			continue
+		}
+		f, err := os.CreateTemp(dir, ".clusteradm-doctor-")
+		if err != nil {
+			errorList = append(errorList, fmt.Errorf("the directory %s is not writable: %v", dir, err))
+			continue
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+	return nil, errorList
+}
+
+func (c WriteAccessCheck) Name() string {
+	return "WriteAccess check"
+}
@@ -0,0 +1,193 @@
+// Copyright Contributors to the Open Cluster Management project
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestKubeconfigCheck(t *testing.T) {
+	dir := t.TempDir()
+	hubKubeconfig := filepath.Join(dir, "hub.kubeconfig")
+	if err := os.WriteFile(hubKubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters: [{name: hub, cluster: {server: "https://hub:6443"}}]
+users: [{name: admin, user: {token: abc}}]
+contexts: [{name: hub, context: {cluster: hub, user: admin}}]
+current-context: hub
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	hubsPath := filepath.Join(dir, "hubs.yaml")
+	if err := os.WriteFile(hubsPath, []byte(fmt.Sprintf(`hubs:
+- name: prod
+  kubeconfig: %s
+- name: dev
+  kubeconfig: %s
+  context: dev
+`, hubKubeconfig, hubKubeconfig)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters["hub"] = &clientcmdapi.Cluster{Server: "https://hub:6443", CertificateAuthority: filepath.Join(dir, "missing-ca.crt")}
+	config.AuthInfos["admin"] = &clientcmdapi.AuthInfo{Token: "abc"}
+	config.Contexts["hub"] = &clientcmdapi.Context{Cluster: "hub", AuthInfo: "admin"}
+	config.Contexts["stale"] = &clientcmdapi.Context{Cluster: "deleted", AuthInfo: "admin"}
+	config.CurrentContext = "hub"
+
+	_, errs := KubeconfigCheck{Config: config, HubsPath: hubsPath}.Check()
+	expected := []string{
+		`the cluster "deleted" of context stale does not exist`,
+		"missing-ca.crt is not readable",
+		`the context "dev" of hub dev does not exist`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range expected {
+		if !strings.Contains(errs[i].Error(), e) {
+			t.Errorf("expected %q in %q", e, errs[i])
+		}
+	}
+
+	if _, errs := (KubeconfigCheck{Config: clientcmdapi.NewConfig(), HubsPath: hubsPath}).Check(); len(errs) != 1 {
+		t.Errorf("expected an error without context, got %v", errs)
+	}
+}
+
+func TestClientMinor(t *testing.T) {
+	info := &debug.BuildInfo{Deps: []*debug.Module{
+		{Path: "k8s.io/api", Version: "v0.25.0"},
+		{Path: clientGoModule, Version: "v0.25.0", Replace: &debug.Module{Path: clientGoModule, Version: "v0.23.5"}},
+	}}
+	if minor, ok := clientMinor(info); !ok || minor != 23 {
+		t.Errorf("expected the minor of the replaced module 23, got %d %v", minor, ok)
+	}
+	if _, ok := clientMinor(&debug.BuildInfo{}); ok {
+		t.Errorf("expected no version without client-go")
+	}
+}
+
+func TestVersionSkewCheck(t *testing.T) {
+	discovery := fakekube.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{Major: "1", Minor: "5", GitVersion: "v1.5.0"}
+	warnings, errs := VersionSkewCheck{Discovery: discovery}.Check()
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	// the test binary may not carry the module versions
+	if _, ok := debug.ReadBuildInfo(); ok && len(warnings) != 1 {
+		t.Errorf("expected a skew warning, got %v", warnings)
+	}
+}
+
+func TestReachabilityCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddress := closed.Addr().String()
+	closed.Close()
+
+	noProxy := func(*http.Request) (*url.URL, error) { return nil, nil }
+	_, errs := ReachabilityCheck{Addresses: []string{listener.Addr().String(), closedAddress}, Proxy: noProxy}.Check()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), closedAddress) {
+		t.Errorf("expected the closed address to be unreachable, got %v", errs)
+	}
+
+	// the registry is dialed through the proxy
+	proxy := func(*http.Request) (*url.URL, error) {
+		return &url.URL{Scheme: "http", Host: listener.Addr().String()}, nil
+	}
+	if _, errs := (ReachabilityCheck{Addresses: []string{closedAddress}, Proxy: proxy}).Check(); len(errs) > 0 {
+		t.Errorf("expected the address to be reachable through the proxy, got %v", errs)
+	}
+}
+
+func TestCacheCheck(t *testing.T) {
+	dir := t.TempDir()
+	hubDir := filepath.Join(dir, "hubs", "https___hub_6443")
+	if err := os.MkdirAll(hubDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hubDir, "server-version.json"), []byte(`{"timestamp":"2022-06-01T00:00:00Z","value":{}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hubDir, "clusters.json"), []byte(`{"timest`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	warnings, errs := CacheCheck{Dir: dir}.Check()
+	if len(errs) > 0 || len(warnings) != 1 || !strings.Contains(warnings[0], "clusters.json") {
+		t.Errorf("expected a warning for the invalid entry, got %v %v", warnings, errs)
+	}
+	if warnings, errs := (CacheCheck{Dir: filepath.Join(dir, "missing")}).Check(); len(warnings) > 0 || len(errs) > 0 {
+		t.Errorf("expected a missing cache to be valid, got %v %v", warnings, errs)
+	}
+}
+
+func TestPluginCheck(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	for path, mode := range map[string]os.FileMode{
+		filepath.Join(first, "clusteradm-foo"):  0755,
+		filepath.Join(first, "clusteradm-bar"):  0644,
+		filepath.Join(second, "clusteradm-foo"): 0755,
+		filepath.Join(second, "kubectl-foo"):    0755,
+	} {
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	warnings, errs := PluginCheck{Path: strings.Join([]string{first, second}, string(os.PathListSeparator))}.Check()
+	if len(errs) > 0 || len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v %v", warnings, errs)
+	}
+	if !strings.Contains(strings.Join(warnings, "\n"), "clusteradm-bar is not executable") ||
+		!strings.Contains(strings.Join(warnings, "\n"), "is shadowed by "+filepath.Join(first, "clusteradm-foo")) {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestWriteAccessCheck(t *testing.T) {
+	dir := t.TempDir()
+	readOnly := filepath.Join(dir, "readonly")
+	if err := os.Mkdir(readOnly, 0500); err != nil {
+		t.Fatal(err)
+	}
+	_, errs := WriteAccessCheck{Dirs: []string{filepath.Join(dir, "new"), readOnly}}.Check()
+	// root can write to any directory
+	if os.Geteuid() != 0 && len(errs) != 1 {
+		t.Errorf("expected the read only directory to fail, got %v", errs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new")); err != nil {
+		t.Errorf("expected the directory to be created: %v", err)
+	}
+}
+
+func TestRegistryAddress(t *testing.T) {
+	for registry, expected := range map[string]string{
+		"quay.io/open-cluster-management": "quay.io:443",
+		"registry.local:5000/ocm":         "registry.local:5000",
+	} {
+		if got := registryAddress(registry); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	}
+}
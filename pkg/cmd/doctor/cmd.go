@@ -0,0 +1,53 @@
+// Copyright Contributors to the Open Cluster Management project
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Check the local environment before running the commands against the hub
+%[1]s doctor
+# Check the registry of the images and the directory of the output files are reachable and writable too
+%[1]s doctor --image-registry registry.example.com/ocm --output-dir ./bundles
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "check the local environment of clusteradm",
+		Long: "check the local environment of clusteradm, so the workstation issues are not mistaken for cluster problems: " +
+			"the kubeconfig contexts and the hubs resolve, the client libraries support the version of the API server, " +
+			"the API server and the image registry are reachable, the cache and the plugins are valid and the output directories are writable. " +
+			"The OCM installations of the cluster of the context conflicting with each other are reported too.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.registry, "image-registry", "quay.io/open-cluster-management", "The name of the image registry serving OCM images, checked to be reachable")
+	cmd.Flags().StringSliceVar(&o.outputDirs, "output-dir", []string{"."}, "The directories the output files of the commands are written to, checked to be writable")
+
+	return cmd
+}
@@ -0,0 +1,105 @@
+// Copyright Contributors to the Open Cluster Management project
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	joinpreflight "open-cluster-management.io/clusteradm/pkg/cmd/join/preflight"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+	preflightinterface "open-cluster-management.io/clusteradm/pkg/helpers/preflight"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("doctor options:", "image-registry", o.registry, "output-dir", o.outputDirs)
+	return nil
+}
+
+func (o *Options) validate() error {
+	if len(o.registry) == 0 {
+		return fmt.Errorf("--image-registry should not be empty")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	f := o.ClusteradmFlags.KubectlFactory
+	hubsPath, err := hubs.DefaultPath()
+	if err != nil {
+		return err
+	}
+	rawConfig, err := f.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		klog.V(2).InfoS("the kubeconfig is not readable", "error", err)
+	}
+	checks := []preflightinterface.Checker{
+		KubeconfigCheck{Config: &rawConfig, HubsPath: hubsPath},
+	}
+
+	// the checks of the API server require a kubeconfig, the client falls back to localhost otherwise
+	if restConfig, err := f.ToRESTConfig(); err == nil && (len(rawConfig.Contexts) > 0 || o.ClusteradmFlags.InCluster) {
+		caData := restConfig.CAData
+		if len(caData) == 0 && len(restConfig.CAFile) > 0 {
+			caData, _ = os.ReadFile(restConfig.CAFile)
+		}
+		checks = append(checks, joinpreflight.HubConnectivityCheck{Server: restConfig.Host, CAData: caData})
+		if discoveryClient, err := f.ToDiscoveryClient(); err == nil {
+			checks = append(checks, VersionSkewCheck{Discovery: discoveryClient})
+		}
+		// the installations of OCM of the cluster of the context conflicting with each other
+		if kubeClient, apiExtensionsClient, _, err := helpers.GetClients(f); err == nil {
+			checks = append(checks, attach.ConflictCheck{KubeClient: kubeClient, APIExtensionsClient: apiExtensionsClient})
+		}
+	}
+
+	cacheDir := ""
+	if o.ClusteradmFlags.CacheDir != nil {
+		cacheDir = *o.ClusteradmFlags.CacheDir
+	}
+	checks = append(checks,
+		ReachabilityCheck{Addresses: []string{registryAddress(o.registry)}},
+		CacheCheck{Dir: cacheDir},
+		PluginCheck{Path: os.Getenv("PATH")},
+		WriteAccessCheck{Dirs: append(append([]string{}, o.outputDirs...), cacheDir)},
+	)
+
+	if failed := printResults(o.Streams.Out, preflightinterface.Run(checks)); failed > 0 {
+		return fmt.Errorf("%d checks of the local environment failed", failed)
+	}
+	return nil
+}
+
+// registryAddress returns the host:port of the registry of the images
+func registryAddress(registry string) string {
+	host := strings.SplitN(registry, "/", 2)[0]
+	if !strings.Contains(host, ":") {
+		host += ":443"
+	}
+	return host
+}
+
+// printResults prints a line per check and its warnings and errors, and returns the number of failed checks
+func printResults(out io.Writer, results []preflightinterface.Result) int {
+	failed := 0
+	for _, result := range results {
+		if len(result.Errors) == 0 && len(result.Warnings) == 0 {
+			fmt.Fprintf(out, "[OK %s]\n", result.Name)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(out, "[WARNING %s]: %s\n", result.Name, warning)
+		}
+		for _, err := range result.Errors {
+			fmt.Fprintf(out, "[ERROR %s]: %s\n", result.Name, err)
+		}
+		if len(result.Errors) > 0 {
+			failed++
+		}
+	}
+	return failed
+}
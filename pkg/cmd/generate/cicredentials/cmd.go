@@ -0,0 +1,66 @@
+// Copyright Contributors to the Open Cluster Management project
+package cicredentials
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Generate the kubeconfig of a pipeline accepting the clusters and reading them
+%[1]s generate ci-credentials --operations accept,get --output sa-kubeconfig.yaml
+# Generate the kubeconfig of a serviceaccount deploying works, valid for 30 days
+%[1]s generate ci-credentials --name deployer --operations "create work,delete work" --expiration 720h --output deployer.kubeconfig
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "ci-credentials",
+		Short: "generate the kubeconfig of a serviceaccount allowed to run clusteradm operations on the hub",
+		Long: "create a serviceaccount on the hub with a clusterrole covering exactly the requested clusteradm operations, " +
+			"and write its kubeconfig, so the pipelines do not run with cluster-admin. The known operations are " +
+			strings.Join(rbac.Operations(), ", ") + ".",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.operations, "operations", []string{}, "The clusteradm operations the serviceaccount runs (comma separated), e.g. accept,get")
+	cmd.Flags().StringVar(&o.name, "name", "clusteradm-ci", "The name of the serviceaccount and its rbac")
+	cmd.Flags().StringVar(&o.namespace, "namespace", "open-cluster-management", "The namespace of the serviceaccount")
+	cmd.Flags().StringVar(&o.outputFile, "output", "", "The file the kubeconfig is written to, it is printed if not set")
+	cmd.Flags().StringVar(&o.hubAPIServer, "hub-apiserver", "", "The api server url of the hub in the kubeconfig, defaults to the server of the current context")
+	cmd.Flags().DurationVar(&o.expiration, "expiration", 365*24*time.Hour, "The requested validity of the token of the kubeconfig, "+
+		"the hub may issue a token of a shorter validity")
+
+	return cmd
+}
@@ -0,0 +1,196 @@
+// Copyright Contributors to the Open Cluster Management project
+package cicredentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	operations := []string{}
+	for _, operation := range o.operations {
+		if operation = strings.Join(strings.Fields(operation), " "); len(operation) > 0 {
+			operations = append(operations, operation)
+		}
+	}
+	o.operations = operations
+	klog.V(1).InfoS("generate ci-credentials options:", "operations", o.operations, "name", o.name, "namespace", o.namespace,
+		"output", o.outputFile, "hub-apiserver", o.hubAPIServer, "expiration", o.expiration)
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(o.operations) == 0 {
+		return fmt.Errorf("the clusteradm operations must be specified with --operations, the known operations are %s",
+			strings.Join(rbac.Operations(), ", "))
+	}
+	if o.rules, err = rbac.Rules(o.operations); err != nil {
+		return err
+	}
+	if errs := validation.IsDNS1123Subdomain(o.name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %v", o.name, errs)
+	}
+	if errs := validation.IsDNS1123Label(o.namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %v", o.namespace, errs)
+	}
+	if o.expiration < 10*time.Minute {
+		return fmt.Errorf("--expiration should be at least 10m")
+	}
+
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, _, _, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+
+	if len(o.hubAPIServer) == 0 {
+		o.hubAPIServer = restConfig.Host
+	}
+	caData, err := helpers.GetHubCA(kubeClient, restConfig)
+	if err != nil {
+		return err
+	}
+
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "serviceaccount %s/%s would be created with the rbac of operations %s\n",
+			o.namespace, o.name, strings.Join(o.operations, ", "))
+		return nil
+	}
+
+	if err := o.applyRBAC(kubeClient); err != nil {
+		return err
+	}
+	token, expiration, err := o.createToken(kubeClient)
+	if err != nil {
+		return err
+	}
+	kubeConfig, err := o.kubeConfig(caData, token)
+	if err != nil {
+		return err
+	}
+
+	if len(o.outputFile) == 0 {
+		_, err := o.Streams.Out.Write(kubeConfig)
+		return err
+	}
+	if err := os.WriteFile(o.outputFile, kubeConfig, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "the kubeconfig of serviceaccount %s/%s is written to %s, its token expires at %s.\n"+
+		"the kubeconfig grants the operations %s on the hub, keep it secret.\n",
+		o.namespace, o.name, o.outputFile, expiration.Format(time.RFC3339), strings.Join(o.operations, ", "))
+	return nil
+}
+
+// applyRBAC creates the serviceaccount and binds it to a clusterrole of the rules of the operations,
+// the rules of an existing clusterrole are replaced.
+func (o *Options) applyRBAC(kubeClient kubernetes.Interface) error {
+	labels := map[string]string{"app.kubernetes.io/name": "clusteradm", "app.kubernetes.io/instance": o.name}
+
+	_, err := kubeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: o.namespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	_, err = kubeClient.CoreV1().ServiceAccounts(o.namespace).Create(context.TODO(), &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: o.name, Namespace: o.namespace, Labels: labels},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	clusterRole, err := kubeClient.RbacV1().ClusterRoles().Get(context.TODO(), o.name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = kubeClient.RbacV1().ClusterRoles().Create(context.TODO(), &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: o.name, Labels: labels},
+			Rules:      o.rules,
+		}, metav1.CreateOptions{})
+	case err != nil:
+		return err
+	default:
+		clusterRole.Rules = o.rules
+		_, err = kubeClient.RbacV1().ClusterRoles().Update(context.TODO(), clusterRole, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: o.name, Namespace: o.namespace}
+	binding, err := kubeClient.RbacV1().ClusterRoleBindings().Get(context.TODO(), o.name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = kubeClient.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: o.name, Labels: labels},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: o.name},
+			Subjects:   []rbacv1.Subject{subject},
+		}, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	case binding.RoleRef.Name != o.name:
+		return fmt.Errorf("clusterrolebinding %s exists and binds clusterrole %s, choose another --name", o.name, binding.RoleRef.Name)
+	}
+	for _, s := range binding.Subjects {
+		if s == subject {
+			return nil
+		}
+	}
+	binding.Subjects = append(binding.Subjects, subject)
+	_, err = kubeClient.RbacV1().ClusterRoleBindings().Update(context.TODO(), binding, metav1.UpdateOptions{})
+	return err
+}
+
+// createToken requests a token of the serviceaccount, the hub may shorten its validity
+func (o *Options) createToken(kubeClient kubernetes.Interface) (string, time.Time, error) {
+	expirationSeconds := int64(o.expiration / time.Second)
+	tr, err := kubeClient.CoreV1().ServiceAccounts(o.namespace).CreateToken(context.TODO(), o.name, &authv1.TokenRequest{
+		Spec: authv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get token from sa %s/%s: %v", o.namespace, o.name, err)
+	}
+	return tr.Status.Token, tr.Status.ExpirationTimestamp.Time, nil
+}
+
+// kubeConfig returns the kubeconfig of the hub authenticating with the token of the serviceaccount
+func (o *Options) kubeConfig(caData []byte, token string) ([]byte, error) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["hub"] = &clientcmdapi.Cluster{Server: o.hubAPIServer, CertificateAuthorityData: caData}
+	config.AuthInfos[o.name] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[o.name] = &clientcmdapi.Context{Cluster: "hub", AuthInfo: o.name, Namespace: o.namespace}
+	config.CurrentContext = o.name
+	return clientcmd.Write(*config)
+}
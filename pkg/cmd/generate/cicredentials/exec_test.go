@@ -0,0 +1,84 @@
+// Copyright Contributors to the Open Cluster Management project
+package cicredentials
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+)
+
+func TestApplyRBAC(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset()
+	o := &Options{operations: []string{"accept", "get"}, name: "clusteradm-ci", namespace: "open-cluster-management"}
+	o.rules, _ = rbac.Rules(o.operations)
+	if err := o.applyRBAC(kubeClient); err != nil {
+		t.Fatal(err)
+	}
+
+	// the rules of the clusterrole are replaced when the operations change
+	o.operations = []string{"get"}
+	o.rules, _ = rbac.Rules(o.operations)
+	if err := o.applyRBAC(kubeClient); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kubeClient.CoreV1().ServiceAccounts(o.namespace).Get(context.TODO(), o.name, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the serviceaccount: %v", err)
+	}
+	clusterRole, err := kubeClient.RbacV1().ClusterRoles().Get(context.TODO(), o.name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(clusterRole.Rules, o.rules) {
+		t.Errorf("expected the rules of get, got %v", clusterRole.Rules)
+	}
+	binding, err := kubeClient.RbacV1().ClusterRoleBindings().Get(context.TODO(), o.name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binding.Subjects) != 1 || binding.Subjects[0].Name != o.name || binding.Subjects[0].Namespace != o.namespace {
+		t.Errorf("unexpected subjects %v", binding.Subjects)
+	}
+}
+
+func TestApplyRBACConflict(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin-ci"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+	})
+	o := &Options{operations: []string{"get"}, name: "admin-ci", namespace: "open-cluster-management"}
+	o.rules, _ = rbac.Rules(o.operations)
+	if err := o.applyRBAC(kubeClient); err == nil {
+		t.Errorf("expected an error binding the serviceaccount to cluster-admin")
+	}
+}
+
+func TestKubeConfig(t *testing.T) {
+	o := &Options{name: "clusteradm-ci", namespace: "open-cluster-management", hubAPIServer: "https://hub:6443"}
+	data, err := o.kubeConfig([]byte("ca"), "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restConfig.Host != "https://hub:6443" || restConfig.BearerToken != "token" || string(restConfig.CAData) != "ca" {
+		t.Errorf("unexpected config %v", restConfig)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	o := &Options{operations: []string{" accept", "clean  orphans", ""}}
+	if err := o.complete(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(o.operations, []string{"accept", "clean orphans"}) {
+		t.Errorf("unexpected operations %q", o.operations)
+	}
+}
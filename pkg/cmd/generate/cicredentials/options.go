@@ -0,0 +1,39 @@
+// Copyright Contributors to the Open Cluster Management project
+package cicredentials
+
+import (
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The clusteradm operations the serviceaccount runs
+	operations []string
+	//The name of the serviceaccount and its rbac
+	name string
+	//The namespace of the serviceaccount
+	namespace string
+	//The file of the kubeconfig
+	outputFile string
+	//The hub api server url in the kubeconfig
+	hubAPIServer string
+	//The requested validity of the token
+	expiration time.Duration
+
+	//The rbac rules of the operations
+	rules []rbacv1.PolicyRule
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
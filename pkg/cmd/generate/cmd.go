@@ -0,0 +1,25 @@
+// Copyright Contributors to the Open Cluster Management project
+package generate
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/generate/cicredentials"
+	"open-cluster-management.io/clusteradm/pkg/cmd/generate/credentials"
+	"open-cluster-management.io/clusteradm/pkg/cmd/generate/operatorjob"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the commands generating artifacts on the hub
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "generate artifacts used to join clusters to the hub or to operate it",
+	}
+
+	cmd.AddCommand(credentials.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(cicredentials.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(operatorjob.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
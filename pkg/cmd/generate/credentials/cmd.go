@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+package credentials
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Generate the pre-approved credentials of a cluster
+%[1]s generate credentials --cluster cluster1 --output cluster1.tar
+# Join the cluster with the credentials, no accept is needed on the hub
+%[1]s join --credentials cluster1.tar
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "generate the pre-approved credentials of a cluster",
+		Long: "generate a client certificate approved by the hub and the kubeconfig of the registration agent of a cluster, " +
+			"and accept the cluster. The cluster joins with the credentials without any interaction with the hub admin.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.clusterName, "cluster", "", "The name of the cluster")
+	cmd.Flags().StringVar(&o.agentName, "agent-name", "", "The name of the registration agent of the cluster, defaults to a random name")
+	cmd.Flags().StringVar(&o.outputFile, "output", "", "The file the credentials bundle is written to, defaults to <cluster>-credentials.tar")
+	cmd.Flags().StringVar(&o.hubAPIServer, "hub-apiserver", "", "The api server url of the hub the cluster connects to, defaults to the server of the current context")
+	cmd.Flags().DurationVar(&o.expiration, "expiration", 0, "The requested validity of the client certificate, defaults to the validity of the signer. "+
+		"The registration agent rotates the certificate before it expires")
+
+	return cmd
+}
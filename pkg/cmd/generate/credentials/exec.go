@@ -0,0 +1,197 @@
+// Copyright Contributors to the Open Cluster Management project
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/credentials"
+)
+
+// clusterNameLabel is set on the CSRs of the clusters by the registration agent
+const clusterNameLabel = "open-cluster-management.io/cluster-name"
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	if len(o.agentName) == 0 {
+		o.agentName = rand.String(5)
+	}
+	if len(o.outputFile) == 0 {
+		o.outputFile = fmt.Sprintf("%s-credentials.tar", o.clusterName)
+	}
+	klog.V(1).InfoS("generate credentials options:", "cluster", o.clusterName, "agent-name", o.agentName, "output", o.outputFile,
+		"hub-apiserver", o.hubAPIServer, "expiration", o.expiration)
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(o.clusterName) == 0 {
+		return fmt.Errorf("the name of the cluster must be specified with --cluster")
+	}
+	if errs := validation.IsDNS1123Label(o.clusterName); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster name %q: %v", o.clusterName, errs)
+	}
+	if o.expiration < 0 || (o.expiration > 0 && o.expiration < 10*time.Minute) {
+		return fmt.Errorf("--expiration should be at least 10m")
+	}
+
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, _, _, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(o.hubAPIServer) == 0 {
+		o.hubAPIServer = restConfig.Host
+	}
+	caData, err := helpers.GetHubCA(kubeClient, restConfig)
+	if err != nil {
+		return err
+	}
+
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "the credentials of agent %s of cluster %s would be written to %s\n", o.agentName, o.clusterName, o.outputFile)
+		return nil
+	}
+
+	bundle, err := o.generate(kubeClient, clusterClient, caData)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(o.outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := credentials.Write(f, bundle); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Streams.Out, "the credentials of cluster %s are written to %s, join the cluster with:\n\n"+
+		"    %s join --credentials %s\n\n"+
+		"the credentials grant access to the hub as the cluster, keep them secret.\n",
+		o.clusterName, o.outputFile, helpers.GetExampleHeader(), o.outputFile)
+	return nil
+}
+
+// generate accepts the cluster and returns its credentials signed by the hub
+func (o *Options) generate(kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface, caData []byte) (*credentials.Bundle, error) {
+	if err := acceptCluster(clusterClient, o.clusterName); err != nil {
+		return nil, err
+	}
+
+	keyPEM, csrPEM, err := credentials.NewKeyAndCSR(o.clusterName, o.agentName)
+	if err != nil {
+		return nil, err
+	}
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-credentials-", o.clusterName),
+			Labels:       map[string]string{clusterNameLabel: o.clusterName},
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    csrPEM,
+			SignerName: certificatesv1.KubeAPIServerClientSignerName,
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}
+	if o.expiration > 0 {
+		expirationSeconds := int32(o.expiration / time.Second)
+		csr.Spec.ExpirationSeconds = &expirationSeconds
+	}
+	csr, err = kubeClient.CertificatesV1().CertificateSigningRequests().Create(context.TODO(), csr, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Status:         corev1.ConditionTrue,
+		Type:           certificatesv1.CertificateApproved,
+		Reason:         fmt.Sprintf("%s Approve", helpers.GetExampleHeader()),
+		Message:        fmt.Sprintf("This CSR was pre-approved by %s generate credentials.", helpers.GetExampleHeader()),
+		LastUpdateTime: metav1.Now(),
+	})
+	if _, err := kubeClient.CertificatesV1().CertificateSigningRequests().UpdateApproval(context.TODO(), csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	var cert []byte
+	err = wait.PollImmediate(time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		csr, err := kubeClient.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), csr.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		cert = csr.Status.Certificate
+		return len(cert) > 0, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the certificate of csr %s: %w", csr.Name, err)
+	}
+
+	kubeConfig, err := credentials.HubKubeConfig(o.hubAPIServer, caData)
+	if err != nil {
+		return nil, err
+	}
+	return &credentials.Bundle{
+		ClusterName: o.clusterName,
+		AgentName:   o.agentName,
+		KubeConfig:  kubeConfig,
+		Cert:        cert,
+		Key:         keyPEM,
+	}, nil
+}
+
+// acceptCluster creates the managed cluster accepted by the hub, the hub creates the permissions
+// of the cluster before its agent connects with the credentials.
+func acceptCluster(clusterClient clusterclientset.Interface, clusterName string) error {
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = clusterClient.ClusterV1().ManagedClusters().Create(context.TODO(), &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+			Spec:       clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+		}, metav1.CreateOptions{})
+	case err != nil:
+		return err
+	case !cluster.Spec.HubAcceptsClient:
+		cluster.Spec.HubAcceptsClient = true
+		_, err = clusterClient.ClusterV1().ManagedClusters().Update(context.TODO(), cluster, metav1.UpdateOptions{})
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+// Copyright Contributors to the Open Cluster Management project
+package credentials
+
+import (
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The name of the cluster
+	clusterName string
+	//The name of the registration agent
+	agentName string
+	//The file of the credentials bundle
+	outputFile string
+	//The hub api server url in the kubeconfig of the bundle
+	hubAPIServer string
+	//The requested validity of the client certificate
+	expiration time.Duration
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+package operatorjob
+
+import (
+	"fmt"
+	"strings"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Generate a cronjob accepting the clusters every 5 minutes, and deploy it on the hub
+%[1]s generate operator-job --command "accept --clusters cluster1,cluster2" --schedule "*/5 * * * *" --image <registry>/clusteradm:<tag> | kubectl apply -f -
+# Generate a cronjob cleaning the resources of the removed clusters every day
+%[1]s generate operator-job --command "clean orphans --confirm" --schedule "0 2 * * *" --image <registry>/clusteradm:<tag>
+# Generate a job running once with an existing clusterrole
+%[1]s generate operator-job --name renew --command "renew --clusters cluster1" --cluster-role cluster-admin --image <registry>/clusteradm:<tag>
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "operator-job",
+		Short: "generate a job running a clusteradm command on the hub",
+		Long: "generate the manifests of a cronjob, or a job without --schedule, running a clusteradm command in the hub cluster, " +
+			"with its serviceaccount and the rbac it needs. The rbac is generated for the " + strings.Join(rbac.Operations(), ", ") + " commands, " +
+			"an existing clusterrole must be set with --cluster-role for the other commands.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.command, "command", "", "The clusteradm command run by the job, without the clusteradm binary, e.g. \"accept --clusters cluster1\"")
+	cmd.Flags().StringVar(&o.schedule, "schedule", "", "The cron schedule of the job, a job running once is generated if not set")
+	cmd.Flags().StringVar(&o.image, "image", "", "The image of clusteradm run by the job")
+	cmd.Flags().StringVar(&o.name, "name", "", "The name of the job and its rbac, defaults to clusteradm-<command>")
+	cmd.Flags().StringVar(&o.namespace, "namespace", "open-cluster-management", "The namespace of the job")
+	cmd.Flags().StringVar(&o.clusterRole, "cluster-role", "", "An existing clusterrole bound to the serviceaccount of the job instead of the generated one")
+
+	return cmd
+}
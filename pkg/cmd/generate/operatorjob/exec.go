@@ -0,0 +1,146 @@
+// Copyright Contributors to the Open Cluster Management project
+package operatorjob
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+	"sigs.k8s.io/yaml"
+)
+
+// maxCronJobNameLength is the max length of the name of a cronjob, the controller appends a suffix
+// to the names of the jobs it creates
+const maxCronJobNameLength = 52
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	o.args = strings.Fields(o.command)
+	if len(o.args) > 0 && o.args[0] == "clusteradm" {
+		o.args = o.args[1:]
+	}
+	if len(o.name) == 0 {
+		o.name = "clusteradm-" + strings.Join(rbac.Subcommands(o.args), "-")
+	}
+	klog.V(1).InfoS("generate operator-job options:", "command", o.command, "schedule", o.schedule, "image", o.image,
+		"name", o.name, "namespace", o.namespace, "cluster-role", o.clusterRole)
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	if len(o.args) == 0 {
+		return fmt.Errorf("the clusteradm command must be specified with --command")
+	}
+	if len(o.image) == 0 {
+		return fmt.Errorf("the image of clusteradm must be specified with --image")
+	}
+	if len(o.schedule) > 0 && len(strings.Fields(o.schedule)) != 5 {
+		return fmt.Errorf("invalid schedule %q, it should be a cron expression of 5 fields", o.schedule)
+	}
+	if errs := validation.IsDNS1123Subdomain(o.name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %v", o.name, errs)
+	}
+	if len(o.schedule) > 0 && len(o.name) > maxCronJobNameLength {
+		return fmt.Errorf("the name %q of the cronjob should be at most %d characters", o.name, maxCronJobNameLength)
+	}
+	if len(o.clusterRole) == 0 && rbac.RulesOf(o.args) == nil {
+		return fmt.Errorf("the rbac of command %q can not be generated, an existing clusterrole must be specified with --cluster-role",
+			strings.Join(rbac.Subcommands(o.args), " "))
+	}
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	return printManifests(o.Streams.Out, o.manifests())
+}
+
+// manifests returns the serviceaccount, the rbac and the job or cronjob running the command
+func (o *Options) manifests() []runtime.Object {
+	labels := map[string]string{"app.kubernetes.io/name": "clusteradm", "app.kubernetes.io/instance": o.name}
+	meta := metav1.ObjectMeta{Name: o.name, Namespace: o.namespace, Labels: labels}
+	clusterMeta := metav1.ObjectMeta{Name: o.name, Labels: labels}
+
+	objs := []runtime.Object{
+		&corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: meta,
+		},
+	}
+
+	clusterRole := o.clusterRole
+	if len(clusterRole) == 0 {
+		clusterRole = o.name
+		objs = append(objs, &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: clusterMeta,
+			Rules:      rbac.RulesOf(o.args),
+		})
+	}
+	objs = append(objs, &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: clusterMeta,
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: clusterRole},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: o.name, Namespace: o.namespace}},
+	})
+
+	backoffLimit := int32(0)
+	jobSpec := batchv1.JobSpec{
+		BackoffLimit: &backoffLimit,
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: o.name,
+				RestartPolicy:      corev1.RestartPolicyNever,
+				Containers: []corev1.Container{{
+					Name:    "clusteradm",
+					Image:   o.image,
+					Command: []string{"clusteradm"},
+					Args:    o.args,
+				}},
+			},
+		},
+	}
+	if len(o.schedule) == 0 {
+		return append(objs, &batchv1.Job{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+			ObjectMeta: meta,
+			Spec:       jobSpec,
+		})
+	}
+	return append(objs, &batchv1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: meta,
+		Spec: batchv1.CronJobSpec{
+			Schedule:          o.schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       jobSpec,
+			},
+		},
+	})
+}
+
+func printManifests(out io.Writer, objs []runtime.Object) error {
+	for i, obj := range objs {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if _, err := out.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
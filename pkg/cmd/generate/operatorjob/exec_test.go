@@ -0,0 +1,85 @@
+// Copyright Contributors to the Open Cluster Management project
+package operatorjob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		options     Options
+		expectedErr bool
+	}{
+		{name: "valid", options: Options{command: "clusteradm accept --clusters c1", image: "clusteradm:latest", schedule: "*/5 * * * *"}},
+		{name: "no command", options: Options{image: "clusteradm:latest"}, expectedErr: true},
+		{name: "no image", options: Options{command: "accept --clusters c1"}, expectedErr: true},
+		{name: "invalid schedule", options: Options{command: "accept", image: "clusteradm:latest", schedule: "@every 5m"}, expectedErr: true},
+		{name: "unknown rbac", options: Options{command: "renew --clusters c1", image: "clusteradm:latest"}, expectedErr: true},
+		{name: "cluster role", options: Options{command: "renew --clusters c1", image: "clusteradm:latest", clusterRole: "cluster-admin"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := c.options
+			if err := o.complete(nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := o.validate(); (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestManifests(t *testing.T) {
+	o := Options{command: "clusteradm accept --clusters c1,c2", image: "clusteradm:latest", schedule: "*/5 * * * *", namespace: "open-cluster-management"}
+	if err := o.complete(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if o.name != "clusteradm-accept" {
+		t.Errorf("expected name clusteradm-accept, got %s", o.name)
+	}
+
+	out := &bytes.Buffer{}
+	if err := printManifests(out, o.manifests()); err != nil {
+		t.Fatal(err)
+	}
+	decoder := yaml.NewYAMLOrJSONDecoder(out, 4096)
+	kinds := []string{}
+	for {
+		obj := map[string]interface{}{}
+		if err := decoder.Decode(&obj); err != nil {
+			break
+		}
+		kinds = append(kinds, obj["kind"].(string))
+	}
+	if strings.Join(kinds, ",") != "ServiceAccount,ClusterRole,ClusterRoleBinding,CronJob" {
+		t.Errorf("unexpected manifests %v", kinds)
+	}
+
+	objs := o.manifests()
+	cronJob := objs[len(objs)-1].(*batchv1.CronJob)
+	container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	if strings.Join(container.Args, " ") != "accept --clusters c1,c2" {
+		t.Errorf("unexpected args %v", container.Args)
+	}
+
+	o.schedule = ""
+	o.clusterRole = "cluster-admin"
+	objs = o.manifests()
+	if len(objs) != 3 {
+		t.Fatalf("expected 3 manifests, got %d", len(objs))
+	}
+	if binding := objs[1].(*rbacv1.ClusterRoleBinding); binding.RoleRef.Name != "cluster-admin" {
+		t.Errorf("expected the binding of cluster-admin, got %s", binding.RoleRef.Name)
+	}
+	if _, ok := objs[2].(*batchv1.Job); !ok {
+		t.Errorf("expected a job, got %T", objs[2])
+	}
+}
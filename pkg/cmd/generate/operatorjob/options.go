@@ -0,0 +1,36 @@
+// Copyright Contributors to the Open Cluster Management project
+package operatorjob
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The clusteradm command run by the job
+	command string
+	//The cron schedule of the job
+	schedule string
+	//The image of clusteradm
+	image string
+	//The name of the job and its rbac
+	name string
+	//The namespace of the job
+	namespace string
+	//The existing clusterrole bound to the serviceaccount
+	clusterRole string
+
+	//The arguments of the command
+	args []string
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
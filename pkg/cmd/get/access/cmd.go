@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+package access
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get who can admin, bind or view the clustersets
+%[1]s get access
+# Get who can admin, bind or view specific clustersets
+%[1]s get access --clustersets clusterset1,clusterset2
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "access",
+		Short:        "get the roles granted on the clustersets",
+		Long:         "get the users, groups and service accounts granted the admin, bind or view role on the clustersets by cluster role bindings, a * clusterset is a role granted on all the clustersets",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.clusterSets, "clustersets", []string{}, "Names of the clustersets (comma separated), defaults to all the clustersets")
+
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,121 @@
+// Copyright Contributors to the Open Cluster Management project
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterset"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get access options:", "clustersets", o.clusterSets)
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	kubeClient, _, _, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+
+	access, err := clusterset.ListAccess(context.TODO(), kubeClient)
+	if err != nil {
+		return err
+	}
+
+	clusterSets := sets.NewString(o.clusterSets...)
+	bindingList := &rbacv1.ClusterRoleBindingList{}
+	for _, a := range access {
+		// the roles granted on all the clustersets apply to the filtered clustersets too
+		if clusterSets.Len() > 0 && !clusterSets.Has(a.ClusterSet) && a.ClusterSet != clusterset.AllClusterSets {
+			continue
+		}
+		if _, ok := o.access[a.ClusterRoleBinding]; !ok {
+			bindingList.Items = append(bindingList.Items, rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: a.ClusterRoleBinding},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: a.ClusterRole},
+			})
+		}
+		o.access[a.ClusterRoleBinding] = append(o.access[a.ClusterRoleBinding], a)
+	}
+
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, bindingList)
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if bindingList, ok := obj.(*rbacv1.ClusterRoleBindingList); ok {
+		for _, binding := range bindingList.Items {
+			for _, a := range o.access[binding.Name] {
+				clusterSet, role, kind, subject := getFileds(a)
+				mp := make(map[string]interface{})
+				mp[".Role"] = role
+				mp[".Subject"] = fmt.Sprintf("%s/%s", kind, subject)
+				mp[".ClusterRoleBinding"] = binding.Name
+
+				tree.AddFileds(clusterSet, &mp)
+			}
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "ClusterSet", Type: "string"},
+			{Name: "Role", Type: "string"},
+			{Name: "Kind", Type: "string"},
+			{Name: "Subject", Type: "string"},
+			{Name: "ClusterRoleBinding", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if bindingList, ok := obj.(*rbacv1.ClusterRoleBindingList); ok {
+		for _, binding := range bindingList.Items {
+			binding := binding
+			for _, a := range o.access[binding.Name] {
+				clusterSet, role, kind, subject := getFileds(a)
+				row := metav1.TableRow{
+					Cells:  []interface{}{clusterSet, role, kind, subject, binding.Name},
+					Object: runtime.RawExtension{Object: &binding},
+				}
+
+				table.Rows = append(table.Rows, row)
+			}
+		}
+	}
+
+	return table
+}
+
+func getFileds(a clusterset.Access) (clusterSet, role, kind, subject string) {
+	subject = a.Subject.Name
+	if len(a.Subject.Namespace) > 0 {
+		subject = a.Subject.Namespace + "/" + a.Subject.Name
+	}
+	return a.ClusterSet, a.Role, a.Subject.Kind, subject
+}
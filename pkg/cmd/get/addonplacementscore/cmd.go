@@ -0,0 +1,57 @@
+// Copyright Contributors to the Open Cluster Management project
+package addonplacementscore
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get the placement scores of all the managed clusters
+%[1]s get addon-placement-scores
+# Get the placement scores of a managed cluster
+%[1]s get addon-placement-scores --cluster cluster1 -o table
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "addon-placement-scores",
+		Aliases:      []string{"addon-placement-score", "addonplacementscores", "addonplacementscore"},
+		Short:        "get the addon placement scores of the managed clusters",
+		Long:         "get the AddOnPlacementScores of the managed clusters with their score values and validity, as used by the score based prioritizers of the placements",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(args); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "Name of the managed cluster, defaults to all the managed clusters")
+
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
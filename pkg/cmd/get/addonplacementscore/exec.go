@@ -0,0 +1,131 @@
+// Copyright Contributors to the Open Cluster Management project
+package addonplacementscore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get addon-placement-scores options:", "cluster", o.cluster)
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate(args []string) (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		return fmt.Errorf("there should be no argument")
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	// the scores are in the namespaces of the clusters
+	namespace := metav1.NamespaceAll
+	if len(o.cluster) > 0 {
+		namespace = o.cluster
+	}
+	scores, err := clusterClient.ClusterV1alpha1().AddOnPlacementScores(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	o.now = time.Now()
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, scores)
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if scoreList, ok := obj.(*clusterv1alpha1.AddOnPlacementScoreList); ok {
+		for _, score := range scoreList.Items {
+			values := []string{}
+			for _, item := range score.Status.Scores {
+				values = append(values, fmt.Sprintf("%s=%d", item.Name, item.Value))
+			}
+			valid, expiry := validity(&score, o.now)
+			mp := make(map[string]interface{})
+			mp[".Scores"] = strings.Join(values, ", ")
+			mp[".Valid"] = valid
+			mp[".Expiry"] = expiry
+			tree.AddFileds(score.Namespace+"."+score.Name, &mp)
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Cluster", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "Score", Type: "string"},
+			{Name: "Value", Type: "integer"},
+			{Name: "Valid", Type: "string"},
+			{Name: "Expiry", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if scoreList, ok := obj.(*clusterv1alpha1.AddOnPlacementScoreList); ok {
+		for _, score := range scoreList.Items {
+			score := score
+			valid, expiry := validity(&score, o.now)
+			// the scores without values are listed, the score producer may not have reported them yet
+			if len(score.Status.Scores) == 0 {
+				table.Rows = append(table.Rows, metav1.TableRow{
+					Cells:  []interface{}{score.Namespace, score.Name, "<none>", "", valid, expiry},
+					Object: runtime.RawExtension{Object: &score},
+				})
+			}
+			for _, item := range score.Status.Scores {
+				table.Rows = append(table.Rows, metav1.TableRow{
+					Cells:  []interface{}{score.Namespace, score.Name, item.Name, item.Value, valid, expiry},
+					Object: runtime.RawExtension{Object: &score},
+				})
+			}
+		}
+	}
+
+	return table
+}
+
+// validity returns whether the scores are valid at now, as a condition status, and when they expire
+func validity(score *clusterv1alpha1.AddOnPlacementScore, now time.Time) (string, string) {
+	validUntil := score.Status.ValidUntil
+	switch {
+	case validUntil == nil:
+		return string(metav1.ConditionTrue), "never"
+	case now.Before(validUntil.Time):
+		return string(metav1.ConditionTrue), "in " + duration.HumanDuration(validUntil.Sub(now))
+	}
+	return string(metav1.ConditionFalse), duration.HumanDuration(now.Sub(validUntil.Time)) + " ago"
+}
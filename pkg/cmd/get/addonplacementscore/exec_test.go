@@ -0,0 +1,57 @@
+// Copyright Contributors to the Open Cluster Management project
+package addonplacementscore
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+)
+
+func TestConverToTable(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(d))
+		return &t
+	}
+	scores := &clusterv1alpha1.AddOnPlacementScoreList{Items: []clusterv1alpha1.AddOnPlacementScore{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "resource-usage-score", Namespace: "cluster1"},
+			Status: clusterv1alpha1.AddOnPlacementScoreStatus{
+				Scores: []clusterv1alpha1.AddOnPlacementScoreItem{
+					{Name: "cpuAvailable", Value: 66},
+					{Name: "memAvailable", Value: -20},
+				},
+				ValidUntil: validUntil(5 * time.Minute),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "resource-usage-score", Namespace: "cluster2"},
+			Status:     clusterv1alpha1.AddOnPlacementScoreStatus{ValidUntil: validUntil(-time.Hour)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "static-score", Namespace: "cluster2"},
+			Status:     clusterv1alpha1.AddOnPlacementScoreStatus{Scores: []clusterv1alpha1.AddOnPlacementScoreItem{{Name: "tier", Value: 100}}},
+		},
+	}}
+
+	o := newOptions(nil, genericclioptions.IOStreams{})
+	o.now = now
+	table := o.converToTable(scores)
+	expected := [][]interface{}{
+		{"cluster1", "resource-usage-score", "cpuAvailable", int32(66), "True", "in 5m"},
+		{"cluster1", "resource-usage-score", "memAvailable", int32(-20), "True", "in 5m"},
+		{"cluster2", "resource-usage-score", "<none>", "", "False", "60m ago"},
+		{"cluster2", "static-score", "tier", int32(100), "True", "never"},
+	}
+	var cells [][]interface{}
+	for _, row := range table.Rows {
+		cells = append(cells, row.Cells)
+	}
+	if !reflect.DeepEqual(cells, expected) {
+		t.Errorf("expected %v, got %v", expected, cells)
+	}
+}
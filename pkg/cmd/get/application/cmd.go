@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+package application
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get the applications and their propagation status on each managed cluster
+%[1]s get applications
+# Get the applications in a namespace
+%[1]s get applications -n default
+# Get the applications propagated to a managed cluster
+%[1]s get applications --cluster cluster1
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "applications",
+		Short:        "get applications and their propagation status",
+		Long:         "get the Subscriptions of the application-manager add-on and the ApplicationSets of the Argo CD pull integration, with their status on each managed cluster",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "Name of the managed cluster, only shows the applications propagated to this cluster")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "", "Namespace of the applications, defaults to all the namespaces")
+
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,230 @@
+// Copyright Contributors to the Open Cluster Management project
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+// hostingSubscriptionAnnotation is set on the subscriptions deployed on the managed clusters
+const hostingSubscriptionAnnotation = "apps.open-cluster-management.io/hosting-subscription"
+
+var (
+	subscriptionGVR = schema.GroupVersionResource{
+		Group:    "apps.open-cluster-management.io",
+		Version:  "v1",
+		Resource: "subscriptions",
+	}
+	subscriptionReportGVR = schema.GroupVersionResource{
+		Group:    "apps.open-cluster-management.io",
+		Version:  "v1alpha1",
+		Resource: "subscriptionreports",
+	}
+	applicationSetGVR = schema.GroupVersionResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Resource: "applicationsets",
+	}
+	applicationSetReportGVR = schema.GroupVersionResource{
+		Group:    "apps.open-cluster-management.io",
+		Version:  "v1alpha1",
+		Resource: "multiclusterapplicationsetreports",
+	}
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get applications options:", "cluster", o.cluster, "namespace", o.namespace)
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	applications := &unstructured.UnstructuredList{}
+
+	subscriptions, err := o.listApplications(dynamicClient, subscriptionGVR, subscriptionReportGVR, getSubscriptionStatuses)
+	if err != nil {
+		return err
+	}
+	applicationSets, err := o.listApplications(dynamicClient, applicationSetGVR, applicationSetReportGVR, getApplicationSetStatuses)
+	if err != nil {
+		return err
+	}
+	for _, app := range append(subscriptions, applicationSets...) {
+		// skip the subscriptions deployed on the managed clusters, e.g. a self-managed hub
+		if _, ok := app.GetAnnotations()[hostingSubscriptionAnnotation]; ok {
+			continue
+		}
+		if len(o.cluster) > 0 {
+			if _, ok := o.clusterStatuses[key(app)][o.cluster]; !ok {
+				continue
+			}
+		}
+		applications.Items = append(applications.Items, app)
+	}
+
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, applications)
+}
+
+// listApplications lists the applications of the given resource and records their
+// status on each cluster from the reports. The resources are ignored if the api
+// is not installed on the hub.
+func (o *Options) listApplications(client dynamic.Interface, gvr, reportGVR schema.GroupVersionResource,
+	getStatuses func(report *unstructured.Unstructured) map[string]string) ([]unstructured.Unstructured, error) {
+	appList, err := client.Resource(gvr).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.V(3).InfoS("resource is not installed on the hub", "resource", gvr.String())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range appList.Items {
+		report, err := client.Resource(reportGVR).Namespace(app.GetNamespace()).Get(context.TODO(), app.GetName(), metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			o.clusterStatuses[key(app)] = map[string]string{}
+		case err != nil:
+			return nil, err
+		default:
+			o.clusterStatuses[key(app)] = getStatuses(report)
+		}
+	}
+	return appList.Items, nil
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if appList, ok := obj.(*unstructured.UnstructuredList); ok {
+		for _, app := range appList.Items {
+			mp := make(map[string]interface{})
+			mp[".Kind"] = app.GetKind()
+			mp[".Namespace"] = app.GetNamespace()
+			for cluster, status := range o.clusterStatuses[key(app)] {
+				mp[".Clusters."+cluster] = status
+			}
+
+			tree.AddFileds(app.GetName(), &mp)
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Namespace", Type: "string"},
+			{Name: "Kind", Type: "string"},
+			{Name: "Clusters", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if appList, ok := obj.(*unstructured.UnstructuredList); ok {
+		for _, app := range appList.Items {
+			app := app
+			statuses := o.clusterStatuses[key(app)]
+			summary := []string{}
+			for cluster, status := range statuses {
+				summary = append(summary, fmt.Sprintf("%s=%s", cluster, status))
+			}
+			sort.Strings(summary)
+			row := metav1.TableRow{
+				Cells:  []interface{}{app.GetName(), app.GetNamespace(), app.GetKind(), strings.Join(summary, ",")},
+				Object: runtime.RawExtension{Object: &app},
+			}
+
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	return table
+}
+
+// getSubscriptionStatuses returns the status of the subscription on each cluster from its SubscriptionReport
+func getSubscriptionStatuses(report *unstructured.Unstructured) map[string]string {
+	statuses := map[string]string{}
+	results, _, _ := unstructured.NestedSlice(report.Object, "results")
+	for _, r := range results {
+		result, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cluster, _, _ := unstructured.NestedString(result, "source")
+		status, _, _ := unstructured.NestedString(result, "result")
+		if len(cluster) > 0 {
+			statuses[cluster] = colorStatus(status)
+		}
+	}
+	return statuses
+}
+
+// getApplicationSetStatuses returns the status of the applicationset on each cluster from its MulticlusterApplicationSetReport
+func getApplicationSetStatuses(report *unstructured.Unstructured) map[string]string {
+	statuses := map[string]string{}
+	conditions, _, _ := unstructured.NestedSlice(report.Object, "statuses", "clusterConditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cluster, _, _ := unstructured.NestedString(condition, "cluster")
+		syncStatus, _, _ := unstructured.NestedString(condition, "syncStatus")
+		healthStatus, _, _ := unstructured.NestedString(condition, "healthStatus")
+		if len(cluster) == 0 {
+			continue
+		}
+		status := syncStatus
+		if len(healthStatus) > 0 {
+			status = fmt.Sprintf("%s/%s", syncStatus, healthStatus)
+		}
+		statuses[cluster] = colorStatus(status)
+	}
+	return statuses
+}
+
+func colorStatus(status string) string {
+	switch {
+	case len(status) == 0:
+		return "Unknown"
+	case strings.Contains(strings.ToLower(status), "fail") || strings.Contains(status, "Degraded") || strings.Contains(status, "OutOfSync"):
+		return color.RedString(status)
+	default:
+		return status
+	}
+}
+
+func key(app unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", app.GetKind(), app.GetNamespace(), app.GetName())
+}
@@ -0,0 +1,50 @@
+// Copyright Contributors to the Open Cluster Management project
+package application
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//Only show the applications propagated to this cluster
+	cluster string
+	//The namespace of the applications
+	namespace string
+
+	//The status of the application on each cluster, keyed by kind/namespace/name
+	clusterStatuses map[string]map[string]string
+
+	Streams genericclioptions.IOStreams
+
+	printer *printer.PrinterOption
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+		clusterStatuses: map[string]map[string]string{},
+		printer:         printer.NewPrinterOption(pntOpt),
+	}
+}
+
+var pntOpt = printers.PrintOptions{
+	NoHeaders:     false,
+	WithNamespace: false,
+	WithKind:      false,
+	Wide:          false,
+	ShowLabels:    false,
+	Kind: schema.GroupKind{
+		Group: "apps.open-cluster-management.io",
+		Kind:  "Application",
+	},
+	ColumnLabels:     []string{},
+	SortBy:           "",
+	AllowMissingKeys: true,
+}
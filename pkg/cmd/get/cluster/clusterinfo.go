@@ -0,0 +1,105 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// clusterInfoGVR is the ManagedClusterInfo of the multicloud-operators foundation, it is created in the
+// cluster namespace by the foundation controller with the details of the cluster reported by its agent
+var clusterInfoGVR = schema.GroupVersionResource{
+	Group:    "internal.open-cluster-management.io",
+	Version:  "v1beta1",
+	Resource: "managedclusterinfos",
+}
+
+// clusterInfo are the details of the ManagedClusterInfo of a cluster
+type clusterInfo struct {
+	consoleURL   string
+	distribution string
+	// nodes is the number of ready nodes out of the nodes of the cluster
+	nodes string
+}
+
+// clusterInfoColumns are printed with -o wide if the foundation is installed on the hub
+var clusterInfoColumns = []metav1.TableColumnDefinition{
+	{Name: "Console", Type: "string", Priority: 1},
+	{Name: "Distribution", Type: "string", Priority: 1},
+	{Name: "Nodes", Type: "string", Priority: 1},
+}
+
+// listClusterInfo lists the ManagedClusterInfos of all the clusters at once, it returns nil if the
+// foundation is not installed on the hub.
+func listClusterInfo(discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface) (map[string]clusterInfo, error) {
+	_, err := discoveryClient.ServerResourcesForGroupVersion(clusterInfoGVR.GroupVersion().String())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := dynamicClient.Resource(clusterInfoGVR).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	switch {
+	case errors.IsNotFound(err) || meta.IsNoMatchError(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	infos := map[string]clusterInfo{}
+	for _, item := range list.Items {
+		// the ManagedClusterInfo is named after its cluster
+		infos[item.GetName()] = clusterInfoOf(item.Object)
+	}
+	return infos, nil
+}
+
+func clusterInfoOf(obj map[string]interface{}) clusterInfo {
+	info := clusterInfo{consoleURL: none, distribution: none, nodes: none}
+	if consoleURL, _, _ := unstructured.NestedString(obj, "status", "consoleURL"); len(consoleURL) > 0 {
+		info.consoleURL = consoleURL
+	}
+
+	vendor, _, _ := unstructured.NestedString(obj, "status", "kubeVendor")
+	version, _, _ := unstructured.NestedString(obj, "status", "version")
+	if distributionType, _, _ := unstructured.NestedString(obj, "status", "distributionInfo", "type"); distributionType == "OCP" {
+		if ocpVersion, _, _ := unstructured.NestedString(obj, "status", "distributionInfo", "ocp", "version"); len(ocpVersion) > 0 {
+			version = ocpVersion
+		}
+	}
+	if distribution := strings.TrimSpace(vendor + " " + version); len(distribution) > 0 {
+		info.distribution = distribution
+	}
+
+	nodes, found, _ := unstructured.NestedSlice(obj, "status", "nodeList")
+	if !found {
+		return info
+	}
+	ready := 0
+	for _, node := range nodes {
+		nodeObj, ok := node.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(nodeObj, "conditions")
+		for _, condition := range conditions {
+			conditionObj, ok := condition.(map[string]interface{})
+			if ok && conditionObj["type"] == "Ready" && conditionObj["status"] == "True" {
+				ready++
+			}
+		}
+	}
+	info.nodes = fmt.Sprintf("%d/%d", ready, len(nodes))
+	return info
+}
@@ -0,0 +1,89 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestClusterInfoOf(t *testing.T) {
+	ready := map[string]interface{}{"type": "Ready", "status": "True"}
+	notReady := map[string]interface{}{"type": "Ready", "status": "False"}
+	cases := []struct {
+		name     string
+		status   map[string]interface{}
+		expected clusterInfo
+	}{
+		{
+			name: "openshift",
+			status: map[string]interface{}{
+				"consoleURL":       "https://console.apps.cluster1.example.com",
+				"kubeVendor":       "OpenShift",
+				"version":          "v1.24.0+9546431",
+				"distributionInfo": map[string]interface{}{"type": "OCP", "ocp": map[string]interface{}{"version": "4.11.3"}},
+				"nodeList": []interface{}{
+					map[string]interface{}{"name": "node1", "conditions": []interface{}{ready}},
+					map[string]interface{}{"name": "node2", "conditions": []interface{}{notReady}},
+					map[string]interface{}{"name": "node3", "conditions": []interface{}{ready}},
+				},
+			},
+			expected: clusterInfo{consoleURL: "https://console.apps.cluster1.example.com", distribution: "OpenShift 4.11.3", nodes: "2/3"},
+		},
+		{
+			name:     "kubernetes",
+			status:   map[string]interface{}{"kubeVendor": "EKS", "version": "v1.23.7"},
+			expected: clusterInfo{consoleURL: none, distribution: "EKS v1.23.7", nodes: none},
+		},
+		{
+			name:     "not reported",
+			expected: clusterInfo{consoleURL: none, distribution: none, nodes: none},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			obj := map[string]interface{}{}
+			if c.status != nil {
+				obj["status"] = c.status
+			}
+			if info := clusterInfoOf(obj); info != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, info)
+			}
+		})
+	}
+}
+
+func TestListClusterInfoWithoutFoundation(t *testing.T) {
+	infos, err := listClusterInfo(fakekube.NewSimpleClientset().Discovery(), nil)
+	if err != nil || infos != nil {
+		t.Errorf("expected no details without the foundation, got %v %v", infos, err)
+	}
+}
+
+func TestClusterInfoColumns(t *testing.T) {
+	clusters := &clusterapiv1.ManagedClusterList{Items: []clusterapiv1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}},
+	}}
+	for _, installed := range []bool{false, true} {
+		o := newOptions(nil, genericclioptions.IOStreams{})
+		if installed {
+			o.clusterInfo = map[string]clusterInfo{"cluster1": {consoleURL: "https://console", distribution: "OpenShift 4.11.3", nodes: "3/3"}}
+		}
+		o.printer.Format = "wide"
+		o.printer.Competele()
+		o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+		out := &bytes.Buffer{}
+		if err := o.printer.Print(genericclioptions.IOStreams{Out: out}, clusters); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(out.String(), "DISTRIBUTION") && strings.Contains(out.String(), "OpenShift 4.11.3"); got != installed {
+			t.Errorf("expected the details %v, got %q", installed, out.String())
+		}
+	}
+}
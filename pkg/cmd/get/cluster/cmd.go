@@ -16,6 +16,11 @@ var example = `
 %[1]s get clusters
 # Get clusters in a clusterset
 %[1]s get clusters --clusterset clusterset1
+# Get the clusters running on AWS
+%[1]s get clusters --claim platform.open-cluster-management.io=AWS
+# Get clusters with their agent version, available addons and last lease renewal, and their console,
+# distribution and ready nodes if the multicloud-operators foundation is installed on the hub
+%[1]s get clusters -o wide
 `
 
 // NewCmd...
@@ -49,6 +54,8 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	}
 
 	cmd.Flags().StringVar(&o.Clusterset, "clusterset", "", "ClusterSet of the clusters")
+	cmd.Flags().StringArrayVar(&o.claims, "claim", []string{},
+		"Only get the clusters reporting the ClusterClaim in the format of name=value, can be repeated to match all the claims")
 
 	o.printer.AddFlag(cmd.Flags())
 
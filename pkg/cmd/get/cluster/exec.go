@@ -9,8 +9,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/clusterclaim"
 	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
 )
 
@@ -30,6 +32,11 @@ func (o *Options) validate(args []string) (err error) {
 		return fmt.Errorf("there should be no argument")
 	}
 
+	o.claimSelector, err = clusterclaim.ParseSelector(o.claims)
+	if err != nil {
+		return err
+	}
+
 	err = o.printer.Validate()
 	if err != nil {
 		return err
@@ -63,6 +70,45 @@ func (o *Options) run() (err error) {
 		return err
 	}
 
+	if len(o.claimSelector) > 0 {
+		matched := clusters.Items[:0]
+		for i := range clusters.Items {
+			if clusterclaim.Match(&clusters.Items[i], o.claimSelector) {
+				matched = append(matched, clusters.Items[i])
+			}
+		}
+		clusters.Items = matched
+	}
+
+	if o.printer.Format == "wide" {
+		kubeClient, err := o.ClusteradmFlags.KubectlFactory.KubernetesClientSet()
+		if err != nil {
+			return err
+		}
+		addonClient, err := addonclientset.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+		if o.wideInfo, err = listWideInfo(kubeClient, addonClient, clusters.Items); err != nil {
+			return err
+		}
+	}
+
+	// the details of the clusters are reported if the foundation is installed on the hub
+	if o.printer.Format == "wide" || o.printer.Format == "tree" {
+		kubeClient, err := o.ClusteradmFlags.KubectlFactory.KubernetesClientSet()
+		if err != nil {
+			return err
+		}
+		dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+		if err != nil {
+			return err
+		}
+		if o.clusterInfo, err = listClusterInfo(kubeClient.Discovery(), dynamicClient); err != nil {
+			return err
+		}
+	}
+
 	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
 
 	return o.printer.Print(o.Streams, clusters)
@@ -79,6 +125,11 @@ func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *
 			mp[".KubernetesVersion"] = version
 			mp[".Capacity.Cpu"] = cpu
 			mp[".Capacity.Memory"] = memory
+			if info, ok := o.clusterInfo[cluster.Name]; ok {
+				mp[".Console"] = info.consoleURL
+				mp[".Distribution"] = info.distribution
+				mp[".Nodes"] = info.nodes
+			}
 
 			tree.AddFileds(cluster.Name, &mp)
 		}
@@ -99,14 +150,26 @@ func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
 		},
 		Rows: []metav1.TableRow{},
 	}
+	table.ColumnDefinitions = append(table.ColumnDefinitions, wideColumns...)
+	if o.clusterInfo != nil {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, clusterInfoColumns...)
+	}
 
 	if mclList, ok := obj.(*clusterapiv1.ManagedClusterList); ok {
 		for _, cluster := range mclList.Items {
 			accepted, available, version, cpu, memory, clusterset := getFileds(cluster)
+			info := o.wideInfo[cluster.Name]
 			row := metav1.TableRow{
-				Cells:  []interface{}{cluster.Name, accepted, available, clusterset, cpu, memory, version},
+				Cells:  []interface{}{cluster.Name, accepted, available, clusterset, cpu, memory, version, info.agentVersion, info.addons, info.leaseRenewed},
 				Object: runtime.RawExtension{Object: &cluster},
 			}
+			if o.clusterInfo != nil {
+				details, ok := o.clusterInfo[cluster.Name]
+				if !ok {
+					details = clusterInfo{consoleURL: none, distribution: none, nodes: none}
+				}
+				row.Cells = append(row.Cells, details.consoleURL, details.distribution, details.nodes)
+			}
 
 			table.Rows = append(table.Rows, row)
 		}
@@ -14,10 +14,18 @@ type Options struct {
 	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
 	//Clusterset of the clusters
 	Clusterset string
+	//claims: the ClusterClaims of the clusters in the format of name=value
+	claims []string
+	//claimSelector is parsed from the claims
+	claimSelector map[string]string
 
 	Streams genericclioptions.IOStreams
 
 	printer *printer.PrinterOption
+	//wideInfo are the columns of -o wide by cluster name
+	wideInfo map[string]wideInfo
+	//clusterInfo are the details of the ManagedClusterInfos by cluster name, nil if the foundation is not installed
+	clusterInfo map[string]clusterInfo
 }
 
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
@@ -0,0 +1,89 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+const (
+	// clusterLeaseName is the lease renewed by the registration agent in the cluster namespace
+	clusterLeaseName = "managed-cluster-lease"
+	// agentVersionClaim is the claim of the version of the agents reported by the cluster
+	agentVersionClaim = "version.open-cluster-management.io"
+	none              = "<none>"
+)
+
+// wideInfo are the columns of -o wide, read from the addons and the leases of the cluster
+type wideInfo struct {
+	agentVersion string
+	// addons is the number of available addons out of the addons of the cluster
+	addons string
+	// leaseRenewed is the time since the lease was renewed
+	leaseRenewed string
+}
+
+// wideColumns are printed with -o wide only
+var wideColumns = []metav1.TableColumnDefinition{
+	{Name: "Agent Version", Type: "string", Priority: 1},
+	{Name: "Addons", Type: "string", Priority: 1},
+	{Name: "Lease Renewed", Type: "string", Priority: 1},
+}
+
+// listWideInfo lists the addons and the leases of all the clusters at once
+func listWideInfo(kubeClient kubernetes.Interface, addonClient addonclientset.Interface,
+	clusters []clusterapiv1.ManagedCluster) (map[string]wideInfo, error) {
+	addons, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	leases, err := kubeClient.CoordinationV1().Leases(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("metadata.name=%s", clusterLeaseName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collectWideInfo(clusters, addons.Items, leases.Items, time.Now()), nil
+}
+
+func collectWideInfo(clusters []clusterapiv1.ManagedCluster, addons []addonv1alpha1.ManagedClusterAddOn,
+	leases []coordinationv1.Lease, now time.Time) map[string]wideInfo {
+	total, available := map[string]int{}, map[string]int{}
+	for _, addon := range addons {
+		total[addon.Namespace]++
+		if meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable) {
+			available[addon.Namespace]++
+		}
+	}
+	renewed := map[string]string{}
+	for _, lease := range leases {
+		if lease.Spec.RenewTime != nil {
+			renewed[lease.Namespace] = duration.HumanDuration(now.Sub(lease.Spec.RenewTime.Time)) + " ago"
+		}
+	}
+
+	infos := map[string]wideInfo{}
+	for _, cluster := range clusters {
+		info := wideInfo{agentVersion: none, addons: fmt.Sprintf("%d/%d", available[cluster.Name], total[cluster.Name]), leaseRenewed: none}
+		for _, claim := range cluster.Status.ClusterClaims {
+			if claim.Name == agentVersionClaim {
+				info.agentVersion = claim.Value
+			}
+		}
+		if age, ok := renewed[cluster.Name]; ok {
+			info.leaseRenewed = age
+		}
+		infos[cluster.Name] = info
+	}
+	return infos
+}
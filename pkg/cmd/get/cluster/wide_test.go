@@ -0,0 +1,70 @@
+// Copyright Contributors to the Open Cluster Management project
+package cluster
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestCollectWideInfo(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	clusters := []clusterapiv1.ManagedCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+			Status: clusterapiv1.ManagedClusterStatus{
+				ClusterClaims: []clusterapiv1.ManagedClusterClaim{
+					{Name: "id.k8s.io", Value: "abc"},
+					{Name: agentVersionClaim, Value: "2.6.1"},
+				},
+			},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}},
+	}
+	available := []metav1.Condition{{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue}}
+	addons := []addonv1alpha1.ManagedClusterAddOn{
+		{ObjectMeta: metav1.ObjectMeta{Name: "addon1", Namespace: "cluster1"}, Status: addonv1alpha1.ManagedClusterAddOnStatus{Conditions: available}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "addon2", Namespace: "cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "addon1", Namespace: "cluster3"}, Status: addonv1alpha1.ManagedClusterAddOnStatus{Conditions: available}},
+	}
+	renewTime := metav1.NewMicroTime(now.Add(-30 * time.Second))
+	leases := []coordinationv1.Lease{
+		{ObjectMeta: metav1.ObjectMeta{Name: clusterLeaseName, Namespace: "cluster1"}, Spec: coordinationv1.LeaseSpec{RenewTime: &renewTime}},
+		{ObjectMeta: metav1.ObjectMeta{Name: clusterLeaseName, Namespace: "cluster2"}},
+	}
+
+	infos := collectWideInfo(clusters, addons, leases, now)
+	expected := map[string]wideInfo{
+		"cluster1": {agentVersion: "2.6.1", addons: "1/2", leaseRenewed: "30s ago"},
+		"cluster2": {agentVersion: none, addons: "0/0", leaseRenewed: none},
+	}
+	if !reflect.DeepEqual(infos, expected) {
+		t.Errorf("expected %v, got %v", expected, infos)
+	}
+}
+
+func TestWideColumns(t *testing.T) {
+	clusters := &clusterapiv1.ManagedClusterList{Items: []clusterapiv1.ManagedCluster{{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}}}
+	for format, expectedWide := range map[string]bool{"table": false, "wide": true} {
+		o := newOptions(nil, genericclioptions.IOStreams{})
+		o.wideInfo = map[string]wideInfo{"cluster1": {agentVersion: "2.6.1", addons: "1/2", leaseRenewed: "30s ago"}}
+		o.printer.Format = format
+		o.printer.Competele()
+		o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+		out := &bytes.Buffer{}
+		if err := o.printer.Print(genericclioptions.IOStreams{Out: out}, clusters); err != nil {
+			t.Fatal(err)
+		}
+		if wide := strings.Contains(out.String(), "AGENT VERSION") && strings.Contains(out.String(), "30s ago"); wide != expectedWide {
+			t.Errorf("expected the wide columns %v with -o %s, got %q", expectedWide, format, out.String())
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterclaim
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get the claims of all the managed clusters
+%[1]s get clusterclaims
+# Get the claims of a managed cluster
+%[1]s get clusterclaims --cluster cluster1
+# Get the platform claims of the managed clusters
+%[1]s get clusterclaims --names platform.open-cluster-management.io -o table
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "clusterclaims",
+		Aliases:      []string{"clusterclaim"},
+		Short:        "get the claims of the managed clusters",
+		Long:         "get the ClusterClaims reported in the status of the managed clusters, as the platform, version or region of the clusters",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(args); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "Name of the managed cluster, defaults to all the managed clusters")
+	cmd.Flags().StringSliceVar(&o.names, "names", []string{}, "Names of the claims (comma separated), defaults to all the claims")
+
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,130 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get clusterclaims options:", "cluster", o.cluster, "names", o.names)
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate(args []string) (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		return fmt.Errorf("there should be no argument")
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	var clusters []clusterapiv1.ManagedCluster
+	if len(o.cluster) > 0 {
+		cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), o.cluster, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		clusters = append(clusters, *cluster)
+	} else {
+		clusterList, err := clusterClient.ClusterV1().ManagedClusters().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		clusters = clusterList.Items
+	}
+
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, claimsOf(clusters, o.names))
+}
+
+// claimsOf returns the clusters with only their name and their claims of the names, all the claims if
+// names is empty
+func claimsOf(clusters []clusterapiv1.ManagedCluster, names []string) *clusterapiv1.ManagedClusterList {
+	wanted := sets.NewString(names...)
+	list := &clusterapiv1.ManagedClusterList{}
+	for _, cluster := range clusters {
+		claims := []clusterapiv1.ManagedClusterClaim{}
+		for _, claim := range cluster.Status.ClusterClaims {
+			if wanted.Len() == 0 || wanted.Has(claim.Name) {
+				claims = append(claims, claim)
+			}
+		}
+		list.Items = append(list.Items, clusterapiv1.ManagedCluster{
+			TypeMeta:   metav1.TypeMeta{APIVersion: clusterapiv1.GroupVersion.String(), Kind: "ManagedCluster"},
+			ObjectMeta: metav1.ObjectMeta{Name: cluster.Name},
+			Status:     clusterapiv1.ManagedClusterStatus{ClusterClaims: claims},
+		})
+	}
+	return list
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if clusterList, ok := obj.(*clusterapiv1.ManagedClusterList); ok {
+		for _, cluster := range clusterList.Items {
+			// the names of the claims are domains, their dots would be levels of the tree
+			claims := []string{}
+			for _, claim := range cluster.Status.ClusterClaims {
+				claims = append(claims, fmt.Sprintf("%s=%s", claim.Name, claim.Value))
+			}
+			mp := make(map[string]interface{})
+			mp[".Claims"] = strings.Join(claims, ", ")
+			tree.AddFileds(cluster.Name, &mp)
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Cluster", Type: "string"},
+			{Name: "Claim", Type: "string"},
+			{Name: "Value", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if clusterList, ok := obj.(*clusterapiv1.ManagedClusterList); ok {
+		for _, cluster := range clusterList.Items {
+			cluster := cluster
+			for _, claim := range cluster.Status.ClusterClaims {
+				table.Rows = append(table.Rows, metav1.TableRow{
+					Cells:  []interface{}{cluster.Name, claim.Name, claim.Value},
+					Object: runtime.RawExtension{Object: &cluster},
+				})
+			}
+		}
+	}
+
+	return table
+}
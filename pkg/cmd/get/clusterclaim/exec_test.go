@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterclaim
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func newCluster(name string, claims ...string) clusterapiv1.ManagedCluster {
+	cluster := clusterapiv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"a": "b"}}}
+	for i := 0; i < len(claims); i += 2 {
+		cluster.Status.ClusterClaims = append(cluster.Status.ClusterClaims, clusterapiv1.ManagedClusterClaim{Name: claims[i], Value: claims[i+1]})
+	}
+	return cluster
+}
+
+func TestClaimsOf(t *testing.T) {
+	clusters := []clusterapiv1.ManagedCluster{
+		newCluster("cluster1", "platform.open-cluster-management.io", "AWS", "region.open-cluster-management.io", "us-east-1"),
+		newCluster("cluster2"),
+	}
+	list := claimsOf(clusters, []string{"region.open-cluster-management.io"})
+	if len(list.Items) != 2 || len(list.Items[0].Labels) != 0 {
+		t.Fatalf("expected 2 clusters with only their name, got %v", list.Items)
+	}
+	expected := []clusterapiv1.ManagedClusterClaim{{Name: "region.open-cluster-management.io", Value: "us-east-1"}}
+	if !reflect.DeepEqual(list.Items[0].Status.ClusterClaims, expected) {
+		t.Errorf("expected the claims %v, got %v", expected, list.Items[0].Status.ClusterClaims)
+	}
+	if all := claimsOf(clusters, nil); len(all.Items[0].Status.ClusterClaims) != 2 {
+		t.Errorf("expected all the claims, got %v", all.Items[0].Status.ClusterClaims)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	if _, err := ParseSelector([]string{"platform.open-cluster-management.io"}); err == nil {
+		t.Errorf("expected an error without value")
+	}
+	if _, err := ParseSelector([]string{"=AWS"}); err == nil {
+		t.Errorf("expected an error without name")
+	}
+	selector, err := ParseSelector([]string{"platform.open-cluster-management.io=AWS", "region.open-cluster-management.io=us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster1 := newCluster("cluster1", "platform.open-cluster-management.io", "AWS", "region.open-cluster-management.io", "us-east-1")
+	cluster2 := newCluster("cluster2", "platform.open-cluster-management.io", "AWS", "region.open-cluster-management.io", "eu-west-1")
+	cluster3 := newCluster("cluster3")
+	if !Match(&cluster1, selector) || Match(&cluster2, selector) || Match(&cluster3, selector) {
+		t.Errorf("expected only cluster1 to match %v", selector)
+	}
+	if !Match(&cluster3, map[string]string{}) {
+		t.Errorf("expected an empty selector to match")
+	}
+}
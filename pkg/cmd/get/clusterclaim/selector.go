@@ -0,0 +1,39 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterclaim
+
+import (
+	"fmt"
+	"strings"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// ParseSelector parses the claims of the --claim flags in the format of name=value
+func ParseSelector(claims []string) (map[string]string, error) {
+	selector := map[string]string{}
+	for _, claim := range claims {
+		parts := strings.SplitN(claim, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid claim %q, it should be in the format of name=value", claim)
+		}
+		selector[parts[0]] = parts[1]
+	}
+	return selector, nil
+}
+
+// Match returns true if the cluster reports all the claims of the selector with their value
+func Match(cluster *clusterapiv1.ManagedCluster, selector map[string]string) bool {
+	for name, value := range selector {
+		found := false
+		for _, claim := range cluster.Status.ClusterClaims {
+			if claim.Name == name && claim.Value == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterpool
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Get the hive cluster pools of all the namespaces
+%[1]s get clusterpools
+
+# Get the cluster pools of a namespace with their claims
+%[1]s get clusterpools -n pools -o tree
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "clusterpools",
+		Aliases:      []string{"clusterpool"},
+		Short:        "get hive cluster pools",
+		Long:         "get the hive cluster pools with their clusters, their claims and the clusterset of their clusters",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the cluster pools, all the namespaces if not set")
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
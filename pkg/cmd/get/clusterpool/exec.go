@@ -0,0 +1,138 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterpool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hive"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get clusterpools options:", "namespace", o.namespace)
+
+	if len(args) > 1 {
+		return fmt.Errorf("can only specify one cluster pool")
+	}
+	if len(args) == 1 {
+		o.poolName = args[0]
+	}
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	listOptions := metav1.ListOptions{}
+	if len(o.poolName) > 0 {
+		listOptions.FieldSelector = fmt.Sprintf("metadata.name=%s", o.poolName)
+	}
+	pools, err := dynamicClient.Resource(hive.ClusterPoolGVR).Namespace(o.namespace).List(context.TODO(), listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list cluster pools, is hive installed? %v", err)
+	}
+	claims, err := dynamicClient.Resource(hive.ClusterClaimGVR).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list cluster claims: %v", err)
+	}
+	o.claims = claimsOfPools(claims.Items)
+
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, pools)
+}
+
+// claimsOfPools returns the claims of each pool in the format of <claim>[=<cluster>]
+func claimsOfPools(claims []unstructured.Unstructured) map[string][]string {
+	result := map[string][]string{}
+	for _, claim := range claims {
+		key := claim.GetNamespace() + "/" + hive.ClaimPool(claim)
+		value := claim.GetName()
+		if cluster := hive.ClaimedCluster(claim); len(cluster) > 0 {
+			value = fmt.Sprintf("%s=%s", value, cluster)
+		}
+		result[key] = append(result[key], value)
+	}
+	for _, values := range result {
+		sort.Strings(values)
+	}
+	return result
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if poolList, ok := obj.(*unstructured.UnstructuredList); ok {
+		for _, pool := range poolList.Items {
+			size, ready, standby, clusterSet, claims := o.getFileds(pool)
+			mp := make(map[string]interface{})
+			mp[".Namespace"] = pool.GetNamespace()
+			mp[".Size"] = size
+			mp[".Ready"] = ready
+			mp[".Standby"] = standby
+			mp[".ClusterSet"] = clusterSet
+			mp[".Claims"] = claims
+
+			tree.AddFileds(pool.GetName(), &mp)
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Namespace", Type: "string"},
+			{Name: "Size", Type: "integer"},
+			{Name: "Ready", Type: "integer"},
+			{Name: "Standby", Type: "integer"},
+			{Name: "ClusterSet", Type: "string"},
+			{Name: "Claims", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if poolList, ok := obj.(*unstructured.UnstructuredList); ok {
+		for _, pool := range poolList.Items {
+			pool := pool
+			size, ready, standby, clusterSet, claims := o.getFileds(pool)
+			row := metav1.TableRow{
+				Cells:  []interface{}{pool.GetName(), pool.GetNamespace(), size, ready, standby, clusterSet, claims},
+				Object: runtime.RawExtension{Object: &pool},
+			}
+
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	return table
+}
+
+func (o *Options) getFileds(pool unstructured.Unstructured) (size, ready, standby int64, clusterSet, claims string) {
+	size, ready, standby = hive.PoolStatus(pool)
+	clusterSet = pool.GetLabels()[hive.ClusterSetLabel]
+	claims = strings.Join(o.claims[pool.GetNamespace()+"/"+pool.GetName()], ",")
+	return
+}
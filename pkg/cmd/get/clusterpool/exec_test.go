@@ -0,0 +1,36 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterpool
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hive"
+)
+
+func TestClaimsOfPools(t *testing.T) {
+	newClaim := func(namespace, name, pool, cluster string) unstructured.Unstructured {
+		claim := hive.NewClaim(namespace, name, pool, "")
+		if len(cluster) > 0 {
+			_ = unstructured.SetNestedField(claim.Object, cluster, "spec", "namespace")
+		}
+		return *claim
+	}
+
+	claims := []unstructured.Unstructured{
+		newClaim("pools", "claim2", "pool1", ""),
+		newClaim("pools", "claim1", "pool1", "pool1-abcde"),
+		newClaim("pools", "claim3", "pool2", "pool2-fghij"),
+		newClaim("other", "claim4", "pool1", ""),
+	}
+
+	expected := map[string][]string{
+		"pools/pool1": {"claim1=pool1-abcde", "claim2"},
+		"pools/pool2": {"claim3=pool2-fghij"},
+		"other/pool1": {"claim4"},
+	}
+	if actual := claimsOfPools(claims); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
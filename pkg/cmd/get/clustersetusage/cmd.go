@@ -0,0 +1,55 @@
+// Copyright Contributors to the Open Cluster Management project
+package clustersetusage
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get the usage of each clusterset
+%[1]s get clusterset-usage
+# Get the usage of specific clustersets in json
+%[1]s get clusterset-usage dev prod -o json
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "clusterset-usage [CLUSTERSET...]",
+		Short: "get the usage of the clustersets",
+		Long: "get the member clusters of each clusterset with the unreachable ones, the namespaces it is bound to, " +
+			"the placements selecting clusters from it and the addons installed by these placements",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format, should be json or text")
+
+	return cmd
+}
@@ -0,0 +1,243 @@
+// Copyright Contributors to the Open Cluster Management project
+package clustersetusage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+)
+
+var clusterManagementAddOnGVR = schema.GroupVersionResource{
+	Group:    "addon.open-cluster-management.io",
+	Version:  "v1alpha1",
+	Resource: "clustermanagementaddons",
+}
+
+// Usage is how a clusterset is used
+type Usage struct {
+	Name    string `json:"name"`
+	Members int    `json:"members"`
+	// Unreachable are the members which are not available
+	Unreachable     []string `json:"unreachable"`
+	BoundNamespaces []string `json:"boundNamespaces"`
+	// Placements are the placements selecting clusters from the clusterset, in the format of namespace/name
+	Placements []string `json:"placements"`
+	// Addons are the addons installed by the placements
+	Addons []string `json:"addons"`
+}
+
+// hubResources are the resources of the hub the usage is computed from
+type hubResources struct {
+	clusterSets []clusterv1beta1.ManagedClusterSet
+	clusters    []clusterv1.ManagedCluster
+	bindings    []clusterv1beta1.ManagedClusterSetBinding
+	placements  []clusterv1beta1.Placement
+	addons      []unstructured.Unstructured
+}
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get clusterset-usage options:", "clustersets", args, "output", o.output)
+	o.clusterSets = args
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("output should be json or text")
+	}
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	resources := hubResources{}
+	clusterSets, err := clusterClient.ClusterV1beta1().ManagedClusterSets().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	resources.clusterSets = filterClusterSets(clusterSets.Items, o.clusterSets)
+	clusters, err := clusterClient.ClusterV1().ManagedClusters().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	resources.clusters = clusters.Items
+	bindings, err := clusterClient.ClusterV1beta1().ManagedClusterSetBindings(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	resources.bindings = bindings.Items
+	placements, err := clusterClient.ClusterV1beta1().Placements(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	resources.placements = placements.Items
+	addons, err := dynamicClient.Resource(clusterManagementAddOnGVR).List(context.TODO(), metav1.ListOptions{})
+	switch {
+	case errors.IsNotFound(err) || meta.IsNoMatchError(err):
+		// the addon framework is not installed
+	case err != nil:
+		return err
+	default:
+		resources.addons = addons.Items
+	}
+
+	usages, err := computeUsages(resources)
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		return clusteradmjson.WriteJsonOutput(o.Streams.Out, usages)
+	}
+	return printUsages(o.Streams.Out, usages)
+}
+
+// filterClusterSets returns the clustersets of the names, all the clustersets if names is empty
+func filterClusterSets(clusterSets []clusterv1beta1.ManagedClusterSet, names []string) []clusterv1beta1.ManagedClusterSet {
+	if len(names) == 0 {
+		return clusterSets
+	}
+	wanted := sets.NewString(names...)
+	filtered := []clusterv1beta1.ManagedClusterSet{}
+	for _, clusterSet := range clusterSets {
+		if wanted.Has(clusterSet.Name) {
+			filtered = append(filtered, clusterSet)
+		}
+	}
+	return filtered
+}
+
+// computeUsages returns the usage of each clusterset, sorted by name
+func computeUsages(resources hubResources) ([]Usage, error) {
+	// the clustersets bound to each namespace
+	boundClusterSets := map[string]sets.String{}
+	for _, binding := range resources.bindings {
+		if _, ok := boundClusterSets[binding.Namespace]; !ok {
+			boundClusterSets[binding.Namespace] = sets.NewString()
+		}
+		boundClusterSets[binding.Namespace].Insert(binding.Spec.ClusterSet)
+	}
+
+	// the placements selecting clusters from each clusterset, a placement without clustersets selects from
+	// all the clustersets bound to its namespace
+	placementsOfClusterSets := map[string]sets.String{}
+	for _, placement := range resources.placements {
+		bound := boundClusterSets[placement.Namespace]
+		if bound == nil {
+			continue
+		}
+		clusterSets := bound
+		if len(placement.Spec.ClusterSets) > 0 {
+			clusterSets = bound.Intersection(sets.NewString(placement.Spec.ClusterSets...))
+		}
+		for _, clusterSet := range clusterSets.List() {
+			if _, ok := placementsOfClusterSets[clusterSet]; !ok {
+				placementsOfClusterSets[clusterSet] = sets.NewString()
+			}
+			placementsOfClusterSets[clusterSet].Insert(placement.Namespace + "/" + placement.Name)
+		}
+	}
+
+	// the addons installed by each placement
+	addonsOfPlacements := map[string]sets.String{}
+	for _, addon := range resources.addons {
+		placements, _, err := unstructured.NestedSlice(addon.Object, "spec", "installStrategy", "placements")
+		if err != nil {
+			return nil, fmt.Errorf("invalid install strategy of addon %s: %v", addon.GetName(), err)
+		}
+		for _, p := range placements {
+			ref, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := fmt.Sprintf("%v/%v", ref["namespace"], ref["name"])
+			if _, ok := addonsOfPlacements[key]; !ok {
+				addonsOfPlacements[key] = sets.NewString()
+			}
+			addonsOfPlacements[key].Insert(addon.GetName())
+		}
+	}
+
+	usages := []Usage{}
+	for _, clusterSet := range resources.clusterSets {
+		clusterSet := clusterSet
+		selector, err := clusterv1beta1.BuildClusterSelector(&clusterSet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster selector of clusterset %s: %v", clusterSet.Name, err)
+		}
+		usage := Usage{Name: clusterSet.Name, Unreachable: []string{}, BoundNamespaces: []string{}, Addons: []string{}}
+		for _, cluster := range resources.clusters {
+			if !selector.Matches(labels.Set(cluster.Labels)) {
+				continue
+			}
+			usage.Members++
+			if !meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+				usage.Unreachable = append(usage.Unreachable, cluster.Name)
+			}
+		}
+		sort.Strings(usage.Unreachable)
+		for namespace, bound := range boundClusterSets {
+			if bound.Has(clusterSet.Name) {
+				usage.BoundNamespaces = append(usage.BoundNamespaces, namespace)
+			}
+		}
+		sort.Strings(usage.BoundNamespaces)
+		usage.Placements = placementsOfClusterSets[clusterSet.Name].List()
+		addons := sets.NewString()
+		for _, placement := range usage.Placements {
+			addons.Insert(addonsOfPlacements[placement].List()...)
+		}
+		usage.Addons = addons.List()
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Name < usages[j].Name })
+	return usages, nil
+}
+
+func printUsages(out io.Writer, usages []Usage) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTERSET\tMEMBERS\tUNREACHABLE\tBOUND NAMESPACES\tPLACEMENTS\tADDONS")
+	for _, usage := range usages {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", usage.Name, usage.Members, orNone(usage.Unreachable),
+			orNone(usage.BoundNamespaces), orNone(usage.Placements), orNone(usage.Addons))
+	}
+	return w.Flush()
+}
+
+func orNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,121 @@
+// Copyright Contributors to the Open Cluster Management project
+package clustersetusage
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+func TestComputeUsages(t *testing.T) {
+	cluster := func(name, clusterSet string, available bool) clusterv1.ManagedCluster {
+		status := metav1.ConditionFalse
+		if available {
+			status = metav1.ConditionTrue
+		}
+		return clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{clusterv1beta1.ClusterSetLabel: clusterSet, "env": clusterSet}},
+			Status: clusterv1.ManagedClusterStatus{Conditions: []metav1.Condition{
+				{Type: clusterv1.ManagedClusterConditionAvailable, Status: status},
+			}},
+		}
+	}
+	binding := func(namespace, clusterSet string) clusterv1beta1.ManagedClusterSetBinding {
+		return clusterv1beta1.ManagedClusterSetBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterSet, Namespace: namespace},
+			Spec:       clusterv1beta1.ManagedClusterSetBindingSpec{ClusterSet: clusterSet},
+		}
+	}
+	placement := func(namespace, name string, clusterSets ...string) clusterv1beta1.Placement {
+		return clusterv1beta1.Placement{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       clusterv1beta1.PlacementSpec{ClusterSets: clusterSets},
+		}
+	}
+	addon := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "application-manager"},
+		"spec": map[string]interface{}{"installStrategy": map[string]interface{}{
+			"type":       "Placements",
+			"placements": []interface{}{map[string]interface{}{"namespace": "team-a", "name": "all"}},
+		}},
+	}}
+
+	usages, err := computeUsages(hubResources{
+		clusterSets: []clusterv1beta1.ManagedClusterSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: "prod"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "dev-selector"},
+				Spec: clusterv1beta1.ManagedClusterSetSpec{ClusterSelector: clusterv1beta1.ManagedClusterSelector{
+					SelectorType:  clusterv1beta1.LabelSelector,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+				}},
+			},
+		},
+		clusters: []clusterv1.ManagedCluster{
+			cluster("dev1", "dev", true),
+			cluster("dev2", "dev", false),
+			cluster("prod1", "prod", true),
+		},
+		bindings: []clusterv1beta1.ManagedClusterSetBinding{
+			binding("team-a", "dev"),
+			binding("team-a", "prod"),
+			binding("team-b", "dev"),
+		},
+		placements: []clusterv1beta1.Placement{
+			placement("team-a", "all"),
+			placement("team-b", "dev-only", "dev"),
+			placement("team-b", "not-bound", "prod"),
+			placement("team-c", "no-binding"),
+		},
+		addons: []unstructured.Unstructured{addon},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Usage{
+		{
+			Name: "dev", Members: 2, Unreachable: []string{"dev2"}, BoundNamespaces: []string{"team-a", "team-b"},
+			Placements: []string{"team-a/all", "team-b/dev-only"}, Addons: []string{"application-manager"},
+		},
+		{
+			Name: "dev-selector", Members: 2, Unreachable: []string{"dev2"}, BoundNamespaces: []string{},
+			Placements: []string{}, Addons: []string{},
+		},
+		{
+			Name: "prod", Members: 1, Unreachable: []string{}, BoundNamespaces: []string{"team-a"},
+			Placements: []string{"team-a/all"}, Addons: []string{"application-manager"},
+		},
+	}
+	if !reflect.DeepEqual(usages, expected) {
+		t.Errorf("expected %+v, got %+v", expected, usages)
+	}
+
+	out := &bytes.Buffer{}
+	if err := printUsages(out, usages); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[2], "<none>") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
+
+func TestFilterClusterSets(t *testing.T) {
+	clusterSets := []clusterv1beta1.ManagedClusterSet{
+		{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prod"}},
+	}
+	if got := filterClusterSets(clusterSets, nil); len(got) != 2 {
+		t.Errorf("expected all the clustersets, got %v", got)
+	}
+	if got := filterClusterSets(clusterSets, []string{"prod"}); len(got) != 1 || got[0].Name != "prod" {
+		t.Errorf("expected prod, got %v", got)
+	}
+}
@@ -4,12 +4,22 @@ package get
 import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/access"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/addon"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/addonplacementscore"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/application"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/cluster"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/clusterclaim"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/clusterpool"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/clusterset"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/clustersetusage"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/csr"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/hubinfo"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/hubresources"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/klusterletinfo"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/lease"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/placement"
+	"open-cluster-management.io/clusteradm/pkg/cmd/get/policy"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/token"
 	"open-cluster-management.io/clusteradm/pkg/cmd/get/work"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
@@ -24,12 +34,22 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 
 	cmd.AddCommand(token.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(addon.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(addonplacementscore.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(cluster.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(clusterclaim.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(clusterset.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(clustersetusage.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(hubinfo.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(hubresources.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(klusterletinfo.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(work.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(placement.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(policy.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(application.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(lease.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(access.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(clusterpool.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(csr.NewCmd(clusteradmFlags, streams))
 
 	return cmd
 }
@@ -0,0 +1,23 @@
+// Copyright Contributors to the Open Cluster Management project
+package get
+
+import (
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rbac"
+)
+
+// TestRBACRules fails if a get command or one of its aliases has no rbac rules of its own, the rules of get
+// would be granted to it by --show-required-rbac and generate ci-credentials
+func TestRBACRules(t *testing.T) {
+	cmd := NewCmd(genericclioptionsclusteradm.NewClusteradmFlags(nil), genericclioptions.NewTestIOStreamsDiscard())
+	for _, sub := range cmd.Commands() {
+		for _, name := range append([]string{sub.Name()}, sub.Aliases...) {
+			if !rbac.Known("get " + name) {
+				t.Errorf("the rbac rules of get %s are unknown, add them to pkg/helpers/rbac", name)
+			}
+		}
+	}
+}
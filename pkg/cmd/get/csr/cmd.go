@@ -0,0 +1,60 @@
+// Copyright Contributors to the Open Cluster Management project
+package csr
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get the registration requests of the managed clusters
+%[1]s get csr
+# Only get the requests waiting to be accepted
+%[1]s get csr --pending-only
+# Get the requests of specific managed clusters
+%[1]s get csr --clusters cluster1,cluster2
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "csr",
+		Aliases:      []string{"csrs"},
+		Short:        "get the registration requests of the managed clusters",
+		Long:         "get the CSRs of the managed clusters with their requester, age, signer and approval state, to review them before running accept",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.clusters, "clusters", []string{}, "Names of the managed clusters (comma separated), defaults to all the managed clusters")
+	cmd.Flags().BoolVar(&o.pendingOnly, "pending-only", false, "Only show the requests which are neither approved nor denied")
+
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,174 @@
+// Copyright Contributors to the Open Cluster Management project
+package csr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/cmd/accept"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+// clusterLabel is set by the registration agent on the CSRs of its cluster
+const clusterLabel = "open-cluster-management.io/cluster-name"
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get csr options:", "clusters", o.clusters, "pending-only", o.pendingOnly)
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	kubeClient, _, _, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+
+	csrList, err := listCSRs(kubeClient, o.clusters, o.pendingOnly)
+	if err != nil {
+		return err
+	}
+
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, csrList)
+}
+
+// listCSRs returns the registration requests of the clusters, of all the clusters if empty, sorted by
+// cluster and creation time
+func listCSRs(kubeClient kubernetes.Interface, clusters []string, pendingOnly bool) (*certificatesv1.CertificateSigningRequestList, error) {
+	selector := clusterLabel
+	if len(clusters) > 0 {
+		selector = fmt.Sprintf("%s in (%s)", clusterLabel, strings.Join(clusters, ","))
+	}
+	csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := sets.NewString(clusters...)
+	csrList := &certificatesv1.CertificateSigningRequestList{}
+	for _, csr := range csrs.Items {
+		if wanted.Len() > 0 && !wanted.Has(csr.Labels[clusterLabel]) {
+			continue
+		}
+		if pendingOnly {
+			if approved, denied := accept.GetCertApprovalCondition(&csr.Status); approved || denied {
+				continue
+			}
+		}
+		csrList.Items = append(csrList.Items, csr)
+	}
+	sort.SliceStable(csrList.Items, func(i, j int) bool {
+		ci, cj := csrList.Items[i], csrList.Items[j]
+		if ci.Labels[clusterLabel] != cj.Labels[clusterLabel] {
+			return ci.Labels[clusterLabel] < cj.Labels[clusterLabel]
+		}
+		return ci.CreationTimestamp.Before(&cj.CreationTimestamp)
+	})
+	return csrList, nil
+}
+
+// condition returns the approval state of the request in the format of kubectl get csr
+func condition(csr *certificatesv1.CertificateSigningRequest) string {
+	approved, denied := accept.GetCertApprovalCondition(&csr.Status)
+	var conditions []string
+	switch {
+	case denied:
+		conditions = append(conditions, "Denied")
+	case approved:
+		conditions = append(conditions, "Approved")
+	default:
+		conditions = append(conditions, "Pending")
+	}
+	for _, c := range csr.Status.Conditions {
+		if c.Type == certificatesv1.CertificateFailed {
+			conditions = append(conditions, "Failed")
+		}
+	}
+	if len(csr.Status.Certificate) > 0 {
+		conditions = append(conditions, "Issued")
+	}
+	return strings.Join(conditions, ",")
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if csrList, ok := obj.(*certificatesv1.CertificateSigningRequestList); ok {
+		for _, csr := range csrList.Items {
+			csr := csr
+			cluster, requester, age, signer, state := getFileds(&csr)
+			mp := make(map[string]interface{})
+			mp[".Cluster"] = cluster
+			mp[".Requester"] = requester
+			mp[".Age"] = age
+			mp[".Signer"] = signer
+			mp[".Condition"] = state
+
+			tree.AddFileds(csr.Name, &mp)
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Cluster", Type: "string"},
+			{Name: "Requester", Type: "string"},
+			{Name: "Age", Type: "string"},
+			{Name: "Signer", Type: "string"},
+			{Name: "Condition", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if csrList, ok := obj.(*certificatesv1.CertificateSigningRequestList); ok {
+		for _, csr := range csrList.Items {
+			csr := csr
+			cluster, requester, age, signer, state := getFileds(&csr)
+			row := metav1.TableRow{
+				Cells:  []interface{}{csr.Name, cluster, requester, age, signer, state},
+				Object: runtime.RawExtension{Object: &csr},
+			}
+
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	return table
+}
+
+func getFileds(csr *certificatesv1.CertificateSigningRequest) (cluster, requester, age, signer, state string) {
+	cluster = csr.Labels[clusterLabel]
+	requester = csr.Spec.Username
+	age = duration.HumanDuration(time.Since(csr.CreationTimestamp.Time))
+	signer = csr.Spec.SignerName
+	state = condition(csr)
+	return
+}
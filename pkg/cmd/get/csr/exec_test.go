@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+package csr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+)
+
+func newCSR(name, cluster string, created time.Time, conditions ...certificatesv1.RequestConditionType) *certificatesv1.CertificateSigningRequest {
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			SignerName: certificatesv1.KubeAPIServerClientSignerName,
+			Username:   "system:serviceaccount:open-cluster-management:cluster-bootstrap",
+		},
+	}
+	if len(cluster) > 0 {
+		csr.Labels = map[string]string{clusterLabel: cluster}
+	}
+	for _, c := range conditions {
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{Type: c})
+	}
+	return csr
+}
+
+func TestListCSRs(t *testing.T) {
+	now := time.Now()
+	kubeClient := fakekube.NewSimpleClientset(
+		newCSR("cluster2-abc", "cluster2", now),
+		newCSR("cluster1-new", "cluster1", now),
+		newCSR("cluster1-old", "cluster1", now.Add(-time.Hour), certificatesv1.CertificateApproved),
+		newCSR("cluster3-abc", "cluster3", now, certificatesv1.CertificateDenied),
+		newCSR("node-csr", "", now),
+	)
+	cases := []struct {
+		name        string
+		clusters    []string
+		pendingOnly bool
+		expected    []string
+	}{
+		{
+			name:     "all the clusters",
+			expected: []string{"cluster1-old", "cluster1-new", "cluster2-abc", "cluster3-abc"},
+		},
+		{
+			name:        "pending only",
+			pendingOnly: true,
+			expected:    []string{"cluster1-new", "cluster2-abc"},
+		},
+		{
+			name:     "specific clusters",
+			clusters: []string{"cluster1", "cluster3"},
+			expected: []string{"cluster1-old", "cluster1-new", "cluster3-abc"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			csrList, err := listCSRs(kubeClient, c.clusters, c.pendingOnly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, csr := range csrList.Items {
+				names = append(names, csr.Name)
+			}
+			if !reflect.DeepEqual(names, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, names)
+			}
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	issued := newCSR("issued", "cluster1", time.Now(), certificatesv1.CertificateApproved)
+	issued.Status.Certificate = []byte("cert")
+	cases := map[string]*certificatesv1.CertificateSigningRequest{
+		"Pending":         newCSR("pending", "cluster1", time.Now()),
+		"Approved,Issued": issued,
+		"Denied":          newCSR("denied", "cluster1", time.Now(), certificatesv1.CertificateDenied),
+		"Approved,Failed": newCSR("failed", "cluster1", time.Now(), certificatesv1.CertificateApproved, certificatesv1.CertificateFailed),
+	}
+	for expected, csr := range cases {
+		if got := condition(csr); got != expected {
+			t.Errorf("expected %s for %s, got %s", expected, csr.Name, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright Contributors to the Open Cluster Management project
+package hubresources
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# List the resources of the hub managed by clusteradm with their status
+%[1]s get hub-resources
+# List them in json with the fields changed since they were applied
+%[1]s get hub-resources -o json
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "hub-resources",
+		Short: "list the resources of the hub managed by clusteradm",
+		Long: "list the resources of the hub installed by init and by the cluster manager: the deployments, the webhooks, " +
+			"the CRDs and the secrets, with their version, who manages them and their status. The resources rendered from the " +
+			"templates of init are compared with the templates of the bundle version of the hub, their status is drift if " +
+			"they were changed since, the deployments are unavailable if their replicas are not available.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format, should be json or text")
+	cmd.Flags().StringVar(&o.bundleVersion, "bundle-version", "", "The bundle version the templates are rendered for, detected from the cluster manager by default")
+	cmd.Flags().StringVar(&o.size, "size", "small", "The --size the hub was initialized with, small, medium or large")
+
+	return cmd
+}
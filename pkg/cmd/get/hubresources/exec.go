@@ -0,0 +1,180 @@
+// Copyright Contributors to the Open Cluster Management project
+package hubresources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/cmd/hub/diff"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/drift"
+	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+)
+
+// ocmSuffix is the suffix of the groups of the CRDs and of the names of the webhooks of the hub
+const ocmSuffix = "open-cluster-management.io"
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get hub-resources options:", "output", o.output, "bundle-version", o.bundleVersion, "size", o.size)
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("output should be json or text")
+	}
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	f := o.ClusteradmFlags.KubectlFactory
+	kubeClient, apiExtensionsClient, dynamicClient, err := helpers.GetClients(f)
+	if err != nil {
+		return err
+	}
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	mapper, err := f.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	inv := newInventory()
+
+	// the resources rendered from the templates of init are compared with their templates
+	cm, err := operatorClient.OperatorV1().ClusterManagers().Get(ctx, diff.ClusterManagerName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		fmt.Fprintf(o.Streams.ErrOut, "The cluster manager is not installed, the resources of init are not listed\n")
+	case err != nil:
+		return err
+	default:
+		objs, _, err := diff.Templates(kubeClient, dynamicClient, cm, o.bundleVersion, o.size)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			gvk := obj.GroupVersionKind()
+			mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err != nil {
+				return err
+			}
+			live, err := dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				inv.add(Resource{Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Version: "-", ManagedBy: "-", Status: statusMissing})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			r := newResource(gvk.Kind, live, imagesOfObject(live.Object), statusOK)
+			for _, d := range drift.Compare(obj.Object, live.Object) {
+				r.Differences = append(r.Differences, d.String())
+			}
+			if len(r.Differences) > 0 {
+				r.Status = statusDrift
+			}
+			inv.add(r)
+		}
+	}
+
+	deployments, err := kubeClient.AppsV1().Deployments(config.HubClusterNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		inv.add(newResource("Deployment", deployment, imagesOf(deployment.Spec.Template.Spec), deploymentStatus(deployment)))
+	}
+
+	validatingWebhooks, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range validatingWebhooks.Items {
+		if webhook := &validatingWebhooks.Items[i]; strings.HasSuffix(webhook.Name, "."+ocmSuffix) {
+			inv.add(newResource("ValidatingWebhookConfiguration", webhook, nil, statusOK))
+		}
+	}
+	mutatingWebhooks, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range mutatingWebhooks.Items {
+		if webhook := &mutatingWebhooks.Items[i]; strings.HasSuffix(webhook.Name, "."+ocmSuffix) {
+			inv.add(newResource("MutatingWebhookConfiguration", webhook, nil, statusOK))
+		}
+	}
+
+	crds, err := apiExtensionsClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range crds.Items {
+		if crd := &crds.Items[i]; strings.HasSuffix(crd.Spec.Group, ocmSuffix) {
+			inv.add(newResource("CustomResourceDefinition", crd, nil, crdStatus(crd)))
+		}
+	}
+
+	secrets, err := kubeClient.CoreV1().Secrets(config.HubClusterNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		// the tokens of the service accounts are managed by kubernetes
+		if secret := &secrets.Items[i]; secret.Type != corev1.SecretTypeServiceAccountToken {
+			inv.add(newResource("Secret", secret, nil, statusOK))
+		}
+	}
+	// the bootstrap tokens created by init
+	tokens, err := kubeClient.CoreV1().Secrets(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{config.LabelApp: config.ClusterManagerName}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range tokens.Items {
+		token := &tokens.Items[i]
+		inv.add(newResource("Secret", token, nil, tokenStatus(token, now)))
+	}
+
+	if o.output == "json" {
+		return clusteradmjson.WriteJsonOutput(o.Streams.Out, inv.resources)
+	}
+	return printResources(o.Streams.Out, inv.resources)
+}
+
+// imagesOfObject returns the images of the containers of a deployment, empty for the other objects
+func imagesOfObject(obj map[string]interface{}) []string {
+	containers, _, _ := unstructured.NestedSlice(obj, "spec", "template", "spec", "containers")
+	images := []string{}
+	for _, c := range containers {
+		if container, ok := c.(map[string]interface{}); ok {
+			if image, ok := container["image"].(string); ok {
+				images = append(images, image)
+			}
+		}
+	}
+	return images
+}
@@ -0,0 +1,177 @@
+// Copyright Contributors to the Open Cluster Management project
+package hubresources
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/version"
+)
+
+const (
+	statusOK             = "ok"
+	statusDrift          = "drift"
+	statusMissing        = "missing"
+	statusUnavailable    = "unavailable"
+	statusNotEstablished = "not-established"
+	statusExpired        = "expired"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	// tokenExpirationKey is the key of the expiration of a bootstrap token secret, in RFC3339
+	tokenExpirationKey = "expiration"
+)
+
+// Resource is a resource of the hub managed by clusteradm
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Version is the image tag of a deployment, the clusteradm version which last applied the resource otherwise
+	Version string `json:"version"`
+	// ManagedBy is the installer of an attached resource, clusteradm, the owner of the resource or its managed-by label
+	ManagedBy string `json:"managedBy"`
+	Status    string `json:"status"`
+	// Differences are the fields changed since the resource was rendered from the templates of init
+	Differences []string `json:"differences,omitempty"`
+}
+
+func (r Resource) key() string {
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// inventory is the resources of the hub, each resource is listed once
+type inventory struct {
+	resources []Resource
+	seen      map[string]bool
+}
+
+func newInventory() *inventory {
+	return &inventory{resources: []Resource{}, seen: map[string]bool{}}
+}
+
+func (i *inventory) add(r Resource) {
+	if i.seen[r.key()] {
+		return
+	}
+	i.seen[r.key()] = true
+	i.resources = append(i.resources, r)
+}
+
+// newResource returns the resource of the object, the images are the images of its containers if any
+func newResource(kind string, obj metav1.Object, images []string, status string) Resource {
+	return Resource{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Version:   versionOf(obj, images),
+		ManagedBy: managedByOf(obj),
+		Status:    status,
+	}
+}
+
+// versionOf returns the tag of the first image, the version of the clusteradm which last applied the object
+// if it has no image, or - if neither is known
+func versionOf(obj metav1.Object, images []string) string {
+	if len(images) > 0 {
+		if tag := version.ImageVersion(images[0]); len(tag) > 0 {
+			return tag
+		}
+	}
+	if op, err := audit.Parse(obj.GetAnnotations()); err == nil && op != nil && len(op.Version) > 0 {
+		return op.Version
+	}
+	return "-"
+}
+
+// managedByOf returns who manages the object: the installer of an attached object, clusteradm if it applied
+// the object, the controller owning it or its managed-by label
+func managedByOf(obj metav1.Object) string {
+	if installation, err := attach.Parse(obj.GetAnnotations()); err == nil && installation != nil {
+		return fmt.Sprintf("attached (%s)", installation.Installer)
+	}
+	if op, err := audit.Parse(obj.GetAnnotations()); err == nil && op != nil {
+		return "clusteradm"
+	}
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		return owner.Kind + "/" + owner.Name
+	}
+	if managedBy, ok := obj.GetLabels()[managedByLabel]; ok {
+		return managedBy
+	}
+	return "-"
+}
+
+func imagesOf(spec corev1.PodSpec) []string {
+	images := []string{}
+	for _, container := range spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+func deploymentStatus(deployment *appsv1.Deployment) string {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	if deployment.Status.AvailableReplicas < replicas {
+		return statusUnavailable
+	}
+	return statusOK
+}
+
+func crdStatus(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+			return statusOK
+		}
+	}
+	return statusNotEstablished
+}
+
+// tokenStatus returns expired if the bootstrap token of the secret expired before now
+func tokenStatus(secret *corev1.Secret, now time.Time) string {
+	expiration, ok := secret.Data[tokenExpirationKey]
+	if !ok {
+		return statusOK
+	}
+	t, err := time.Parse(time.RFC3339, string(expiration))
+	if err == nil && t.Before(now) {
+		return statusExpired
+	}
+	return statusOK
+}
+
+func printResources(out io.Writer, resources []Resource) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tVERSION\tMANAGED BY\tSTATUS")
+	for _, r := range resources {
+		namespace := r.Namespace
+		if len(namespace) == 0 {
+			namespace = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Kind, namespace, r.Name, r.Version, r.ManagedBy, r.Status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, r := range resources {
+		for _, d := range r.Differences {
+			fmt.Fprintf(out, "%s %s: %s\n", r.Kind, qualifiedName(r), d)
+		}
+	}
+	return nil
+}
+
+func qualifiedName(r Resource) string {
+	return strings.TrimPrefix(r.Namespace+"/"+r.Name, "/")
+}
@@ -0,0 +1,127 @@
+// Copyright Contributors to the Open Cluster Management project
+package hubresources
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+)
+
+func TestManagedByOf(t *testing.T) {
+	controller := true
+	cases := []struct {
+		name     string
+		meta     metav1.ObjectMeta
+		expected string
+	}{
+		{
+			name:     "attached",
+			meta:     metav1.ObjectMeta{Annotations: map[string]string{attach.Annotation: `{"installer":"Helm"}`}},
+			expected: "attached (Helm)",
+		},
+		{
+			name:     "applied by clusteradm",
+			meta:     metav1.ObjectMeta{Annotations: map[string]string{audit.Annotation: `{"version":"v0.2.0"}`}},
+			expected: "clusteradm",
+		},
+		{
+			name: "owned",
+			meta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ClusterManager", Name: "cluster-manager", Controller: &controller},
+			}},
+			expected: "ClusterManager/cluster-manager",
+		},
+		{
+			name:     "managed-by label",
+			meta:     metav1.ObjectMeta{Labels: map[string]string{managedByLabel: "Helm"}},
+			expected: "Helm",
+		},
+		{name: "unknown", expected: "-"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := managedByOf(&c.meta); got != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestVersionOf(t *testing.T) {
+	applied := &metav1.ObjectMeta{Annotations: map[string]string{audit.Annotation: `{"version":"v0.2.0"}`}}
+	if got := versionOf(applied, []string{"quay.io/open-cluster-management/registration:v0.9.0"}); got != "v0.9.0" {
+		t.Errorf("expected the image tag, got %q", got)
+	}
+	if got := versionOf(applied, []string{"quay.io/open-cluster-management/registration@sha256:abc"}); got != "v0.2.0" {
+		t.Errorf("expected the clusteradm version, got %q", got)
+	}
+	if got := versionOf(&metav1.ObjectMeta{}, nil); got != "-" {
+		t.Errorf("expected -, got %q", got)
+	}
+}
+
+func TestDeploymentStatus(t *testing.T) {
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}
+	deployment.Status.AvailableReplicas = 2
+	if got := deploymentStatus(deployment); got != statusUnavailable {
+		t.Errorf("expected %s, got %s", statusUnavailable, got)
+	}
+	deployment.Status.AvailableReplicas = 3
+	if got := deploymentStatus(deployment); got != statusOK {
+		t.Errorf("expected %s, got %s", statusOK, got)
+	}
+}
+
+func TestTokenStatus(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		data     map[string][]byte
+		expected string
+	}{
+		{name: "no expiration", expected: statusOK},
+		{name: "expired", data: map[string][]byte{tokenExpirationKey: []byte("2022-05-31T00:00:00Z")}, expected: statusExpired},
+		{name: "valid", data: map[string][]byte{tokenExpirationKey: []byte("2022-06-02T00:00:00Z")}, expected: statusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := tokenStatus(&corev1.Secret{Data: c.data}, now); got != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestPrintResources(t *testing.T) {
+	inv := newInventory()
+	inv.add(Resource{Kind: "Deployment", Name: "cluster-manager", Namespace: "open-cluster-management", Version: "v0.9.0",
+		ManagedBy: "clusteradm", Status: statusDrift, Differences: []string{"spec.replicas: 1 -> 2"}})
+	inv.add(Resource{Kind: "Deployment", Name: "cluster-manager", Namespace: "open-cluster-management"})
+	inv.add(Resource{Kind: "CustomResourceDefinition", Name: "managedclusters.cluster.open-cluster-management.io",
+		Version: "-", ManagedBy: "-", Status: statusOK})
+	if len(inv.resources) != 2 {
+		t.Fatalf("expected the duplicated resource to be listed once, got %d resources", len(inv.resources))
+	}
+
+	out := &bytes.Buffer{}
+	if err := printResources(out, inv.resources); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"KIND", "open-cluster-management", "drift",
+		"Deployment open-cluster-management/cluster-manager: spec.replicas: 1 -> 2",
+		"CustomResourceDefinition  -",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in the output:\n%s", expected, out.String())
+		}
+	}
+}
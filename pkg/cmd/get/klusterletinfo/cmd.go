@@ -6,13 +6,21 @@ import (
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
 )
 
 var example = `
 # Get klusterlet-info.
 %[1]s get klusterlet-info
+
+# Get klusterlet-info of the managed cluster cluster1 from the hub, a short-lived ManifestWork reads it
+%[1]s get klusterlet-info --cluster cluster1
+
+# Get klusterlet-info of the managed cluster cluster1 from the hub through cluster-proxy, with the managedServiceAccount reader
+%[1]s get klusterlet-info --cluster cluster1 --sa reader
 `
 
 // NewCmd...
@@ -35,6 +43,9 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 			if err := o.validate(args); err != nil {
 				return err
 			}
+			if o.closeProxy != nil {
+				defer o.closeProxy()
+			}
 			if err := o.run(); err != nil {
 				return err
 			}
@@ -43,5 +54,15 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 	}
 
+	cmd.Flags().StringVar(&o.klusterletName, "klusterlet-name", join.DefaultKlusterletName,
+		"The name of the klusterlet, the klusterlet of the hub on the clusters registered to several hubs")
+	cmd.Flags().StringVar(&o.cluster, "cluster", "",
+		"The managed cluster the klusterlet is read from through the hub of the context, for the clusters whose kubeconfig is not available. "+
+			"A short-lived ManifestWork reads the status of the klusterlet and its agents, or cluster-proxy if --sa is set")
+	cmd.Flags().StringVar(&o.managedServiceAccount, "sa", "",
+		"The managedServiceAccount reading the klusterlet, its deployments and CRDs through cluster-proxy. Only used with --cluster")
+	cmd.Flags().IntVar(&o.proxyServerPort, "proxy-server-port", clusterproxy.DefaultProxyServerPort,
+		"The local port forwarded to the proxy servers. Only used with --sa")
+
 	return cmd
 }
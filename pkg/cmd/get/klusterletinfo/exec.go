@@ -4,14 +4,22 @@ package klusterletinfo
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
 	v1 "open-cluster-management.io/api/operator/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+	"open-cluster-management.io/clusteradm/pkg/helpers/oplog"
 	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
 )
 
@@ -20,6 +28,16 @@ func (o *Options) complete(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if len(o.cluster) > 0 {
+		o.hubRestConfig = cfg
+		// the clients of the managed cluster are built through cluster-proxy, the work API is used otherwise
+		if len(o.managedServiceAccount) == 0 {
+			return nil
+		}
+		if cfg, err = o.proxyConfig(); err != nil {
+			return err
+		}
+	}
 	operatorClient, err := operatorclient.NewForConfig(cfg)
 	if err != nil {
 		return err
@@ -40,7 +58,17 @@ func (o *Options) complete(cmd *cobra.Command, args []string) error {
 }
 
 func (o *Options) validate(args []string) (err error) {
-	err = o.ClusteradmFlags.ValidateManagedCluster()
+	if err := join.ValidateKlusterletName(o.klusterletName); err != nil {
+		return err
+	}
+	if len(o.cluster) > 0 {
+		err = o.ClusteradmFlags.ValidateHub()
+	} else {
+		if len(o.managedServiceAccount) > 0 {
+			return fmt.Errorf("--sa is only supported with --cluster")
+		}
+		err = o.ClusteradmFlags.ValidateManagedCluster()
+	}
 	if err != nil {
 		return err
 	}
@@ -62,7 +90,10 @@ const (
 )
 
 func (o *Options) run() error {
-	k, err := o.operatorClient.OperatorV1().Klusterlets().Get(context.TODO(), klusterletName, metav1.GetOptions{})
+	if len(o.cluster) > 0 && o.operatorClient == nil {
+		return o.runQueryWork()
+	}
+	k, err := o.operatorClient.OperatorV1().Klusterlets().Get(context.TODO(), o.klusterletName, metav1.GetOptions{})
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
@@ -89,6 +120,61 @@ func (o *Options) run() error {
 	if err := o.printComponents(k); err != nil {
 		return err
 	}
+	// printing the join, unjoin and upgrade runs
+	if err := o.printOperations(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// proxyConfig returns the config of the managed cluster through the konnectivity tunnels of cluster-proxy,
+// authenticated with the token of the managedServiceAccount
+func (o *Options) proxyConfig() (*rest.Config, error) {
+	ctx := context.TODO()
+	proxyConfig, err := clusterproxy.GetProxyConfig(ctx, o.hubRestConfig)
+	if err != nil {
+		return nil, err
+	}
+	token, err := clusterproxy.ManagedServiceAccountToken(ctx, o.hubRestConfig, o.managedServiceAccount, o.cluster)
+	if err != nil {
+		return nil, err
+	}
+	dial, closeFn, err := clusterproxy.Dial(ctx, o.hubRestConfig, proxyConfig, o.proxyServerPort)
+	if err != nil {
+		return nil, err
+	}
+	o.closeProxy = closeFn
+	return clusterproxy.ManagedClusterConfig(o.cluster, token, dial), nil
+}
+
+func (o *Options) printOperations() error {
+	ops, err := oplog.List(o.kubeClient, config.ManagedClusterNamespace)
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		o.printer.Write(printer.LEVEL_0, "Operations:\t<none>\n")
+		return nil
+	}
+	o.printer.Write(printer.LEVEL_0, "Operations:\n")
+	for _, op := range ops {
+		o.printer.Write(printer.LEVEL_1, "%s:\n", op.Command)
+		o.printer.Write(printer.LEVEL_2, "Result:\t\t%s\n", op.Result)
+		if len(op.Error) > 0 {
+			o.printer.Write(printer.LEVEL_2, "Error:\t\t%s\n", op.Error)
+		}
+		if len(op.BundleVersion) > 0 {
+			o.printer.Write(printer.LEVEL_2, "BundleVersion:\t%s\n", op.BundleVersion)
+		}
+		o.printer.Write(printer.LEVEL_2, "StartTime:\t%s\n", op.StartTime.Format(time.RFC3339))
+		o.printer.Write(printer.LEVEL_2, "EndTime:\t%s\n", op.EndTime.Format(time.RFC3339))
+		flags := make([]string, 0, len(op.Flags))
+		for name, value := range op.Flags {
+			flags = append(flags, fmt.Sprintf("--%s=%s", name, value))
+		}
+		sort.Strings(flags)
+		o.printer.Write(printer.LEVEL_2, "Flags:\t\t%s\n", strings.Join(flags, " "))
+	}
 	return nil
 }
 
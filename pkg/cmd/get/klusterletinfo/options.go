@@ -5,6 +5,7 @@ import (
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
@@ -16,10 +17,23 @@ type Options struct {
 
 	Streams genericclioptions.IOStreams
 
+	//The name of the klusterlet
+	klusterletName string
+	//The managed cluster the klusterlet is read from through the hub, the cluster of the context if empty
+	cluster string
+	//The managedServiceAccount the klusterlet is read with through cluster-proxy, a ManifestWork reads it if empty
+	managedServiceAccount string
+	//The local port forwarded to the proxy servers
+	proxyServerPort int
+
 	printer        printer.PrefixWriter
 	operatorClient operatorclient.Interface
 	kubeClient     kubernetes.Interface
 	crdClient      clientset.Interface
+	//hubRestConfig: the config of the hub the cluster is read through
+	hubRestConfig *rest.Config
+	//closeProxy: closes the tunnel to the proxy servers
+	closeProxy func()
 }
 
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
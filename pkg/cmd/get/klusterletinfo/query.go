@@ -0,0 +1,238 @@
+// Copyright Contributors to the Open Cluster Management project
+package klusterletinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+const (
+	queryWorkPrefix = "clusteradm-klusterlet-info-"
+	// statusFeedbackSynced is the condition the work agent sets on the manifests once their status is fed back
+	statusFeedbackSynced = "StatusFeedbackSynced"
+)
+
+// klusterletConditionTypes are the conditions of the klusterlet read through the work API, the status
+// feedback only returns scalar values
+var klusterletConditionTypes = []string{
+	"Applied",
+	"Available",
+	"HubConnectionDegraded",
+	"RegistrationDesiredDegraded",
+	"WorkDesiredDegraded",
+}
+
+// queryWork returns the ManifestWork reading the status of the agents of the klusterlet and its operator, and
+// of the klusterlet if withKlusterlet is set. The resources are never updated nor deleted by the work agent, they
+// are orphaned and only created if they do not exist: the deployments without spec are rejected by the cluster,
+// while a klusterlet would be created from its name, so it is only read once its registration agent is found.
+func queryWork(cluster, name string, withKlusterlet bool) (*workapiv1.ManifestWork, error) {
+	agentNamespace := join.AgentNamespace(name)
+	type object struct {
+		apiVersion, kind, resource, group, namespace, name string
+	}
+	var objects []object
+	if withKlusterlet {
+		objects = append(objects, object{"operator.open-cluster-management.io/v1", "Klusterlet", "klusterlets", "operator.open-cluster-management.io", "", name})
+	}
+	objects = append(objects,
+		object{"apps/v1", "Deployment", "deployments", "apps", registrationOperatorNamespace, klusterletName},
+		object{"apps/v1", "Deployment", "deployments", "apps", agentNamespace, componentNameRegistrationAgent},
+		object{"apps/v1", "Deployment", "deployments", "apps", agentNamespace, componentNameWorkAgent},
+	)
+
+	jsonPaths := []workapiv1.JsonPath{{Name: "observedGeneration", Path: ".observedGeneration"}}
+	for _, conditionType := range klusterletConditionTypes {
+		for _, field := range []string{"status", "reason", "message"} {
+			jsonPaths = append(jsonPaths, workapiv1.JsonPath{
+				Name: conditionType + "." + field,
+				Path: fmt.Sprintf(`.conditions[?(@.type=="%s")].%s`, conditionType, field),
+			})
+		}
+	}
+
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      queryWorkPrefix + helpers.RandStringRunes_az09(6),
+			Namespace: cluster,
+		},
+		Spec: workapiv1.ManifestWorkSpec{
+			DeleteOption: &workapiv1.DeleteOption{PropagationPolicy: workapiv1.DeletePropagationPolicyTypeOrphan},
+		},
+	}
+	for _, object := range objects {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(object.apiVersion)
+		obj.SetKind(object.kind)
+		obj.SetNamespace(object.namespace)
+		obj.SetName(object.name)
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, workapiv1.Manifest{
+			RawExtension: runtime.RawExtension{Raw: data},
+		})
+
+		rule := workapiv1.FeedbackRule{Type: workapiv1.WellKnownStatusType}
+		if object.kind == "Klusterlet" {
+			rule = workapiv1.FeedbackRule{Type: workapiv1.JSONPathsType, JsonPaths: jsonPaths}
+		}
+		work.Spec.ManifestConfigs = append(work.Spec.ManifestConfigs, workapiv1.ManifestConfigOption{
+			ResourceIdentifier: workapiv1.ResourceIdentifier{
+				Group:     object.group,
+				Resource:  object.resource,
+				Namespace: object.namespace,
+				Name:      object.name,
+			},
+			FeedbackRules:  []workapiv1.FeedbackRule{rule},
+			UpdateStrategy: &workapiv1.UpdateStrategy{Type: workapiv1.UpdateStrategyTypeCreateOnly},
+		})
+	}
+	return work, nil
+}
+
+// feedbackSynced returns true once the status of all the manifests of the work is fed back, the
+// manifests of the missing resources fail to apply
+func feedbackSynced(work *workapiv1.ManifestWork) bool {
+	if len(work.Status.ResourceStatus.Manifests) < len(work.Spec.Workload.Manifests) {
+		return false
+	}
+	for _, manifest := range work.Status.ResourceStatus.Manifests {
+		if meta.FindStatusCondition(manifest.Conditions, statusFeedbackSynced) == nil &&
+			!meta.IsStatusConditionFalse(manifest.Conditions, string(workapiv1.ManifestApplied)) {
+			return false
+		}
+	}
+	return true
+}
+
+// feedbackOf returns the values fed back for the resource, nil if it is not in the status of the work
+func feedbackOf(work *workapiv1.ManifestWork, kind, name string) map[string]string {
+	for _, manifest := range work.Status.ResourceStatus.Manifests {
+		if manifest.ResourceMeta.Kind != kind || manifest.ResourceMeta.Name != name {
+			continue
+		}
+		values := map[string]string{}
+		for _, value := range manifest.StatusFeedbacks.Values {
+			switch {
+			case value.Value.String != nil:
+				values[value.Name] = *value.Value.String
+			case value.Value.Integer != nil:
+				values[value.Name] = fmt.Sprintf("%d", *value.Value.Integer)
+			case value.Value.Boolean != nil:
+				values[value.Name] = fmt.Sprintf("%t", *value.Value.Boolean)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// runQueryWork prints the status of the klusterlet read by short-lived ManifestWorks, for the clusters
+// whose kubeconfig is not available
+func (o *Options) runQueryWork() error {
+	workClient, err := workclientset.NewForConfig(o.hubRestConfig)
+	if err != nil {
+		return err
+	}
+	return o.runQueryWorkWithClient(workClient)
+}
+
+func (o *Options) runQueryWorkWithClient(workClient workclientset.Interface) error {
+	// the klusterlet is not read, and so not created, if it has not deployed its registration agent
+	work, err := o.applyQueryWork(workClient, false)
+	if err != nil {
+		return err
+	}
+	if len(feedbackOf(work, "Deployment", componentNameRegistrationAgent)) == 0 {
+		o.printer.Write(printer.LEVEL_0, "No klusterlet %s detected through the work agent of cluster %s!\n", o.klusterletName, o.cluster)
+		return nil
+	}
+	if work, err = o.applyQueryWork(workClient, true); err != nil {
+		return err
+	}
+	o.printQueryWork(work)
+	return nil
+}
+
+// applyQueryWork creates the query work, waits for the status of its resources and deletes it
+func (o *Options) applyQueryWork(workClient workclientset.Interface, withKlusterlet bool) (*workapiv1.ManifestWork, error) {
+	work, err := queryWork(o.cluster, o.klusterletName, withKlusterlet)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.TODO()
+	if work, err = workClient.WorkV1().ManifestWorks(o.cluster).Create(ctx, work, metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+	defer func(name string) {
+		if err := workClient.WorkV1().ManifestWorks(o.cluster).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			fmt.Fprintf(o.Streams.ErrOut, "failed to delete the work %s of cluster %s: %v\n", name, o.cluster, err)
+		}
+	}(work.Name)
+
+	err = wait.PollImmediate(2*time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		work, err = workClient.WorkV1().ManifestWorks(o.cluster).Get(ctx, work.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return feedbackSynced(work), nil
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		return nil, err
+	}
+	if err == wait.ErrWaitTimeout {
+		fmt.Fprintf(o.Streams.ErrOut, "the status of the klusterlet of cluster %s is not fully fed back by its work agent after %ds\n",
+			o.cluster, o.ClusteradmFlags.Timeout)
+	}
+	return work, nil
+}
+
+func (o *Options) printQueryWork(work *workapiv1.ManifestWork) {
+	klusterlet := feedbackOf(work, "Klusterlet", o.klusterletName)
+	if len(klusterlet) == 0 {
+		o.printer.Write(printer.LEVEL_0, "No klusterlet %s detected through the work agent of cluster %s!\n", o.klusterletName, o.cluster)
+		return
+	}
+	o.printer.Write(printer.LEVEL_0, "Klusterlet Conditions:\n")
+	for _, conditionType := range klusterletConditionTypes {
+		status, ok := klusterlet[conditionType+".status"]
+		if !ok {
+			continue
+		}
+		o.printer.Write(printer.LEVEL_1, "Type:\t\t\t%v\n", conditionType)
+		o.printer.Write(printer.LEVEL_1, "Status:\t\t%v\n", status)
+		o.printer.Write(printer.LEVEL_1, "Reason:\t\t%v\n", klusterlet[conditionType+".reason"])
+		o.printer.Write(printer.LEVEL_1, "Message:\t\t%v\n", klusterlet[conditionType+".message"])
+		o.printer.Write(printer.LEVEL_0, "\n")
+	}
+
+	deployment := func(name string) string {
+		values := feedbackOf(work, "Deployment", name)
+		if len(values) == 0 {
+			return "<none>"
+		}
+		return fmt.Sprintf("(%s/%s)", values["AvailableReplicas"], values["Replicas"])
+	}
+	o.printer.Write(printer.LEVEL_0, "Registration Operator:\n")
+	o.printer.Write(printer.LEVEL_1, "Controller:\t%s\n", deployment(klusterletName))
+	o.printer.Write(printer.LEVEL_0, "Components:\n")
+	o.printer.Write(printer.LEVEL_1, "Registration:\t%s\n", deployment(componentNameRegistrationAgent))
+	o.printer.Write(printer.LEVEL_1, "Work:\t\t%s\n", deployment(componentNameWorkAgent))
+	o.printer.Write(printer.LEVEL_0, "The images of the agents are only read through cluster-proxy, set --sa\n")
+}
@@ -0,0 +1,149 @@
+// Copyright Contributors to the Open Cluster Management project
+package klusterletinfo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clienttesting "k8s.io/client-go/testing"
+	workfake "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+func TestQueryWork(t *testing.T) {
+	work, err := queryWork("cluster1", "hub2", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.Namespace != "cluster1" || !strings.HasPrefix(work.Name, queryWorkPrefix) {
+		t.Errorf("unexpected work %s/%s", work.Namespace, work.Name)
+	}
+	if work.Spec.DeleteOption == nil || work.Spec.DeleteOption.PropagationPolicy != workapiv1.DeletePropagationPolicyTypeOrphan {
+		t.Errorf("expected the resources to be orphaned")
+	}
+	if len(work.Spec.Workload.Manifests) != len(work.Spec.ManifestConfigs) {
+		t.Fatalf("expected a config per manifest")
+	}
+	for _, config := range work.Spec.ManifestConfigs {
+		if config.UpdateStrategy == nil || config.UpdateStrategy.Type != workapiv1.UpdateStrategyTypeCreateOnly {
+			t.Errorf("expected %s to never be updated", config.ResourceIdentifier.Name)
+		}
+		if config.ResourceIdentifier.Name == componentNameWorkAgent && config.ResourceIdentifier.Namespace != "open-cluster-management-hub2-agent" {
+			t.Errorf("expected the work agent in the agent namespace of the klusterlet, got %s", config.ResourceIdentifier.Namespace)
+		}
+	}
+	if !strings.Contains(string(work.Spec.Workload.Manifests[0].Raw), `"name":"hub2"`) {
+		t.Errorf("expected the klusterlet hub2, got %s", work.Spec.Workload.Manifests[0].Raw)
+	}
+}
+
+func TestQueryWorkWithoutKlusterlet(t *testing.T) {
+	work, err := queryWork("cluster1", "hub2", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, config := range work.Spec.ManifestConfigs {
+		if config.ResourceIdentifier.Resource == "klusterlets" {
+			t.Errorf("expected the klusterlet not to be read")
+		}
+	}
+}
+
+func TestRunQueryWorkUnknownKlusterlet(t *testing.T) {
+	workClient := workfake.NewSimpleClientset()
+	// the work agent fails to apply the deployments of the agents which do not exist
+	workClient.PrependReactor("create", "manifestworks", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		work := action.(clienttesting.CreateAction).GetObject().(*workapiv1.ManifestWork)
+		for _, config := range work.Spec.ManifestConfigs {
+			work.Status.ResourceStatus.Manifests = append(work.Status.ResourceStatus.Manifests, workapiv1.ManifestCondition{
+				ResourceMeta: workapiv1.ManifestResourceMeta{Resource: config.ResourceIdentifier.Resource, Name: config.ResourceIdentifier.Name},
+				Conditions:   []metav1.Condition{{Type: string(workapiv1.ManifestApplied), Status: metav1.ConditionFalse}},
+			})
+		}
+		return false, nil, nil
+	})
+
+	out := &bytes.Buffer{}
+	o := &Options{
+		ClusteradmFlags: genericclioptionsclusteradm.NewClusteradmFlags(nil),
+		Streams:         genericclioptions.IOStreams{Out: out, ErrOut: out},
+		printer:         printer.NewPrefixWriter(out),
+		klusterletName:  "hub2",
+		cluster:         "cluster1",
+	}
+	if err := o.runQueryWorkWithClient(workClient); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "No klusterlet hub2 detected") {
+		t.Errorf("expected no klusterlet to be detected, got %q", out.String())
+	}
+	var created int
+	for _, action := range workClient.Actions() {
+		if action.GetVerb() != "create" {
+			continue
+		}
+		created++
+		for _, config := range action.(clienttesting.CreateAction).GetObject().(*workapiv1.ManifestWork).Spec.ManifestConfigs {
+			if config.ResourceIdentifier.Resource == "klusterlets" {
+				t.Errorf("expected the unknown klusterlet not to be applied")
+			}
+		}
+	}
+	if created != 1 {
+		t.Errorf("expected a single work, got %d", created)
+	}
+	if works, _ := workClient.WorkV1().ManifestWorks("cluster1").List(context.TODO(), metav1.ListOptions{}); len(works.Items) != 0 {
+		t.Errorf("expected the work to be deleted")
+	}
+}
+
+func TestPrintQueryWork(t *testing.T) {
+	str := func(s string) *string { return &s }
+	integer := func(i int64) *int64 { return &i }
+	synced := []metav1.Condition{{Type: statusFeedbackSynced, Status: metav1.ConditionTrue}}
+	work := &workapiv1.ManifestWork{
+		Spec: workapiv1.ManifestWorkSpec{Workload: workapiv1.ManifestsTemplate{Manifests: make([]workapiv1.Manifest, 2)}},
+		Status: workapiv1.ManifestWorkStatus{ResourceStatus: workapiv1.ManifestResourceStatus{Manifests: []workapiv1.ManifestCondition{
+			{
+				ResourceMeta: workapiv1.ManifestResourceMeta{Kind: "Klusterlet", Name: "klusterlet"},
+				StatusFeedbacks: workapiv1.StatusFeedbackResult{Values: []workapiv1.FeedbackValue{
+					{Name: "Available.status", Value: workapiv1.FieldValue{Type: workapiv1.String, String: str("True")}},
+					{Name: "Available.reason", Value: workapiv1.FieldValue{Type: workapiv1.String, String: str("klusterletAvailable")}},
+				}},
+				Conditions: synced,
+			},
+			{
+				ResourceMeta: workapiv1.ManifestResourceMeta{Kind: "Deployment", Name: componentNameWorkAgent},
+				StatusFeedbacks: workapiv1.StatusFeedbackResult{Values: []workapiv1.FeedbackValue{
+					{Name: "Replicas", Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: integer(1)}},
+					{Name: "AvailableReplicas", Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: integer(1)}},
+				}},
+				Conditions: []metav1.Condition{{Type: string(workapiv1.ManifestApplied), Status: metav1.ConditionFalse}},
+			},
+		}}},
+	}
+	if !feedbackSynced(work) {
+		t.Errorf("expected the feedback to be synced")
+	}
+
+	out := &bytes.Buffer{}
+	o := &Options{
+		Streams:        genericclioptions.IOStreams{Out: out},
+		printer:        printer.NewPrefixWriter(out),
+		klusterletName: "klusterlet",
+		cluster:        "cluster1",
+	}
+	o.printQueryWork(work)
+	for _, expected := range []string{"Available", "klusterletAvailable", "(1/1)", "<none>"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in\n%s", expected, out.String())
+		}
+	}
+}
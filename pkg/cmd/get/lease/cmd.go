@@ -0,0 +1,60 @@
+// Copyright Contributors to the Open Cluster Management project
+package lease
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get the lease of each managed cluster
+%[1]s get leases
+# Get the lease of specific managed clusters
+%[1]s get leases --clusters cluster1,cluster2
+# Only get the managed clusters whose lease is stale
+%[1]s get leases --stale-only
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "leases",
+		Short:        "get the lease of the managed clusters",
+		Long:         "get the lease renew time, age and duration of each managed cluster, the lease is stale if it is not renewed within the grace period",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.clusters, "clusters", []string{}, "Names of the managed clusters (comma separated), defaults to all the managed clusters")
+	cmd.Flags().BoolVar(&o.staleOnly, "stale-only", false, "Only show the managed clusters whose lease is stale")
+	cmd.Flags().DurationVar(&o.threshold, "threshold", 0, "The lease is stale if it is not renewed within the threshold, defaults to 5 times the lease duration of the cluster")
+
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,184 @@
+// Copyright Contributors to the Open Cluster Management project
+package lease
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+const (
+	// clusterLeaseName is the lease renewed by the registration agent in the cluster namespace
+	clusterLeaseName = "managed-cluster-lease"
+	// the hub considers the cluster unknown if the lease is not renewed within 5 lease durations
+	leaseDurationTimes = 5
+	// defaultLeaseDurationSeconds is used if the lease duration is not set on the cluster
+	defaultLeaseDurationSeconds = 60
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get leases options:", "clusters", o.clusters, "stale-only", o.staleOnly, "threshold", o.threshold)
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if o.threshold < 0 {
+		return fmt.Errorf("--threshold should not be negative")
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	kubeClient, _, _, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+
+	var clusters []clusterv1.ManagedCluster
+	if len(o.clusters) == 0 {
+		clusterList, err := clusterClient.ClusterV1().ManagedClusters().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		clusters = clusterList.Items
+	} else {
+		for _, name := range o.clusters {
+			cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			clusters = append(clusters, *cluster)
+		}
+	}
+
+	leaseList := &coordinationv1.LeaseList{}
+	for _, cluster := range clusters {
+		leaseDurationSeconds := cluster.Spec.LeaseDurationSeconds
+		if leaseDurationSeconds == 0 {
+			leaseDurationSeconds = defaultLeaseDurationSeconds
+		}
+		o.leaseDurations[cluster.Name] = time.Duration(leaseDurationSeconds) * time.Second
+
+		lease, err := kubeClient.CoordinationV1().Leases(cluster.Name).Get(context.TODO(), clusterLeaseName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			// the cluster is not accepted or its agent never renewed the lease
+			lease = &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterLeaseName, Namespace: cluster.Name},
+			}
+		case err != nil:
+			return err
+		}
+
+		if o.staleOnly && !o.isStale(lease) {
+			continue
+		}
+		leaseList.Items = append(leaseList.Items, *lease)
+	}
+
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, leaseList)
+}
+
+// isStale returns true if the lease is not renewed within the threshold
+func (o *Options) isStale(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil {
+		return true
+	}
+	threshold := o.threshold
+	if threshold == 0 {
+		threshold = leaseDurationTimes * o.leaseDurations[lease.Namespace]
+	}
+	return time.Since(lease.Spec.RenewTime.Time) > threshold
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if leaseList, ok := obj.(*coordinationv1.LeaseList); ok {
+		for _, lease := range leaseList.Items {
+			lease := lease
+			renewTime, age, leaseDuration, stale := o.getFileds(&lease)
+			mp := make(map[string]interface{})
+			mp[".RenewTime"] = renewTime
+			mp[".Age"] = age
+			mp[".LeaseDuration"] = leaseDuration
+			mp[".Stale"] = stale
+
+			tree.AddFileds(lease.Namespace, &mp)
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Cluster", Type: "string"},
+			{Name: "Renew Time", Type: "string"},
+			{Name: "Age", Type: "string"},
+			{Name: "Lease Duration", Type: "string"},
+			{Name: "Stale", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if leaseList, ok := obj.(*coordinationv1.LeaseList); ok {
+		for _, lease := range leaseList.Items {
+			lease := lease
+			renewTime, age, leaseDuration, stale := o.getFileds(&lease)
+			row := metav1.TableRow{
+				Cells:  []interface{}{lease.Namespace, renewTime, age, leaseDuration, stale},
+				Object: runtime.RawExtension{Object: &lease},
+			}
+
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	return table
+}
+
+func (o *Options) getFileds(lease *coordinationv1.Lease) (renewTime, age, leaseDuration, stale string) {
+	renewTime, age = "<none>", "<none>"
+	if lease.Spec.RenewTime != nil {
+		renewTime = lease.Spec.RenewTime.UTC().Format(time.RFC3339)
+		age = duration.HumanDuration(time.Since(lease.Spec.RenewTime.Time))
+	}
+	leaseDuration = o.leaseDurations[lease.Namespace].String()
+
+	stale = "false"
+	if o.isStale(lease) {
+		stale = color.RedString("true")
+	}
+	return
+}
@@ -0,0 +1,63 @@
+// Copyright Contributors to the Open Cluster Management project
+package policy
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Get the policies and their compliance on each managed cluster
+%[1]s get policies
+# Get the policies in a namespace
+%[1]s get policies -n default
+# Get the compliance of the policies propagated to a managed cluster
+%[1]s get policies --cluster cluster1
+# Get a specific policy
+%[1]s get policies policy1 -n default
+# Get the compliance of the policy default.policy1 on a managed cluster
+%[1]s get policies default.policy1 --cluster cluster1
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "policies",
+		Short:        "get policies and their compliance",
+		Long:         "get the policies of the governance-policy-framework hub add-on and their compliance on the managed clusters",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "Name of the managed cluster, shows the policies propagated to this cluster")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "", "Namespace of the policies, defaults to all the namespaces")
+
+	o.printer.AddFlag(cmd.Flags())
+
+	return cmd
+}
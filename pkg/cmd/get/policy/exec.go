@@ -0,0 +1,231 @@
+// Copyright Contributors to the Open Cluster Management project
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+const (
+	// rootPolicyLabel is set on the policies replicated to the managed cluster namespaces
+	rootPolicyLabel = "policy.open-cluster-management.io/root-policy"
+
+	compliant    = "Compliant"
+	nonCompliant = "NonCompliant"
+)
+
+var policyGVR = schema.GroupVersionResource{
+	Group:    "policy.open-cluster-management.io",
+	Version:  "v1",
+	Resource: "policies",
+}
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("get policies options:", "cluster", o.cluster, "namespace", o.namespace)
+
+	if len(args) > 1 {
+		return fmt.Errorf("can only specify one policy")
+	}
+	if len(args) == 1 {
+		o.policyName = args[0]
+	}
+
+	o.printer.Competele()
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(o.cluster) > 0 && len(o.namespace) > 0 {
+		return fmt.Errorf("--cluster and --namespace can not be specified at the same time")
+	}
+
+	return o.printer.Validate()
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	if len(o.cluster) > 0 {
+		clusterClient, err := clusterclientset.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+		if _, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), o.cluster, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+
+	policyList, err := o.listPolicies(dynamicClient)
+	if err != nil {
+		return err
+	}
+
+	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
+
+	return o.printer.Print(o.Streams, policyList)
+}
+
+// listPolicies lists the root policies, or the policies replicated to the namespace of the cluster if it is set
+func (o *Options) listPolicies(dynamicClient dynamic.Interface) (*unstructured.UnstructuredList, error) {
+	namespace := o.namespace
+	listOptions := metav1.ListOptions{}
+	switch {
+	case len(o.cluster) > 0:
+		// the policies are replicated to the namespace of the managed cluster
+		namespace = o.cluster
+		listOptions.LabelSelector = rootPolicyLabel
+	case len(o.policyName) > 0:
+		listOptions.FieldSelector = fmt.Sprintf("metadata.name=%s", o.policyName)
+	}
+
+	policyList, err := dynamicClient.Resource(policyGVR).Namespace(namespace).List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies, is the governance-policy-framework hub add-on installed? %v", err)
+	}
+
+	items := []unstructured.Unstructured{}
+	for _, item := range policyList.Items {
+		root, replicated := item.GetLabels()[rootPolicyLabel]
+		switch {
+		case len(o.cluster) == 0 && replicated:
+			// only keep the root policies when the cluster is not specified
+			continue
+		case len(o.cluster) > 0 && len(o.policyName) > 0 && root != o.policyName && !strings.HasSuffix(root, "."+o.policyName):
+			// the replicated policies are named and labeled <root namespace>.<root name>, the name of the
+			// policy is the root name, or the label value to select the root policy of a namespace
+			continue
+		}
+		items = append(items, item)
+	}
+	policyList.Items = items
+	return policyList, nil
+}
+
+func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
+	if policyList, ok := obj.(*unstructured.UnstructuredList); ok {
+		for _, policy := range policyList.Items {
+			name, namespace, remediation, compliance, clusters := getFileds(policy)
+			mp := make(map[string]interface{})
+			mp[".Namespace"] = namespace
+			mp[".RemediationAction"] = remediation
+			mp[".Compliance"] = compliance
+			for _, cluster := range sortedClusters(clusters) {
+				mp[".Clusters."+cluster] = clusters[cluster]
+			}
+
+			tree.AddFileds(name, &mp)
+		}
+	}
+	return tree
+}
+
+func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Namespace", Type: "string"},
+			{Name: "Remediation Action", Type: "string"},
+			{Name: "Compliance", Type: "string"},
+			{Name: "Clusters", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if policyList, ok := obj.(*unstructured.UnstructuredList); ok {
+		for _, policy := range policyList.Items {
+			policy := policy
+			name, namespace, remediation, compliance, clusters := getFileds(policy)
+			summary := []string{}
+			for _, cluster := range sortedClusters(clusters) {
+				summary = append(summary, fmt.Sprintf("%s=%s", cluster, clusters[cluster]))
+			}
+			row := metav1.TableRow{
+				Cells:  []interface{}{name, namespace, remediation, compliance, strings.Join(summary, ",")},
+				Object: runtime.RawExtension{Object: &policy},
+			}
+
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	return table
+}
+
+// getFileds returns the fields of the policy to print. For a replicated policy the
+// name and namespace of its root policy are returned.
+func getFileds(policy unstructured.Unstructured) (name, namespace, remediation, compliance string, clusters map[string]string) {
+	name, namespace = policy.GetName(), policy.GetNamespace()
+	if root, ok := policy.GetLabels()[rootPolicyLabel]; ok {
+		if parts := strings.SplitN(root, ".", 2); len(parts) == 2 {
+			namespace, name = parts[0], parts[1]
+		}
+	}
+
+	remediation, _, _ = unstructured.NestedString(policy.Object, "spec", "remediationAction")
+	compliance, _, _ = unstructured.NestedString(policy.Object, "status", "compliant")
+	compliance = colorCompliance(compliance)
+
+	clusters = map[string]string{}
+	statuses, _, _ := unstructured.NestedSlice(policy.Object, "status", "status")
+	for _, s := range statuses {
+		status, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cluster, _, _ := unstructured.NestedString(status, "clustername")
+		state, _, _ := unstructured.NestedString(status, "compliant")
+		if len(cluster) > 0 {
+			clusters[cluster] = colorCompliance(state)
+		}
+	}
+	return
+}
+
+// sortedClusters returns the names of the clusters in order
+func sortedClusters(clusters map[string]string) []string {
+	names := make([]string, 0, len(clusters))
+	for cluster := range clusters {
+		names = append(names, cluster)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func colorCompliance(compliance string) string {
+	switch compliance {
+	case compliant:
+		return compliance
+	case nonCompliant:
+		return color.RedString(compliance)
+	case "":
+		return "Unknown"
+	default:
+		return color.YellowString(compliance)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright Contributors to the Open Cluster Management project
+package policy
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+)
+
+func TestConverters(t *testing.T) {
+	policy := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy.open-cluster-management.io/v1",
+		"kind":       "Policy",
+		"metadata":   map[string]interface{}{"name": "policy1", "namespace": "policies"},
+		"spec":       map[string]interface{}{"remediationAction": "inform"},
+		"status": map[string]interface{}{
+			"compliant": compliant,
+			"status": []interface{}{
+				map[string]interface{}{"clustername": "cluster3", "compliant": compliant},
+				map[string]interface{}{"clustername": "cluster1", "compliant": compliant},
+				map[string]interface{}{"clustername": "cluster2", "compliant": compliant},
+			},
+		},
+	}}
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{policy}}
+	o := &Options{}
+
+	for i := 0; i < 5; i++ {
+		table := o.converToTable(list)
+		if len(table.Rows) != 1 {
+			t.Fatalf("expected 1 row, got %d", len(table.Rows))
+		}
+		if clusters := table.Rows[0].Cells[4]; clusters != "cluster1=Compliant,cluster2=Compliant,cluster3=Compliant" {
+			t.Errorf("expected the clusters in order, got %v", clusters)
+		}
+
+		tree := printer.NewTreePrinter("policies")
+		out := &bytes.Buffer{}
+		if err := o.convertToTree(list, &tree).Print(out); err != nil {
+			t.Fatal(err)
+		}
+		first, second, third := strings.Index(out.String(), "<cluster1>"), strings.Index(out.String(), "<cluster2>"), strings.Index(out.String(), "<cluster3>")
+		if first < 0 || first > second || second > third {
+			t.Errorf("expected the clusters in order, got\n%s", out.String())
+		}
+	}
+}
+
+func TestListPolicies(t *testing.T) {
+	newPolicy := func(namespace, name, root string) runtime.Object {
+		policy := &unstructured.Unstructured{}
+		policy.SetAPIVersion("policy.open-cluster-management.io/v1")
+		policy.SetKind("Policy")
+		policy.SetNamespace(namespace)
+		policy.SetName(name)
+		if len(root) > 0 {
+			policy.SetLabels(map[string]string{rootPolicyLabel: root})
+		}
+		return policy
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{policyGVR: "PolicyList"},
+		newPolicy("policies", "policy1", ""),
+		newPolicy("policies", "policy2", ""),
+		newPolicy("cluster1", "policies.policy1", "policies.policy1"),
+		newPolicy("cluster1", "policies.policy2", "policies.policy2"),
+		newPolicy("cluster1", "other.policy1", "other.policy1"),
+	)
+
+	cases := []struct {
+		name     string
+		options  Options
+		expected []string
+	}{
+		{name: "root policies", expected: []string{"policies/policy1", "policies/policy2"}},
+		{name: "replicated policies", options: Options{cluster: "cluster1"},
+			expected: []string{"cluster1/other.policy1", "cluster1/policies.policy1", "cluster1/policies.policy2"}},
+		{name: "replicated policies of a name", options: Options{cluster: "cluster1", policyName: "policy1"},
+			expected: []string{"cluster1/other.policy1", "cluster1/policies.policy1"}},
+		{name: "replicated policy of a root policy", options: Options{cluster: "cluster1", policyName: "policies.policy1"},
+			expected: []string{"cluster1/policies.policy1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			list, err := c.options.listPolicies(dynamicClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := []string{}
+			for _, item := range list.Items {
+				actual = append(actual, item.GetNamespace()+"/"+item.GetName())
+			}
+			sort.Strings(actual)
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
@@ -14,6 +14,9 @@ import (
 var example = `
 # Get the bootstrap token
 %[1]s get token
+
+# List the bootstrap tokens with their expiration and the clusters which joined with them
+%[1]s get token --list
 `
 
 // NewCmd ...
@@ -43,9 +46,17 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 	}
 
+	cmd.Flags().BoolVar(&o.list, "list", false,
+		"If set, list the bootstrap tokens and the bootstrap service account with their age, expiration and the clusters whose CSR they requested")
 	cmd.Flags().StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
 	cmd.Flags().BoolVar(&o.useBootstrapToken, "use-bootstrap-token", false, "If set then the bootstrap token will used instead of a service account token")
 	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output should be json or text")
+	cmd.Flags().StringVar(&o.bootstrapNamespace, "bootstrap-namespace", "",
+		"The namespace of the bootstrap service account, discovered from the bootstrap cluster role binding if not set")
+	cmd.Flags().StringSliceVar(&o.bootstrapLabels, "bootstrap-labels", []string{},
+		"Labels to add to the bootstrap resources if they are created (eg. key1=value1,key2=value2)")
+	cmd.Flags().StringSliceVar(&o.bootstrapAnnotations, "bootstrap-annotations", []string{},
+		"Annotations to add to the bootstrap resources if they are created (eg. key1=value1,key2=value2)")
 
 	return cmd
 }
@@ -5,12 +5,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stolostron/applier/pkg/apply"
 	"github.com/stolostron/applier/pkg/asset"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
 	"open-cluster-management.io/clusteradm/pkg/cmd/init/scenario"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
 	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
 )
@@ -22,6 +26,12 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 			TokenSecret: helpers.RandStringRunes_az09(16),
 		},
 	}
+	if o.values.Hub.BootstrapLabels, err = helpers.ParseKeyValues(o.bootstrapLabels); err != nil {
+		return err
+	}
+	if o.values.Hub.BootstrapAnnotations, err = helpers.ParseKeyValues(o.bootstrapAnnotations); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -46,13 +56,24 @@ func (o *Options) run() error {
 	applierBuilder := apply.NewApplierBuilder()
 	applier := applierBuilder.WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
 
+	o.values.Hub.BootstrapNamespace = o.bootstrapNamespace
+	if len(o.values.Hub.BootstrapNamespace) == 0 {
+		if o.values.Hub.BootstrapNamespace, err = helpers.GetBootstrapSANamespace(context.TODO(), kubeClient); err != nil {
+			return err
+		}
+	}
+
+	if o.list {
+		return o.listTokens(kubeClient)
+	}
+
 	//Retrieve token from service-account/bootstrap-token
 	// and if not found create it
 	var token string
 	if o.useBootstrapToken {
 		token, err = helpers.GetBootstrapToken(context.TODO(), kubeClient)
 	} else {
-		token, err = helpers.GetBootstrapTokenFromSA(context.TODO(), kubeClient)
+		token, err = helpers.GetBootstrapTokenFromSA(context.TODO(), kubeClient, o.values.Hub.BootstrapNamespace)
 	}
 	switch {
 	case errors.IsNotFound(err):
@@ -81,7 +102,7 @@ func (o *Options) run() error {
 	}
 	// if dry-run then there is nothing else to do
 	if o.ClusteradmFlags.DryRun {
-		return o.writeResult(token, restConfig.Host, output)
+		return o.writeResult(kubeClient, token, restConfig.Host, output)
 	}
 
 	//if bootstrap token then read the token
@@ -90,22 +111,36 @@ func (o *Options) run() error {
 		if err != nil {
 			return err
 		}
-		return o.writeResult(token, restConfig.Host, output)
+		return o.writeResult(kubeClient, token, restConfig.Host, output)
 	}
 
 	//read the token
-	token, err = helpers.GetBootstrapTokenFromSA(context.TODO(), kubeClient)
+	token, err = helpers.GetBootstrapTokenFromSA(context.TODO(), kubeClient, o.values.Hub.BootstrapNamespace)
 	if err != nil {
 		return err
 	}
 
-	return o.writeResult(token, restConfig.Host, output)
+	return o.writeResult(kubeClient, token, restConfig.Host, output)
+}
+
+func (o *Options) listTokens(kubeClient kubernetes.Interface) error {
+	tokens, err := listTokens(kubeClient, o.values.Hub.BootstrapNamespace)
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		return clusteradmjson.WriteJsonOutput(os.Stdout, tokens)
+	}
+	return printTokens(os.Stdout, tokens, time.Now())
 }
 
 func (o *Options) applyToken(applier apply.Applier, reader *asset.ScenarioResourcesReader) ([]string, error) {
 	files := []string{
 		"init/namespace.yaml",
 	}
+	if o.values.Hub.BootstrapNamespace != config.OpenClusterManagementNamespace {
+		files = append(files, "init/bootstrap_namespace.yaml")
+	}
 	if o.useBootstrapToken {
 		files = append(files,
 			"init/bootstrap-token-secret.yaml",
@@ -126,22 +161,33 @@ func (o *Options) applyToken(applier apply.Applier, reader *asset.ScenarioResour
 	return out, err
 }
 
-func (o *Options) writeResult(token, host string, output []string) error {
+func (o *Options) writeResult(kubeClient kubernetes.Interface, token, host string, output []string) error {
 	if len(token) == 0 {
 		fmt.Println("token doesn't exist")
 		return apply.WriteOutput(o.outputFile, output)
 	}
+	// the joining clusters verify the CA they read from the hub against its hashes
+	caHashes, err := helpers.GetCAHashes(kubeClient)
+	if err != nil {
+		return err
+	}
 	if o.output == "json" {
 		err := clusteradmjson.WriteJsonOutput(os.Stdout, clusteradmjson.HubInfo{
 			HubToken:     token,
 			HubApiserver: host,
+			CAHashes:     caHashes,
 		})
 		if err != nil {
 			return err
 		}
 	} else {
+		caHashFlag := ""
+		if len(caHashes) > 0 {
+			caHashFlag = " --discovery-token-ca-cert-hash " + strings.Join(caHashes, ",")
+		}
 		fmt.Printf("token=%s\n", token)
-		fmt.Printf("please log on spoke and run:\n%s join --hub-token %s --hub-apiserver %s --cluster-name <cluster_name>\n", helpers.GetExampleHeader(), token, host)
+		fmt.Printf("please log on spoke and run:\n%s join --hub-token %s --hub-apiserver %s%s --cluster-name <cluster_name>\n",
+			helpers.GetExampleHeader(), token, host, caHashFlag)
 	}
 	return apply.WriteOutput(o.outputFile, output)
 }
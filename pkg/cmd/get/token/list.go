@@ -0,0 +1,165 @@
+// Copyright Contributors to the Open Cluster Management project
+package token
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"open-cluster-management.io/clusteradm/pkg/config"
+)
+
+const (
+	tokenNamespace   = "kube-system"
+	tokenSecretType  = corev1.SecretType("bootstrap.kubernetes.io/token")
+	bootstrapGroup   = "system:bootstrappers:managedcluster"
+	bootstrapPrefix  = "system:bootstrap:"
+	userNameSAFormat = "system:serviceaccount:%s:%s"
+	clusterLabel     = "open-cluster-management.io/cluster-name"
+
+	tokenTypeBootstrap      = "bootstrap-token"
+	tokenTypeServiceAccount = "service-account"
+)
+
+// TokenInfo is a token allowing the clusters to join the hub
+type TokenInfo struct {
+	Type      string    `json:"type"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Created   time.Time `json:"created"`
+	// Expiration is empty if the token does not expire
+	Expiration *time.Time `json:"expiration,omitempty"`
+	// Clusters are the clusters whose CSR was requested with the token
+	Clusters []string `json:"clusters"`
+}
+
+// listTokens returns the bootstrap tokens of the managed clusters and the bootstrap service account
+// with its token secrets. The tokens requested for the service account are not stored, the service
+// account stands for them.
+func listTokens(kubeClient kubernetes.Interface, bootstrapNamespace string) ([]TokenInfo, error) {
+	clustersOfUsers, err := clustersOfRequesters(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []TokenInfo{}
+	secrets, err := kubeClient.CoreV1().Secrets(tokenNamespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("type=%s", tokenSecretType),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets.Items {
+		if secret.Type != tokenSecretType {
+			continue
+		}
+		if !sets.NewString(strings.Split(string(secret.Data["auth-extra-groups"]), ",")...).Has(bootstrapGroup) {
+			continue
+		}
+		token := TokenInfo{
+			Type:      tokenTypeBootstrap,
+			Namespace: secret.Namespace,
+			Name:      secret.Name,
+			Created:   secret.CreationTimestamp.Time,
+			Clusters:  clustersOfUsers[bootstrapPrefix+string(secret.Data["token-id"])].List(),
+		}
+		if expiration, err := time.Parse(time.RFC3339, string(secret.Data["expiration"])); err == nil {
+			token.Expiration = &expiration
+		}
+		tokens = append(tokens, token)
+	}
+
+	sa, err := kubeClient.CoreV1().ServiceAccounts(bootstrapNamespace).Get(context.TODO(), config.BootstrapSAName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		// the hub only uses bootstrap tokens
+		return tokens, nil
+	case err != nil:
+		return nil, err
+	}
+	saClusters := clustersOfUsers[fmt.Sprintf(userNameSAFormat, bootstrapNamespace, config.BootstrapSAName)].List()
+	tokens = append(tokens, TokenInfo{
+		Type:      tokenTypeServiceAccount,
+		Namespace: sa.Namespace,
+		Name:      sa.Name,
+		Created:   sa.CreationTimestamp.Time,
+		Clusters:  saClusters,
+	})
+	saSecrets, err := kubeClient.CoreV1().Secrets(bootstrapNamespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("type=%s", corev1.SecretTypeServiceAccountToken),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range saSecrets.Items {
+		if secret.Type != corev1.SecretTypeServiceAccountToken || secret.Annotations[corev1.ServiceAccountNameKey] != config.BootstrapSAName {
+			continue
+		}
+		tokens = append(tokens, TokenInfo{
+			Type:      tokenTypeServiceAccount,
+			Namespace: secret.Namespace,
+			Name:      secret.Name,
+			Created:   secret.CreationTimestamp.Time,
+			Clusters:  saClusters,
+		})
+	}
+	return tokens, nil
+}
+
+// clustersOfRequesters returns the clusters whose CSR was requested by each user
+func clustersOfRequesters(kubeClient kubernetes.Interface) (map[string]sets.String, error) {
+	csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: clusterLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	clusters := map[string]sets.String{}
+	for _, csr := range csrs.Items {
+		if _, ok := clusters[csr.Spec.Username]; !ok {
+			clusters[csr.Spec.Username] = sets.NewString()
+		}
+		clusters[csr.Spec.Username].Insert(csr.Labels[clusterLabel])
+	}
+	return clusters, nil
+}
+
+// printTokens prints a table of the tokens
+func printTokens(out io.Writer, tokens []TokenInfo, now time.Time) error {
+	sort.SliceStable(tokens, func(i, j int) bool {
+		if tokens[i].Type != tokens[j].Type {
+			return tokens[i].Type < tokens[j].Type
+		}
+		return tokens[i].Created.Before(tokens[j].Created)
+	})
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tNAMESPACE\tNAME\tAGE\tTTL\tCLUSTERS")
+	for _, token := range tokens {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", token.Type, token.Namespace, token.Name,
+			duration.HumanDuration(now.Sub(token.Created)), ttl(token, now), strings.Join(token.Clusters, ","))
+	}
+	return w.Flush()
+}
+
+func ttl(token TokenInfo, now time.Time) string {
+	switch {
+	case token.Expiration != nil && now.After(*token.Expiration):
+		return "expired"
+	case token.Expiration != nil:
+		return duration.HumanDuration(token.Expiration.Sub(now))
+	case token.Type == tokenTypeServiceAccount && token.Name == config.BootstrapSAName:
+		// the requested tokens expire in 1 hour
+		return "1h per token"
+	}
+	return "<none>"
+}
@@ -0,0 +1,105 @@
+// Copyright Contributors to the Open Cluster Management project
+package token
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"open-cluster-management.io/clusteradm/pkg/config"
+)
+
+func TestListTokens(t *testing.T) {
+	created := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	expiration := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	csr := func(name, cluster, username string) *certificatesv1.CertificateSigningRequest {
+		return &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{clusterLabel: cluster}},
+			Spec:       certificatesv1.CertificateSigningRequestSpec{Username: username},
+		}
+	}
+	bootstrapToken := func(id, groups, expiration string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-token-" + id, Namespace: tokenNamespace, CreationTimestamp: created},
+			Type:       tokenSecretType,
+			Data: map[string][]byte{
+				"token-id":          []byte(id),
+				"auth-extra-groups": []byte(groups),
+				"expiration":        []byte(expiration),
+			},
+		}
+	}
+	saUser := "system:serviceaccount:open-cluster-management:cluster-bootstrap"
+	kubeClient := fakekube.NewSimpleClientset(
+		bootstrapToken("abc", bootstrapGroup, "2023-01-02T00:00:00Z"),
+		bootstrapToken("def", "system:bootstrappers:kubeadm:default-node-token", ""),
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: config.BootstrapSAName, Namespace: "open-cluster-management", CreationTimestamp: created},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "cluster-bootstrap-token",
+				Namespace:         "open-cluster-management",
+				Annotations:       map[string]string{corev1.ServiceAccountNameKey: config.BootstrapSAName},
+				CreationTimestamp: created,
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+		},
+		csr("csr1", "cluster1", "system:bootstrap:abc"),
+		csr("csr2", "cluster2", saUser),
+		csr("csr3", "cluster3", saUser),
+	)
+
+	tokens, err := listTokens(kubeClient, "open-cluster-management")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TokenInfo{
+		{
+			Type: tokenTypeBootstrap, Namespace: tokenNamespace, Name: "bootstrap-token-abc", Created: created.Time,
+			Expiration: &expiration, Clusters: []string{"cluster1"},
+		},
+		{
+			Type: tokenTypeServiceAccount, Namespace: "open-cluster-management", Name: config.BootstrapSAName, Created: created.Time,
+			Clusters: []string{"cluster2", "cluster3"},
+		},
+		{
+			Type: tokenTypeServiceAccount, Namespace: "open-cluster-management", Name: "cluster-bootstrap-token", Created: created.Time,
+			Clusters: []string{"cluster2", "cluster3"},
+		},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("expected %+v, got %+v", expected, tokens)
+	}
+
+	out := &bytes.Buffer{}
+	if err := printTokens(out, tokens, time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 tokens, got %q", out.String())
+	}
+	for i, want := range []string{"12h", "1h per token", "<none>"} {
+		if !strings.Contains(lines[i+1], want) {
+			t.Errorf("expected line %q to contain %q", lines[i+1], want)
+		}
+	}
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	if got := ttl(TokenInfo{Expiration: &expired}, now); got != "expired" {
+		t.Errorf("expected expired, got %s", got)
+	}
+	if got := ttl(TokenInfo{Type: tokenTypeBootstrap}, now); got != "<none>" {
+		t.Errorf("expected <none>, got %s", got)
+	}
+}
@@ -17,6 +17,14 @@ type Options struct {
 	useBootstrapToken bool
 	//output format
 	output string
+	//The namespace of the bootstrap service account, discovered from the bootstrap cluster role binding if not set
+	bootstrapNamespace string
+	//The labels added to the bootstrap resources when they are created, in the format of key=value
+	bootstrapLabels []string
+	//The annotations added to the bootstrap resources when they are created, in the format of key=value
+	bootstrapAnnotations []string
+	//If true the existing tokens are listed instead of getting a token
+	list bool
 }
 
 //Values: The values used in the template
@@ -31,6 +39,12 @@ type Hub struct {
 	TokenID string `json:"tokenID"`
 	//TokenSecret: A token secret allowing the cluster to connect back to the hub
 	TokenSecret string `json:"tokenSecret"`
+	//BootstrapNamespace: the namespace of the bootstrap service account
+	BootstrapNamespace string `json:"bootstrapNamespace"`
+	//BootstrapLabels: the labels added to the bootstrap resources
+	BootstrapLabels map[string]string `json:"bootstrapLabels"`
+	//BootstrapAnnotations: the annotations added to the bootstrap resources
+	BootstrapAnnotations map[string]string `json:"bootstrapAnnotations"`
 }
 
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
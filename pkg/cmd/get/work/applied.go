@@ -0,0 +1,94 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// workQuotaResource is the object count quota of the manifestworks of a cluster namespace
+	workQuotaResource corev1.ResourceName = "count/manifestworks.work.open-cluster-management.io"
+	// maxWorkSize is the size of the manifests of a manifestwork above which the hub webhook rejects it
+	maxWorkSize = 500 * 1024
+	// warningRatio is the ratio of a limit above which a warning is printed
+	warningRatio = 0.8
+)
+
+// appliedResourceCount is the number of resources of a kind the manifestworks applied on the cluster
+type appliedResourceCount struct {
+	group string
+	kind  string
+	count int
+}
+
+// summarizeAppliedResources counts the resources of each kind reported as applied in the status of the manifestworks
+func summarizeAppliedResources(works []workapiv1.ManifestWork) []appliedResourceCount {
+	counts := map[[2]string]int{}
+	for _, work := range works {
+		for _, manifest := range work.Status.ResourceStatus.Manifests {
+			counts[[2]string{manifest.ResourceMeta.Group, manifest.ResourceMeta.Kind}]++
+		}
+	}
+	summary := []appliedResourceCount{}
+	for key, count := range counts {
+		summary = append(summary, appliedResourceCount{group: key[0], kind: key[1], count: count})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].count != summary[j].count {
+			return summary[i].count > summary[j].count
+		}
+		return summary[i].kind+"."+summary[i].group < summary[j].kind+"."+summary[j].group
+	})
+	return summary
+}
+
+func printAppliedResources(out io.Writer, cluster string, summary []appliedResourceCount) error {
+	fmt.Fprintf(out, "\nResources applied by the manifestworks on cluster %s:\n", cluster)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tGROUP\tCOUNT")
+	for _, c := range summary {
+		group := c.group
+		if len(group) == 0 {
+			group = "core"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\n", c.kind, group, c.count)
+	}
+	return w.Flush()
+}
+
+// limitWarnings warns when the number of manifestworks of the cluster approaches its quota, or when the
+// manifests of a manifestwork approach the size accepted by the hub
+func limitWarnings(cluster string, quotas []corev1.ResourceQuota, works []workapiv1.ManifestWork) []string {
+	warnings := []string{}
+	for _, quota := range quotas {
+		hard, ok := quota.Status.Hard[workQuotaResource]
+		if !ok || hard.Value() == 0 {
+			continue
+		}
+		used := quota.Status.Used[workQuotaResource]
+		if float64(used.Value()) >= warningRatio*float64(hard.Value()) {
+			warnings = append(warnings, fmt.Sprintf("cluster %s has %d manifestworks out of the %d allowed by quota %s",
+				cluster, used.Value(), hard.Value(), quota.Name))
+		}
+	}
+	for _, work := range works {
+		size := 0
+		for _, manifest := range work.Spec.Workload.Manifests {
+			if raw, err := json.Marshal(manifest); err == nil {
+				size += len(raw)
+			}
+		}
+		if float64(size) >= warningRatio*maxWorkSize {
+			warnings = append(warnings, fmt.Sprintf("the manifests of manifestwork %s are %d bytes, the hub rejects the manifestworks above %d bytes",
+				work.Name, size, maxWorkSize))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,95 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newAppliedWork(name string, metas ...workapiv1.ManifestResourceMeta) workapiv1.ManifestWork {
+	work := workapiv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster1"}}
+	for _, m := range metas {
+		work.Status.ResourceStatus.Manifests = append(work.Status.ResourceStatus.Manifests, workapiv1.ManifestCondition{ResourceMeta: m})
+	}
+	return work
+}
+
+func TestSummarizeAppliedResources(t *testing.T) {
+	deployment := workapiv1.ManifestResourceMeta{Group: "apps", Kind: "Deployment"}
+	configMap := workapiv1.ManifestResourceMeta{Kind: "ConfigMap"}
+	service := workapiv1.ManifestResourceMeta{Kind: "Service"}
+	works := []workapiv1.ManifestWork{
+		newAppliedWork("work1", deployment, configMap, service),
+		newAppliedWork("work2", configMap),
+		newAppliedWork("work3"),
+	}
+
+	summary := summarizeAppliedResources(works)
+	expected := []appliedResourceCount{
+		{kind: "ConfigMap", count: 2},
+		{group: "apps", kind: "Deployment", count: 1},
+		{kind: "Service", count: 1},
+	}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Fatalf("expected %v, got %v", expected, summary)
+	}
+
+	out := &bytes.Buffer{}
+	if err := printAppliedResources(out, "cluster1", summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "ConfigMap   core   2") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestLimitWarnings(t *testing.T) {
+	newQuota := func(name string, used, hard int64) corev1.ResourceQuota {
+		return corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{workQuotaResource: *resource.NewQuantity(hard, resource.DecimalSI)},
+				Used: corev1.ResourceList{workQuotaResource: *resource.NewQuantity(used, resource.DecimalSI)},
+			},
+		}
+	}
+	largeWork := newAppliedWork("large")
+	largeWork.Spec.Workload.Manifests = []workapiv1.Manifest{
+		{RawExtension: runtime.RawExtension{Raw: []byte(`{"data":"` + strings.Repeat("a", maxWorkSize) + `"}`)}},
+	}
+	smallWork := newAppliedWork("small")
+	smallWork.Spec.Workload.Manifests = []workapiv1.Manifest{
+		{RawExtension: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap"}`)}},
+	}
+
+	cases := []struct {
+		name     string
+		quotas   []corev1.ResourceQuota
+		works    []workapiv1.ManifestWork
+		expected int
+	}{
+		{name: "no quota", works: []workapiv1.ManifestWork{smallWork}},
+		{name: "quota not approached", quotas: []corev1.ResourceQuota{newQuota("works", 5, 10)}},
+		{name: "quota approached", quotas: []corev1.ResourceQuota{newQuota("works", 8, 10)}, expected: 1},
+		{name: "other quota", quotas: []corev1.ResourceQuota{{Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+			Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+		}}}},
+		{name: "large work", works: []workapiv1.ManifestWork{smallWork, largeWork}, expected: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if warnings := limitWarnings("cluster1", c.quotas, c.works); len(warnings) != c.expected {
+				t.Errorf("expected %d warnings, got %v", c.expected, warnings)
+			}
+		})
+	}
+}
@@ -16,6 +16,17 @@ var example = `
 %[1]s get works --cluster cluster1
 # Get a specific manifestwork in a cluster
 %[1]s get works work1 --cluster cluster1
+# Get the manifestworks of an application in a cluster
+%[1]s get works --cluster cluster1 --selector app=app1
+# Get the manifestworks failing to be applied or available in a cluster
+%[1]s get works --cluster cluster1 --applied=false
+%[1]s get works --cluster cluster1 --available=false
+# Get the ready replicas of the deployments reported in the status feedback of the manifestworks
+%[1]s get works --cluster cluster1 --feedback deployments:ReadyReplicas -o table
+# Summarize the kinds of the resources the manifestworks applied in a cluster, and warn when the manifestwork quota is approached
+%[1]s get works --cluster cluster1 --show-applied-resources
+# List the names of the manifestworks of a cluster with thousands of manifestworks, without fetching their manifests
+%[1]s get works --cluster cluster1 -o name
 `
 
 // NewCmd...
@@ -48,8 +59,23 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	}
 
 	cmd.Flags().StringVar(&o.cluster, "cluster", "", "Names of the managed cluster")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector of the manifestworks, supports '=', '==', and '!='")
+	cmd.Flags().BoolVar(&o.applied, "applied", true, "If set, only show the manifestworks whose Applied condition is true, or not true with --applied=false")
+	cmd.Flags().BoolVar(&o.available, "available", true, "If set, only show the manifestworks whose Available condition is true, or not true with --available=false")
+
+	cmd.Flags().StringSliceVar(&o.feedback, "feedback", []string{},
+		"Status feedback values shown as columns (comma separated), in the format of [<kind or resource>[/<name>]:]<value name>, "+
+			"e.g. deployments/nginx:ReadyReplicas")
+
+	cmd.Flags().BoolVar(&o.showAppliedResources, "show-applied-resources", false,
+		"If set, summarize the kinds and counts of the resources applied by the manifestworks on the cluster, "+
+			"and warn when the manifestwork quota of the cluster namespace or the manifestwork size limit of the hub is approached")
+
+	cmd.Flags().Int64Var(&o.chunkSize, "chunk-size", defaultChunkSize,
+		"The number of manifestworks returned by each list call, the manifestworks are listed in pages. Set to 0 to list them in one call")
 
 	o.printer.AddFlag(cmd.Flags())
+	cmd.Flags().Lookup("output").Usage = "output format can be tree, table, wide, yaml or name, only the metadata of the manifestworks are fetched with name"
 
 	return cmd
 }
@@ -8,7 +8,9 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/metadata"
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	workclient "open-cluster-management.io/api/client/work/clientset/versioned"
 	workapiv1 "open-cluster-management.io/api/work/v1"
@@ -23,6 +25,15 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 	if len(args) == 1 {
 		o.workName = args[0]
 	}
+	o.filterApplied = cmd.Flags().Changed("applied")
+	o.filterAvailable = cmd.Flags().Changed("available")
+	for _, spec := range o.feedback {
+		column, err := parseFeedbackColumn(spec)
+		if err != nil {
+			return err
+		}
+		o.feedbackColumns = append(o.feedbackColumns, column)
+	}
 
 	o.printer.Competele()
 
@@ -39,11 +50,31 @@ func (o *Options) validate() (err error) {
 		return fmt.Errorf("cluster name must be specified")
 	}
 
+	if _, err := labels.Parse(o.selector); err != nil {
+		return fmt.Errorf("invalid --selector: %v", err)
+	}
+
+	if o.chunkSize < 0 {
+		return fmt.Errorf("--chunk-size should not be negative")
+	}
+
+	// only the names are printed, the manifestworks are fetched without their spec and status
+	if o.printer.Format == "name" {
+		if o.filterApplied || o.filterAvailable || len(o.feedback) > 0 || o.showAppliedResources {
+			return fmt.Errorf("--applied, --available, --feedback and --show-applied-resources can not be used with --output name")
+		}
+		return nil
+	}
+
 	err = o.printer.Validate()
 	if err != nil {
 		return err
 	}
 
+	if o.showAppliedResources && o.printer.Format == "yaml" {
+		return fmt.Errorf("--show-applied-resources can not be used with --output yaml")
+	}
+
 	return nil
 }
 
@@ -66,21 +97,57 @@ func (o *Options) run() (err error) {
 		return err
 	}
 
-	var workList *workapiv1.ManifestWorkList
-	if len(o.workName) == 0 {
-		workList, err = workClient.WorkV1().ManifestWorks(o.cluster).List(context.TODO(), metav1.ListOptions{})
-	} else {
-		workList, err = workClient.WorkV1().ManifestWorks(o.cluster).List(context.TODO(), metav1.ListOptions{
-			FieldSelector: fmt.Sprintf("name=%s", o.workName),
-		})
+	if o.printer.Format == "name" {
+		metadataClient, err := metadata.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+		names, err := o.getWorkNames(metadataClient)
+		if err != nil {
+			return err
+		}
+		printNames(o.Streams.Out, names)
+		return nil
 	}
+
+	workList, err := o.getWorks(workClient)
 	if err != nil {
 		return err
 	}
 
 	o.printer.WithTreeConverter(o.convertToTree).WithTableConverter(o.converToTable)
 
-	return o.printer.Print(o.Streams, workList)
+	if err := o.printer.Print(o.Streams, workList); err != nil {
+		return err
+	}
+	if !o.showAppliedResources {
+		return nil
+	}
+
+	kubeClient, err := o.ClusteradmFlags.KubectlFactory.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	quotas, err := kubeClient.CoreV1().ResourceQuotas(o.cluster).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, warning := range limitWarnings(o.cluster, quotas.Items, workList.Items) {
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: %s\n", warning)
+	}
+	return printAppliedResources(o.Streams.Out, o.cluster, summarizeAppliedResources(workList.Items))
+}
+
+// matchConditions returns true if the applied and available conditions of the manifestwork
+// match the filters, a missing condition is not true.
+func (o *Options) matchConditions(work workapiv1.ManifestWork) bool {
+	if o.filterApplied && meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkApplied) != o.applied {
+		return false
+	}
+	if o.filterAvailable && meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkAvailable) != o.available {
+		return false
+	}
+	return true
 }
 
 func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *printer.TreePrinter {
@@ -92,6 +159,9 @@ func (o *Options) convertToTree(obj runtime.Object, tree *printer.TreePrinter) *
 			mp[".Number of Manifests"] = number
 			mp[".Applied"] = applied
 			mp[".Available"] = available
+			for _, column := range o.feedbackColumns {
+				mp[".Feedback."+column.header] = column.get(work)
+			}
 
 			tree.AddFileds(work.Name, &mp)
 		}
@@ -110,12 +180,19 @@ func (o *Options) converToTable(obj runtime.Object) *metav1.Table {
 		},
 		Rows: []metav1.TableRow{},
 	}
+	for _, column := range o.feedbackColumns {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{Name: column.header, Type: "string"})
+	}
 
 	if workList, ok := obj.(*workapiv1.ManifestWorkList); ok {
 		for _, work := range workList.Items {
 			cluster, number, applied, available := getFileds(work)
+			cells := []interface{}{work.Name, cluster, number, applied, available}
+			for _, column := range o.feedbackColumns {
+				cells = append(cells, column.get(work))
+			}
 			row := metav1.TableRow{
-				Cells:  []interface{}{work.Name, cluster, number, applied, available},
+				Cells:  cells,
 				Object: runtime.RawExtension{Object: &work},
 			}
 
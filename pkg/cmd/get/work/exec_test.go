@@ -0,0 +1,43 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestMatchConditions(t *testing.T) {
+	work := func(applied, available metav1.ConditionStatus) workapiv1.ManifestWork {
+		w := workapiv1.ManifestWork{}
+		if applied != "" {
+			w.Status.Conditions = append(w.Status.Conditions, metav1.Condition{Type: workapiv1.WorkApplied, Status: applied})
+		}
+		if available != "" {
+			w.Status.Conditions = append(w.Status.Conditions, metav1.Condition{Type: workapiv1.WorkAvailable, Status: available})
+		}
+		return w
+	}
+	cases := []struct {
+		name     string
+		options  Options
+		work     workapiv1.ManifestWork
+		expected bool
+	}{
+		{name: "no filter", work: work("", ""), expected: true},
+		{name: "applied", options: Options{filterApplied: true, applied: true}, work: work(metav1.ConditionTrue, ""), expected: true},
+		{name: "not applied", options: Options{filterApplied: true}, work: work(metav1.ConditionTrue, ""), expected: false},
+		{name: "missing applied", options: Options{filterApplied: true}, work: work("", ""), expected: true},
+		{name: "not available", options: Options{filterAvailable: true}, work: work(metav1.ConditionTrue, metav1.ConditionFalse), expected: true},
+		{name: "applied and not available", options: Options{filterApplied: true, applied: true, filterAvailable: true},
+			work: work(metav1.ConditionFalse, metav1.ConditionUnknown), expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if matched := c.options.matchConditions(c.work); matched != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, matched)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// feedbackColumn is a column of the status feedback values of the manifests, in the format of
+// [<kind or resource>[/<name>]:]<value name>, e.g. deployments/nginx:ReadyReplicas
+type feedbackColumn struct {
+	header string
+	// kind matches the kind or the resource of the manifests, any manifest if empty
+	kind string
+	// name matches the name of the manifests, any manifest if empty
+	name  string
+	value string
+}
+
+func parseFeedbackColumn(spec string) (feedbackColumn, error) {
+	spec = strings.TrimPrefix(spec, ".")
+	column := feedbackColumn{header: spec, value: spec}
+	if i := strings.LastIndex(spec, ":"); i >= 0 {
+		column.value = strings.TrimPrefix(spec[i+1:], ".")
+		column.kind = spec[:i]
+		if j := strings.Index(column.kind, "/"); j >= 0 {
+			column.name = column.kind[j+1:]
+			column.kind = column.kind[:j]
+		}
+		if len(column.kind) == 0 {
+			return column, fmt.Errorf("invalid feedback %q, the kind or resource should not be empty", spec)
+		}
+	}
+	if len(column.value) == 0 {
+		return column, fmt.Errorf("invalid feedback %q, the value name should not be empty", spec)
+	}
+	return column, nil
+}
+
+// get returns the values of the manifests matching the column, separated by commas
+func (c feedbackColumn) get(work workapiv1.ManifestWork) string {
+	var values []string
+	for _, manifest := range work.Status.ResourceStatus.Manifests {
+		meta := manifest.ResourceMeta
+		if len(c.kind) > 0 && !strings.EqualFold(c.kind, meta.Kind) && !strings.EqualFold(c.kind, meta.Resource) {
+			continue
+		}
+		if len(c.name) > 0 && c.name != meta.Name {
+			continue
+		}
+		for _, feedback := range manifest.StatusFeedbacks.Values {
+			if feedback.Name == c.value {
+				values = append(values, fieldValue(feedback.Value))
+			}
+		}
+	}
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
+
+func fieldValue(value workapiv1.FieldValue) string {
+	switch {
+	case value.Integer != nil:
+		return strconv.FormatInt(*value.Integer, 10)
+	case value.String != nil:
+		return *value.String
+	case value.Boolean != nil:
+		return strconv.FormatBool(*value.Boolean)
+	}
+	return "<none>"
+}
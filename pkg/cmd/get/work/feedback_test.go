@@ -0,0 +1,77 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"testing"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestParseFeedbackColumn(t *testing.T) {
+	cases := []struct {
+		spec        string
+		expected    feedbackColumn
+		expectedErr bool
+	}{
+		{spec: "ReadyReplicas", expected: feedbackColumn{header: "ReadyReplicas", value: "ReadyReplicas"}},
+		{spec: ".ReadyReplicas", expected: feedbackColumn{header: "ReadyReplicas", value: "ReadyReplicas"}},
+		{spec: "deployments:ReadyReplicas", expected: feedbackColumn{header: "deployments:ReadyReplicas", kind: "deployments", value: "ReadyReplicas"}},
+		{spec: "Deployment/nginx:.ReadyReplicas", expected: feedbackColumn{header: "Deployment/nginx:.ReadyReplicas", kind: "Deployment", name: "nginx", value: "ReadyReplicas"}},
+		{spec: ":ReadyReplicas", expectedErr: true},
+		{spec: "deployments:", expectedErr: true},
+	}
+	for _, c := range cases {
+		column, err := parseFeedbackColumn(c.spec)
+		if c.expectedErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", c.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.spec, err)
+		}
+		if column != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.spec, c.expected, column)
+		}
+	}
+}
+
+func TestFeedbackColumnGet(t *testing.T) {
+	integer := func(i int64) workapiv1.FieldValue {
+		return workapiv1.FieldValue{Type: workapiv1.Integer, Integer: &i}
+	}
+	manifest := func(kind, resource, name string, replicas int64) workapiv1.ManifestCondition {
+		return workapiv1.ManifestCondition{
+			ResourceMeta: workapiv1.ManifestResourceMeta{Kind: kind, Resource: resource, Name: name},
+			StatusFeedbacks: workapiv1.StatusFeedbackResult{
+				Values: []workapiv1.FeedbackValue{{Name: "ReadyReplicas", Value: integer(replicas)}},
+			},
+		}
+	}
+	work := workapiv1.ManifestWork{}
+	work.Status.ResourceStatus.Manifests = []workapiv1.ManifestCondition{
+		manifest("Deployment", "deployments", "nginx", 2),
+		manifest("Deployment", "deployments", "redis", 1),
+		manifest("StatefulSet", "statefulsets", "db", 3),
+	}
+
+	cases := []struct {
+		spec     string
+		expected string
+	}{
+		{spec: "ReadyReplicas", expected: "2,1,3"},
+		{spec: "deployments:ReadyReplicas", expected: "2,1"},
+		{spec: "deployment/redis:ReadyReplicas", expected: "1"},
+		{spec: "Replicas", expected: "<none>"},
+	}
+	for _, c := range cases {
+		column, err := parseFeedbackColumn(c.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value := column.get(work); value != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.spec, c.expected, value)
+		}
+	}
+}
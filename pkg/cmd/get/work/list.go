@@ -0,0 +1,106 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/metadata"
+	workclient "open-cluster-management.io/api/client/work/clientset/versioned"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// defaultChunkSize is the number of manifestworks returned by each list call
+const defaultChunkSize = 500
+
+// listPages calls list with the continue token of the previous page until the last page is returned,
+// list returns the continue token of the next page
+func listPages(options metav1.ListOptions, chunkSize int64, list func(metav1.ListOptions) (string, error)) error {
+	options.Limit = chunkSize
+	for {
+		next, err := list(options)
+		if err != nil {
+			return err
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		options.Continue = next
+	}
+}
+
+// getWorks returns the manifestworks of the cluster matching the selector and the condition filters,
+// the filters are applied on each page so the manifestworks filtered out are not kept
+func (o *Options) getWorks(workClient workclient.Interface) (*workapiv1.ManifestWorkList, error) {
+	works := &workapiv1.ManifestWorkList{}
+	// the name is not a field selector of the manifestworks, the manifestwork is got directly
+	if len(o.workName) > 0 {
+		work, err := workClient.WorkV1().ManifestWorks(o.cluster).Get(context.TODO(), o.workName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if o.matchSelector(work.Labels) && o.matchConditions(*work) {
+			works.Items = append(works.Items, *work)
+		}
+		return works, nil
+	}
+
+	err := listPages(metav1.ListOptions{LabelSelector: o.selector}, o.chunkSize, func(options metav1.ListOptions) (string, error) {
+		page, err := workClient.WorkV1().ManifestWorks(o.cluster).List(context.TODO(), options)
+		if err != nil {
+			return "", err
+		}
+		// the conditions can not be selected by the apiserver
+		for _, work := range page.Items {
+			if o.matchConditions(work) {
+				works.Items = append(works.Items, work)
+			}
+		}
+		return page.Continue, nil
+	})
+	return works, err
+}
+
+// getWorkNames returns the names of the manifestworks of the cluster matching the selector, only
+// the metadata of the manifestworks are fetched
+func (o *Options) getWorkNames(metadataClient metadata.Interface) ([]string, error) {
+	client := metadataClient.Resource(workapiv1.GroupVersion.WithResource("manifestworks")).Namespace(o.cluster)
+	if len(o.workName) > 0 {
+		work, err := client.Get(context.TODO(), o.workName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if !o.matchSelector(work.Labels) {
+			return nil, nil
+		}
+		return []string{work.Name}, nil
+	}
+
+	names := []string{}
+	err := listPages(metav1.ListOptions{LabelSelector: o.selector}, o.chunkSize, func(options metav1.ListOptions) (string, error) {
+		page, err := client.List(context.TODO(), options)
+		if err != nil {
+			return "", err
+		}
+		for _, work := range page.Items {
+			names = append(names, work.Name)
+		}
+		return page.Continue, nil
+	})
+	return names, err
+}
+
+func (o *Options) matchSelector(workLabels map[string]string) bool {
+	// the selector is validated
+	selector, _ := labels.Parse(o.selector)
+	return selector.Matches(labels.Set(workLabels))
+}
+
+func printNames(out io.Writer, names []string) {
+	for _, name := range names {
+		fmt.Fprintf(out, "manifestwork.work.open-cluster-management.io/%s\n", name)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestListPages(t *testing.T) {
+	pages := map[string]string{"": "page2", "page2": "page3", "page3": ""}
+	continues := []string{}
+	err := listPages(metav1.ListOptions{LabelSelector: "app=app1"}, 2, func(options metav1.ListOptions) (string, error) {
+		if options.Limit != 2 || options.LabelSelector != "app=app1" {
+			t.Errorf("unexpected options %v", options)
+		}
+		continues = append(continues, options.Continue)
+		return pages[options.Continue], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"", "page2", "page3"}; !reflect.DeepEqual(continues, expected) {
+		t.Errorf("expected the pages %v, got %v", expected, continues)
+	}
+
+	err = listPages(metav1.ListOptions{}, 2, func(options metav1.ListOptions) (string, error) {
+		return "", fmt.Errorf("expired")
+	})
+	if err == nil {
+		t.Errorf("expected the error of the list to be returned")
+	}
+}
+
+func TestMatchSelector(t *testing.T) {
+	cases := []struct {
+		selector string
+		labels   map[string]string
+		expected bool
+	}{
+		{selector: "", expected: true},
+		{selector: "app=app1", labels: map[string]string{"app": "app1"}, expected: true},
+		{selector: "app=app1", labels: map[string]string{"app": "app2"}},
+		{selector: "app!=app1", expected: true},
+	}
+	for _, c := range cases {
+		o := &Options{selector: c.selector}
+		if matched := o.matchSelector(c.labels); matched != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.selector, c.expected, matched)
+		}
+	}
+}
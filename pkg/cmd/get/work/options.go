@@ -16,6 +16,26 @@ type Options struct {
 	cluster string
 
 	workName string
+	//The label selector of the manifestworks
+	selector string
+	//Only show the manifestworks whose applied and available conditions match, if set
+	applied   bool
+	available bool
+
+	//The status feedback values shown as columns
+	feedback []string
+
+	//Summarize the resources applied by the manifestworks and warn when the hub limits are approached
+	showAppliedResources bool
+
+	//The number of manifestworks returned by each list call
+	chunkSize int64
+
+	//The conditions filtered on, the flags are set
+	filterApplied   bool
+	filterAvailable bool
+	//The parsed feedback columns
+	feedbackColumns []feedbackColumn
 
 	Streams genericclioptions.IOStreams
 
@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+package history
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+)
+
+var example = `
+# Show the last clusteradm operation on the managed cluster and on the resources of its namespace
+%[1]s history cluster1
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "history CLUSTER",
+		Short: "show the clusteradm operations on a managed cluster",
+		Long: fmt.Sprintf("show who changed the managed cluster, its namespace, its addons and its works with clusteradm, "+
+			"when, with which command and version, as recorded in their %s annotation", audit.Annotation),
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format, should be json or text")
+
+	return cmd
+}
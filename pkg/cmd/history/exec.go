@@ -0,0 +1,146 @@
+// Copyright Contributors to the Open Cluster Management project
+package history
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+)
+
+// resource is a kind of object of a managed cluster, its objects are in the cluster namespace if it is namespaced
+type resource struct {
+	kind       string
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+var resources = []resource{
+	{kind: "ManagedCluster", gvr: schema.GroupVersionResource{Group: "cluster.open-cluster-management.io", Version: "v1", Resource: "managedclusters"}},
+	{kind: "Namespace", gvr: schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}},
+	{kind: "ManagedClusterAddOn", gvr: schema.GroupVersionResource{Group: "addon.open-cluster-management.io", Version: "v1alpha1", Resource: "managedclusteraddons"}, namespaced: true},
+	{kind: "ManifestWork", gvr: schema.GroupVersionResource{Group: "work.open-cluster-management.io", Version: "v1", Resource: "manifestworks"}, namespaced: true},
+}
+
+// Entry is the last clusteradm operation on an object
+type Entry struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	audit.Operation
+}
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("history options:", "cluster", args, "output", o.output)
+	o.cluster = args[0]
+	return nil
+}
+
+func (o *Options) validate() error {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("output should be json or text")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	entries, err := o.history(dynamicClient)
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		return clusteradmjson.WriteJsonOutput(o.Streams.Out, entries)
+	}
+	return printEntries(o.Streams.Out, entries)
+}
+
+// history returns the operations on the objects of the cluster, the kinds which are not installed are skipped
+func (o *Options) history(dynamicClient dynamic.Interface) ([]Entry, error) {
+	entries := []Entry{}
+	for _, r := range resources {
+		var objs []unstructured.Unstructured
+		if r.namespaced {
+			list, err := dynamicClient.Resource(r.gvr).Namespace(o.cluster).List(context.TODO(), metav1.ListOptions{})
+			if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return nil, err
+			}
+			if list != nil {
+				objs = list.Items
+			}
+		} else {
+			obj, err := dynamicClient.Resource(r.gvr).Get(context.TODO(), o.cluster, metav1.GetOptions{})
+			switch {
+			case errors.IsNotFound(err):
+				if r.kind == "ManagedCluster" {
+					return nil, fmt.Errorf("managed cluster %s is not found", o.cluster)
+				}
+			case err != nil:
+				return nil, err
+			default:
+				objs = []unstructured.Unstructured{*obj}
+			}
+		}
+		kindEntries, err := entriesOf(r.kind, objs)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, kindEntries...)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// entriesOf returns the operations recorded on the objects, the objects not changed by clusteradm are skipped
+func entriesOf(kind string, objs []unstructured.Unstructured) ([]Entry, error) {
+	entries := []Entry{}
+	for _, obj := range objs {
+		op, err := audit.Parse(obj.GetAnnotations())
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation on %s %s: %v", audit.Annotation, kind, obj.GetName(), err)
+		}
+		if op == nil {
+			continue
+		}
+		entries = append(entries, Entry{Kind: kind, Name: obj.GetName(), Operation: *op})
+	}
+	return entries, nil
+}
+
+// sortEntries sorts the entries from the most recent operation
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+}
+
+func printEntries(out io.Writer, entries []Entry) error {
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "no clusteradm operation recorded")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIMESTAMP\tKIND\tNAME\tUSER\tCOMMAND\tVERSION")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Timestamp.Format(time.RFC3339), e.Kind, e.Name, e.User, e.Command, e.Version)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+)
+
+func newObject(name string, op *audit.Operation) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetName(name)
+	if op != nil {
+		value, _ := json.Marshal(op)
+		obj.SetAnnotations(map[string]string{audit.Annotation: string(value)})
+	}
+	return obj
+}
+
+func TestEntriesOf(t *testing.T) {
+	older := audit.Operation{User: "admin", Timestamp: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Command: "clusteradm accept --clusters", Version: "v0.6.0"}
+	newer := audit.Operation{User: "dev", Timestamp: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Command: "clusteradm create work --file", Version: "v0.6.0"}
+
+	entries, err := entriesOf("ManifestWork", []unstructured.Unstructured{
+		newObject("old", &older),
+		newObject("untracked", nil),
+		newObject("new", &newer),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortEntries(entries)
+	expected := []Entry{
+		{Kind: "ManifestWork", Name: "new", Operation: newer},
+		{Kind: "ManifestWork", Name: "old", Operation: older},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected %+v, got %+v", expected, entries)
+	}
+
+	out := &bytes.Buffer{}
+	if err := printEntries(out, entries); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "clusteradm create work --file") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	invalid := newObject("invalid", nil)
+	invalid.SetAnnotations(map[string]string{audit.Annotation: "{"})
+	if _, err := entriesOf("ManifestWork", []unstructured.Unstructured{invalid}); err == nil {
+		t.Errorf("expected an error for an invalid annotation")
+	}
+}
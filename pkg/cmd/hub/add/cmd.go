@@ -0,0 +1,51 @@
+// Copyright Contributors to the Open Cluster Management project
+package add
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Add the hub of the current context of the kubeconfig
+%[1]s hub add prod --kubeconfig ~/.kube/prod.kubeconfig
+
+# Add a hub with the defaults of the commands targeting it
+%[1]s hub add staging --context staging-hub --clusterset staging --registry quay.io/my-mirror
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "add NAME",
+		Short:        "add or replace a named hub connection from --kubeconfig and --context",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.clusterSet, "clusterset", "", "The default of the --clusterset flags of the commands targeting the hub")
+	cmd.Flags().StringVar(&o.registry, "registry", "", "The default of the --image-registry flags of the commands targeting the hub")
+	cmd.Flags().BoolVar(&o.use, "use", false, "If set, the hub becomes the current hub")
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+// Copyright Contributors to the Open Cluster Management project
+package add
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("hub add options:", "name", args[0], "clusterset", o.clusterSet, "registry", o.registry, "use", o.use)
+	o.hub = hubs.Hub{
+		Name:       args[0],
+		ClusterSet: o.clusterSet,
+		Registry:   o.registry,
+	}
+	if kubeconfig, err := cmd.Flags().GetString("kubeconfig"); err == nil && len(kubeconfig) > 0 {
+		// the hub is used from any directory
+		if o.hub.Kubeconfig, err = filepath.Abs(kubeconfig); err != nil {
+			return err
+		}
+	}
+	// pin the context, the current context of the kubeconfig may change
+	rawConfig, err := o.ClusteradmFlags.KubectlFactory.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return err
+	}
+	o.hub.Context = o.ClusteradmFlags.Context
+	if len(o.hub.Context) == 0 {
+		o.hub.Context = rawConfig.CurrentContext
+	}
+	if _, ok := rawConfig.Contexts[o.hub.Context]; !ok {
+		return fmt.Errorf("context %q is not found in the kubeconfig", o.hub.Context)
+	}
+	o.path, err = hubs.DefaultPath()
+	return err
+}
+
+func (o *Options) validate() error {
+	if len(o.hub.Name) == 0 {
+		return fmt.Errorf("the name of the hub is missing")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	config, err := hubs.Load(o.path)
+	if err != nil {
+		return err
+	}
+	config.Set(o.hub)
+	if o.use {
+		config.Current = o.hub.Name
+	}
+	if err := config.Save(o.path); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "hub %s added with context %s\n", o.hub.Name, o.hub.Context)
+	return nil
+}
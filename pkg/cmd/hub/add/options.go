@@ -0,0 +1,32 @@
+// Copyright Contributors to the Open Cluster Management project
+package add
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	Streams         genericclioptions.IOStreams
+	//The hub connection to store
+	hub hubs.Hub
+	//The default clusterset of the commands targeting the hub
+	clusterSet string
+	//The default image registry of the commands targeting the hub
+	registry string
+	//If true the hub becomes the current hub
+	use bool
+	//The file of the hubs
+	path string
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
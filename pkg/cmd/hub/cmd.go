@@ -0,0 +1,36 @@
+// Copyright Contributors to the Open Cluster Management project
+package hub
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/hub/add"
+	"open-cluster-management.io/clusteradm/pkg/cmd/hub/diff"
+	"open-cluster-management.io/clusteradm/pkg/cmd/hub/list"
+	"open-cluster-management.io/clusteradm/pkg/cmd/hub/remove"
+	"open-cluster-management.io/clusteradm/pkg/cmd/hub/use"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+)
+
+// NewCmd provides a cobra command managing the named hub connections
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hub",
+		Short: "manage the named hub connections targeted with --hub and inspect the hub",
+	}
+
+	// the commands manage the hubs, they do not target one
+	for _, c := range []*cobra.Command{
+		add.NewCmd(clusteradmFlags, streams),
+		list.NewCmd(clusteradmFlags, streams),
+		remove.NewCmd(clusteradmFlags, streams),
+		use.NewCmd(clusteradmFlags, streams),
+	} {
+		c.Annotations = map[string]string{hubs.SkipAnnotation: ""}
+		cmd.AddCommand(c)
+	}
+	cmd.AddCommand(diff.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
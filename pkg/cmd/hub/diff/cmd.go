@@ -0,0 +1,50 @@
+// Copyright Contributors to the Open Cluster Management project
+package diff
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Report the changes made on the cluster manager of the hub since it was installed
+%[1]s hub diff
+
+# Compare with the templates of a bundle version, required if the images are referenced by digests
+%[1]s hub diff --bundle-version 0.9.1 --size medium
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "report the changes made on the cluster manager that an upgrade overwrites",
+		Long: "render the templates of the cluster manager and of its operator for the bundle version of the hub and compare them " +
+			"with the resources on the hub. The fields set by the templates which were changed or removed on the hub are " +
+			"reported, as the next upgrade overwrites them. The fields not set by the templates are not compared.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.bundleVersion, "bundle-version", "", "The bundle version the cluster manager was installed with, detected from its registration image by default")
+	cmd.Flags().StringVar(&o.size, "size", "small", "The --size the hub was initialized with, small, medium or large")
+
+	return cmd
+}
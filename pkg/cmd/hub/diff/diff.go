@@ -0,0 +1,96 @@
+// Copyright Contributors to the Open Cluster Management project
+package diff
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+	initcmd "open-cluster-management.io/clusteradm/pkg/cmd/init"
+	"open-cluster-management.io/clusteradm/pkg/helpers/podsecurity"
+	"open-cluster-management.io/clusteradm/pkg/helpers/version"
+)
+
+const (
+	// ClusterManagerName is the name of the cluster manager installed by init
+	ClusterManagerName = "cluster-manager"
+	operatorNamespace  = "open-cluster-management"
+	archLabel          = "kubernetes.io/arch"
+)
+
+// Templates returns the objects of the cluster manager and of its operator rendered for the bundle version
+// of the cluster manager, or for bundleVersion if set, and the bundle version. The architectures and the
+// seccomp profile are detected from the hub as init does.
+func Templates(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, cm *operatorv1.ClusterManager,
+	bundleVersion, size string) ([]*unstructured.Unstructured, string, error) {
+	registry, bundleVersion, err := registryAndVersion(cm.Spec.RegistrationImagePullSpec, bundleVersion)
+	if err != nil {
+		return nil, "", err
+	}
+	values, err := initcmd.HubValues(bundleVersion, registry, size)
+	if err != nil {
+		return nil, "", err
+	}
+	values.Architectures = architecturesOf(kubeClient)
+	if _, values.SeccompProfile, err = podsecurity.Detect(kubeClient, dynamicClient); err != nil {
+		return nil, "", err
+	}
+	objs, err := initcmd.RenderHub(values)
+	return objs, bundleVersion, err
+}
+
+// registryAndVersion returns the registry and the bundle version of the registration image of the cluster
+// manager, the bundle version is the one given if set
+func registryAndVersion(registrationImage, bundleVersion string) (string, string, error) {
+	i := strings.LastIndex(registrationImage, "/registration")
+	if i < 0 {
+		return "", "", fmt.Errorf("the registry of the registration image %q is not known", registrationImage)
+	}
+	registry := registrationImage[:i]
+	if len(bundleVersion) > 0 {
+		return registry, bundleVersion, nil
+	}
+	tag := version.ImageVersion(registrationImage)
+	if len(tag) == 0 {
+		return "", "", fmt.Errorf("the registration image %q has no tag, set the bundle version with --bundle-version", registrationImage)
+	}
+	bundleVersion, ok := version.BundleVersionOf(tag)
+	if !ok {
+		return "", "", fmt.Errorf("no bundle version has the registration image version %s, set it with --bundle-version", tag)
+	}
+	return registry, bundleVersion, nil
+}
+
+// architecturesOf returns the architectures the operator is scheduled to, nil if it is not restricted
+func architecturesOf(kubeClient kubernetes.Interface) []string {
+	deployment, err := kubeClient.AppsV1().Deployments(operatorNamespace).Get(context.TODO(), ClusterManagerName, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	affinity := deployment.Spec.Template.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return nil
+	}
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == archLabel && expression.Operator == corev1.NodeSelectorOpIn {
+				return expression.Values
+			}
+		}
+	}
+	return nil
+}
+
+// NameOf returns the namespace/name of the namespaced objects, the name of the others
+func NameOf(obj *unstructured.Unstructured) string {
+	if len(obj.GetNamespace()) == 0 {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,77 @@
+// Copyright Contributors to the Open Cluster Management project
+package diff
+
+import (
+	"testing"
+
+	initcmd "open-cluster-management.io/clusteradm/pkg/cmd/init"
+	"open-cluster-management.io/clusteradm/pkg/helpers/drift"
+)
+
+func TestDriftOfRenderedHub(t *testing.T) {
+	values, err := initcmd.HubValues("default", "quay.io/open-cluster-management", "small")
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs, err := initcmd.RenderHub(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range objs {
+		if differences := drift.Compare(obj.Object, obj.DeepCopy().Object); len(differences) != 0 {
+			t.Errorf("expected no differences of %s %s, got %v", obj.GetKind(), obj.GetName(), differences)
+		}
+	}
+}
+
+func TestRegistryAndVersion(t *testing.T) {
+	cases := []struct {
+		name              string
+		registrationImage string
+		bundleVersion     string
+		expectedRegistry  string
+		expectedVersion   string
+		expectErr         bool
+	}{
+		{
+			name:              "bundle version of the tag",
+			registrationImage: "quay.io/open-cluster-management/registration:v0.9.0",
+			expectedRegistry:  "quay.io/open-cluster-management",
+			expectedVersion:   "0.9.1",
+		},
+		{
+			name:              "bundle version given",
+			registrationImage: "my-registry:5000/ocm/registration@sha256:0123",
+			bundleVersion:     "0.9.1",
+			expectedRegistry:  "my-registry:5000/ocm",
+			expectedVersion:   "0.9.1",
+		},
+		{
+			name:              "digest without bundle version",
+			registrationImage: "quay.io/open-cluster-management/registration@sha256:0123",
+			expectErr:         true,
+		},
+		{
+			name:              "unknown tag",
+			registrationImage: "quay.io/open-cluster-management/registration:v0.0.1",
+			expectErr:         true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			registry, bundleVersion, err := registryAndVersion(c.registrationImage, c.bundleVersion)
+			if c.expectErr {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if registry != c.expectedRegistry || bundleVersion != c.expectedVersion {
+				t.Errorf("expected %s %s, got %s %s", c.expectedRegistry, c.expectedVersion, registry, bundleVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright Contributors to the Open Cluster Management project
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/drift"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("hub diff options:", "dry-run", o.ClusteradmFlags.DryRun, "bundle-version", o.bundleVersion, "size", o.size)
+	return nil
+}
+
+func (o *Options) validate() error {
+	return o.ClusteradmFlags.ValidateHub()
+}
+
+func (o *Options) run() error {
+	f := o.ClusteradmFlags.KubectlFactory
+	kubeClient, _, dynamicClient, err := helpers.GetClients(f)
+	if err != nil {
+		return err
+	}
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	mapper, err := f.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+
+	cm, err := operatorClient.OperatorV1().ClusterManagers().Get(context.TODO(), ClusterManagerName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("the cluster manager is not installed on the hub")
+	}
+	if err != nil {
+		return err
+	}
+	objs, bundleVersion, err := Templates(kubeClient, dynamicClient, cm, o.bundleVersion, o.size)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return err
+		}
+		live, err := dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()).Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			fmt.Fprintf(o.Streams.Out, "%s %s: missing\n", gvk.Kind, NameOf(obj))
+			changed++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, d := range drift.Compare(obj.Object, live.Object) {
+			fmt.Fprintf(o.Streams.Out, "%s %s: %s\n", gvk.Kind, NameOf(obj), d)
+			changed++
+		}
+	}
+	if changed > 0 {
+		return fmt.Errorf("%d changes of the cluster manager from the templates of bundle version %s are overwritten by the next upgrade",
+			changed, bundleVersion)
+	}
+	fmt.Fprintf(o.Streams.Out, "The cluster manager matches the templates of bundle version %s\n", bundleVersion)
+	return nil
+}
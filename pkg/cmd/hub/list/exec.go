@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+package list
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("hub list options:", "output", o.output)
+	o.path, err = hubs.DefaultPath()
+	return err
+}
+
+func (o *Options) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("output should be json or text")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	config, err := hubs.Load(o.path)
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		return clusteradmjson.WriteJsonOutput(o.Streams.Out, config)
+	}
+	return printHubs(o.Streams.Out, config)
+}
+
+// printHubs prints a table of the hubs, the current hub is marked with *
+func printHubs(out io.Writer, config *hubs.Config) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tKUBECONFIG\tCONTEXT\tCLUSTERSET\tREGISTRY")
+	for _, hub := range config.Hubs {
+		current := ""
+		if hub.Name == config.Current {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", current, hub.Name, orDefault(hub.Kubeconfig),
+			orDefault(hub.Context), orDefault(hub.ClusterSet), orDefault(hub.Registry))
+	}
+	return w.Flush()
+}
+
+func orDefault(value string) string {
+	if len(value) == 0 {
+		return "<default>"
+	}
+	return value
+}
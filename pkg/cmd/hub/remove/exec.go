@@ -0,0 +1,41 @@
+// Copyright Contributors to the Open Cluster Management project
+package remove
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	o.name = args[0]
+	klog.V(1).InfoS("hub remove options:", "name", o.name)
+	o.path, err = hubs.DefaultPath()
+	return err
+}
+
+func (o *Options) run() error {
+	config, err := hubs.Load(o.path)
+	if err != nil {
+		return err
+	}
+	hub := config.Get(o.name)
+	if hub == nil {
+		return fmt.Errorf("hub %s is not found", o.name)
+	}
+	// the credentials stored by login are removed with the hub
+	if len(hub.Issuer) > 0 && hub.Kubeconfig == hubs.KubeconfigPath(o.path, hub.Name) {
+		if err := os.Remove(hub.Kubeconfig); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	config.Remove(o.name)
+	if err := config.Save(o.path); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "hub %s removed\n", o.name)
+	return nil
+}
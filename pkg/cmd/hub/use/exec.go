@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+package use
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	klog.V(1).InfoS("hub use options:", "name", o.name, "none", o.none)
+	o.path, err = hubs.DefaultPath()
+	return err
+}
+
+func (o *Options) validate() error {
+	if o.none == (len(o.name) > 0) {
+		return fmt.Errorf("either the name of the hub or --none must be set")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	config, err := hubs.Load(o.path)
+	if err != nil {
+		return err
+	}
+	if _, err := config.Resolve(o.name); err != nil {
+		return err
+	}
+	config.Current = o.name
+	if err := config.Save(o.path); err != nil {
+		return err
+	}
+	if o.none {
+		fmt.Fprintln(o.Streams.Out, "no current hub, the commands target the kubeconfig and the context of the environment")
+		return nil
+	}
+	fmt.Fprintf(o.Streams.Out, "the commands without --hub target hub %s\n", o.name)
+	return nil
+}
@@ -3,16 +3,52 @@ package init
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/stamp"
 )
 
 var example = `
 # Init the hub
 %[1]s init
+
+# Init the hub, the webhook serving certificates are signed by the CA in the secret
+%[1]s init --webhook-cert-secret open-cluster-management/webhook-ca
+
+# Init the hub, the webhook serving certificates are signed by a CA issued by cert-manager,
+# re-run it once cert-manager renewed the CA to rotate the signer
+%[1]s init --use-cert-manager --cert-manager-issuer my-cluster-issuer
+
+# Init the hub, the bootstrap service account is created in a namespace exempted from the admission policies
+%[1]s init --bootstrap-namespace ocm-bootstrap --bootstrap-labels policy.example.com/exempt=true
+
+# Init the hub, the cluster manager is installed with its helm chart
+%[1]s init --use-helm --chart-version 0.11.0 --values hub-values.yaml
+
+# Init the hub, then create the clustersets, placements and addons of the organization
+%[1]s init --wait --bootstrap-profile profile.yaml
+
+# Init the hub, the resources created are labeled for the cost allocation and selected by the backups
+%[1]s init --labels cost-center=platform,backup=true --annotations owner=platform-team
+
+# Init the hub of a fleet of up to 1000 managed clusters
+%[1]s init --size medium
+
+# Init a hub whose API server is the external cluster of the kubeconfig, the cluster manager runs
+# on the cluster in the context and its webhooks are reached from the hub through the addresses
+%[1]s init --mode hosted --external-hub-kubeconfig hub.kubeconfig \
+  --registration-webhook-address registration-webhook.example.com \
+  --work-webhook-address work-webhook.example.com:8443
+
+# Init the hub and report its anonymized result to the telemetry endpoint of the platform team
+%[1]s init --telemetry --telemetry-endpoint https://telemetry.example.com/clusteradm
 `
 
 // NewCmd ...
@@ -31,30 +67,89 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.complete(c, args); err != nil {
-				return err
+				return exit.Validation(err)
 			}
 			if err := o.validate(); err != nil {
-				return err
+				return exit.Validation(err)
 			}
-			if err := o.run(); err != nil {
-				return err
+			start := time.Now()
+			err := o.run(c.Context())
+			if !o.ClusteradmFlags.DryRun {
+				o.telemetry.Record(o.ClusteradmFlags.KubectlFactory, "init", o.bundleVersion, start, err, o.Streams.ErrOut)
 			}
-
-			return nil
+			return err
 		},
 	}
 
-	cmd.Flags().StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
-	cmd.Flags().BoolVar(&o.useBootstrapToken, "use-bootstrap-token", false, "If set then the bootstrap token will used instead of a service account token")
-	cmd.Flags().BoolVar(&o.force, "force", false, "If set then the hub will be reinitialized")
-	cmd.Flags().StringVar(&o.registry, "image-registry", "quay.io/open-cluster-management",
+	o.addFlags(cmd.Flags())
+	stamp.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// addFlags adds the flags of the options, their defaults are the defaults of the options
+func (o *Options) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
+	flags.BoolVar(&o.useBootstrapToken, "use-bootstrap-token", false, "If set then the bootstrap token will used instead of a service account token")
+	flags.BoolVar(&o.force, "force", false,
+		"If set then the hub will be reinitialized, the preflight checks are skipped and an installed version incompatible with --bundle-version is a warning")
+	flags.StringVar(&o.registry, "image-registry", "quay.io/open-cluster-management",
 		"The name of the image registry serving OCM images, which will be applied to all the deploying OCM components.")
-	cmd.Flags().StringVar(&o.bundleVersion, "bundle-version", "default",
+	flags.StringVar(&o.bundleVersion, "bundle-version", "default",
 		`the version of predefined compatible image versions. e.g. v0.6.0, defaulted to the latest release version. also, we can set "latest" to install latest develop version`)
-	cmd.Flags().StringVar(&o.outputJoinCommandFile, "output-join-command-file", "",
+	flags.StringVar(&o.outputJoinCommandFile, "output-join-command-file", "",
 		"If set, the generated join command be saved to the prescribed file.")
-	cmd.Flags().BoolVar(&o.wait, "wait", false,
+	flags.BoolVar(&o.wait, "wait", false,
 		"If set, the command will initialize the OCM control plan in foreground.")
-	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output foramt, should be json or text")
-	return cmd
+	flags.StringVarP(&o.output, "output", "o", "text", "output foramt, should be json or text")
+	flags.StringVar(&o.imageDigestFile, "image-digest-file", "",
+		"A yaml file mapping the image names (registration-operator, registration, work, placement) to their digests, "+
+			"the images are referenced by digests instead of tags")
+	flags.BoolVar(&o.skipImageCheck, "skip-image-check", false,
+		"If true, the images and their architectures are not looked up in the registry, "+
+			"e.g. if the registry is mirrored on the nodes or not reachable from here")
+	o.verifyImages.AddFlags(flags)
+	o.telemetry.AddFlags(flags)
+	o.report.AddFlags(flags)
+	flags.StringVar(&o.bootstrapNamespace, "bootstrap-namespace", config.OpenClusterManagementNamespace,
+		"The namespace of the bootstrap service account and its token, the bootstrap token secrets are always in kube-system")
+	flags.StringSliceVar(&o.bootstrapLabels, "bootstrap-labels", []string{},
+		"Labels to add to the bootstrap namespace, service account and token secret (eg. key1=value1,key2=value2)")
+	flags.StringSliceVar(&o.bootstrapAnnotations, "bootstrap-annotations", []string{},
+		"Annotations to add to the bootstrap namespace, service account and token secret (eg. key1=value1,key2=value2)")
+	flags.StringVar(&o.webhookCertSecret, "webhook-cert-secret", "",
+		"The TLS secret holding the CA which signs the registration and work webhook serving certificates, in the format of "+
+			"[namespace/]name, the namespace is defaulted to open-cluster-management")
+	flags.BoolVar(&o.useCertManager, "use-cert-manager", false,
+		"If set, the CA which signs the registration and work webhook serving certificates is issued by cert-manager. "+
+			"The CA is copied to the signer, re-run init with this flag once cert-manager renewed it")
+	flags.StringVar(&o.certManagerIssuer, "cert-manager-issuer", "",
+		"The cert-manager ClusterIssuer issuing the webhook CA, a self-signed issuer is created if not set. Only used with --use-cert-manager")
+	flags.BoolVar(&o.useHelm, "use-helm", false,
+		"If set, the cluster manager is installed with its helm chart by the helm binary instead of the templates of clusteradm")
+	flags.StringVar(&o.chart, "chart", defaultChart,
+		"The cluster manager chart, a chart of --chart-repo, an oci:// reference or a local path. Only used with --use-helm")
+	flags.StringVar(&o.chartRepo, "chart-repo", defaultChartRepo, "The repository of the cluster manager chart. Only used with --use-helm")
+	flags.StringVar(&o.chartVersion, "chart-version", "", "The version of the cluster manager chart, the latest one if not set. Only used with --use-helm")
+	flags.StringSliceVar(&o.chartValues, "values", []string{}, "The values files passed to the cluster manager chart. Only used with --use-helm")
+	flags.StringVar(&o.bootstrapProfileFile, "bootstrap-profile", "",
+		"A file of the globalBindings namespaces of the global clusterset, the teams namespaces with their clustersets and placement, "+
+			"and the built-in addons with their install strategy, created after the hub is installed")
+	flags.StringVar(&o.preferIPFamily, "prefer-ip-family", "",
+		"The IP family the dual-stack managed clusters reach the hub over, ipv4 or ipv6: a warning is printed if "+
+			"the hub API server or the endpoints of the cluster-info have no address of it")
+	flags.StringVar(&o.size, "size", defaultSize,
+		"The preset sizing the hub for the expected number of managed clusters: small up to 100, medium up to 1000, large above 1000. "+
+			"It scales the replicas and resource requests of the cluster manager operator, the ClusterManager of the bundle versions "+
+			"does not expose the replicas, concurrency or QPS of the hub controllers")
+	flags.StringVar(&o.mode, "mode", modeDefault,
+		"The mode of the cluster manager, default or hosted. In hosted mode the cluster manager runs on the cluster in the context, "+
+			"in the cluster-manager namespace, while the hub API server is the cluster of --external-hub-kubeconfig")
+	flags.StringVar(&o.externalHubKubeconfig, "external-hub-kubeconfig", "",
+		"The kubeconfig of the hub with the cluster-admin permissions, its current context is used. Only used with --mode hosted")
+	flags.StringVar(&o.registrationWebhookAddress, "registration-webhook-address", "",
+		"The address of the registration webhook reachable from the hub, in the format of host[:port], the port is defaulted to 443. "+
+			"Only used with --mode hosted")
+	flags.StringVar(&o.workWebhookAddress, "work-webhook-address", "",
+		"The address of the work webhook reachable from the hub, in the format of host[:port], the port is defaulted to 443. "+
+			"Only used with --mode hosted")
 }
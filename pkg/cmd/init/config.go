@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"context"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Config is the configuration of an init run from Go, e.g. by pkg/init, the zero values are the defaults of the flags
+type Config struct {
+	BundleVersion     string
+	Registry          string
+	UseBootstrapToken bool
+	Wait              bool
+}
+
+// Result is what the clusters need to join the hub
+type Result struct {
+	HubToken     string
+	HubAPIServer string
+	// CAHashes are the hashes of the public key of the hub CA
+	CAHashes []string
+}
+
+// NewOptionsOf returns the options of the init of the config, the options which are not in the config
+// have the defaults of the flags
+func NewOptionsOf(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams, config Config) *Options {
+	o := newOptions(clusteradmFlags, streams)
+	o.addFlags(pflag.NewFlagSet("init", pflag.ContinueOnError))
+	o.useBootstrapToken = config.UseBootstrapToken
+	o.wait = config.Wait
+	if len(config.BundleVersion) > 0 {
+		o.bundleVersion = config.BundleVersion
+	}
+	if len(config.Registry) > 0 {
+		o.registry = config.Registry
+	}
+	return o
+}
+
+// Run completes, validates and runs the init, it stops waiting when the context is done
+func (o *Options) Run(ctx context.Context) (*Result, error) {
+	if err := o.complete(nil, nil); err != nil {
+		return nil, err
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if err := o.run(ctx); err != nil {
+		return nil, err
+	}
+	return o.result, nil
+}
@@ -5,29 +5,50 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/stolostron/applier/pkg/apply"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
 	"open-cluster-management.io/clusteradm/pkg/cmd/init/preflight"
 	"open-cluster-management.io/clusteradm/pkg/cmd/init/scenario"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+	"open-cluster-management.io/clusteradm/pkg/helpers/endpoint"
+	"open-cluster-management.io/clusteradm/pkg/helpers/i18n"
+	"open-cluster-management.io/clusteradm/pkg/helpers/image"
 	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+	"open-cluster-management.io/clusteradm/pkg/helpers/podsecurity"
 	preflightinterface "open-cluster-management.io/clusteradm/pkg/helpers/preflight"
+	"open-cluster-management.io/clusteradm/pkg/helpers/tlspolicy"
 	version "open-cluster-management.io/clusteradm/pkg/helpers/version"
 	helperwait "open-cluster-management.io/clusteradm/pkg/helpers/wait"
 )
 
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
-	klog.V(1).InfoS("init options:", "dry-run", o.ClusteradmFlags.DryRun, "force", o.force, "output-file", o.outputFile)
+	klog.V(1).InfoS("init options:", "dry-run", o.ClusteradmFlags.DryRun, "force", o.force, "output-file", o.outputFile,
+		"bootstrap-namespace", o.bootstrapNamespace, "size", o.size, "mode", o.mode)
 	o.values = Values{
 		Hub: Hub{
 			TokenID:     helpers.RandStringRunes_az09(6),
 			TokenSecret: helpers.RandStringRunes_az09(16),
 			Registry:    o.registry,
+
+			BootstrapNamespace: o.bootstrapNamespace,
 		},
 	}
+	if o.values.Hub.BootstrapLabels, err = helpers.ParseKeyValues(o.bootstrapLabels); err != nil {
+		return err
+	}
+	if o.values.Hub.BootstrapAnnotations, err = helpers.ParseKeyValues(o.bootstrapAnnotations); err != nil {
+		return err
+	}
 
 	versionBundle, err := version.GetVersionBundle(o.bundleVersion)
 
@@ -43,35 +64,157 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 		OperatorImageVersion:     versionBundle.Operator,
 	}
 
-	return nil
+	digests := image.Digests{}
+	if len(o.imageDigestFile) > 0 {
+		if digests, err = image.LoadDigestFile(o.imageDigestFile); err != nil {
+			return err
+		}
+	}
+	o.values.Webhook.CertManagerIssuer = o.certManagerIssuer
+	if o.values.Size, err = getSize(o.size); err != nil {
+		return err
+	}
+
+	o.values.Images = Images{
+		Operator:     image.PullSpec(o.registry, image.OperatorImageName, versionBundle.Registration, digests),
+		Registration: image.PullSpec(o.registry, image.RegistrationImageName, versionBundle.Registration, digests),
+		Work:         image.PullSpec(o.registry, image.WorkImageName, versionBundle.Work, digests),
+		Placement:    image.PullSpec(o.registry, image.PlacementImageName, versionBundle.Placement, digests),
+	}
+
+	return o.completeHosted()
 }
 
 func (o *Options) validate() error {
-	if o.force {
-		return nil
+	if err := o.verifyImages.Validate(); err != nil {
+		return err
+	}
+	if err := o.telemetry.Validate(); err != nil {
+		return err
+	}
+	if err := o.report.Validate(); err != nil {
+		return err
+	}
+	if len(o.bootstrapNamespace) == 0 {
+		return fmt.Errorf("--bootstrap-namespace should not be empty")
+	}
+	if err := o.validateHosted(); err != nil {
+		return err
+	}
+	if err := endpoint.ValidateFamily(o.preferIPFamily); err != nil {
+		return fmt.Errorf("invalid --prefer-ip-family: %v", err)
+	}
+	if len(o.webhookCertSecret) > 0 && o.useCertManager {
+		return fmt.Errorf("--webhook-cert-secret and --use-cert-manager are mutually exclusive")
+	}
+	if len(o.certManagerIssuer) > 0 && !o.useCertManager {
+		return fmt.Errorf("--cert-manager-issuer is only supported with --use-cert-manager")
+	}
+	if len(o.webhookCertSecret) > 0 {
+		if _, _, err := parseWebhookCertSecret(o.webhookCertSecret); err != nil {
+			return err
+		}
+	}
+	if o.useHelm {
+		if len(o.webhookCertSecret) > 0 || o.useCertManager {
+			return fmt.Errorf("--webhook-cert-secret and --use-cert-manager are not supported with --use-helm, set the chart values instead")
+		}
+		if o.size != defaultSize {
+			return fmt.Errorf("--size is not supported with --use-helm, set the chart values instead")
+		}
+		if len(o.chart) == 0 {
+			return fmt.Errorf("--chart should not be empty")
+		}
+		if _, err := exec.LookPath(helmBinary); err != nil && !o.ClusteradmFlags.DryRun {
+			return fmt.Errorf("%s is required to install the cluster manager with --use-helm: %v", helmBinary, err)
+		}
+	}
+	if len(o.bootstrapProfileFile) > 0 {
+		profile, err := loadBootstrapProfile(o.bootstrapProfileFile)
+		if err != nil {
+			return err
+		}
+		o.bootstrapProfile = profile
 	}
-	// preflight check
 	f := o.ClusteradmFlags.KubectlFactory
-	kubeClient, _, _, err := helpers.GetClients(f)
+	kubeClient, apiExtensionsClient, dynamicClient, err := helpers.GetClients(f)
+	if err != nil {
+		return err
+	}
+	podSecurityCheck := &podsecurity.Check{
+		KubeClient:    kubeClient,
+		DynamicClient: dynamicClient,
+		Namespaces:    []string{config.OpenClusterManagementNamespace},
+	}
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
 	if err != nil {
 		return err
 	}
-	if err := preflightinterface.RunChecks(
+	versionCheck := preflight.ClusterManagerVersionCheck{
+		OperatorClient: operatorClient,
+		Version:        o.values.BundleVersion.RegistrationImageVersion,
+		Force:          o.force,
+	}
+	if o.force {
+		// the preflight checks are skipped, the seccomp profile is still detected and
+		// the incompatible versions are reported
+		_, o.values.SeccompProfile, _ = podsecurity.Detect(kubeClient, dynamicClient)
+		return o.report.RunChecks("init", []preflightinterface.Checker{versionCheck}, o.Streams.ErrOut)
+	}
+	// preflight check
+	architectureCheck := &image.ArchitectureCheck{
+		Images: []string{
+			o.values.Images.Operator,
+			o.values.Images.Registration,
+			o.values.Images.Work,
+			o.values.Images.Placement,
+		},
+		KubeClient: kubeClient,
+		Skip:       o.skipImageCheck,
+	}
+	// the managed clusters join the external hub in Hosted mode
+	hubCtx, hubConfigPath, hubKubeClient := o.ClusteradmFlags.Context, "", kubeClient
+	if o.values.Hosted.Enabled {
+		hubCtx, hubConfigPath = "", o.externalHubKubeconfig
+		if hubKubeClient, _, _, err = o.externalHubClients(); err != nil {
+			return err
+		}
+	}
+	if err := o.report.RunChecks("init",
 		[]preflightinterface.Checker{
 			preflight.HubApiServerCheck{
-				ClusterCtx: o.ClusteradmFlags.Context,
-				ConfigPath: "", // TODO(@Promacanthus)： user custom kubeconfig path from command line arguments.
+				ClusterCtx: hubCtx,
+				ConfigPath: hubConfigPath,
+				Family:     o.preferIPFamily,
 			},
 			preflight.ClusterInfoCheck{
 				Namespace:    metav1.NamespacePublic,
 				ResourceName: preflight.BootstrapConfigMap,
-				ClusterCtx:   o.ClusteradmFlags.Context,
-				ConfigPath:   "", // TODO(@Promacanthus)： user custom kubeconfig path from command line arguments.
-				Client:       kubeClient,
+				ClusterCtx:   hubCtx,
+				ConfigPath:   hubConfigPath,
+				Client:       hubKubeClient,
+				Family:       o.preferIPFamily,
+			},
+			architectureCheck,
+			podSecurityCheck,
+			versionCheck,
+			tlspolicy.Check{Config: restConfig},
+			attach.ConflictCheck{
+				KubeClient:          kubeClient,
+				APIExtensionsClient: apiExtensionsClient,
+				Mode:                attach.ModeHub,
+				OperatorNamespace:   config.OpenClusterManagementNamespace,
+				OperatorName:        config.ClusterManagerName,
 			},
-		}, os.Stderr); err != nil {
+		}, o.Streams.ErrOut); err != nil {
 		return err
 	}
+	o.values.Architectures = architectureCheck.Architectures
+	o.values.SeccompProfile = podSecurityCheck.SeccompProfile
 
 	if len(o.registry) == 0 {
 		return fmt.Errorf("registry should not be empty")
@@ -85,7 +228,25 @@ func (o *Options) validate() error {
 	return nil
 }
 
-func (o *Options) run() error {
+func (o *Options) run(ctx context.Context) error {
+	// verify the images before deploying anything, fail closed if the verification is enabled.
+	// The verified digests are deployed, the images are not verified by a dry run
+	if o.verifyImages.Enabled && o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.ErrOut, "The images are not verified by a dry run\n")
+	} else {
+		images, err := o.verifyImages.Verify([]string{
+			o.values.Images.Operator,
+			o.values.Images.Registration,
+			o.values.Images.Work,
+			o.values.Images.Placement,
+		})
+		if err != nil {
+			return err
+		}
+		o.values.Images.Operator, o.values.Images.Registration = images[0], images[1]
+		o.values.Images.Work, o.values.Images.Placement = images[2], images[3]
+	}
+
 	token := fmt.Sprintf("%s.%s", o.values.Hub.TokenID, o.values.Hub.TokenSecret)
 	output := make([]string, 0)
 	reader := scenario.GetScenarioResourcesReader()
@@ -98,9 +259,29 @@ func (o *Options) run() error {
 	applierBuilder := apply.NewApplierBuilder()
 	applier := applierBuilder.WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
 
+	// the bootstrap resources are created on the external hub in Hosted mode, the cluster manager
+	// runs on the cluster in the context
+	hubKubeClient, hubApplier := kubeClient, applier
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	if o.values.Hosted.Enabled {
+		var hubAPIExtensionsClient apiextensionsclient.Interface
+		var hubDynamicClient dynamic.Interface
+		if hubKubeClient, hubAPIExtensionsClient, hubDynamicClient, err = o.externalHubClients(); err != nil {
+			return err
+		}
+		hubApplier = apply.NewApplierBuilder().WithClient(hubKubeClient, hubAPIExtensionsClient, hubDynamicClient).Build()
+		restConfig = o.hubRestConfig
+	}
+
 	files := []string{
 		"init/namespace.yaml",
 	}
+	if o.bootstrapNamespace != config.OpenClusterManagementNamespace {
+		files = append(files, "init/bootstrap_namespace.yaml")
+	}
 	if o.useBootstrapToken {
 		files = append(files,
 			"init/bootstrap-token-secret.yaml",
@@ -114,13 +295,28 @@ func (o *Options) run() error {
 			"init/bootstrap_sa_cluster_role_binding.yaml",
 		)
 	}
+	if o.values.Hosted.Enabled {
+		out, err := hubApplier.ApplyDirectly(reader, o.values, o.ClusteradmFlags.DryRun, "", files...)
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
+		files = append([]string{"init/namespace.yaml"}, hostedFiles...)
+	}
 
-	files = append(files,
-		"init/clustermanager_cluster_role.yaml",
-		"init/clustermanager_cluster_role_binding.yaml",
-		"init/clustermanagers.crd.yaml",
-		"init/clustermanager_sa.yaml",
-	)
+	// the cluster manager is installed by the chart, only the bootstrap resources are applied
+	if o.useHelm {
+		if err := o.installWithHelm(ctx); err != nil {
+			return err
+		}
+	} else {
+		files = append(files,
+			"init/clustermanager_cluster_role.yaml",
+			"init/clustermanager_cluster_role_binding.yaml",
+			"init/clustermanagers.crd.yaml",
+			"init/clustermanager_sa.yaml",
+		)
+	}
 
 	out, err := applier.ApplyDirectly(reader, o.values, o.ClusteradmFlags.DryRun, "", files...)
 	if err != nil {
@@ -128,11 +324,25 @@ func (o *Options) run() error {
 	}
 	output = append(output, out...)
 
-	out, err = applier.ApplyDeployments(reader, o.values, o.ClusteradmFlags.DryRun, "", "init/operator.yaml")
-	if err != nil {
-		return err
+	if o.useCertManager && !o.ClusteradmFlags.DryRun {
+		if _, err := apiExtensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(
+			ctx, certManagerCRD, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("cert-manager should be installed to use --use-cert-manager: %v", err)
+		}
+	}
+	if !o.useHelm {
+		out, err = o.applyWebhookSigner(ctx, kubeClient, applier, reader)
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
+
+		out, err = applier.ApplyDeployments(reader, o.values, o.ClusteradmFlags.DryRun, "", "init/operator.yaml")
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
 	}
-	output = append(output, out...)
 
 	if !o.ClusteradmFlags.DryRun {
 		if err := helperwait.WaitUntilCRDReady(apiExtensionsClient, "clustermanagers.operator.open-cluster-management.io", o.wait); err != nil {
@@ -147,37 +357,56 @@ func (o *Options) run() error {
 		}
 	}
 
-	out, err = applier.ApplyCustomResources(reader, o.values, o.ClusteradmFlags.DryRun, "", "init/clustermanager.cr.yaml")
-	if err != nil {
-		return err
+	if !o.useHelm {
+		out, err = applier.ApplyCustomResources(reader, o.values, o.ClusteradmFlags.DryRun, "", "init/clustermanager.cr.yaml")
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
 	}
-	output = append(output, out...)
 
 	if o.wait && !o.ClusteradmFlags.DryRun {
 		if err := helperwait.WaitUntilClusterManagerRegistrationReady(
 			o.ClusteradmFlags.KubectlFactory,
+			o.registrationNamespace(),
 			int64(o.ClusteradmFlags.Timeout)); err != nil {
 			return err
 		}
 	}
 
+	if o.bootstrapProfile != nil {
+		if err := o.applyBootstrapProfile(ctx, kubeClient, apiExtensionsClient, dynamicClient); err != nil {
+			return err
+		}
+	}
+
 	//if service-account wait for the sa secret
 	if !o.useBootstrapToken && !o.ClusteradmFlags.DryRun {
-		token, err = helpers.GetBootstrapTokenFromSA(context.TODO(), kubeClient)
+		token, err = helpers.GetBootstrapTokenFromSA(ctx, hubKubeClient, o.bootstrapNamespace)
 		if err != nil {
 			return err
 		}
 	}
 
-	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	// the IPv6 literals are bracketed in the printed command
+	hubAPIServer, err := endpoint.Normalize(restConfig.Host)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	cmd := fmt.Sprintf("%s join --hub-token %s --hub-apiserver %s",
 		helpers.GetExampleHeader(),
 		token,
-		restConfig.Host)
+		hubAPIServer)
+
+	// the joining clusters verify the CA they read from the hub against its hashes
+	caHashes, err := helpers.GetCAHashes(hubKubeClient)
+	if err != nil {
+		return err
+	}
+	if len(caHashes) > 0 {
+		cmd = cmd + " --discovery-token-ca-cert-hash " + strings.Join(caHashes, ",")
+	}
 
 	// if the init command prescribes a foreground installation, adds the `--wait`
 	// flag to the join command to cohere the behavior of init and join commands.
@@ -199,21 +428,18 @@ func (o *Options) run() error {
 		}
 	}
 
+	o.result = &Result{HubToken: token, HubAPIServer: hubAPIServer, CAHashes: caHashes}
 	if o.output == "json" {
-		err := clusteradmjson.WriteJsonOutput(os.Stdout, clusteradmjson.HubInfo{
+		err := clusteradmjson.WriteJsonOutput(o.Streams.Out, clusteradmjson.HubInfo{
 			HubToken:     token,
-			HubApiserver: restConfig.Host,
+			HubApiserver: hubAPIServer,
+			CAHashes:     caHashes,
 		})
 		if err != nil {
 			return err
 		}
 	} else {
-		fmt.Printf("The multicluster hub control plane has been initialized successfully!\n\n"+
-			"You can now register cluster(s) to the hub control plane. Log onto those cluster(s) and run the following command:\n\n"+
-			"    %s --cluster-name <cluster_name>\n\n"+
-			"Replace <cluster_name> with a cluster name of your choice. For example, cluster1.\n\n",
-			cmd,
-		)
+		fmt.Fprint(o.Streams.Out, i18n.T("init.success", cmd))
 	}
 
 	return apply.WriteOutput(o.outputFile, output)
@@ -0,0 +1,64 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/config"
+)
+
+const (
+	helmBinary       = "helm"
+	defaultChart     = "cluster-manager"
+	defaultChartRepo = "https://open-cluster-management.io/helm-charts"
+)
+
+// helmArgs returns the arguments of the helm command installing or upgrading the cluster manager
+// release, the chart is looked up in the chart repository unless it is an oci reference or a path.
+func (o *Options) helmArgs(kubeconfig string) []string {
+	args := []string{
+		"upgrade", "--install", config.ClusterManagerName, o.chart,
+		"--namespace", config.OpenClusterManagementNamespace,
+		"--create-namespace",
+	}
+	if _, err := os.Stat(o.chart); err != nil && !strings.HasPrefix(o.chart, "oci://") {
+		args = append(args, "--repo", o.chartRepo)
+	}
+	if len(o.chartVersion) > 0 {
+		args = append(args, "--version", o.chartVersion)
+	}
+	for _, values := range o.chartValues {
+		args = append(args, "--values", values)
+	}
+	if len(kubeconfig) > 0 {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+	if len(o.ClusteradmFlags.Context) > 0 {
+		args = append(args, "--kube-context", o.ClusteradmFlags.Context)
+	}
+	return args
+}
+
+// installWithHelm installs the cluster manager with its chart, in dry-run mode the command is only printed
+func (o *Options) installWithHelm(ctx context.Context) error {
+	kubeconfig := o.ClusteradmFlags.KubectlFactory.ToRawKubeConfigLoader().ConfigAccess().GetExplicitFile()
+	args := o.helmArgs(kubeconfig)
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "%s %s\n", helmBinary, strings.Join(args, " "))
+		return nil
+	}
+
+	klog.V(1).InfoS("running:", "command", helmBinary, "args", args)
+	c := exec.CommandContext(ctx, helmBinary, args...)
+	c.Stdout = o.Streams.ErrOut
+	c.Stderr = o.Streams.ErrOut
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to install the cluster manager chart %s: %v", o.chart, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+func TestHelmArgs(t *testing.T) {
+	localChart := filepath.Join(t.TempDir(), "cluster-manager")
+	if err := os.Mkdir(localChart, 0700); err != nil {
+		t.Fatal(err)
+	}
+	release := []string{"upgrade", "--install", "cluster-manager"}
+	namespace := []string{"--namespace", "open-cluster-management", "--create-namespace"}
+	join := func(parts ...[]string) []string {
+		var args []string
+		for _, p := range parts {
+			args = append(args, p...)
+		}
+		return args
+	}
+
+	cases := []struct {
+		name       string
+		options    Options
+		kubeconfig string
+		expected   []string
+	}{
+		{
+			name:     "chart of the repository",
+			options:  Options{chart: defaultChart, chartRepo: defaultChartRepo},
+			expected: join(release, []string{defaultChart}, namespace, []string{"--repo", defaultChartRepo}),
+		},
+		{
+			name: "version, values and context",
+			options: Options{
+				chart: defaultChart, chartRepo: defaultChartRepo, chartVersion: "0.11.0",
+				chartValues:     []string{"a.yaml", "b.yaml"},
+				ClusteradmFlags: &genericclioptionsclusteradm.ClusteradmFlags{Context: "hub"},
+			},
+			kubeconfig: "/tmp/kubeconfig",
+			expected: join(release, []string{defaultChart}, namespace, []string{"--repo", defaultChartRepo,
+				"--version", "0.11.0", "--values", "a.yaml", "--values", "b.yaml", "--kubeconfig", "/tmp/kubeconfig", "--kube-context", "hub"}),
+		},
+		{
+			name:     "oci chart",
+			options:  Options{chart: "oci://registry.example.com/charts/cluster-manager", chartRepo: defaultChartRepo},
+			expected: join(release, []string{"oci://registry.example.com/charts/cluster-manager"}, namespace),
+		},
+		{
+			name:     "local chart",
+			options:  Options{chart: localChart, chartRepo: defaultChartRepo},
+			expected: join(release, []string{localChart}, namespace),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.options.ClusteradmFlags == nil {
+				c.options.ClusteradmFlags = &genericclioptionsclusteradm.ClusteradmFlags{}
+			}
+			if actual := c.options.helmArgs(c.kubeconfig); !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
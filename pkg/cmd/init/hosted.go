@@ -0,0 +1,150 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	"open-cluster-management.io/clusteradm/pkg/helpers/restconfig"
+)
+
+const (
+	modeDefault = "default"
+	modeHosted  = "hosted"
+	// defaultWebhookPort is the port of the webhook servers exposed by the management cluster
+	defaultWebhookPort = 443
+)
+
+// hostedFiles are the templates applied to the management cluster in Hosted mode, the cluster manager
+// runs in the namespace named after the ClusterManager and reads the kubeconfig of the hub from the secret
+var hostedFiles = []string{
+	"init/hosted/namespace.yaml",
+	"init/hosted/external_hub_kubeconfig_secret.yaml",
+}
+
+// parseWebhookAddress returns the address of a webhook in the format of host[:port], the port is
+// defaulted to 443
+func parseWebhookAddress(flag, value string) (WebhookAddress, error) {
+	if len(value) == 0 {
+		return WebhookAddress{}, fmt.Errorf("%s is required with --mode %s", flag, modeHosted)
+	}
+	if !strings.Contains(value, ":") {
+		value = net.JoinHostPort(value, strconv.Itoa(defaultWebhookPort))
+	}
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return WebhookAddress{}, fmt.Errorf("invalid %s %q, should be in the format of host[:port]: %v", flag, value, err)
+	}
+	p, err := strconv.ParseInt(port, 10, 32)
+	if err != nil || p <= 0 || p > 65535 || len(host) == 0 || strings.ContainsAny(host, "/ ") {
+		return WebhookAddress{}, fmt.Errorf("invalid %s %q, should be in the format of host[:port]", flag, value)
+	}
+	return WebhookAddress{Address: host, Port: int32(p)}, nil
+}
+
+// loadExternalHubKubeconfig returns the kubeconfig of the current context of the file with its
+// credentials embedded, it is stored in the secret read by the cluster manager, and its rest config
+func loadExternalHubKubeconfig(path string) ([]byte, *rest.Config, error) {
+	kubeconfig, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load the external hub kubeconfig %s: %v", path, err)
+	}
+	if err := clientcmdapi.MinifyConfig(kubeconfig); err != nil {
+		return nil, nil, fmt.Errorf("invalid external hub kubeconfig %s: %v", path, err)
+	}
+	if err := clientcmdapi.FlattenConfig(kubeconfig); err != nil {
+		return nil, nil, fmt.Errorf("failed to embed the credentials of the external hub kubeconfig %s: %v", path, err)
+	}
+	data, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid external hub kubeconfig %s: %v", path, err)
+	}
+	return data, restconfig.Wrap(restConfig), nil
+}
+
+// externalHubClients returns the clients of the external hub
+func (o *Options) externalHubClients() (kubernetes.Interface, apiextensionsclient.Interface, dynamic.Interface, error) {
+	kubeClient, err := kubernetes.NewForConfig(o.hubRestConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	apiExtensionsClient, err := apiextensionsclient.NewForConfig(o.hubRestConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(o.hubRestConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return kubeClient, apiExtensionsClient, dynamicClient, nil
+}
+
+// completeHosted fills the values of the Hosted mode
+func (o *Options) completeHosted() (err error) {
+	if o.mode != modeHosted {
+		return nil
+	}
+	o.values.Hosted.Enabled = true
+	o.values.Hosted.Namespace = config.ClusterManagerName
+	if o.values.Hosted.RegistrationWebhook, err = parseWebhookAddress("--registration-webhook-address", o.registrationWebhookAddress); err != nil {
+		return err
+	}
+	if o.values.Hosted.WorkWebhook, err = parseWebhookAddress("--work-webhook-address", o.workWebhookAddress); err != nil {
+		return err
+	}
+	if len(o.externalHubKubeconfig) == 0 {
+		return fmt.Errorf("--external-hub-kubeconfig is required with --mode %s", modeHosted)
+	}
+	data, restConfig, err := loadExternalHubKubeconfig(o.externalHubKubeconfig)
+	if err != nil {
+		return err
+	}
+	o.values.Hosted.ExternalHubKubeconfig = string(data)
+	o.hubRestConfig = restConfig
+	return nil
+}
+
+// validateHosted rejects the options the Hosted mode does not support
+func (o *Options) validateHosted() error {
+	switch o.mode {
+	case modeDefault:
+		if len(o.externalHubKubeconfig) > 0 || len(o.registrationWebhookAddress) > 0 || len(o.workWebhookAddress) > 0 {
+			return fmt.Errorf("--external-hub-kubeconfig, --registration-webhook-address and --work-webhook-address are only supported with --mode %s", modeHosted)
+		}
+		return nil
+	case modeHosted:
+	default:
+		return fmt.Errorf("invalid --mode %q, should be %s or %s", o.mode, modeDefault, modeHosted)
+	}
+	if o.useHelm {
+		return fmt.Errorf("--use-helm is not supported with --mode %s", modeHosted)
+	}
+	if len(o.webhookCertSecret) > 0 || o.useCertManager {
+		return fmt.Errorf("--webhook-cert-secret and --use-cert-manager are not supported with --mode %s", modeHosted)
+	}
+	if len(o.bootstrapProfileFile) > 0 {
+		return fmt.Errorf("--bootstrap-profile is not supported with --mode %s", modeHosted)
+	}
+	return nil
+}
+
+// registrationNamespace returns the namespace of the registration controller on the cluster in the context
+func (o *Options) registrationNamespace() string {
+	if o.values.Hosted.Enabled {
+		return o.values.Hosted.Namespace
+	}
+	return config.HubClusterNamespace
+}
@@ -0,0 +1,115 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stolostron/applier/pkg/apply"
+	"open-cluster-management.io/clusteradm/pkg/cmd/init/scenario"
+)
+
+func TestParseWebhookAddress(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected WebhookAddress
+		err      bool
+	}{
+		{value: "webhook.example.com", expected: WebhookAddress{Address: "webhook.example.com", Port: 443}},
+		{value: "webhook.example.com:8443", expected: WebhookAddress{Address: "webhook.example.com", Port: 8443}},
+		{value: "[fd00::1]:9443", expected: WebhookAddress{Address: "fd00::1", Port: 9443}},
+		{value: "", err: true},
+		{value: "webhook.example.com:0", err: true},
+		{value: "webhook.example.com:http", err: true},
+		{value: "https://webhook.example.com", err: true},
+	}
+	for _, c := range cases {
+		address, err := parseWebhookAddress("--registration-webhook-address", c.value)
+		if c.err != (err != nil) {
+			t.Errorf("%q: unexpected error %v", c.value, err)
+			continue
+		}
+		if address != c.expected {
+			t.Errorf("%q: expected %v, got %v", c.value, c.expected, address)
+		}
+	}
+}
+
+func TestValidateHosted(t *testing.T) {
+	if err := (&Options{mode: modeDefault}).validateHosted(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := (&Options{mode: "remote"}).validateHosted(); err == nil {
+		t.Errorf("expected the invalid mode to be rejected")
+	}
+	if err := (&Options{mode: modeDefault, externalHubKubeconfig: "hub.kubeconfig"}).validateHosted(); err == nil {
+		t.Errorf("expected --external-hub-kubeconfig to be rejected in default mode")
+	}
+	if err := (&Options{mode: modeHosted, useHelm: true}).validateHosted(); err == nil {
+		t.Errorf("expected --use-helm to be rejected in hosted mode")
+	}
+}
+
+func TestCompleteHosted(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ca.crt"), []byte("ca"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig := filepath.Join(dir, "hub.kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: hub
+contexts:
+- name: hub
+  context: {cluster: hub, user: admin}
+- name: other
+  context: {cluster: other, user: admin}
+clusters:
+- name: hub
+  cluster: {server: "https://hub.example.com:6443", certificate-authority: ca.crt}
+- name: other
+  cluster: {server: "https://other.example.com:6443"}
+users:
+- name: admin
+  user: {token: secret}
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	o := &Options{
+		mode:                       modeHosted,
+		externalHubKubeconfig:      kubeconfig,
+		registrationWebhookAddress: "registration.example.com",
+		workWebhookAddress:         "work.example.com:8443",
+	}
+	if err := o.completeHosted(); err != nil {
+		t.Fatal(err)
+	}
+	if o.hubRestConfig.Host != "https://hub.example.com:6443" {
+		t.Errorf("expected the current context to be used, got %s", o.hubRestConfig.Host)
+	}
+	if strings.Contains(o.values.Hosted.ExternalHubKubeconfig, "other") ||
+		!strings.Contains(o.values.Hosted.ExternalHubKubeconfig, "certificate-authority-data") {
+		t.Errorf("expected the minified kubeconfig with the CA embedded, got\n%s", o.values.Hosted.ExternalHubKubeconfig)
+	}
+
+	applier := apply.NewApplierBuilder().Build()
+	reader := scenario.GetScenarioResourcesReader()
+	out, err := applier.MustTemplateAsset(reader, o.values, "", "init/clustermanager.cr.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"mode: Hosted", "address: registration.example.com", "port: 443", "address: work.example.com", "port: 8443"} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("expected %q in the cluster manager:\n%s", expected, out)
+		}
+	}
+	out, err = applier.MustTemplateAsset(reader, o.values, "", "init/hosted/external_hub_kubeconfig_secret.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "namespace: cluster-manager") || !strings.Contains(string(out), "kubeconfig: ") {
+		t.Errorf("unexpected external hub kubeconfig secret:\n%s", out)
+	}
+}
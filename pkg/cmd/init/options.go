@@ -3,7 +3,11 @@ package init
 
 import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/image"
+	preflightinterface "open-cluster-management.io/clusteradm/pkg/helpers/preflight"
+	"open-cluster-management.io/clusteradm/pkg/helpers/telemetry"
 )
 
 //Options: The structure holding all the command-line options
@@ -11,6 +15,8 @@ type Options struct {
 	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
 	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
 	values          Values
+
+	Streams genericclioptions.IOStreams
 	//The file to output the resources will be sent to the file.
 	outputFile string
 	//If true the bootstrap token will be used instead of the service account token
@@ -27,6 +33,59 @@ type Options struct {
 	wait bool
 	//
 	output string
+	//The file of the image digests, the images are referenced by digests instead of tags
+	imageDigestFile string
+	//Skips the lookup of the images and their architectures in the registry
+	skipImageCheck bool
+	//Verifies the signatures of the images before deploying them
+	verifyImages image.VerifyOptions
+	//Reports the anonymized result of the command if enabled
+	telemetry telemetry.Options
+	//Writes the results of the preflight checks to a file if set
+	report preflightinterface.ReportOptions
+	//The secret holding the CA which signs the webhook serving certificates, in the format of [namespace/]name
+	webhookCertSecret string
+	//If true the CA signing the webhook serving certificates is issued by cert-manager
+	useCertManager bool
+	//The cert-manager ClusterIssuer issuing the CA, a self-signed issuer is created if not set
+	certManagerIssuer string
+	//The namespace of the bootstrap service account
+	bootstrapNamespace string
+	//The labels added to the bootstrap resources, in the format of key=value
+	bootstrapLabels []string
+	//The annotations added to the bootstrap resources, in the format of key=value
+	bootstrapAnnotations []string
+	//If true the cluster manager is installed with its helm chart
+	useHelm bool
+	//The cluster manager chart, a chart of --chart-repo, an oci:// reference or a local path
+	chart string
+	//The repository of the cluster manager chart
+	chartRepo string
+	//The version of the cluster manager chart, the latest one if not set
+	chartVersion string
+	//The values files passed to the chart
+	chartValues []string
+	//The file of the clustersets, placements and addons created after the hub is installed
+	bootstrapProfileFile string
+	//The IP family the managed clusters reach the hub over, ipv4 or ipv6, any if empty
+	preferIPFamily string
+	//The preset sizing the hub for the expected number of managed clusters, small, medium or large
+	size string
+	//The mode of the cluster manager, default or hosted
+	mode string
+	//The kubeconfig of the hub the cluster manager connects to in hosted mode
+	externalHubKubeconfig string
+	//The address of the registration webhook reachable from the hub in hosted mode, in the format of host[:port]
+	registrationWebhookAddress string
+	//The address of the work webhook reachable from the hub in hosted mode, in the format of host[:port]
+	workWebhookAddress string
+
+	//The profile read from bootstrapProfileFile
+	bootstrapProfile *BootstrapProfile
+	//The rest config of the external hub in hosted mode
+	hubRestConfig *rest.Config
+	//What the clusters need to join the hub, set once the hub is initialized
+	result *Result
 }
 
 type BundleVersion struct {
@@ -46,6 +105,62 @@ type Values struct {
 	Hub Hub `json:"hub"`
 	//bundle version
 	BundleVersion BundleVersion
+	//Images: the pull specs of the images
+	Images Images
+	//Architectures: the node architectures the pods are scheduled to, empty means no restriction
+	Architectures []string
+	//SeccompProfile: sets the RuntimeDefault seccomp profile of the pods, false if the cluster rejects it
+	SeccompProfile bool
+	//Webhook: the signer of the webhook serving certificates
+	Webhook Webhook
+	//Size: the sizing of the hub
+	Size Size
+	//Hosted: the cluster manager in Hosted mode
+	Hosted Hosted
+}
+
+//Hosted: The cluster manager running on the management cluster and connecting to an external hub
+type Hosted struct {
+	//Enabled: true in Hosted mode
+	Enabled bool
+	//Namespace: the namespace of the cluster manager on the management cluster, named after the ClusterManager
+	Namespace string
+	//ExternalHubKubeconfig: the kubeconfig of the hub with its credentials embedded
+	ExternalHubKubeconfig string
+	//RegistrationWebhook: the address of the registration webhook reachable from the hub
+	RegistrationWebhook WebhookAddress
+	//WorkWebhook: the address of the work webhook reachable from the hub
+	WorkWebhook WebhookAddress
+}
+
+//WebhookAddress: The address of a webhook server of the management cluster
+type WebhookAddress struct {
+	Address string
+	Port    int32
+}
+
+//Webhook: The signer of the registration and work webhook serving certificates
+type Webhook struct {
+	//Cert: the PEM encoded certificate of the signer
+	Cert string
+	//Key: the PEM encoded private key of the signer
+	Key string
+	//NotBefore: the start of the validity period of the certificate, in RFC3339
+	NotBefore string
+	//NotAfter: the end of the validity period of the certificate, in RFC3339
+	NotAfter string
+	//Issuer: the common name of the issuer of the certificate
+	Issuer string
+	//CertManagerIssuer: the cert-manager ClusterIssuer issuing the signer
+	CertManagerIssuer string
+}
+
+//Images: The pull specs of the images for the template
+type Images struct {
+	Operator     string
+	Registration string
+	Work         string
+	Placement    string
 }
 
 //Hub: The hub values for the template
@@ -56,10 +171,17 @@ type Hub struct {
 	TokenSecret string `json:"tokenSecret"`
 	// Registry is the name of the image registry to pull.
 	Registry string `json:"registry"`
+	//BootstrapNamespace: the namespace of the bootstrap service account
+	BootstrapNamespace string `json:"bootstrapNamespace"`
+	//BootstrapLabels: the labels added to the bootstrap resources
+	BootstrapLabels map[string]string `json:"bootstrapLabels"`
+	//BootstrapAnnotations: the annotations added to the bootstrap resources
+	BootstrapAnnotations map[string]string `json:"bootstrapAnnotations"`
 }
 
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
 	return &Options{
 		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
 	}
 }
@@ -3,6 +3,7 @@ package preflight
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/url"
 
@@ -14,41 +15,57 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"open-cluster-management.io/clusteradm/pkg/helpers/endpoint"
 )
 
 var BootstrapConfigMap = "cluster-info"
 
+// lookupIP resolves the domain names of the API servers, it is replaced in the tests
+var lookupIP = net.LookupIP
+
 type HubApiServerCheck struct {
 	ClusterCtx string // current-context in kubeconfig
 	ConfigPath string // kubeconfig file path
+	Family     string // IP family the managed clusters reach the hub over, any if empty
 }
 
 func checkServer(server string) (warnings []string, errorList []error) {
-	u, err := url.Parse(server)
+	server, err := endpoint.Normalize(server)
 	if err != nil {
 		return nil, []error{err}
 	}
-	host, _, err := net.SplitHostPort(u.Host)
+	u, err := url.Parse(server)
 	if err != nil {
-		missingPortInAddressErr := net.AddrError{Err: "missing port in address", Addr: u.Host}
-		if err.Error() == missingPortInAddressErr.Error() {
-			host = u.Host
-		} else {
-			return nil, []error{err}
-		}
+		return nil, []error{err}
 	}
-	if net.ParseIP(host) == nil {
+	if net.ParseIP(u.Hostname()) == nil {
 		return []string{"Hub Api Server is a domain name, maybe you should set HostAlias in klusterlet"}, nil
 	}
 	return nil, nil
 }
 
+// checkFamily warns if the server has no address of the family
+func checkFamily(server, family string) []string {
+	if len(family) == 0 {
+		return nil
+	}
+	if _, err := endpoint.Addresses(server, family, lookupIP); err != nil {
+		return []string{fmt.Sprintf("the managed clusters may not reach the hub over %s: %v", family, err)}
+	}
+	return nil
+}
+
 func (c HubApiServerCheck) Check() (warnings []string, errorList []error) {
 	cluster, err := loadCurrentCluster(c.ClusterCtx, c.ConfigPath)
 	if err != nil {
 		return nil, []error{err}
 	}
-	return checkServer(cluster.Server)
+	warnings, errorList = checkServer(cluster.Server)
+	if len(errorList) > 0 {
+		return warnings, errorList
+	}
+	return append(warnings, checkFamily(cluster.Server, c.Family)...), nil
 }
 
 func (c HubApiServerCheck) Name() string {
@@ -62,6 +79,7 @@ type ClusterInfoCheck struct {
 	ClusterCtx   string // current-context in kubeconfig
 	ConfigPath   string // kubeconfig file path
 	Client       kubernetes.Interface
+	Family       string // IP family the managed clusters reach the hub over, any if empty
 }
 
 func (c ClusterInfoCheck) Check() (warnings []string, errorList []error) {
@@ -83,7 +101,19 @@ func (c ClusterInfoCheck) Check() (warnings []string, errorList []error) {
 	if len(cm.Data["kubeconfig"]) == 0 {
 		return nil, []error{errors.New("empty kubeconfig data in cluster-info")}
 	}
-	return nil, nil
+	kubeconfig, err := clientcmd.Load([]byte(cm.Data["kubeconfig"]))
+	if err != nil {
+		return nil, []error{errors.Wrap(err, "invalid kubeconfig data in cluster-info")}
+	}
+	// a dual-stack hub may list a server per family
+	for _, cluster := range kubeconfig.Clusters {
+		if _, err := endpoint.Normalize(cluster.Server); err != nil {
+			errorList = append(errorList, errors.Wrap(err, "invalid server in cluster-info"))
+			continue
+		}
+		warnings = append(warnings, checkFamily(cluster.Server, c.Family)...)
+	}
+	return warnings, errorList
 }
 
 func (c ClusterInfoCheck) Name() string {
@@ -128,6 +158,12 @@ func loadCurrentCluster(context string, kubeConfigFilePath string) (*api.Cluster
 
 // createClusterInfo will create a ConfigMap named cluster-info in the kube-public namespace.
 func createClusterInfo(client kubernetes.Interface, cluster *clientcmdapi.Cluster) error {
+	server, err := endpoint.Normalize(cluster.Server)
+	if err != nil {
+		return err
+	}
+	cluster = cluster.DeepCopy()
+	cluster.Server = server
 	kubeconfig := &clientcmdapi.Config{Clusters: map[string]*clientcmdapi.Cluster{"": cluster}}
 	if err := clientcmdapi.FlattenConfig(kubeconfig); err != nil {
 		return err
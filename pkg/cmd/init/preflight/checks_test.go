@@ -2,6 +2,7 @@
 package preflight
 
 import (
+	"net"
 	"reflect"
 	"testing"
 
@@ -88,6 +89,24 @@ func Test_checkServer(t *testing.T) {
 			wantErrList:  nil,
 			wantWarnings: []string{"Hub Api Server is a domain name, maybe you should set HostAlias in klusterlet"},
 		},
+		{
+			name:         "IPv6 address and port",
+			server:       "https://[fd00::1]:6443",
+			wantErrList:  nil,
+			wantWarnings: nil,
+		},
+		{
+			name:         "IPv6 address without port",
+			server:       "https://[fd00::1]",
+			wantErrList:  nil,
+			wantWarnings: nil,
+		},
+		{
+			name:         "unbracketed IPv6 address",
+			server:       "https://fd00::1",
+			wantErrList:  nil,
+			wantWarnings: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -102,6 +121,51 @@ func Test_checkServer(t *testing.T) {
 	}
 }
 
+func Test_checkServerAmbiguousIPv6(t *testing.T) {
+	if _, errorList := checkServer("https://2001:db8::1:6443"); len(errorList) == 0 {
+		t.Errorf("expected an error for the ambiguous IPv6 address")
+	}
+}
+
+func Test_checkFamily(t *testing.T) {
+	defer func(lookup func(string) ([]net.IP, error)) { lookupIP = lookup }(lookupIP)
+	lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("10.0.0.1")}, nil
+	}
+	tests := []struct {
+		name         string
+		server       string
+		family       string
+		wantWarnings []string
+	}{
+		{
+			name:   "any family",
+			server: "https://hub.example.com:6443",
+		},
+		{
+			name:   "ipv4 domain name",
+			server: "https://hub.example.com:6443",
+			family: "ipv4",
+		},
+		{
+			name:         "no ipv6 address",
+			server:       "https://hub.example.com:6443",
+			family:       "ipv6",
+			wantWarnings: []string{"the managed clusters may not reach the hub over ipv6: hub.example.com has no ipv6 address"},
+		},
+		{
+			name:   "ipv6 address",
+			server: "https://[fd00::1]:6443",
+			family: "ipv6",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testinghelper.AssertWarnings(t, checkFamily(tt.server, tt.family), tt.wantWarnings)
+		})
+	}
+}
+
 func Test_createClusterInfo(t *testing.T) {
 	type args struct {
 		cluster *clientcmdapi.Cluster
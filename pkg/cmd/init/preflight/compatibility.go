@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	"open-cluster-management.io/clusteradm/pkg/helpers/version"
+)
+
+// ClusterManagerVersionCheck verifies the hub of Version supports the agents joined to the installed
+// cluster manager, which run the version of the installed cluster manager.
+type ClusterManagerVersionCheck struct {
+	OperatorClient operatorclient.Interface
+	Version        string
+	// Force reports the incompatibility as a warning
+	Force bool
+}
+
+func (c ClusterManagerVersionCheck) Check() (warnings []string, errorList []error) {
+	cm, err := c.OperatorClient.OperatorV1().ClusterManagers().Get(context.TODO(), config.ClusterManagerName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return []string{fmt.Sprintf("failed to get the installed cluster manager: %v", err)}, nil
+	}
+
+	installed := version.ImageVersion(cm.Spec.RegistrationImagePullSpec)
+	if err := version.CheckSkew(c.Version, installed); err != nil {
+		err = fmt.Errorf("the hub %s does not support the agents joined to the installed hub %s: %v", c.Version, installed, err)
+		if c.Force {
+			return []string{err.Error()}, nil
+		}
+		return nil, []error{err}
+	}
+	return nil, nil
+}
+
+func (c ClusterManagerVersionCheck) Name() string {
+	return "ClusterManagerVersion check"
+}
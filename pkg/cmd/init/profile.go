@@ -0,0 +1,371 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// globalClusterSet selects all the managed clusters
+	globalClusterSet = "global"
+	// defaultPlacement is the name of the placement created in the team namespaces
+	defaultPlacement = "default"
+
+	installStrategyManual     = "Manual"
+	installStrategyPlacements = "Placements"
+)
+
+var clusterManagementAddOnGVR = schema.GroupVersionResource{
+	Group:    "addon.open-cluster-management.io",
+	Version:  "v1alpha1",
+	Resource: "clustermanagementaddons",
+}
+
+// BootstrapProfile is the organizational setup of the hub created after its installation
+type BootstrapProfile struct {
+	// GlobalBindings are the namespaces the global clusterset is bound to
+	GlobalBindings []string `json:"globalBindings,omitempty"`
+	// Teams get a placement of the clusters of their clustersets in their namespace
+	Teams []TeamProfile `json:"teams,omitempty"`
+	// Addons are the built-in hub addons installed with their install strategy
+	Addons []AddonProfile `json:"addons,omitempty"`
+}
+
+// TeamProfile is the namespace of a team
+type TeamProfile struct {
+	Namespace string `json:"namespace"`
+	// ClusterSets are bound to the namespace and selected by the placement, the global clusterset if empty
+	ClusterSets []string `json:"clusterSets,omitempty"`
+	// Placement is the name of the placement, default if empty
+	Placement string `json:"placement,omitempty"`
+}
+
+// AddonProfile is a built-in hub addon, installed as `clusteradm install hub-addon` does
+type AddonProfile struct {
+	Name string `json:"name"`
+	// InstallStrategy is Manual or Placements, the addon is installed on the clusters selected by
+	// the placements with Placements
+	InstallStrategy string         `json:"installStrategy,omitempty"`
+	Placements      []PlacementRef `json:"placements,omitempty"`
+}
+
+// PlacementRef is a placement selecting the clusters an addon is installed on
+type PlacementRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// loadBootstrapProfile reads the profile file and fills in the defaults
+func loadBootstrapProfile(path string) (*BootstrapProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profile := &BootstrapProfile{}
+	if err := yaml.UnmarshalStrict(data, profile); err != nil {
+		return nil, fmt.Errorf("failed to parse the bootstrap profile %s: %v", path, err)
+	}
+	for i := range profile.Teams {
+		team := &profile.Teams[i]
+		if len(team.Namespace) == 0 {
+			return nil, fmt.Errorf("the namespace of team %d is missing", i)
+		}
+		if len(team.ClusterSets) == 0 {
+			team.ClusterSets = []string{globalClusterSet}
+		}
+		if len(team.Placement) == 0 {
+			team.Placement = defaultPlacement
+		}
+	}
+	for i := range profile.Addons {
+		addon := &profile.Addons[i]
+		if len(addon.Name) == 0 {
+			return nil, fmt.Errorf("the name of addon %d is missing", i)
+		}
+		switch addon.InstallStrategy {
+		case "", installStrategyManual:
+			addon.InstallStrategy = installStrategyManual
+			if len(addon.Placements) > 0 {
+				return nil, fmt.Errorf("the placements of addon %s are only supported with the %s install strategy", addon.Name, installStrategyPlacements)
+			}
+		case installStrategyPlacements:
+			if len(addon.Placements) == 0 {
+				return nil, fmt.Errorf("the placements of addon %s are missing", addon.Name)
+			}
+		default:
+			return nil, fmt.Errorf("the install strategy of addon %s should be %s or %s", addon.Name, installStrategyManual, installStrategyPlacements)
+		}
+	}
+	return profile, nil
+}
+
+// clusterSets returns the clustersets used by the profile
+func (p *BootstrapProfile) clusterSets() []string {
+	clusterSets := []string{}
+	seen := map[string]bool{}
+	add := func(clusterSet string) {
+		if !seen[clusterSet] {
+			seen[clusterSet] = true
+			clusterSets = append(clusterSets, clusterSet)
+		}
+	}
+	if len(p.GlobalBindings) > 0 {
+		add(globalClusterSet)
+	}
+	for _, team := range p.Teams {
+		for _, clusterSet := range team.ClusterSets {
+			add(clusterSet)
+		}
+	}
+	return clusterSets
+}
+
+// applyBootstrapProfile creates the clustersets, bindings and placements of the profile and installs its addons
+func (o *Options) applyBootstrapProfile(ctx context.Context, kubeClient kubernetes.Interface, apiExtensionsClient apiextensionsclient.Interface,
+	dynamicClient dynamic.Interface) error {
+	profile := o.bootstrapProfile
+	if o.ClusteradmFlags.DryRun {
+		for _, clusterSet := range profile.clusterSets() {
+			fmt.Fprintf(o.Streams.ErrOut, "clusterset %s would be created\n", clusterSet)
+		}
+		for _, namespace := range profile.GlobalBindings {
+			fmt.Fprintf(o.Streams.ErrOut, "clusterset %s would be bound to namespace %s\n", globalClusterSet, namespace)
+		}
+		for _, team := range profile.Teams {
+			fmt.Fprintf(o.Streams.ErrOut, "clustersets %s would be bound to namespace %s and selected by placement %s\n",
+				strings.Join(team.ClusterSets, ","), team.Namespace, team.Placement)
+		}
+		for _, addon := range profile.Addons {
+			fmt.Fprintf(o.Streams.ErrOut, "addon %s would be installed with the %s install strategy\n", addon.Name, addon.InstallStrategy)
+		}
+		return nil
+	}
+
+	if err := waitForCRDs(ctx, apiExtensionsClient, time.Duration(o.ClusteradmFlags.Timeout)*time.Second,
+		"managedclustersets.cluster.open-cluster-management.io",
+		"managedclustersetbindings.cluster.open-cluster-management.io",
+		"placements.cluster.open-cluster-management.io",
+	); err != nil {
+		return err
+	}
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, clusterSet := range profile.clusterSets() {
+		if err := createClusterSet(ctx, o.Streams.ErrOut, clusterClient, clusterSet); err != nil {
+			return err
+		}
+	}
+	for _, namespace := range profile.GlobalBindings {
+		if err := bindClusterSet(ctx, o.Streams.ErrOut, kubeClient, clusterClient, globalClusterSet, namespace); err != nil {
+			return err
+		}
+	}
+	for _, team := range profile.Teams {
+		for _, clusterSet := range team.ClusterSets {
+			if err := bindClusterSet(ctx, o.Streams.ErrOut, kubeClient, clusterClient, clusterSet, team.Namespace); err != nil {
+				return err
+			}
+		}
+		if err := createPlacement(ctx, o.Streams.ErrOut, clusterClient, team); err != nil {
+			return err
+		}
+	}
+
+	if len(profile.Addons) == 0 {
+		return nil
+	}
+	names := []string{}
+	for _, addon := range profile.Addons {
+		names = append(names, addon.Name)
+	}
+	if err := o.installHubAddons(ctx, names); err != nil {
+		return err
+	}
+	for _, addon := range profile.Addons {
+		if err := setInstallStrategy(ctx, o.Streams.ErrOut, dynamicClient, addon); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForCRDs waits until the CRDs installed by the cluster manager are established
+func waitForCRDs(ctx context.Context, apiExtensionsClient apiextensionsclient.Interface, timeout time.Duration, names ...string) error {
+	for _, name := range names {
+		err := wait.PollImmediateWithContext(ctx, 2*time.Second, timeout, func(ctx context.Context) (bool, error) {
+			crd, err := apiExtensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			for _, condition := range crd.Status.Conditions {
+				if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			return fmt.Errorf("the CRD %s of the cluster manager is not ready: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func createClusterSet(ctx context.Context, out io.Writer, clusterClient clusterclientset.Interface, name string) error {
+	clusterSet := &clusterv1beta1.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	// the global clusterset selects all the clusters
+	if name == globalClusterSet {
+		clusterSet.Spec.ClusterSelector = clusterv1beta1.ManagedClusterSelector{
+			SelectorType:  clusterv1beta1.LabelSelector,
+			LabelSelector: &metav1.LabelSelector{},
+		}
+	}
+	_, err := clusterClient.ClusterV1beta1().ManagedClusterSets().Create(ctx, clusterSet, metav1.CreateOptions{})
+	switch {
+	case errors.IsAlreadyExists(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	fmt.Fprintf(out, "clusterset %s is created\n", name)
+	return nil
+}
+
+func bindClusterSet(ctx context.Context, out io.Writer, kubeClient kubernetes.Interface, clusterClient clusterclientset.Interface, clusterSet, namespace string) error {
+	_, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	_, err = clusterClient.ClusterV1beta1().ManagedClusterSetBindings(namespace).Create(ctx, &clusterv1beta1.ManagedClusterSetBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterSet,
+			Namespace: namespace,
+		},
+		Spec: clusterv1beta1.ManagedClusterSetBindingSpec{
+			ClusterSet: clusterSet,
+		},
+	}, metav1.CreateOptions{})
+	switch {
+	case errors.IsAlreadyExists(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	fmt.Fprintf(out, "clusterset %s is bound to namespace %s\n", clusterSet, namespace)
+	return nil
+}
+
+func createPlacement(ctx context.Context, out io.Writer, clusterClient clusterclientset.Interface, team TeamProfile) error {
+	_, err := clusterClient.ClusterV1beta1().Placements(team.Namespace).Create(ctx, &clusterv1beta1.Placement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      team.Placement,
+			Namespace: team.Namespace,
+		},
+		Spec: clusterv1beta1.PlacementSpec{
+			ClusterSets: team.ClusterSets,
+		},
+	}, metav1.CreateOptions{})
+	switch {
+	case errors.IsAlreadyExists(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	fmt.Fprintf(out, "placement %s/%s is created\n", team.Namespace, team.Placement)
+	return nil
+}
+
+// installHubAddons installs the built-in addons with the install hub-addon command of the running binary
+func (o *Options) installHubAddons(ctx context.Context, names []string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"install", "hub-addon", "--names", strings.Join(names, ",")}
+	if kubeconfig := o.ClusteradmFlags.KubectlFactory.ToRawKubeConfigLoader().ConfigAccess().GetExplicitFile(); len(kubeconfig) > 0 {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+	if len(o.ClusteradmFlags.Context) > 0 {
+		args = append(args, "--context", o.ClusteradmFlags.Context)
+	}
+
+	klog.V(1).InfoS("running:", "command", self, "args", args)
+	c := exec.CommandContext(ctx, self, args...)
+	c.Stdout = o.Streams.ErrOut
+	c.Stderr = o.Streams.ErrOut
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to install the addons %s: %v", strings.Join(names, ","), err)
+	}
+	return nil
+}
+
+// setInstallStrategy patches the install strategy of the addon, the strategy is pruned by the
+// cluster managers not supporting it, a warning is printed in that case.
+func setInstallStrategy(ctx context.Context, out io.Writer, dynamicClient dynamic.Interface, addon AddonProfile) error {
+	placements := []interface{}{}
+	for _, placement := range addon.Placements {
+		placements = append(placements, map[string]interface{}{
+			"namespace": placement.Namespace,
+			"name":      placement.Name,
+		})
+	}
+	patch := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"installStrategy": map[string]interface{}{
+				"type":       addon.InstallStrategy,
+				"placements": placements,
+			},
+		},
+	}}
+	data, err := patch.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	updated, err := dynamicClient.Resource(clusterManagementAddOnGVR).Patch(ctx, addon.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to set the install strategy of addon %s: %v", addon.Name, err)
+	}
+	if strategy, _, _ := unstructured.NestedString(updated.Object, "spec", "installStrategy", "type"); strategy != addon.InstallStrategy {
+		fmt.Fprintf(out, "WARNING: the cluster manager does not support the install strategy of addon %s, "+
+			"enable it with `clusteradm addon enable`\n", addon.Name)
+		return nil
+	}
+	fmt.Fprintf(out, "addon %s is installed with the %s install strategy\n", addon.Name, addon.InstallStrategy)
+	return nil
+}
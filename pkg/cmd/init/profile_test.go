@@ -0,0 +1,110 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadBootstrapProfile(t *testing.T) {
+	cases := []struct {
+		name        string
+		profile     string
+		expectedErr bool
+		expected    *BootstrapProfile
+	}{
+		{
+			name: "defaults",
+			profile: `
+globalBindings:
+- ops
+teams:
+- namespace: team-a
+- namespace: team-b
+  clusterSets: [dev]
+  placement: dev-clusters
+addons:
+- name: application-manager
+- name: governance-policy-framework
+  installStrategy: Placements
+  placements:
+  - namespace: team-a
+    name: default
+`,
+			expected: &BootstrapProfile{
+				GlobalBindings: []string{"ops"},
+				Teams: []TeamProfile{
+					{Namespace: "team-a", ClusterSets: []string{globalClusterSet}, Placement: defaultPlacement},
+					{Namespace: "team-b", ClusterSets: []string{"dev"}, Placement: "dev-clusters"},
+				},
+				Addons: []AddonProfile{
+					{Name: "application-manager", InstallStrategy: installStrategyManual},
+					{Name: "governance-policy-framework", InstallStrategy: installStrategyPlacements,
+						Placements: []PlacementRef{{Namespace: "team-a", Name: "default"}}},
+				},
+			},
+		},
+		{
+			name:        "unknown field",
+			profile:     "team: []",
+			expectedErr: true,
+		},
+		{
+			name:        "team without namespace",
+			profile:     "teams:\n- placement: default",
+			expectedErr: true,
+		},
+		{
+			name:        "placements without strategy",
+			profile:     "addons:\n- name: application-manager\n  placements:\n  - namespace: team-a\n    name: default",
+			expectedErr: true,
+		},
+		{
+			name:        "placements strategy without placements",
+			profile:     "addons:\n- name: application-manager\n  installStrategy: Placements",
+			expectedErr: true,
+		},
+		{
+			name:        "unknown strategy",
+			profile:     "addons:\n- name: application-manager\n  installStrategy: All",
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profile.yaml")
+			if err := os.WriteFile(path, []byte(c.profile), 0600); err != nil {
+				t.Fatal(err)
+			}
+			profile, err := loadBootstrapProfile(path)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(profile, c.expected) {
+				t.Errorf("expected %+v, got %+v", c.expected, profile)
+			}
+		})
+	}
+}
+
+func TestBootstrapProfileClusterSets(t *testing.T) {
+	profile := &BootstrapProfile{
+		GlobalBindings: []string{"ops"},
+		Teams: []TeamProfile{
+			{Namespace: "team-a", ClusterSets: []string{"dev", globalClusterSet}},
+			{Namespace: "team-b", ClusterSets: []string{"dev", "prod"}},
+		},
+	}
+	expected := []string{globalClusterSet, "dev", "prod"}
+	if got := profile.clusterSets(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"github.com/stolostron/applier/pkg/apply"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"open-cluster-management.io/clusteradm/pkg/cmd/init/scenario"
+	"open-cluster-management.io/clusteradm/pkg/helpers/image"
+	version "open-cluster-management.io/clusteradm/pkg/helpers/version"
+	"sigs.k8s.io/yaml"
+)
+
+// hubTemplates are the templates of the cluster manager and its operator, the bootstrap resources and the
+// CRD are not part of them
+var hubTemplates = []string{
+	"init/clustermanager_cluster_role.yaml",
+	"init/clustermanager_cluster_role_binding.yaml",
+	"init/clustermanager_sa.yaml",
+	"init/operator.yaml",
+	"init/clustermanager.cr.yaml",
+}
+
+// HubValues returns the values init renders the cluster manager of the bundle version with, the images are
+// pulled from the registry
+func HubValues(bundleVersion, registry, size string) (Values, error) {
+	values := Values{Hub: Hub{Registry: registry}}
+	versionBundle, err := version.GetVersionBundle(bundleVersion)
+	if err != nil {
+		return values, err
+	}
+	values.BundleVersion = BundleVersion{
+		RegistrationImageVersion: versionBundle.Registration,
+		PlacementImageVersion:    versionBundle.Placement,
+		WorkImageVersion:         versionBundle.Work,
+		OperatorImageVersion:     versionBundle.Operator,
+	}
+	values.Images = Images{
+		Operator:     image.PullSpec(registry, image.OperatorImageName, versionBundle.Registration, image.Digests{}),
+		Registration: image.PullSpec(registry, image.RegistrationImageName, versionBundle.Registration, image.Digests{}),
+		Work:         image.PullSpec(registry, image.WorkImageName, versionBundle.Work, image.Digests{}),
+		Placement:    image.PullSpec(registry, image.PlacementImageName, versionBundle.Placement, image.Digests{}),
+	}
+	if values.Size, err = getSize(size); err != nil {
+		return values, err
+	}
+	return values, nil
+}
+
+// RenderHub returns the objects of the cluster manager and its operator rendered with the values
+func RenderHub(values Values) ([]*unstructured.Unstructured, error) {
+	reader := scenario.GetScenarioResourcesReader()
+	applier := apply.NewApplierBuilder().Build()
+	objs := []*unstructured.Unstructured{}
+	for _, name := range hubTemplates {
+		data, err := applier.MustTemplateAsset(reader, values, "", name)
+		if err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
@@ -0,0 +1,44 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Size is the sizing of the hub for the expected number of managed clusters
+type Size struct {
+	//OperatorReplicas: the replicas of the cluster manager operator, one is the leader
+	OperatorReplicas int32
+	//OperatorCPU: the cpu request of the cluster manager operator
+	OperatorCPU string
+	//OperatorMemory: the memory request of the cluster manager operator
+	OperatorMemory string
+}
+
+// sizes are the presets of --size, small keeps the defaults of the cluster manager
+var sizes = map[string]Size{
+	// up to 100 managed clusters
+	"small": {OperatorReplicas: 1, OperatorCPU: "100m", OperatorMemory: "128Mi"},
+	// up to 1000 managed clusters
+	"medium": {OperatorReplicas: 2, OperatorCPU: "200m", OperatorMemory: "256Mi"},
+	// above 1000 managed clusters
+	"large": {OperatorReplicas: 3, OperatorCPU: "500m", OperatorMemory: "512Mi"},
+}
+
+// defaultSize is the size of the hub if --size is not set
+const defaultSize = "small"
+
+func getSize(name string) (Size, error) {
+	size, ok := sizes[name]
+	if !ok {
+		names := []string{}
+		for name := range sizes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return size, fmt.Errorf("invalid --size %q, should be one of %s", name, strings.Join(names, ", "))
+	}
+	return size, nil
+}
@@ -0,0 +1,41 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stolostron/applier/pkg/apply"
+	"open-cluster-management.io/clusteradm/pkg/cmd/init/scenario"
+)
+
+func TestGetSize(t *testing.T) {
+	if _, err := getSize("huge"); err == nil || !strings.Contains(err.Error(), "large, medium, small") {
+		t.Errorf("expected the sizes to be listed, got %v", err)
+	}
+	size, err := getSize(defaultSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size.OperatorReplicas != 1 || size.OperatorCPU != "100m" || size.OperatorMemory != "128Mi" {
+		t.Errorf("expected the default size to keep the defaults, got %v", size)
+	}
+}
+
+func TestSizeOfOperator(t *testing.T) {
+	size, err := getSize("large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := Values{Size: size, Images: Images{Operator: "quay.io/open-cluster-management/registration-operator:latest"}}
+	applier := apply.NewApplierBuilder().Build()
+	out, err := applier.MustTemplateAsset(scenario.GetScenarioResourcesReader(), values, "", "init/operator.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"replicas: 3", "cpu: 500m", "memory: 512Mi"} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("expected %q in the operator:\n%s", expected, out)
+		}
+	}
+}
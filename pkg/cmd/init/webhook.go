@@ -0,0 +1,178 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stolostron/applier/pkg/apply"
+	"github.com/stolostron/applier/pkg/asset"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	webhookCertSecretDefaultNamespace = "open-cluster-management"
+	hubNamespace                      = "open-cluster-management-hub"
+	certManagerCASecret               = "cluster-manager-webhook-ca"
+	certManagerCRD                    = "certificates.cert-manager.io"
+	// certManagerRenewBefore is the renewBefore of the cert-manager CA certificate
+	certManagerRenewBefore = 720 * time.Hour
+)
+
+// parseWebhookCertSecret returns the namespace and the name of the secret in the format of [namespace/]name
+func parseWebhookCertSecret(secret string) (string, string, error) {
+	parts := strings.Split(secret, "/")
+	switch {
+	case len(parts) == 1 && len(parts[0]) > 0:
+		return webhookCertSecretDefaultNamespace, parts[0], nil
+	case len(parts) == 2 && len(parts[0]) > 0 && len(parts[1]) > 0:
+		return parts[0], parts[1], nil
+	}
+	return "", "", fmt.Errorf("invalid --webhook-cert-secret %q, should be in the format of [namespace/]name", secret)
+}
+
+// applyWebhookSigner provisions the signer of the registration and work webhook serving
+// certificates, the cluster-manager operator reuses an existing signer instead of
+// generating a self-signed one.
+func (o *Options) applyWebhookSigner(ctx context.Context, kubeClient kubernetes.Interface, applier apply.Applier, reader asset.ScenarioReader) ([]string, error) {
+	output := make([]string, 0)
+	if len(o.webhookCertSecret) == 0 && !o.useCertManager {
+		return output, nil
+	}
+
+	out, err := applier.ApplyDirectly(reader, o.values, o.ClusteradmFlags.DryRun, "", "init/namespace_hub.yaml")
+	if err != nil {
+		return output, err
+	}
+	output = append(output, out...)
+
+	namespace, name := hubNamespace, certManagerCASecret
+	if o.useCertManager {
+		files := []string{}
+		if len(o.certManagerIssuer) == 0 {
+			files = append(files, "init/webhook_cert-manager_issuer.yaml")
+		}
+		files = append(files, "init/webhook_cert-manager_certificate.yaml")
+		out, err := applier.ApplyCustomResources(reader, o.values, o.ClusteradmFlags.DryRun, "", files...)
+		if err != nil {
+			return output, err
+		}
+		output = append(output, out...)
+		if o.ClusteradmFlags.DryRun {
+			return output, nil
+		}
+	} else {
+		if namespace, name, err = parseWebhookCertSecret(o.webhookCertSecret); err != nil {
+			return output, err
+		}
+	}
+
+	secret, err := o.getWebhookCASecret(ctx, kubeClient, namespace, name)
+	if err != nil {
+		return output, err
+	}
+	if err := o.setWebhookValues(secret); err != nil {
+		return output, err
+	}
+
+	out, err = applier.ApplyDirectly(reader, o.values, o.ClusteradmFlags.DryRun, "", "init/webhook_signer_secret.yaml")
+	if err != nil {
+		return output, err
+	}
+	output = append(output, out...)
+
+	if o.useCertManager {
+		renewal, err := certManagerRenewal(o.values.Webhook.NotAfter)
+		if err != nil {
+			return output, err
+		}
+		fmt.Fprintf(o.Streams.ErrOut, "The webhook CA %s/%s is renewed by cert-manager at %s, "+
+			"re-run clusteradm init --use-cert-manager once renewed to rotate the signer\n",
+			namespace, name, renewal.Format(time.RFC3339))
+	}
+	return output, nil
+}
+
+// certManagerRenewal returns the time cert-manager renews the CA expiring at notAfter,
+// the signer secret is a copy of the CA so it is not rotated by the renewal.
+func certManagerRenewal(notAfter string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, notAfter)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiration %q of the webhook CA: %v", notAfter, err)
+	}
+	return t.Add(-certManagerRenewBefore), nil
+}
+
+// getWebhookCASecret gets the secret of the CA, the secret issued by cert-manager
+// is waited for until the timeout.
+func (o *Options) getWebhookCASecret(ctx context.Context, kubeClient kubernetes.Interface, namespace, name string) (*corev1.Secret, error) {
+	if !o.useCertManager {
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the webhook CA secret %s/%s: %v", namespace, name, err)
+		}
+		return secret, nil
+	}
+
+	var secret *corev1.Secret
+	err := wait.PollImmediateWithContext(ctx, time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func(ctx context.Context) (bool, error) {
+		s, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if len(s.Data[corev1.TLSCertKey]) == 0 || len(s.Data[corev1.TLSPrivateKeyKey]) == 0 {
+			return false, nil
+		}
+		secret = s
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for the webhook CA secret %s/%s issued by cert-manager: %v", namespace, name, err)
+	}
+	return secret, nil
+}
+
+// setWebhookValues fills the webhook values of the template with the CA in the secret
+func (o *Options) setWebhookValues(secret *corev1.Secret) error {
+	certData := secret.Data[corev1.TLSCertKey]
+	keyData := secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certData) == 0 || len(keyData) == 0 {
+		return fmt.Errorf("the webhook CA secret %s/%s should contain %s and %s",
+			secret.Namespace, secret.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+
+	block, _ := pem.Decode(certData)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("the %s of the webhook CA secret %s/%s is not a PEM encoded certificate",
+			corev1.TLSCertKey, secret.Namespace, secret.Name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse the certificate of the webhook CA secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	if !cert.IsCA {
+		return fmt.Errorf("the certificate of the webhook CA secret %s/%s is not a CA", secret.Namespace, secret.Name)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return fmt.Errorf("the certificate of the webhook CA secret %s/%s expired at %s",
+			secret.Namespace, secret.Name, cert.NotAfter.Format(time.RFC3339))
+	}
+
+	o.values.Webhook.Cert = string(certData)
+	o.values.Webhook.Key = string(keyData)
+	o.values.Webhook.NotBefore = cert.NotBefore.Format(time.RFC3339)
+	o.values.Webhook.NotAfter = cert.NotAfter.Format(time.RFC3339)
+	o.values.Webhook.Issuer = cert.Issuer.CommonName
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseWebhookCertSecret(t *testing.T) {
+	cases := []struct {
+		secret            string
+		expectedNamespace string
+		expectedName      string
+		expectErr         bool
+	}{
+		{secret: "ca", expectedNamespace: webhookCertSecretDefaultNamespace, expectedName: "ca"},
+		{secret: "ns/ca", expectedNamespace: "ns", expectedName: "ca"},
+		{secret: "ns/", expectErr: true},
+		{secret: "a/b/c", expectErr: true},
+	}
+	for _, c := range cases {
+		namespace, name, err := parseWebhookCertSecret(c.secret)
+		if c.expectErr != (err != nil) {
+			t.Errorf("%s: expect error %v, got %v", c.secret, c.expectErr, err)
+			continue
+		}
+		if namespace != c.expectedNamespace || name != c.expectedName {
+			t.Errorf("%s: expect %s/%s, got %s/%s", c.secret, c.expectedNamespace, c.expectedName, namespace, name)
+		}
+	}
+}
+
+func newTestCertSecret(t *testing.T, isCA bool) *corev1.Secret {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "webhook-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ca"},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+	}
+}
+
+func TestSetWebhookValues(t *testing.T) {
+	cases := []struct {
+		name      string
+		secret    *corev1.Secret
+		expectErr bool
+	}{
+		{name: "ca", secret: newTestCertSecret(t, true)},
+		{name: "not a ca", secret: newTestCertSecret(t, false), expectErr: true},
+		{name: "missing key", secret: &corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: []byte("cert")}}, expectErr: true},
+		{name: "invalid cert", secret: &corev1.Secret{Data: map[string][]byte{
+			corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")}}, expectErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &Options{}
+			err := o.setWebhookValues(c.secret)
+			if c.expectErr != (err != nil) {
+				t.Fatalf("expect error %v, got %v", c.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if o.values.Webhook.Issuer != "webhook-ca" || len(o.values.Webhook.NotAfter) == 0 || len(o.values.Webhook.Key) == 0 {
+				t.Errorf("unexpected webhook values %+v", o.values.Webhook)
+			}
+		})
+	}
+}
+
+func TestCertManagerRenewal(t *testing.T) {
+	cases := []struct {
+		notAfter  string
+		expected  string
+		expectErr bool
+	}{
+		{notAfter: "2027-01-31T00:00:00Z", expected: "2027-01-01T00:00:00Z"},
+		{notAfter: "", expectErr: true},
+	}
+	for _, c := range cases {
+		renewal, err := certManagerRenewal(c.notAfter)
+		if c.expectErr != (err != nil) {
+			t.Errorf("%q: expect error %v, got %v", c.notAfter, c.expectErr, err)
+			continue
+		}
+		if err == nil && renewal.Format(time.RFC3339) != c.expected {
+			t.Errorf("%q: expect %s, got %s", c.notAfter, c.expected, renewal.Format(time.RFC3339))
+		}
+	}
+}
@@ -15,6 +15,9 @@ var example = `
 # Install built-in add-ons to the hub cluster
 %[1]s install hub-addon --names application-manager
 %[1]s install hub-addon --names governance-policy-framework
+
+# Install the observability add-on once the MultiClusterObservability CRD of the operator release is applied
+%[1]s install hub-addon --names multicluster-observability --storage-config s3.yaml
 `
 
 // NewCmd...
@@ -47,10 +50,11 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 	}
 
-	cmd.Flags().StringVar(&o.names, "names", "", "Names of the built-in add-on to install (comma separated). The built-in add-ons are: application-manager, governance-policy-framework")
+	cmd.Flags().StringVar(&o.names, "names", "", "Names of the built-in add-on to install (comma separated). The built-in add-ons are: application-manager, governance-policy-framework, multicluster-observability")
 	cmd.Flags().StringVar(&o.values.Namespace, "namespace", "open-cluster-management", "Namespace of the built-in add-on to install. Defaults to open-cluster-management")
 	cmd.Flags().StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
 	cmd.Flags().StringVar(&o.bundleVersion, "bundle-version", "default", "The image version tag to use when deploying the hub add-on")
+	cmd.Flags().StringVar(&o.storageConfig, "storage-config", "", "The thanos object storage configuration file used by the multicluster-observability add-on to store the metrics")
 
 	return cmd
 }
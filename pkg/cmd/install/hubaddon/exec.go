@@ -2,7 +2,9 @@
 package hubaddon
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -10,18 +12,39 @@ import (
 
 	"github.com/stolostron/applier/pkg/apply"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"open-cluster-management.io/clusteradm/pkg/cmd/install/hubaddon/preflight"
 	"open-cluster-management.io/clusteradm/pkg/cmd/install/hubaddon/scenario"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	preflightinterface "open-cluster-management.io/clusteradm/pkg/helpers/preflight"
 	"open-cluster-management.io/clusteradm/pkg/helpers/version"
+	"open-cluster-management.io/clusteradm/pkg/helpers/wait"
 )
 
 const (
 	appMgrAddonName          = "application-manager"
 	policyFrameworkAddonName = "governance-policy-framework"
+	observabilityAddonName   = "multicluster-observability"
+
+	observabilityNamespace = "open-cluster-management-observability"
+	// observabilityCRD is installed with the upstream manifests of the observability operator, its schema
+	// follows the operator release
+	observabilityCRD = "multiclusterobservabilities.observability.open-cluster-management.io"
 )
 
+// observabilityCRDSource returns the upstream location of the MultiClusterObservability CRD of the operator release
+func observabilityCRDSource(release string) string {
+	branch := "main"
+	if parts := strings.Split(release, "."); len(parts) >= 2 {
+		branch = fmt.Sprintf("release-%s.%s", parts[0], parts[1])
+	}
+	return fmt.Sprintf("https://github.com/stolostron/multicluster-observability-operator/blob/%s/operators/multiclusterobservability/"+
+		"config/crd/bases/observability.open-cluster-management.io_multiclusterobservabilities.yaml", branch)
+}
+
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 	klog.V(1).InfoS("addon options:", "dry-run", o.ClusteradmFlags.DryRun, "names", o.names, "output-file", o.outputFile)
 
@@ -45,6 +68,10 @@ func (o *Options) validate() (err error) {
 			continue
 		case policyFrameworkAddonName:
 			continue
+		case observabilityAddonName:
+			if err := o.validateStorageConfig(); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("invalid add-on name %s", n)
 		}
@@ -58,10 +85,35 @@ func (o *Options) validate() (err error) {
 	}
 
 	o.values.BundleVersion = BundleVersion{
-		AppAddon:    versionBundle.AppAddon,
-		PolicyAddon: versionBundle.PolicyAddon,
+		AppAddon:           versionBundle.AppAddon,
+		PolicyAddon:        versionBundle.PolicyAddon,
+		ObservabilityAddon: versionBundle.ObservabilityAddon,
+	}
+
+	return nil
+}
+
+// validateStorageConfig reads the object storage configuration of the observability
+// add-on and runs the preflight checks on it
+func (o *Options) validateStorageConfig() error {
+	if len(o.storageConfig) == 0 {
+		return fmt.Errorf("--storage-config is required by the %s add-on", observabilityAddonName)
+	}
+	config, err := os.ReadFile(o.storageConfig)
+	if err != nil {
+		return err
+	}
+	if err := preflightinterface.RunChecks(
+		[]preflightinterface.Checker{
+			preflight.ObjectStorageCheck{Config: config},
+		}, os.Stderr); err != nil {
+		return err
 	}
 
+	o.values.Observability = ObservabilityValues{
+		Namespace:     observabilityNamespace,
+		StorageConfig: string(config),
+	}
 	return nil
 }
 
@@ -177,6 +229,52 @@ func (o *Options) runWithClient(kubeClient kubernetes.Interface,
 			output = append(output, out...)
 
 			fmt.Printf("Installing built-in %s add-on to the Hub cluster...\n", policyFrameworkAddonName)
+			fmt.Printf("Use \"clusteradm get policies\" to view the compliance of the policies on the managed clusters.\n")
+
+		// Install the Observability Addon
+		case observabilityAddonName:
+			_, err := apiExtensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), observabilityCRD, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				return fmt.Errorf("the CRD %s is not installed, apply the CRD of the observability operator %s first: %s",
+					observabilityCRD, o.values.BundleVersion.ObservabilityAddon, observabilityCRDSource(o.values.BundleVersion.ObservabilityAddon))
+			}
+			if err != nil {
+				return err
+			}
+
+			files := []string{
+				"addon/observability/namespace.yaml",
+				"addon/observability/object-storage_secret.yaml",
+				"addon/observability/operator_serviceaccount.yaml",
+				"addon/observability/operator_clusterrole.yaml",
+				"addon/observability/operator_clusterrolebinding.yaml",
+			}
+
+			out, err := applier.ApplyDirectly(reader, o.values, dryRun, "", files...)
+			if err != nil {
+				return err
+			}
+			output = append(output, out...)
+
+			out, err = applier.ApplyDeployments(reader, o.values, dryRun, "", "addon/observability/operator_deployment.yaml")
+			if err != nil {
+				return err
+			}
+			output = append(output, out...)
+
+			if !dryRun {
+				if err := wait.WaitUntilCRDReady(apiExtensionsClient, observabilityCRD, false); err != nil {
+					return err
+				}
+			}
+
+			out, err = applier.ApplyCustomResources(reader, o.values, dryRun, "", "addon/observability/multiclusterobservability_cr.yaml")
+			if err != nil {
+				return err
+			}
+			output = append(output, out...)
+
+			fmt.Printf("Installing built-in %s add-on to the Hub cluster...\n", observabilityAddonName)
 		}
 	}
 
@@ -15,6 +15,8 @@ type Options struct {
 	outputFile    string
 	values        Values
 	bundleVersion string
+	//The thanos object storage configuration file of the observability add-on
+	storageConfig string
 }
 
 type BundleVersion struct {
@@ -22,6 +24,16 @@ type BundleVersion struct {
 	AppAddon string
 	// policy image version
 	PolicyAddon string
+	// observability image version
+	ObservabilityAddon string
+}
+
+// ObservabilityValues: The values of the observability add-on
+type ObservabilityValues struct {
+	// Namespace of the observability components
+	Namespace string
+	// The thanos object storage configuration
+	StorageConfig string
 }
 
 // Values: The values used in the template
@@ -31,6 +43,8 @@ type Values struct {
 	Namespace string
 	// Version to install
 	BundleVersion BundleVersion
+	// Observability add-on configuration
+	Observability ObservabilityValues
 }
 
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
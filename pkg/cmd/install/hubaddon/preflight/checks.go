@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// objectStorageConfig is the thanos object storage configuration,
+// see https://thanos.io/tip/thanos/storage.md/
+type objectStorageConfig struct {
+	Type   string                 `json:"type"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// requiredStorageFields are the fields required by each supported storage type
+var requiredStorageFields = map[string][]string{
+	"S3":    {"bucket", "endpoint"},
+	"GCS":   {"bucket"},
+	"AZURE": {"storage_account", "container"},
+	"SWIFT": {"auth_url", "container_name"},
+	"COS":   {"bucket", "region"},
+}
+
+// ObjectStorageCheck validates the object storage configuration used by the
+// multicluster-observability add-on.
+type ObjectStorageCheck struct {
+	Config []byte
+}
+
+func (c ObjectStorageCheck) Check() (warnings []string, errorList []error) {
+	storage := &objectStorageConfig{}
+	if err := yaml.Unmarshal(c.Config, storage); err != nil {
+		return nil, []error{errors.Wrapf(err, "failed to parse the storage config")}
+	}
+
+	storageType := strings.ToUpper(storage.Type)
+	required, ok := requiredStorageFields[storageType]
+	if !ok {
+		return nil, []error{fmt.Errorf("unsupported storage type %q", storage.Type)}
+	}
+	for _, field := range required {
+		if len(getString(storage.Config, field)) == 0 {
+			errorList = append(errorList, fmt.Errorf("the %s field is missing in the %s storage config", field, storageType))
+		}
+	}
+
+	switch storageType {
+	case "S3":
+		accessKey, secretKey := getString(storage.Config, "access_key"), getString(storage.Config, "secret_key")
+		if len(accessKey) > 0 && len(secretKey) == 0 || len(accessKey) == 0 && len(secretKey) > 0 {
+			errorList = append(errorList, fmt.Errorf("access_key and secret_key should be both set in the S3 storage config"))
+		}
+		if len(accessKey) == 0 && len(secretKey) == 0 {
+			warnings = append(warnings, "no access_key and secret_key in the S3 storage config, the credentials should be provided by the environment")
+		}
+		if insecure, ok := storage.Config["insecure"].(bool); ok && insecure {
+			warnings = append(warnings, "the S3 storage is accessed with an insecure connection")
+		}
+	case "GCS":
+		if len(getString(storage.Config, "service_account")) == 0 {
+			warnings = append(warnings, "no service_account in the GCS storage config, the credentials should be provided by the environment")
+		}
+	case "AZURE":
+		if len(getString(storage.Config, "storage_account_key")) == 0 {
+			warnings = append(warnings, "no storage_account_key in the AZURE storage config, the credentials should be provided by the environment")
+		}
+	}
+
+	return warnings, errorList
+}
+
+func (c ObjectStorageCheck) Name() string {
+	return "ObjectStorage check"
+}
+
+func getString(config map[string]interface{}, key string) string {
+	value, ok := config[key].(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,84 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"errors"
+	"testing"
+
+	testinghelper "open-cluster-management.io/clusteradm/pkg/helpers/testing"
+)
+
+func TestObjectStorageCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		wantWarnings []string
+		wantErrors   []error
+	}{
+		{
+			name: "valid s3 config",
+			config: `
+type: s3
+config:
+  bucket: thanos
+  endpoint: s3.amazonaws.com
+  access_key: key
+  secret_key: secret
+`,
+		},
+		{
+			name: "s3 config without credentials",
+			config: `
+type: S3
+config:
+  bucket: thanos
+  endpoint: s3.amazonaws.com
+`,
+			wantWarnings: []string{"no access_key and secret_key in the S3 storage config, the credentials should be provided by the environment"},
+		},
+		{
+			name: "s3 config with partial credentials",
+			config: `
+type: s3
+config:
+  bucket: thanos
+  endpoint: s3.amazonaws.com
+  access_key: key
+`,
+			wantErrors: []error{errors.New("access_key and secret_key should be both set in the S3 storage config")},
+		},
+		{
+			name: "s3 config without bucket",
+			config: `
+type: s3
+config:
+  endpoint: s3.amazonaws.com
+  access_key: key
+  secret_key: secret
+`,
+			wantErrors: []error{errors.New("the bucket field is missing in the S3 storage config")},
+		},
+		{
+			name: "unsupported type",
+			config: `
+type: FILESYSTEM
+config:
+  directory: /tmp
+`,
+			wantErrors: []error{errors.New(`unsupported storage type "FILESYSTEM"`)},
+		},
+		{
+			name:       "invalid yaml",
+			config:     "type: [",
+			wantErrors: []error{errors.New("failed to parse the storage config: error converting YAML to JSON: yaml: line 1: did not find expected node content")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ObjectStorageCheck{Config: []byte(tt.config)}
+			warnings, errs := c.Check()
+			testinghelper.AssertWarnings(t, warnings, tt.wantWarnings)
+			testinghelper.AssertErrors(t, errs, tt.wantErrors)
+		})
+	}
+}
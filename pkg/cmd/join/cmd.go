@@ -3,16 +3,49 @@ package join
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join/preflight"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/oplog"
+	"open-cluster-management.io/clusteradm/pkg/helpers/stamp"
 )
 
 var example = `
 # Join a cluster to the hub
 %[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name>
+# Join a cluster to the hub with the credentials generated by '%[1]s generate credentials', no accept is needed on the hub
+%[1]s join --credentials <cluster_name>-credentials.tar
+# Join a cluster to the hub without the klusterlet operator, the agents are deployed directly
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --no-operator
+
+# Register a cluster joined to a hub to a second hub, e.g. the hub of the disaster recovery site
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <dr_hub_apiserver_url> --cluster-name <cluster_name> --additional-hub --klusterlet-name dr
+
+# Join a cluster to the hub, the resources created on the cluster are labeled for the cost allocation
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --labels cost-center=platform
+
+# Join a cluster to the hub and verify the CA read from the hub against the hash printed by init
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --discovery-token-ca-cert-hash sha256:<hash>
+
+# Join a cluster to the hub without ever trusting the hub certificate on first use, the hub CA is pinned by its hash
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --secure-bootstrap --ca-hash sha256:<hash>
+
+# Join a cluster to the hub from a CI pipeline, the results of the preflight checks are published as JUnit tests
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --report-format junit --report-file preflight.xml
+
+# Join a cluster receiving very large ManifestWorks, the work agent is tuned at install time
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --work-agent-qps 50 --work-agent-burst 100 --max-concurrent-work-reconciles 20
+
+# Join an edge cluster with an intermittent connectivity, then resume the join where it stopped if it is interrupted
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --state-file join.state
+%[1]s join --hub-token <tokenID.tokenSecret> --hub-apiserver <hub_apiserver_url> --cluster-name <cluster_name> --resume-from join.state
 `
 
 // NewCmd ...
@@ -30,30 +63,105 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.complete(c, args); err != nil {
-				return err
+				return exit.Validation(err)
 			}
 			if err := o.validate(); err != nil {
-				return err
+				return exit.Validation(err)
 			}
-			if err := o.run(); err != nil {
-				return err
+			start := time.Now()
+			err := o.run(c.Context())
+			if !o.ClusteradmFlags.DryRun {
+				oplog.Record(o.ClusteradmFlags.KubectlFactory, config.ManagedClusterNamespace,
+					oplog.NewOperation(c, o.bundleVersion, start, err), o.Streams.ErrOut)
+				o.telemetry.Record(o.ClusteradmFlags.KubectlFactory, "join", o.bundleVersion, start, err, o.Streams.ErrOut)
 			}
-
-			return nil
+			return err
 		},
 	}
 
-	cmd.Flags().StringVar(&o.token, "hub-token", "", "The token to access the hub")
-	cmd.Flags().StringVar(&o.hubAPIServer, "hub-apiserver", "", "The api server url to the hub")
-	cmd.Flags().StringVar(&o.caFile, "ca-file", "", "the file path to hub ca, optional")
-	cmd.Flags().StringVar(&o.clusterName, "cluster-name", "", "The name of the joining cluster")
-	cmd.Flags().StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
-	cmd.Flags().StringVar(&o.registry, "image-registry", "quay.io/open-cluster-management", "The name of the image registry serving OCM images.")
-	cmd.Flags().StringVar(&o.bundleVersion, "bundle-version", "default",
+	o.addFlags(cmd.Flags())
+	stamp.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// addFlags adds the flags of the options, their defaults are the defaults of the options
+func (o *Options) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.token, "hub-token", "", "The token to access the hub")
+	flags.StringVar(&o.hubAPIServer, "hub-apiserver", "", "The api server url to the hub")
+	flags.StringVar(&o.caFile, "ca-file", "", "the file path to hub ca, optional")
+	flags.StringVar(&o.clusterName, "cluster-name", "", "The name of the joining cluster")
+	flags.StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
+	flags.StringVar(&o.registry, "image-registry", "quay.io/open-cluster-management", "The name of the image registry serving OCM images.")
+	flags.StringVar(&o.bundleVersion, "bundle-version", "default",
 		"version of predefined compatible image versions")
-	cmd.Flags().BoolVar(&o.forceHubInClusterEndpointLookup, "force-internal-endpoint-lookup", false,
+	flags.BoolVar(&o.forceHubInClusterEndpointLookup, "force-internal-endpoint-lookup", false,
 		"If true, the installed klusterlet agent will be starting the cluster registration process by "+
 			"looking for the internal endpoint from the public cluster-info in the hub cluster instead of from --hub-apiserver.")
-	cmd.Flags().BoolVar(&o.wait, "wait", false, "If true, running the cluster registration in foreground.")
-	return cmd
+	flags.BoolVar(&o.wait, "wait", false, "If true, running the cluster registration in foreground.")
+	flags.DurationVar(&o.leaseDuration, "lease-duration", 0,
+		"The duration the klusterlet agent renews its lease on the hub, a longer duration reduces the network traffic "+
+			"but delays the detection of an unavailable cluster. Defaults to 60s.")
+	flags.StringVar(&o.imageDigestFile, "image-digest-file", "",
+		"A yaml file mapping the image names (registration-operator, registration, work) to their digests, "+
+			"the images are referenced by digests instead of tags")
+	flags.BoolVar(&o.skipImageCheck, "skip-image-check", false,
+		"If true, the images and their architectures are not looked up in the registry, "+
+			"e.g. if the registry is mirrored on the nodes or not reachable from here")
+	flags.StringVar(&o.credentialsFile, "credentials", "",
+		"The pre-approved credentials bundle generated on the hub, the cluster joins without token and accept. "+
+			"The cluster name, hub api server and ca are read from the bundle")
+	flags.BoolVar(&o.cleanupOnFailure, "cleanup-on-failure", false,
+		"If true, the resources applied by the join are deleted in reverse order if it fails or times out, so the next join starts clean")
+	flags.BoolVar(&o.noOperator, "no-operator", false,
+		"If true, the registration and work agents are deployed directly instead of the klusterlet operator, "+
+			"for the clusters where an operator is not allowed. The agents are upgraded by running the join again")
+	flags.BoolVar(&o.inClusterCheck, "in-cluster-check", false,
+		"If true, the connectivity to the hub is also checked from a pod of the cluster before anything is applied")
+	flags.StringVar(&o.inClusterCheckImage, "in-cluster-check-image", preflight.DefaultInClusterCheckImage,
+		"The image of the pod checking the connectivity to the hub, it runs curl. Only used with --in-cluster-check")
+	flags.BoolVar(&o.force, "force", false,
+		"If true, the hub APIs and version incompatible with the agents of --bundle-version are reported as warnings instead of errors")
+	flags.BoolVar(&o.secureBootstrap, "secure-bootstrap", false,
+		"If true, the hub certificate is always verified: the hub CA is read from --ca-file, pinned by --ca-hash or in the local trust store, "+
+			"instead of being read from the cluster-info of the hub without verifying its certificate")
+	flags.StringSliceVar(&o.caHash, "ca-hash", []string{},
+		"The sha256:<hex> hashes of the public key of the hub CA, the CA presented by the hub must match one of them. Only used with --secure-bootstrap")
+	flags.StringSliceVar(&o.discoveryTokenCACertHash, "discovery-token-ca-cert-hash", []string{},
+		"The sha256:<hex> hashes of the public key of the hub CA printed by init and get token, "+
+			"the CA read from the cluster-info of the hub must match one of them")
+	flags.BoolVar(&o.withNetworkPolicies, "with-network-policies", false,
+		"If true, deny by default network policies are applied to the agent namespaces, only the DNS, the hub API server "+
+			"and the API server of the cluster are allowed. The hub host is resolved at join, run the join again if its addresses change")
+	flags.StringVar(&o.preferIPFamily, "prefer-ip-family", "",
+		"The IP family of the dual-stack clusters, ipv4 or ipv6: the hub is checked to be reachable over it and "+
+			"a warning is printed if the endpoint of the cluster in its cluster-info has no address of it")
+	flags.StringVar(&o.klusterletName, "klusterlet-name", DefaultKlusterletName,
+		"The name of the klusterlet, a cluster registered to several hubs runs a klusterlet per hub. The agents of the klusterlets "+
+			"other than the default one run in the open-cluster-management-<name>-agent namespace")
+	flags.BoolVar(&o.additionalHub, "additional-hub", false,
+		"If true, the cluster already registered to a hub is registered to this hub too by the klusterlet --klusterlet-name. "+
+			"The klusterlet runs its agents in its own namespace and is reconciled by the klusterlet operator of the cluster, "+
+			"which is not reinstalled. The join fails if the klusterlet, its namespaces or a registration to the hub already exist")
+	flags.Int32Var(&o.workAgentQPS, "work-agent-qps", 0,
+		"The QPS of the work agent to the API server of the cluster, for the clusters receiving very large ManifestWorks. "+
+			"Set in the workConfiguration of the klusterlet, the default of the agent if not set")
+	flags.Int32Var(&o.workAgentBurst, "work-agent-burst", 0,
+		"The burst of the work agent to the API server of the cluster, not lower than --work-agent-qps. "+
+			"Set in the workConfiguration of the klusterlet, the default of the agent if not set")
+	flags.Int32Var(&o.maxConcurrentWorkReconciles, "max-concurrent-work-reconciles", 0,
+		"The number of ManifestWorks the work agent applies concurrently. "+
+			"Set in the workConfiguration of the klusterlet, the default of the agent if not set")
+	flags.DurationVar(&o.evictionGracePeriod, "appliedmanifestwork-eviction-grace-period", 0,
+		"The duration the work agent keeps the resources of the ManifestWorks it can not find on the hub before evicting them, "+
+			"a longer duration keeps the workloads running through the disconnections of the hub. "+
+			"Set in the workConfiguration of the klusterlet, the default of the agent if not set")
+	flags.StringVar(&o.stateFile, "state-file", "",
+		"The file the progress of the join is saved to: the completed phases and the applied resources, without the hub credentials. "+
+			"The join fails if the file exists, resume it with --resume-from")
+	flags.StringVar(&o.resumeFrom, "resume-from", "",
+		"The state file of an interrupted join to resume, with the same cluster name, hub and klusterlet: its completed phases, "+
+			"including the preflight checks, are skipped and the progress is saved to the same file unless --state-file is set")
+	o.verifyImages.AddFlags(flags)
+	o.telemetry.AddFlags(flags)
+	o.report.AddFlags(flags)
 }
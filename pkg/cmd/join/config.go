@@ -0,0 +1,80 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"context"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Config is the configuration of a join run from Go, e.g. by pkg/join, the zero values are the defaults of the flags
+type Config struct {
+	ClusterName                string
+	HubAPIServer               string
+	HubToken                   string
+	CAFile                     string
+	DiscoveryTokenCACertHashes []string
+	BundleVersion              string
+	Registry                   string
+	KlusterletName             string
+	Wait                       bool
+}
+
+// Result is what the join deployed on the cluster
+type Result struct {
+	// ClusterName is the name of the cluster on the hub, read from the credentials bundle if the join uses one
+	ClusterName string
+	// KlusterletName is the name of the klusterlet registering the cluster to the hub
+	KlusterletName string
+	// AgentNamespace is the namespace of the registration and work agents
+	AgentNamespace string
+	// Images are the images of the operator and the agents, pinned by their digests once verified
+	Images []string
+	// Available is true once the registration agent is ready, it is only waited for with Wait
+	Available bool
+}
+
+// NewOptionsOf returns the options of the join of the config, the options which are not in the config
+// have the defaults of the flags
+func NewOptionsOf(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams, config Config) *Options {
+	o := newOptions(clusteradmFlags, streams)
+	o.addFlags(pflag.NewFlagSet("join", pflag.ContinueOnError))
+	o.clusterName = config.ClusterName
+	o.hubAPIServer = config.HubAPIServer
+	o.token = config.HubToken
+	o.caFile = config.CAFile
+	o.discoveryTokenCACertHash = config.DiscoveryTokenCACertHashes
+	o.wait = config.Wait
+	if len(config.BundleVersion) > 0 {
+		o.bundleVersion = config.BundleVersion
+	}
+	if len(config.Registry) > 0 {
+		o.registry = config.Registry
+	}
+	if len(config.KlusterletName) > 0 {
+		o.klusterletName = config.KlusterletName
+	}
+	return o
+}
+
+// Run completes, validates and runs the join, it stops waiting when the context is done
+func (o *Options) Run(ctx context.Context) (*Result, error) {
+	if err := o.complete(nil, nil); err != nil {
+		return nil, err
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if err := o.run(ctx); err != nil {
+		return nil, err
+	}
+	return &Result{
+		ClusterName:    o.values.ClusterName,
+		KlusterletName: o.values.Klusterlet.Name,
+		AgentNamespace: o.values.Klusterlet.Namespace,
+		Images:         o.images(),
+		Available:      o.wait && !o.ClusteradmFlags.DryRun,
+	}, nil
+}
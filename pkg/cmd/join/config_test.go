@@ -0,0 +1,30 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join/preflight"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+func TestNewOptionsOf(t *testing.T) {
+	flags := genericclioptionsclusteradm.NewClusteradmFlags(nil)
+	streams := genericclioptions.NewTestIOStreamsDiscard()
+
+	o := NewOptionsOf(flags, streams, Config{ClusterName: "cluster1", HubAPIServer: "https://hub:6443", HubToken: "abc.def"})
+	if o.clusterName != "cluster1" || o.hubAPIServer != "https://hub:6443" || o.token != "abc.def" {
+		t.Errorf("unexpected options %+v", o)
+	}
+	// the defaults of the flags
+	if o.registry != "quay.io/open-cluster-management" || o.bundleVersion != "default" ||
+		o.klusterletName != DefaultKlusterletName || o.inClusterCheckImage != preflight.DefaultInClusterCheckImage {
+		t.Errorf("expected the defaults of the flags, got %+v", o)
+	}
+
+	o = NewOptionsOf(flags, streams, Config{BundleVersion: "v0.9.0", Registry: "registry.example.com/ocm", KlusterletName: "hub2"})
+	if o.bundleVersion != "v0.9.0" || o.registry != "registry.example.com/ocm" || o.klusterletName != "hub2" {
+		t.Errorf("expected the config to override the defaults, got %+v", o)
+	}
+}
@@ -4,6 +4,7 @@ package join
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"sync/atomic"
 	"time"
@@ -11,45 +12,94 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 	"github.com/stolostron/applier/pkg/apply"
+	"github.com/stolostron/applier/pkg/asset"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/cmd/util"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"open-cluster-management.io/clusteradm/pkg/cmd/join/preflight"
 	"open-cluster-management.io/clusteradm/pkg/cmd/join/scenario"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+	"open-cluster-management.io/clusteradm/pkg/helpers/cahash"
+	"open-cluster-management.io/clusteradm/pkg/helpers/credentials"
+	"open-cluster-management.io/clusteradm/pkg/helpers/endpoint"
+	"open-cluster-management.io/clusteradm/pkg/helpers/i18n"
+	"open-cluster-management.io/clusteradm/pkg/helpers/image"
+	"open-cluster-management.io/clusteradm/pkg/helpers/podsecurity"
 	preflightinterface "open-cluster-management.io/clusteradm/pkg/helpers/preflight"
 	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollback"
+	"open-cluster-management.io/clusteradm/pkg/helpers/tlspolicy"
 	"open-cluster-management.io/clusteradm/pkg/helpers/version"
 	"open-cluster-management.io/clusteradm/pkg/helpers/wait"
 )
 
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
-	if o.token == "" {
-		return fmt.Errorf("token is missing")
-	}
-	if o.hubAPIServer == "" {
-		return fmt.Errorf("hub-server is missing")
+	if o.credentialsFile != "" {
+		if err := o.loadCredentials(); err != nil {
+			return err
+		}
+	} else {
+		if o.token == "" {
+			return fmt.Errorf("token is missing")
+		}
+		if o.hubAPIServer == "" {
+			return fmt.Errorf("hub-server is missing")
+		}
 	}
 	if o.clusterName == "" {
 		return fmt.Errorf("name is missing")
 	}
+	if err := endpoint.ValidateFamily(o.preferIPFamily); err != nil {
+		return fmt.Errorf("invalid --prefer-ip-family: %v", err)
+	}
+	// the IPv6 literals are bracketed in the rendered kubeconfigs
+	if o.hubAPIServer, err = endpoint.Normalize(o.hubAPIServer); err != nil {
+		return err
+	}
+	if o.HubConfig != nil && len(o.HubConfig.Clusters) == 1 {
+		o.HubConfig.Clusters[0].Cluster.Server = o.hubAPIServer
+	}
 	if len(o.registry) == 0 {
 		return fmt.Errorf("the OCM image registry should not be empty, like quay.io/open-cluster-management")
 	}
-	klog.V(1).InfoS("join options:", "dry-run", o.ClusteradmFlags.DryRun, "cluster", o.clusterName, "api-server", o.hubAPIServer, "output", o.outputFile)
+	if err := ValidateKlusterletName(o.klusterletName); err != nil {
+		return err
+	}
+	if o.noOperator && o.klusterletName != DefaultKlusterletName {
+		return fmt.Errorf("--klusterlet-name can not be set with --no-operator")
+	}
+	if o.additionalHub && o.klusterletName == DefaultKlusterletName {
+		return fmt.Errorf("--additional-hub requires --klusterlet-name, the name of the klusterlet of the hub")
+	}
+	if err := o.completeState(); err != nil {
+		return err
+	}
+	klog.V(1).InfoS("join options:", "dry-run", o.ClusteradmFlags.DryRun, "cluster", o.clusterName, "api-server", o.hubAPIServer, "output", o.outputFile,
+		"klusterlet", o.klusterletName, "state-file", o.stateFile)
 
 	o.values = Values{
 		ClusterName: o.clusterName,
 		Hub: Hub{
 			APIServer: o.hubAPIServer,
 		},
+		Klusterlet: Klusterlet{
+			Name:      o.klusterletName,
+			Namespace: AgentNamespace(o.klusterletName),
+		},
 		Registry: o.registry,
 	}
 
@@ -66,12 +116,36 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 		WorkImageVersion:         versionBundle.Work,
 		OperatorImageVersion:     versionBundle.Operator,
 	}
+	digests := image.Digests{}
+	if len(o.imageDigestFile) > 0 {
+		if digests, err = image.LoadDigestFile(o.imageDigestFile); err != nil {
+			return err
+		}
+	}
+	o.values.Images = Images{
+		Operator:     image.PullSpec(o.registry, image.OperatorImageName, versionBundle.Registration, digests),
+		Registration: image.PullSpec(o.registry, image.RegistrationImageName, versionBundle.Registration, digests),
+		Work:         image.PullSpec(o.registry, image.WorkImageName, versionBundle.Registration, digests),
+	}
+
 	klog.V(3).InfoS("Image version:",
 		"'registration image version'", versionBundle.Registration,
 		"'placement image version'", versionBundle.Placement,
 		"'work image version'", versionBundle.Work,
 		"'operator image version'", versionBundle.Operator)
 
+	// the hub kubeconfig is built from the credentials
+	if o.credentialsFile != "" {
+		o.values.Credentials = Credentials{
+			ClusterName: o.credentials.ClusterName,
+			AgentName:   o.credentials.AgentName,
+			KubeConfig:  string(o.credentials.KubeConfig),
+			Cert:        string(o.credentials.Cert),
+			Key:         string(o.credentials.Key),
+		}
+		return o.completeKlusterletAPIServer()
+	}
+
 	// if --ca-file is set, read ca data
 	if o.caFile != "" {
 		cabytes, err := os.ReadFile(o.caFile)
@@ -81,6 +155,32 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 		o.HubCADate = cabytes
 	}
 
+	if len(o.caHash) > 0 {
+		if !o.secureBootstrap {
+			return fmt.Errorf("--ca-hash can only be set with --secure-bootstrap")
+		}
+		if o.caFile != "" {
+			return fmt.Errorf("--ca-file and --ca-hash can not be set together")
+		}
+		if err := cahash.Validate(o.caHash); err != nil {
+			return err
+		}
+	}
+	if len(o.discoveryTokenCACertHash) > 0 {
+		if o.secureBootstrap {
+			return fmt.Errorf("--discovery-token-ca-cert-hash can not be set with --secure-bootstrap, use --ca-hash instead")
+		}
+		if err := cahash.Validate(o.discoveryTokenCACertHash); err != nil {
+			return err
+		}
+	}
+	if o.secureBootstrap {
+		if o.HubConfig, err = o.createSecureBootstrapConfig(); err != nil {
+			return err
+		}
+		return o.completeKlusterletAPIServer()
+	}
+
 	// code logic of building hub client in join process:
 	// 1. use the token and insecure to fetch the ca data from cm in kube-public ns
 	// 2. if not found, assume using a authorized ca.
@@ -88,6 +188,7 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 
 	//Create an unsecure bootstrap
 	bootstrapExternalConfigUnSecure := o.createExternalBootstrapConfig()
+	bootstrapExternalConfigUnSecure.Clusters[0].Cluster.InsecureSkipTLSVerify = true
 	//create external client from the bootstrap
 	externalClientUnSecure, err := helpers.CreateClientFromClientcmdapiv1Config(bootstrapExternalConfigUnSecure)
 	if err != nil {
@@ -96,9 +197,20 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 	//Create the kubeconfig for the internal client
 	o.HubConfig, err = o.createClientcmdapiv1Config(externalClientUnSecure, bootstrapExternalConfigUnSecure)
 	if err != nil {
+		// report the precise cause if the hub is not reachable
+		if checkErr := o.report.RunChecks("join", []preflightinterface.Checker{
+			preflight.HubConnectivityCheck{Server: o.hubAPIServer, Family: o.preferIPFamily},
+		}, o.Streams.ErrOut); checkErr != nil {
+			return checkErr
+		}
 		return err
 	}
 
+	return o.completeKlusterletAPIServer()
+}
+
+// completeKlusterletAPIServer sets the external server url of the managed cluster
+func (o *Options) completeKlusterletAPIServer() error {
 	// get managed cluster externalServerURL
 	kubeClient, err := o.ClusteradmFlags.KubectlFactory.KubernetesClientSet()
 	if err != nil {
@@ -112,6 +224,11 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 	} else if !preflight.ValidAPIHost(klusterletApiserver) {
 		klog.Warningf("ConfigMap/cluster-info.data.kubeconfig.clusters[0].cluster.server field [%s] in namespace kube-public should start with http:// or https://", klusterletApiserver)
 		klusterletApiserver = ""
+	} else if klusterletApiserver, err = endpoint.Normalize(klusterletApiserver); err != nil {
+		klog.Warningf("ConfigMap/cluster-info.data.kubeconfig.clusters[0].cluster.server field in namespace kube-public is invalid: %v", err)
+		klusterletApiserver = ""
+	} else if _, err := endpoint.Addresses(klusterletApiserver, o.preferIPFamily, net.LookupIP); err != nil {
+		klog.Warningf("The endpoint %s of the cluster does not match --prefer-ip-family: %v", klusterletApiserver, err)
 	}
 	o.values.Klusterlet.APIServer = klusterletApiserver
 
@@ -120,28 +237,184 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 		"hubAPIServer", o.values.Hub.APIServer,
 		"klusterletAPIServer", o.values.Klusterlet.APIServer)
 	return nil
+}
 
+// loadCredentials reads the pre-approved credentials, the hub kubeconfig secret of the registration
+// agent is created from them and the bootstrap kubeconfig embeds their client certificate.
+func (o *Options) loadCredentials() error {
+	if o.token != "" || o.hubAPIServer != "" || o.caFile != "" || o.forceHubInClusterEndpointLookup {
+		return fmt.Errorf("--hub-token, --hub-apiserver, --ca-file and --force-internal-endpoint-lookup can not be set with --credentials, " +
+			"the hub is read from the credentials")
+	}
+	f, err := os.Open(o.credentialsFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bundle, err := credentials.Read(f)
+	if err != nil {
+		return err
+	}
+	if o.clusterName != "" && o.clusterName != bundle.ClusterName {
+		return fmt.Errorf("the credentials are generated for cluster %s, not %s", bundle.ClusterName, o.clusterName)
+	}
+	o.clusterName = bundle.ClusterName
+
+	o.HubConfig, err = bundle.EmbeddedKubeConfig()
+	if err != nil {
+		return err
+	}
+	o.hubAPIServer = o.HubConfig.Clusters[0].Cluster.Server
+	o.credentials = bundle
+	return nil
 }
 
 func (o *Options) validate() error {
+	if err := o.verifyImages.Validate(); err != nil {
+		return err
+	}
+	if err := o.telemetry.Validate(); err != nil {
+		return err
+	}
+	if err := o.report.Validate(); err != nil {
+		return err
+	}
+	if o.leaseDuration < 0 || o.leaseDuration%time.Second != 0 {
+		return fmt.Errorf("--lease-duration should be a positive number of seconds")
+	}
+	if err := o.validateWorkConfiguration(); err != nil {
+		return err
+	}
+	if len(o.stateFile) > 0 && o.ClusteradmFlags.DryRun {
+		return fmt.Errorf("--state-file and --resume-from can not be set with --dry-run")
+	}
+
+	kubeClient, apiExtensionsClient, dynamicClient, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+	architectureCheck := &image.ArchitectureCheck{
+		Images:     o.images(),
+		KubeClient: kubeClient,
+		Skip:       o.skipImageCheck,
+	}
+	podSecurityCheck := &podsecurity.Check{
+		KubeClient:    kubeClient,
+		DynamicClient: dynamicClient,
+		Namespaces:    []string{config.OpenClusterManagementNamespace, o.values.Klusterlet.Namespace},
+	}
+
 	// preflight check
-	if err := preflightinterface.RunChecks(
-		[]preflightinterface.Checker{
-			preflight.HubKubeconfigCheck{
-				Config: o.HubConfig,
-			},
-		}, os.Stderr); err != nil {
+	checks := []preflightinterface.Checker{podSecurityCheck}
+	if o.HubConfig != nil && len(o.HubConfig.Clusters) == 1 {
+		hubCluster := o.HubConfig.Clusters[0].Cluster
+		checks = append(checks, preflight.HubConnectivityCheck{
+			Server: hubCluster.Server,
+			CAData: hubCluster.CertificateAuthorityData,
+			Family: o.preferIPFamily,
+		})
+		hubRestConfig, err := helpers.CreateRESTConfigFromClientcmdapiv1Config(*o.HubConfig)
+		if err != nil {
+			return err
+		}
+		hubDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(hubRestConfig)
+		if err != nil {
+			return err
+		}
+		hubOperatorClient, err := operatorclient.NewForConfig(hubRestConfig)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, tlspolicy.Check{Config: hubRestConfig})
+		checks = append(checks, preflight.HubCompatibilityCheck{
+			Discovery:      hubDiscoveryClient,
+			OperatorClient: hubOperatorClient,
+			AgentVersion:   o.values.BundleVersion.RegistrationImageVersion,
+			Force:          o.force,
+		})
+		if o.inClusterCheck {
+			checks = append(checks, preflight.InClusterConnectivityCheck{
+				KubeClient:  kubeClient,
+				Server:      hubCluster.Server,
+				CAData:      hubCluster.CertificateAuthorityData,
+				Image:       o.inClusterCheckImage,
+				Namespace:   metav1.NamespaceDefault,
+				Timeout:     time.Duration(o.ClusteradmFlags.Timeout) * time.Second,
+				PodSecurity: podSecurityCheck,
+			})
+		}
+	}
+	checks = append(checks,
+		preflight.HubKubeconfigCheck{
+			Config: o.HubConfig,
+		},
+		architectureCheck,
+		attach.ConflictCheck{
+			KubeClient:          kubeClient,
+			APIExtensionsClient: apiExtensionsClient,
+			Mode:                attach.ModeKlusterlet,
+			OperatorNamespace:   config.OpenClusterManagementNamespace,
+			OperatorName:        klusterletOperatorName,
+		},
+	)
+	if o.additionalHub {
+		restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		operatorClient, err := operatorclient.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, preflight.AdditionalHubCheck{
+			KubeClient:     kubeClient,
+			OperatorClient: operatorClient,
+			KlusterletName: o.values.Klusterlet.Name,
+			Namespace:      o.values.Klusterlet.Namespace,
+			HubAPIServer:   o.hubAPIServer,
+			AgentVersion:   o.values.BundleVersion.RegistrationImageVersion,
+		})
+	}
+	// the checks are not run again once passed, the resources applied since then may fail them
+	if o.state.Completed(phasePreflight) {
+		o.values.Architectures = o.state.Architectures
+		o.values.SeccompProfile = o.state.SeccompProfile
+	}
+	if err := o.phase(phasePreflight, nil, func() error {
+		if err := o.report.RunChecks("join", checks, o.Streams.ErrOut); err != nil {
+			return err
+		}
+		o.values.Architectures = architectureCheck.Architectures
+		o.values.SeccompProfile = podSecurityCheck.SeccompProfile
+		if o.state != nil {
+			o.state.Architectures = o.values.Architectures
+			o.state.SeccompProfile = o.values.SeccompProfile
+		}
+		return nil
+	}); err != nil {
 		return err
 	}
 
-	err := o.setKubeconfig()
+	err = o.setKubeconfig()
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (o *Options) run() error {
+func (o *Options) run(ctx context.Context) (err error) {
+	// verify the images before deploying anything, fail closed if the verification is enabled.
+	// The verified digests are deployed, the images are not verified by a dry run
+	if o.verifyImages.Enabled && o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.ErrOut, "The images are not verified by a dry run\n")
+	} else {
+		images, err := o.verifyImages.Verify(o.images())
+		if err != nil {
+			return err
+		}
+		o.setImages(images)
+	}
+
 	output := make([]string, 0)
 	reader := scenario.GetScenarioResourcesReader()
 
@@ -152,62 +425,300 @@ func (o *Options) run() error {
 	applierBuilder := apply.NewApplierBuilder()
 	applier := applierBuilder.WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
 
-	files := []string{
-		"join/namespace_agent.yaml",
-		"join/namespace.yaml",
-		"join/bootstrap_hub_kubeconfig.yaml",
-		"join/cluster_role.yaml",
-		"join/cluster_role_binding.yaml",
-		"join/klusterlets.crd.yaml",
-		"join/service_account.yaml",
+	files := directFiles(o.credentialsFile != "", !o.noOperator)
+	if o.additionalHub {
+		// the klusterlet is reconciled by the operator of the cluster
+		files = directFiles(o.credentialsFile != "", false)
+	}
+	if o.noOperator {
+		files = append(files, agentFiles...)
 	}
 
-	out, err := applier.ApplyDirectly(reader, o.values, o.ClusteradmFlags.DryRun, "", files...)
-	if err != nil {
+	// track the resources created by the join to delete them if the join fails, with the ones of the interrupted
+	// joins. The existing resources are not tracked, e.g. the namespace of the cluster manager on a self-managed hub
+	tracker := &rollback.Tracker{}
+	if err := o.trackState(tracker); err != nil {
 		return err
 	}
-	output = append(output, out...)
+	if !o.ClusteradmFlags.DryRun {
+		if err := o.excludeExisting(ctx, tracker, applier, reader, dynamicClient, files); err != nil {
+			return err
+		}
+	}
+	defer func() {
+		if err != nil && o.cleanupOnFailure && !o.ClusteradmFlags.DryRun {
+			o.cleanup(tracker, dynamicClient)
+			o.removeState()
+		}
+	}()
 
-	out, err = applier.ApplyDeployments(reader, o.values, o.ClusteradmFlags.DryRun, "", "join/operator.yaml")
-	if err != nil {
+	if err := o.phase(phaseResources, tracker, func() error {
+		out, err := applier.ApplyDirectly(reader, o.values, o.ClusteradmFlags.DryRun, "", files...)
+		track(tracker, out)
+		output = append(output, out...)
+		return err
+	}); err != nil {
 		return err
 	}
-	output = append(output, out...)
 
-	if !o.ClusteradmFlags.DryRun {
-		if err := wait.WaitUntilCRDReady(apiExtensionsClient, "klusterlets.operator.open-cluster-management.io", o.wait); err != nil {
+	if o.withNetworkPolicies {
+		if err := o.phase(phaseNetworkPolicies, tracker, func() error {
+			out, err := o.applyNetworkPolicies(ctx, kubeClient, applier, reader)
+			output = append(output, out...)
+			return err
+		}); err != nil {
 			return err
 		}
 	}
 
-	out, err = applier.ApplyCustomResources(reader, o.values, o.ClusteradmFlags.DryRun, "", "join/klusterlets.cr.yaml")
-	if err != nil {
+	if o.noOperator {
+		if err := o.phase(phaseAgents, tracker, func() error {
+			out, err := applier.ApplyDeployments(reader, o.values, o.ClusteradmFlags.DryRun, "", agentDeploymentFiles...)
+			track(tracker, out)
+			output = append(output, out...)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if o.leaseDuration > 0 && !o.ClusteradmFlags.DryRun {
+			if err := o.phase(phaseLeaseDuration, tracker, func() error { return o.setLeaseDuration(ctx) }); err != nil {
+				return err
+			}
+		}
+		if o.wait && !o.ClusteradmFlags.DryRun {
+			err = waitUntilKlusterletConditionIsTrue(ctx, o.ClusteradmFlags.KubectlFactory, o.values.Klusterlet.Namespace, int64(o.ClusteradmFlags.Timeout))
+			if err != nil {
+				return err
+			}
+		}
+		return o.printNextStep(output)
+	}
+
+	if !o.additionalHub {
+		if err := o.phase(phaseOperator, tracker, func() error {
+			out, err := applier.ApplyDeployments(reader, o.values, o.ClusteradmFlags.DryRun, "", operatorFile)
+			track(tracker, out)
+			output = append(output, out...)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := o.phase(phaseKlusterlet, tracker, func() error {
+		if !o.ClusteradmFlags.DryRun {
+			if err := wait.WaitUntilCRDReady(apiExtensionsClient, "klusterlets.operator.open-cluster-management.io", o.wait); err != nil {
+				return err
+			}
+		}
+
+		// the klusterlet is tracked before it is applied, it may be created even if the apply fails
+		if out, err := applier.MustTemplateAssets(reader, o.values, "", klusterletFile); err == nil {
+			track(tracker, out)
+		}
+		out, err := applier.ApplyCustomResources(reader, o.values, o.ClusteradmFlags.DryRun, "", klusterletFile)
+		output = append(output, out...)
+		return err
+	}); err != nil {
 		return err
 	}
-	output = append(output, out...)
+
+	if o.leaseDuration > 0 && !o.ClusteradmFlags.DryRun {
+		if err := o.phase(phaseLeaseDuration, tracker, func() error { return o.setLeaseDuration(ctx) }); err != nil {
+			return err
+		}
+	}
 
 	if o.wait && !o.ClusteradmFlags.DryRun {
-		err = waitUntilRegistrationOperatorConditionIsTrue(o.ClusteradmFlags.KubectlFactory, int64(o.ClusteradmFlags.Timeout))
+		err = waitUntilRegistrationOperatorConditionIsTrue(ctx, o.ClusteradmFlags.KubectlFactory, int64(o.ClusteradmFlags.Timeout))
 		if err != nil {
 			return err
 		}
 	}
 
 	if o.wait && !o.ClusteradmFlags.DryRun {
-		err = waitUntilKlusterletConditionIsTrue(o.ClusteradmFlags.KubectlFactory, int64(o.ClusteradmFlags.Timeout))
+		err = waitUntilKlusterletConditionIsTrue(ctx, o.ClusteradmFlags.KubectlFactory, o.values.Klusterlet.Namespace, int64(o.ClusteradmFlags.Timeout))
 		if err != nil {
 			return err
 		}
 	}
 
-	fmt.Printf("Please log onto the hub cluster and run the following command:\n\n"+
-		"    %s accept --clusters %s\n\n", helpers.GetExampleHeader(), o.values.ClusterName)
+	return o.printNextStep(output)
+}
+
+// printNextStep prints how to accept the cluster and writes the output file
+func (o *Options) printNextStep(output []string) error {
+	if o.credentialsFile != "" {
+		fmt.Fprint(o.Streams.Out, i18n.T("join.preApproved", o.values.ClusterName))
+	} else {
+		fmt.Fprint(o.Streams.Out, i18n.T("join.nextStep", helpers.GetExampleHeader(), o.values.ClusterName))
+	}
 
 	return apply.WriteOutput(o.outputFile, output)
+}
+
+// images returns the images deployed by the join
+func (o *Options) images() []string {
+	images := []string{o.values.Images.Registration, o.values.Images.Work}
+	if o.noOperator {
+		return images
+	}
+	return append([]string{o.values.Images.Operator}, images...)
+}
+
+// setImages sets the images deployed by the join, in the order of images
+func (o *Options) setImages(images []string) {
+	if !o.noOperator {
+		o.values.Images.Operator, images = images[0], images[1:]
+	}
+	o.values.Images.Registration, o.values.Images.Work = images[0], images[1]
+}
+
+const (
+	operatorFile = "join/operator.yaml"
+	// operatorNamespaceFile is the open-cluster-management namespace, the namespace of the cluster manager too
+	operatorNamespaceFile = "join/namespace_agent.yaml"
+	klusterletFile        = "join/klusterlets.cr.yaml"
+)
+
+var (
+	// agentFiles are the resources of the agents applied instead of the operator, in order
+	agentFiles = []string{
+		"join/agent/appliedmanifestworks.crd.yaml",
+		"join/agent/clusterclaims.crd.yaml",
+		"join/agent/registration_service_account.yaml",
+		"join/agent/registration_cluster_role.yaml",
+		"join/agent/registration_cluster_role_binding.yaml",
+		"join/agent/work_service_account.yaml",
+		"join/agent/work_cluster_role_binding.yaml",
+	}
+	agentDeploymentFiles = []string{
+		"join/agent/registration_deployment.yaml",
+		"join/agent/work_deployment.yaml",
+	}
+)
+
+// directFiles returns the resources applied before the operator, in order. Without operator
+// only the agent namespace and the hub kubeconfigs are applied.
+func directFiles(withCredentials, withOperator bool) []string {
+	files := []string{}
+	if withOperator {
+		files = append(files, operatorNamespaceFile)
+	}
+	files = append(files, "join/namespace.yaml")
+	// the registration agent uses the pre-approved credentials instead of creating a csr
+	if withCredentials {
+		files = append(files, "join/hub_kubeconfig_secret.yaml")
+	}
+	files = append(files, "join/bootstrap_hub_kubeconfig.yaml")
+	if !withOperator {
+		return files
+	}
+	return append(files,
+		"join/cluster_role.yaml",
+		"join/cluster_role_binding.yaml",
+		"join/klusterlets.crd.yaml",
+		"join/service_account.yaml",
+	)
+}
 
+// AppliedResources returns the yaml of all the resources join may apply for the klusterlet in order, so a
+// partial join can be cleaned up without knowing the options it ran with. The open-cluster-management
+// namespace is left out, it is shared with the cluster manager of a self-managed hub.
+func AppliedResources(klusterletName string) ([]string, error) {
+	files := append(directFiles(true, true)[1:], agentFiles...)
+	files = append(files, agentDeploymentFiles...)
+	return klusterletResources(klusterletName, append(files, operatorFile, klusterletFile)...)
 }
 
-func waitUntilRegistrationOperatorConditionIsTrue(f util.Factory, timeout int64) error {
+// KlusterletResources returns the yaml of the resources join applies for the klusterlet only in order, the
+// operator and the agents are shared by the klusterlets of the cluster.
+func KlusterletResources(klusterletName string) ([]string, error) {
+	files := []string{"join/namespace.yaml", "join/hub_kubeconfig_secret.yaml", "join/bootstrap_hub_kubeconfig.yaml"}
+	return klusterletResources(klusterletName, append(files, klusterletFile)...)
+}
+
+func klusterletResources(klusterletName string, files ...string) ([]string, error) {
+	reader := scenario.GetScenarioResourcesReader()
+	values := Values{Klusterlet: Klusterlet{Name: klusterletName, Namespace: AgentNamespace(klusterletName)}}
+	applier := apply.NewApplierBuilder().Build()
+	return applier.MustTemplateAssets(reader, values, "", files...)
+}
+
+// track records the applied resources, a resource not tracked is only left behind by the cleanup
+func track(tracker *rollback.Tracker, output []string) {
+	if err := tracker.Track(output...); err != nil {
+		klog.Warningf("failed to track the applied resources: %v", err)
+	}
+}
+
+// excludeExisting excludes the resources of the join which already exist from the tracker
+func (o *Options) excludeExisting(ctx context.Context, tracker *rollback.Tracker, applier apply.Applier, reader asset.ScenarioReader,
+	dynamicClient dynamic.Interface, files []string) error {
+	files = append(append(files, agentDeploymentFiles...), operatorFile, klusterletFile)
+	manifests, err := applier.MustTemplateAssets(reader, o.values, "", files...)
+	if err != nil {
+		return err
+	}
+	mapper, err := o.ClusteradmFlags.KubectlFactory.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	return tracker.Exclude(ctx, dynamicClient, mapper, manifests...)
+}
+
+// cleanup deletes the resources applied by the failed join in reverse order, the failure
+// of the cleanup is only reported as the error of the join is returned. It is not bound to the
+// context of the join, it also cleans up the join which is canceled.
+func (o *Options) cleanup(tracker *rollback.Tracker, dynamicClient dynamic.Interface) {
+	fmt.Fprint(o.Streams.Out, i18n.T("join.cleanup"))
+	mapper, err := o.ClusteradmFlags.KubectlFactory.ToRESTMapper()
+	if err == nil {
+		err = tracker.Rollback(context.TODO(), dynamicClient, mapper, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, o.Streams.Out)
+	}
+	if err != nil {
+		fmt.Fprintf(o.Streams.ErrOut, "failed to clean up the failed join, run '%s unjoin --partial' to retry: %v\n", helpers.GetExampleHeader(), err)
+	}
+}
+
+// setLeaseDuration sets the lease duration on the ManagedCluster with the bootstrap
+// credentials, the registration agent renews its lease with the duration of the
+// ManagedCluster. The ManagedCluster is created if the agent has not created it yet.
+func (o *Options) setLeaseDuration(ctx context.Context) error {
+	bootstrapConfig := o.HubConfig.DeepCopy()
+	bootstrapConfig.Clusters[0].Cluster.Server = o.hubAPIServer
+	restConfig, err := helpers.CreateRESTConfigFromClientcmdapiv1Config(*bootstrapConfig)
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	leaseDurationSeconds := int32(o.leaseDuration / time.Second)
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(ctx, o.clusterName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		cluster = &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: o.clusterName},
+			Spec:       clusterv1.ManagedClusterSpec{LeaseDurationSeconds: leaseDurationSeconds},
+		}
+		_, err = clusterClient.ClusterV1().ManagedClusters().Create(ctx, cluster, metav1.CreateOptions{})
+	case err != nil:
+		return err
+	case cluster.Spec.LeaseDurationSeconds != leaseDurationSeconds:
+		cluster.Spec.LeaseDurationSeconds = leaseDurationSeconds
+		_, err = clusterClient.ClusterV1().ManagedClusters().Update(ctx, cluster, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set the lease duration of managed cluster %s: %v", o.clusterName, err)
+	}
+	return nil
+}
+
+func waitUntilRegistrationOperatorConditionIsTrue(ctx context.Context, f util.Factory, timeout int64) error {
 	var restConfig *rest.Config
 	restConfig, err := f.ToRESTConfig()
 	if err != nil {
@@ -221,9 +732,9 @@ func waitUntilRegistrationOperatorConditionIsTrue(f util.Factory, timeout int64)
 	phase := &atomic.Value{}
 	phase.Store("")
 	operatorSpinner := printer.NewSpinnerWithStatus(
-		"Waiting for registration operator to become ready...",
+		i18n.T("wait.registrationOperator"),
 		time.Millisecond*500,
-		"Registration operator is now available.\n",
+		i18n.T("wait.registrationOperator.done"),
 		func() string {
 			return phase.Load().(string)
 		})
@@ -233,7 +744,7 @@ func waitUntilRegistrationOperatorConditionIsTrue(f util.Factory, timeout int64)
 	return helpers.WatchUntil(
 		func() (watch.Interface, error) {
 			return client.CoreV1().Pods("open-cluster-management").
-				Watch(context.TODO(), metav1.ListOptions{
+				Watch(ctx, metav1.ListOptions{
 					TimeoutSeconds: &timeout,
 					LabelSelector:  "app=klusterlet",
 				})
@@ -258,7 +769,7 @@ func waitUntilRegistrationOperatorConditionIsTrue(f util.Factory, timeout int64)
 }
 
 // Wait until the klusterlet condition available=true, or timeout in $timeout seconds
-func waitUntilKlusterletConditionIsTrue(f util.Factory, timeout int64) error {
+func waitUntilKlusterletConditionIsTrue(ctx context.Context, f util.Factory, namespace string, timeout int64) error {
 	client, err := f.KubernetesClientSet()
 	if err != nil {
 		return err
@@ -267,9 +778,9 @@ func waitUntilKlusterletConditionIsTrue(f util.Factory, timeout int64) error {
 	phase := &atomic.Value{}
 	phase.Store("")
 	klusterletSpinner := printer.NewSpinnerWithStatus(
-		"Waiting for klusterlet agent to become ready...",
+		i18n.T("wait.klusterlet"),
 		time.Millisecond*500,
-		"Klusterlet is now available.\n",
+		i18n.T("wait.klusterlet.done"),
 		func() string {
 			return phase.Load().(string)
 		})
@@ -278,8 +789,8 @@ func waitUntilKlusterletConditionIsTrue(f util.Factory, timeout int64) error {
 
 	return helpers.WatchUntil(
 		func() (watch.Interface, error) {
-			return client.CoreV1().Pods("open-cluster-management-agent").
-				Watch(context.TODO(), metav1.ListOptions{
+			return client.CoreV1().Pods(namespace).
+				Watch(ctx, metav1.ListOptions{
 					TimeoutSeconds: &timeout,
 					LabelSelector:  "app=klusterlet-registration-agent",
 				})
@@ -304,7 +815,7 @@ func waitUntilKlusterletConditionIsTrue(f util.Factory, timeout int64) error {
 	)
 }
 
-// Create bootstrap with token but without CA
+// Create bootstrap with token but without CA, the hub certificate is verified against the trust store
 func (o *Options) createExternalBootstrapConfig() clientcmdapiv1.Config {
 	return clientcmdapiv1.Config{
 		// Define a cluster stanza based on the bootstrap kubeconfig.
@@ -312,8 +823,7 @@ func (o *Options) createExternalBootstrapConfig() clientcmdapiv1.Config {
 			{
 				Name: "hub",
 				Cluster: clientcmdapiv1.Cluster{
-					Server:                o.hubAPIServer,
-					InsecureSkipTLSVerify: true,
+					Server: o.hubAPIServer,
 				},
 			},
 		},
@@ -341,16 +851,45 @@ func (o *Options) createExternalBootstrapConfig() clientcmdapiv1.Config {
 	}
 }
 
+// createSecureBootstrapConfig creates the bootstrap config without connecting to the hub unverified, the CA is
+// read from --ca-file, fetched from the hub and verified against --ca-hash, or left to the trust store
+func (o *Options) createSecureBootstrapConfig() (*clientcmdapiv1.Config, error) {
+	bootstrapConfig := o.createExternalBootstrapConfig()
+	switch {
+	case o.HubCADate != nil:
+		bootstrapConfig.Clusters[0].Cluster.CertificateAuthorityData = o.HubCADate
+	case len(o.caHash) > 0:
+		ca, err := cahash.FetchPinnedCA(o.hubAPIServer, o.caHash, 10*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		bootstrapConfig.Clusters[0].Cluster.CertificateAuthorityData = ca
+	}
+	if o.forceHubInClusterEndpointLookup {
+		externalClient, err := helpers.CreateClientFromClientcmdapiv1Config(bootstrapConfig)
+		if err != nil {
+			return nil, err
+		}
+		if o.hubInClusterEndpoint, err = helpers.GetAPIServer(externalClient); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return &bootstrapConfig, nil
+}
+
 func (o *Options) createClientcmdapiv1Config(externalClientUnSecure *kubernetes.Clientset,
 	bootstrapExternalConfigUnSecure clientcmdapiv1.Config) (*clientcmdapiv1.Config, error) {
 	var err error
 	// set hub in cluster endpoint
-	if o.forceHubInClusterEndpointLookup {
+	hubCache := o.ClusteradmFlags.Cache()
+	if o.forceHubInClusterEndpointLookup && !hubCache.Get(o.hubAPIServer, "cluster-info-apiserver", &o.hubInClusterEndpoint) {
 		o.hubInClusterEndpoint, err = helpers.GetAPIServer(externalClientUnSecure)
 		if err != nil {
 			if !errors.IsNotFound(err) {
 				return nil, err
 			}
+		} else {
+			hubCache.Set(o.hubAPIServer, "cluster-info-apiserver", o.hubInClusterEndpoint)
 		}
 	}
 
@@ -360,13 +899,20 @@ func (o *Options) createClientcmdapiv1Config(externalClientUnSecure *kubernetes.
 	if o.HubCADate != nil {
 		// directly set ca-data if --ca-file is set
 		bootstrapConfig.Clusters[0].Cluster.CertificateAuthorityData = o.HubCADate
-	} else {
+	} else if !hubCache.Get(o.hubAPIServer, "cluster-info-ca", &bootstrapConfig.Clusters[0].Cluster.CertificateAuthorityData) {
 		// get ca data from externalClientUnsecure, ca may empty(cluster-info exists with no ca data)
 		ca, err := helpers.GetCACert(externalClientUnSecure)
 		if err != nil {
 			return nil, err
 		}
 		bootstrapConfig.Clusters[0].Cluster.CertificateAuthorityData = ca
+		hubCache.Set(o.hubAPIServer, "cluster-info-ca", ca)
+	}
+	// the CA read without verifying the hub certificate is only trusted if it matches the hashes
+	if len(o.discoveryTokenCACertHash) > 0 {
+		if err := cahash.VerifyPEM(bootstrapConfig.Clusters[0].Cluster.CertificateAuthorityData, o.discoveryTokenCACertHash); err != nil {
+			return nil, fmt.Errorf("the hub CA is not trusted: %v", err)
+		}
 	}
 
 	return bootstrapConfig, nil
@@ -376,6 +922,11 @@ func (o *Options) setKubeconfig() error {
 	// replace apiserver if the flag is set, the apiserver value should not be set
 	// to in-cluster endpoint until preflight check is finished
 	if o.forceHubInClusterEndpointLookup {
+		hubInClusterEndpoint, err := endpoint.Normalize(o.hubInClusterEndpoint)
+		if err != nil {
+			return fmt.Errorf("invalid server of the cluster-info of the hub: %v", err)
+		}
+		o.hubInClusterEndpoint = hubInClusterEndpoint
 		o.HubConfig.Clusters[0].Cluster.Server = o.hubInClusterEndpoint
 	}
 
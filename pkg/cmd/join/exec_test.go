@@ -0,0 +1,151 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stolostron/applier/pkg/apply"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join/scenario"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/cahash"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollback"
+)
+
+func TestAppliedResources(t *testing.T) {
+	resources, err := AppliedResources(DefaultKlusterletName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &rollback.Tracker{}
+	if err := tracker.Track(resources...); err != nil {
+		t.Fatal(err)
+	}
+	objects := tracker.Objects()
+	if len(objects) != len(directFiles(true, true))-1+len(agentFiles)+len(agentDeploymentFiles)+2 {
+		t.Fatalf("expected a resource per file, got %d", len(objects))
+	}
+	for _, object := range objects {
+		if object.GetKind() == "Namespace" && object.GetName() == "open-cluster-management" {
+			t.Errorf("expected the namespace shared with the cluster manager to be left")
+		}
+	}
+	// the namespaces are deleted last and the klusterlet first
+	if objects[0].GetKind() != "Namespace" || objects[len(objects)-1].GetKind() != "Klusterlet" {
+		t.Errorf("unexpected order, first %s, last %s", objects[0].GetKind(), objects[len(objects)-1].GetKind())
+	}
+}
+
+func TestKlusterletResources(t *testing.T) {
+	resources, err := KlusterletResources("hub2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &rollback.Tracker{}
+	if err := tracker.Track(resources...); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range tracker.Objects() {
+		switch {
+		case object.GetKind() == "Namespace" && object.GetName() != "open-cluster-management-hub2-agent":
+			t.Errorf("expected the agent namespace of the klusterlet, got %s", object.GetName())
+		case object.GetKind() == "Klusterlet" && object.GetName() != "hub2":
+			t.Errorf("expected the klusterlet hub2, got %s", object.GetName())
+		case object.GetKind() == "Secret" && object.GetNamespace() != "open-cluster-management-hub2-agent":
+			t.Errorf("expected the secret %s in the agent namespace of the klusterlet, got %s", object.GetName(), object.GetNamespace())
+		}
+	}
+}
+
+func TestValidateKlusterletName(t *testing.T) {
+	if AgentNamespace(DefaultKlusterletName) != "open-cluster-management-agent" {
+		t.Errorf("expected the default agent namespace, got %s", AgentNamespace(DefaultKlusterletName))
+	}
+	for _, name := range []string{DefaultKlusterletName, "hub2"} {
+		if err := ValidateKlusterletName(name); err != nil {
+			t.Errorf("unexpected error of %s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"Hub2", "a-name-long-enough-for-the-namespace-to-be-invalid"} {
+		if err := ValidateKlusterletName(name); err == nil {
+			t.Errorf("expected an error of %s", name)
+		}
+	}
+}
+
+func TestAgentFiles(t *testing.T) {
+	values := Values{
+		ClusterName:   "cluster1",
+		Klusterlet:    Klusterlet{APIServer: "https://cluster1:6443", Name: DefaultKlusterletName, Namespace: AgentNamespace(DefaultKlusterletName)},
+		Images:        Images{Registration: "registration:v0.9.1", Work: "work:v0.9.1"},
+		Architectures: []string{"amd64", "arm64"},
+	}
+	files := append(directFiles(false, false), agentFiles...)
+	files = append(files, agentDeploymentFiles...)
+	applier := apply.NewApplierBuilder().Build()
+	resources, err := applier.MustTemplateAssets(scenario.GetScenarioResourcesReader(), values, "", files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &rollback.Tracker{}
+	if err := tracker.Track(resources...); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range tracker.Objects() {
+		if object.GetKind() == "Deployment" && object.GetNamespace() != "open-cluster-management-agent" {
+			t.Errorf("expected the agent %s in the agent namespace, got %s", object.GetName(), object.GetNamespace())
+		}
+		if object.GetKind() == "Klusterlet" || object.GetName() == "klusterlet" {
+			t.Errorf("unexpected operator resource %s %s", object.GetKind(), object.GetName())
+		}
+	}
+	if len(tracker.Objects()) != len(files) {
+		t.Errorf("expected a resource per file, got %d", len(tracker.Objects()))
+	}
+}
+
+func TestCreateSecureBootstrapConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	o := &Options{hubAPIServer: server.URL, token: "abc.def", caHash: []string{cahash.Hash(server.Certificate())}}
+	config, err := o.createSecureBootstrapConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := config.Clusters[0].Cluster
+	if cluster.InsecureSkipTLSVerify || len(cluster.CertificateAuthorityData) == 0 {
+		t.Errorf("expected the pinned CA without skipping the verification, got %+v", cluster)
+	}
+
+	// the trust store verifies the hub without --ca-file and --ca-hash
+	o = &Options{hubAPIServer: server.URL, token: "abc.def"}
+	if config, err = o.createSecureBootstrapConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if cluster := config.Clusters[0].Cluster; cluster.InsecureSkipTLSVerify || len(cluster.CertificateAuthorityData) != 0 {
+		t.Errorf("expected no CA without skipping the verification, got %+v", cluster)
+	}
+}
+
+func TestDiscoveryTokenCACertHash(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	o := &Options{
+		ClusteradmFlags: &genericclioptionsclusteradm.ClusteradmFlags{},
+		hubAPIServer:    server.URL,
+		HubCADate:       caPEM,
+	}
+	o.discoveryTokenCACertHash = []string{cahash.Hash(server.Certificate())}
+	if _, err := o.createClientcmdapiv1Config(nil, o.createExternalBootstrapConfig()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	o.discoveryTokenCACertHash = []string{"sha256:0000000000000000000000000000000000000000000000000000000000000000"}
+	if _, err := o.createClientcmdapiv1Config(nil, o.createExternalBootstrapConfig()); err == nil {
+		t.Errorf("expected an error for a CA which does not match the hash")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"open-cluster-management.io/clusteradm/pkg/config"
+)
+
+// DefaultKlusterletName is the name of the klusterlet of the clusters registered to a single hub
+const DefaultKlusterletName = "klusterlet"
+
+// klusterletOperatorName is the deployment of the klusterlet operator in the open-cluster-management namespace
+const klusterletOperatorName = "klusterlet"
+
+// AgentNamespace returns the namespace of the agents of the klusterlet. The klusterlets registering the cluster
+// to other hubs run their agents in their own namespace, the operator requires the open-cluster-management-
+// prefix.
+func AgentNamespace(klusterletName string) string {
+	if klusterletName == DefaultKlusterletName {
+		return config.ManagedClusterNamespace
+	}
+	return fmt.Sprintf("open-cluster-management-%s-agent", klusterletName)
+}
+
+// ValidateKlusterletName returns an error if the klusterlet name or the name of its agent namespace is invalid
+func ValidateKlusterletName(klusterletName string) error {
+	if errs := validation.IsDNS1123Label(klusterletName); len(errs) > 0 {
+		return fmt.Errorf("invalid klusterlet name %q: %v", klusterletName, errs)
+	}
+	// the addons of the klusterlet are deployed in <agent namespace>-addon
+	if errs := validation.IsDNS1123Label(AgentNamespace(klusterletName) + "-addon"); len(errs) > 0 {
+		return fmt.Errorf("the klusterlet name %q is too long for its agent namespace: %v", klusterletName, errs)
+	}
+	return nil
+}
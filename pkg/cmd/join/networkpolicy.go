@@ -0,0 +1,152 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/stolostron/applier/pkg/apply"
+	"github.com/stolostron/applier/pkg/asset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"open-cluster-management.io/clusteradm/pkg/config"
+)
+
+var networkPolicyFiles = []string{
+	"join/network_policies/deny_all.yaml",
+	"join/network_policies/allow_egress.yaml",
+}
+
+// applyNetworkPolicies applies the deny by default network policies of the agent namespaces, the agents
+// only reach the DNS, the hub API server and the API server of the cluster
+func (o *Options) applyNetworkPolicies(ctx context.Context, kubeClient kubernetes.Interface, applier apply.Applier, reader *asset.ScenarioResourcesReader) ([]string, error) {
+	egress, err := o.egressPeers(ctx, kubeClient, net.LookupIP)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []string{o.values.Klusterlet.Namespace}
+	if !o.noOperator {
+		namespaces = append([]string{config.OpenClusterManagementNamespace}, namespaces...)
+	}
+	output := []string{}
+	for _, namespace := range namespaces {
+		values := o.values
+		values.NetworkPolicy = NetworkPolicy{Namespace: namespace, Egress: egress}
+		out, err := applier.ApplyCustomResources(reader, values, o.ClusteradmFlags.DryRun, "", networkPolicyFiles...)
+		output = append(output, out...)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// egressPeers returns the addresses of the hub API server and of the API server of the cluster
+func (o *Options) egressPeers(ctx context.Context, kubeClient kubernetes.Interface, lookup func(host string) ([]net.IP, error)) ([]EgressPeer, error) {
+	peers := []EgressPeer{}
+	servers := []string{o.hubAPIServer}
+	if len(o.hubInClusterEndpoint) > 0 {
+		servers = append(servers, o.hubInClusterEndpoint)
+	}
+	for _, server := range servers {
+		serverPeers, err := serverPeers(server, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the hub API server %s: %v", server, err)
+		}
+		peers = appendPeers(peers, serverPeers...)
+	}
+	localPeers, err := localAPIServerPeers(ctx, kubeClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the API server of the cluster: %v", err)
+	}
+	return appendPeers(peers, localPeers...), nil
+}
+
+// serverPeers returns the addresses of the host and the port of the server url
+func serverPeers(server string, lookup func(host string) ([]net.IP, error)) ([]EgressPeer, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	port := int32(443)
+	if len(u.Port()) > 0 {
+		p, err := strconv.ParseInt(u.Port(), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		port = int32(p)
+	}
+	ips := []net.IP{net.ParseIP(u.Hostname())}
+	if ips[0] == nil {
+		if ips, err = lookup(u.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	peers := []EgressPeer{}
+	for _, ip := range ips {
+		peers = append(peers, EgressPeer{CIDR: hostCIDR(ip), Port: port})
+	}
+	return peers, nil
+}
+
+// localAPIServerPeers returns the service and the endpoints of the API server of the cluster, the network
+// policies may be enforced before or after the service address is translated
+func localAPIServerPeers(ctx context.Context, kubeClient kubernetes.Interface) ([]EgressPeer, error) {
+	peers := []EgressPeer{}
+	service, err := kubeClient.CoreV1().Services(metav1.NamespaceDefault).Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(service.Spec.ClusterIP); ip != nil {
+		for _, port := range service.Spec.Ports {
+			peers = append(peers, EgressPeer{CIDR: hostCIDR(ip), Port: port.Port})
+		}
+	}
+	endpoints, err := kubeClient.CoreV1().Endpoints(metav1.NamespaceDefault).Get(ctx, "kubernetes", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return peers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			ip := net.ParseIP(address.IP)
+			if ip == nil {
+				continue
+			}
+			for _, port := range subset.Ports {
+				peers = append(peers, EgressPeer{CIDR: hostCIDR(ip), Port: port.Port})
+			}
+		}
+	}
+	return peers, nil
+}
+
+func hostCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+// appendPeers appends the peers which are not in the list yet
+func appendPeers(peers []EgressPeer, newPeers ...EgressPeer) []EgressPeer {
+	for _, newPeer := range newPeers {
+		found := false
+		for _, peer := range peers {
+			if peer == newPeer {
+				found = true
+				break
+			}
+		}
+		if !found {
+			peers = append(peers, newPeer)
+		}
+	}
+	return peers
+}
@@ -0,0 +1,77 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stolostron/applier/pkg/apply"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join/scenario"
+)
+
+func TestEgressPeers(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: metav1.NamespaceDefault},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.96.0.1", Ports: []corev1.ServicePort{{Port: 443}}},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: metav1.NamespaceDefault},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: "172.18.0.2"}},
+				Ports:     []corev1.EndpointPort{{Port: 6443}},
+			}},
+		},
+	)
+	lookup := func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.0.2.10"), net.ParseIP("2001:db8::10")}, nil
+	}
+	o := &Options{hubAPIServer: "https://hub.example.com:6443", hubInClusterEndpoint: "https://192.0.2.10:6443"}
+	peers, err := o.egressPeers(context.TODO(), kubeClient, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []EgressPeer{
+		{CIDR: "192.0.2.10/32", Port: 6443},
+		{CIDR: "2001:db8::10/128", Port: 6443},
+		{CIDR: "10.96.0.1/32", Port: 443},
+		{CIDR: "172.18.0.2/32", Port: 6443},
+	}
+	if !reflect.DeepEqual(peers, expected) {
+		t.Errorf("expected %v, got %v", expected, peers)
+	}
+}
+
+func TestServerPeersDefaultPort(t *testing.T) {
+	peers, err := serverPeers("https://192.0.2.10", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 1 || peers[0].Port != 443 {
+		t.Errorf("expected the https port, got %v", peers)
+	}
+}
+
+func TestNetworkPolicyFiles(t *testing.T) {
+	values := Values{NetworkPolicy: NetworkPolicy{
+		Namespace: "open-cluster-management-agent",
+		Egress:    []EgressPeer{{CIDR: "192.0.2.10/32", Port: 6443}},
+	}}
+	applier := apply.NewApplierBuilder().Build()
+	resources, err := applier.MustTemplateAssets(scenario.GetScenarioResourcesReader(), values, "", networkPolicyFiles...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 network policies, got %d", len(resources))
+	}
+	if !strings.Contains(resources[1], "cidr: 192.0.2.10/32") || !strings.Contains(resources[1], "port: 6443") {
+		t.Errorf("expected the egress to the hub, got %s", resources[1])
+	}
+}
@@ -2,9 +2,15 @@
 package join
 
 import (
+	"time"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/credentials"
+	"open-cluster-management.io/clusteradm/pkg/helpers/image"
+	preflightinterface "open-cluster-management.io/clusteradm/pkg/helpers/preflight"
+	"open-cluster-management.io/clusteradm/pkg/helpers/telemetry"
 )
 
 // Options: The structure holding all the command-line options
@@ -12,6 +18,8 @@ type Options struct {
 	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
 	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
 
+	Streams genericclioptions.IOStreams
+
 	//Values below are input from flags
 	//The token generated on the hub to access it from the cluster
 	token string
@@ -34,12 +42,66 @@ type Options struct {
 	// endpoint from the public cluster-info.
 	forceHubInClusterEndpointLookup bool
 	hubInClusterEndpoint            string
+	//The duration the klusterlet agent renews its lease on the hub
+	leaseDuration time.Duration
+	//The file of the image digests, the images are referenced by digests instead of tags
+	imageDigestFile string
+	//Skips the lookup of the images and their architectures in the registry
+	skipImageCheck bool
+	//Verifies the signatures of the images before deploying them
+	verifyImages image.VerifyOptions
+	//Reports the anonymized result of the command if enabled
+	telemetry telemetry.Options
+	//Writes the results of the preflight checks to a file if set
+	report preflightinterface.ReportOptions
+	//The pre-approved credentials bundle generated on the hub
+	credentialsFile string
+	//Deletes the applied resources if the join fails
+	cleanupOnFailure bool
+	//Deploys the registration and work agents directly instead of the klusterlet operator
+	noOperator bool
+	//Dials the hub from a pod of the cluster in the preflight checks
+	inClusterCheck bool
+	//The image of the pod dialing the hub
+	inClusterCheckImage string
+	//Reports the incompatible hub and agent versions as warnings instead of errors
+	force bool
+	//Never connects to the hub without verifying its certificate, the CA is read from caFile, pinned by caHash or in the trust store
+	secureBootstrap bool
+	//The sha256 hashes of the public key of the hub CA, the CA is fetched from the hub and verified against them
+	caHash []string
+	//The sha256 hashes of the public key of the hub CA, the CA read from the cluster-info of the hub is verified against them
+	discoveryTokenCACertHash []string
+	//Applies deny by default network policies to the agent namespaces
+	withNetworkPolicies bool
+	//The IP family the hub is reached over, ipv4 or ipv6, any if empty
+	preferIPFamily string
+	//The name of the klusterlet, the clusters registered to several hubs run a klusterlet per hub
+	klusterletName string
+	//Registers the cluster already registered to a hub to another hub, with the klusterlet operator of the cluster
+	additionalHub bool
+	//The QPS of the work agent to the API server of the cluster, the default of the agent if zero
+	workAgentQPS int32
+	//The burst of the work agent to the API server of the cluster, the default of the agent if zero
+	workAgentBurst int32
+	//The number of ManifestWorks the work agent applies concurrently, the default of the agent if zero
+	maxConcurrentWorkReconciles int32
+	//The duration the work agent keeps the resources of the ManifestWorks it can not find on the hub, the default of the agent if zero
+	evictionGracePeriod time.Duration
+	//The file the progress of the join is saved to
+	stateFile string
+	//The file of the interrupted join to resume, the progress is saved to it unless stateFile is set
+	resumeFrom string
 
 	//Values below are tempoary data
 	//HubCADate: data in hub ca file
 	HubCADate []byte
 	// hub config
 	HubConfig *clientcmdapiv1.Config
+	//credentials: the pre-approved credentials read from the bundle
+	credentials *credentials.Bundle
+	//state: the progress of the join, nil if it is not saved
+	state *State
 
 	//Values below are used to fill in yaml files
 	values Values
@@ -57,6 +119,46 @@ type Values struct {
 	Registry string
 	//bundle version
 	BundleVersion BundleVersion
+	//Images: the pull specs of the images
+	Images Images
+	//Architectures: the node architectures the pods are scheduled to, empty means no restriction
+	Architectures []string
+	//SeccompProfile: sets the RuntimeDefault seccomp profile of the pods, false if the cluster rejects it
+	SeccompProfile bool
+	//Credentials: the pre-approved credentials of the registration agent, empty if the cluster joins with a token
+	Credentials Credentials
+	//NetworkPolicy: the network policy of an agent namespace
+	NetworkPolicy NetworkPolicy
+}
+
+// NetworkPolicy: The network policy of an agent namespace for the template
+type NetworkPolicy struct {
+	//Namespace: the agent namespace
+	Namespace string
+	//Egress: the peers the agents are allowed to reach besides the DNS
+	Egress []EgressPeer
+}
+
+// EgressPeer: An address and a port the agents are allowed to reach
+type EgressPeer struct {
+	CIDR string
+	Port int32
+}
+
+// Credentials: The files of the hub kubeconfig secret of the registration agent for the template
+type Credentials struct {
+	ClusterName string
+	AgentName   string
+	KubeConfig  string
+	Cert        string
+	Key         string
+}
+
+// Images: The pull specs of the images for the template
+type Images struct {
+	Operator     string
+	Registration string
+	Work         string
 }
 
 // Hub: The hub values for the template
@@ -71,6 +173,21 @@ type Hub struct {
 type Klusterlet struct {
 	//APIServer: The API Server external URL
 	APIServer string
+	//Name: the name of the klusterlet
+	Name string
+	//Namespace: the namespace of the agents of the klusterlet
+	Namespace string
+	//WorkConfiguration: the tuning of the work agent, zero keeps the defaults
+	WorkConfiguration WorkConfiguration
+}
+
+// WorkConfiguration is for templating the work configuration of the klusterlet
+type WorkConfiguration struct {
+	KubeAPIQPS                  int32
+	KubeAPIBurst                int32
+	MaxConcurrentWorkReconciles int32
+	//AppliedManifestWorkEvictionGracePeriod: the duration of the grace period, empty keeps the default
+	AppliedManifestWorkEvictionGracePeriod string
 }
 
 type BundleVersion struct {
@@ -87,5 +204,6 @@ type BundleVersion struct {
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
 	return &Options{
 		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
 	}
 }
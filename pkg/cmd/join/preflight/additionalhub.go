@@ -0,0 +1,133 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	"open-cluster-management.io/clusteradm/pkg/helpers/endpoint"
+	"open-cluster-management.io/clusteradm/pkg/helpers/version"
+)
+
+const bootstrapSecretName = "bootstrap-hub-kubeconfig"
+
+// InstalledKlusterlet is a klusterlet of the cluster and the hub it registers the cluster to
+type InstalledKlusterlet struct {
+	Name      string
+	Namespace string
+	// HubAPIServer is the server of the bootstrap kubeconfig of the klusterlet, empty if it is not readable
+	HubAPIServer string
+}
+
+// AdditionalHubCheck verifies a cluster registered to a hub by a klusterlet can register to another hub with
+// a klusterlet of its own: the klusterlet operator and its CRD are installed, the klusterlet and its namespaces
+// do not exist and the cluster is not registered to the hub yet.
+type AdditionalHubCheck struct {
+	KubeClient     kubernetes.Interface
+	OperatorClient operatorclient.Interface
+	// KlusterletName and Namespace are the name and the agent namespace of the klusterlet of the hub
+	KlusterletName string
+	Namespace      string
+	HubAPIServer   string
+	AgentVersion   string
+}
+
+func (c AdditionalHubCheck) Check() (warningList []string, errorList []error) {
+	klusterlets, err := c.OperatorClient.OperatorV1().Klusterlets().List(context.TODO(), metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		return nil, []error{fmt.Errorf("the klusterlet operator is not installed, join the first hub without --additional-hub")}
+	}
+	if err != nil {
+		return nil, []error{err}
+	}
+	installed := []InstalledKlusterlet{}
+	for _, klusterlet := range klusterlets.Items {
+		namespace := klusterlet.Spec.Namespace
+		if len(namespace) == 0 {
+			namespace = config.ManagedClusterNamespace
+		}
+		installed = append(installed, InstalledKlusterlet{
+			Name:         klusterlet.Name,
+			Namespace:    namespace,
+			HubAPIServer: c.hubAPIServerOf(namespace),
+		})
+	}
+	errorList = checkAdditionalHub(c.KlusterletName, c.Namespace, c.HubAPIServer, installed, c.namespaceExists)
+
+	// the klusterlet of the hub is reconciled by the operator of the cluster, it is not upgraded by the join
+	operator, err := c.KubeClient.AppsV1().Deployments(config.OpenClusterManagementNamespace).Get(context.TODO(), "klusterlet", metav1.GetOptions{})
+	if err == nil && len(operator.Spec.Template.Spec.Containers) > 0 {
+		if operatorVersion := version.ImageVersion(operator.Spec.Template.Spec.Containers[0].Image); operatorVersion != c.AgentVersion {
+			warningList = append(warningList, fmt.Sprintf("the klusterlet operator of the cluster runs %s, the klusterlet %s is reconciled by it instead of %s",
+				operatorVersion, c.KlusterletName, c.AgentVersion))
+		}
+	}
+	return warningList, errorList
+}
+
+func (c AdditionalHubCheck) Name() string {
+	return "AdditionalHub check"
+}
+
+// hubAPIServerOf returns the server of the bootstrap kubeconfig in the namespace, empty if it is not readable
+func (c AdditionalHubCheck) hubAPIServerOf(namespace string) string {
+	secret, err := c.KubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), bootstrapSecretName, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	kubeconfig, err := clientcmd.Load(secret.Data["kubeconfig"])
+	if err != nil {
+		return ""
+	}
+	current, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !ok {
+		return ""
+	}
+	cluster, ok := kubeconfig.Clusters[current.Cluster]
+	if !ok {
+		return ""
+	}
+	if server, err := endpoint.Normalize(cluster.Server); err == nil {
+		return server
+	}
+	return cluster.Server
+}
+
+func (c AdditionalHubCheck) namespaceExists(namespace string) bool {
+	_, err := c.KubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	return err == nil
+}
+
+// checkAdditionalHub returns the collisions of the klusterlet of the hub with the klusterlets installed
+func checkAdditionalHub(name, namespace, hubAPIServer string, installed []InstalledKlusterlet, namespaceExists func(string) bool) []error {
+	if len(installed) == 0 {
+		return []error{fmt.Errorf("no klusterlet is installed, join the first hub without --additional-hub")}
+	}
+	errs := []error{}
+	for _, klusterlet := range installed {
+		switch {
+		case klusterlet.Name == name:
+			errs = append(errs, fmt.Errorf("the klusterlet %s already exists, choose another --klusterlet-name", name))
+		case klusterlet.Namespace == namespace:
+			errs = append(errs, fmt.Errorf("the namespace %s is used by the klusterlet %s", namespace, klusterlet.Name))
+		case klusterlet.HubAPIServer == hubAPIServer:
+			errs = append(errs, fmt.Errorf("the cluster is already registered to the hub %s by the klusterlet %s", hubAPIServer, klusterlet.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	// the addons of the klusterlet are deployed in <namespace>-addon
+	for _, ns := range []string{namespace, namespace + "-addon"} {
+		if namespaceExists(ns) {
+			errs = append(errs, fmt.Errorf("the namespace %s already exists, it would be shared with the agents of the klusterlet %s", ns, name))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,77 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"testing"
+)
+
+func TestCheckAdditionalHub(t *testing.T) {
+	installed := []InstalledKlusterlet{
+		{Name: "klusterlet", Namespace: "open-cluster-management-agent", HubAPIServer: "https://hub1:6443"},
+	}
+	cases := []struct {
+		name       string
+		klusterlet string
+		namespace  string
+		hub        string
+		installed  []InstalledKlusterlet
+		namespaces []string
+		expectErrs int
+	}{
+		{
+			name:       "second hub",
+			klusterlet: "dr",
+			namespace:  "open-cluster-management-dr-agent",
+			hub:        "https://hub2:6443",
+			installed:  installed,
+		},
+		{
+			name:       "no klusterlet installed",
+			klusterlet: "dr",
+			namespace:  "open-cluster-management-dr-agent",
+			hub:        "https://hub2:6443",
+			expectErrs: 1,
+		},
+		{
+			name:       "klusterlet exists",
+			klusterlet: "klusterlet",
+			namespace:  "open-cluster-management-agent",
+			hub:        "https://hub2:6443",
+			installed:  installed,
+			expectErrs: 1,
+		},
+		{
+			name:       "registered to the hub",
+			klusterlet: "dr",
+			namespace:  "open-cluster-management-dr-agent",
+			hub:        "https://hub1:6443",
+			installed:  installed,
+			expectErrs: 1,
+		},
+		{
+			name:       "namespaces exist",
+			klusterlet: "dr",
+			namespace:  "open-cluster-management-dr-agent",
+			hub:        "https://hub2:6443",
+			installed:  installed,
+			namespaces: []string{"open-cluster-management-dr-agent", "open-cluster-management-dr-agent-addon"},
+			expectErrs: 2,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			exists := func(namespace string) bool {
+				for _, ns := range c.namespaces {
+					if ns == namespace {
+						return true
+					}
+				}
+				return false
+			}
+			errs := checkAdditionalHub(c.klusterlet, c.namespace, c.hub, c.installed, exists)
+			if len(errs) != c.expectErrs {
+				t.Errorf("expected %d errors, got %v", c.expectErrs, errs)
+			}
+		})
+	}
+}
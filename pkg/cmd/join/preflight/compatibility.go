@@ -0,0 +1,76 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	"open-cluster-management.io/clusteradm/pkg/helpers/version"
+)
+
+// hubAPIs are the API versions of the hub the registration, work and addon agents use
+var hubAPIs = []string{
+	"cluster.open-cluster-management.io/v1",
+	"work.open-cluster-management.io/v1",
+	"addon.open-cluster-management.io/v1alpha1",
+	"coordination.k8s.io/v1",
+}
+
+// HubCompatibilityCheck verifies the hub serves the APIs of the agents and, if the cluster manager
+// is readable with the hub credentials, that the hub version supports the agent version. A warning is
+// returned if the hub version can not be verified.
+type HubCompatibilityCheck struct {
+	Discovery discovery.DiscoveryInterface
+	// OperatorClient reads the cluster manager, the version is not checked if it is nil
+	OperatorClient operatorclient.Interface
+	AgentVersion   string
+	// Force reports the incompatibilities as warnings
+	Force bool
+}
+
+func (c HubCompatibilityCheck) Check() (warningList []string, errorList []error) {
+	var incompatibilities []error
+	for _, gv := range hubAPIs {
+		_, err := c.Discovery.ServerResourcesForGroupVersion(gv)
+		switch {
+		case errors.IsNotFound(err):
+			incompatibilities = append(incompatibilities, fmt.Errorf("the hub does not serve %s used by the agents %s", gv, c.AgentVersion))
+		case err != nil:
+			warningList = append(warningList, fmt.Sprintf("failed to discover %s on the hub: %v", gv, err))
+		}
+	}
+
+	if c.OperatorClient != nil {
+		cm, err := c.OperatorClient.OperatorV1().ClusterManagers().Get(context.TODO(), config.ClusterManagerName, metav1.GetOptions{})
+		switch {
+		case errors.IsForbidden(err):
+			// the bootstrap credentials of clusteradm init are not allowed to read the cluster manager
+			warningList = append(warningList, fmt.Sprintf("the hub version could not be verified, the hub credentials "+
+				"are not allowed to read the cluster manager: the hub may not support the agents %s", c.AgentVersion))
+		case err != nil:
+			warningList = append(warningList, fmt.Sprintf("the hub version could not be verified, failed to read the "+
+				"cluster manager: %v: the hub may not support the agents %s", err, c.AgentVersion))
+		default:
+			if err := version.CheckSkew(version.ImageVersion(cm.Spec.RegistrationImagePullSpec), c.AgentVersion); err != nil {
+				incompatibilities = append(incompatibilities, err)
+			}
+		}
+	}
+
+	if c.Force {
+		for _, err := range incompatibilities {
+			warningList = append(warningList, err.Error())
+		}
+		return warningList, nil
+	}
+	return warningList, incompatibilities
+}
+
+func (c HubCompatibilityCheck) Name() string {
+	return "HubCompatibility check"
+}
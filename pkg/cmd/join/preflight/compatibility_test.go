@@ -0,0 +1,96 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	testinghelper "open-cluster-management.io/clusteradm/pkg/helpers/testing"
+)
+
+func TestHubCompatibilityCheck(t *testing.T) {
+	served := func(groupVersions ...string) []*metav1.APIResourceList {
+		var resources []*metav1.APIResourceList
+		for _, gv := range groupVersions {
+			resources = append(resources, &metav1.APIResourceList{GroupVersion: gv})
+		}
+		return resources
+	}
+	missingAddon := errors.New("the hub does not serve addon.open-cluster-management.io/v1alpha1 used by the agents v0.9.0")
+
+	tests := []struct {
+		name         string
+		resources    []*metav1.APIResourceList
+		force        bool
+		wantWarnings []string
+		wantErrors   []error
+	}{
+		{
+			name:      "all the APIs are served",
+			resources: served(hubAPIs...),
+		},
+		{
+			name:       "missing API",
+			resources:  served("cluster.open-cluster-management.io/v1", "work.open-cluster-management.io/v1", "coordination.k8s.io/v1"),
+			wantErrors: []error{missingAddon},
+		},
+		{
+			name:         "missing API with force",
+			resources:    served("cluster.open-cluster-management.io/v1", "work.open-cluster-management.io/v1", "coordination.k8s.io/v1"),
+			force:        true,
+			wantWarnings: []string{missingAddon.Error()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discovery := fakekube.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+			discovery.Resources = tt.resources
+			warnings, errs := HubCompatibilityCheck{
+				Discovery:    discovery,
+				AgentVersion: "v0.9.0",
+				Force:        tt.force,
+			}.Check()
+			testinghelper.AssertWarnings(t, warnings, tt.wantWarnings)
+			testinghelper.AssertErrors(t, errs, tt.wantErrors)
+		})
+	}
+}
+
+func TestHubCompatibilityCheckForbidden(t *testing.T) {
+	// the hub does not allow the bootstrap credentials to read the cluster manager
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(apierrors.NewForbidden(schema.GroupResource{Group: "operator.open-cluster-management.io",
+			Resource: "clustermanagers"}, "cluster-manager", errors.New("forbidden")).ErrStatus)
+	}))
+	defer hub.Close()
+	operatorClient, err := operatorclient.NewForConfig(&rest.Config{Host: hub.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	discovery := fakekube.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{}
+	for _, gv := range hubAPIs {
+		discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{GroupVersion: gv})
+	}
+	warnings, errs := HubCompatibilityCheck{
+		Discovery:      discovery,
+		OperatorClient: operatorClient,
+		AgentVersion:   "v0.9.0",
+	}.Check()
+	testinghelper.AssertWarnings(t, warnings, []string{"the hub version could not be verified, the hub credentials are not " +
+		"allowed to read the cluster manager: the hub may not support the agents v0.9.0"})
+	testinghelper.AssertErrors(t, errs, nil)
+}
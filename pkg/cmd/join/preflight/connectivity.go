@@ -0,0 +1,272 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"open-cluster-management.io/clusteradm/pkg/helpers/endpoint"
+	"open-cluster-management.io/clusteradm/pkg/helpers/podsecurity"
+)
+
+const (
+	dialTimeout = 10 * time.Second
+	// the clock skew above which the certificates issued by the hub may be rejected
+	maxClockSkew = time.Minute
+	// DefaultInClusterCheckImage is the image of the pod dialing the hub from the cluster
+	DefaultInClusterCheckImage = "docker.io/curlimages/curl:7.87.0"
+)
+
+// HubConnectivityCheck dials the hub api server from the machine running the join: the host is
+// resolved, a connection is opened to the port and the tls chain is validated against the hub ca.
+type HubConnectivityCheck struct {
+	Server string
+	// CAData is the ca of the hub, the chain is not validated if it is empty
+	CAData []byte
+	// Now returns the local time the date of the hub is compared to, defaults to time.Now
+	Now func() time.Time
+	// Family is the IP family the hub is dialed over, ipv4 or ipv6, any if empty
+	Family string
+}
+
+func (c HubConnectivityCheck) Check() (warningList []string, errorList []error) {
+	u, err := url.Parse(c.Server)
+	if err != nil {
+		return nil, []error{fmt.Errorf("invalid hub api server %q: %v", c.Server, err)}
+	}
+	host, port := u.Hostname(), u.Port()
+	if len(port) == 0 {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	if net.ParseIP(host) == nil {
+		if _, err := net.LookupHost(host); err != nil {
+			return nil, []error{fmt.Errorf("failed to resolve %s, check the DNS configuration: %v", host, err)}
+		}
+	}
+	if len(c.Family) > 0 {
+		if _, err := endpoint.Addresses(c.Server, c.Family, net.LookupIP); err != nil {
+			return nil, []error{fmt.Errorf("the hub is not reachable over %s: %v", c.Family, err)}
+		}
+	}
+
+	network := endpoint.Network(c.Family)
+	conn, err := net.DialTimeout(network, net.JoinHostPort(host, port), dialTimeout)
+	if err != nil {
+		return nil, []error{dialError(net.JoinHostPort(host, port), err)}
+	}
+	conn.Close()
+
+	if u.Scheme != "https" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{ServerName: host}
+	if len(c.CAData) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(c.CAData) {
+			return nil, []error{fmt.Errorf("the hub ca is not a valid PEM certificate")}
+		}
+	} else {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	client := &http.Client{
+		Timeout: dialTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			DialContext: func(ctx context.Context, _, address string) (net.Conn, error) {
+				return (&net.Dialer{Timeout: dialTimeout}).DialContext(ctx, network, address)
+			},
+		},
+	}
+	resp, err := client.Get(strings.TrimSuffix(c.Server, "/") + "/healthz")
+	if err != nil {
+		return nil, []error{c.tlsError(host, err)}
+	}
+	resp.Body.Close()
+
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		if skew := c.now().Sub(date); skew > maxClockSkew || skew < -maxClockSkew {
+			warningList = append(warningList, fmt.Sprintf("the local clock is %s off the clock of the hub, "+
+				"the certificates issued by the hub may be rejected, check the time synchronization", skew.Round(time.Second)))
+		}
+	}
+	return warningList, nil
+}
+
+func (c HubConnectivityCheck) Name() string {
+	return "HubConnectivity check"
+}
+
+func (c HubConnectivityCheck) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// dialError returns the cause of the failure to open a connection to the address
+func dialError(address string, err error) error {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("connection to %s timed out, the hub is not reachable directly, a proxy or a firewall rule may be required: %v", address, err)
+	case strings.Contains(err.Error(), "connection refused"):
+		return fmt.Errorf("connection to %s is refused, check the port of the hub api server: %v", address, err)
+	}
+	return fmt.Errorf("failed to connect to %s: %v", address, err)
+}
+
+// tlsError returns the cause of the failure of the tls handshake with the host
+func (c HubConnectivityCheck) tlsError(host string, err error) error {
+	var hostnameErr x509.HostnameError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &hostnameErr):
+		return fmt.Errorf("the certificate of the hub is not valid for %s (SNI mismatch), it is valid for %s, use one of them in --hub-apiserver",
+			host, strings.Join(certificateNames(hostnameErr.Certificate), ", "))
+	case errors.As(err, &authorityErr):
+		return fmt.Errorf("the certificate of the hub is not signed by the hub ca, a proxy may intercept the tls connections: %v", err)
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired:
+		return fmt.Errorf("the certificate of the hub is not valid at the local time %s, check the clock: %v", c.now().Format(time.RFC3339), err)
+	}
+	return fmt.Errorf("failed to connect to the hub api server %s: %v", c.Server, err)
+}
+
+func certificateNames(cert *x509.Certificate) []string {
+	if cert == nil {
+		return nil
+	}
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+// InClusterConnectivityCheck dials the hub api server from a pod of the cluster, the network of
+// the pods may differ from the one of the machine running the join.
+type InClusterConnectivityCheck struct {
+	KubeClient kubernetes.Interface
+	Server     string
+	CAData     []byte
+	// Image is the image of the pod, it runs curl
+	Image     string
+	Namespace string
+	Timeout   time.Duration
+	// PodSecurity is the pod security check of the cluster, it runs before this check and sets the
+	// security context of the pod. The pod has no security context if it is nil.
+	PodSecurity *podsecurity.Check
+}
+
+// the user of the curl image
+const inClusterCheckUser = 100
+
+// the script of the pod, the ca is passed in an environment variable
+const inClusterCheckScript = `if [ -n "$HUB_CA" ]; then
+  echo "$HUB_CA" > /tmp/hub-ca.crt
+  exec curl -sS -o /dev/null --max-time 10 --cacert /tmp/hub-ca.crt "$HUB_SERVER/healthz"
+fi
+exec curl -sS -o /dev/null --max-time 10 -k "$HUB_SERVER/healthz"`
+
+func (c InClusterConnectivityCheck) Check() (warningList []string, errorList []error) {
+	ctx := context.TODO()
+	var securityContext *corev1.SecurityContext
+	if c.PodSecurity != nil {
+		securityContext = c.PodSecurity.SecurityContext(inClusterCheckUser)
+	}
+	pod, err := c.KubeClient.CoreV1().Pods(c.Namespace).Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "clusteradm-connectivity-check-",
+			Namespace:    c.Namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "check",
+				Image:   c.Image,
+				Command: []string{"sh", "-c", inClusterCheckScript},
+				Env: []corev1.EnvVar{
+					{Name: "HUB_SERVER", Value: strings.TrimSuffix(c.Server, "/")},
+					{Name: "HUB_CA", Value: string(c.CAData)},
+				},
+				SecurityContext: securityContext,
+			}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to create the check pod: %v", err)}
+	}
+	defer func() {
+		_ = c.KubeClient.CoreV1().Pods(c.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	}()
+
+	var terminated *corev1.ContainerStateTerminated
+	err = wait.PollImmediate(2*time.Second, c.Timeout, func() (bool, error) {
+		pod, err := c.KubeClient.CoreV1().Pods(c.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil {
+				terminated = status.State.Terminated
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, []error{fmt.Errorf("the check pod %s/%s did not complete, is the image %s available in the cluster? %v",
+			c.Namespace, pod.Name, c.Image, err)}
+	}
+	if terminated.ExitCode == 0 {
+		return nil, nil
+	}
+
+	message := terminated.Message
+	if logs, err := c.KubeClient.CoreV1().Pods(c.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx); err == nil {
+		message = strings.TrimSpace(string(logs))
+	}
+	return nil, []error{curlError(terminated.ExitCode, message)}
+}
+
+func (c InClusterConnectivityCheck) Name() string {
+	return "InClusterHubConnectivity check"
+}
+
+// curlError returns the cause of the failure of curl from its exit code
+func curlError(exitCode int32, message string) error {
+	var cause string
+	switch exitCode {
+	case 5, 6:
+		cause = "the pods fail to resolve the hub, check the DNS of the cluster"
+	case 7:
+		cause = "the connection to the hub is refused from the pods, check the port of the hub api server"
+	case 28:
+		cause = "the connection to the hub timed out from the pods, a proxy or an egress rule may be required"
+	case 35:
+		cause = "the tls handshake with the hub failed from the pods, a proxy may intercept the tls connections"
+	case 51, 60:
+		cause = "the certificate of the hub is rejected from the pods, check the SNI of --hub-apiserver and the hub ca"
+	default:
+		cause = fmt.Sprintf("the pods fail to reach the hub (curl exit code %d)", exitCode)
+	}
+	return fmt.Errorf("%s: %s", cause, message)
+}
@@ -0,0 +1,125 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHubConnectivityCheck(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	// the test servers share the same certificate, the other ca is generated
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCAData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddress := listener.Addr().String()
+	listener.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	cases := []struct {
+		name            string
+		check           HubConnectivityCheck
+		expectedWarning string
+		expectedError   string
+	}{
+		{
+			name:  "reachable",
+			check: HubConnectivityCheck{Server: server.URL, CAData: caData},
+		},
+		{
+			name:  "without ca",
+			check: HubConnectivityCheck{Server: server.URL},
+		},
+		{
+			name:          "sni mismatch",
+			check:         HubConnectivityCheck{Server: "https://localhost:" + port, CAData: caData},
+			expectedError: "SNI mismatch",
+		},
+		{
+			name:          "unknown authority",
+			check:         HubConnectivityCheck{Server: server.URL, CAData: otherCAData},
+			expectedError: "not signed by the hub ca",
+		},
+		{
+			name:          "refused",
+			check:         HubConnectivityCheck{Server: "https://" + closedAddress},
+			expectedError: "refused",
+		},
+		{
+			name:  "ipv4",
+			check: HubConnectivityCheck{Server: server.URL, CAData: caData, Family: "ipv4"},
+		},
+		{
+			name:          "no ipv6 address",
+			check:         HubConnectivityCheck{Server: server.URL, CAData: caData, Family: "ipv6"},
+			expectedError: "not reachable over ipv6",
+		},
+		{
+			name: "clock skew",
+			check: HubConnectivityCheck{Server: server.URL, CAData: caData, Now: func() time.Time {
+				return time.Now().Add(time.Hour)
+			}},
+			expectedWarning: "off the clock of the hub",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			warnings, errs := c.check.Check()
+			if len(c.expectedWarning) == 0 && len(warnings) > 0 || len(c.expectedWarning) > 0 && (len(warnings) != 1 || !strings.Contains(warnings[0], c.expectedWarning)) {
+				t.Errorf("expected warning %q, got %v", c.expectedWarning, warnings)
+			}
+			if len(c.expectedError) == 0 && len(errs) > 0 || len(c.expectedError) > 0 && (len(errs) != 1 || !strings.Contains(errs[0].Error(), c.expectedError)) {
+				t.Errorf("expected error %q, got %v", c.expectedError, errs)
+			}
+		})
+	}
+}
+
+func TestCurlError(t *testing.T) {
+	cases := map[int32]string{
+		6:  "resolve",
+		7:  "refused",
+		28: "timed out",
+		60: "rejected",
+		99: "exit code 99",
+	}
+	for code, expected := range cases {
+		if err := curlError(code, "curl: failed"); !strings.Contains(err.Error(), expected) || !strings.Contains(err.Error(), "curl: failed") {
+			t.Errorf("exit code %d: expected %q, got %v", code, expected, err)
+		}
+	}
+}
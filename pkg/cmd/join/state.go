@@ -0,0 +1,214 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollback"
+)
+
+// The phases of the join recorded in the state file, in order
+const (
+	phasePreflight       = "preflight"
+	phaseResources       = "resources"
+	phaseNetworkPolicies = "network-policies"
+	phaseAgents          = "agents"
+	phaseOperator        = "operator"
+	phaseKlusterlet      = "klusterlet"
+	phaseLeaseDuration   = "lease-duration"
+)
+
+// State is the progress of a join persisted to a local file, an interrupted join resumed from the file
+// skips its completed phases. The hub credentials are never written to the file.
+type State struct {
+	//ClusterName, HubAPIServer and KlusterletName identify the join, a join is only resumed with the same ones
+	ClusterName    string `json:"clusterName"`
+	HubAPIServer   string `json:"hubAPIServer"`
+	KlusterletName string `json:"klusterletName"`
+	//Architectures and SeccompProfile are the results of the preflight checks, which are not run again
+	Architectures  []string `json:"architectures,omitempty"`
+	SeccompProfile bool     `json:"seccompProfile,omitempty"`
+	//Phases: the completed phases in order
+	Phases []string `json:"phases,omitempty"`
+	//Resources: the resources applied in order, including the ones of the failed phases
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+// Resource identifies an applied resource
+type Resource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// LoadState reads the state file
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the join state %s: %v", path, err)
+	}
+	state := &State{}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid join state %s: %v", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the state file, the file is replaced at once so a reboot never leaves it half written
+func (s *State) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Completed returns true if the phase is completed, no phase is completed without state
+func (s *State) Completed(phase string) bool {
+	if s == nil {
+		return false
+	}
+	for _, p := range s.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// complete records the completed phase
+func (s *State) complete(phase string) {
+	if !s.Completed(phase) {
+		s.Phases = append(s.Phases, phase)
+	}
+}
+
+// record sets the applied resources from the tracked ones, a resource applied again is recorded once
+func (s *State) record(objects []*unstructured.Unstructured) {
+	seen := map[Resource]bool{}
+	s.Resources = nil
+	for _, obj := range objects {
+		r := Resource{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		s.Resources = append(s.Resources, r)
+	}
+}
+
+// manifests returns the applied resources in the format of the applier output, so the resources of the
+// interrupted joins are tracked too
+func (s *State) manifests() ([]string, error) {
+	var manifests []string
+	for _, r := range s.Resources {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(r.APIVersion)
+		obj.SetKind(r.Kind)
+		obj.SetNamespace(r.Namespace)
+		obj.SetName(r.Name)
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, string(data))
+	}
+	return manifests, nil
+}
+
+// completeState loads the state of the resumed join, or starts a new one
+func (o *Options) completeState() error {
+	if len(o.stateFile) == 0 {
+		o.stateFile = o.resumeFrom
+	}
+	if len(o.stateFile) == 0 {
+		return nil
+	}
+	if len(o.resumeFrom) == 0 {
+		if _, err := os.Stat(o.stateFile); err == nil {
+			return fmt.Errorf("the join state %s already exists, set --resume-from %s to resume the join", o.stateFile, o.stateFile)
+		}
+		o.state = &State{ClusterName: o.clusterName, HubAPIServer: o.hubAPIServer, KlusterletName: o.klusterletName}
+		return nil
+	}
+
+	state, err := LoadState(o.resumeFrom)
+	if err != nil {
+		return err
+	}
+	if state.ClusterName != o.clusterName || state.HubAPIServer != o.hubAPIServer || state.KlusterletName != o.klusterletName {
+		return fmt.Errorf("the join state %s is the join of cluster %s to hub %s by klusterlet %s, not of cluster %s to hub %s by klusterlet %s",
+			o.resumeFrom, state.ClusterName, state.HubAPIServer, state.KlusterletName, o.clusterName, o.hubAPIServer, o.klusterletName)
+	}
+	o.state = state
+	return nil
+}
+
+// phase runs a phase of the join unless it is completed, then saves the state with the resources it applied
+func (o *Options) phase(name string, tracker *rollback.Tracker, run func() error) error {
+	if o.state.Completed(name) {
+		fmt.Fprintf(o.Streams.Out, "The %s phase is completed, skipping it\n", name)
+		return nil
+	}
+	err := run()
+	if o.state == nil || o.ClusteradmFlags.DryRun {
+		return err
+	}
+	if err == nil {
+		o.state.complete(name)
+	}
+	if tracker != nil {
+		o.state.record(tracker.Objects())
+	}
+	if saveErr := o.state.Save(o.stateFile); saveErr != nil {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to save the join state %s: %v", o.stateFile, saveErr)
+	}
+	return err
+}
+
+// trackState tracks the resources applied by the interrupted joins
+func (o *Options) trackState(tracker *rollback.Tracker) error {
+	if o.state == nil {
+		return nil
+	}
+	manifests, err := o.state.manifests()
+	if err != nil {
+		return err
+	}
+	return tracker.Track(manifests...)
+}
+
+// removeState deletes the state file once the applied resources are cleaned up, the next join starts over
+func (o *Options) removeState() {
+	if o.state == nil {
+		return
+	}
+	if err := os.Remove(o.stateFile); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(o.Streams.ErrOut, "failed to delete the join state %s: %v\n", o.stateFile, err)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollback"
+)
+
+func TestStateSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "join.state")
+	state := &State{
+		ClusterName:    "cluster1",
+		HubAPIServer:   "https://hub:6443",
+		KlusterletName: "klusterlet",
+		Architectures:  []string{"arm64"},
+		Phases:         []string{phasePreflight, phaseResources},
+		Resources:      []Resource{{APIVersion: "v1", Kind: "Namespace", Name: "open-cluster-management"}},
+	}
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(state, loaded) {
+		t.Errorf("expected %v, got %v", state, loaded)
+	}
+	if !loaded.Completed(phaseResources) || loaded.Completed(phaseKlusterlet) {
+		t.Errorf("unexpected completed phases %v", loaded.Phases)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected the temporary file to be removed, got %d files", len(entries))
+	}
+}
+
+func TestCompleteState(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "join.state")
+	if err := (&State{ClusterName: "cluster1", HubAPIServer: "https://hub:6443", KlusterletName: "klusterlet",
+		Phases: []string{phasePreflight}}).Save(existing); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name       string
+		stateFile  string
+		resumeFrom string
+		cluster    string
+		phases     []string
+		err        string
+	}{
+		{name: "no state", cluster: "cluster1"},
+		{name: "new state", stateFile: filepath.Join(dir, "new.state"), cluster: "cluster1", phases: []string{}},
+		{name: "existing state", stateFile: existing, cluster: "cluster1", err: "--resume-from"},
+		{name: "resume", resumeFrom: existing, cluster: "cluster1", phases: []string{phasePreflight}},
+		{name: "resume another cluster", resumeFrom: existing, cluster: "cluster2", err: "not of cluster cluster2"},
+		{name: "resume missing state", resumeFrom: filepath.Join(dir, "missing.state"), cluster: "cluster1", err: "failed to read"},
+	}
+	for _, c := range cases {
+		o := &Options{stateFile: c.stateFile, resumeFrom: c.resumeFrom, clusterName: c.cluster,
+			hubAPIServer: "https://hub:6443", klusterletName: "klusterlet"}
+		err := o.completeState()
+		switch {
+		case len(c.err) == 0 && err != nil:
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		case len(c.err) > 0 && (err == nil || !strings.Contains(err.Error(), c.err)):
+			t.Errorf("%s: expected an error with %q, got %v", c.name, c.err, err)
+		case err != nil:
+		case c.phases == nil && o.state != nil:
+			t.Errorf("%s: expected no state, got %v", c.name, o.state)
+		case c.phases != nil && (o.state == nil || len(o.state.Phases) != len(c.phases)):
+			t.Errorf("%s: expected the phases %v, got %v", c.name, c.phases, o.state)
+		}
+	}
+}
+
+func TestPhase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "join.state")
+	out := &bytes.Buffer{}
+	o := &Options{
+		ClusteradmFlags: genericclioptionsclusteradm.NewClusteradmFlags(nil),
+		Streams:         genericclioptions.IOStreams{Out: out, ErrOut: out},
+		stateFile:       path,
+		state:           &State{ClusterName: "cluster1"},
+	}
+	tracker := &rollback.Tracker{}
+	namespace := `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"open-cluster-management"}}`
+
+	if err := o.phase(phaseResources, tracker, func() error {
+		return tracker.Track(namespace)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// the failed phase records its resources but is not completed
+	if err := o.phase(phaseOperator, tracker, func() error {
+		if err := tracker.Track(namespace, `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"namespace":"open-cluster-management","name":"klusterlet"}}`); err != nil {
+			return err
+		}
+		return fmt.Errorf("connection refused")
+	}); err == nil {
+		t.Fatalf("expected the error of the phase")
+	}
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(state.Phases, []string{phaseResources}) {
+		t.Errorf("expected only the resources phase to be completed, got %v", state.Phases)
+	}
+	if len(state.Resources) != 2 || state.Resources[1].Kind != "Deployment" {
+		t.Errorf("expected the namespace and the deployment to be recorded once, got %v", state.Resources)
+	}
+
+	// the resumed join skips the completed phase and tracks the recorded resources
+	o.state = state
+	if err := o.phase(phaseResources, tracker, func() error {
+		t.Errorf("expected the completed phase to be skipped")
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resumed := &rollback.Tracker{}
+	if err := o.trackState(resumed); err != nil {
+		t.Fatal(err)
+	}
+	if objects := resumed.Objects(); len(objects) != 2 || objects[1].GetName() != "klusterlet" || objects[1].GetNamespace() != "open-cluster-management" {
+		t.Errorf("unexpected tracked resources %v", objects)
+	}
+}
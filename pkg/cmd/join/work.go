@@ -0,0 +1,64 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// validateWorkConfiguration checks the tuning of the work agent and fills its values, zero keeps
+// the defaults of the work agent
+func (o *Options) validateWorkConfiguration() error {
+	if o.workAgentQPS < 0 || o.workAgentBurst < 0 || o.maxConcurrentWorkReconciles < 0 {
+		return fmt.Errorf("--work-agent-qps, --work-agent-burst and --max-concurrent-work-reconciles should not be negative")
+	}
+	if o.workAgentQPS > 0 && o.workAgentBurst > 0 && o.workAgentBurst < o.workAgentQPS {
+		return fmt.Errorf("--work-agent-burst %d should not be lower than --work-agent-qps %d", o.workAgentBurst, o.workAgentQPS)
+	}
+	if err := ValidateEvictionGracePeriod(o.evictionGracePeriod); err != nil {
+		return err
+	}
+	tuned := o.workAgentQPS > 0 || o.workAgentBurst > 0 || o.maxConcurrentWorkReconciles > 0 || o.evictionGracePeriod > 0
+	// the agents deployed directly run with the flags of the bundle version
+	if tuned && o.noOperator {
+		return fmt.Errorf("--work-agent-qps, --work-agent-burst, --max-concurrent-work-reconciles and " +
+			"--appliedmanifestwork-eviction-grace-period are not supported with --no-operator")
+	}
+	o.values.Klusterlet.WorkConfiguration = WorkConfiguration{
+		KubeAPIQPS:                  o.workAgentQPS,
+		KubeAPIBurst:                o.workAgentBurst,
+		MaxConcurrentWorkReconciles: o.maxConcurrentWorkReconciles,
+	}
+	if o.evictionGracePeriod > 0 {
+		o.values.Klusterlet.WorkConfiguration.AppliedManifestWorkEvictionGracePeriod = o.evictionGracePeriod.String()
+	}
+	return nil
+}
+
+// ValidateEvictionGracePeriod checks the duration the work agent keeps the resources of the ManifestWorks
+// it can not find on the hub before evicting them, zero keeps the default of the agent
+func ValidateEvictionGracePeriod(period time.Duration) error {
+	if period < 0 || period%time.Second != 0 {
+		return fmt.Errorf("--appliedmanifestwork-eviction-grace-period should be a positive number of seconds")
+	}
+	return nil
+}
+
+// WorkConfigurationOf returns the tuning of the work agent of the klusterlet, the typed klusterlet of the
+// vendored API does not have the fields
+func WorkConfigurationOf(klusterlet *unstructured.Unstructured) WorkConfiguration {
+	gracePeriod, _, _ := unstructured.NestedString(klusterlet.Object, "spec", "workConfiguration", "appliedManifestWorkEvictionGracePeriod")
+	field := func(name string) int32 {
+		value, _, _ := unstructured.NestedInt64(klusterlet.Object, "spec", "workConfiguration", name)
+		return int32(value)
+	}
+	return WorkConfiguration{
+		KubeAPIQPS:                  field("kubeAPIQPS"),
+		KubeAPIBurst:                field("kubeAPIBurst"),
+		MaxConcurrentWorkReconciles: field("maxConcurrentWorkReconciles"),
+
+		AppliedManifestWorkEvictionGracePeriod: gracePeriod,
+	}
+}
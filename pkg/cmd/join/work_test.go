@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stolostron/applier/pkg/apply"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join/scenario"
+	"sigs.k8s.io/yaml"
+)
+
+func TestValidateWorkConfiguration(t *testing.T) {
+	cases := []struct {
+		name    string
+		options Options
+		err     string
+	}{
+		{name: "defaults", options: Options{}},
+		{name: "tuned", options: Options{workAgentQPS: 50, workAgentBurst: 100, maxConcurrentWorkReconciles: 20}},
+		{name: "negative", options: Options{workAgentQPS: -1}, err: "should not be negative"},
+		{name: "burst lower than qps", options: Options{workAgentQPS: 50, workAgentBurst: 10}, err: "should not be lower"},
+		{name: "no operator", options: Options{noOperator: true, maxConcurrentWorkReconciles: 20}, err: "--no-operator"},
+		{name: "grace period", options: Options{evictionGracePeriod: time.Hour}},
+		{name: "sub-second grace period", options: Options{evictionGracePeriod: 1500 * time.Millisecond}, err: "number of seconds"},
+	}
+	for _, c := range cases {
+		err := c.options.validateWorkConfiguration()
+		switch {
+		case len(c.err) == 0 && err != nil:
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		case len(c.err) > 0 && (err == nil || !strings.Contains(err.Error(), c.err)):
+			t.Errorf("%s: expected an error with %q, got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestKlusterletWorkConfiguration(t *testing.T) {
+	applier := apply.NewApplierBuilder().Build()
+	reader := scenario.GetScenarioResourcesReader()
+	render := func(work WorkConfiguration) map[string]interface{} {
+		values := Values{ClusterName: "cluster1", Klusterlet: Klusterlet{Name: "klusterlet", WorkConfiguration: work}}
+		data, err := applier.MustTemplateAsset(reader, values, "", "join/klusterlets.cr.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		klusterlet := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &klusterlet); err != nil {
+			t.Fatal(err)
+		}
+		return klusterlet["spec"].(map[string]interface{})
+	}
+
+	if _, ok := render(WorkConfiguration{})["workConfiguration"]; ok {
+		t.Errorf("expected no workConfiguration by default")
+	}
+	work, ok := render(WorkConfiguration{KubeAPIQPS: 50, MaxConcurrentWorkReconciles: 20, AppliedManifestWorkEvictionGracePeriod: "1h0m0s"})["workConfiguration"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the workConfiguration to be set")
+	}
+	if work["kubeAPIQPS"] != float64(50) || work["maxConcurrentWorkReconciles"] != float64(20) ||
+		work["appliedManifestWorkEvictionGracePeriod"] != "1h0m0s" {
+		t.Errorf("unexpected workConfiguration %v", work)
+	}
+	if _, ok := work["kubeAPIBurst"]; ok {
+		t.Errorf("expected the burst to be left to the default, got %v", work)
+	}
+}
+
+func TestWorkConfigurationOf(t *testing.T) {
+	klusterlet := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"workConfiguration": map[string]interface{}{
+				"kubeAPIQPS":                             int64(50),
+				"maxConcurrentWorkReconciles":            int64(20),
+				"appliedManifestWorkEvictionGracePeriod": "30m",
+			},
+		},
+	}}
+	expected := WorkConfiguration{KubeAPIQPS: 50, MaxConcurrentWorkReconciles: 20, AppliedManifestWorkEvictionGracePeriod: "30m"}
+	if work := WorkConfigurationOf(klusterlet); work != expected {
+		t.Errorf("expected %v, got %v", expected, work)
+	}
+}
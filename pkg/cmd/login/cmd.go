@@ -0,0 +1,60 @@
+// Copyright Contributors to the Open Cluster Management project
+package login
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+)
+
+var example = `
+# Log in to a hub fronted by an SSO, the hub connection is named after its host and becomes the current hub
+%[1]s login --hub https://api.hub.example.com:6443 --oidc-issuer https://sso.example.com/realms/ocm --ca-file hub-ca.crt
+
+# Log in with a confidential client and name the hub connection
+%[1]s login prod --hub https://api.hub.example.com:6443 --oidc-issuer https://sso.example.com/realms/ocm --oidc-client-id clusteradm --oidc-client-secret <secret>
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "login [NAME]",
+		Short: "log in to a hub with an OIDC issuer",
+		Long: "log in to a hub fronted by an OIDC issuer with the device authorization flow: the code printed is entered " +
+			"in the browser, then the tokens are stored in a kubeconfig and the hub connection NAME is added, the host of " +
+			"the hub if not set. The id token is refreshed with the refresh token by the following commands targeting the hub.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		// the command adds a hub, it does not target one
+		Annotations: map[string]string{hubs.SkipAnnotation: ""},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.server, "hub", "", "The url of the API server of the hub")
+	cmd.Flags().StringVar(&o.caFile, "ca-file", "", "The CA of the API server of the hub, the system trust store is used if not set")
+	cmd.Flags().StringVar(&o.issuer, "oidc-issuer", "", "The url of the OIDC issuer the API server of the hub trusts")
+	cmd.Flags().StringVar(&o.clientID, "oidc-client-id", "clusteradm", "The id of the OIDC client, it must be allowed the device authorization grant")
+	cmd.Flags().StringVar(&o.clientSecret, "oidc-client-secret", "", "The secret of the OIDC client, empty for a public client")
+	cmd.Flags().StringSliceVar(&o.scopes, "oidc-scopes", []string{"openid", "offline_access"},
+		"The scopes requested, offline_access is required to refresh the id token without logging in again")
+	cmd.Flags().StringVar(&o.issuerCAFile, "oidc-ca-file", "", "The CA of the OIDC issuer, the system trust store is used if not set")
+	cmd.Flags().BoolVar(&o.use, "use", true, "If set, the hub becomes the current hub")
+
+	return cmd
+}
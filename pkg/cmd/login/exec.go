@@ -0,0 +1,157 @@
+// Copyright Contributors to the Open Cluster Management project
+package login
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+	"open-cluster-management.io/clusteradm/pkg/helpers/oidc"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("login options:", "hub", o.server, "oidc-issuer", o.issuer, "oidc-client-id", o.clientID, "oidc-scopes", o.scopes)
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	if len(o.name) == 0 && len(o.server) > 0 {
+		if u, err := url.Parse(o.server); err == nil {
+			o.name = u.Hostname()
+		}
+	}
+	if len(o.caFile) > 0 {
+		if o.ca, err = os.ReadFile(o.caFile); err != nil {
+			return err
+		}
+	}
+	if len(o.issuerCAFile) > 0 {
+		if o.issuerCA, err = os.ReadFile(o.issuerCAFile); err != nil {
+			return err
+		}
+	}
+	o.path, err = hubs.DefaultPath()
+	return err
+}
+
+func (o *Options) validate() error {
+	for name, value := range map[string]string{"--hub": o.server, "--oidc-issuer": o.issuer} {
+		u, err := url.Parse(value)
+		if len(value) == 0 || err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+			return fmt.Errorf("%s must be an https url", name)
+		}
+	}
+	if len(o.name) == 0 {
+		return fmt.Errorf("the name of the hub is missing")
+	}
+	if len(o.clientID) == 0 {
+		return fmt.Errorf("--oidc-client-id is required")
+	}
+	hasOpenID := false
+	for _, scope := range o.scopes {
+		hasOpenID = hasOpenID || scope == "openid"
+	}
+	if !hasOpenID {
+		return fmt.Errorf("--oidc-scopes must include openid")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	client := &oidc.Client{ID: o.clientID, Secret: o.clientSecret, Scopes: o.scopes}
+	if len(o.issuerCA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(o.issuerCA) {
+			return fmt.Errorf("no certificate is found in %s", o.issuerCAFile)
+		}
+		client.HTTP = &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		}}
+	}
+
+	ctx := context.Background()
+	provider, err := client.Discover(ctx, o.issuer)
+	if err != nil {
+		return err
+	}
+	auth, err := client.Authorize(ctx, provider)
+	if err != nil {
+		return err
+	}
+	if len(auth.VerificationURIComplete) > 0 {
+		fmt.Fprintf(o.Streams.Out, "To log in, open %s in a browser and check the code %s\n", auth.VerificationURIComplete, auth.UserCode)
+	} else {
+		fmt.Fprintf(o.Streams.Out, "To log in, open %s in a browser and enter the code %s\n", auth.VerificationURI, auth.UserCode)
+	}
+	token, err := client.PollToken(ctx, provider, auth)
+	if err != nil {
+		return err
+	}
+
+	kubeconfigPath := hubs.KubeconfigPath(o.path, o.name)
+	if err := os.MkdirAll(filepath.Dir(kubeconfigPath), 0700); err != nil {
+		return err
+	}
+	if err := clientcmd.WriteToFile(*o.kubeconfig(client, token), kubeconfigPath); err != nil {
+		return err
+	}
+	// the kubeconfig holds the tokens, only the user can read it
+	if err := os.Chmod(kubeconfigPath, 0600); err != nil {
+		return err
+	}
+
+	config, err := hubs.Load(o.path)
+	if err != nil {
+		return err
+	}
+	hub := hubs.Hub{Name: o.name, Kubeconfig: kubeconfigPath, Context: o.name, Issuer: o.issuer}
+	// the defaults of the hub are kept when logging in again
+	if existing := config.Get(o.name); existing != nil {
+		hub.ClusterSet, hub.Registry = existing.ClusterSet, existing.Registry
+	}
+	config.Set(hub)
+	if o.use {
+		config.Current = o.name
+	}
+	if err := config.Save(o.path); err != nil {
+		return err
+	}
+
+	subject := oidc.Subject(token.IDToken)
+	if len(subject) == 0 {
+		subject = "unknown user"
+	}
+	fmt.Fprintf(o.Streams.Out, "Logged in to hub %s as %s, the credentials are stored in %s\n", o.name, subject, kubeconfigPath)
+	if len(token.RefreshToken) == 0 {
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: the issuer returned no refresh token, log in again when the id token expires\n")
+	}
+	return nil
+}
+
+// kubeconfig returns the kubeconfig of the hub with the oidc auth provider logged in with the token
+func (o *Options) kubeconfig(client *oidc.Client, token *oidc.Token) *clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+	config.Clusters[o.name] = &clientcmdapi.Cluster{
+		Server:                   o.server,
+		CertificateAuthorityData: o.ca,
+	}
+	config.AuthInfos[o.name] = &clientcmdapi.AuthInfo{
+		AuthProvider: &clientcmdapi.AuthProviderConfig{
+			Name:   "oidc",
+			Config: oidc.AuthProviderConfig(o.issuer, client, o.issuerCA, token),
+		},
+	}
+	config.Contexts[o.name] = &clientcmdapi.Context{Cluster: o.name, AuthInfo: o.name}
+	config.CurrentContext = o.name
+	return config
+}
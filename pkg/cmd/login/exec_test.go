@@ -0,0 +1,27 @@
+// Copyright Contributors to the Open Cluster Management project
+package login
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"open-cluster-management.io/clusteradm/pkg/helpers/oidc"
+)
+
+func TestKubeconfig(t *testing.T) {
+	o := &Options{name: "hub.example.com", server: "https://hub.example.com:6443", ca: []byte("ca"), issuer: "https://sso.example.com"}
+	client := &oidc.Client{ID: "clusteradm"}
+	config := o.kubeconfig(client, &oidc.Token{IDToken: "id", RefreshToken: "refresh"})
+
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restConfig.Host != o.server || string(restConfig.CAData) != "ca" {
+		t.Errorf("unexpected server %s and ca %s", restConfig.Host, restConfig.CAData)
+	}
+	if provider := restConfig.AuthProvider; provider == nil || provider.Name != "oidc" ||
+		provider.Config["id-token"] != "id" || provider.Config["refresh-token"] != "refresh" || provider.Config["idp-issuer-url"] != o.issuer {
+		t.Errorf("unexpected auth provider %#v", provider)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright Contributors to the Open Cluster Management project
+package login
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	Streams         genericclioptions.IOStreams
+	//The name of the hub connection, the host of the hub if not set
+	name string
+	//The url of the API server of the hub
+	server string
+	//The CA of the API server of the hub
+	caFile string
+	ca     []byte
+	//The url of the OIDC issuer
+	issuer string
+	//The OIDC client and the scopes requested
+	clientID     string
+	clientSecret string
+	scopes       []string
+	//The CA of the OIDC issuer
+	issuerCAFile string
+	issuerCA     []byte
+	//If true the hub becomes the current hub
+	use bool
+	//The file of the hubs
+	path string
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright Contributors to the Open Cluster Management project
+package patch
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/patch/work"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the commands updating existing resources
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "update fields of a resource",
+	}
+
+	cmd.AddCommand(work.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
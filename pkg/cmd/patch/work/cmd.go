@@ -0,0 +1,68 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"fmt"
+	"strings"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Roll out the manifestwork work1 to 2 clusters at a time
+%[1]s patch work work1 --rollout-strategy Progressive --max-concurrency 2
+# Roll out the manifestwork work1 to a region at a time
+%[1]s patch work work1 --rollout-strategy ProgressivePerGroup --group-label region
+# Pause and resume the rollout of the manifestwork work1
+%[1]s patch work work1 --pause
+%[1]s patch work work1 --resume
+# Pause the rollout of the placemanifestwork work1 in the namespace default
+%[1]s patch work work1 --place-manifestwork --namespace default --pause
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "work",
+		Short: "update the rollout strategy of a work",
+		Long: "update the rollout strategy of the manifestworks of a name in the cluster namespaces, or of a placemanifestwork, " +
+			"and pause or resume their rollout. The strategy is honored by the clusteradm commands rolling out the works to the clusters.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.clusters, "clusters", []string{}, "Names of the managed clusters (comma separated) whose manifestwork is updated, defaults to all the clusters")
+	cmd.Flags().BoolVar(&o.placeManifestWork, "place-manifestwork", false, "Update the placemanifestwork of the name in the namespace instead of the manifestworks")
+	cmd.Flags().StringVar(&o.strategy, "rollout-strategy", "", fmt.Sprintf("The rollout strategy, one of %s", strings.Join(rollout.Types, ", ")))
+	cmd.Flags().StringVar(&o.maxConcurrency, "max-concurrency", "", "The number or percentage of clusters rolled out at a time by the progressive strategies, empty to unset")
+	cmd.Flags().StringVar(&o.groupLabel, "group-label", "", "The label of the clusters grouping them for the ProgressivePerGroup strategy, defaults to the clusterset label")
+	cmd.Flags().BoolVar(&o.pause, "pause", false, "Pause the rollout to the clusters not rolled out yet")
+	cmd.Flags().BoolVar(&o.resume, "resume", false, "Resume the paused rollout")
+
+	return cmd
+}
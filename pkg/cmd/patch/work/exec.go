@@ -0,0 +1,159 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	if len(args) != 1 {
+		return fmt.Errorf("the name of the work must be specified")
+	}
+	o.workName = args[0]
+
+	klog.V(1).InfoS("patch work options:", "work", o.workName, "clusters", o.clusters, "place-manifestwork", o.placeManifestWork,
+		"rollout-strategy", o.strategy, "max-concurrency", o.maxConcurrency, "group-label", o.groupLabel, "pause", o.pause, "resume", o.resume)
+
+	set := func(flag, annotation, value string) {
+		if !cmd.Flags().Changed(flag) {
+			return
+		}
+		if len(value) == 0 {
+			o.annotations[annotation] = nil
+			return
+		}
+		o.annotations[annotation] = value
+	}
+	set("rollout-strategy", rollout.StrategyAnnotation, o.strategy)
+	set("max-concurrency", rollout.MaxConcurrencyAnnotation, o.maxConcurrency)
+	set("group-label", rollout.GroupLabelAnnotation, o.groupLabel)
+	if o.pause {
+		o.annotations[rollout.PausedAnnotation] = "true"
+	}
+	if o.resume {
+		o.annotations[rollout.PausedAnnotation] = nil
+	}
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if o.pause && o.resume {
+		return fmt.Errorf("--pause and --resume can not be set together")
+	}
+	if len(o.annotations) == 0 {
+		return fmt.Errorf("at least one of --rollout-strategy, --max-concurrency, --group-label, --pause or --resume must be set")
+	}
+	if o.placeManifestWork && len(o.clusters) > 0 {
+		return fmt.Errorf("--clusters can not be set with --place-manifestwork")
+	}
+	// validate the fields set, the other fields are validated with the annotations of each work
+	_, err = rollout.FromAnnotations(o.patchedAnnotations(nil))
+	return err
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	return o.runWithClient(workClient)
+}
+
+func (o *Options) runWithClient(workClient workclientset.Interface) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": o.annotations},
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.placeManifestWork {
+		namespace, _, err := o.ClusteradmFlags.KubectlFactory.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return err
+		}
+		work, err := workClient.WorkV1alpha1().PlaceManifestWorks(namespace).Get(context.TODO(), o.workName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if _, err := rollout.FromAnnotations(o.patchedAnnotations(work.Annotations)); err != nil {
+			return fmt.Errorf("placemanifestwork %s/%s: %v", namespace, o.workName, err)
+		}
+		if o.ClusteradmFlags.DryRun {
+			fmt.Fprintf(o.Streams.Out, "placemanifestwork %s/%s would be patched\n", namespace, o.workName)
+			return nil
+		}
+		if _, err := workClient.WorkV1alpha1().PlaceManifestWorks(namespace).Patch(context.TODO(), o.workName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "placemanifestwork %s/%s is patched\n", namespace, o.workName)
+		return nil
+	}
+
+	works, err := workClient.WorkV1().ManifestWorks(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("metadata.name=%s", o.workName),
+	})
+	if err != nil {
+		return err
+	}
+	clusters := sets.NewString(o.clusters...)
+	patched := 0
+	for _, work := range works.Items {
+		if work.Name != o.workName || (clusters.Len() > 0 && !clusters.Has(work.Namespace)) {
+			continue
+		}
+		if _, err := rollout.FromAnnotations(o.patchedAnnotations(work.Annotations)); err != nil {
+			return fmt.Errorf("manifestwork %s in cluster %s: %v", o.workName, work.Namespace, err)
+		}
+		patched++
+		if o.ClusteradmFlags.DryRun {
+			fmt.Fprintf(o.Streams.Out, "manifestwork %s in cluster %s would be patched\n", o.workName, work.Namespace)
+			continue
+		}
+		if _, err := workClient.WorkV1().ManifestWorks(work.Namespace).Patch(context.TODO(), o.workName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "manifestwork %s in cluster %s is patched\n", o.workName, work.Namespace)
+	}
+	if patched == 0 {
+		return fmt.Errorf("manifestwork %s is not found in the clusters", o.workName)
+	}
+	return nil
+}
+
+// patchedAnnotations returns the annotations once patched
+func (o *Options) patchedAnnotations(annotations map[string]string) map[string]string {
+	patched := map[string]string{}
+	for key, value := range annotations {
+		patched[key] = value
+	}
+	for key, value := range o.annotations {
+		if value == nil {
+			delete(patched, key)
+			continue
+		}
+		patched[key] = value.(string)
+	}
+	return patched
+}
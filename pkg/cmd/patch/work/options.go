@@ -0,0 +1,38 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	Streams genericclioptions.IOStreams
+
+	workName string
+	//The clusters whose manifestwork is updated, all the clusters if empty
+	clusters []string
+	//Updates the placemanifestwork instead of the manifestworks
+	placeManifestWork bool
+
+	//The rollout strategy fields, only the flags set are updated
+	strategy       string
+	maxConcurrency string
+	groupLabel     string
+	pause          bool
+	resume         bool
+
+	//The annotations patched, nil values remove the annotations
+	annotations map[string]interface{}
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+		annotations:     map[string]interface{}{},
+	}
+}
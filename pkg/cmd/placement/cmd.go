@@ -0,0 +1,22 @@
+// Copyright Contributors to the Open Cluster Management project
+package placement
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/placement/simulate"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping NewCmdImportCluster
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "placement",
+		Short: "placement options",
+		Long:  "there is 1 placement option: simulate",
+	}
+
+	cmd.AddCommand(simulate.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
@@ -0,0 +1,44 @@
+// Copyright Contributors to the Open Cluster Management project
+package simulate
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Show the clusters the placement would select and why
+%[1]s placement simulate -f placement.yaml
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "simulate the decisions of a placement",
+		Long: "evaluate a placement against the managed clusters, the clustersets bound to its namespace and the addon placement " +
+			"scores of the hub without creating it, and print the clusters it would select with the score of each prioritizer, " +
+			"or the predicate or taint filtering them out",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.filename, "filename", "f", "", "The file of the placement")
+	return cmd
+}
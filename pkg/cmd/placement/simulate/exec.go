@@ -0,0 +1,132 @@
+// Copyright Contributors to the Open Cluster Management project
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("placement simulate options:", "filename", o.filename)
+	if len(o.filename) == 0 {
+		return fmt.Errorf("the file of the placement must be specified in --filename")
+	}
+	data, err := os.ReadFile(o.filename)
+	if err != nil {
+		return err
+	}
+	o.placement = &clusterv1beta1.Placement{}
+	if err := yaml.Unmarshal(data, o.placement); err != nil {
+		return fmt.Errorf("failed to parse the placement %s: %v", o.filename, err)
+	}
+	if len(o.placement.Namespace) == 0 {
+		o.placement.Namespace = metav1.NamespaceDefault
+	}
+	return nil
+}
+
+func (o *Options) validate() error {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if o.placement.Kind != "Placement" {
+		return fmt.Errorf("the file %s is not a placement but a %q", o.filename, o.placement.Kind)
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	s, err := o.readState(clusterClient)
+	if err != nil {
+		return err
+	}
+	results, notes, err := simulate(o.placement, s)
+	if err != nil {
+		return err
+	}
+
+	selected := 0
+	w := tabwriter.NewWriter(o.Streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tSELECTED\tSCORE\tDETAILS")
+	for _, r := range results {
+		score := fmt.Sprintf("%d", r.score)
+		if r.filtered {
+			score = "-"
+		}
+		if r.selected {
+			selected++
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", r.cluster, r.selected, score, strings.Join(r.details, "; "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "\nPlacement %s/%s would select %d of %d clusters\n", o.placement.Namespace, o.placement.Name, selected, len(results))
+	for _, note := range notes {
+		fmt.Fprintf(o.Streams.Out, "NOTE: %s\n", note)
+	}
+	return nil
+}
+
+// readState reads the clusters, clustersets, bindings, scores and decisions of the hub
+func (o *Options) readState(clusterClient clusterclientset.Interface) (*state, error) {
+	ctx := context.TODO()
+	clusters, err := clusterClient.ClusterV1().ManagedClusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	clusterSets, err := clusterClient.ClusterV1beta1().ManagedClusterSets().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := clusterClient.ClusterV1beta1().ManagedClusterSetBindings(o.placement.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	scores, err := clusterClient.ClusterV1alpha1().AddOnPlacementScores(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := clusterClient.ClusterV1beta1().PlacementDecisions(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	s := &state{
+		clusters:    clusters.Items,
+		clusterSets: clusterSets.Items,
+		bindings:    bindings.Items,
+		scores:      scores.Items,
+		decisions:   map[string]int{},
+		now:         time.Now(),
+	}
+	for _, decision := range decisions.Items {
+		// the decisions of the placement simulated are not the ones of another placement
+		if decision.Namespace == o.placement.Namespace && decision.Labels[clusterv1beta1.PlacementLabel] == o.placement.Name {
+			continue
+		}
+		for _, d := range decision.Status.Decisions {
+			s.decisions[d.ClusterName]++
+		}
+	}
+	return s, nil
+}
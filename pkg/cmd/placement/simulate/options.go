@@ -0,0 +1,27 @@
+// Copyright Contributors to the Open Cluster Management project
+package simulate
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The file of the placement
+	filename string
+	//The placement read from the file
+	placement *clusterv1beta1.Placement
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright Contributors to the Open Cluster Management project
+package simulate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	prioritizerBalance   = "Balance"
+	prioritizerSteady    = "Steady"
+	prioritizerCPU       = "ResourceAllocatableCPU"
+	prioritizerMemory    = "ResourceAllocatableMemory"
+	prioritizerSpread    = "Spread"
+	maxScore             = 100
+	minScore             = -100
+	defaultPlacementMode = clusterv1beta1.PrioritizerPolicyModeAdditive
+)
+
+// state is what the placement is evaluated against, as read from the hub
+type state struct {
+	clusters    []clusterv1.ManagedCluster
+	clusterSets []clusterv1beta1.ManagedClusterSet
+	// bindings are the clusterset bindings of the namespace of the placement
+	bindings []clusterv1beta1.ManagedClusterSetBinding
+	scores   []clusterv1alpha1.AddOnPlacementScore
+	// decisions is the number of decisions of the other placements per cluster
+	decisions map[string]int
+	now       time.Time
+}
+
+// result is the evaluation of a cluster against the placement
+type result struct {
+	cluster  string
+	selected bool
+	// filtered is true if a predicate or a taint excludes the cluster
+	filtered bool
+	score    int64
+	details  []string
+}
+
+// prioritizer is a prioritizer of the placement with its weight
+type prioritizer struct {
+	name   string
+	weight int32
+	addOn  *clusterv1beta1.AddOnScore
+}
+
+// simulate evaluates the placement as the placement controller would for a new placement, without existing
+// decisions. The clusters filtered are returned after the clusters sorted by score.
+func simulate(placement *clusterv1beta1.Placement, s *state) ([]result, []string, error) {
+	var notes []string
+	clusterSets, note := eligibleClusterSets(placement, s)
+	if len(note) > 0 {
+		notes = append(notes, note)
+	}
+	candidates := sets.NewString()
+	for i := range s.clusterSets {
+		if !clusterSets.Has(s.clusterSets[i].Name) {
+			continue
+		}
+		selector, err := clusterv1beta1.BuildClusterSelector(&s.clusterSets[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, cluster := range s.clusters {
+			if selector.Matches(labels.Set(cluster.Labels)) {
+				candidates.Insert(cluster.Name)
+			}
+		}
+	}
+
+	var scored, filtered []result
+	var feasible []clusterv1.ManagedCluster
+	for _, cluster := range s.clusters {
+		r := result{cluster: cluster.Name}
+		switch {
+		case !candidates.Has(cluster.Name):
+			r.filtered = true
+			r.details = []string{"not in the clustersets " + strings.Join(clusterSets.List(), ",")}
+		default:
+			if ok, details := matchPredicates(placement.Spec.Predicates, &cluster); !ok {
+				r.filtered = true
+				r.details = details
+			} else if taint := untoleratedTaint(placement.Spec.Tolerations, cluster.Spec.Taints, s.now); len(taint) > 0 {
+				r.filtered = true
+				r.details = []string{taint}
+			}
+		}
+		if r.filtered {
+			filtered = append(filtered, r)
+			continue
+		}
+		feasible = append(feasible, cluster)
+	}
+
+	prioritizers, note := prioritizersOf(placement.Spec.PrioritizerPolicy)
+	if len(note) > 0 {
+		notes = append(notes, note)
+	}
+	scores := map[string]map[string]int64{}
+	for _, p := range prioritizers {
+		scores[p.name] = score(p, feasible, s)
+	}
+	for _, cluster := range feasible {
+		r := result{cluster: cluster.Name}
+		for _, p := range prioritizers {
+			value := scores[p.name][cluster.Name]
+			r.score += int64(p.weight) * value
+			r.details = append(r.details, fmt.Sprintf("%s=%d*%d", p.name, value, p.weight))
+		}
+		scored = append(scored, r)
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].cluster < scored[j].cluster
+	})
+	for i := range scored {
+		numberOfClusters := placement.Spec.NumberOfClusters
+		scored[i].selected = numberOfClusters == nil || i < int(*numberOfClusters)
+	}
+	if n := placement.Spec.NumberOfClusters; n != nil && int(*n) > len(scored) {
+		notes = append(notes, fmt.Sprintf("only %d of the %d clusters requested are feasible, the placement would not be satisfied", len(scored), *n))
+	}
+	if len(placement.Spec.SpreadPolicy.SpreadConstraints) > 0 {
+		notes = append(notes, "the spread constraints are not simulated")
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].cluster < filtered[j].cluster })
+	return append(scored, filtered...), notes, nil
+}
+
+// eligibleClusterSets returns the clustersets bound to the namespace of the placement, and of its clustersets if set
+func eligibleClusterSets(placement *clusterv1beta1.Placement, s *state) (sets.String, string) {
+	existing := sets.NewString()
+	for _, clusterSet := range s.clusterSets {
+		existing.Insert(clusterSet.Name)
+	}
+	bound := sets.NewString()
+	for _, binding := range s.bindings {
+		if existing.Has(binding.Spec.ClusterSet) {
+			bound.Insert(binding.Spec.ClusterSet)
+		}
+	}
+	if bound.Len() == 0 {
+		return bound, fmt.Sprintf("no clusterset is bound to the namespace %s", placement.Namespace)
+	}
+	if len(placement.Spec.ClusterSets) == 0 {
+		return bound, ""
+	}
+	eligible := bound.Intersection(sets.NewString(placement.Spec.ClusterSets...))
+	if missing := sets.NewString(placement.Spec.ClusterSets...).Difference(bound); missing.Len() > 0 {
+		return eligible, fmt.Sprintf("the clustersets %s are not bound to the namespace %s", strings.Join(missing.List(), ","), placement.Namespace)
+	}
+	return eligible, ""
+}
+
+// matchPredicates returns true if one of the predicates matches the cluster, or why none matches
+func matchPredicates(predicates []clusterv1beta1.ClusterPredicate, cluster *clusterv1.ManagedCluster) (bool, []string) {
+	if len(predicates) == 0 {
+		return true, nil
+	}
+	claims := labels.Set{}
+	for _, claim := range cluster.Status.ClusterClaims {
+		claims[claim.Name] = claim.Value
+	}
+	var details []string
+	for i, predicate := range predicates {
+		selector := predicate.RequiredClusterSelector
+		unmatched := unmatchedRequirements(&selector.LabelSelector, labels.Set(cluster.Labels), "label")
+		unmatched = append(unmatched, unmatchedRequirements(&metav1.LabelSelector{MatchExpressions: selector.ClaimSelector.MatchExpressions}, claims, "claim")...)
+		if len(unmatched) == 0 {
+			return true, nil
+		}
+		details = append(details, fmt.Sprintf("predicate %d: %s", i+1, strings.Join(unmatched, ", ")))
+	}
+	return false, details
+}
+
+// unmatchedRequirements returns the requirements of the selector the values do not match
+func unmatchedRequirements(selector *metav1.LabelSelector, values labels.Set, kind string) []string {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid %s selector: %v", kind, err)}
+	}
+	requirements, _ := s.Requirements()
+	var unmatched []string
+	for _, requirement := range requirements {
+		if !requirement.Matches(values) {
+			unmatched = append(unmatched, fmt.Sprintf("%s %s not matched", kind, requirement.String()))
+		}
+	}
+	return unmatched
+}
+
+// untoleratedTaint returns the first taint of the cluster the tolerations do not tolerate. A new placement does
+// not select the clusters with a taint of any effect it does not tolerate.
+func untoleratedTaint(tolerations []clusterv1beta1.Toleration, taints []clusterv1.Taint, now time.Time) string {
+	for _, taint := range taints {
+		tolerated := false
+		for _, toleration := range tolerations {
+			if tolerates(toleration, taint, now) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return fmt.Sprintf("taint %s=%s:%s not tolerated", taint.Key, taint.Value, taint.Effect)
+		}
+	}
+	return ""
+}
+
+func tolerates(toleration clusterv1beta1.Toleration, taint clusterv1.Taint, now time.Time) bool {
+	if len(toleration.Effect) > 0 && toleration.Effect != taint.Effect {
+		return false
+	}
+	if len(toleration.Key) > 0 && toleration.Key != taint.Key {
+		return false
+	}
+	switch toleration.Operator {
+	case clusterv1beta1.TolerationOpExists:
+	case clusterv1beta1.TolerationOpEqual, "":
+		if toleration.Value != taint.Value {
+			return false
+		}
+	default:
+		return false
+	}
+	if toleration.TolerationSeconds != nil {
+		expiry := taint.TimeAdded.Add(time.Duration(*toleration.TolerationSeconds) * time.Second)
+		return now.Before(expiry)
+	}
+	return true
+}
+
+// prioritizersOf returns the prioritizers with a weight, Balance and Steady have a weight of 1 in the Additive mode
+func prioritizersOf(policy clusterv1beta1.PrioritizerPolicy) ([]prioritizer, string) {
+	weights := map[string]int32{}
+	addOns := map[string]*clusterv1beta1.AddOnScore{}
+	var names []string
+	set := func(name string, weight int32) {
+		if _, ok := weights[name]; !ok {
+			names = append(names, name)
+		}
+		weights[name] = weight
+	}
+	mode := policy.Mode
+	if len(mode) == 0 {
+		mode = defaultPlacementMode
+	}
+	if mode == clusterv1beta1.PrioritizerPolicyModeAdditive {
+		set(prioritizerBalance, 1)
+		set(prioritizerSteady, 1)
+	}
+	var notes []string
+	for _, config := range policy.Configurations {
+		if config.ScoreCoordinate == nil {
+			continue
+		}
+		switch config.ScoreCoordinate.Type {
+		case clusterv1beta1.ScoreCoordinateTypeAddOn:
+			if config.ScoreCoordinate.AddOn == nil {
+				notes = append(notes, "an AddOn prioritizer has no addOn score")
+				continue
+			}
+			name := fmt.Sprintf("%s/%s", config.ScoreCoordinate.AddOn.ResourceName, config.ScoreCoordinate.AddOn.ScoreName)
+			set(name, config.Weight)
+			addOns[name] = config.ScoreCoordinate.AddOn
+		default:
+			set(config.ScoreCoordinate.BuiltIn, config.Weight)
+		}
+	}
+	var prioritizers []prioritizer
+	for _, name := range names {
+		if weights[name] == 0 {
+			continue
+		}
+		prioritizers = append(prioritizers, prioritizer{name: name, weight: weights[name], addOn: addOns[name]})
+		switch name {
+		case prioritizerSpread:
+			notes = append(notes, "the Spread prioritizer is not simulated, its score is 0")
+		case prioritizerSteady:
+			notes = append(notes, "the Steady prioritizer scores 0 as the placement has no decision yet")
+		}
+	}
+	return prioritizers, strings.Join(notes, "; ")
+}
+
+// score returns the scores of the clusters of the prioritizer, between -100 and 100
+func score(p prioritizer, clusters []clusterv1.ManagedCluster, s *state) map[string]int64 {
+	scores := map[string]int64{}
+	switch {
+	case p.addOn != nil:
+		for _, cluster := range clusters {
+			scores[cluster.Name] = addOnScore(p.addOn, cluster.Name, s)
+		}
+	case p.name == prioritizerBalance:
+		maxCount := 0
+		for _, count := range s.decisions {
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+		for _, cluster := range clusters {
+			scores[cluster.Name] = maxScore
+			if maxCount > 0 {
+				scores[cluster.Name] = int64(maxScore - 2*maxScore*s.decisions[cluster.Name]/maxCount)
+			}
+		}
+	case p.name == prioritizerCPU || p.name == prioritizerMemory:
+		resource := clusterv1.ResourceCPU
+		if p.name == prioritizerMemory {
+			resource = clusterv1.ResourceMemory
+		}
+		values := map[string]int64{}
+		var min, max int64
+		for i, cluster := range clusters {
+			quantity := cluster.Status.Allocatable[resource]
+			value := quantity.MilliValue()
+			values[cluster.Name] = value
+			if i == 0 || value < min {
+				min = value
+			}
+			if i == 0 || value > max {
+				max = value
+			}
+		}
+		for _, cluster := range clusters {
+			scores[cluster.Name] = maxScore
+			if max > min {
+				scores[cluster.Name] = minScore + (maxScore-minScore)*(values[cluster.Name]-min)/(max-min)
+			}
+		}
+	}
+	// the other prioritizers score 0
+	return scores
+}
+
+// addOnScore returns the score of the cluster in its AddOnPlacementScore, 0 if missing or expired
+func addOnScore(addOn *clusterv1beta1.AddOnScore, cluster string, s *state) int64 {
+	for _, score := range s.scores {
+		if score.Namespace != cluster || score.Name != addOn.ResourceName {
+			continue
+		}
+		if score.Status.ValidUntil != nil && !s.now.Before(score.Status.ValidUntil.Time) {
+			return 0
+		}
+		for _, item := range score.Status.Scores {
+			if item.Name == addOn.ScoreName {
+				return int64(item.Value)
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,163 @@
+// Copyright Contributors to the Open Cluster Management project
+package simulate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+func newCluster(name string, labels map[string]string, taints ...clusterv1.Taint) clusterv1.ManagedCluster {
+	return clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       clusterv1.ManagedClusterSpec{Taints: taints},
+	}
+}
+
+func newState(now time.Time, clusters ...clusterv1.ManagedCluster) *state {
+	return &state{
+		clusters:    clusters,
+		clusterSets: []clusterv1beta1.ManagedClusterSet{{ObjectMeta: metav1.ObjectMeta{Name: "prod"}}},
+		bindings: []clusterv1beta1.ManagedClusterSetBinding{
+			{Spec: clusterv1beta1.ManagedClusterSetBindingSpec{ClusterSet: "prod"}},
+		},
+		decisions: map[string]int{},
+		now:       now,
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	prod := func(labels map[string]string) map[string]string {
+		labels[clusterv1beta1.ClusterSetLabel] = "prod"
+		return labels
+	}
+	two := int32(2)
+
+	cases := []struct {
+		name      string
+		placement clusterv1beta1.PlacementSpec
+		state     func() *state
+		expected  []result
+		notes     []string
+	}{
+		{
+			name: "predicates and clustersets",
+			placement: clusterv1beta1.PlacementSpec{
+				Predicates: []clusterv1beta1.ClusterPredicate{{
+					RequiredClusterSelector: clusterv1beta1.ClusterSelector{
+						LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+					},
+				}},
+			},
+			state: func() *state {
+				return newState(now,
+					newCluster("cluster1", prod(map[string]string{"env": "prod"})),
+					newCluster("cluster2", prod(map[string]string{"env": "dev"})),
+					newCluster("cluster3", map[string]string{"env": "prod"}),
+				)
+			},
+			expected: []result{
+				{cluster: "cluster1", selected: true, score: 100, details: []string{"Balance=100*1", "Steady=0*1"}},
+				{cluster: "cluster2", filtered: true, details: []string{"predicate 1: label env=prod not matched"}},
+				{cluster: "cluster3", filtered: true, details: []string{"not in the clustersets prod"}},
+			},
+			notes: []string{"the Steady prioritizer scores 0 as the placement has no decision yet"},
+		},
+		{
+			name: "taints",
+			placement: clusterv1beta1.PlacementSpec{
+				Tolerations: []clusterv1beta1.Toleration{
+					{Key: "gpu", Operator: clusterv1beta1.TolerationOpExists},
+					{Key: "unreachable", Operator: clusterv1beta1.TolerationOpExists, TolerationSeconds: func() *int64 { s := int64(60); return &s }()},
+				},
+				PrioritizerPolicy: clusterv1beta1.PrioritizerPolicy{Mode: clusterv1beta1.PrioritizerPolicyModeExact},
+			},
+			state: func() *state {
+				return newState(now,
+					newCluster("cluster1", prod(map[string]string{}), clusterv1.Taint{Key: "gpu", Effect: clusterv1.TaintEffectNoSelect}),
+					newCluster("cluster2", prod(map[string]string{}), clusterv1.Taint{Key: "unreachable", Effect: clusterv1.TaintEffectNoSelect,
+						TimeAdded: metav1.NewTime(now.Add(-time.Minute))}),
+					newCluster("cluster3", prod(map[string]string{}), clusterv1.Taint{Key: "unreachable", Effect: clusterv1.TaintEffectNoSelect,
+						TimeAdded: metav1.NewTime(now.Add(-30 * time.Second))}),
+					newCluster("cluster4", prod(map[string]string{}), clusterv1.Taint{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect}),
+				)
+			},
+			expected: []result{
+				{cluster: "cluster1", selected: true},
+				{cluster: "cluster3", selected: true},
+				{cluster: "cluster2", filtered: true, details: []string{"taint unreachable=:NoSelect not tolerated"}},
+				{cluster: "cluster4", filtered: true, details: []string{"taint maintenance=true:PreferNoSelect not tolerated"}},
+			},
+		},
+		{
+			name: "addon and balance scores",
+			placement: clusterv1beta1.PlacementSpec{
+				NumberOfClusters: &two,
+				PrioritizerPolicy: clusterv1beta1.PrioritizerPolicy{
+					Configurations: []clusterv1beta1.PrioritizerConfig{
+						{ScoreCoordinate: &clusterv1beta1.ScoreCoordinate{Type: clusterv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: "Steady"}, Weight: 0},
+						{ScoreCoordinate: &clusterv1beta1.ScoreCoordinate{
+							Type:  clusterv1beta1.ScoreCoordinateTypeAddOn,
+							AddOn: &clusterv1beta1.AddOnScore{ResourceName: "usage", ScoreName: "cpu"},
+						}, Weight: 2},
+					},
+				},
+			},
+			state: func() *state {
+				s := newState(now,
+					newCluster("cluster1", prod(map[string]string{})),
+					newCluster("cluster2", prod(map[string]string{})),
+					newCluster("cluster3", prod(map[string]string{})),
+				)
+				s.decisions = map[string]int{"cluster1": 2, "cluster2": 1}
+				expired := metav1.NewTime(now.Add(-time.Minute))
+				s.scores = []clusterv1alpha1.AddOnPlacementScore{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "usage", Namespace: "cluster1"},
+						Status: clusterv1alpha1.AddOnPlacementScoreStatus{
+							Scores: []clusterv1alpha1.AddOnPlacementScoreItem{{Name: "cpu", Value: 90}},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "usage", Namespace: "cluster3"},
+						Status: clusterv1alpha1.AddOnPlacementScoreStatus{
+							Scores:     []clusterv1alpha1.AddOnPlacementScoreItem{{Name: "cpu", Value: 90}},
+							ValidUntil: &expired,
+						},
+					},
+				}
+				return s
+			},
+			expected: []result{
+				{cluster: "cluster3", selected: true, score: 100, details: []string{"Balance=100*1", "usage/cpu=0*2"}},
+				{cluster: "cluster1", selected: true, score: 80, details: []string{"Balance=-100*1", "usage/cpu=90*2"}},
+				{cluster: "cluster2", score: 0, details: []string{"Balance=0*1", "usage/cpu=0*2"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := &clusterv1beta1.Placement{
+				ObjectMeta: metav1.ObjectMeta{Name: "placement", Namespace: "default"},
+				Spec:       c.placement,
+			}
+			results, notes, err := simulate(placement, c.state())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(results, c.expected) {
+				t.Errorf("expected %+v, got %+v", c.expected, results)
+			}
+			if !reflect.DeepEqual(notes, c.notes) {
+				t.Errorf("expected notes %q, got %q", c.notes, notes)
+			}
+		})
+	}
+}
@@ -4,10 +4,14 @@ package proxy
 import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/curl"
+	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/enable"
 	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/health"
 	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/kubectl"
 	proxyapi "open-cluster-management.io/clusteradm/pkg/cmd/proxy/api"
 	service "open-cluster-management.io/clusteradm/pkg/cmd/proxy/service"
+	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/services"
+	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/tunnel"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 )
 
@@ -19,9 +23,13 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		Short: "helper commands for cluster-proxy addon",
 	}
 
+	cmd.AddCommand(curl.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(enable.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(health.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(kubectl.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(proxyapi.NewCmd(clusteradmFlags, streams))
 	cmd.AddCommand(service.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(services.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(tunnel.NewCmd(clusteradmFlags, streams))
 	return cmd
 }
@@ -0,0 +1,58 @@
+// Copyright Contributors to the Open Cluster Management project
+package curl
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Request the metrics of the prometheus service of the managed cluster cluster1
+%[1]s proxy curl --cluster cluster1 --sa test --service monitoring/prometheus:9090 /metrics
+
+# Post the body read from the stdin to a pod with a header, and print the response headers
+echo '{"ping": true}' | %[1]s proxy curl --cluster cluster1 --sa test --pod default/app-0:8443 --scheme https \
+  -X POST -H "Content-Type: application/json" -d - -i /api/ping
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "curl PATH",
+		Short:        "send a http request to a service or a pod of a managed cluster",
+		Long:         "send a http request through the cluster-proxy tunnel to a service or a pod of a managed cluster, proxied by its kube-apiserver, and print the response",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "The name of the managed cluster")
+	cmd.Flags().StringVar(&o.managedServiceAccount, "sa", "", "The name of the managedServiceAccount authenticating the request to the kube-apiserver of the managed cluster")
+	cmd.Flags().StringVar(&o.service, "service", "", "The service to request, in the format of <namespace>/<name>:<port>")
+	cmd.Flags().StringVar(&o.pod, "pod", "", "The pod to request, in the format of <namespace>/<name>:<port>")
+	cmd.Flags().StringVar(&o.scheme, "scheme", "http", "The scheme of the request to the service or the pod, http or https")
+	cmd.Flags().StringVarP(&o.method, "request", "X", "GET", "The method of the request")
+	cmd.Flags().StringArrayVarP(&o.headers, "header", "H", nil, "The headers of the request, in the format of <name>: <value>")
+	cmd.Flags().StringVarP(&o.data, "data", "d", "", "The body of the request, - reads it from the stdin")
+	cmd.Flags().BoolVarP(&o.include, "include", "i", false, "Print the status and the headers of the response")
+	cmd.Flags().BoolVarP(&o.fail, "fail", "f", false, "Fail if the response status is an error")
+	cmd.Flags().IntVar(&o.proxyServerPort, "proxy-server-port", clusterproxy.DefaultProxyServerPort, "The local port forwarded to the proxy servers")
+
+	return cmd
+}
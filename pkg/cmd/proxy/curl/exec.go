@@ -0,0 +1,182 @@
+// Copyright Contributors to the Open Cluster Management project
+package curl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+)
+
+// target is the service or the pod the request is proxied to by the kube-apiserver
+type target struct {
+	resource  string
+	namespace string
+	name      string
+	port      string
+}
+
+// parseTarget parses a target in the format of <namespace>/<name>:<port>
+func parseTarget(resource, value string) (*target, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%q should be in the format of <namespace>/<name>:<port>", value)
+	}
+	nameAndPort := strings.SplitN(parts[1], ":", 2)
+	if len(nameAndPort) != 2 || len(parts[0]) == 0 || len(nameAndPort[0]) == 0 || len(nameAndPort[1]) == 0 {
+		return nil, fmt.Errorf("%q should be in the format of <namespace>/<name>:<port>", value)
+	}
+	return &target{resource: resource, namespace: parts[0], name: nameAndPort[0], port: nameAndPort[1]}, nil
+}
+
+// proxyURL returns the url of the proxy subresource of the target on the kube-apiserver of the cluster
+func (t *target) proxyURL(cluster, scheme, path string) (*url.URL, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	return &url.URL{
+		Scheme:   "https",
+		Host:     cluster,
+		Path:     fmt.Sprintf("/api/v1/namespaces/%s/%s/%s:%s:%s/proxy%s", t.namespace, t.resource, scheme, t.name, t.port, u.Path),
+		RawQuery: u.RawQuery,
+	}, nil
+}
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("proxy curl options:", "cluster", o.cluster, "service", o.service, "pod", o.pod, "scheme", o.scheme, "method", o.method)
+
+	if len(args) != 1 {
+		return fmt.Errorf("the path of the request must be specified")
+	}
+	o.path = args[0]
+	if !strings.HasPrefix(o.path, "/") {
+		o.path = "/" + o.path
+	}
+
+	switch {
+	case len(o.service) > 0 && len(o.pod) > 0:
+		return fmt.Errorf("--service and --pod can only specify one")
+	case len(o.service) > 0:
+		o.target, err = parseTarget("services", o.service)
+	case len(o.pod) > 0:
+		o.target, err = parseTarget("pods", o.pod)
+	default:
+		return fmt.Errorf("--service or --pod must be specified")
+	}
+	if err != nil {
+		return err
+	}
+
+	o.header = http.Header{}
+	for _, header := range o.headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || len(validation.IsHTTPHeaderName(strings.TrimSpace(parts[0]))) > 0 {
+			return fmt.Errorf("invalid header %q, should be in the format of <name>: <value>", header)
+		}
+		o.header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	return nil
+}
+
+func (o *Options) validate() error {
+	if len(o.cluster) == 0 {
+		return fmt.Errorf("cluster is required")
+	}
+	if len(o.managedServiceAccount) == 0 {
+		return fmt.Errorf("managedServiceAccount is required")
+	}
+	if o.scheme != "http" && o.scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q, should be http or https", o.scheme)
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var body io.Reader
+	switch o.data {
+	case "":
+	case "-":
+		data, err := io.ReadAll(o.Streams.In)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	default:
+		body = strings.NewReader(o.data)
+	}
+
+	hubRestConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return errors.Wrapf(err, "failed loading hub cluster's client config")
+	}
+	proxyConfig, err := clusterproxy.GetProxyConfig(ctx, hubRestConfig)
+	if err != nil {
+		return err
+	}
+	token, err := clusterproxy.ManagedServiceAccountToken(ctx, hubRestConfig, o.managedServiceAccount, o.cluster)
+	if err != nil {
+		return err
+	}
+	dial, closeFn, err := clusterproxy.Dial(ctx, hubRestConfig, proxyConfig, o.proxyServerPort)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	rt, err := rest.TransportFor(clusterproxy.ManagedClusterConfig(o.cluster, token, dial))
+	if err != nil {
+		return err
+	}
+	u, err := o.target.proxyURL(o.cluster, o.scheme, o.path)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, o.method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header = o.header
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed requesting %s %s", o.method, o.path)
+	}
+	defer resp.Body.Close()
+
+	if o.include {
+		fmt.Fprintf(o.Streams.Out, "%s %s\n", resp.Proto, resp.Status)
+		names := make([]string, 0, len(resp.Header))
+		for name := range resp.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, value := range resp.Header[name] {
+				fmt.Fprintf(o.Streams.Out, "%s: %s\n", name, value)
+			}
+		}
+		fmt.Fprintln(o.Streams.Out)
+	}
+	if _, err := io.Copy(o.Streams.Out, resp.Body); err != nil {
+		return err
+	}
+	if o.fail && resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("the request returned %s", resp.Status)
+	}
+	return nil
+}
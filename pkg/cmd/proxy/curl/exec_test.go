@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+package curl
+
+import (
+	"testing"
+)
+
+func TestProxyURL(t *testing.T) {
+	cases := []struct {
+		name      string
+		resource  string
+		value     string
+		scheme    string
+		path      string
+		expected  string
+		expectErr bool
+	}{
+		{name: "service", resource: "services", value: "monitoring/prometheus:9090", scheme: "http", path: "/metrics",
+			expected: "https://cluster1/api/v1/namespaces/monitoring/services/http:prometheus:9090/proxy/metrics"},
+		{name: "pod with query", resource: "pods", value: "default/app-0:8443", scheme: "https", path: "/api/items?limit=10",
+			expected: "https://cluster1/api/v1/namespaces/default/pods/https:app-0:8443/proxy/api/items?limit=10"},
+		{name: "named port", resource: "services", value: "default/web:http", scheme: "http", path: "/",
+			expected: "https://cluster1/api/v1/namespaces/default/services/http:web:http/proxy/"},
+		{name: "missing namespace", resource: "services", value: "prometheus:9090", expectErr: true},
+		{name: "missing port", resource: "services", value: "monitoring/prometheus", expectErr: true},
+		{name: "empty name", resource: "pods", value: "default/:80", expectErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			target, err := parseTarget(c.resource, c.value)
+			if c.expectErr {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := target.proxyURL("cluster1", c.scheme, c.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.String() != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, u.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright Contributors to the Open Cluster Management project
+package curl
+
+import (
+	"net/http"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+//Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	Streams genericclioptions.IOStreams
+
+	cluster               string
+	managedServiceAccount string
+	service               string
+	pod                   string
+	scheme                string
+	method                string
+	headers               []string
+	data                  string
+	include               bool
+	fail                  bool
+	proxyServerPort       int
+
+	// completed fields
+	path   string
+	target *target
+	header http.Header
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
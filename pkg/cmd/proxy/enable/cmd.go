@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+package enable
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Install the cluster-proxy add-on on the hub if missing, enable it on the clusters and wait for their tunnels
+%[1]s proxy enable --clusters cluster1,cluster2
+
+# Let the proxy agents connect the proxy servers through a fixed hostname instead of port-forwarding
+%[1]s proxy enable --clusters cluster1 --proxy-server-host proxy.example.com
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "enable",
+		Short:        "enable the cluster-proxy add-on on the clusters",
+		Long:         "install the cluster-proxy add-on on the hub cluster if missing, enable it on the given managed clusters and wait for the tunnels to be established",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			helpers.DryRunMessage(clusteradmFlags.DryRun)
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.clusters, "clusters", []string{}, "Names of the managed clusters to enable the add-on on (comma separated)")
+	cmd.Flags().StringVar(&o.values.ProxyServerHost, "proxy-server-host", "",
+		"The hostname the proxy agents connect the proxy servers with, the agents port-forward to the proxy servers through the hub apiserver if not set")
+	cmd.Flags().Int32Var(&o.values.ProxyServerPort, "proxy-server-port", 8091, "The port the proxy agents connect the proxy servers with")
+	cmd.Flags().StringVar(&o.values.Namespace, "namespace", "open-cluster-management-addon", "The namespace of the cluster-proxy add-on on the hub cluster")
+	cmd.Flags().StringVar(&o.values.AgentNamespace, "agent-namespace", "open-cluster-management-cluster-proxy", "The namespace of the proxy agents on the managed clusters")
+	cmd.Flags().StringVar(&o.values.Image, "image", "quay.io/open-cluster-management/cluster-proxy:v0.2.0", "The image of the cluster-proxy add-on")
+	cmd.Flags().Int32Var(&o.values.Replicas, "replicas", 1, "The replicas of the proxy servers and agents")
+	cmd.Flags().BoolVar(&o.wait, "wait", true, "Wait for the add-on to be available and for the tunnels to be established")
+	cmd.Flags().StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
+
+	return cmd
+}
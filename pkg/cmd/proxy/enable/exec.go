@@ -0,0 +1,196 @@
+// Copyright Contributors to the Open Cluster Management project
+package enable
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/applier/pkg/apply"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/enable/scenario"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+	clusteradmwait "open-cluster-management.io/clusteradm/pkg/helpers/wait"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("proxy enable options:", "dry-run", o.ClusteradmFlags.DryRun, "clusters", o.clusters,
+		"proxy-server-host", o.values.ProxyServerHost, "namespace", o.values.Namespace, "wait", o.wait, "output-file", o.outputFile)
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+	if len(o.clusters) == 0 {
+		return fmt.Errorf("clusters is missing")
+	}
+	if o.values.Replicas < 1 {
+		return fmt.Errorf("replicas must be at least 1")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	addonClient, err := addonclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	kubeClient, apiExtensionsClient, dynamicClient, err := helpers.GetClients(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+
+	clusters := sets.NewString(o.clusters...).List()
+	for _, clusterName := range clusters {
+		if _, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+
+	dryRun := o.ClusteradmFlags.DryRun
+	output := make([]string, 0)
+	reader := scenario.GetScenarioResourcesReader()
+	applier := apply.NewApplierBuilder().WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
+
+	_, err = addonClient.AddonV1alpha1().ClusterManagementAddOns().Get(context.TODO(), clusterproxy.AddonName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		files := []string{
+			"proxy/crd_managedproxyconfigurations.yaml",
+			"proxy/crd_managedproxyserviceresolvers.yaml",
+			"proxy/namespace.yaml",
+			"proxy/serviceaccount.yaml",
+			"proxy/clusterrole.yaml",
+			"proxy/clusterrolebinding.yaml",
+			"proxy/role.yaml",
+			"proxy/rolebinding.yaml",
+		}
+		out, err := applier.ApplyDirectly(reader, o.values, dryRun, "", files...)
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
+
+		out, err = applier.ApplyDeployments(reader, o.values, dryRun, "", "proxy/deployment.yaml")
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
+
+		if !dryRun {
+			if err := clusteradmwait.WaitUntilCRDReady(apiExtensionsClient, "managedproxyconfigurations.proxy.open-cluster-management.io", false); err != nil {
+				return err
+			}
+		}
+		out, err = applier.ApplyCustomResources(reader, o.values, dryRun, "",
+			"proxy/clustermanagementaddon.yaml", "proxy/managedproxyconfiguration.yaml")
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
+
+		fmt.Fprintf(o.Streams.Out, "Installing %s add-on to the Hub cluster...\n", clusterproxy.AddonName)
+	case err != nil:
+		return err
+	default:
+		fmt.Fprintf(o.Streams.Out, "%s add-on is already installed on the Hub cluster\n", clusterproxy.AddonName)
+	}
+
+	for _, clusterName := range clusters {
+		values := o.values
+		values.ClusterName = clusterName
+		out, err := applier.ApplyCustomResources(reader, values, dryRun, "", "proxy/managedclusteraddon.yaml")
+		if err != nil {
+			return err
+		}
+		output = append(output, out...)
+		fmt.Fprintf(o.Streams.Out, "Deploying %s add-on to namespaces %s of managed cluster: %s.\n", clusterproxy.AddonName, o.values.AgentNamespace, clusterName)
+	}
+
+	if err := apply.WriteOutput(o.outputFile, output); err != nil {
+		return err
+	}
+	if !o.wait || dryRun {
+		return nil
+	}
+	return o.waitForTunnels(addonClient, clusters)
+}
+
+// waitForTunnels waits for the add-on to be available on the clusters, then for their tunnels to answer
+func (o *Options) waitForTunnels(addonClient addonclientset.Interface, clusters []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(o.ClusteradmFlags.Timeout)*time.Second)
+	defer cancel()
+
+	pending := sets.NewString(clusters...)
+	err := wait.PollImmediateUntilWithContext(ctx, 2*time.Second, func(ctx context.Context) (bool, error) {
+		for _, clusterName := range pending.List() {
+			addon, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(clusterName).Get(ctx, clusterproxy.AddonName, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable) {
+				pending.Delete(clusterName)
+			}
+		}
+		return pending.Len() == 0, nil
+	})
+	if err != nil {
+		return utilerrors.NewAggregate([]error{err, fmt.Errorf("%s add-on is not available on clusters %v", clusterproxy.AddonName, pending.List())})
+	}
+
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	proxyConfig, err := clusterproxy.GetProxyConfig(ctx, restConfig)
+	if err != nil {
+		return err
+	}
+	pending = sets.NewString(clusters...)
+	err = wait.PollImmediateUntilWithContext(ctx, 2*time.Second, func(ctx context.Context) (bool, error) {
+		// the proxy servers and their secrets are created by the add-on manager
+		dial, closeFn, err := clusterproxy.Dial(ctx, restConfig, proxyConfig, clusterproxy.DefaultProxyServerPort)
+		if err != nil {
+			klog.V(3).InfoS("waiting for the proxy servers", "error", err)
+			return false, nil
+		}
+		defer closeFn()
+
+		for _, clusterName := range pending.List() {
+			if _, err := clusterproxy.Probe(ctx, clusterName, dial); err != nil {
+				klog.V(3).InfoS("waiting for the tunnel", "cluster", clusterName, "error", err)
+				continue
+			}
+			pending.Delete(clusterName)
+		}
+		return pending.Len() == 0, nil
+	})
+	if err != nil {
+		return utilerrors.NewAggregate([]error{err, fmt.Errorf("the tunnels of clusters %v are not established", pending.List())})
+	}
+
+	fmt.Fprintf(o.Streams.Out, "The tunnels of clusters %v are established, run '%s proxy health' to check them\n", clusters, helpers.GetExampleHeader())
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright Contributors to the Open Cluster Management project
+package enable
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stolostron/applier/pkg/apply"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"open-cluster-management.io/clusteradm/pkg/cmd/proxy/enable/scenario"
+	"sigs.k8s.io/yaml"
+)
+
+func TestTemplates(t *testing.T) {
+	cases := []struct {
+		name       string
+		values     Values
+		entrypoint string
+	}{
+		{name: "port-forward", values: Values{ProxyServerPort: 8091}, entrypoint: "PortForward"},
+		{name: "hostname", values: Values{ProxyServerHost: "proxy.example.com", ProxyServerPort: 8091}, entrypoint: "Hostname"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.values.Namespace = "open-cluster-management-addon"
+			c.values.AgentNamespace = "open-cluster-management-cluster-proxy"
+			c.values.Image = "quay.io/open-cluster-management/cluster-proxy:v0.2.0"
+			c.values.Replicas = 1
+			c.values.ClusterName = "cluster1"
+
+			files, err := scenario.GetScenarioResourcesReader().AssetNames(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			applier := apply.NewApplierBuilder().Build()
+			for _, file := range files {
+				data, err := applier.MustTemplateAsset(scenario.GetScenarioResourcesReader(), c.values, "", file)
+				if err != nil {
+					t.Fatalf("failed to render %s: %v", file, err)
+				}
+				obj := &unstructured.Unstructured{}
+				if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+					t.Fatalf("failed to parse %s: %v", file, err)
+				}
+				if obj.GetKind() != "ManagedProxyConfiguration" {
+					continue
+				}
+				entrypoint, _, _ := unstructured.NestedString(obj.Object, "spec", "proxyServer", "entrypoint", "type")
+				if entrypoint != c.entrypoint {
+					t.Errorf("expected entrypoint %s, got %s", c.entrypoint, entrypoint)
+				}
+				if !strings.Contains(string(data), c.values.Image) {
+					t.Errorf("expected image %s in %s", c.values.Image, file)
+				}
+			}
+		})
+	}
+}
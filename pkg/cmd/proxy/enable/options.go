@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+package enable
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+//Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	Streams genericclioptions.IOStreams
+
+	//The names of the managed clusters to enable the add-on on
+	clusters []string
+	//Wait for the add-on to be available and for the tunnels to be established
+	wait bool
+	//The file to output the resources will be sent to the file.
+	outputFile string
+
+	values Values
+}
+
+//Values: The values used in the template
+type Values struct {
+	//Namespace of the add-on on the hub cluster
+	Namespace string
+	//AgentNamespace is the namespace of the proxy agents on the managed clusters
+	AgentNamespace string
+	//Image of the add-on manager, the proxy servers and the proxy agents
+	Image string
+	//Replicas of the proxy servers and the proxy agents
+	Replicas int32
+	//ProxyServerHost is the hostname the proxy agents connect the proxy servers with
+	ProxyServerHost string
+	//ProxyServerPort is the port the proxy agents connect the proxy servers with
+	ProxyServerPort int32
+	//ClusterName is the managed cluster the add-on is enabled on
+	ClusterName string
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -14,6 +14,10 @@ import (
 var example = `
 # Probing healthiness of each managed clusters through the konnectivity tunnels installed by cluster-proxy addon
 %[1]s proxy health
+
+# Diagnose the tunnel of the managed cluster cluster1: the addon agent, the latency through the tunnel,
+# the certificates of the proxy servers and their agent connections
+%[1]s proxy health --cluster cluster1
 `
 
 const (
@@ -61,6 +65,8 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		"Konnectivity proxy server's entry port")
 	cmd.Flags().StringArrayVarP(&o.clusters, "clusters", "c", nil,
 		"The names of the clusters to probe")
+	cmd.Flags().StringVar(&o.cluster, "cluster", "",
+		"The name of the cluster to diagnose, reports the detailed checks of its tunnel instead of the health of all the clusters")
 
 	return cmd
 }
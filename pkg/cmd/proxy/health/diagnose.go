@@ -0,0 +1,269 @@
+// Copyright Contributors to the Open Cluster Management project
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	grpccredentials "google.golang.org/grpc/credentials"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
+	proxyv1alpha1 "open-cluster-management.io/cluster-proxy/pkg/apis/proxy/v1alpha1"
+	"open-cluster-management.io/cluster-proxy/pkg/common"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+	konnectivity "sigs.k8s.io/apiserver-network-proxy/konnectivity-client/pkg/client"
+)
+
+const (
+	statusOK      = "OK"
+	statusWarning = "WARNING"
+	statusFailed  = "FAILED"
+
+	// latencyProbes is the number of requests sent through the tunnel to measure the latency
+	latencyProbes = 3
+	// certificateExpiryWarning is the remaining validity under which the certificates are reported
+	certificateExpiryWarning = 30 * 24 * time.Hour
+	// defaultAdminPort is the admin port of the proxy servers serving the metrics
+	defaultAdminPort = 8095
+	// readyBackendConnectionsMetric is the number of agent connections of a proxy server
+	readyBackendConnectionsMetric = "konnectivity_network_proxy_server_ready_backend_connections"
+
+	inClusterSecretServer = "proxy-server"
+)
+
+type diagnostic struct {
+	check  string
+	status string
+	detail string
+}
+
+// diagnose checks the connectivity of the cluster through its tunnel: the addon agent, the
+// latency of the tunnel, the certificates of the proxy servers and their agent connections.
+func (o *Options) diagnose(
+	streams genericclioptions.IOStreams,
+	hubRestConfig *rest.Config,
+	addonClient addonv1alpha1client.Interface,
+	proxyConfig *proxyv1alpha1.ManagedProxyConfiguration,
+	tlsCfg *tls.Config) error {
+	kubeClient, err := kubernetes.NewForConfig(hubRestConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed building cilent")
+	}
+
+	var diagnostics []diagnostic
+	diagnostics = append(diagnostics, o.diagnoseAgent(addonClient))
+	diagnostics = append(diagnostics, o.diagnoseTunnel(tlsCfg))
+	for _, secret := range []string{inClusterSecretProxyCA, inClusterSecretServer, inClusterSecretClient} {
+		diagnostics = append(diagnostics, diagnoseCertificate(kubeClient, proxyConfig.Spec.ProxyServer.Namespace, secret, time.Now()))
+	}
+	diagnostics = append(diagnostics, diagnoseConnections(kubeClient, proxyConfig)...)
+
+	w := tabwriter.NewWriter(streams.Out, 4, 8, 4, ' ', 0)
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", "CHECK", "STATUS", "DETAIL")
+	failed := 0
+	for _, d := range diagnostics {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", d.check, d.status, d.detail)
+		if d.status == statusFailed {
+			failed++
+		}
+	}
+	_ = w.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of the proxy checks of cluster %s failed", failed, o.cluster)
+	}
+	return nil
+}
+
+func (o *Options) diagnoseAgent(addonClient addonv1alpha1client.Interface) diagnostic {
+	d := diagnostic{check: "addon agent"}
+	addon, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(o.cluster).Get(context.TODO(), common.AddonName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		d.status, d.detail = statusFailed, fmt.Sprintf("the addon is not enabled on cluster %s", o.cluster)
+		return d
+	case err != nil:
+		d.status, d.detail = statusFailed, err.Error()
+		return d
+	}
+	cond := meta.FindStatusCondition(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable)
+	switch {
+	case cond == nil:
+		d.status, d.detail = statusFailed, "the agent has not reported its status"
+	case cond.Status != metav1.ConditionTrue:
+		d.status, d.detail = statusFailed, fmt.Sprintf("the agent is not available: %s", cond.Message)
+	default:
+		d.status, d.detail = statusOK, "the agent is available"
+	}
+	return d
+}
+
+// diagnoseTunnel measures the round-trip latency of the healthz requests sent through the tunnel
+func (o *Options) diagnoseTunnel(tlsCfg *tls.Config) diagnostic {
+	d := diagnostic{check: "tunnel"}
+	var latencies []time.Duration
+	for i := 0; i < latencyProbes; i++ {
+		latency, err := o.probe(tlsCfg)
+		if err != nil {
+			d.status, d.detail = statusFailed, err.Error()
+			return d
+		}
+		latencies = append(latencies, latency)
+	}
+	min, max, total := latencies[0], latencies[0], time.Duration(0)
+	for _, latency := range latencies {
+		if latency < min {
+			min = latency
+		}
+		if latency > max {
+			max = latency
+		}
+		total += latency
+	}
+	d.status = statusOK
+	d.detail = fmt.Sprintf("round-trip latency min/avg/max %s/%s/%s", min, total/time.Duration(len(latencies)), max)
+	return d
+}
+
+func (o *Options) probe(tlsCfg *tls.Config) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tunnel, err := konnectivity.CreateSingleUseGrpcTunnel(
+		ctx,
+		net.JoinHostPort(o.proxyServerHost, strconv.Itoa(o.proxyServerPort)),
+		grpc.WithTransportCredentials(grpccredentials.NewTLS(tlsCfg)),
+	)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed starting konnectivity proxy")
+	}
+	return clusterproxy.Probe(ctx, o.cluster, tunnel.DialContext)
+}
+
+// diagnoseCertificate checks the validity of the certificate of the proxy secret
+func diagnoseCertificate(kubeClient kubernetes.Interface, namespace, name string, now time.Time) diagnostic {
+	d := diagnostic{check: fmt.Sprintf("certificate %s", name)}
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		d.status, d.detail = statusFailed, err.Error()
+		return d
+	}
+	data := secret.Data["tls.crt"]
+	if len(data) == 0 {
+		data = secret.Data["ca.crt"]
+	}
+	d.status, d.detail = certificateStatus(data, now)
+	return d
+}
+
+func certificateStatus(data []byte, now time.Time) (string, string) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return statusFailed, "no certificate found"
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return statusFailed, err.Error()
+	}
+	switch {
+	case now.Before(cert.NotBefore):
+		return statusFailed, fmt.Sprintf("not valid before %s", cert.NotBefore.Format(time.RFC3339))
+	case now.After(cert.NotAfter):
+		return statusFailed, fmt.Sprintf("expired at %s", cert.NotAfter.Format(time.RFC3339))
+	case cert.NotAfter.Sub(now) < certificateExpiryWarning:
+		return statusWarning, fmt.Sprintf("expires at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+	return statusOK, fmt.Sprintf("valid until %s", cert.NotAfter.Format(time.RFC3339))
+}
+
+// diagnoseConnections reports the agent connections of each proxy server, read from its metrics
+func diagnoseConnections(kubeClient kubernetes.Interface, proxyConfig *proxyv1alpha1.ManagedProxyConfiguration) []diagnostic {
+	namespace := proxyConfig.Spec.ProxyServer.Namespace
+	adminPort := int32(defaultAdminPort)
+	if proxyConfig.Spec.Deploy != nil && proxyConfig.Spec.Deploy.Ports.AdminServer != 0 {
+		adminPort = proxyConfig.Spec.Deploy.Ports.AdminServer
+	}
+
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: common.LabelKeyComponentName + "=" + common.ComponentNameProxyServer,
+	})
+	if err != nil {
+		return []diagnostic{{check: "agent connections", status: statusFailed, detail: err.Error()}}
+	}
+	if len(pods.Items) == 0 {
+		return []diagnostic{{check: "agent connections", status: statusFailed, detail: "no proxy server is running"}}
+	}
+
+	var diagnostics []diagnostic
+	for _, pod := range pods.Items {
+		d := diagnostic{check: fmt.Sprintf("agent connections %s", pod.Name)}
+		metrics, err := kubeClient.CoreV1().Pods(namespace).
+			ProxyGet("http", pod.Name, strconv.Itoa(int(adminPort)), "metrics", nil).
+			DoRaw(context.TODO())
+		if err != nil {
+			d.status, d.detail = statusWarning, fmt.Sprintf("failed reading the metrics: %v", err)
+			diagnostics = append(diagnostics, d)
+			continue
+		}
+		connections, ok := metricValue(metrics, readyBackendConnectionsMetric)
+		switch {
+		case !ok:
+			d.status, d.detail = statusWarning, fmt.Sprintf("metric %s is not reported", readyBackendConnectionsMetric)
+		case connections == 0:
+			d.status, d.detail = statusFailed, "no agent is connected"
+		default:
+			d.status, d.detail = statusOK, fmt.Sprintf("%d agent connections", connections)
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// metricValue sums the samples of the metric in the prometheus text format
+func metricValue(metrics []byte, name string) (int, bool) {
+	found := false
+	total := 0.0
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, name) {
+			continue
+		}
+		rest := strings.TrimPrefix(line, name)
+		if len(rest) == 0 || (rest[0] != ' ' && rest[0] != '{') {
+			continue
+		}
+		if i := strings.LastIndex(rest, "}"); i >= 0 {
+			rest = rest[i+1:]
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		total += value
+		found = true
+	}
+	return int(total), found
+}
@@ -0,0 +1,80 @@
+// Copyright Contributors to the Open Cluster Management project
+package health
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCertificateStatus(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := func(notBefore, notAfter time.Time) []byte {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "proxy-server"},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "valid", data: cert(now.Add(-time.Hour), now.AddDate(1, 0, 0)), expected: statusOK},
+		{name: "expiring", data: cert(now.Add(-time.Hour), now.AddDate(0, 0, 7)), expected: statusWarning},
+		{name: "expired", data: cert(now.AddDate(-1, 0, 0), now.Add(-time.Hour)), expected: statusFailed},
+		{name: "not yet valid", data: cert(now.Add(time.Hour), now.AddDate(1, 0, 0)), expected: statusFailed},
+		{name: "invalid", data: []byte("invalid"), expected: statusFailed},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if status, detail := certificateStatus(c.data, now); status != c.expected {
+				t.Errorf("expected %s, got %s: %s", c.expected, status, detail)
+			}
+		})
+	}
+}
+
+func TestMetricValue(t *testing.T) {
+	metrics := []byte(`# HELP konnectivity_network_proxy_server_ready_backend_connections Number of konnectivity agent connected to the proxy server
+# TYPE konnectivity_network_proxy_server_ready_backend_connections gauge
+konnectivity_network_proxy_server_ready_backend_connections 3
+konnectivity_network_proxy_server_ready_backend_connections_total 10
+konnectivity_network_proxy_server_pending_backend_dials{id="a"} 1
+konnectivity_network_proxy_server_pending_backend_dials{id="b"} 2
+`)
+	cases := []struct {
+		name          string
+		metric        string
+		expected      int
+		expectedFound bool
+	}{
+		{name: "gauge", metric: "konnectivity_network_proxy_server_ready_backend_connections", expected: 3, expectedFound: true},
+		{name: "labels", metric: "konnectivity_network_proxy_server_pending_backend_dials", expected: 3, expectedFound: true},
+		{name: "missing", metric: "konnectivity_network_proxy_server_dial_failure_count", expected: 0, expectedFound: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			value, found := metricValue(metrics, c.metric)
+			if value != c.expected || found != c.expectedFound {
+				t.Errorf("expected %d %v, got %d %v", c.expected, c.expectedFound, value, found)
+			}
+		})
+	}
+}
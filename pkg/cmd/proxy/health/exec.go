@@ -64,6 +64,9 @@ func (o *Options) validate() error {
 			return errors.New("--proxy-key must be set when in-cluster lookup is disabled")
 		}
 	}
+	if len(o.cluster) > 0 && len(o.clusters) > 0 {
+		return errors.New("--cluster and --clusters can only specify one")
+	}
 	return nil
 }
 
@@ -150,6 +153,10 @@ func (o *Options) run(streams genericclioptions.IOStreams) error {
 		return errors.Wrapf(err, "failed building tls config")
 	}
 
+	if len(o.cluster) > 0 {
+		return o.diagnose(streams, hubRestConfig, addonClient, proxyConfig, tlsCfg)
+	}
+
 	tunnel, err := konnectivity.CreateSingleUseGrpcTunnel(
 		ctx,
 		net.JoinHostPort(o.proxyServerHost, strconv.Itoa(o.proxyServerPort)),
@@ -12,6 +12,7 @@ type Options struct {
 	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
 
 	clusters                 []string
+	cluster                  string
 	inClusterProxyCertLookup bool
 	proxyClientCACertPath    string
 	proxyClientCertPath      string
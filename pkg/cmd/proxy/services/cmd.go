@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+package services
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+)
+
+var example = `
+# List the services of the managed cluster cluster1 which can be targeted by proxy service
+%[1]s proxy services --cluster cluster1 --sa test
+
+# List the services of the monitoring namespace
+%[1]s proxy services --cluster cluster1 --sa test --namespace monitoring
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:          "services",
+		Short:        "list the services of a managed cluster exposable through cluster-proxy",
+		Long:         "list the services of a managed cluster with their ports and the service resolvers exposing them, those can be targeted by proxy service",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "The name of the managed cluster")
+	cmd.Flags().StringVar(&o.managedServiceAccount, "sa", "", "The name of the managedServiceAccount used to list the services")
+	cmd.Flags().StringVar(&o.namespace, "namespace", "", "The namespace of the services, all the namespaces if not set")
+	cmd.Flags().IntVar(&o.proxyServerPort, "proxy-server-port", clusterproxy.DefaultProxyServerPort, "The local port forwarded to the proxy servers")
+
+	return cmd
+}
@@ -0,0 +1,136 @@
+// Copyright Contributors to the Open Cluster Management project
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	proxyv1alpha1 "open-cluster-management.io/cluster-proxy/pkg/apis/proxy/v1alpha1"
+	clusterproxyclient "open-cluster-management.io/cluster-proxy/pkg/generated/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+)
+
+func (o *Options) validate() error {
+	if len(o.cluster) == 0 {
+		return fmt.Errorf("cluster is required")
+	}
+	if len(o.managedServiceAccount) == 0 {
+		return fmt.Errorf("managedServiceAccount is required")
+	}
+	return nil
+}
+
+func (o *Options) run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hubRestConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return errors.Wrapf(err, "failed loading hub cluster's client config")
+	}
+	proxyConfig, err := clusterproxy.GetProxyConfig(ctx, hubRestConfig)
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(hubRestConfig)
+	if err != nil {
+		return err
+	}
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(ctx, o.cluster, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	proxyClient, err := clusterproxyclient.NewForConfig(hubRestConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed initializing proxy api client")
+	}
+	resolvers, err := proxyClient.ProxyV1alpha1().ManagedProxyServiceResolvers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed listing managedproxyserviceresolvers")
+	}
+
+	token, err := clusterproxy.ManagedServiceAccountToken(ctx, hubRestConfig, o.managedServiceAccount, o.cluster)
+	if err != nil {
+		return err
+	}
+	dial, closeFn, err := clusterproxy.Dial(ctx, hubRestConfig, proxyConfig, o.proxyServerPort)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	kubeClient, err := kubernetes.NewForConfig(clusterproxy.ManagedClusterConfig(o.cluster, token, dial))
+	if err != nil {
+		return err
+	}
+	services, err := kubeClient.CoreV1().Services(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed listing the services of cluster %s", o.cluster)
+	}
+
+	clusterSet := cluster.Labels[clusterv1beta1.ClusterSetLabel]
+	sort.Slice(services.Items, func(i, j int) bool {
+		if services.Items[i].Namespace != services.Items[j].Namespace {
+			return services.Items[i].Namespace < services.Items[j].Namespace
+		}
+		return services.Items[i].Name < services.Items[j].Name
+	})
+
+	w := tabwriter.NewWriter(o.Streams.Out, 4, 8, 4, ' ', 0)
+	fmt.Fprintf(w, "NAMESPACE\tNAME\tTYPE\tPORTS\tRESOLVER\n")
+	for _, svc := range services.Items {
+		resolver := resolverOf(resolvers.Items, clusterSet, svc.Namespace, svc.Name)
+		if len(resolver) == 0 {
+			resolver = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", svc.Namespace, svc.Name, svc.Spec.Type, ports(svc.Spec.Ports), resolver)
+	}
+	return w.Flush()
+}
+
+// resolverOf returns the name of the available service resolver exposing the service from the clusterset
+func resolverOf(resolvers []proxyv1alpha1.ManagedProxyServiceResolver, clusterSet, namespace, name string) string {
+	if len(clusterSet) == 0 {
+		return ""
+	}
+	for _, resolver := range resolvers {
+		clusterSelector := resolver.Spec.ManagedClusterSelector.ManagedClusterSet
+		serviceRef := resolver.Spec.ServiceSelector.ServiceRef
+		if clusterSelector == nil || serviceRef == nil {
+			continue
+		}
+		if clusterSelector.Name != clusterSet || serviceRef.Namespace != namespace || serviceRef.Name != name {
+			continue
+		}
+		if !meta.IsStatusConditionTrue(resolver.Status.Conditions, proxyv1alpha1.ConditionTypeServiceResolverAvaliable) {
+			continue
+		}
+		return resolver.Name
+	}
+	return ""
+}
+
+func ports(servicePorts []corev1.ServicePort) string {
+	if len(servicePorts) == 0 {
+		return "<none>"
+	}
+	var result []string
+	for _, port := range servicePorts {
+		if len(port.Name) > 0 {
+			result = append(result, fmt.Sprintf("%s:%d/%s", port.Name, port.Port, port.Protocol))
+			continue
+		}
+		result = append(result, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+	}
+	return strings.Join(result, ",")
+}
@@ -0,0 +1,51 @@
+// Copyright Contributors to the Open Cluster Management project
+package services
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	proxyv1alpha1 "open-cluster-management.io/cluster-proxy/pkg/apis/proxy/v1alpha1"
+)
+
+func TestResolverOf(t *testing.T) {
+	resolver := func(name, clusterSet, namespace, service string, available metav1.ConditionStatus) proxyv1alpha1.ManagedProxyServiceResolver {
+		return proxyv1alpha1.ManagedProxyServiceResolver{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: proxyv1alpha1.ManagedProxyServiceResolverSpec{
+				ManagedClusterSelector: proxyv1alpha1.ManagedClusterSelector{
+					ManagedClusterSet: &proxyv1alpha1.ManagedClusterSet{Name: clusterSet},
+				},
+				ServiceSelector: proxyv1alpha1.ServiceSelector{
+					ServiceRef: &proxyv1alpha1.ServiceRef{Namespace: namespace, Name: service},
+				},
+			},
+			Status: proxyv1alpha1.ManagedProxyServiceResolverStatus{
+				Conditions: []metav1.Condition{{Type: proxyv1alpha1.ConditionTypeServiceResolverAvaliable, Status: available}},
+			},
+		}
+	}
+	resolvers := []proxyv1alpha1.ManagedProxyServiceResolver{
+		resolver("prom", "dev", "monitoring", "prometheus", metav1.ConditionTrue),
+		resolver("grafana", "dev", "monitoring", "grafana", metav1.ConditionFalse),
+	}
+	cases := []struct {
+		name       string
+		clusterSet string
+		service    string
+		expected   string
+	}{
+		{name: "exposed", clusterSet: "dev", service: "prometheus", expected: "prom"},
+		{name: "other clusterset", clusterSet: "prod", service: "prometheus", expected: ""},
+		{name: "no clusterset", service: "prometheus", expected: ""},
+		{name: "not available", clusterSet: "dev", service: "grafana", expected: ""},
+		{name: "not exposed", clusterSet: "dev", service: "alertmanager", expected: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if resolver := resolverOf(resolvers, c.clusterSet, "monitoring", c.service); resolver != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, resolver)
+			}
+		})
+	}
+}
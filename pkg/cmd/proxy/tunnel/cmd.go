@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+package tunnel
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Serve a local SOCKS5 and HTTP proxy forwarding the connections into the managed cluster cluster1
+%[1]s proxy tunnel --cluster cluster1 --listen 127.0.0.1:1080
+
+# Request the prometheus service of the monitoring namespace through the proxy, the service must be
+# exposed by a ManagedProxyServiceResolver, see '%[1]s proxy services'
+curl --proxy socks5h://127.0.0.1:1080 http://prometheus.monitoring:9090/metrics
+curl -x http://127.0.0.1:1080 http://prometheus.monitoring:9090/metrics
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "tunnel",
+		Short: "serve a local SOCKS5 and HTTP proxy into a managed cluster",
+		Long: "serve a local SOCKS5 and HTTP proxy forwarding the TCP connections into the network of a managed cluster through the cluster-proxy tunnel. " +
+			"The hosts <service>.<namespace>[.svc] are mapped to the services exposed by a ManagedProxyServiceResolver, the name of the cluster to its kube-apiserver.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run(c.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "The name of the managed cluster")
+	cmd.Flags().StringVar(&o.listen, "listen", "127.0.0.1:1080", "The local address of the SOCKS5 and HTTP proxy")
+	cmd.Flags().IntVar(&o.proxyServerPort, "proxy-server-port", clusterproxy.DefaultProxyServerPort, "The local port forwarded to the proxy servers")
+
+	return cmd
+}
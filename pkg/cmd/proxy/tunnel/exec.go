@@ -0,0 +1,98 @@
+// Copyright Contributors to the Open Cluster Management project
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	"open-cluster-management.io/cluster-proxy/pkg/util"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clusterproxy"
+)
+
+func (o *Options) validate() error {
+	if len(o.cluster) == 0 {
+		return fmt.Errorf("cluster is required")
+	}
+	if _, _, err := net.SplitHostPort(o.listen); err != nil {
+		return fmt.Errorf("invalid listen address %q: %v", o.listen, err)
+	}
+	return nil
+}
+
+func (o *Options) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	hubRestConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return errors.Wrapf(err, "failed loading hub cluster's client config")
+	}
+	clusterClient, err := clusterclientset.NewForConfig(hubRestConfig)
+	if err != nil {
+		return err
+	}
+	if _, err := clusterClient.ClusterV1().ManagedClusters().Get(ctx, o.cluster, metav1.GetOptions{}); err != nil {
+		return err
+	}
+	proxyConfig, err := clusterproxy.GetProxyConfig(ctx, hubRestConfig)
+	if err != nil {
+		return err
+	}
+	dial, closeFn, err := clusterproxy.Dial(ctx, hubRestConfig, proxyConfig, o.proxyServerPort)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	listener, err := net.Listen("tcp", o.listen)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	fmt.Fprintf(o.Streams.Out, "Serving the SOCKS5 and HTTP proxy to cluster %s on %s, press Ctrl+C to stop\n", o.cluster, listener.Addr())
+	s := &server{
+		dial: func(ctx context.Context, address string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			return dial(ctx, "tcp", net.JoinHostPort(resolve(o.cluster, host), port))
+		},
+	}
+	err = s.serve(ctx, listener)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// resolve maps the host to the host routed by the cluster-proxy tunnel: the name of the cluster
+// reaches its kube-apiserver and <service>.<namespace>[.svc[.cluster.local]] reaches the service
+// exposed by a ManagedProxyServiceResolver. The other hosts are kept.
+func resolve(cluster, host string) string {
+	if host == cluster || net.ParseIP(host) != nil {
+		return host
+	}
+	parts := strings.Split(strings.TrimSuffix(host, "."), ".")
+	switch {
+	case len(parts) == 2,
+		len(parts) == 3 && parts[2] == "svc",
+		len(parts) == 5 && parts[2] == "svc" && parts[3] == "cluster" && parts[4] == "local":
+		return util.GenerateServiceURL(cluster, parts[1], parts[0])
+	}
+	return host
+}
@@ -0,0 +1,201 @@
+// Copyright Contributors to the Open Cluster Management project
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	socksVersion5 = 0x05
+
+	socksAuthNone         = 0x00
+	socksAuthNoAcceptable = 0xff
+
+	socksCmdConnect = 0x01
+
+	socksAddrIPv4   = 0x01
+	socksAddrDomain = 0x03
+	socksAddrIPv6   = 0x04
+
+	socksReplySucceeded        = 0x00
+	socksReplyGeneralFailure   = 0x01
+	socksReplyHostUnreachable  = 0x04
+	socksReplyCmdNotSupported  = 0x07
+	socksReplyAddrNotSupported = 0x08
+)
+
+// server is a SOCKS5 and HTTP proxy forwarding the connections with its dial function, the
+// protocol of a connection is detected from its first byte.
+type server struct {
+	dial func(ctx context.Context, address string) (net.Conn, error)
+}
+
+func (s *server) serve(ctx context.Context, listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(ctx, conn)
+	}
+}
+
+func (s *server) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	if err != nil {
+		return
+	}
+	var upstream net.Conn
+	if first[0] == socksVersion5 {
+		upstream, err = s.handleSOCKS5(ctx, reader, conn)
+	} else {
+		upstream, err = s.handleHTTP(ctx, reader, conn)
+	}
+	if err != nil {
+		klog.V(2).InfoS("failed to proxy the connection", "client", conn.RemoteAddr(), "error", err)
+		return
+	}
+	defer upstream.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, reader)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// handleSOCKS5 negotiates a SOCKS5 CONNECT without authentication and dials its destination
+func (s *server) handleSOCKS5(ctx context.Context, reader *bufio.Reader, conn net.Conn) (net.Conn, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, methods); err != nil {
+		return nil, err
+	}
+	method := byte(socksAuthNoAcceptable)
+	for _, m := range methods {
+		if m == socksAuthNone {
+			method = socksAuthNone
+		}
+	}
+	if _, err := conn.Write([]byte{socksVersion5, method}); err != nil {
+		return nil, err
+	}
+	if method == socksAuthNoAcceptable {
+		return nil, fmt.Errorf("the client does not support the authentication method none")
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(reader, request); err != nil {
+		return nil, err
+	}
+	if request[1] != socksCmdConnect {
+		_ = socksReply(conn, socksReplyCmdNotSupported)
+		return nil, fmt.Errorf("unsupported SOCKS5 command %d", request[1])
+	}
+	var host string
+	switch request[3] {
+	case socksAddrIPv4, socksAddrIPv6:
+		ip := make([]byte, net.IPv4len)
+		if request[3] == socksAddrIPv6 {
+			ip = make([]byte, net.IPv6len)
+		}
+		if _, err := io.ReadFull(reader, ip); err != nil {
+			return nil, err
+		}
+		host = net.IP(ip).String()
+	case socksAddrDomain:
+		length, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		domain := make([]byte, length)
+		if _, err := io.ReadFull(reader, domain); err != nil {
+			return nil, err
+		}
+		host = string(domain)
+	default:
+		_ = socksReply(conn, socksReplyAddrNotSupported)
+		return nil, fmt.Errorf("unsupported SOCKS5 address type %d", request[3])
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(reader, port); err != nil {
+		return nil, err
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+	upstream, err := s.dial(ctx, address)
+	if err != nil {
+		_ = socksReply(conn, socksReplyHostUnreachable)
+		return nil, fmt.Errorf("failed to dial %s: %v", address, err)
+	}
+	if err := socksReply(conn, socksReplySucceeded); err != nil {
+		upstream.Close()
+		return nil, err
+	}
+	return upstream, nil
+}
+
+func socksReply(conn net.Conn, reply byte) error {
+	// the bound address is not known through the tunnel
+	_, err := conn.Write([]byte{socksVersion5, reply, 0x00, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+// handleHTTP serves a CONNECT request, or forwards a plain http request to its host
+func (s *server) handleHTTP(ctx context.Context, reader *bufio.Reader, conn net.Conn) (net.Conn, error) {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	address := req.Host
+	if req.Method != http.MethodConnect && req.URL.Host != "" {
+		address = req.URL.Host
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "80")
+	}
+
+	upstream, err := s.dial(ctx, address)
+	if err != nil {
+		_, _ = fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\n\r\n", http.StatusBadGateway, http.StatusText(http.StatusBadGateway))
+		return nil, fmt.Errorf("failed to dial %s: %v", address, err)
+	}
+
+	if req.Method == http.MethodConnect {
+		if _, err := fmt.Fprintf(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+			upstream.Close()
+			return nil, err
+		}
+		return upstream, nil
+	}
+
+	// the following requests of the connection are forwarded to the same host
+	req.Header.Del("Proxy-Connection")
+	req.Header.Del("Proxy-Authorization")
+	if err := req.Write(upstream); err != nil {
+		upstream.Close()
+		return nil, err
+	}
+	return upstream, nil
+}
@@ -0,0 +1,136 @@
+// Copyright Contributors to the Open Cluster Management project
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"open-cluster-management.io/cluster-proxy/pkg/util"
+)
+
+func TestResolve(t *testing.T) {
+	cases := []struct {
+		host     string
+		expected string
+	}{
+		{host: "cluster1", expected: "cluster1"},
+		{host: "10.0.0.1", expected: "10.0.0.1"},
+		{host: "postgres.db", expected: util.GenerateServiceURL("cluster1", "db", "postgres")},
+		{host: "postgres.db.svc", expected: util.GenerateServiceURL("cluster1", "db", "postgres")},
+		{host: "postgres.db.svc.cluster.local", expected: util.GenerateServiceURL("cluster1", "db", "postgres")},
+		{host: "example.com.internal", expected: "example.com.internal"},
+	}
+	for _, c := range cases {
+		t.Run(c.host, func(t *testing.T) {
+			if host := resolve("cluster1", c.host); host != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, host)
+			}
+		})
+	}
+}
+
+// serve runs the server on one end of a pipe, the dialed addresses are sent to the channel
+// and answered by an echo connection
+func serve(t *testing.T) (net.Conn, chan string) {
+	dialed := make(chan string, 1)
+	s := &server{
+		dial: func(ctx context.Context, address string) (net.Conn, error) {
+			dialed <- address
+			client, upstream := net.Pipe()
+			go func() {
+				_, _ = io.Copy(upstream, upstream)
+			}()
+			return client, nil
+		},
+	}
+	client, conn := net.Pipe()
+	go s.handle(context.TODO(), conn)
+	t.Cleanup(func() { client.Close() })
+	return client, dialed
+}
+
+func TestSOCKS5(t *testing.T) {
+	client, dialed := serve(t)
+
+	go func() {
+		_, _ = client.Write([]byte{socksVersion5, 1, socksAuthNone})
+	}()
+	method := make([]byte, 2)
+	if _, err := io.ReadFull(client, method); err != nil {
+		t.Fatal(err)
+	}
+	if method[1] != socksAuthNone {
+		t.Fatalf("expected the method none, got %d", method[1])
+	}
+
+	request := append([]byte{socksVersion5, socksCmdConnect, 0x00, socksAddrDomain, byte(len("postgres.db"))}, "postgres.db"...)
+	request = append(request, 0x15, 0x38)
+	go func() {
+		_, _ = client.Write(request)
+	}()
+	reply := make([]byte, 10)
+	if _, err := io.ReadFull(client, reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply[1] != socksReplySucceeded {
+		t.Fatalf("expected the reply succeeded, got %d", reply[1])
+	}
+	if address := <-dialed; address != "postgres.db:5432" {
+		t.Errorf("expected to dial postgres.db:5432, got %s", address)
+	}
+
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+	}()
+	echo := make([]byte, 4)
+	if _, err := io.ReadFull(client, echo); err != nil {
+		t.Fatal(err)
+	}
+	if string(echo) != "ping" {
+		t.Errorf("expected ping, got %s", echo)
+	}
+}
+
+func TestHTTPConnect(t *testing.T) {
+	client, dialed := serve(t)
+
+	go func() {
+		_, _ = client.Write([]byte("CONNECT prometheus.monitoring:9090 HTTP/1.1\r\nHost: prometheus.monitoring:9090\r\n\r\n"))
+	}()
+	resp, err := http.ReadResponse(bufio.NewReader(client), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if address := <-dialed; address != "prometheus.monitoring:9090" {
+		t.Errorf("expected to dial prometheus.monitoring:9090, got %s", address)
+	}
+}
+
+func TestHTTPForward(t *testing.T) {
+	client, dialed := serve(t)
+
+	go func() {
+		_, _ = client.Write([]byte("GET http://prometheus.monitoring/metrics HTTP/1.1\r\nHost: prometheus.monitoring\r\nProxy-Connection: keep-alive\r\n\r\n"))
+	}()
+	// the upstream echoes the forwarded request
+	req, err := http.ReadRequest(bufio.NewReader(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := <-dialed; address != "prometheus.monitoring:80" {
+		t.Errorf("expected to dial prometheus.monitoring:80, got %s", address)
+	}
+	if req.RequestURI != "/metrics" {
+		t.Errorf("expected the request uri /metrics, got %s", req.RequestURI)
+	}
+	if len(req.Header.Get("Proxy-Connection")) > 0 {
+		t.Errorf("expected the proxy headers to be removed")
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright Contributors to the Open Cluster Management project
+package renew
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/renew/join"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the commands renewing the credentials of a cluster
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "renew",
+		Short: "renew the credentials of a cluster",
+	}
+
+	cmd.AddCommand(join.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
@@ -0,0 +1,57 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+var example = `
+# Get a new token on the hub
+%[1]s get token --context hub
+# Renew the bootstrap kubeconfig of the managed cluster with the new token and restart the registration agent
+%[1]s renew join --cluster cluster1 --hub-token <tokenID.tokenSecret> --context cluster1
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "join",
+		Short: "renew the bootstrap kubeconfig of a joined cluster",
+		Long: "replace the token of the bootstrap hub kubeconfig on the managed cluster with a new token of the hub and restart " +
+			"the registration agent, it recovers the clusters whose bootstrap token expired before they were registered.",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.clusterName, "cluster", "", "The name of the joined cluster, it must match the cluster name of the klusterlet")
+	cmd.Flags().StringVar(&o.token, "hub-token", "", "The new token to access the hub")
+	cmd.Flags().StringVar(&o.hubAPIServer, "hub-apiserver", "", "The api server url to the hub, the one of the current bootstrap kubeconfig if not set")
+	cmd.Flags().StringVar(&o.caFile, "ca-file", "", "the file path to hub ca, the one of the current bootstrap kubeconfig if not set")
+	cmd.Flags().BoolVar(&o.wait, "wait", true, "Wait for the restarted registration agent to be ready")
+
+	return cmd
+}
@@ -0,0 +1,187 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+)
+
+const (
+	klusterletName       = "klusterlet"
+	agentNamespace       = "open-cluster-management-agent"
+	bootstrapSecretName  = "bootstrap-hub-kubeconfig"
+	hubSecretName        = "hub-kubeconfig-secret"
+	kubeconfigSecretKey  = "kubeconfig"
+	registrationSelector = "app=klusterlet-registration-agent"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("renew join options:", "dry-run", o.ClusteradmFlags.DryRun, "cluster", o.clusterName, "api-server", o.hubAPIServer, "wait", o.wait)
+
+	if o.caFile != "" {
+		o.HubCAData, err = os.ReadFile(o.caFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) validate() error {
+	if err := o.ClusteradmFlags.ValidateManagedCluster(); err != nil {
+		return err
+	}
+	if o.clusterName == "" {
+		return fmt.Errorf("the cluster name must be specified in --cluster")
+	}
+	if o.token == "" {
+		return fmt.Errorf("token is missing")
+	}
+
+	return nil
+}
+
+func (o *Options) run() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	klusterlet, err := operatorClient.OperatorV1().Klusterlets().Get(context.TODO(), klusterletName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the klusterlet, is the cluster joined? %v", err)
+	}
+	if klusterlet.Spec.ClusterName != o.clusterName {
+		return fmt.Errorf("the cluster is joined as %s instead of %s", klusterlet.Spec.ClusterName, o.clusterName)
+	}
+	namespace := agentNamespace
+	if len(klusterlet.Spec.Namespace) > 0 {
+		namespace = klusterlet.Spec.Namespace
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), bootstrapSecretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the bootstrap kubeconfig of cluster %s: %v", o.clusterName, err)
+	}
+	kubeconfig, err := renewKubeconfig(secret.Data[kubeconfigSecretKey], o.token, o.hubAPIServer, o.HubCAData)
+	if err != nil {
+		return fmt.Errorf("failed to renew the bootstrap kubeconfig in secret %s/%s: %v", namespace, bootstrapSecretName, err)
+	}
+
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "Secret %s/%s is renewed, the registration agent of cluster %s is restarted\n", namespace, bootstrapSecretName, o.clusterName)
+		return nil
+	}
+
+	secret.Data[kubeconfigSecretKey] = kubeconfig
+	if _, err := kubeClient.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "Secret %s/%s is renewed\n", namespace, bootstrapSecretName)
+
+	// the registration agent bootstraps again without a valid hub kubeconfig
+	err = kubeClient.CoreV1().Secrets(namespace).Delete(context.TODO(), hubSecretName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	restartedAt := time.Now()
+	err = kubeClient.CoreV1().Pods(namespace).DeleteCollection(context.TODO(), metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: registrationSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restart the registration agent: %v", err)
+	}
+	fmt.Fprintf(o.Streams.Out, "Registration agent of cluster %s is restarted\n", o.clusterName)
+
+	if !o.wait {
+		return nil
+	}
+	return o.waitForRegistrationAgent(kubeClient, namespace, restartedAt)
+}
+
+// renewKubeconfig replaces the credentials of the current context of the kubeconfig with the token,
+// the server and the ca are replaced if they are set.
+func renewKubeconfig(data []byte, token, server string, ca []byte) ([]byte, error) {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, err
+	}
+	current, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("the current context %q is not found", config.CurrentContext)
+	}
+	authInfo, ok := config.AuthInfos[current.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("the user %q of the current context is not found", current.AuthInfo)
+	}
+	cluster, ok := config.Clusters[current.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("the cluster %q of the current context is not found", current.Cluster)
+	}
+
+	authInfo.Token = token
+	authInfo.TokenFile = ""
+	authInfo.ClientCertificate, authInfo.ClientCertificateData = "", nil
+	authInfo.ClientKey, authInfo.ClientKeyData = "", nil
+	// the credential plugins take precedence over the token
+	authInfo.Exec, authInfo.AuthProvider = nil, nil
+	if len(server) > 0 {
+		cluster.Server = server
+	}
+	if len(ca) > 0 {
+		cluster.CertificateAuthority = ""
+		cluster.CertificateAuthorityData = ca
+	}
+
+	return clientcmd.Write(*config)
+}
+
+// waitForRegistrationAgent waits for a registration agent started after the restart to be ready
+func (o *Options) waitForRegistrationAgent(kubeClient kubernetes.Interface, namespace string, restartedAt time.Time) error {
+	err := wait.PollImmediate(2*time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		pods, err := kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: registrationSelector,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range pods.Items {
+			if pod.CreationTimestamp.Time.Before(restartedAt.Truncate(time.Second)) || pod.DeletionTimestamp != nil {
+				continue
+			}
+			for _, condition := range pod.Status.Conditions {
+				if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the registration agent of cluster %s is not ready: %v", o.clusterName, err)
+	}
+	fmt.Fprintf(o.Streams.Out, "Registration agent is ready, accept the cluster on the hub if it is not accepted yet:\n\n    clusteradm accept --clusters %s\n\n", o.clusterName)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestRenewKubeconfig(t *testing.T) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["hub"] = &clientcmdapi.Cluster{Server: "https://hub:6443", CertificateAuthorityData: []byte("ca")}
+	config.AuthInfos["bootstrap"] = &clientcmdapi.AuthInfo{Token: "expired", ClientKeyData: []byte("key"),
+		Exec: &clientcmdapi.ExecConfig{Command: "sso-login", APIVersion: "client.authentication.k8s.io/v1beta1"}}
+	config.Contexts["bootstrap"] = &clientcmdapi.Context{Cluster: "hub", AuthInfo: "bootstrap"}
+	config.CurrentContext = "bootstrap"
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renewed, err := renewKubeconfig(data, "new-token", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := clientcmd.Load(renewed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authInfo := actual.AuthInfos["bootstrap"]; authInfo.Token != "new-token" || len(authInfo.ClientKeyData) != 0 || authInfo.Exec != nil {
+		t.Errorf("expected the token to be replaced, got %#v", authInfo)
+	}
+	if cluster := actual.Clusters["hub"]; cluster.Server != "https://hub:6443" || string(cluster.CertificateAuthorityData) != "ca" {
+		t.Errorf("expected the cluster to be kept, got %#v", cluster)
+	}
+
+	renewed, err = renewKubeconfig(data, "new-token", "https://hub2:6443", []byte("ca2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err = clientcmd.Load(renewed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster := actual.Clusters["hub"]; cluster.Server != "https://hub2:6443" || string(cluster.CertificateAuthorityData) != "ca2" {
+		t.Errorf("expected the cluster to be replaced, got %#v", cluster)
+	}
+
+	if _, err := renewKubeconfig([]byte("apiVersion: v1\nkind: Config\n"), "new-token", "", nil); err == nil {
+		t.Errorf("expected an error without current context")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// Options: The structure holding all the command-line options
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	//The name of the joined cluster
+	clusterName string
+	//The new token generated on the hub to access it from the cluster
+	token string
+	//The external hub apiserver url (https://<host>:<port>)
+	hubAPIServer string
+	//The hub ca-file(optional)
+	caFile string
+	//Wait for the restarted registration agent to be ready
+	wait bool
+
+	//HubCAData: data in hub ca file
+	HubCAData []byte
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright Contributors to the Open Cluster Management project
+package rollout
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/rollout/work"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the commands rolling out resources to the clusters
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "roll out a resource to the clusters progressively",
+	}
+
+	cmd.AddCommand(work.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Roll out the manifestwork to the canary cluster only
+%[1]s rollout work work1 -f xxx.yaml --placement default/placement1 --canary-clusters cluster1 --wait-available
+# Roll out the manifestwork to the canary cluster, then to the other clusters once it is available
+%[1]s rollout work work1 -f xxx.yaml --placement default/placement1 --canary-clusters cluster1 --wait-available --then-all
+# Wait for the ready replicas reported in the status feedback before rolling out to the other clusters
+%[1]s rollout work work1 -f xxx.yaml --clusters cluster1,cluster2,cluster3 --canary-clusters cluster1 \
+  --wait-available --wait-feedback ReadyReplicas=3 --then-all
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "work",
+		Short: "roll out a work to canary clusters first",
+		Long: "create or update a manifestwork on the canary clusters, wait for it to be available, then roll it out to the other clusters " +
+			"following the rollout strategy of the work. The clusters rolled out are reverted if the work fails to be available.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			clusteradmhelpers.DryRunMessage(clusteradmFlags.DryRun)
+
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.clusters, "clusters", []string{}, "Names of the managed clusters (comma separated) the work is rolled out to")
+	cmd.Flags().StringVar(&o.placement, "placement", "", "The placement selecting the clusters the work is rolled out to, in the format of <namespace>/<name>")
+	cmd.Flags().StringSliceVar(&o.canaryClusters, "canary-clusters", []string{}, "Names of the managed clusters (comma separated) the work is rolled out to first")
+	cmd.Flags().BoolVar(&o.waitAvailable, "wait-available", false, "Wait for the work to be available on each batch of clusters before rolling out to the next one")
+	cmd.Flags().StringSliceVar(&o.waitFeedback, "wait-feedback", []string{},
+		"Status feedback values (comma separated) the work should report before rolling out to the next batch, in the format of <value name>=<value>")
+	cmd.Flags().BoolVar(&o.thenAll, "then-all", false, "Roll out to the other clusters once the canary clusters are available")
+	cmd.Flags().BoolVar(&o.rollback, "rollback", true, "Revert the clusters rolled out if the work fails to be available")
+	o.FileNameFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,353 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	if len(args) != 1 {
+		return fmt.Errorf("the name of the work must be specified")
+	}
+	o.workName = args[0]
+
+	klog.V(1).InfoS("rollout work options:", "work", o.workName, "clusters", o.clusters, "placement", o.placement,
+		"canary-clusters", o.canaryClusters, "wait-available", o.waitAvailable, "wait-feedback", o.waitFeedback,
+		"then-all", o.thenAll, "rollback", o.rollback)
+
+	o.feedback = map[string]string{}
+	for _, feedback := range o.waitFeedback {
+		parts := strings.SplitN(feedback, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return fmt.Errorf("invalid --wait-feedback %q, should be in the format of <value name>=<value>", feedback)
+		}
+		o.feedback[parts[0]] = parts[1]
+	}
+
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	err = o.ClusteradmFlags.ValidateHub()
+	if err != nil {
+		return err
+	}
+
+	if len(o.clusters) == 0 && len(o.placement) == 0 {
+		return fmt.Errorf("--clusters or --placement must be specified")
+	}
+	if len(o.clusters) > 0 && len(o.placement) > 0 {
+		return fmt.Errorf("--clusters and --placement can only specify one")
+	}
+	if len(o.placement) > 0 && len(strings.Split(o.placement, "/")) != 2 {
+		return fmt.Errorf("the name of the placement %s must be in the format of <namespace>/<name>", o.placement)
+	}
+	if len(o.canaryClusters) == 0 {
+		return fmt.Errorf("--canary-clusters must be specified")
+	}
+	if len(o.feedback) > 0 && !o.waitAvailable {
+		return fmt.Errorf("--wait-feedback requires --wait-available")
+	}
+	if len(*o.FileNameFlags.Filenames) == 0 {
+		return fmt.Errorf("manifest files must be specified")
+	}
+
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	manifests, err := o.readManifests()
+	if err != nil {
+		return err
+	}
+	clusters, err := o.getClusters(clusterClient)
+	if err != nil {
+		return err
+	}
+
+	// the previous works are restored on rollback, and hold the rollout strategy
+	previous := map[string]*workapiv1.ManifestWork{}
+	strategy := &rollout.Strategy{Type: rollout.TypeAll}
+	for _, cluster := range clusters {
+		work, err := workClient.WorkV1().ManifestWorks(cluster.Name).Get(context.TODO(), o.workName, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			continue
+		case err != nil:
+			return err
+		}
+		previous[cluster.Name] = work
+		if s, err := rollout.FromAnnotations(work.Annotations); err == nil && s.Type != rollout.TypeAll {
+			strategy = s
+		}
+		if rollout.IsPaused(work) {
+			return fmt.Errorf("the rollout of work %s is paused in cluster %s, run '%s patch work %s --resume' to resume it",
+				o.workName, cluster.Name, helpers.GetExampleHeader(), o.workName)
+		}
+	}
+
+	batches, err := plan(o.canaryClusters, clusters, strategy)
+	if err != nil {
+		return err
+	}
+	if !o.thenAll {
+		batches = batches[:1]
+	}
+	if o.ClusteradmFlags.DryRun {
+		for i, batch := range batches {
+			fmt.Fprintf(o.Streams.Out, "batch %d: work %s would be rolled out to %s\n", i+1, o.workName, strings.Join(batch, ","))
+		}
+		return nil
+	}
+
+	var rolledOut []string
+	for i, batch := range batches {
+		fmt.Fprintf(o.Streams.Out, "batch %d: rolling out work %s to %s\n", i+1, o.workName, strings.Join(batch, ","))
+		err := o.applyWork(workClient, manifests, batch, previous)
+		rolledOut = append(rolledOut, batch...)
+		if err == nil && o.waitAvailable {
+			err = o.waitUntilAvailable(workClient, batch)
+		}
+		if err != nil {
+			if o.rollback {
+				fmt.Fprintf(o.Streams.Out, "rollout of work %s failed, reverting the clusters %s\n", o.workName, strings.Join(rolledOut, ","))
+				if rollbackErr := o.revert(workClient, rolledOut, previous); rollbackErr != nil {
+					fmt.Fprintf(o.Streams.ErrOut, "failed to revert work %s: %v\n", o.workName, rollbackErr)
+				}
+			}
+			return err
+		}
+	}
+
+	if !o.thenAll && len(clusters) > len(batches[0]) {
+		fmt.Fprintf(o.Streams.Out, "work %s is rolled out to the canary clusters, add --then-all to roll it out to the other clusters\n", o.workName)
+		return nil
+	}
+	fmt.Fprintf(o.Streams.Out, "work %s is rolled out to %d clusters\n", o.workName, len(rolledOut))
+	return nil
+}
+
+// plan returns the canary clusters as the first batch, followed by the batches of the other
+// clusters following the strategy.
+func plan(canaryClusters []string, clusters []clusterv1.ManagedCluster, strategy *rollout.Strategy) ([][]string, error) {
+	names := sets.NewString()
+	for _, cluster := range clusters {
+		names.Insert(cluster.Name)
+	}
+	canaries := sets.NewString(canaryClusters...)
+	if missing := canaries.Difference(names); missing.Len() > 0 {
+		return nil, fmt.Errorf("the canary clusters %v are not in the clusters the work is rolled out to", missing.List())
+	}
+
+	var others []clusterv1.ManagedCluster
+	for _, cluster := range clusters {
+		if !canaries.Has(cluster.Name) {
+			others = append(others, cluster)
+		}
+	}
+	return append([][]string{canaries.List()}, strategy.Batches(others)...), nil
+}
+
+func (o *Options) readManifests() ([]workapiv1.Manifest, error) {
+	opt := o.FileNameFlags.ToOptions()
+	result := resource.NewLocalBuilder().
+		Unstructured().
+		FilenameParam(false, &opt).
+		Flatten().
+		ContinueOnError().
+		Do()
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := result.Infos()
+	if err != nil {
+		return nil, err
+	}
+	manifests := []workapiv1.Manifest{}
+	for _, item := range items {
+		manifests = append(manifests, workapiv1.Manifest{RawExtension: runtime.RawExtension{Object: item.Object}})
+	}
+	return manifests, nil
+}
+
+// getClusters returns the clusters of --clusters or selected by the placement
+func (o *Options) getClusters(clusterClient clusterclientset.Interface) ([]clusterv1.ManagedCluster, error) {
+	names := o.clusters
+	if len(o.placement) > 0 {
+		parts := strings.Split(o.placement, "/")
+		decisions, err := clusterClient.ClusterV1beta1().PlacementDecisions(parts[0]).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", clusterv1beta1.PlacementLabel, parts[1]),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, decision := range decisions.Items {
+			for _, d := range decision.Status.Decisions {
+				names = append(names, d.ClusterName)
+			}
+		}
+	}
+
+	var clusters []clusterv1.ManagedCluster
+	for _, name := range sets.NewString(names...).List() {
+		cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, *cluster)
+	}
+	return clusters, nil
+}
+
+func (o *Options) applyWork(workClient workclientset.Interface, manifests []workapiv1.Manifest, clusters []string, previous map[string]*workapiv1.ManifestWork) error {
+	for _, clusterName := range clusters {
+		if work, ok := previous[clusterName]; ok {
+			work = work.DeepCopy()
+			work.Spec.Workload.Manifests = manifests
+			if _, err := workClient.WorkV1().ManifestWorks(clusterName).Update(context.TODO(), work, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Streams.Out, "update work %s in cluster %s\n", o.workName, clusterName)
+			continue
+		}
+		work := &workapiv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      o.workName,
+				Namespace: clusterName,
+			},
+			Spec: workapiv1.ManifestWorkSpec{
+				Workload: workapiv1.ManifestsTemplate{Manifests: manifests},
+			},
+		}
+		if _, err := workClient.WorkV1().ManifestWorks(clusterName).Create(context.TODO(), work, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "create work %s in cluster %s\n", o.workName, clusterName)
+	}
+	return nil
+}
+
+// waitUntilAvailable waits for the work of the current generation to be available on the clusters
+// and to report the expected feedback values.
+func (o *Options) waitUntilAvailable(workClient workclientset.Interface, clusters []string) error {
+	pending := sets.NewString(clusters...)
+	err := wait.PollImmediate(2*time.Second, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, func() (bool, error) {
+		for _, clusterName := range pending.List() {
+			work, err := workClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), o.workName, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if isAvailable(work, o.feedback) {
+				fmt.Fprintf(o.Streams.Out, "work %s is available in cluster %s\n", o.workName, clusterName)
+				pending.Delete(clusterName)
+			}
+		}
+		return pending.Len() == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return utilerrors.NewAggregate([]error{err, fmt.Errorf("work %s is not available in clusters %v", o.workName, pending.List())})
+	}
+	return err
+}
+
+// isAvailable returns true if the work of the current generation is available and reports the feedback values
+func isAvailable(work *workapiv1.ManifestWork, feedback map[string]string) bool {
+	cond := meta.FindStatusCondition(work.Status.Conditions, workapiv1.WorkAvailable)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != work.Generation {
+		return false
+	}
+	for name, expected := range feedback {
+		found := false
+		for _, manifest := range work.Status.ResourceStatus.Manifests {
+			for _, value := range manifest.StatusFeedbacks.Values {
+				if value.Name != name {
+					continue
+				}
+				if fieldValue(value.Value) != expected {
+					return false
+				}
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldValue(value workapiv1.FieldValue) string {
+	switch {
+	case value.Integer != nil:
+		return fmt.Sprintf("%d", *value.Integer)
+	case value.String != nil:
+		return *value.String
+	case value.Boolean != nil:
+		return fmt.Sprintf("%t", *value.Boolean)
+	}
+	return ""
+}
+
+// revert restores the previous works of the clusters and deletes the works created by the rollout
+func (o *Options) revert(workClient workclientset.Interface, clusters []string, previous map[string]*workapiv1.ManifestWork) error {
+	var errs []error
+	for _, clusterName := range clusters {
+		prev, ok := previous[clusterName]
+		if !ok {
+			err := workClient.WorkV1().ManifestWorks(clusterName).Delete(context.TODO(), o.workName, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Fprintf(o.Streams.Out, "delete work %s in cluster %s\n", o.workName, clusterName)
+			continue
+		}
+		work, err := workClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), o.workName, metav1.GetOptions{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		work.Spec = prev.Spec
+		if _, err := workClient.WorkV1().ManifestWorks(clusterName).Update(context.TODO(), work, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(o.Streams.Out, "restore work %s in cluster %s\n", o.workName, clusterName)
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollout"
+)
+
+func TestPlan(t *testing.T) {
+	clusters := []clusterv1.ManagedCluster{}
+	for _, name := range []string{"c1", "c2", "c3", "c4"} {
+		clusters = append(clusters, clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	maxConcurrency := intstr.FromInt(2)
+	cases := []struct {
+		name      string
+		canaries  []string
+		strategy  *rollout.Strategy
+		expected  [][]string
+		expectErr bool
+	}{
+		{name: "all", canaries: []string{"c3"}, strategy: &rollout.Strategy{Type: rollout.TypeAll},
+			expected: [][]string{{"c3"}, {"c1", "c2", "c4"}}},
+		{name: "progressive", canaries: []string{"c1"}, strategy: &rollout.Strategy{Type: rollout.TypeProgressive, MaxConcurrency: maxConcurrency},
+			expected: [][]string{{"c1"}, {"c2", "c3"}, {"c4"}}},
+		{name: "only canaries", canaries: []string{"c1", "c2", "c3", "c4"}, strategy: &rollout.Strategy{Type: rollout.TypeAll},
+			expected: [][]string{{"c1", "c2", "c3", "c4"}}},
+		{name: "unknown canary", canaries: []string{"c5"}, strategy: &rollout.Strategy{Type: rollout.TypeAll}, expectErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			batches, err := plan(c.canaries, clusters, c.strategy)
+			if c.expectErr {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(batches, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, batches)
+			}
+		})
+	}
+}
+
+func TestIsAvailable(t *testing.T) {
+	ready := "true"
+	work := func(status metav1.ConditionStatus, observedGeneration int64, values ...workapiv1.FeedbackValue) *workapiv1.ManifestWork {
+		w := &workapiv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+		w.Status.Conditions = []metav1.Condition{{Type: workapiv1.WorkAvailable, Status: status, ObservedGeneration: observedGeneration}}
+		w.Status.ResourceStatus.Manifests = []workapiv1.ManifestCondition{{
+			StatusFeedbacks: workapiv1.StatusFeedbackResult{Values: values},
+		}}
+		return w
+	}
+	readyValue := workapiv1.FeedbackValue{Name: "ready", Value: workapiv1.FieldValue{Type: workapiv1.String, String: &ready}}
+	cases := []struct {
+		name     string
+		work     *workapiv1.ManifestWork
+		feedback map[string]string
+		expected bool
+	}{
+		{name: "available", work: work(metav1.ConditionTrue, 2), expected: true},
+		{name: "not available", work: work(metav1.ConditionFalse, 2), expected: false},
+		{name: "previous generation", work: work(metav1.ConditionTrue, 1), expected: false},
+		{name: "feedback", work: work(metav1.ConditionTrue, 2, readyValue), feedback: map[string]string{"ready": "true"}, expected: true},
+		{name: "unexpected feedback", work: work(metav1.ConditionTrue, 2, readyValue), feedback: map[string]string{"ready": "false"}, expected: false},
+		{name: "missing feedback", work: work(metav1.ConditionTrue, 2), feedback: map[string]string{"ready": "true"}, expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if available := isAvailable(c.work, c.feedback); available != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, available)
+			}
+		})
+	}
+}
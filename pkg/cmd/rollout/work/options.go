@@ -0,0 +1,48 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+
+	Streams genericclioptions.IOStreams
+
+	workName string
+	//The manifest files of the work
+	FileNameFlags genericclioptions.FileNameFlags
+	//The clusters the work is rolled out to, or the placement selecting them
+	clusters  []string
+	placement string
+	//The clusters the work is rolled out to first
+	canaryClusters []string
+	//Waits for the work to be available and to report the feedback values on each batch
+	waitAvailable bool
+	waitFeedback  []string
+	//Rolls out to the other clusters after the canary clusters
+	thenAll bool
+	//Reverts the clusters rolled out on failure
+	rollback bool
+
+	//The expected feedback values by name
+	feedback map[string]string
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+		FileNameFlags: genericclioptions.FileNameFlags{
+			Filenames: &[]string{},
+			Recursive: boolPtr(true),
+		},
+	}
+}
+
+func boolPtr(val bool) *bool {
+	return &val
+}
@@ -0,0 +1,148 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// hubCache keeps the managed clusters, manifestworks and addons of the hub in sync with a watch each,
+// the requests are served from it so the load on the hub does not grow with the number of clients
+type hubCache struct {
+	clusters cache.SharedIndexInformer
+	works    cache.SharedIndexInformer
+	addons   cache.SharedIndexInformer
+}
+
+func newHubCache(clusterClient clusterclientset.Interface, workClient workclientset.Interface, addonClient addonclientset.Interface) *hubCache {
+	return &hubCache{
+		clusters: newInformer(&clusterv1.ManagedCluster{},
+			func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				return clusterClient.ClusterV1().ManagedClusters().List(ctx, options)
+			},
+			clusterClient.ClusterV1().ManagedClusters().Watch),
+		works: newInformer(&workapiv1.ManifestWork{},
+			func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				return workClient.WorkV1().ManifestWorks(metav1.NamespaceAll).List(ctx, options)
+			},
+			workClient.WorkV1().ManifestWorks(metav1.NamespaceAll).Watch),
+		addons: newInformer(&addonv1alpha1.ManagedClusterAddOn{},
+			func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				return addonClient.AddonV1alpha1().ManagedClusterAddOns(metav1.NamespaceAll).List(ctx, options)
+			},
+			addonClient.AddonV1alpha1().ManagedClusterAddOns(metav1.NamespaceAll).Watch),
+	}
+}
+
+// newInformer returns an informer without resync, the objects are indexed by namespace
+func newInformer(object runtime.Object,
+	list func(context.Context, metav1.ListOptions) (runtime.Object, error),
+	watchFunc func(context.Context, metav1.ListOptions) (watch.Interface, error)) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return list(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return watchFunc(context.TODO(), options)
+		},
+	}, object, 0, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+// start runs the informers until the context is done, and waits for their first list
+func (c *hubCache) start(ctx context.Context) error {
+	informers := []cache.SharedIndexInformer{c.clusters, c.works, c.addons}
+	synced := []cache.InformerSynced{}
+	for _, informer := range informers {
+		go informer.Run(ctx.Done())
+		synced = append(synced, informer.HasSynced)
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return fmt.Errorf("failed to sync the managed clusters, manifestworks and addons of the hub")
+	}
+	return nil
+}
+
+func clustersOf(store cache.Store) []Cluster {
+	result := []Cluster{}
+	for _, obj := range store.List() {
+		cluster, ok := obj.(*clusterv1.ManagedCluster)
+		if !ok {
+			continue
+		}
+		result = append(result, Cluster{
+			Name:      cluster.Name,
+			Accepted:  cluster.Spec.HubAcceptsClient,
+			Joined:    meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionJoined),
+			Available: conditionStatus(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func worksOf(clusters cache.Store, works cache.Indexer, cluster string) ([]Work, error) {
+	objs, err := objectsOf(clusters, works, cluster)
+	if err != nil {
+		return nil, err
+	}
+	result := []Work{}
+	for _, obj := range objs {
+		work, ok := obj.(*workapiv1.ManifestWork)
+		if !ok {
+			continue
+		}
+		result = append(result, Work{
+			Name:      work.Name,
+			Manifests: len(work.Spec.Workload.Manifests),
+			Applied:   conditionStatus(work.Status.Conditions, workapiv1.WorkApplied),
+			Available: conditionStatus(work.Status.Conditions, workapiv1.WorkAvailable),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func addonsOf(clusters cache.Store, addons cache.Indexer, cluster string) ([]Addon, error) {
+	objs, err := objectsOf(clusters, addons, cluster)
+	if err != nil {
+		return nil, err
+	}
+	result := []Addon{}
+	for _, obj := range objs {
+		addon, ok := obj.(*addonv1alpha1.ManagedClusterAddOn)
+		if !ok {
+			continue
+		}
+		result = append(result, Addon{
+			Name:      addon.Name,
+			Available: conditionStatus(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable),
+			Degraded:  conditionStatus(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// objectsOf returns the objects in the namespace of the cluster, the cluster is not found if it is not in the cache
+func objectsOf(clusters cache.Store, indexer cache.Indexer, cluster string) ([]interface{}, error) {
+	if _, exists, err := clusters.GetByKey(cluster); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errors.NewNotFound(clusterv1.Resource("managedclusters"), cluster)
+	}
+	return indexer.ByIndex(cache.NamespaceIndex, cluster)
+}
@@ -0,0 +1,94 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newIndexer(t *testing.T, objs ...interface{}) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objs {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return indexer
+}
+
+func TestHubCache(t *testing.T) {
+	clusters := newIndexer(t,
+		&clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster2"},
+			Spec:       clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+			Status: clusterv1.ManagedClusterStatus{Conditions: []metav1.Condition{
+				{Type: clusterv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue},
+				{Type: clusterv1.ManagedClusterConditionAvailable, Status: metav1.ConditionFalse},
+			}},
+		},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+	)
+	works := newIndexer(t,
+		&workapiv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Name: "work2", Namespace: "cluster1"},
+			Spec:       workapiv1.ManifestWorkSpec{Workload: workapiv1.ManifestsTemplate{Manifests: make([]workapiv1.Manifest, 2)}},
+		},
+		&workapiv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Name: "work1", Namespace: "cluster1"},
+			Status: workapiv1.ManifestWorkStatus{Conditions: []metav1.Condition{
+				{Type: workapiv1.WorkApplied, Status: metav1.ConditionTrue},
+			}},
+		},
+		&workapiv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "work3", Namespace: "cluster2"}},
+	)
+	addons := newIndexer(t,
+		&addonv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: metav1.ObjectMeta{Name: "application-manager", Namespace: "cluster2"},
+			Status: addonv1alpha1.ManagedClusterAddOnStatus{Conditions: []metav1.Condition{
+				{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue},
+			}},
+		},
+	)
+
+	expectedClusters := []Cluster{
+		{Name: "cluster1", Available: "Unknown"},
+		{Name: "cluster2", Accepted: true, Joined: true, Available: "False"},
+	}
+	if result := clustersOf(clusters); !reflect.DeepEqual(result, expectedClusters) {
+		t.Errorf("expected %v, got %v", expectedClusters, result)
+	}
+
+	expectedWorks := []Work{
+		{Name: "work1", Applied: "True", Available: "Unknown"},
+		{Name: "work2", Manifests: 2, Applied: "Unknown", Available: "Unknown"},
+	}
+	result, err := worksOf(clusters, works, "cluster1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, expectedWorks) {
+		t.Errorf("expected %v, got %v", expectedWorks, result)
+	}
+	if _, err := worksOf(clusters, works, "cluster3"); !errors.IsNotFound(err) {
+		t.Errorf("expected the cluster not to be found, got %v", err)
+	}
+
+	expectedAddons := []Addon{{Name: "application-manager", Available: "True", Degraded: "Unknown"}}
+	addonResult, err := addonsOf(clusters, addons, "cluster2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(addonResult, expectedAddons) {
+		t.Errorf("expected %v, got %v", expectedAddons, addonResult)
+	}
+	if addonResult, _ := addonsOf(clusters, addons, "cluster1"); len(addonResult) != 0 {
+		t.Errorf("expected no addon, got %v", addonResult)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import (
+	"fmt"
+
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	clusteradmhelpers "open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var example = `
+# Serve the API of the hub on localhost, the token of the clients is printed
+%[1]s serve
+# Serve it with TLS and the token of a file
+%[1]s serve --listen 0.0.0.0:9443 --token-file token --tls-cert-file tls.crt --tls-key-file tls.key
+# Call it
+curl -H "Authorization: Bearer $TOKEN" http://localhost:9443/v1/clusters
+curl -H "Authorization: Bearer $TOKEN" -X POST http://localhost:9443/v1/clusters/cluster1/accept
+`
+
+// NewCmd...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "serve the operations of clusteradm on the hub over a REST API",
+		Long: "serve a REST API authenticated by a bearer token, for the provisioning portals calling clusteradm without running the CLI:\n\n" +
+			"  GET  /v1/join-command                the hub api server, token and CA hashes the clusters join with\n" +
+			"  GET  /v1/clusters                    the managed clusters\n" +
+			"  POST /v1/clusters/<cluster>/accept   accept a managed cluster, optional query parameters wait=true and timeout=<duration>\n" +
+			"  GET  /v1/clusters/<cluster>/works    the manifestworks of a managed cluster\n" +
+			"  GET  /v1/clusters/<cluster>/addons   the addons of a managed cluster\n" +
+			"  GET  /healthz                        not authenticated\n\n" +
+			"The managed clusters, manifestworks and addons are watched once and served from memory, " +
+			"the load on the hub does not grow with the number of clients.",
+		Example:      fmt.Sprintf(example, clusteradmhelpers.GetExampleHeader()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return exit.Validation(err)
+			}
+			if err := o.validate(); err != nil {
+				return exit.Validation(err)
+			}
+			return o.run(c.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.listen, "listen", "127.0.0.1:9443", "The address the API listens on")
+	cmd.Flags().StringVar(&o.tokenFile, "token-file", "", "The file of the bearer token of the clients, a random token is generated and printed if not set")
+	cmd.Flags().StringVar(&o.tlsCertFile, "tls-cert-file", "", "The certificate the API is served with over TLS, it is served over plain HTTP if not set, "+
+		"which is only allowed on a loopback --listen address")
+	cmd.Flags().StringVar(&o.tlsKeyFile, "tls-key-file", "", "The key of --tls-cert-file")
+
+	return cmd
+}
@@ -0,0 +1,217 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/accept"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("serve options:", "listen", o.listen, "token-file", o.tokenFile, "tls-cert-file", o.tlsCertFile)
+	if len(o.tokenFile) > 0 {
+		data, err := os.ReadFile(o.tokenFile)
+		if err != nil {
+			return err
+		}
+		o.token = strings.TrimSpace(string(data))
+	}
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	host, _, err := net.SplitHostPort(o.listen)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q: %v", o.listen, err)
+	}
+	if len(o.tokenFile) > 0 && len(o.token) < 16 {
+		return fmt.Errorf("the token of %s should be at least 16 characters", o.tokenFile)
+	}
+	if (len(o.tlsCertFile) == 0) != (len(o.tlsKeyFile) == 0) {
+		return fmt.Errorf("--tls-cert-file and --tls-key-file should be set together")
+	}
+	// the bearer token of the clients and the bootstrap token of the hub never go over the network in clear
+	if !isLoopback(host) && len(o.tlsCertFile) == 0 {
+		return fmt.Errorf("--tls-cert-file and --tls-key-file are required to listen on %s, only the loopback addresses are served over plain HTTP", o.listen)
+	}
+	return nil
+}
+
+// isLoopback returns true if the host only accepts local connections, an empty host listens on all the interfaces
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (o *Options) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	b, err := newHubBackend(o.ClusteradmFlags.KubectlFactory)
+	if err != nil {
+		return err
+	}
+	if err := b.cache.start(ctx); err != nil {
+		return err
+	}
+	if len(o.token) == 0 {
+		if o.token, err = randomToken(); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.ErrOut, "The bearer token of the clients is %s\n", o.token)
+	}
+
+	listener, err := net.Listen("tcp", o.listen)
+	if err != nil {
+		return err
+	}
+	s := &server{token: o.token, backend: b}
+	httpServer := &http.Server{
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	scheme := "http"
+	if len(o.tlsCertFile) > 0 {
+		scheme = "https"
+	}
+	fmt.Fprintf(o.Streams.Out, "Serving the clusteradm API on %s://%s, press Ctrl+C to stop\n", scheme, listener.Addr())
+	if len(o.tlsCertFile) > 0 {
+		err = httpServer.ServeTLS(listener, o.tlsCertFile, o.tlsKeyFile)
+	} else {
+		err = httpServer.Serve(listener)
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hubBackend runs the operations with the clients of the hub
+type hubBackend struct {
+	factory    cmdutil.Factory
+	host       string
+	kubeClient kubernetes.Interface
+	cache      *hubCache
+}
+
+func newHubBackend(f cmdutil.Factory) (*hubBackend, error) {
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	b := &hubBackend{factory: f, host: restConfig.Host}
+	if b.kubeClient, err = f.KubernetesClientSet(); err != nil {
+		return nil, err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	addonClient, err := addonclientset.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	b.cache = newHubCache(clusterClient, workClient, addonClient)
+	return b, nil
+}
+
+// joinCommand returns the token of the bootstrap service account, or the bootstrap token if there is no
+// service account. Unlike get token, no token is created if none exists.
+func (b *hubBackend) joinCommand(ctx context.Context) (*JoinCommand, error) {
+	namespace, err := helpers.GetBootstrapSANamespace(ctx, b.kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	token, err := helpers.GetBootstrapTokenFromSA(ctx, b.kubeClient, namespace)
+	if err != nil {
+		if token, err = helpers.GetBootstrapToken(ctx, b.kubeClient); err != nil {
+			return nil, err
+		}
+	}
+	caHashes, err := helpers.GetCAHashes(b.kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	command := fmt.Sprintf("%s join --hub-token %s --hub-apiserver %s", helpers.GetExampleHeader(), token, b.host)
+	if len(caHashes) > 0 {
+		command += " --discovery-token-ca-cert-hash " + strings.Join(caHashes, ",")
+	}
+	return &JoinCommand{
+		HubAPIServer: b.host,
+		HubToken:     token,
+		CAHashes:     caHashes,
+		Command:      command + " --cluster-name <cluster_name>",
+	}, nil
+}
+
+func (b *hubBackend) listClusters(ctx context.Context) ([]Cluster, error) {
+	return clustersOf(b.cache.clusters.GetStore()), nil
+}
+
+func (b *hubBackend) accept(ctx context.Context, cluster string, wait bool, timeout time.Duration) (*accept.Result, error) {
+	return accept.Run(ctx, b.factory, accept.Options{Clusters: []string{cluster}, Wait: wait, Timeout: timeout})
+}
+
+func (b *hubBackend) listWorks(ctx context.Context, cluster string) ([]Work, error) {
+	return worksOf(b.cache.clusters.GetStore(), b.cache.works.GetIndexer(), cluster)
+}
+
+func (b *hubBackend) listAddons(ctx context.Context, cluster string) ([]Addon, error) {
+	return addonsOf(b.cache.clusters.GetStore(), b.cache.addons.GetIndexer(), cluster)
+}
+
+// conditionStatus returns the status of the condition, Unknown if it is missing
+func conditionStatus(conditions []metav1.Condition, conditionType string) string {
+	if condition := meta.FindStatusCondition(conditions, conditionType); condition != nil {
+		return string(condition.Status)
+	}
+	return string(metav1.ConditionUnknown)
+}
@@ -0,0 +1,21 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import "testing"
+
+func TestIsLoopback(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1": true,
+		"::1":       true,
+		"localhost": true,
+		"":          false,
+		"0.0.0.0":   false,
+		"10.0.0.1":  false,
+		"hub.local": false,
+	}
+	for host, expected := range cases {
+		if isLoopback(host) != expected {
+			t.Errorf("expected isLoopback(%q) to be %v", host, expected)
+		}
+	}
+}
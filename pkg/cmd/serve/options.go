@@ -0,0 +1,31 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+type Options struct {
+	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
+	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The address the API listens on
+	listen string
+	//The file of the bearer token of the clients
+	tokenFile string
+	//The certificate and key of the TLS server
+	tlsCertFile string
+	tlsKeyFile  string
+
+	//The bearer token of the clients
+	token string
+
+	Streams genericclioptions.IOStreams
+}
+
+func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		ClusteradmFlags: clusteradmFlags,
+		Streams:         streams,
+	}
+}
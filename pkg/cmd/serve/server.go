@@ -0,0 +1,170 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/clusteradm/pkg/accept"
+)
+
+// JoinCommand is what the clusters join the hub with
+type JoinCommand struct {
+	HubAPIServer string   `json:"hubApiserver"`
+	HubToken     string   `json:"hubToken"`
+	CAHashes     []string `json:"caHashes,omitempty"`
+	// Command is the join command, the cluster name is to be set
+	Command string `json:"command"`
+}
+
+// Cluster is the registration status of a managed cluster
+type Cluster struct {
+	Name      string `json:"name"`
+	Accepted  bool   `json:"accepted"`
+	Joined    bool   `json:"joined"`
+	Available string `json:"available"`
+}
+
+// Work is the status of a manifestwork
+type Work struct {
+	Name      string `json:"name"`
+	Manifests int    `json:"manifests"`
+	Applied   string `json:"applied"`
+	Available string `json:"available"`
+}
+
+// Addon is the status of a managed cluster addon
+type Addon struct {
+	Name      string `json:"name"`
+	Available string `json:"available"`
+	Degraded  string `json:"degraded"`
+}
+
+// backend runs the operations of the API on the hub
+type backend interface {
+	joinCommand(ctx context.Context) (*JoinCommand, error)
+	listClusters(ctx context.Context) ([]Cluster, error)
+	accept(ctx context.Context, cluster string, wait bool, timeout time.Duration) (*accept.Result, error)
+	listWorks(ctx context.Context, cluster string) ([]Work, error)
+	listAddons(ctx context.Context, cluster string) ([]Addon, error)
+}
+
+type server struct {
+	token   string
+	backend backend
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/v1/", s.authenticate(http.HandlerFunc(s.route)))
+	return mux
+}
+
+// authenticate rejects the requests without the bearer token
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(authorization, "Bearer ")
+		if token == authorization || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) route(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/"), "/"), "/")
+	klog.V(2).InfoS("serve request", "method", r.Method, "path", r.URL.Path)
+	switch {
+	case len(parts) == 1 && parts[0] == "join-command" && r.Method == http.MethodGet:
+		s.serveResult(w, func() (interface{}, error) { return s.backend.joinCommand(r.Context()) })
+	case len(parts) == 1 && parts[0] == "clusters" && r.Method == http.MethodGet:
+		s.serveResult(w, func() (interface{}, error) { return s.backend.listClusters(r.Context()) })
+	case len(parts) == 3 && parts[0] == "clusters" && parts[2] == "accept" && r.Method == http.MethodPost:
+		s.accept(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "clusters" && parts[2] == "works" && r.Method == http.MethodGet:
+		if !validCluster(w, parts[1]) {
+			return
+		}
+		s.serveResult(w, func() (interface{}, error) { return s.backend.listWorks(r.Context(), parts[1]) })
+	case len(parts) == 3 && parts[0] == "clusters" && parts[2] == "addons" && r.Method == http.MethodGet:
+		if !validCluster(w, parts[1]) {
+			return
+		}
+		s.serveResult(w, func() (interface{}, error) { return s.backend.listAddons(r.Context(), parts[1]) })
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s %s is not found", r.Method, r.URL.Path))
+	}
+}
+
+func (s *server) accept(w http.ResponseWriter, r *http.Request, cluster string) {
+	if !validCluster(w, cluster) {
+		return
+	}
+	query := r.URL.Query()
+	wait := false
+	if value := query.Get("wait"); len(value) > 0 {
+		var err error
+		if wait, err = strconv.ParseBool(value); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid wait %q: %v", value, err))
+			return
+		}
+	}
+	var timeout time.Duration
+	if value := query.Get("timeout"); len(value) > 0 {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil || timeout < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid timeout %q", value))
+			return
+		}
+	}
+	s.serveResult(w, func() (interface{}, error) { return s.backend.accept(r.Context(), cluster, wait, timeout) })
+}
+
+// serveResult writes the result of the operation, or its error
+func (s *server) serveResult(w http.ResponseWriter, operation func() (interface{}, error)) {
+	result, err := operation()
+	switch {
+	case errors.IsNotFound(err):
+		writeError(w, http.StatusNotFound, err)
+	case errors.IsForbidden(err):
+		writeError(w, http.StatusForbidden, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func validCluster(w http.ResponseWriter, cluster string) bool {
+	if errs := validation.IsDNS1123Label(cluster); len(errs) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid cluster name %q: %v", cluster, errs))
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		klog.V(2).InfoS("failed to write the response", "error", err)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright Contributors to the Open Cluster Management project
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"open-cluster-management.io/clusteradm/pkg/accept"
+)
+
+type fakeBackend struct {
+	accepted []string
+	wait     bool
+	timeout  time.Duration
+}
+
+func (b *fakeBackend) joinCommand(ctx context.Context) (*JoinCommand, error) {
+	return &JoinCommand{HubAPIServer: "https://hub:6443", HubToken: "abc.def"}, nil
+}
+
+func (b *fakeBackend) listClusters(ctx context.Context) ([]Cluster, error) {
+	return []Cluster{{Name: "cluster1", Accepted: true}}, nil
+}
+
+func (b *fakeBackend) accept(ctx context.Context, cluster string, wait bool, timeout time.Duration) (*accept.Result, error) {
+	b.accepted = append(b.accepted, cluster)
+	b.wait, b.timeout = wait, timeout
+	return &accept.Result{AcceptedClusters: []string{cluster}}, nil
+}
+
+func (b *fakeBackend) listWorks(ctx context.Context, cluster string) ([]Work, error) {
+	if cluster != "cluster1" {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "managedclusters"}, cluster)
+	}
+	return []Work{{Name: "work1"}}, nil
+}
+
+func (b *fakeBackend) listAddons(ctx context.Context, cluster string) ([]Addon, error) {
+	if cluster != "cluster1" {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "managedclusters"}, cluster)
+	}
+	return []Addon{{Name: "application-manager"}}, nil
+}
+
+func TestServer(t *testing.T) {
+	b := &fakeBackend{}
+	s := &server{token: "0123456789abcdef", backend: b}
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	do := func(method, path, token string) (int, map[string]interface{}, []interface{}) {
+		req, _ := http.NewRequest(method, ts.URL+path, nil)
+		if len(token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		object, _ := body.(map[string]interface{})
+		list, _ := body.([]interface{})
+		return resp.StatusCode, object, list
+	}
+
+	cases := []struct {
+		method         string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{method: http.MethodGet, path: "/healthz", expectedStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/v1/clusters", expectedStatus: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/v1/clusters", token: "wrong", expectedStatus: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/v1/clusters", token: s.token, expectedStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/v1/join-command", token: s.token, expectedStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/v1/clusters/cluster1/accept", token: s.token, expectedStatus: http.StatusNotFound},
+		{method: http.MethodPost, path: "/v1/clusters/Cluster_1/accept", token: s.token, expectedStatus: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/v1/clusters/cluster1/accept?timeout=soon", token: s.token, expectedStatus: http.StatusBadRequest},
+		{method: http.MethodGet, path: "/v1/clusters/cluster1/works", token: s.token, expectedStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/v1/clusters/cluster2/works", token: s.token, expectedStatus: http.StatusNotFound},
+		{method: http.MethodGet, path: "/v1/clusters/cluster1/addons", token: s.token, expectedStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/v1/clusters/cluster2/addons", token: s.token, expectedStatus: http.StatusNotFound},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s %s", c.method, c.path), func(t *testing.T) {
+			if status, _, _ := do(c.method, c.path, c.token); status != c.expectedStatus {
+				t.Errorf("expected status %d, got %d", c.expectedStatus, status)
+			}
+		})
+	}
+
+	// the token is only accepted with the bearer scheme
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/v1/clusters", nil)
+	req.Header.Set("Authorization", s.token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected the token without the bearer scheme to be rejected, got %d", resp.StatusCode)
+	}
+
+	status, result, _ := do(http.MethodPost, "/v1/clusters/cluster1/accept?wait=true&timeout=30s", s.token)
+	if status != http.StatusOK || fmt.Sprint(result["AcceptedClusters"]) != "[cluster1]" {
+		t.Errorf("unexpected accept response %d %v", status, result)
+	}
+	if !b.wait || b.timeout != 30*time.Second {
+		t.Errorf("expected the wait and timeout to be passed, got %v %s", b.wait, b.timeout)
+	}
+	if _, _, clusters := do(http.MethodGet, "/v1/clusters", s.token); len(clusters) != 1 {
+		t.Errorf("expected 1 cluster, got %v", clusters)
+	}
+}
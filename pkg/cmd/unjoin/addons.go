@@ -0,0 +1,267 @@
+// Copyright Contributors to the Open Cluster Management project
+package unjoin
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclient "open-cluster-management.io/api/client/addon/clientset/versioned"
+	klusterletclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	appliedworkclient "open-cluster-management.io/api/client/work/clientset/versioned"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/helpers/restconfig"
+)
+
+const (
+	hubSecretName       = "hub-kubeconfig-secret"
+	kubeconfigSecretKey = "kubeconfig"
+)
+
+// addonClients are the clients of the managed cluster removing the agents of the addons
+type addonClients struct {
+	kubeClient        kubernetes.Interface
+	dynamicClient     dynamic.Interface
+	klusterletClient  klusterletclient.Interface
+	appliedWorkClient appliedworkclient.Interface
+}
+
+// cleanAddons removes the agents of the addons of the cluster before the klusterlet is deleted, the
+// klusterlet orphans them otherwise. The addons are disabled on the hub if --hub-kubeconfig is set and
+// their agents are removed by the work agent, the agents which are not removed within the timeout, or
+// all of them if the addons cannot be disabled, are removed by clusteradm.
+func (o *Options) cleanAddons(clients addonClients) error {
+	ctx := context.Background()
+	klusterlet, err := clients.klusterletClient.OperatorV1().Klusterlets().Get(ctx, o.klusterletName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	namespace := join.AgentNamespace(o.klusterletName)
+	if len(klusterlet.Spec.Namespace) > 0 {
+		namespace = klusterlet.Spec.Namespace
+	}
+
+	hubConfig, agentHubConfig, err := o.hubConfig(clients.kubeClient, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get the hub kubeconfig, set it with --hub-kubeconfig: %v", err)
+	}
+	// the appliedmanifestworks of the other klusterlets of the cluster are applied from other hubs
+	hash := ""
+	if agentHubConfig != nil {
+		hash = hubHash(agentHubConfig.Host)
+	}
+	addonClient, err := addonclient.NewForConfig(hubConfig)
+	if err != nil {
+		return err
+	}
+	addons, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(o.clusterName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list the addons of cluster %s on the hub: %v", o.clusterName, err)
+	}
+	if len(addons.Items) == 0 {
+		return nil
+	}
+
+	if o.ClusteradmFlags.DryRun {
+		for _, addon := range addons.Items {
+			fmt.Fprintf(o.Streams.Out, "The agent of addon %s would be removed\n", addon.Name)
+		}
+		return nil
+	}
+
+	// the install namespaces are listed before the addons are disabled
+	namespaces := installNamespaces(addons.Items, namespace)
+	if len(o.hubKubeconfig) > 0 {
+		for _, addon := range addons.Items {
+			err := addonClient.AddonV1alpha1().ManagedClusterAddOns(o.clusterName).Delete(ctx, addon.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			fmt.Fprintf(o.Streams.Out, "Addon %s is disabled on the hub\n", addon.Name)
+		}
+		timeout := time.Duration(o.ClusteradmFlags.Timeout) * time.Second
+		err := wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+			works, err := addonWorks(ctx, clients.appliedWorkClient, hash, addons.Items)
+			return len(works) == 0, err
+		})
+		if err == nil {
+			fmt.Fprintf(o.Streams.Out, "The agents of the addons are removed\n")
+			return deleteNamespaces(ctx, clients.kubeClient, namespaces)
+		}
+		if err != wait.ErrWaitTimeout {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "The agents of the addons are not removed after %v, they are force removed\n", timeout)
+	} else {
+		fmt.Fprintf(o.Streams.Out, "The addons stay enabled on the hub without --hub-kubeconfig, their agents are force removed\n")
+	}
+
+	works, err := addonWorks(ctx, clients.appliedWorkClient, hash, addons.Items)
+	if err != nil {
+		return err
+	}
+	for _, work := range works {
+		if err := forceRemove(ctx, clients.dynamicClient, clients.appliedWorkClient, work); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "The resources of manifestwork %s are removed\n", work.Spec.ManifestWorkName)
+	}
+	return deleteNamespaces(ctx, clients.kubeClient, namespaces)
+}
+
+// hubConfig returns the config of --hub-kubeconfig, or of the hub kubeconfig of the registration agent, and
+// the config of the registration agent, nil if --hub-kubeconfig is set and the agent has no hub kubeconfig
+func (o *Options) hubConfig(kubeClient kubernetes.Interface, namespace string) (*rest.Config, *rest.Config, error) {
+	var agentConfig *rest.Config
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), hubSecretName, metav1.GetOptions{})
+	if err == nil {
+		agentConfig, err = hubConfigOf(secret.Data)
+	}
+	if len(o.hubKubeconfig) > 0 {
+		config, err := clientcmd.BuildConfigFromFlags("", o.hubKubeconfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return restconfig.Wrap(config), agentConfig, nil
+	}
+	return agentConfig, agentConfig, err
+}
+
+// hubHash returns the hash of the hub API server the work agent prefixes the appliedmanifestworks with
+func hubHash(host string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(host)))
+}
+
+// hubConfigOf returns the config of the hub kubeconfig secret of the registration agent, the kubeconfig
+// references the certificate and the key of the secret by their file names.
+func hubConfigOf(data map[string][]byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(data[kubeconfigSecretKey])
+	if err != nil {
+		return nil, err
+	}
+	for _, authInfo := range config.AuthInfos {
+		if cert, ok := data[baseName(authInfo.ClientCertificate)]; ok && len(authInfo.ClientCertificate) > 0 {
+			authInfo.ClientCertificate, authInfo.ClientCertificateData = "", cert
+		}
+		if key, ok := data[baseName(authInfo.ClientKey)]; ok && len(authInfo.ClientKey) > 0 {
+			authInfo.ClientKey, authInfo.ClientKeyData = "", key
+		}
+	}
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, nil).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return restconfig.Wrap(restConfig), nil
+}
+
+func baseName(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// isAddonWork returns true if the manifestwork deploys the agent of the addon, the manifestworks of an
+// addon are named addon-<name>-deploy, addon-<name>-deploy-<index> and addon-<name>-pre-delete
+func isAddonWork(workName, addon string) bool {
+	prefix := "addon-" + addon + "-"
+	if !strings.HasPrefix(workName, prefix) {
+		return false
+	}
+	suffix := strings.TrimPrefix(workName, prefix)
+	return suffix == "deploy" || strings.HasPrefix(suffix, "deploy-") || suffix == "pre-delete"
+}
+
+// addonWorks returns the appliedmanifestworks of the agents of the addons applied from the hub of the hash,
+// from any hub if the hash is empty
+func addonWorks(ctx context.Context, client appliedworkclient.Interface, hash string, addons []addonv1alpha1.ManagedClusterAddOn) ([]workapiv1.AppliedManifestWork, error) {
+	works, err := client.WorkV1().AppliedManifestWorks().List(ctx, metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	addonWorks := []workapiv1.AppliedManifestWork{}
+	for _, work := range works.Items {
+		if len(hash) > 0 && work.Spec.HubHash != hash {
+			continue
+		}
+		for _, addon := range addons {
+			if isAddonWork(work.Spec.ManifestWorkName, addon.Name) {
+				addonWorks = append(addonWorks, work)
+				break
+			}
+		}
+	}
+	return addonWorks, nil
+}
+
+// installNamespaces returns the namespaces the agents of the addons are installed in, except the namespace
+// of the klusterlet. The addons are installed in <klusterlet namespace>-addon by default.
+func installNamespaces(addons []addonv1alpha1.ManagedClusterAddOn, klusterletNamespace string) []string {
+	namespaces := []string{}
+	seen := map[string]bool{klusterletNamespace: true}
+	for _, addon := range addons {
+		namespace := addon.Spec.InstallNamespace
+		if len(namespace) == 0 {
+			namespace = klusterletNamespace + "-addon"
+		}
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// forceRemove deletes the resources applied by the appliedmanifestwork, then the appliedmanifestwork
+// without waiting for the work agent to finalize it
+func forceRemove(ctx context.Context, dynamicClient dynamic.Interface, client appliedworkclient.Interface, work workapiv1.AppliedManifestWork) error {
+	var errs []error
+	for _, resource := range work.Status.AppliedResources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Resource}
+		err := dynamicClient.Resource(gvr).Namespace(resource.Namespace).Delete(ctx, resource.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	_, err := client.WorkV1().AppliedManifestWorks().Patch(ctx, work.Name, types.MergePatchType,
+		[]byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	err = client.WorkV1().AppliedManifestWorks().Delete(ctx, work.Name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func deleteNamespaces(ctx context.Context, kubeClient kubernetes.Interface, namespaces []string) error {
+	var errs []error
+	for _, namespace := range namespaces {
+		err := kubeClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
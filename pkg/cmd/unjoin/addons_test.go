@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+package unjoin
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+)
+
+func TestIsAddonWork(t *testing.T) {
+	cases := []struct {
+		workName string
+		addon    string
+		expected bool
+	}{
+		{workName: "addon-application-manager-deploy", addon: "application-manager", expected: true},
+		{workName: "addon-application-manager-deploy-0", addon: "application-manager", expected: true},
+		{workName: "addon-application-manager-pre-delete", addon: "application-manager", expected: true},
+		{workName: "addon-application-manager-deploy", addon: "application", expected: false},
+		{workName: "application-manager", addon: "application-manager", expected: false},
+	}
+	for _, c := range cases {
+		if actual := isAddonWork(c.workName, c.addon); actual != c.expected {
+			t.Errorf("expected %v for %s of addon %s, got %v", c.expected, c.workName, c.addon, actual)
+		}
+	}
+}
+
+func TestInstallNamespaces(t *testing.T) {
+	addons := []addonv1alpha1.ManagedClusterAddOn{
+		{ObjectMeta: metav1.ObjectMeta{Name: "application-manager"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "config-policy-controller"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "observability"}, Spec: addonv1alpha1.ManagedClusterAddOnSpec{InstallNamespace: "observability"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "in-agent"}, Spec: addonv1alpha1.ManagedClusterAddOnSpec{InstallNamespace: join.AgentNamespace(join.DefaultKlusterletName)}},
+	}
+	expected := []string{"open-cluster-management-agent-addon", "observability"}
+	if actual := installNamespaces(addons, join.AgentNamespace(join.DefaultKlusterletName)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestHubConfigOf(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://hub:6443
+    insecure-skip-tls-verify: true
+users:
+- name: agent
+  user:
+    client-certificate: /spoke/hub-kubeconfig/tls.crt
+    client-key: /spoke/hub-kubeconfig/tls.key
+contexts:
+- name: hub
+  context:
+    cluster: hub
+    user: agent
+current-context: hub
+`
+	config, err := hubConfigOf(map[string][]byte{
+		kubeconfigSecretKey: []byte(kubeconfig),
+		"tls.crt":           []byte("cert"),
+		"tls.key":           []byte("key"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "https://hub:6443" {
+		t.Errorf("expected the host https://hub:6443, got %s", config.Host)
+	}
+	if string(config.CertData) != "cert" || string(config.KeyData) != "key" || len(config.CertFile) > 0 || len(config.KeyFile) > 0 {
+		t.Errorf("expected the certificate and the key of the secret, got %q %q %q %q", config.CertData, config.KeyData, config.CertFile, config.KeyFile)
+	}
+}
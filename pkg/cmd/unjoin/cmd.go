@@ -3,9 +3,13 @@ package unjoin
 
 import (
 	"fmt"
+	"time"
 
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/oplog"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -14,6 +18,16 @@ import (
 var example = `
 # UnJoin a cluster from a hub
 %[1]s unjoin --cluster-name <cluster_name>
+# UnJoin a cluster, its addons are disabled on the hub and their agents removed before the klusterlet
+%[1]s unjoin --cluster-name <cluster_name> --hub-kubeconfig <hub_kubeconfig>
+# UnJoin the cluster from the hub of the klusterlet hub2, the other klusterlets stay registered to their hubs
+%[1]s unjoin --cluster-name <cluster_name> --klusterlet-name hub2
+# UnJoin a cluster once the work agent evicted the resources of the ManifestWorks deleted on the hub
+%[1]s unjoin --cluster-name <cluster_name> --wait-for-eviction --timeout 600
+# UnJoin a cluster from the hub, its klusterlet is removed by a ManifestWork and the kubeconfig of the cluster is not needed
+%[1]s unjoin --from-hub --cluster <cluster_name>
+# Clean up the resources of a failed or timed out join
+%[1]s unjoin --partial
 `
 
 // NewCmd ...
@@ -36,15 +50,33 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 			if err := o.validate(); err != nil {
 				return err
 			}
-			if err := o.run(); err != nil {
-				return err
+			start := time.Now()
+			err := o.run()
+			// the context is the hub with --from-hub
+			if !o.ClusteradmFlags.DryRun && !o.fromHub {
+				oplog.Record(o.ClusteradmFlags.KubectlFactory, config.ManagedClusterNamespace,
+					oplog.NewOperation(c, "", start, err), o.Streams.ErrOut)
 			}
-
-			return nil
+			return err
 		},
 	}
 	cmd.Flags().StringVar(&o.clusterName, "cluster-name", "", "The name of the joining cluster")
 	cmd.Flags().BoolVar(&o.purgeOperator, "purge-operator", true, "Purge the operator")
 	cmd.Flags().StringVar(&o.outputFile, "output-file", "", "The generated resources will be copied in the specified file")
+	cmd.Flags().BoolVar(&o.partial, "partial", false,
+		"Delete whatever a failed or timed out join applied in reverse order, the klusterlet does not need to be registered")
+	cmd.Flags().StringVar(&o.klusterletName, "klusterlet-name", join.DefaultKlusterletName,
+		"The name of the klusterlet to delete, the operator is only purged once the last klusterlet of the cluster is deleted")
+	cmd.Flags().StringVar(&o.hubKubeconfig, "hub-kubeconfig", "",
+		"The kubeconfig of the hub the addons of the cluster are disabled with before their agents are removed, "+
+			"the agents are force removed with the hub kubeconfig of the klusterlet if not set")
+	cmd.Flags().BoolVar(&o.waitForEviction, "wait-for-eviction", false,
+		"Wait up to --timeout for the work agent to evict the AppliedManifestWorks, once their ManifestWorks are deleted on the hub "+
+			"or their eviction grace period expired, instead of failing if some exist")
+	cmd.Flags().BoolVar(&o.fromHub, "from-hub", false,
+		"Unjoin the cluster from the hub of the context: a ManifestWork removes the klusterlet of the cluster, then the ManagedCluster "+
+			"is deleted. The klusterlet is not removed if the cluster is not available, the klusterlet operator is left on the cluster")
+	cmd.Flags().StringVar(&o.clusterName, "cluster", "", "The name of the cluster to unjoin, same as --cluster-name")
+	o.maintenance.AddFlags(cmd.Flags())
 	return cmd
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -18,11 +19,14 @@ import (
 	"k8s.io/klog/v2"
 	klusterletclient "open-cluster-management.io/api/client/operator/clientset/versioned"
 	appliedworkclient "open-cluster-management.io/api/client/work/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/rollback"
 )
 
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
-	klog.V(1).InfoS("unjoin  options:", "dry-run", o.ClusteradmFlags.DryRun, "cluster", o.clusterName, o.outputFile)
+	klog.V(1).InfoS("unjoin  options:", "dry-run", o.ClusteradmFlags.DryRun, "cluster", o.clusterName, o.outputFile,
+		"klusterlet", o.klusterletName, "from-hub", o.fromHub)
 
 	o.values = Values{
 		ClusterName: o.clusterName,
@@ -32,6 +36,18 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 }
 
 func (o *Options) validate() error {
+	if err := o.maintenance.Validate(time.Now(), o.Streams.ErrOut); err != nil {
+		return err
+	}
+	if err := join.ValidateKlusterletName(o.klusterletName); err != nil {
+		return err
+	}
+	if o.fromHub {
+		return o.validateFromHub()
+	}
+	if o.partial {
+		return nil
+	}
 	if o.values.ClusterName == "" {
 		return fmt.Errorf("name is missing")
 	}
@@ -39,6 +55,12 @@ func (o *Options) validate() error {
 }
 
 func (o *Options) run() error {
+	if o.fromHub {
+		return o.runFromHub()
+	}
+	if o.partial {
+		return o.runPartial()
+	}
 
 	// Delete the applied resource in the Managed cluster
 	fmt.Fprintf(o.Streams.Out, "Remove applied resources in the managed cluster %s ... \n", o.clusterName)
@@ -49,7 +71,7 @@ func (o *Options) run() error {
 	if err != nil {
 		return err
 	}
-	kubeClient, apiExtensionsClient, _, err := helpers.GetClients(f)
+	kubeClient, apiExtensionsClient, dynamicClient, err := helpers.GetClients(f)
 	if err != nil {
 		return err
 	}
@@ -57,16 +79,36 @@ func (o *Options) run() error {
 	if err != nil {
 		return err
 	}
+	//Create klusterlet client
+	klusterletClient, err := klusterletclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
 
+	// the klusterlet orphans the agents of the addons, they are removed first
+	if err := o.cleanAddons(addonClients{
+		kubeClient:        kubeClient,
+		dynamicClient:     dynamicClient,
+		klusterletClient:  klusterletClient,
+		appliedWorkClient: appliedWorkClient,
+	}); err != nil {
+		return err
+	}
+	if o.ClusteradmFlags.DryRun {
+		return nil
+	}
+
+	if o.waitForEviction {
+		fmt.Fprintf(o.Streams.Out, "Waiting for the eviction of the appliedManifestWorks ...\n")
+		if err := WaitAppliedManifestWorksEvicted(context.Background(), appliedWorkClient,
+			time.Duration(o.ClusteradmFlags.Timeout)*time.Second); err != nil {
+			return err
+		}
+	}
 	if IsAppliedManifestWorkExist(appliedWorkClient) {
 		return fmt.Errorf("appliedManifestWork exist on the managed cluster, uninstalling the klusterlet will cause that the manifestworks on hub cannot be cleaned")
 	} else {
-		//Create klusterlet client
-		klusterletClient, err := klusterletclient.NewForConfig(config)
-		if err != nil {
-			return err
-		}
-		err = klusterletClient.OperatorV1().Klusterlets().Delete(context.Background(), "klusterlet", metav1.DeleteOptions{})
+		err = klusterletClient.OperatorV1().Klusterlets().Delete(context.Background(), o.klusterletName, metav1.DeleteOptions{})
 		if errors.IsNotFound(err) {
 			fmt.Fprintf(o.Streams.Out, "klusterlet is cleaned up already\n")
 			return nil
@@ -77,14 +119,20 @@ func (o *Options) run() error {
 		b := retry.DefaultBackoff
 		b.Duration = 1 * time.Second
 
-		err = WaitResourceToBeDelete(context.Background(), klusterletClient, "klusterlet", b)
+		err = WaitResourceToBeDelete(context.Background(), klusterletClient, o.klusterletName, b)
 		if err != nil {
 			return err
 		}
 	}
 
-	//Delete the other applied resources
-	if o.purgeOperator {
+	//Delete the other applied resources, the operator is shared by the klusterlets of the cluster
+	others, err := otherKlusterlets(klusterletClient, o.klusterletName)
+	if err != nil {
+		return err
+	}
+	if o.purgeOperator && len(others) > 0 {
+		fmt.Fprintf(o.Streams.Out, "The operator is not purged, it runs the klusterlets %s\n", strings.Join(others, ", "))
+	} else if o.purgeOperator {
 		if err := puregeOperator(kubeClient, apiExtensionsClient); err != nil {
 			return err
 		}
@@ -95,6 +143,54 @@ func (o *Options) run() error {
 
 }
 
+// runPartial deletes the resources join applies in reverse order, ignoring the missing ones
+func (o *Options) runPartial() error {
+	f := o.ClusteradmFlags.KubectlFactory
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	klusterletClient, err := klusterletclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	others, err := otherKlusterlets(klusterletClient, o.klusterletName)
+	if err != nil {
+		return err
+	}
+	resources, err := join.AppliedResources(o.klusterletName)
+	if len(others) > 0 {
+		// the operator and the CRDs are left to the other klusterlets
+		resources, err = join.KlusterletResources(o.klusterletName)
+	}
+	if err != nil {
+		return err
+	}
+	tracker := &rollback.Tracker{}
+	if err := tracker.Track(resources...); err != nil {
+		return err
+	}
+
+	if o.ClusteradmFlags.DryRun {
+		objects := tracker.Objects()
+		for i := len(objects) - 1; i >= 0; i-- {
+			fmt.Fprintf(o.Streams.Out, "%s %s would be deleted\n", objects[i].GetKind(), objects[i].GetName())
+		}
+		return nil
+	}
+
+	_, _, dynamicClient, err := helpers.GetClients(f)
+	if err != nil {
+		return err
+	}
+	mapper, err := f.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "Remove the resources of the partial join ... \n")
+	return tracker.Rollback(context.Background(), dynamicClient, mapper, time.Duration(o.ClusteradmFlags.Timeout)*time.Second, o.Streams.Out)
+}
+
 func puregeOperator(client kubernetes.Interface, extensionClient apiextensionsclient.Interface) error {
 	var errs []error
 
@@ -133,6 +229,24 @@ func puregeOperator(client kubernetes.Interface, extensionClient apiextensionscl
 	return utilerrors.NewAggregate(errs)
 }
 
+// otherKlusterlets returns the names of the klusterlets of the cluster other than the klusterlet
+func otherKlusterlets(client klusterletclient.Interface, klusterletName string) ([]string, error) {
+	klusterlets, err := client.OperatorV1().Klusterlets().List(context.Background(), metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	others := []string{}
+	for _, klusterlet := range klusterlets.Items {
+		if klusterlet.Name != klusterletName {
+			others = append(others, klusterlet.Name)
+		}
+	}
+	return others, nil
+}
+
 func WaitResourceToBeDelete(context context.Context, client klusterletclient.Interface, name string, b wait.Backoff) error {
 	errGet := retry.OnError(b, func(err error) bool {
 		return true
@@ -150,6 +264,31 @@ func WaitResourceToBeDelete(context context.Context, client klusterletclient.Int
 
 }
 
+// WaitAppliedManifestWorksEvicted waits until the work agent evicted all the AppliedManifestWorks, the
+// remaining ones are listed if the timeout expires
+func WaitAppliedManifestWorksEvicted(ctx context.Context, client appliedworkclient.Interface, timeout time.Duration) error {
+	var remaining []string
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		works, err := client.WorkV1().AppliedManifestWorks().List(ctx, metav1.ListOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		remaining = []string{}
+		for _, work := range works.Items {
+			remaining = append(remaining, work.Name)
+		}
+		return len(remaining) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("the appliedManifestWorks %s are not evicted after %s, delete their manifestworks on the hub",
+			strings.Join(remaining, ", "), timeout)
+	}
+	return err
+}
+
 func IsAppliedManifestWorkExist(client appliedworkclient.Interface) bool {
 	obj, err := client.WorkV1().AppliedManifestWorks().List(context.Background(), metav1.ListOptions{})
 	if errors.IsNotFound(err) {
@@ -0,0 +1,75 @@
+// Copyright Contributors to the Open Cluster Management project
+package unjoin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/selfdestruct"
+)
+
+func (o *Options) validateFromHub() error {
+	if len(o.clusterName) == 0 {
+		return fmt.Errorf("--cluster is required with --from-hub")
+	}
+	if o.partial || len(o.hubKubeconfig) > 0 || o.waitForEviction {
+		return fmt.Errorf("--partial, --hub-kubeconfig and --wait-for-eviction are not supported with --from-hub")
+	}
+	return o.ClusteradmFlags.ValidateHub()
+}
+
+// runFromHub removes the klusterlet of the cluster with a ManifestWork deleting itself, then the ManagedCluster,
+// the credentials of the managed cluster are not needed
+func (o *Options) runFromHub() error {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(ctx, o.clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	available := meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if o.ClusteradmFlags.DryRun {
+		if available {
+			fmt.Fprintf(o.Streams.Out, "work %s would remove the klusterlet %s of cluster %s\n", selfdestruct.WorkName(o.klusterletName),
+				o.klusterletName, o.clusterName)
+		}
+		fmt.Fprintf(o.Streams.Out, "managedCluster %s would be deleted\n", o.clusterName)
+		return nil
+	}
+
+	if available {
+		fmt.Fprintf(o.Streams.Out, "Removing the klusterlet %s of cluster %s ...\n", o.klusterletName, o.clusterName)
+		timeout := time.Duration(o.ClusteradmFlags.Timeout) * time.Second
+		if err := selfdestruct.RemoveKlusterlet(ctx, workClient, o.clusterName, o.klusterletName, timeout, o.Streams.ErrOut); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(o.Streams.ErrOut, "cluster %s is not available, its klusterlet is not removed, run unjoin on the cluster if it comes back\n",
+			o.clusterName)
+	}
+
+	if err := clusterClient.ClusterV1().ManagedClusters().Delete(ctx, o.clusterName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "managedCluster %s is deleted, the klusterlet operator is left on the cluster\n", o.clusterName)
+	return nil
+}
@@ -0,0 +1,15 @@
+// Copyright Contributors to the Open Cluster Management project
+package unjoin
+
+import (
+	"testing"
+)
+
+func TestValidateFromHub(t *testing.T) {
+	if err := (&Options{fromHub: true}).validateFromHub(); err == nil {
+		t.Errorf("expected the cluster to be required")
+	}
+	if err := (&Options{fromHub: true, clusterName: "cluster1", partial: true}).validateFromHub(); err == nil {
+		t.Errorf("expected --partial to be rejected")
+	}
+}
@@ -4,6 +4,7 @@ package unjoin
 import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/maintenance"
 )
 
 //Options: The structure holding all the command-line options
@@ -16,9 +17,21 @@ type Options struct {
 	purgeOperator bool
 	//The file to output the resources will be sent to the file.
 	outputFile string
-	values     Values
+	//Delete the resources of a partial join, whatever the state of the klusterlet
+	partial bool
+	//The kubeconfig of the hub the addons of the cluster are disabled with
+	hubKubeconfig string
+	//The name of the klusterlet deleted, the klusterlet of the hub on the clusters registered to several hubs
+	klusterletName string
+	//Waits for the work agent to evict the AppliedManifestWorks instead of failing if some exist
+	waitForEviction bool
+	//Removes the klusterlet of the cluster from the hub with a ManifestWork, then the ManagedCluster
+	fromHub bool
+	values  Values
 
 	Streams genericclioptions.IOStreams
+	//The maintenance window the command runs in
+	maintenance maintenance.Options
 }
 type Values struct {
 	//ClusterName: the name of the joined cluster on the hub
@@ -0,0 +1,58 @@
+// Copyright Contributors to the Open Cluster Management project
+package clustermanager
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+	"open-cluster-management.io/clusteradm/pkg/helpers/image"
+)
+
+// clusterManagerName is the name of the clustermanager of the templates
+const clusterManagerName = "cluster-manager"
+
+// upgradeAttached upgrades the operator and the images of the clustermanager attached by attach in place,
+// it returns false if the clustermanager is not attached
+func (o *Options) upgradeAttached(kubeClient kubernetes.Interface, operatorClient operatorclient.Interface) (bool, error) {
+	ctx := context.TODO()
+	clusterManager, err := operatorClient.OperatorV1().ClusterManagers().Get(ctx, clusterManagerName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	installation, err := attach.Parse(clusterManager.Annotations)
+	if err != nil || installation == nil {
+		return false, err
+	}
+	if installation.Installer == attach.InstallerOLM {
+		return true, fmt.Errorf("the operator of clustermanager %s is managed by the OLM ClusterServiceVersion %s, upgrade it with its subscription",
+			clusterManager.Name, installation.Release)
+	}
+
+	operatorImage := image.PullSpec(o.registry, image.OperatorImageName, o.values.BundleVersion.RegistrationImageVersion, image.Digests{})
+	fmt.Fprintf(o.Streams.Out, "clustermanager %s is attached, upgrading its operator %s/%s to %s\n",
+		clusterManager.Name, installation.OperatorNamespace, installation.OperatorName, operatorImage)
+	if o.ClusteradmFlags.DryRun {
+		return true, nil
+	}
+	if err := attach.UpgradeOperator(ctx, kubeClient, installation, attach.ModeHub, operatorImage); err != nil {
+		return true, err
+	}
+	clusterManager.Spec.RegistrationImagePullSpec = image.PullSpec(o.registry, image.RegistrationImageName, o.values.BundleVersion.RegistrationImageVersion, image.Digests{})
+	clusterManager.Spec.WorkImagePullSpec = image.PullSpec(o.registry, image.WorkImageName, o.values.BundleVersion.WorkImageVersion, image.Digests{})
+	clusterManager.Spec.PlacementImagePullSpec = image.PullSpec(o.registry, image.PlacementImageName, o.values.BundleVersion.PlacementImageVersion, image.Digests{})
+	if _, err := operatorClient.OperatorV1().ClusterManagers().Update(ctx, clusterManager, metav1.UpdateOptions{}); err != nil {
+		return true, err
+	}
+	if installation.Installer == attach.InstallerHelm {
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: the next upgrade of the helm release %s overwrites the images\n", installation.Release)
+	}
+	return true, nil
+}
@@ -49,5 +49,6 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		`the version of predefined compatible image versions. e.g. v0.6.0, defaulted to the latest release version. also, we can set "latest" to install latest develop version`)
 	cmd.Flags().BoolVar(&o.wait, "wait", false,
 		"If set, the command will initialize the OCM control plan in foreground.")
+	o.maintenance.AddFlags(cmd.Flags())
 	return cmd
 }
@@ -3,11 +3,13 @@ package clustermanager
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stolostron/applier/pkg/apply"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/klog/v2"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
 	init_scenario "open-cluster-management.io/clusteradm/pkg/cmd/init/scenario"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
 	version "open-cluster-management.io/clusteradm/pkg/helpers/version"
@@ -40,6 +42,9 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 }
 
 func (o *Options) validate() (err error) {
+	if err := o.maintenance.Validate(time.Now(), o.Streams.ErrOut); err != nil {
+		return err
+	}
 	err = o.ClusteradmFlags.ValidateHub()
 	if err != nil {
 		return err
@@ -79,6 +84,19 @@ func (o *Options) run() error {
 		return err
 	}
 
+	// the operator installed by another tool and attached is upgraded in place
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	if attached, err := o.upgradeAttached(kubeClient, operatorClient); attached || err != nil {
+		return err
+	}
+
 	applierBuilder := apply.NewApplierBuilder()
 	applier := applierBuilder.WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
 
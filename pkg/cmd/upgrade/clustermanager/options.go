@@ -4,6 +4,7 @@ package clustermanager
 import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/maintenance"
 )
 
 //Options: The structure holding all the command-line options
@@ -20,6 +21,8 @@ type Options struct {
 	wait bool
 
 	Streams genericclioptions.IOStreams
+	//The maintenance window the command runs in
+	maintenance maintenance.Options
 }
 
 type BundleVersion struct {
@@ -0,0 +1,47 @@
+// Copyright Contributors to the Open Cluster Management project
+package klusterlet
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/attach"
+	"open-cluster-management.io/clusteradm/pkg/helpers/image"
+)
+
+// upgradeAttached upgrades the operator and the images of the klusterlet attached by attach in place,
+// it returns false if the klusterlet is not attached
+func (o *Options) upgradeAttached(kubeClient kubernetes.Interface, operatorClient operatorclient.Interface, klusterlet *operatorv1.Klusterlet) (bool, error) {
+	ctx := context.TODO()
+	installation, err := attach.Parse(klusterlet.Annotations)
+	if err != nil || installation == nil {
+		return false, err
+	}
+	if installation.Installer == attach.InstallerOLM {
+		return true, fmt.Errorf("the operator of klusterlet %s is managed by the OLM ClusterServiceVersion %s, upgrade it with its subscription",
+			klusterlet.Name, installation.Release)
+	}
+
+	operatorImage := image.PullSpec(o.registry, image.OperatorImageName, o.values.BundleVersion.RegistrationImageVersion, image.Digests{})
+	fmt.Fprintf(o.Streams.Out, "klusterlet %s is attached, upgrading its operator %s/%s to %s\n",
+		klusterlet.Name, installation.OperatorNamespace, installation.OperatorName, operatorImage)
+	if o.ClusteradmFlags.DryRun {
+		return true, nil
+	}
+	if err := attach.UpgradeOperator(ctx, kubeClient, installation, attach.ModeKlusterlet, operatorImage); err != nil {
+		return true, err
+	}
+	klusterlet.Spec.RegistrationImagePullSpec = image.PullSpec(o.registry, image.RegistrationImageName, o.values.BundleVersion.RegistrationImageVersion, image.Digests{})
+	klusterlet.Spec.WorkImagePullSpec = image.PullSpec(o.registry, image.WorkImageName, o.values.BundleVersion.WorkImageVersion, image.Digests{})
+	if _, err := operatorClient.OperatorV1().Klusterlets().Update(ctx, klusterlet, metav1.UpdateOptions{}); err != nil {
+		return true, err
+	}
+	if installation.Installer == attach.InstallerHelm {
+		fmt.Fprintf(o.Streams.ErrOut, "WARNING: the next upgrade of the helm release %s overwrites the images\n", installation.Release)
+	}
+	return true, nil
+}
@@ -3,16 +3,23 @@ package klusterlet
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/config"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/oplog"
 )
 
 var example = `
 # Upgrade clustermanager
 %[1]s upgrade clustermanager --bundle-version latest
+
+# Upgrade the klusterlet, the workloads survive the disconnections of the hub up to an hour
+%[1]s upgrade klusterlet --bundle-version latest --appliedmanifestwork-eviction-grace-period 1h
 `
 
 // NewCmd ...
@@ -35,11 +42,13 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 			if err := o.validate(); err != nil {
 				return err
 			}
-			if err := o.run(); err != nil {
-				return err
+			start := time.Now()
+			err := o.run()
+			if !o.ClusteradmFlags.DryRun {
+				oplog.Record(o.ClusteradmFlags.KubectlFactory, config.ManagedClusterNamespace,
+					oplog.NewOperation(c, o.bundleVersion, start, err), o.Streams.ErrOut)
 			}
-
-			return nil
+			return err
 		},
 	}
 
@@ -49,5 +58,11 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		`the version of predefined compatible image versions. e.g. v0.6.0, defaulted to the latest release version. also, we can set "latest" to install latest develop version`)
 	cmd.Flags().BoolVar(&o.wait, "wait", false,
 		"If set, the command will initialize the OCM control plan in foreground.")
+	cmd.Flags().StringVar(&o.klusterletName, "klusterlet-name", join.DefaultKlusterletName,
+		"The name of the klusterlet to upgrade, the klusterlet of the hub on the clusters registered to several hubs")
+	cmd.Flags().DurationVar(&o.evictionGracePeriod, "appliedmanifestwork-eviction-grace-period", 0,
+		"The duration the work agent keeps the resources of the ManifestWorks it can not find on the hub before evicting them, "+
+			"the grace period of the klusterlet is kept if not set")
+	o.maintenance.AddFlags(cmd.Flags())
 	return cmd
 }
@@ -4,6 +4,7 @@ package klusterlet
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stolostron/applier/pkg/apply"
@@ -11,6 +12,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
 	join_scenario "open-cluster-management.io/clusteradm/pkg/cmd/join/scenario"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
 	version "open-cluster-management.io/clusteradm/pkg/helpers/version"
@@ -32,6 +35,9 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	if err := join.ValidateEvictionGracePeriod(o.evictionGracePeriod); err != nil {
+		return err
+	}
 
 	cfg, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
 	if err != nil {
@@ -43,17 +49,39 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	k, err := operatorClient.OperatorV1().Klusterlets().Get(context.TODO(), klusterletName, metav1.GetOptions{})
+	k, err := operatorClient.OperatorV1().Klusterlets().Get(context.TODO(), o.klusterletName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
-	klog.V(1).InfoS("init options:", "dry-run", o.ClusteradmFlags.DryRun)
+	klog.V(1).InfoS("init options:", "dry-run", o.ClusteradmFlags.DryRun, "klusterlet", o.klusterletName)
+	o.klusterlet = k
+	namespace := join.AgentNamespace(k.Name)
+	if len(k.Spec.Namespace) > 0 {
+		namespace = k.Spec.Namespace
+	}
+	dynamicClient, err := o.ClusteradmFlags.KubectlFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	obj, err := dynamicClient.Resource(operatorv1.GroupVersion.WithResource("klusterlets")).Get(context.TODO(), k.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
 	o.values = Values{
 		ClusterName: k.ClusterName,
 		Hub: Hub{
 			Registry: o.registry,
 		},
+		Klusterlet: Klusterlet{
+			Name:              k.Name,
+			Namespace:         namespace,
+			WorkConfiguration: join.WorkConfigurationOf(obj),
+		},
+	}
+
+	if o.evictionGracePeriod > 0 {
+		o.values.Klusterlet.WorkConfiguration.AppliedManifestWorkEvictionGracePeriod = o.evictionGracePeriod.String()
 	}
 
 	versionBundle, err := version.GetVersionBundle(o.bundleVersion)
@@ -74,6 +102,9 @@ func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
 }
 
 func (o *Options) validate() error {
+	if err := o.maintenance.Validate(time.Now(), o.Streams.ErrOut); err != nil {
+		return err
+	}
 
 	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
 	if err != nil {
@@ -106,6 +137,19 @@ func (o *Options) run() error {
 		return err
 	}
 
+	// the operator installed by another tool and attached is upgraded in place
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	if attached, err := o.upgradeAttached(kubeClient, operatorClient, o.klusterlet); attached || err != nil {
+		return err
+	}
+
 	applierBuilder := apply.NewApplierBuilder()
 	applier := applierBuilder.WithClient(kubeClient, apiExtensionsClient, dynamicClient).Build()
 
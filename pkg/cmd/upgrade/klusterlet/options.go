@@ -2,8 +2,13 @@
 package klusterlet
 
 import (
+	"time"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+	"open-cluster-management.io/clusteradm/pkg/cmd/join"
 	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers/maintenance"
 )
 
 //Options: The structure holding all the command-line options
@@ -18,8 +23,16 @@ type Options struct {
 	bundleVersion string
 	//If set, the command will hold until the OCM control plane initialized
 	wait bool
+	//The name of the klusterlet upgraded
+	klusterletName string
+	//The klusterlet upgraded
+	klusterlet *operatorv1.Klusterlet
+	//The duration the work agent keeps the resources of the ManifestWorks it can not find on the hub, kept if zero
+	evictionGracePeriod time.Duration
 
 	Streams genericclioptions.IOStreams
+	//The maintenance window the command runs in
+	maintenance maintenance.Options
 }
 
 type BundleVersion struct {
@@ -49,6 +62,12 @@ type Values struct {
 type Klusterlet struct {
 	//APIServer: The API Server external URL
 	APIServer string
+	//Name: the name of the klusterlet
+	Name string
+	//Namespace: the namespace of the agents of the klusterlet
+	Namespace string
+	//WorkConfiguration: the tuning of the work agent set at join, kept by the upgrade
+	WorkConfiguration join.WorkConfiguration
 }
 
 type Hub struct {
@@ -14,6 +14,9 @@ import (
 var example = `
 # Version
 %[1]s version
+
+# Version of the hub and of the klusterlets, and the newer bundle versions
+%[1]s version --klusterlet-kubeconfigs cluster1.kubeconfig,cluster2.kubeconfig --check-upgrade -o json
 `
 
 // NewCmd...
@@ -22,7 +25,7 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 	cmd := &cobra.Command{
 		Use:          "version",
 		Short:        "get the versions of different components",
-		Long:         "display versions of different components like: 'client' and 'server release'",
+		Long:         "display versions of different components like: 'client', 'server release', the hub and the klusterlet agents",
 		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
@@ -40,5 +43,11 @@ func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, stream
 		},
 	}
 
+	cmd.Flags().StringSliceVar(&o.klusterletKubeconfigs, "klusterlet-kubeconfigs", []string{},
+		"The kubeconfigs of the managed clusters whose klusterlet versions are reported and compared to the hub")
+	cmd.Flags().BoolVar(&o.checkUpgrade, "check-upgrade", false, "If set, report whether a newer version is available in --channel")
+	cmd.Flags().StringVar(&o.channel, "channel", channelLocal,
+		"The channel of the upgrades: local for the bundle versions of this client, release for the latest clusteradm release on GitHub")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format, should be json or text")
 	return cmd
 }
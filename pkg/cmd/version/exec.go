@@ -2,34 +2,251 @@
 package version
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
 	clusteradm "open-cluster-management.io/clusteradm"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	clusteradmjson "open-cluster-management.io/clusteradm/pkg/helpers/json"
+	"open-cluster-management.io/clusteradm/pkg/helpers/restconfig"
 	version "open-cluster-management.io/clusteradm/pkg/helpers/version"
 )
 
+const (
+	channelLocal   = "local"
+	channelRelease = "release"
+
+	// latestReleaseURL returns the latest release of clusteradm, which ships the newest bundle versions
+	latestReleaseURL = "https://api.github.com/repos/open-cluster-management-io/clusteradm/releases/latest"
+)
+
 func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("version options:", "klusterlet-kubeconfigs", o.klusterletKubeconfigs, "check-upgrade", o.checkUpgrade,
+		"channel", o.channel, "output", o.output)
 	return nil
 }
 
 func (o *Options) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("--output should be text or json")
+	}
+	if o.channel != channelLocal && o.channel != channelRelease {
+		return fmt.Errorf("--channel should be %s or %s", channelLocal, channelRelease)
+	}
 	return nil
 }
 
 func (o *Options) run() (err error) {
-	fmt.Printf("client\t\tversion\t:%s\n", clusteradm.GetVersion())
-	discoveryClient, err := o.ClusteradmFlags.KubectlFactory.ToDiscoveryClient()
+	report := &Report{
+		Client:        clusteradm.GetVersion(),
+		DefaultBundle: version.GetDefaultBundleVersion(),
+	}
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
 	if err != nil {
 		return err
 	}
-	serverVersion, err := discoveryClient.ServerVersion()
+	hubCache := o.ClusteradmFlags.Cache()
+	serverVersion := &k8sversion.Info{}
+	if !hubCache.Get(restConfig.Host, "server-version", serverVersion) {
+		discoveryClient, err := o.ClusteradmFlags.KubectlFactory.ToDiscoveryClient()
+		if err != nil {
+			return err
+		}
+		serverVersion, err = discoveryClient.ServerVersion()
+		if err != nil {
+			return err
+		}
+		hubCache.Set(restConfig.Host, "server-version", serverVersion)
+	}
+	report.Server = serverVersion.GitVersion
+
+	// the cluster of the context is a hub, a managed cluster or both
+	if report.Hub, err = hubVersions(restConfig); err != nil {
+		return err
+	}
+	klusterlet, err := klusterletVersions(restConfig, "")
 	if err != nil {
 		return err
 	}
-	fmt.Printf("server release\tversion\t:%s\n", serverVersion.GitVersion)
+	if klusterlet != nil {
+		report.Klusterlets = append(report.Klusterlets, *klusterlet)
+	}
+	for _, kubeconfig := range o.klusterletKubeconfigs {
+		config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return err
+		}
+		klusterlet, err := klusterletVersions(restconfig.Wrap(config), kubeconfig)
+		if err != nil {
+			return err
+		}
+		if klusterlet == nil {
+			return fmt.Errorf("no klusterlet is found in the cluster of %s", kubeconfig)
+		}
+		report.Klusterlets = append(report.Klusterlets, *klusterlet)
+	}
+	report.Drift = drift(report.Hub, report.Klusterlets)
 
-	bundleVersion := version.GetDefaultBundleVersion()
-	fmt.Printf("default bundle\tversion\t:%s\n", bundleVersion)
+	if o.checkUpgrade {
+		if report.Upgrade, err = o.upgrade(report); err != nil {
+			return err
+		}
+	}
+
+	if o.output == "json" {
+		return clusteradmjson.WriteJsonOutput(os.Stdout, report)
+	}
+	printReport(report)
 	return nil
 }
+
+// hubVersions returns the versions of the hub components, nil if the cluster is not a hub
+func hubVersions(restConfig *rest.Config) (*ComponentVersions, error) {
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := operatorClient.OperatorV1().ClusterManagers().Get(context.TODO(), config.ClusterManagerName, metav1.GetOptions{})
+	if err != nil {
+		// the cluster is not a hub or the user is not allowed to read the cluster manager
+		klog.V(2).InfoS("the hub versions are not reported", "error", err)
+		return nil, nil
+	}
+	return &ComponentVersions{
+		Registration: version.ImageVersion(cm.Spec.RegistrationImagePullSpec),
+		Work:         version.ImageVersion(cm.Spec.WorkImagePullSpec),
+		Placement:    version.ImageVersion(cm.Spec.PlacementImagePullSpec),
+	}, nil
+}
+
+// klusterletVersions returns the versions of the klusterlet agents, nil if the cluster is not a managed cluster
+func klusterletVersions(restConfig *rest.Config, kubeconfig string) (*KlusterletVersions, error) {
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	klusterlet, err := operatorClient.OperatorV1().Klusterlets().Get(context.TODO(), "klusterlet", metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return nil, nil
+	case err != nil && len(kubeconfig) == 0:
+		// the cluster of the context may not be a managed cluster
+		klog.V(2).InfoS("the klusterlet versions are not reported", "error", err)
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get the klusterlet of %s: %v", kubeconfig, err)
+	}
+	return &KlusterletVersions{
+		Cluster:    klusterlet.Spec.ClusterName,
+		Kubeconfig: kubeconfig,
+		ComponentVersions: ComponentVersions{
+			Registration: version.ImageVersion(klusterlet.Spec.RegistrationImagePullSpec),
+			Work:         version.ImageVersion(klusterlet.Spec.WorkImagePullSpec),
+		},
+	}, nil
+}
+
+// drift returns the klusterlets whose versions differ from the hub or from each other
+func drift(hub *ComponentVersions, klusterlets []KlusterletVersions) []string {
+	var result []string
+	reference := ""
+	if hub != nil {
+		reference = hub.Registration
+	} else if len(klusterlets) > 0 {
+		reference = klusterlets[0].Registration
+	}
+	for _, k := range klusterlets {
+		if k.Registration != reference || k.Work != k.Registration {
+			message := fmt.Sprintf("cluster %s runs registration %s and work %s", k.Cluster, k.Registration, k.Work)
+			if hub != nil {
+				message = fmt.Sprintf("%s, the hub runs %s", message, hub.Registration)
+				if err := version.CheckSkew(hub.Registration, k.Registration); err != nil {
+					message = fmt.Sprintf("%s: %v", message, err)
+				}
+			}
+			result = append(result, message)
+		}
+	}
+	return result
+}
+
+// upgrade returns the newest version of the channel
+func (o *Options) upgrade(report *Report) (*Upgrade, error) {
+	upgrade := &Upgrade{Channel: o.channel}
+	switch o.channel {
+	case channelLocal:
+		// the bundles of this client are compared to the hub, or to the default bundle if there is no hub
+		upgrade.Current = report.DefaultBundle
+		if report.Hub != nil {
+			upgrade.Current = report.Hub.Registration
+		}
+		versions := version.ListBundleVersions()
+		upgrade.Latest = versions[len(versions)-1]
+	case channelRelease:
+		// a newer client ships newer bundles
+		upgrade.Current = report.Client
+		latest, err := latestRelease()
+		if err != nil {
+			return nil, err
+		}
+		upgrade.Latest = latest
+	}
+	upgrade.Available = version.Compare(upgrade.Latest, upgrade.Current) > 0
+	return upgrade, nil
+}
+
+func latestRelease() (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(latestReleaseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the latest release: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get the latest release: %s", resp.Status)
+	}
+	release := struct {
+		TagName string `json:"tag_name"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to decode the latest release: %v", err)
+	}
+	return release.TagName, nil
+}
+
+func printReport(report *Report) {
+	fmt.Printf("client\t\tversion\t:%s\n", report.Client)
+	fmt.Printf("server release\tversion\t:%s\n", report.Server)
+	fmt.Printf("default bundle\tversion\t:%s\n", report.DefaultBundle)
+	if report.Hub != nil {
+		fmt.Printf("hub registration\tversion\t:%s\n", report.Hub.Registration)
+		fmt.Printf("hub work\tversion\t:%s\n", report.Hub.Work)
+		fmt.Printf("hub placement\tversion\t:%s\n", report.Hub.Placement)
+	}
+	for _, k := range report.Klusterlets {
+		fmt.Printf("klusterlet %s\tversion\t:registration %s, work %s\n", k.Cluster, k.Registration, k.Work)
+	}
+	for _, d := range report.Drift {
+		fmt.Printf("drift: %s\n", d)
+	}
+	if report.Upgrade != nil {
+		if report.Upgrade.Available {
+			fmt.Printf("upgrade: %s is available in the %s channel, the current version is %s\n",
+				report.Upgrade.Latest, report.Upgrade.Channel, report.Upgrade.Current)
+		} else {
+			fmt.Printf("upgrade: %s is the newest version of the %s channel\n", report.Upgrade.Current, report.Upgrade.Channel)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright Contributors to the Open Cluster Management project
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDrift(t *testing.T) {
+	klusterlet := func(cluster, registration, work string) KlusterletVersions {
+		return KlusterletVersions{Cluster: cluster, ComponentVersions: ComponentVersions{Registration: registration, Work: work}}
+	}
+	hub := &ComponentVersions{Registration: "v0.10.0", Work: "v0.10.0"}
+	cases := []struct {
+		name        string
+		hub         *ComponentVersions
+		klusterlets []KlusterletVersions
+		expected    []string
+	}{
+		{
+			name:        "no drift",
+			hub:         hub,
+			klusterlets: []KlusterletVersions{klusterlet("cluster1", "v0.10.0", "v0.10.0")},
+		},
+		{
+			name: "drift from the hub",
+			hub:  hub,
+			klusterlets: []KlusterletVersions{
+				klusterlet("cluster1", "v0.9.0", "v0.9.0"),
+				klusterlet("cluster2", "v0.8.0", "v0.8.0"),
+			},
+			expected: []string{
+				"cluster cluster1 runs registration v0.9.0 and work v0.9.0, the hub runs v0.10.0",
+				"cluster cluster2 runs registration v0.8.0 and work v0.8.0, the hub runs v0.10.0: " +
+					"the agents v0.8.0 are more than one minor version older than the hub v0.10.0",
+			},
+		},
+		{
+			name: "drift between the klusterlets",
+			klusterlets: []KlusterletVersions{
+				klusterlet("cluster1", "v0.9.0", "v0.9.0"),
+				klusterlet("cluster2", "v0.9.0", "v0.8.0"),
+			},
+			expected: []string{"cluster cluster2 runs registration v0.9.0 and work v0.8.0"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := drift(c.hub, c.klusterlets); !reflect.DeepEqual(got, c.expected) {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	o := &Options{channel: channelLocal}
+	upgrade, err := o.upgrade(&Report{DefaultBundle: "0.9.1", Hub: &ComponentVersions{Registration: "v0.8.0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upgrade.Current != "v0.8.0" || !upgrade.Available {
+		t.Errorf("expected an upgrade of the hub v0.8.0, got %+v", upgrade)
+	}
+}
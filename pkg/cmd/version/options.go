@@ -9,6 +9,51 @@ import (
 type Options struct {
 	//ClusteradmFlags: The generic options from the clusteradm cli-runtime.
 	ClusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags
+	//The kubeconfigs of the managed clusters whose klusterlet versions are reported
+	klusterletKubeconfigs []string
+	//If true the newest version of the channel is reported
+	checkUpgrade bool
+	//The channel of the upgrades, local or release
+	channel string
+	//The output format, text or json
+	output string
+}
+
+// Report: The versions of the client, the hub and the klusterlets
+type Report struct {
+	Client        string `json:"client"`
+	Server        string `json:"server"`
+	DefaultBundle string `json:"defaultBundle"`
+	//Hub: the versions of the hub components, empty if the cluster of the context is not a hub
+	Hub *ComponentVersions `json:"hub,omitempty"`
+	//Klusterlets: the versions of the klusterlet agents of the cluster of the context and of the kubeconfigs
+	Klusterlets []KlusterletVersions `json:"klusterlets,omitempty"`
+	//Drift: the klusterlets whose versions differ from the hub or from each other
+	Drift []string `json:"drift,omitempty"`
+	//Upgrade: the newest version of the channel
+	Upgrade *Upgrade `json:"upgrade,omitempty"`
+}
+
+// ComponentVersions: The image versions of the components
+type ComponentVersions struct {
+	Registration string `json:"registration"`
+	Work         string `json:"work"`
+	Placement    string `json:"placement,omitempty"`
+}
+
+// KlusterletVersions: The image versions of the agents of a managed cluster
+type KlusterletVersions struct {
+	Cluster    string `json:"cluster"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	ComponentVersions
+}
+
+// Upgrade: The newest version of the channel
+type Upgrade struct {
+	Channel   string `json:"channel"`
+	Current   string `json:"current"`
+	Latest    string `json:"latest"`
+	Available bool   `json:"available"`
 }
 
 func newOptions(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *Options {
@@ -0,0 +1,23 @@
+// Copyright Contributors to the Open Cluster Management project
+package work
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/cmd/work/restore"
+	"open-cluster-management.io/clusteradm/pkg/cmd/work/snapshot"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// NewCmd provides a cobra command wrapping the commands archiving and restoring the works of the clusters
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "work",
+		Short: "archive and restore the works of the clusters",
+	}
+
+	cmd.AddCommand(snapshot.NewCmd(clusteradmFlags, streams))
+	cmd.AddCommand(restore.NewCmd(clusteradmFlags, streams))
+
+	return cmd
+}
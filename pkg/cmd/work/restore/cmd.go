@@ -0,0 +1,55 @@
+// Copyright Contributors to the Open Cluster Management project
+package restore
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Apply the archived works to the cluster they were archived from
+%[1]s work restore --input works.tar.gz
+# Migrate the archived works of cluster1 to cluster2
+%[1]s work restore --input works.tar.gz --cluster cluster2
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "apply the works archived by work snapshot",
+		Long: "apply the manifestworks archived by '" + helpers.GetExampleHeader() + " work snapshot' to the cluster they " +
+			"were archived from, or to --cluster. The works existing on the cluster are not changed unless --overwrite is set",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PreRun: func(c *cobra.Command, args []string) {
+			helpers.DryRunMessage(o.ClusteradmFlags.DryRun)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.inputFile, "input", "", "The archive written by work snapshot")
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "Name of the managed cluster the works are applied to, the cluster they were archived from by default")
+	cmd.Flags().BoolVar(&o.overwrite, "overwrite", false, "If true, the works existing on the cluster are updated with the archived ones")
+
+	return cmd
+}
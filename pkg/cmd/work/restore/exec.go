@@ -0,0 +1,121 @@
+// Copyright Contributors to the Open Cluster Management project
+package restore
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/clusteradm/pkg/helpers/workarchive"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("work restore options:", "dry-run", o.ClusteradmFlags.DryRun, "input", o.inputFile,
+		"cluster", o.cluster, "overwrite", o.overwrite)
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if len(o.inputFile) == 0 {
+		return fmt.Errorf("the input file must be specified")
+	}
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	f, err := os.Open(o.inputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	works, err := workarchive.Read(f)
+	if err != nil {
+		return err
+	}
+	if len(o.cluster) > 0 {
+		for i := range works {
+			works[i].Namespace = o.cluster
+		}
+	}
+
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	// the works of a cluster which is not registered are never applied
+	clusters := sets.NewString()
+	for _, work := range works {
+		clusters.Insert(work.Namespace)
+	}
+	for _, cluster := range clusters.List() {
+		if _, err := clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), cluster, metav1.GetOptions{}); err != nil {
+			if errors.IsNotFound(err) {
+				return fmt.Errorf("the cluster %s is not registered to the hub", cluster)
+			}
+			return err
+		}
+	}
+
+	for i := range works {
+		if err := o.apply(workClient, &works[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply creates the work, or updates the existing work with --overwrite
+func (o *Options) apply(workClient workclientset.Interface, work *workapiv1.ManifestWork) error {
+	existing, err := workClient.WorkV1().ManifestWorks(work.Namespace).Get(context.TODO(), work.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if o.ClusteradmFlags.DryRun {
+			fmt.Fprintf(o.Streams.Out, "work %s would be created in cluster %s\n", work.Name, work.Namespace)
+			return nil
+		}
+		if _, err := workClient.WorkV1().ManifestWorks(work.Namespace).Create(context.TODO(), work, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Streams.Out, "work %s is created in cluster %s\n", work.Name, work.Namespace)
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if !o.overwrite {
+		fmt.Fprintf(o.Streams.Out, "work %s already exists in cluster %s, it is not changed without --overwrite\n", work.Name, work.Namespace)
+		return nil
+	}
+	if o.ClusteradmFlags.DryRun {
+		fmt.Fprintf(o.Streams.Out, "work %s would be updated in cluster %s\n", work.Name, work.Namespace)
+		return nil
+	}
+	existing.Labels = work.Labels
+	existing.Annotations = work.Annotations
+	existing.Spec = work.Spec
+	if _, err := workClient.WorkV1().ManifestWorks(work.Namespace).Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "work %s is updated in cluster %s\n", work.Name, work.Namespace)
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+	"open-cluster-management.io/clusteradm/pkg/helpers"
+)
+
+var example = `
+# Archive the works of cluster1
+%[1]s work snapshot --cluster cluster1 --output works.tar.gz
+`
+
+// NewCmd ...
+func NewCmd(clusteradmFlags *genericclioptionsclusteradm.ClusteradmFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(clusteradmFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "archive the works of a cluster",
+		Long: "archive the manifestworks of a cluster in a gzipped tar file, without their status, " +
+			"the works are applied again to the cluster or to another cluster by '" + helpers.GetExampleHeader() + " work restore'",
+		Example:      fmt.Sprintf(example, helpers.GetExampleHeader()),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if err := o.run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.cluster, "cluster", "", "Name of the managed cluster the works are archived from")
+	cmd.Flags().StringVar(&o.outputFile, "output", "", "The file the works are archived to")
+
+	return cmd
+}
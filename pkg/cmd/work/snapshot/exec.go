@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/workarchive"
+)
+
+func (o *Options) complete(cmd *cobra.Command, args []string) (err error) {
+	klog.V(1).InfoS("work snapshot options:", "cluster", o.cluster, "output", o.outputFile)
+	return nil
+}
+
+func (o *Options) validate() (err error) {
+	if err := o.ClusteradmFlags.ValidateHub(); err != nil {
+		return err
+	}
+	if len(o.cluster) == 0 {
+		return fmt.Errorf("the name of the cluster must be specified")
+	}
+	if len(o.outputFile) == 0 {
+		return fmt.Errorf("the output file must be specified")
+	}
+	return nil
+}
+
+func (o *Options) run() (err error) {
+	restConfig, err := o.ClusteradmFlags.KubectlFactory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	workClient, err := workclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	works, err := workClient.WorkV1().ManifestWorks(o.cluster).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(o.outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := workarchive.Write(f, works.Items); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "%d works of cluster %s are archived to %s\n", len(works.Items), o.cluster, o.outputFile)
+	return nil
+}
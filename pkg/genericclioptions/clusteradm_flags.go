@@ -3,11 +3,19 @@ package genericclioptions
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	"open-cluster-management.io/clusteradm/pkg/helpers/cache"
 	"open-cluster-management.io/clusteradm/pkg/helpers/check"
+	"open-cluster-management.io/clusteradm/pkg/helpers/hubs"
+	"open-cluster-management.io/clusteradm/pkg/helpers/incluster"
 )
 
 type ClusteradmFlags struct {
@@ -16,6 +24,28 @@ type ClusteradmFlags struct {
 	DryRun  bool
 	Timeout int
 	Context string
+	//if set the spans of the API calls are exported to the OpenTelemetry collector
+	OtelEndpoint string
+	//CacheDir: the directory of the discovery and hub metadata cache, shared with the kubeconfig flags
+	CacheDir *string
+	//if set the cached discovery and hub metadata are ignored
+	NoCache bool
+	//Hub: the name of the hub connection targeted by the command, the current hub if not set
+	Hub string
+	//TLSMinVersion: the minimum TLS version of the connections to the clusters
+	TLSMinVersion string
+	//TLSCipherSuites: the cipher suites of the connections to the clusters
+	TLSCipherSuites []string
+	//InCluster: clusteradm runs in a pod without kubeconfig, the clients are built from its service account
+	InCluster bool
+	//Lang: the language of the messages, the one of the locale if not set
+	Lang string
+	//NoColor: if set the output is not colored
+	NoColor bool
+	//ReadOnly: if set the API calls changing the clusters are refused
+	ReadOnly bool
+	//ShowRequiredRBAC: if set the rbac of the command is printed instead of running it
+	ShowRequiredRBAC bool
 }
 
 // NewClusteradmFlags returns ClusteradmFlags with default values set
@@ -28,6 +58,65 @@ func NewClusteradmFlags(f cmdutil.Factory) *ClusteradmFlags {
 func (f *ClusteradmFlags) AddFlags(flags *pflag.FlagSet) {
 	flags.BoolVar(&f.DryRun, "dry-run", false, "If set the generated resources will be displayed but not applied")
 	flags.IntVar(&f.Timeout, "timeout", 300, "extend timeout from 300 secounds ")
+	flags.StringVar(&f.OtelEndpoint, "otel-endpoint", "",
+		"If set the API calls are traced and the spans are exported to the OTLP/HTTP collector at this endpoint (eg. localhost:4318)")
+	flags.BoolVar(&f.NoCache, "no-cache", false, "If set the API discovery and hub metadata are not read from the cache in --cache-dir")
+	flags.StringVar(&f.TLSMinVersion, "tls-min-version", "",
+		"The minimum TLS version of the connections to the clusters, the API servers are checked to support it by init and join. "+
+			"The kubeconfigs rendered for the agents can not carry it. Possible values: "+strings.Join(cliflag.TLSPossibleVersions(), ", "))
+	flags.StringSliceVar(&f.TLSCipherSuites, "tls-cipher-suites", []string{},
+		"The cipher suites of the connections to the clusters with TLS 1.2 and below (eg. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)")
+	flags.StringVar(&f.Lang, "lang", "", "The language of the messages (eg. fr), the one of the LC_ALL, LC_MESSAGES or LANG environment variables if not set")
+	flags.BoolVar(&f.NoColor, "no-color", false, "If set the output is not colored, it is not either if the NO_COLOR environment variable is set or the output is not a terminal")
+	flags.BoolVar(&f.ReadOnly, "read-only", false,
+		"If set the API calls changing the clusters fail with a summary of the change, to explore a hub safely or verify the RBAC of the user of --as")
+	flags.StringVar(&f.Hub, "hub", "", "The name of the hub connection added by 'clusteradm hub add' to target, the hub of 'clusteradm hub use' if not set")
+	flags.BoolVar(&f.ShowRequiredRBAC, "show-required-rbac", false,
+		"If set the clusterrole of the permissions the command needs on the hub is printed and the command is not run")
+}
+
+// UseHub points the kubeconfig flags to the hub targeted by the command and sets the flags the user did not
+// set to the defaults of the hub. The current hub is ignored if --kubeconfig or --context is set.
+func (f *ClusteradmFlags) UseHub(cmd *cobra.Command, kubeConfigFlags *genericclioptions.ConfigFlags) error {
+	defer func() {
+		// the context is known once the flags are parsed
+		f.Context = *kubeConfigFlags.Context
+	}()
+	if hubs.Skipped(cmd) {
+		return nil
+	}
+	explicit := cmd.Flags().Changed("kubeconfig") || cmd.Flags().Changed("context")
+	if explicit && len(f.Hub) > 0 {
+		return fmt.Errorf("--hub can not be set with --kubeconfig or --context")
+	}
+	if explicit {
+		return nil
+	}
+	path, err := hubs.DefaultPath()
+	if err != nil {
+		return err
+	}
+	config, err := hubs.Load(path)
+	if err != nil {
+		return err
+	}
+	hub, err := config.Resolve(f.Hub)
+	if err != nil || hub == nil {
+		return err
+	}
+	*kubeConfigFlags.KubeConfig = hub.Kubeconfig
+	*kubeConfigFlags.Context = hub.Context
+	return hub.ApplyDefaults(cmd)
+}
+
+// DetectInCluster sets InCluster if no kubeconfig is found and clusteradm runs in a pod. The clients of
+// the kubectl factory, the ones of the hub and of the managed cluster alike, then fall back to the
+// service account of the pod. It is called once the hub targeted by the command is known.
+func (f *ClusteradmFlags) DetectInCluster(kubeConfigFlags *genericclioptions.ConfigFlags) {
+	f.InCluster = incluster.Detect(kubeConfigFlags.ToRawKubeConfigLoader())
+	if f.InCluster {
+		klog.V(1).InfoS("no kubeconfig is found, using the service account of the pod")
+	}
 }
 
 // SetContext will set current context from command line argument --context.
@@ -37,6 +126,19 @@ func (f *ClusteradmFlags) SetContext(context *string) {
 	}
 }
 
+// SetCacheDir will set the cache directory from command line argument --cache-dir.
+func (f *ClusteradmFlags) SetCacheDir(cacheDir *string) {
+	f.CacheDir = cacheDir
+}
+
+// Cache returns the on-disk cache of the hub metadata, it is nil if --no-cache is set.
+func (f *ClusteradmFlags) Cache() *cache.Cache {
+	if f.CacheDir == nil {
+		return nil
+	}
+	return cache.New(*f.CacheDir, f.NoCache)
+}
+
 func (f *ClusteradmFlags) ValidateHub() error {
 	client, err := f.buildClusterClientset()
 	if err != nil {
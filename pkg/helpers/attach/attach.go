@@ -0,0 +1,178 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package attach records the operator of a ClusterManager or Klusterlet installed by another tool, e.g. Helm
+// or OLM, so upgrade and clean manage that operator instead of the one of the templates of clusteradm.
+package attach
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Annotation is the annotation of the ClusterManager or Klusterlet attached to clusteradm
+const Annotation = "open-cluster-management.io/clusteradm-attached"
+
+const (
+	// ModeHub is the argument of the registration operator managing the ClusterManager
+	ModeHub = "hub"
+	// ModeKlusterlet is the argument of the registration operator managing the Klusterlet
+	ModeKlusterlet = "klusterlet"
+
+	InstallerHelm = "Helm"
+	InstallerOLM  = "OLM"
+	// InstallerOther is any other installer, e.g. clusteradm or kubectl
+	InstallerOther = "Other"
+)
+
+// Installation is the operator of an attached ClusterManager or Klusterlet
+type Installation struct {
+	OperatorNamespace string `json:"operatorNamespace"`
+	OperatorName      string `json:"operatorName"`
+	// Installer is the tool which installed the operator, Helm, OLM or Other
+	Installer string `json:"installer"`
+	// Release is the Helm release or the OLM ClusterServiceVersion of the operator, if any
+	Release    string    `json:"release,omitempty"`
+	AttachedAt time.Time `json:"attachedAt"`
+}
+
+// Parse returns the installation of the annotations, nil if they are not attached
+func Parse(annotations map[string]string) (*Installation, error) {
+	value, ok := annotations[Annotation]
+	if !ok {
+		return nil, nil
+	}
+	installation := &Installation{}
+	if err := json.Unmarshal([]byte(value), installation); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %v", Annotation, err)
+	}
+	return installation, nil
+}
+
+// Patch returns the merge patch setting the annotation of the installation
+func (i *Installation) Patch() ([]byte, error) {
+	value, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{Annotation: string(value)},
+		},
+	})
+}
+
+// ManagedExternally returns true if the operator is upgraded and removed by its installer, Helm or OLM
+func (i *Installation) ManagedExternally() bool {
+	return i.Installer == InstallerHelm || i.Installer == InstallerOLM
+}
+
+// Discover returns the installation of the registration operator running in the mode, hub or klusterlet,
+// an error if there is none or more than one
+func Discover(ctx context.Context, kubeClient kubernetes.Interface, mode string) (*Installation, error) {
+	operators, err := listOperators(ctx, kubeClient, mode)
+	if err != nil {
+		return nil, err
+	}
+	found := []*Installation{}
+	for _, deployment := range operators {
+		installer, release := installerOf(deployment.ObjectMeta)
+		found = append(found, &Installation{
+			OperatorNamespace: deployment.Namespace,
+			OperatorName:      deployment.Name,
+			Installer:         installer,
+			Release:           release,
+			AttachedAt:        time.Now().UTC(),
+		})
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no registration operator running in %s mode is found", mode)
+	case 1:
+		return found[0], nil
+	}
+	names := []string{}
+	for _, installation := range found {
+		names = append(names, installation.OperatorNamespace+"/"+installation.OperatorName)
+	}
+	return nil, fmt.Errorf("more than one registration operator running in %s mode is found: %v", mode, names)
+}
+
+// listOperators returns the deployments of the registration operators running in the mode
+func listOperators(ctx context.Context, kubeClient kubernetes.Interface, mode string) ([]appsv1.Deployment, error) {
+	deployments, err := kubeClient.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	operators := []appsv1.Deployment{}
+	for i := range deployments.Items {
+		if isOperator(&deployments.Items[i], mode) {
+			operators = append(operators, deployments.Items[i])
+		}
+	}
+	return operators, nil
+}
+
+// isOperator returns true if a container of the deployment runs the registration operator in the mode
+func isOperator(deployment *appsv1.Deployment, mode string) bool {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if isOperatorContainer(container, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+func isOperatorContainer(container corev1.Container, mode string) bool {
+	args := append(append([]string{}, container.Command...), container.Args...)
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "/registration-operator" && args[i+1] == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// installerOf returns the installer of the object and its release, from the metadata set by Helm and OLM
+func installerOf(meta metav1.ObjectMeta) (string, string) {
+	if release, ok := meta.Annotations["meta.helm.sh/release-name"]; ok {
+		return InstallerHelm, release
+	}
+	if meta.Labels["app.kubernetes.io/managed-by"] == "Helm" {
+		return InstallerHelm, meta.Labels["app.kubernetes.io/instance"]
+	}
+	if owner, ok := meta.Labels["olm.owner"]; ok {
+		return InstallerOLM, owner
+	}
+	for _, ref := range meta.OwnerReferences {
+		if ref.Kind == "ClusterServiceVersion" {
+			return InstallerOLM, ref.Name
+		}
+	}
+	return InstallerOther, ""
+}
+
+// UpgradeOperator sets the image of the registration operator container of the deployment of the installation
+func UpgradeOperator(ctx context.Context, kubeClient kubernetes.Interface, installation *Installation, mode, image string) error {
+	deployment, err := kubeClient.AppsV1().Deployments(installation.OperatorNamespace).Get(ctx, installation.OperatorName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !isOperator(deployment, mode) {
+		return fmt.Errorf("deployment %s/%s does not run the registration operator in %s mode anymore, run attach again",
+			deployment.Namespace, deployment.Name, mode)
+	}
+	for i, container := range deployment.Spec.Template.Spec.Containers {
+		if isOperatorContainer(container, mode) {
+			deployment.Spec.Template.Spec.Containers[i].Image = image
+		}
+	}
+	_, err = kubeClient.AppsV1().Deployments(deployment.Namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	return err
+}
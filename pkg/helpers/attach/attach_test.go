@@ -0,0 +1,129 @@
+// Copyright Contributors to the Open Cluster Management project
+package attach
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newOperator(namespace, name, mode string, labels, annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels, Annotations: annotations},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "sidecar", Image: "sidecar:v1"},
+			{Name: "registration-operator", Image: "registration-operator:v0.9.0", Args: []string{"/registration-operator", mode}},
+		}}}},
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	cases := []struct {
+		name              string
+		objs              []runtime.Object
+		expectedName      string
+		expectedInstaller string
+		expectedRelease   string
+		expectedErr       string
+	}{
+		{
+			name:        "none",
+			objs:        []runtime.Object{newOperator("ocm", "klusterlet", ModeKlusterlet, nil, nil)},
+			expectedErr: "no registration operator",
+		},
+		{
+			name: "helm",
+			objs: []runtime.Object{
+				newOperator("ocm", "cluster-manager", ModeHub, nil, map[string]string{"meta.helm.sh/release-name": "ocm"}),
+				newOperator("ocm", "klusterlet", ModeKlusterlet, nil, nil),
+			},
+			expectedName: "cluster-manager", expectedInstaller: InstallerHelm, expectedRelease: "ocm",
+		},
+		{
+			name:         "olm",
+			objs:         []runtime.Object{newOperator("operators", "cluster-manager", ModeHub, map[string]string{"olm.owner": "cluster-manager.v0.9.0"}, nil)},
+			expectedName: "cluster-manager", expectedInstaller: InstallerOLM, expectedRelease: "cluster-manager.v0.9.0",
+		},
+		{
+			name:         "other",
+			objs:         []runtime.Object{newOperator("open-cluster-management", "cluster-manager", ModeHub, nil, nil)},
+			expectedName: "cluster-manager", expectedInstaller: InstallerOther,
+		},
+		{
+			name: "several",
+			objs: []runtime.Object{
+				newOperator("ocm", "cluster-manager", ModeHub, nil, nil),
+				newOperator("open-cluster-management", "cluster-manager", ModeHub, nil, nil),
+			},
+			expectedErr: "more than one",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			installation, err := Discover(context.TODO(), kubefake.NewSimpleClientset(c.objs...), ModeHub)
+			if len(c.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+					t.Fatalf("expected error %q, got %v", c.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if installation.OperatorName != c.expectedName || installation.Installer != c.expectedInstaller || installation.Release != c.expectedRelease {
+				t.Errorf("unexpected installation %v", installation)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	installation := &Installation{OperatorNamespace: "ocm", OperatorName: "cluster-manager", Installer: InstallerHelm, Release: "ocm"}
+	patch, err := installation.Patch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(patch, obj); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(obj.Annotations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *parsed != *installation {
+		t.Errorf("expected %v, got %v", installation, parsed)
+	}
+	if parsed, err := Parse(nil); parsed != nil || err != nil {
+		t.Errorf("expected no installation, got %v %v", parsed, err)
+	}
+	if _, err := Parse(map[string]string{Annotation: "{"}); err == nil {
+		t.Errorf("expected an invalid annotation to fail")
+	}
+}
+
+func TestUpgradeOperator(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset(newOperator("ocm", "cluster-manager", ModeHub, nil, nil))
+	installation := &Installation{OperatorNamespace: "ocm", OperatorName: "cluster-manager"}
+	if err := UpgradeOperator(context.TODO(), kubeClient, installation, ModeHub, "registration-operator:v0.10.0"); err != nil {
+		t.Fatal(err)
+	}
+	deployment, err := kubeClient.AppsV1().Deployments("ocm").Get(context.TODO(), "cluster-manager", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers := deployment.Spec.Template.Spec.Containers
+	if containers[0].Image != "sidecar:v1" || containers[1].Image != "registration-operator:v0.10.0" {
+		t.Errorf("expected only the operator to be upgraded, got %v", containers)
+	}
+	if err := UpgradeOperator(context.TODO(), kubeClient, installation, ModeKlusterlet, "registration-operator:v0.10.0"); err == nil {
+		t.Errorf("expected the operator of another mode to be refused")
+	}
+}
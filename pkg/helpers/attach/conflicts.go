@@ -0,0 +1,194 @@
+// Copyright Contributors to the Open Cluster Management project
+package attach
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"open-cluster-management.io/clusteradm/pkg/helpers/version"
+)
+
+// ocmGroupSuffix is the suffix of the api groups of OCM and of the names of its webhooks
+const ocmGroupSuffix = "open-cluster-management.io"
+
+// crdNames are the CRDs of the ClusterManager and Klusterlet of the modes
+var crdNames = map[string]string{
+	ModeHub:        "clustermanagers.operator.open-cluster-management.io",
+	ModeKlusterlet: "klusterlets.operator.open-cluster-management.io",
+}
+
+// ConflictCheck reports the installations of OCM conflicting with each other, a frequent cause of
+// registration failures: several registration operators running in the mode, e.g. installed by
+// clusteradm, Helm and OLM, an operator other than the one clusteradm installs, a CRD installed by
+// another tool than the operator, and the webhooks of OCM served by a service which does not exist.
+type ConflictCheck struct {
+	KubeClient          kubernetes.Interface
+	APIExtensionsClient apiextensionsclient.Interface
+	// Mode is the mode of the registration operators, hub or klusterlet, both if it is empty
+	Mode string
+	// OperatorNamespace and OperatorName are the deployment of the operator installed by clusteradm in
+	// the mode, the other operators are not reported if they are empty
+	OperatorNamespace string
+	OperatorName      string
+}
+
+func (c ConflictCheck) Check() (warningList []string, errorList []error) {
+	ctx := context.TODO()
+	modes := []string{c.Mode}
+	if len(c.Mode) == 0 {
+		modes = []string{ModeHub, ModeKlusterlet}
+	}
+	for _, mode := range modes {
+		warnings, errs := c.checkOperators(ctx, mode)
+		warningList, errorList = append(warningList, warnings...), append(errorList, errs...)
+	}
+
+	webhookWarnings, webhookErrors := checkWebhooks(ctx, c.KubeClient)
+	return append(warningList, webhookWarnings...), append(errorList, webhookErrors...)
+}
+
+// checkOperators reports the registration operators of the mode conflicting with each other or with the
+// operator of clusteradm
+func (c ConflictCheck) checkOperators(ctx context.Context, mode string) (warningList []string, errorList []error) {
+	operators, err := listOperators(ctx, c.KubeClient, mode)
+	if errors.IsForbidden(err) {
+		return []string{fmt.Sprintf("the registration operators are not checked, the deployments are not readable: %v", err)}, nil
+	}
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list the registration operators: %v", err)}
+	}
+
+	switch {
+	case len(operators) > 1:
+		descriptions := []string{}
+		for _, operator := range operators {
+			descriptions = append(descriptions, describeOperator(operator, mode))
+		}
+		errorList = append(errorList, fmt.Errorf("%d registration operators running in %s mode are found, they reconcile the same resources "+
+			"with their own version: %s. Remove all of them but one", len(operators), mode, strings.Join(descriptions, ", ")))
+	case len(operators) == 1 && len(c.OperatorName) > 0 &&
+		(operators[0].Namespace != c.OperatorNamespace || operators[0].Name != c.OperatorName):
+		errorList = append(errorList, fmt.Errorf("the registration operator %s is already running in %s mode, "+
+			"run clusteradm attach to manage it with clusteradm instead of installing another operator",
+			describeOperator(operators[0], mode), mode))
+	}
+
+	if len(operators) > 0 {
+		warningList = append(warningList, c.checkCRD(ctx, mode, operators[0])...)
+	}
+	return warningList, errorList
+}
+
+func (c ConflictCheck) Name() string {
+	return "ConflictingInstallations check"
+}
+
+// describeOperator returns the deployment of the operator, its installer and version
+func describeOperator(deployment appsv1.Deployment, mode string) string {
+	installer, release := installerOf(deployment.ObjectMeta)
+	installedBy := "installed by " + installer
+	if len(release) > 0 {
+		installedBy += " " + release
+	}
+	imageVersion := "unknown version"
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if isOperatorContainer(container, mode) {
+			if v := version.ImageVersion(container.Image); len(v) > 0 {
+				imageVersion = "version " + v
+			}
+		}
+	}
+	return fmt.Sprintf("%s/%s (%s, %s)", deployment.Namespace, deployment.Name, installedBy, imageVersion)
+}
+
+// checkCRD warns if the CRD of the mode is installed by another tool than the operator, the upgrades
+// of one of them change the CRD served to the other
+func (c ConflictCheck) checkCRD(ctx context.Context, mode string, operator appsv1.Deployment) []string {
+	if c.APIExtensionsClient == nil {
+		return nil
+	}
+	crd, err := c.APIExtensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crdNames[mode], metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	crdInstaller, crdRelease := installerOf(crd.ObjectMeta)
+	operatorInstaller, operatorRelease := installerOf(operator.ObjectMeta)
+	if crdInstaller == operatorInstaller && crdRelease == operatorRelease {
+		return nil
+	}
+	return []string{fmt.Sprintf("the CRD %s is installed by %s, the operator %s/%s by %s, the upgrades of one of them change the CRD of the other",
+		crd.Name, strings.TrimSpace(crdInstaller+" "+crdRelease), operator.Namespace, operator.Name,
+		strings.TrimSpace(operatorInstaller+" "+operatorRelease))}
+}
+
+// webhook is a webhook of OCM and the service serving it
+type webhook struct {
+	configuration string
+	name          string
+	service       *admissionregistrationv1.ServiceReference
+}
+
+// checkWebhooks reports the webhooks of OCM served by a service which does not exist, they are left by a
+// previous installation and fail the requests to the resources of OCM. The namespaces serving the webhooks
+// are reported if there are several of them.
+func checkWebhooks(ctx context.Context, kubeClient kubernetes.Interface) (warningList []string, errorList []error) {
+	webhooks := []webhook{}
+	validating, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return []string{fmt.Sprintf("failed to list the validating webhooks: %v", err)}, nil
+	}
+	for _, configuration := range validating.Items {
+		for _, w := range configuration.Webhooks {
+			webhooks = append(webhooks, webhook{configuration: "validatingwebhookconfiguration/" + configuration.Name, name: w.Name, service: w.ClientConfig.Service})
+		}
+	}
+	mutating, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return []string{fmt.Sprintf("failed to list the mutating webhooks: %v", err)}, nil
+	}
+	for _, configuration := range mutating.Items {
+		for _, w := range configuration.Webhooks {
+			webhooks = append(webhooks, webhook{configuration: "mutatingwebhookconfiguration/" + configuration.Name, name: w.Name, service: w.ClientConfig.Service})
+		}
+	}
+
+	owners := map[string][]string{}
+	for _, w := range webhooks {
+		if !strings.HasSuffix(w.name, ocmGroupSuffix) || w.service == nil {
+			continue
+		}
+		owners[w.service.Namespace] = append(owners[w.service.Namespace], w.name)
+		_, err := kubeClient.CoreV1().Services(w.service.Namespace).Get(ctx, w.service.Name, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			errorList = append(errorList, fmt.Errorf("the webhook %s of %s is served by service %s/%s which does not exist, "+
+				"it is left by a previous installation and fails the requests to the resources of OCM, delete it",
+				w.name, w.configuration, w.service.Namespace, w.service.Name))
+		case err != nil:
+			warningList = append(warningList, fmt.Sprintf("failed to get the service of webhook %s: %v", w.name, err))
+		}
+	}
+
+	if len(owners) > 1 {
+		namespaces := []string{}
+		for namespace := range owners {
+			namespaces = append(namespaces, namespace)
+		}
+		sort.Strings(namespaces)
+		descriptions := []string{}
+		for _, namespace := range namespaces {
+			descriptions = append(descriptions, fmt.Sprintf("%s (%s)", namespace, strings.Join(owners[namespace], ", ")))
+		}
+		warningList = append(warningList, fmt.Sprintf("the webhooks of OCM are served from several namespaces: %s",
+			strings.Join(descriptions, "; ")))
+	}
+	return warningList, errorList
+}
@@ -0,0 +1,113 @@
+// Copyright Contributors to the Open Cluster Management project
+package attach
+
+import (
+	"strings"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newWebhookConfiguration(name, webhookName, namespace, service string) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: webhookName,
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{Namespace: namespace, Name: service},
+			},
+		}},
+	}
+}
+
+func TestConflictCheck(t *testing.T) {
+	helmOperator := newOperator("ocm", "cluster-manager", ModeHub, nil, map[string]string{"meta.helm.sh/release-name": "ocm"})
+	ownOperator := newOperator("open-cluster-management", "cluster-manager", ModeHub, nil, nil)
+	cases := []struct {
+		name             string
+		objs             []runtime.Object
+		expectedWarnings []string
+		expectedErrors   []string
+	}{
+		{
+			name: "not installed",
+		},
+		{
+			name: "installed by clusteradm",
+			objs: []runtime.Object{ownOperator},
+		},
+		{
+			name:           "installed by helm",
+			objs:           []runtime.Object{helmOperator},
+			expectedErrors: []string{"ocm/cluster-manager (installed by Helm ocm, version v0.9.0) is already running in hub mode, run clusteradm attach"},
+		},
+		{
+			name:           "several operators",
+			objs:           []runtime.Object{helmOperator, ownOperator},
+			expectedErrors: []string{"2 registration operators running in hub mode are found"},
+		},
+		{
+			name: "stale webhook",
+			objs: []runtime.Object{
+				ownOperator,
+				newWebhookConfiguration("managedclustervalidators.admission.cluster.open-cluster-management.io",
+					"managedclustervalidators.admission.cluster.open-cluster-management.io", "open-cluster-management-hub", "cluster-manager-registration-webhook"),
+				newWebhookConfiguration("other", "pods.example.com", "other", "missing"),
+			},
+			expectedErrors: []string{"is served by service open-cluster-management-hub/cluster-manager-registration-webhook which does not exist"},
+		},
+		{
+			name: "webhooks of several namespaces",
+			objs: []runtime.Object{
+				ownOperator,
+				newWebhookConfiguration("a", "managedclustervalidators.admission.cluster.open-cluster-management.io", "open-cluster-management-hub", "webhook"),
+				newWebhookConfiguration("b", "manifestworkvalidators.admission.work.open-cluster-management.io", "ocm", "webhook"),
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "open-cluster-management-hub", Name: "webhook"}},
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ocm", Name: "webhook"}},
+			},
+			expectedWarnings: []string{"served from several namespaces: ocm (manifestworkvalidators.admission.work.open-cluster-management.io); open-cluster-management-hub"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			warnings, errs := ConflictCheck{
+				KubeClient:        kubefake.NewSimpleClientset(c.objs...),
+				Mode:              ModeHub,
+				OperatorNamespace: "open-cluster-management",
+				OperatorName:      "cluster-manager",
+			}.Check()
+			if len(warnings) != len(c.expectedWarnings) {
+				t.Fatalf("expected warnings %v, got %v", c.expectedWarnings, warnings)
+			}
+			for i, expected := range c.expectedWarnings {
+				if !strings.Contains(warnings[i], expected) {
+					t.Errorf("expected %q in %q", expected, warnings[i])
+				}
+			}
+			if len(errs) != len(c.expectedErrors) {
+				t.Fatalf("expected errors %v, got %v", c.expectedErrors, errs)
+			}
+			for i, expected := range c.expectedErrors {
+				if !strings.Contains(errs[i].Error(), expected) {
+					t.Errorf("expected %q in %q", expected, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConflictCheckAllModes(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset(
+		newOperator("open-cluster-management", "cluster-manager", ModeHub, nil, nil),
+		newOperator("open-cluster-management", "klusterlet", ModeKlusterlet, nil, nil),
+		newOperator("operators", "klusterlet", ModeKlusterlet, map[string]string{"olm.owner": "klusterlet.v0.9.0"}, nil),
+	)
+	_, errs := ConflictCheck{KubeClient: kubeClient}.Check()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "operators/klusterlet (installed by OLM klusterlet.v0.9.0, version v0.9.0)") {
+		t.Errorf("expected the conflicting klusterlet operators, got %v", errs)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package audit records the last clusteradm operation on the objects created, updated or patched by
+// clusteradm, so the changes made on the clusters by the CLI are attributable.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"open-cluster-management.io/clusteradm/pkg/helpers/incluster"
+)
+
+// Annotation is the annotation of the last clusteradm operation on an object
+const Annotation = "open-cluster-management.io/last-clusteradm-operation"
+
+// Operation is who ran which clusteradm command when
+type Operation struct {
+	// User is the kubeconfig user of the command, the service account of the pod in-cluster, the local
+	// user if it is not known, followed by the user it impersonates if any
+	User      string    `json:"user"`
+	Timestamp time.Time `json:"timestamp"`
+	// Command is the command path with the names of the flags set, their values are not recorded
+	// as they may be secrets, like the token of join
+	Command string `json:"command"`
+	Version string `json:"version"`
+}
+
+// current is the operation of the running command, nil if the objects are not annotated
+var current *Operation
+
+// Setup records the running command, the objects created, updated or patched afterwards by the
+// clients wrapped by WrapConfig are annotated with it.
+func Setup(cmd *cobra.Command, clientConfig clientcmd.ClientConfig, context, version string) {
+	current = &Operation{
+		User:      userOf(clientConfig, context),
+		Timestamp: time.Now().UTC(),
+		Command:   commandOf(cmd),
+		Version:   strings.TrimSpace(version),
+	}
+}
+
+// Current returns the operation of the running command, nil if no operation is set up
+func Current() *Operation {
+	return current
+}
+
+// Parse returns the operation of the annotations, nil if they have no operation
+func Parse(annotations map[string]string) (*Operation, error) {
+	value, ok := annotations[Annotation]
+	if !ok {
+		return nil, nil
+	}
+	op := &Operation{}
+	if err := json.Unmarshal([]byte(value), op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func commandOf(cmd *cobra.Command) string {
+	command := []string{cmd.CommandPath()}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		command = append(command, "--"+flag.Name)
+	})
+	return strings.Join(command, " ")
+}
+
+// userOf returns the user of the context of the kubeconfig, of its current context if empty, and the user
+// it impersonates with --as
+func userOf(clientConfig clientcmd.ClientConfig, context string) string {
+	user := kubeconfigUserOf(clientConfig, context)
+	if restConfig, err := clientConfig.ClientConfig(); err == nil && len(restConfig.Impersonate.UserName) > 0 {
+		return user + " as " + restConfig.Impersonate.UserName
+	}
+	return user
+}
+
+func kubeconfigUserOf(clientConfig clientcmd.ClientConfig, context string) string {
+	if rawConfig, err := clientConfig.RawConfig(); err == nil {
+		if len(context) == 0 {
+			context = rawConfig.CurrentContext
+		}
+		if kubeContext, ok := rawConfig.Contexts[context]; ok && len(kubeContext.AuthInfo) > 0 {
+			return kubeContext.AuthInfo
+		}
+	}
+	if incluster.Detect(clientConfig) {
+		if serviceAccount, err := incluster.ServiceAccount(); err == nil {
+			return serviceAccount
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// WrapConfig annotates the objects created, updated or patched with the rest config with the operation
+// of the running command. The subresources, e.g. status, are not annotated.
+func WrapConfig(config *rest.Config) *rest.Config {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{delegate: rt}
+	})
+	return config
+}
+
+type roundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	op := current
+	if op == nil || req.Body == nil || IsSubresource(req.URL.Path) {
+		return rt.delegate.RoundTrip(req)
+	}
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return rt.delegate.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if annotated, ok := annotate(body, op); ok {
+		body = annotated
+	}
+	// a round tripper must not modify the request
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	req.ContentLength = int64(len(body))
+	return rt.delegate.RoundTrip(req)
+}
+
+// annotate sets the annotation of the operation on the JSON object of the body, it returns false if
+// the body is not a JSON object, e.g. a protobuf object or a JSON patch
+func annotate(body []byte, op *Operation) ([]byte, bool) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, false
+	}
+	value, err := json.Marshal(op)
+	if err != nil {
+		return nil, false
+	}
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = map[string]interface{}{}
+		metadata["annotations"] = annotations
+	}
+	annotations[Annotation] = string(value)
+	annotated, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false
+	}
+	return annotated, true
+}
+
+// IsSubresource returns true if the path is not the path of a resource or of a collection of a resource,
+// e.g. /api/v1/namespaces/ns/pods/name/status
+func IsSubresource(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		parts = parts[3:]
+	default:
+		return true
+	}
+	// the resources of a namespace, the namespace itself has the status and finalize subresources
+	if len(parts) >= 3 && parts[0] == "namespaces" && parts[2] != "status" && parts[2] != "finalize" {
+		parts = parts[2:]
+	}
+	return len(parts) == 0 || len(parts) > 2
+}
@@ -0,0 +1,150 @@
+// Copyright Contributors to the Open Cluster Management project
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestIsSubresource(t *testing.T) {
+	cases := map[string]bool{
+		"/api/v1/namespaces":                                                          false,
+		"/api/v1/namespaces/cluster1":                                                 false,
+		"/api/v1/namespaces/cluster1/status":                                          true,
+		"/api/v1/namespaces/cluster1/finalize":                                        true,
+		"/api/v1/namespaces/cluster1/secrets":                                         false,
+		"/api/v1/namespaces/cluster1/secrets/token":                                   false,
+		"/api/v1/namespaces/cluster1/serviceaccounts/sa/token":                        true,
+		"/apis/cluster.open-cluster-management.io/v1/managedclusters/cluster1":        false,
+		"/apis/cluster.open-cluster-management.io/v1/managedclusters/cluster1/status": true,
+		"/apis/certificates.k8s.io/v1/certificatesigningrequests/csr1/approval":       true,
+		"/healthz": true,
+	}
+	for path, expected := range cases {
+		if got := IsSubresource(path); got != expected {
+			t.Errorf("expected %v for %s, got %v", expected, path, got)
+		}
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	op := &Operation{User: "admin", Timestamp: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Command: "clusteradm accept --clusters", Version: "v0.6.0"}
+	cases := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{name: "object", body: `{"kind":"Namespace","metadata":{"name":"ns","annotations":{"a":"b"}}}`, expected: true},
+		{name: "merge patch", body: `{"spec":{"hubAcceptsClient":true}}`, expected: true},
+		{name: "json patch", body: `[{"op":"add","path":"/spec/taints","value":[]}]`, expected: false},
+		{name: "not json", body: "\x6b\x38\x73\x00", expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			annotated, ok := annotate([]byte(c.body), op)
+			if ok != c.expected {
+				t.Fatalf("expected %v, got %v", c.expected, ok)
+			}
+			if !ok {
+				return
+			}
+			obj := struct {
+				Metadata struct {
+					Annotations map[string]string `json:"annotations"`
+				} `json:"metadata"`
+			}{}
+			if err := json.Unmarshal(annotated, &obj); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(obj.Metadata.Annotations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != *op {
+				t.Errorf("expected %+v, got %+v", op, got)
+			}
+		})
+	}
+}
+
+func TestWrapConfig(t *testing.T) {
+	defer func() { current = nil }()
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	post := func() {
+		rt, err := rest.TransportFor(WrapConfig(&rest.Config{Host: server.URL}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/namespaces", bytes.NewBufferString(`{"metadata":{"name":"ns"}}`))
+		if _, err := (&http.Client{Transport: rt}).Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	post()
+	if bytes.Contains(received, []byte(Annotation)) {
+		t.Errorf("expected no annotation without operation, got %s", received)
+	}
+
+	cmd := &cobra.Command{Use: "accept"}
+	cmd.Flags().String("clusters", "", "")
+	cmd.Flags().Bool("wait", false, "")
+	if err := cmd.Flags().Set("clusters", "cluster1"); err != nil {
+		t.Fatal(err)
+	}
+	clientConfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{
+		CurrentContext: "hub",
+		Contexts:       map[string]*clientcmdapi.Context{"hub": {AuthInfo: "hub-admin"}},
+	}, nil)
+	Setup(cmd, clientConfig, "", "v0.6.0\n")
+
+	post()
+	obj := struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(received, &obj); err != nil {
+		t.Fatal(err)
+	}
+	op, err := Parse(obj.Metadata.Annotations)
+	if err != nil || op == nil {
+		t.Fatalf("expected an operation, got %v %v", op, err)
+	}
+	if op.User != "hub-admin" || op.Command != "accept --clusters" || op.Version != "v0.6.0" {
+		t.Errorf("unexpected operation %+v", op)
+	}
+}
+
+func TestUserOf(t *testing.T) {
+	config := clientcmdapi.Config{
+		CurrentContext: "hub",
+		Clusters:       map[string]*clientcmdapi.Cluster{"hub": {Server: "https://hub:6443"}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"hub-admin": {Token: "token"}},
+		Contexts:       map[string]*clientcmdapi.Context{"hub": {Cluster: "hub", AuthInfo: "hub-admin"}},
+	}
+	if user := userOf(clientcmd.NewDefaultClientConfig(config, &clientcmd.ConfigOverrides{}), ""); user != "hub-admin" {
+		t.Errorf("expected hub-admin, got %s", user)
+	}
+	impersonated := clientcmd.NewDefaultClientConfig(config, &clientcmd.ConfigOverrides{
+		AuthInfo: clientcmdapi.AuthInfo{Impersonate: "alice"},
+	})
+	if user := userOf(impersonated, ""); user != "hub-admin as alice" {
+		t.Errorf("expected hub-admin as alice, got %s", user)
+	}
+}
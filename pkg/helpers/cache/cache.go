@@ -0,0 +1,95 @@
+// Copyright Contributors to the Open Cluster Management project
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultTTL is the time an entry is valid, it is the same as the discovery cache
+const DefaultTTL = 10 * time.Minute
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Cache stores metadata of the hubs on disk between invocations, the entries are
+// keyed by the hub identity, e.g. its apiserver URL. A nil Cache is disabled.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+type entry struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// New returns a cache storing its entries in the hubs sub-directory of dir, next to the discovery cache,
+// it returns nil if the cache is disabled or dir is empty.
+func New(dir string, disabled bool) *Cache {
+	if disabled || len(dir) == 0 {
+		return nil
+	}
+	return &Cache{
+		dir: filepath.Join(dir, "hubs"),
+		ttl: DefaultTTL,
+		now: time.Now,
+	}
+}
+
+func (c *Cache) path(hub, key string) string {
+	return filepath.Join(c.dir, unsafeChars.ReplaceAllString(hub, "_"), unsafeChars.ReplaceAllString(key, "_")+".json")
+}
+
+// Get reads the entry of the hub into value, it returns false if the entry is missing or expired
+func (c *Cache) Get(hub, key string, value interface{}) bool {
+	if c == nil {
+		return false
+	}
+	data, err := os.ReadFile(c.path(hub, key))
+	if err != nil {
+		return false
+	}
+	e := &entry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		klog.V(3).InfoS("ignoring the invalid cache entry", "hub", hub, "key", key, "error", err)
+		return false
+	}
+	if c.now().Sub(e.Timestamp) > c.ttl {
+		return false
+	}
+	if err := json.Unmarshal(e.Value, value); err != nil {
+		return false
+	}
+	klog.V(3).InfoS("cache hit", "hub", hub, "key", key)
+	return true
+}
+
+// Set writes the entry of the hub, the failures are only logged as the cache is best effort
+func (c *Cache) Set(hub, key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		klog.V(3).InfoS("failed to marshal the cache entry", "hub", hub, "key", key, "error", err)
+		return
+	}
+	data, err := json.Marshal(&entry{Timestamp: c.now(), Value: raw})
+	if err != nil {
+		return
+	}
+	path := c.path(hub, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		klog.V(3).InfoS("failed to create the cache directory", "path", path, "error", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		klog.V(3).InfoS("failed to write the cache entry", "path", path, "error", err)
+	}
+}
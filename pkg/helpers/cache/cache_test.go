@@ -0,0 +1,42 @@
+// Copyright Contributors to the Open Cluster Management project
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	c := New(t.TempDir(), false)
+	c.now = func() time.Time { return now }
+
+	value := ""
+	if c.Get("https://hub:6443", "ca", &value) {
+		t.Fatalf("expected a miss on an empty cache")
+	}
+	c.Set("https://hub:6443", "ca", "data")
+	if !c.Get("https://hub:6443", "ca", &value) || value != "data" {
+		t.Fatalf("expected a hit with data, got %q", value)
+	}
+	if c.Get("https://other:6443", "ca", &value) {
+		t.Errorf("expected the entries to be keyed by hub")
+	}
+
+	now = now.Add(DefaultTTL + time.Second)
+	if c.Get("https://hub:6443", "ca", &value) {
+		t.Errorf("expected the entry to be expired")
+	}
+}
+
+func TestDisabledCache(t *testing.T) {
+	c := New(t.TempDir(), true)
+	if c != nil {
+		t.Fatalf("expected a nil cache when disabled")
+	}
+	value := ""
+	c.Set("hub", "ca", "data")
+	if c.Get("hub", "ca", &value) {
+		t.Errorf("expected a miss on a disabled cache")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package cahash pins the CA of the hub with the sha256 hash of its public key (SPKI), in the
+// sha256:<hex> format of kubeadm, so the joining clusters do not trust the CA on first use.
+package cahash
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const prefix = "sha256:"
+
+// Hash returns the sha256:<hex> hash of the public key of the certificate
+func Hash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return prefix + hex.EncodeToString(sum[:])
+}
+
+// Validate checks the hashes are in the sha256:<hex> format
+func Validate(hashes []string) error {
+	for _, hash := range hashes {
+		value := strings.TrimPrefix(hash, prefix)
+		if value == hash {
+			return fmt.Errorf("the CA hash %s should start with %s", hash, prefix)
+		}
+		if decoded, err := hex.DecodeString(value); err != nil || len(decoded) != sha256.Size {
+			return fmt.Errorf("the CA hash %s should be %s followed by %d hex characters", hash, prefix, 2*sha256.Size)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the hash of the public key of the certificate is one of the hashes
+func Matches(cert *x509.Certificate, hashes []string) bool {
+	hash := Hash(cert)
+	for _, h := range hashes {
+		if strings.EqualFold(h, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// HashesOfPEM returns the hashes of the certificates of the PEM bundle
+func HashesOfPEM(caPEM []byte) ([]string, error) {
+	hashes := []string{}
+	for rest := caPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, Hash(cert))
+	}
+	return hashes, nil
+}
+
+// VerifyPEM checks a certificate of the PEM bundle matches one of the hashes
+func VerifyPEM(caPEM []byte, hashes []string) error {
+	caHashes, err := HashesOfPEM(caPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse the CA: %v", err)
+	}
+	if len(caHashes) == 0 {
+		return fmt.Errorf("no CA is found to verify against the CA hashes")
+	}
+	for _, caHash := range caHashes {
+		for _, hash := range hashes {
+			if strings.EqualFold(caHash, hash) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("the CA with hashes %s does not match the CA hashes %s",
+		strings.Join(caHashes, ","), strings.Join(hashes, ","))
+}
+
+// FetchPinnedCA returns the PEM of the CA of the server whose public key matches one of the hashes.
+// The serving certificate of the server must be signed by the pinned CA, so the CA must be in the
+// certificates presented by the server.
+func FetchPinnedCA(server string, hashes []string, timeout time.Duration) ([]byte, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if len(u.Port()) == 0 {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	var ca *x509.Certificate
+	config := &tls.Config{
+		// the certificates are verified against the pinned CA below instead of the system roots
+		// nolint:gosec
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			certs := []*x509.Certificate{}
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs = append(certs, cert)
+			}
+			if len(certs) == 0 {
+				return fmt.Errorf("the server presented no certificate")
+			}
+			ca, err = verifyPinned(certs, hashes, u.Hostname())
+			return err
+		},
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", host, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the CA of %s pinned by its hash: %v", server, err)
+	}
+	defer conn.Close()
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), nil
+}
+
+// verifyPinned returns the pinned CA among the certificates presented by the server, once the serving
+// certificate is verified against it
+func verifyPinned(certs []*x509.Certificate, hashes []string, hostname string) (*x509.Certificate, error) {
+	var ca *x509.Certificate
+	for _, cert := range certs {
+		if Matches(cert, hashes) {
+			ca = cert
+			break
+		}
+	}
+	if ca == nil {
+		return nil, fmt.Errorf("no certificate presented by the server matches the CA hashes, " +
+			"the server may not present its CA, use --ca-file instead")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       hostname,
+		Roots:         roots,
+		Intermediates: intermediates,
+	}); err != nil {
+		return nil, fmt.Errorf("the serving certificate is not signed by the pinned CA: %v", err)
+	}
+	return ca, nil
+}
@@ -0,0 +1,66 @@
+// Copyright Contributors to the Open Cluster Management project
+package cahash
+
+import (
+	"bytes"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	valid := prefix + strings.Repeat("ab", 32)
+	if err := Validate([]string{valid}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, hash := range []string{strings.Repeat("ab", 32), prefix + "abc", prefix + strings.Repeat("zz", 32)} {
+		if err := Validate([]string{hash}); err == nil {
+			t.Errorf("expected an error for %s", hash)
+		}
+	}
+}
+
+func TestFetchPinnedCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	cert := server.Certificate()
+
+	ca, err := FetchPinnedCA(server.URL, []string{Hash(cert)}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if !bytes.Equal(ca, expected) {
+		t.Errorf("expected the certificate of the server, got %s", ca)
+	}
+
+	if _, err := FetchPinnedCA(server.URL, []string{prefix + strings.Repeat("ab", 32)}, 5*time.Second); err == nil {
+		t.Errorf("expected an error for an unpinned CA")
+	}
+}
+
+func TestVerifyPEM(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	hashes, err := HashesOfPEM(caPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 1 || hashes[0] != Hash(server.Certificate()) {
+		t.Errorf("expected the hash of the certificate, got %v", hashes)
+	}
+	if err := VerifyPEM(caPEM, []string{strings.ToUpper(hashes[0][len(prefix):])}); err == nil {
+		t.Errorf("expected an error for a hash without prefix")
+	}
+	if err := VerifyPEM(caPEM, []string{prefix + strings.Repeat("ab", 32), hashes[0]}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := VerifyPEM(nil, hashes); err == nil {
+		t.Errorf("expected an error without CA")
+	}
+}
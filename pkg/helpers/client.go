@@ -5,9 +5,11 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/ghodss/yaml"
+	"go.opentelemetry.io/otel/attribute"
 	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionshelpers "k8s.io/apiextensions-apiserver/pkg/apihelpers"
@@ -30,6 +32,11 @@ import (
 	"k8s.io/client-go/util/retry"
 	"k8s.io/kubectl/pkg/cmd/util"
 	"open-cluster-management.io/clusteradm/pkg/config"
+	"open-cluster-management.io/clusteradm/pkg/helpers/cahash"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clierror"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/restconfig"
+	"open-cluster-management.io/clusteradm/pkg/helpers/tracing"
 )
 
 type TokenType string
@@ -45,6 +52,9 @@ func GetClients(f util.Factory) (
 	apiExtensionsClient apiextensionsclient.Interface,
 	dynamicClient dynamic.Interface,
 	err error) {
+	_, span := tracing.Start(context.TODO(), "GetClients")
+	defer func() { tracing.End(span, err) }()
+
 	kubeClient, err = f.KubernetesClientSet()
 	if err != nil {
 		return
@@ -108,8 +118,42 @@ func GetCACert(kubeClient kubernetes.Interface) ([]byte, error) {
 	return nil, err
 }
 
+// GetHubCA returns the ca of the hub published in the cluster-info, or the ca of the kubeconfig
+// if the hub does not publish it.
+func GetHubCA(kubeClient kubernetes.Interface, restConfig *rest.Config) ([]byte, error) {
+	caData, err := GetCACert(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	if len(caData) == 0 {
+		caData = restConfig.CAData
+	}
+	if len(caData) == 0 && len(restConfig.CAFile) > 0 {
+		if caData, err = os.ReadFile(restConfig.CAFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(caData) == 0 {
+		return nil, fmt.Errorf("failed to find the ca of the hub in the cluster-info or the kubeconfig")
+	}
+	return caData, nil
+}
+
+// GetCAHashes returns the hashes of the CA returned by GetCACert, the joining clusters verify the CA they
+// read from the hub against them. It returns no hash if the hub does not publish its CA.
+func GetCAHashes(kubeClient kubernetes.Interface) ([]string, error) {
+	ca, err := GetCACert(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	return cahash.HashesOfPEM(ca)
+}
+
 func getClusterInfoKubeConfig(kubeClient kubernetes.Interface) (*clientcmdapiv1.Config, error) {
 	cm, err := kubeClient.CoreV1().ConfigMaps("kube-public").Get(context.TODO(), "cluster-info", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, clierror.New(clierror.CodeClusterInfoMissing, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +167,7 @@ func getClusterInfoKubeConfig(kubeClient kubernetes.Interface) (*clientcmdapiv1.
 
 // WaitCRDToBeReady waits if a crd is ready
 func WaitCRDToBeReady(apiExtensionsClient apiextensionsclient.Interface, name string, b wait.Backoff, wait bool) error {
+	_, span := tracing.Start(context.TODO(), "WaitCRDToBeReady", attribute.String("crd", name))
 	errGet := retry.OnError(b, func(err error) bool {
 		if err != nil {
 			if wait {
@@ -145,6 +190,7 @@ func WaitCRDToBeReady(apiExtensionsClient apiextensionsclient.Interface, name st
 
 		return err
 	})
+	tracing.End(span, errGet)
 	return errGet
 }
 
@@ -152,7 +198,11 @@ func WaitCRDToBeReady(apiExtensionsClient apiextensionsclient.Interface, name st
 // It searches first for the service-account token and then if it is not found
 // it looks for the bootstrap token in kube-system.
 func GetToken(ctx context.Context, kubeClient kubernetes.Interface) (string, TokenType, error) {
-	token, err := GetBootstrapTokenFromSA(ctx, kubeClient)
+	namespace, err := GetBootstrapSANamespace(ctx, kubeClient)
+	if err != nil {
+		return "", UnknownToken, err
+	}
+	token, err := GetBootstrapTokenFromSA(ctx, kubeClient, namespace)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			//As no SA search for bootstrap token
@@ -200,10 +250,29 @@ func GetBootstrapToken(ctx context.Context, kubeClient kubernetes.Interface) (st
 	return fmt.Sprintf("%s.%s", string(bootstrapSecret.Data["token-id"]), string(bootstrapSecret.Data["token-secret"])), nil
 }
 
+// GetBootstrapSANamespace returns the namespace of the bootstrap service-account.
+// The namespace is read from the subject of the bootstrap cluster role binding,
+// it defaults to open-cluster-management if the binding is not found.
+func GetBootstrapSANamespace(ctx context.Context, kubeClient kubernetes.Interface) (string, error) {
+	crb, err := kubeClient.RbacV1().ClusterRoleBindings().Get(ctx, config.BootstrapClusterRoleBindingSAName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return config.OpenClusterManagementNamespace, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, subject := range crb.Subjects {
+		if subject.Kind == "ServiceAccount" && subject.Name == config.BootstrapSAName && len(subject.Namespace) > 0 {
+			return subject.Namespace, nil
+		}
+	}
+	return config.OpenClusterManagementNamespace, nil
+}
+
 // GetBootstrapSecretFromSA retrieves the service-account token secret
-func GetBootstrapTokenFromSA(ctx context.Context, kubeClient kubernetes.Interface) (string, error) {
+func GetBootstrapTokenFromSA(ctx context.Context, kubeClient kubernetes.Interface, namespace string) (string, error) {
 	tr, err := kubeClient.CoreV1().
-		ServiceAccounts(config.OpenClusterManagementNamespace).
+		ServiceAccounts(namespace).
 		CreateToken(ctx, config.BootstrapSAName, &authv1.TokenRequest{
 			Spec: authv1.TokenRequestSpec{
 				// token expired in 1 hour
@@ -211,7 +280,7 @@ func GetBootstrapTokenFromSA(ctx context.Context, kubeClient kubernetes.Interfac
 			},
 		}, metav1.CreateOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to get token from sa %s/%s: %v", config.OpenClusterManagementNamespace, config.BootstrapSAName, err)
+		return "", fmt.Errorf("failed to get token from sa %s/%s: %v", namespace, config.BootstrapSAName, err)
 	}
 	return tr.Status.Token, nil
 }
@@ -253,7 +322,10 @@ func IsKlusterletsInstalled(apiExtensionsClient apiextensionsclient.Interface) (
 // WatchUntil starts a watch stream and holds until the condition is satisfied.
 func WatchUntil(
 	watchFunc func() (watch.Interface, error),
-	assertEvent func(event watch.Event) bool) error {
+	assertEvent func(event watch.Event) bool) (err error) {
+	_, span := tracing.Start(context.TODO(), "WatchUntil")
+	defer func() { tracing.End(span, err) }()
+
 	w, err := watchFunc()
 	if err != nil {
 		return err
@@ -262,7 +334,7 @@ func WatchUntil(
 	for {
 		event, ok := <-w.ResultChan()
 		if !ok { //The channel is closed by Kubernetes, thus, user should check the pod status manually
-			return fmt.Errorf("unexpected watch event received")
+			return exit.Timeout(fmt.Errorf("unexpected watch event received"))
 		}
 
 		if assertEvent(event) {
@@ -289,7 +361,7 @@ func CreateRESTConfigFromClientcmdapiv1Config(clientcmdapiv1Config clientcmdapiv
 		return nil, err
 	}
 
-	return restConfig, nil
+	return restconfig.Wrap(restConfig), nil
 }
 
 // CreateClientFromClientcmdapiv1Config
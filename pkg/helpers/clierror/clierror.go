@@ -0,0 +1,118 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package clierror maps the common failures to stable error codes with a remediation, printed uniformly by
+// clusteradm instead of the raw API errors. The commands and helpers return a typed error with New when they
+// know the failure, the other errors are classified from the API status when the command exits.
+package clierror
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"open-cluster-management.io/clusteradm/pkg/helpers/i18n"
+)
+
+// Code is the stable code of a failure, the automation may rely on it
+type Code string
+
+const (
+	// CodeTokenExpired is returned when the token or the credentials are expired or invalid
+	CodeTokenExpired Code = "TokenExpired"
+	// CodeClusterInfoMissing is returned when the hub does not publish its cluster-info ConfigMap
+	CodeClusterInfoMissing Code = "ClusterInfoMissing"
+	// CodeCRDVersionConflict is returned when the CRDs installed are of another version than the one expected
+	CodeCRDVersionConflict Code = "CRDVersionConflict"
+	// CodeWebhookUnreachable is returned when an admission webhook does not answer
+	CodeWebhookUnreachable Code = "WebhookUnreachable"
+	// CodeReadOnly is returned when a change is refused by --read-only
+	CodeReadOnly Code = "ReadOnly"
+)
+
+// Error is an error with a stable code and the remediation of the failure
+type Error struct {
+	Code Code
+	Err  error
+	// args are the arguments of the remediation message
+	args []interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Remediation returns how to fix the failure, in the language of the command. The cli is the command line
+// of clusteradm the remediation refers to, e.g. clusteradm or kubectl cm.
+func (e *Error) Remediation(cli string) string {
+	return strings.TrimSpace(i18n.T("error."+string(e.Code), append([]interface{}{cli}, e.args...)...))
+}
+
+// New returns the error with the code, an error already typed keeps its code
+func New(code Code, err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	typed := &Error{}
+	if errors.As(err, &typed) {
+		return err
+	}
+	return &Error{Code: code, Err: err, args: args}
+}
+
+var webhookPattern = regexp.MustCompile(`failed calling webhook "([^"]+)"`)
+
+// Classify returns the error typed with the code of the failure recognized from its API status or message,
+// or the error itself if it is already typed or not recognized
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	typed := &Error{}
+	if errors.As(err, &typed) {
+		return err
+	}
+	switch {
+	case apierrors.IsUnauthorized(err):
+		return New(CodeTokenExpired, err)
+	case apierrors.IsNotFound(err) && isClusterInfo(err):
+		return New(CodeClusterInfoMissing, err)
+	case meta.IsNoMatchError(err) || strings.Contains(err.Error(), "no matches for kind"):
+		return New(CodeCRDVersionConflict, err)
+	case apierrors.IsInvalid(err) && strings.Contains(err.Error(), "storedVersions"):
+		return New(CodeCRDVersionConflict, err)
+	}
+	if match := webhookPattern.FindStringSubmatch(err.Error()); match != nil {
+		return New(CodeWebhookUnreachable, err, match[1])
+	}
+	return err
+}
+
+// isClusterInfo returns true if the error is about the cluster-info ConfigMap
+func isClusterInfo(err error) bool {
+	status, ok := err.(apierrors.APIStatus)
+	if !ok && !errors.As(err, &status) {
+		return false
+	}
+	details := status.Status().Details
+	return details != nil && details.Name == "cluster-info" && details.Kind == "configmaps"
+}
+
+// Print writes the error with its code and remediation if it is typed. The cli is the command line of
+// clusteradm the remediation refers to.
+func Print(w io.Writer, err error, cli string) {
+	fmt.Fprintf(w, "Error: %v\n", err)
+	typed := &Error{}
+	if !errors.As(err, &typed) {
+		return
+	}
+	fmt.Fprintf(w, "  Code: %s\n", typed.Code)
+	fmt.Fprintf(w, "  Remediation: %s\n", typed.Remediation(cli))
+}
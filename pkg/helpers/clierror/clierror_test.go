@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+package clierror
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{name: "unauthorized", err: apierrors.NewUnauthorized("Unauthorized"), expected: CodeTokenExpired},
+		{
+			name:     "cluster-info missing",
+			err:      fmt.Errorf("failed to read the hub: %w", apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "cluster-info")),
+			expected: CodeClusterInfoMissing,
+		},
+		{name: "other configmap missing", err: apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "other")},
+		{
+			name:     "no kind match",
+			err:      &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "operator.open-cluster-management.io", Kind: "Klusterlet"}, SearchedVersions: []string{"v1"}},
+			expected: CodeCRDVersionConflict,
+		},
+		{
+			name: "stored versions",
+			err: apierrors.NewInvalid(schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}, "klusterlets.operator.open-cluster-management.io",
+				field.ErrorList{field.Invalid(field.NewPath("status", "storedVersions"), []string{"v1alpha1"}, "must appear in spec.versions")}),
+			expected: CodeCRDVersionConflict,
+		},
+		{
+			name: "webhook unreachable",
+			err: apierrors.NewInternalError(fmt.Errorf(`failed calling webhook "managedclustervalidators.admission.cluster.open-cluster-management.io": ` +
+				`Post "https://cluster-manager-registration-webhook.open-cluster-management-hub.svc:9443/": connection refused`)),
+			expected: CodeWebhookUnreachable,
+		},
+		{name: "generic", err: fmt.Errorf("failed")},
+		{name: "already typed", err: New(CodeClusterInfoMissing, apierrors.NewUnauthorized("Unauthorized")), expected: CodeClusterInfoMissing},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := Classify(c.err)
+			typed, ok := err.(*Error)
+			switch {
+			case len(c.expected) == 0 && ok:
+				t.Errorf("expected an untyped error, got %s", typed.Code)
+			case len(c.expected) > 0 && !ok:
+				t.Errorf("expected the code %s, got an untyped error %v", c.expected, err)
+			case ok && typed.Code != c.expected:
+				t.Errorf("expected the code %s, got %s", c.expected, typed.Code)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := Classify(exit.Timeout(apierrors.NewInternalError(fmt.Errorf(`failed calling webhook "manifestworkvalidators": context deadline exceeded`))))
+	Print(out, err, "clusteradm")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "Error: Internal error occurred") || lines[1] != "  Code: WebhookUnreachable" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	if !strings.Contains(lines[2], "webhook manifestworkvalidators does not answer") || strings.Contains(lines[2], "%!") {
+		t.Errorf("unexpected remediation %q", lines[2])
+	}
+	if exit.CodeOf(err) != exit.CodeTimeout {
+		t.Errorf("expected the exit code to be kept, got %d", exit.CodeOf(err))
+	}
+
+	out.Reset()
+	Print(out, New(CodeTokenExpired, fmt.Errorf("Unauthorized")), "kubectl cm")
+	if !strings.Contains(out.String(), "'kubectl cm get token'") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	Print(out, fmt.Errorf("failed"), "clusteradm")
+	if out.String() != "Error: failed\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
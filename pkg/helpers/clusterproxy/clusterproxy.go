@@ -0,0 +1,209 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package clusterproxy connects to the managed clusters through the konnectivity
+// tunnels of the cluster-proxy addon.
+package clusterproxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	grpccredentials "google.golang.org/grpc/credentials"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8snet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
+	proxyv1alpha1 "open-cluster-management.io/cluster-proxy/pkg/apis/proxy/v1alpha1"
+	"open-cluster-management.io/cluster-proxy/pkg/common"
+	clusterproxyclient "open-cluster-management.io/cluster-proxy/pkg/generated/clientset/versioned"
+	"open-cluster-management.io/cluster-proxy/pkg/util"
+	"open-cluster-management.io/clusteradm/pkg/helpers/restconfig"
+	msaclient "open-cluster-management.io/managed-serviceaccount/pkg/generated/clientset/versioned"
+	konnectivity "sigs.k8s.io/apiserver-network-proxy/konnectivity-client/pkg/client"
+)
+
+const (
+	// AddonName is the name of the cluster-proxy addon
+	AddonName = common.AddonName
+	// DefaultProxyServerPort is the local port forwarded to the proxy servers
+	DefaultProxyServerPort = 8090
+
+	secretProxyCA     = "proxy-server-ca"
+	secretProxyClient = "proxy-client"
+)
+
+// ErrNotInstalled is returned when the cluster-proxy addon is not installed on the hub
+var ErrNotInstalled = fmt.Errorf("cluster-proxy addon is not installed, consider following the guide: " +
+	"https://open-cluster-management.io/getting-started/integration/cluster-proxy/")
+
+// GetProxyConfig returns the configuration of the cluster-proxy addon
+func GetProxyConfig(ctx context.Context, hubRestConfig *rest.Config) (*proxyv1alpha1.ManagedProxyConfiguration, error) {
+	addonClient, err := addonv1alpha1client.NewForConfig(hubRestConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed initializing addon api client")
+	}
+	clusterAddon, err := addonClient.AddonV1alpha1().ClusterManagementAddOns().Get(ctx, AddonName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrNotInstalled
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed checking cluster management addon for cluster-proxy")
+	}
+
+	proxyClient, err := clusterproxyclient.NewForConfig(hubRestConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed initializing proxy api client")
+	}
+	// TODO: fix this deprecated field AddOnConfiguration
+	// nolint:staticcheck
+	proxyConfig, err := proxyClient.ProxyV1alpha1().ManagedProxyConfigurations().
+		Get(ctx, clusterAddon.Spec.AddOnConfiguration.CRName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting managedproxyconfiguration for cluster-proxy")
+	}
+	return proxyConfig, nil
+}
+
+// ClientTLSConfig builds the tls config of the konnectivity client from the in-cluster proxy client secrets
+func ClientTLSConfig(ctx context.Context, kubeClient kubernetes.Interface, proxyConfig *proxyv1alpha1.ManagedProxyConfiguration, serverName string) (*tls.Config, error) {
+	namespace := proxyConfig.Spec.ProxyServer.Namespace
+	caSecret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, secretProxyCA, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting CA secret")
+	}
+	certSecret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, secretProxyClient, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting cert & key secret")
+	}
+
+	certPool := x509.NewCertPool()
+	certPool.AppendCertsFromPEM(caSecret.Data["ca.crt"])
+	cert, err := tls.X509KeyPair(certSecret.Data["tls.crt"], certSecret.Data["tls.key"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed building TLS config from secret")
+	}
+	return &tls.Config{
+		RootCAs:      certPool,
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ServerName:   serverName,
+	}, nil
+}
+
+// ManagedServiceAccountToken returns the token of the managed service account in the cluster namespace
+func ManagedServiceAccountToken(ctx context.Context, hubRestConfig *rest.Config, name, cluster string) (string, error) {
+	msaClient, err := msaclient.NewForConfig(hubRestConfig)
+	if err != nil {
+		return "", err
+	}
+	msa, err := msaClient.Authentication().ManagedServiceAccounts(cluster).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if msa.Status.TokenSecretRef == nil {
+		return "", errors.Errorf("the token of managed service account %s is not ready", name)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(hubRestConfig)
+	if err != nil {
+		return "", err
+	}
+	secret, err := kubeClient.CoreV1().Secrets(cluster).Get(ctx, msa.Status.TokenSecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	token, ok := secret.Data["token"]
+	if !ok {
+		return "", errors.Errorf("token is not found in secret %s", secret.Name)
+	}
+	return string(token), nil
+}
+
+// Dial port-forwards the local port to the proxy servers and returns a dial function opening a
+// single-use konnectivity tunnel per connection, the returned function stops the port-forward.
+func Dial(ctx context.Context, hubRestConfig *rest.Config, proxyConfig *proxyv1alpha1.ManagedProxyConfiguration, port int) (k8snet.DialFunc, func(), error) {
+	kubeClient, err := kubernetes.NewForConfig(hubRestConfig)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed building cilent")
+	}
+	tlsCfg, err := ClientTLSConfig(ctx, kubeClient, proxyConfig, "127.0.0.1")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	readiness := &atomic.Value{}
+	readiness.Store(true)
+	localProxy := util.NewRoundRobinLocalProxy(
+		hubRestConfig,
+		readiness,
+		proxyConfig.Spec.ProxyServer.Namespace,
+		common.LabelKeyComponentName+"="+common.ComponentNameProxyServer,
+		int32(port),
+	)
+	closeFn, err := localProxy.Listen(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed listening local proxy")
+	}
+
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		tunnel, err := konnectivity.CreateSingleUseGrpcTunnel(
+			ctx,
+			net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+			grpc.WithTransportCredentials(grpccredentials.NewTLS(tlsCfg)),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed starting konnectivity proxy")
+		}
+		return tunnel.DialContext(ctx, network, address)
+	}
+	return dial, closeFn, nil
+}
+
+// ManagedClusterConfig returns the config of the kube-apiserver of the managed cluster reached through the tunnel.
+// The tunnel routes on the name of the managed cluster, which the apiserver certificate does not hold in its
+// SAN, so the server certificate is not verified.
+func ManagedClusterConfig(cluster, token string, dial k8snet.DialFunc) *rest.Config {
+	return restconfig.Wrap(&rest.Config{
+		Host:            fmt.Sprintf("https://%s", cluster),
+		BearerToken:     token,
+		Dial:            dial,
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	})
+}
+
+// Probe requests the healthz endpoint of the managed cluster through the tunnel and returns the round-trip latency
+func Probe(ctx context.Context, cluster string, dial k8snet.DialFunc) (time.Duration, error) {
+	rt, err := rest.TransportFor(ManagedClusterConfig(cluster, "", dial))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed creating roundtripper for cluster %v", cluster)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/healthz", cluster), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed requesting /healthz endpoint for cluster %v", cluster)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	latency := time.Since(start)
+	if string(data) != "ok" {
+		return 0, errors.Errorf("the /healthz endpoint of cluster %v returned %q", cluster, string(data))
+	}
+	return latency, nil
+}
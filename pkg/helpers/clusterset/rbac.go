@@ -0,0 +1,256 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// RoleAdmin allows to manage the clusterset, to add clusters to it and to bind it to namespaces
+	RoleAdmin = "admin"
+	// RoleBind allows to bind the clusterset to namespaces
+	RoleBind = "bind"
+	// RoleView allows to read the clusterset
+	RoleView = "view"
+
+	// ClusterSetLabel is the label of the managed cluster holding its clusterset
+	ClusterSetLabel = clusterv1beta1.ClusterSetLabel
+
+	// clusterRolePrefix prefixes the names of the clusterset cluster roles, it is used to list them
+	clusterRolePrefix = "open-cluster-management:managedclusterset:"
+	// labelClusterSet and labelRole are set on the cluster roles and bindings created for a clusterset
+	labelClusterSet = "clusteradm.open-cluster-management.io/clusterset"
+	labelRole       = "clusteradm.open-cluster-management.io/clusterset-role"
+)
+
+// Roles are the supported clusterset roles
+var Roles = []string{RoleAdmin, RoleBind, RoleView}
+
+// ClusterRoleName returns the name of the cluster role, and of its binding, granting the role on the clusterset
+func ClusterRoleName(clusterSet, role string) string {
+	return fmt.Sprintf("%s%s:%s", clusterRolePrefix, role, clusterSet)
+}
+
+// ClusterRole returns the cluster role granting the role on the clusterset, following the
+// OCM RBAC model: binding a clusterset requires create on managedclustersets/bind and adding
+// a cluster to a clusterset requires create on managedclustersets/join.
+func ClusterRole(clusterSet, role string) (*rbacv1.ClusterRole, error) {
+	view := rbacv1.PolicyRule{
+		APIGroups:     []string{clusterv1beta1.GroupName},
+		Resources:     []string{"managedclustersets"},
+		ResourceNames: []string{clusterSet},
+		Verbs:         []string{"get", "watch"},
+	}
+	var rules []rbacv1.PolicyRule
+	switch role {
+	case RoleAdmin:
+		view.Verbs = []string{"get", "watch", "update", "patch", "delete"}
+		rules = []rbacv1.PolicyRule{view, {
+			APIGroups:     []string{clusterv1beta1.GroupName},
+			Resources:     []string{"managedclustersets/join", "managedclustersets/bind"},
+			ResourceNames: []string{clusterSet},
+			Verbs:         []string{"create"},
+		}}
+	case RoleBind:
+		rules = []rbacv1.PolicyRule{view, {
+			APIGroups:     []string{clusterv1beta1.GroupName},
+			Resources:     []string{"managedclustersets/bind"},
+			ResourceNames: []string{clusterSet},
+			Verbs:         []string{"create"},
+		}}
+	case RoleView:
+		rules = []rbacv1.PolicyRule{view}
+	default:
+		return nil, fmt.Errorf("unsupported clusterset role %q, should be one of %v", role, Roles)
+	}
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ClusterRoleName(clusterSet, role),
+			Labels: map[string]string{labelClusterSet: clusterSet, labelRole: role},
+		},
+		Rules: rules,
+	}, nil
+}
+
+// Grant creates or updates the cluster role of the clusterset role and adds the subject to its binding.
+// It returns false if the subject was already granted the role.
+func Grant(ctx context.Context, kubeClient kubernetes.Interface, clusterSet, role string, subject rbacv1.Subject) (bool, error) {
+	clusterRole, err := ClusterRole(clusterSet, role)
+	if err != nil {
+		return false, err
+	}
+
+	existing, err := kubeClient.RbacV1().ClusterRoles().Get(ctx, clusterRole.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if _, err := kubeClient.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{}); err != nil {
+			return false, err
+		}
+	case err != nil:
+		return false, err
+	case !equality.Semantic.DeepEqual(existing.Rules, clusterRole.Rules):
+		existing.Rules = clusterRole.Rules
+		if _, err := kubeClient.RbacV1().ClusterRoles().Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return false, err
+		}
+	}
+
+	binding, err := kubeClient.RbacV1().ClusterRoleBindings().Get(ctx, clusterRole.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = kubeClient.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   clusterRole.Name,
+				Labels: clusterRole.Labels,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     clusterRole.Name,
+			},
+			Subjects: []rbacv1.Subject{subject},
+		}, metav1.CreateOptions{})
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, s := range binding.Subjects {
+		if s.Kind == subject.Kind && s.Name == subject.Name && s.Namespace == subject.Namespace {
+			return false, nil
+		}
+	}
+	binding.Subjects = append(binding.Subjects, subject)
+	_, err = kubeClient.RbacV1().ClusterRoleBindings().Update(ctx, binding, metav1.UpdateOptions{})
+	return err == nil, err
+}
+
+// Subject returns the rbac subject of the kind (User, Group or ServiceAccount) and name,
+// the name of a service account is in the format of namespace/name.
+func Subject(kind, name string) (rbacv1.Subject, error) {
+	switch kind {
+	case rbacv1.UserKind, rbacv1.GroupKind:
+		return rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: kind, Name: name}, nil
+	case rbacv1.ServiceAccountKind:
+		parts := strings.Split(name, "/")
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return rbacv1.Subject{}, fmt.Errorf("the service account %q should be in the format of namespace/name", name)
+		}
+		return rbacv1.Subject{Kind: kind, Namespace: parts[0], Name: parts[1]}, nil
+	}
+	return rbacv1.Subject{}, fmt.Errorf("unsupported subject kind %q", kind)
+}
+
+// AllClusterSets is the clusterset of the access granted on all the clustersets
+const AllClusterSets = "*"
+
+// Access is a role on a clusterset granted to a subject by a cluster role binding
+type Access struct {
+	ClusterSet         string
+	Role               string
+	Subject            rbacv1.Subject
+	ClusterRoleBinding string
+	ClusterRole        string
+}
+
+// ListAccess evaluates the rules of the cluster roles bound to subjects and returns the
+// clusterset roles they grant, including the roles not created by clusteradm.
+func ListAccess(ctx context.Context, kubeClient kubernetes.Interface) ([]Access, error) {
+	clusterRoles, err := kubeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// the clusterset roles granted by each cluster role
+	roles := map[string]map[string]string{}
+	for _, clusterRole := range clusterRoles.Items {
+		if granted := clusterSetRoles(clusterRole.Rules); len(granted) > 0 {
+			roles[clusterRole.Name] = granted
+		}
+	}
+
+	bindings, err := kubeClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	access := []Access{}
+	for _, binding := range bindings.Items {
+		if binding.RoleRef.Kind != "ClusterRole" {
+			continue
+		}
+		for clusterSet, role := range roles[binding.RoleRef.Name] {
+			for _, subject := range binding.Subjects {
+				access = append(access, Access{
+					ClusterSet:         clusterSet,
+					Role:               role,
+					Subject:            subject,
+					ClusterRoleBinding: binding.Name,
+					ClusterRole:        binding.RoleRef.Name,
+				})
+			}
+		}
+	}
+	return access, nil
+}
+
+// clusterSetRoles returns the role granted by the rules on each clusterset, the rules without
+// resource names grant the role on all the clustersets.
+func clusterSetRoles(rules []rbacv1.PolicyRule) map[string]string {
+	clusterSets := map[string]bool{}
+	for _, rule := range rules {
+		if !contains(rule.APIGroups, clusterv1beta1.GroupName) {
+			continue
+		}
+		if len(rule.ResourceNames) == 0 {
+			clusterSets[AllClusterSets] = true
+		}
+		for _, name := range rule.ResourceNames {
+			clusterSets[name] = true
+		}
+	}
+
+	roles := map[string]string{}
+	for clusterSet := range clusterSets {
+		canJoin := allows(rules, clusterSet, "managedclustersets/join", "create")
+		canBind := allows(rules, clusterSet, "managedclustersets/bind", "create")
+		switch {
+		case canJoin && canBind:
+			roles[clusterSet] = RoleAdmin
+		case canBind:
+			roles[clusterSet] = RoleBind
+		case allows(rules, clusterSet, "managedclustersets", "get"):
+			roles[clusterSet] = RoleView
+		}
+	}
+	return roles
+}
+
+// allows returns true if the rules allow the verb on the resource of the clusterset
+func allows(rules []rbacv1.PolicyRule, clusterSet, resource, verb string) bool {
+	for _, rule := range rules {
+		if !contains(rule.APIGroups, clusterv1beta1.GroupName) || !contains(rule.Resources, resource) || !contains(rule.Verbs, verb) {
+			continue
+		}
+		if len(rule.ResourceNames) == 0 || (clusterSet != AllClusterSets && contains(rule.ResourceNames, clusterSet)) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns true if the values contain the value or the wildcard
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == rbacv1.ResourceAll {
+			return true
+		}
+	}
+	return false
+}
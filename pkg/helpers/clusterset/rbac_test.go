@@ -0,0 +1,136 @@
+// Copyright Contributors to the Open Cluster Management project
+package clusterset
+
+import (
+	"context"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClusterRole(t *testing.T) {
+	for _, role := range Roles {
+		clusterRole, err := ClusterRole("dev", role)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", role, err)
+		}
+		if clusterRole.Name != "open-cluster-management:managedclusterset:"+role+":dev" {
+			t.Errorf("%s: unexpected name %s", role, clusterRole.Name)
+		}
+		for _, rule := range clusterRole.Rules {
+			if len(rule.ResourceNames) != 1 || rule.ResourceNames[0] != "dev" {
+				t.Errorf("%s: expected the rules to be restricted to the clusterset, got %v", role, rule.ResourceNames)
+			}
+		}
+	}
+	if _, err := ClusterRole("dev", "owner"); err == nil {
+		t.Errorf("expected an error for an unsupported role")
+	}
+}
+
+func TestGrant(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	user, _ := Subject(rbacv1.UserKind, "u1")
+	group, _ := Subject(rbacv1.GroupKind, "team")
+
+	cases := []struct {
+		subject         rbacv1.Subject
+		expectedGranted bool
+	}{
+		{subject: user, expectedGranted: true},
+		{subject: user, expectedGranted: false},
+		{subject: group, expectedGranted: true},
+	}
+	for _, c := range cases {
+		granted, err := Grant(context.TODO(), kubeClient, "dev", RoleBind, c.subject)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if granted != c.expectedGranted {
+			t.Errorf("%s %s: expected granted %v, got %v", c.subject.Kind, c.subject.Name, c.expectedGranted, granted)
+		}
+	}
+
+	binding, err := kubeClient.RbacV1().ClusterRoleBindings().Get(context.TODO(), ClusterRoleName("dev", RoleBind), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binding.Subjects) != 2 {
+		t.Errorf("expected 2 subjects, got %v", binding.Subjects)
+	}
+}
+
+func TestSubject(t *testing.T) {
+	if _, err := Subject(rbacv1.ServiceAccountKind, "sa"); err == nil {
+		t.Errorf("expected an error for a service account without namespace")
+	}
+	sa, err := Subject(rbacv1.ServiceAccountKind, "ns/sa")
+	if err != nil || sa.Namespace != "ns" || sa.Name != "sa" {
+		t.Errorf("unexpected subject %v, error %v", sa, err)
+	}
+	if _, err := Subject("Robot", "r"); err == nil {
+		t.Errorf("expected an error for an unsupported kind")
+	}
+}
+
+func TestListAccess(t *testing.T) {
+	user, _ := Subject(rbacv1.UserKind, "u1")
+	group, _ := Subject(rbacv1.GroupKind, "g1")
+	kubeClient := kubefake.NewSimpleClientset(
+		// a role not created by clusteradm granting bind on all the clustersets
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "binder"},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{"cluster.open-cluster-management.io"},
+				Resources: []string{"managedclustersets/bind"},
+				Verbs:     []string{"create"},
+			}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binder"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "binder"},
+			Subjects:   []rbacv1.Subject{group},
+		},
+		// a role unrelated to the clustersets
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "pods"},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"*"},
+			}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "pods"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "pods"},
+			Subjects:   []rbacv1.Subject{user},
+		},
+	)
+	for _, role := range Roles {
+		if _, err := Grant(context.TODO(), kubeClient, "dev", role, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	access, err := ListAccess(context.TODO(), kubeClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"*/bind/g1":    "binder",
+		"dev/admin/u1": ClusterRoleName("dev", RoleAdmin),
+		"dev/bind/u1":  ClusterRoleName("dev", RoleBind),
+		"dev/view/u1":  ClusterRoleName("dev", RoleView),
+	}
+	if len(access) != len(expected) {
+		t.Fatalf("expected %d access, got %v", len(expected), access)
+	}
+	for _, a := range access {
+		key := a.ClusterSet + "/" + a.Role + "/" + a.Subject.Name
+		if binding, ok := expected[key]; !ok || binding != a.ClusterRoleBinding {
+			t.Errorf("unexpected access %v", a)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/stolostron/applier/pkg/asset"
+	"open-cluster-management.io/clusteradm/pkg/helpers/keyvalues"
 )
 
 func GetExampleHeader() string {
@@ -35,3 +36,8 @@ func DryRunMessage(dryRun bool) {
 		fmt.Printf("%s is running in dry-run mode\n", GetExampleHeader())
 	}
 }
+
+// ParseKeyValues parses a list of key=value pairs, e.g. the labels or annotations provided by a flag
+func ParseKeyValues(pairs []string) (map[string]string, error) {
+	return keyvalues.Parse(pairs)
+}
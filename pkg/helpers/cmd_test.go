@@ -4,6 +4,7 @@ package helpers
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -38,3 +39,44 @@ func TestGetExampleHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestParseKeyValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			pairs: []string{},
+			want:  map[string]string{},
+		},
+		{
+			name:  "pairs",
+			pairs: []string{"a=b", "example.com/c=d=e", "f="},
+			want:  map[string]string{"a": "b", "example.com/c": "d=e", "f": ""},
+		},
+		{
+			name:    "missing value",
+			pairs:   []string{"a"},
+			wantErr: true,
+		},
+		{
+			name:    "missing key",
+			pairs:   []string{"=b"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeyValues(tt.pairs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeyValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseKeyValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
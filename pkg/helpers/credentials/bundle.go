@@ -0,0 +1,183 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package credentials builds the pre-approved credential bundles of the managed clusters. A bundle
+// holds the same files as the hub kubeconfig secret of the registration agent, so the agent uses the
+// client certificate of the bundle without creating a CSR to be accepted on the hub.
+package credentials
+
+import (
+	"archive/tar"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ghodss/yaml"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+const (
+	// the files of the bundle are the keys of the hub kubeconfig secret of the registration agent
+	ClusterNameFile = "cluster-name"
+	AgentNameFile   = "agent-name"
+	KubeConfigFile  = "kubeconfig"
+	CertFile        = "tls.crt"
+	KeyFile         = "tls.key"
+
+	// the user and groups of the managed clusters on the hub
+	userPrefix    = "system:open-cluster-management:"
+	clustersGroup = "system:open-cluster-management:managed-clusters"
+)
+
+var files = []string{ClusterNameFile, AgentNameFile, KubeConfigFile, CertFile, KeyFile}
+
+// Bundle is the pre-approved credentials of a managed cluster
+type Bundle struct {
+	ClusterName string
+	AgentName   string
+	// KubeConfig refers to the client certificate and key files, like the kubeconfig of the hub kubeconfig secret
+	KubeConfig []byte
+	Cert       []byte
+	Key        []byte
+}
+
+// Subject returns the subject of the client certificate of the registration agent of the cluster
+func Subject(clusterName, agentName string) *pkix.Name {
+	return &pkix.Name{
+		CommonName:   fmt.Sprintf("%s%s:%s", userPrefix, clusterName, agentName),
+		Organization: []string{userPrefix + clusterName, clustersGroup},
+	}
+}
+
+// NewKeyAndCSR generates a private key and the certificate request of the registration agent of the cluster
+func NewKeyAndCSR(clusterName, agentName string) (keyPEM, csrPEM []byte, err error) {
+	keyPEM, err = keyutil.MakeEllipticPrivateKeyPEM()
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := keyutil.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	csrPEM, err = certutil.MakeCSR(key, Subject(clusterName, agentName), nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keyPEM, csrPEM, nil
+}
+
+// HubKubeConfig returns the kubeconfig of the hub referring to the client certificate and key files of the bundle
+func HubKubeConfig(server string, caData []byte) ([]byte, error) {
+	return yaml.Marshal(&clientcmdapiv1.Config{
+		Clusters: []clientcmdapiv1.NamedCluster{{
+			Name: "hub",
+			Cluster: clientcmdapiv1.Cluster{
+				Server:                   server,
+				CertificateAuthorityData: caData,
+			},
+		}},
+		AuthInfos: []clientcmdapiv1.NamedAuthInfo{{
+			Name: "default-auth",
+			AuthInfo: clientcmdapiv1.AuthInfo{
+				ClientCertificate: CertFile,
+				ClientKey:         KeyFile,
+			},
+		}},
+		Contexts: []clientcmdapiv1.NamedContext{{
+			Name: "default-context",
+			Context: clientcmdapiv1.Context{
+				Cluster:   "hub",
+				AuthInfo:  "default-auth",
+				Namespace: "configuration",
+			},
+		}},
+		CurrentContext: "default-context",
+	})
+}
+
+// EmbeddedKubeConfig returns the kubeconfig of the bundle with the client certificate and key data
+// embedded, it is used as the bootstrap kubeconfig of the agent.
+func (b *Bundle) EmbeddedKubeConfig() (*clientcmdapiv1.Config, error) {
+	config := &clientcmdapiv1.Config{}
+	if err := yaml.Unmarshal(b.KubeConfig, config); err != nil {
+		return nil, err
+	}
+	if len(config.AuthInfos) != 1 || len(config.Clusters) != 1 {
+		return nil, fmt.Errorf("the kubeconfig of the credentials should have one cluster and one user")
+	}
+	config.AuthInfos[0].AuthInfo.ClientCertificate = ""
+	config.AuthInfos[0].AuthInfo.ClientCertificateData = b.Cert
+	config.AuthInfos[0].AuthInfo.ClientKey = ""
+	config.AuthInfos[0].AuthInfo.ClientKeyData = b.Key
+	return config, nil
+}
+
+// Write writes the bundle as a tar archive
+func Write(w io.Writer, b *Bundle) error {
+	tw := tar.NewWriter(w)
+	for _, name := range files {
+		data := b.data(name)
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// Read reads a bundle from a tar archive, all the files of the bundle are required
+func Read(r io.Reader) (*Bundle, error) {
+	contents := map[string][]byte{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the credentials: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		contents[header.Name] = data
+	}
+	for _, name := range files {
+		if len(contents[name]) == 0 {
+			return nil, fmt.Errorf("the credentials miss the %s file", name)
+		}
+	}
+	return &Bundle{
+		ClusterName: string(contents[ClusterNameFile]),
+		AgentName:   string(contents[AgentNameFile]),
+		KubeConfig:  contents[KubeConfigFile],
+		Cert:        contents[CertFile],
+		Key:         contents[KeyFile],
+	}, nil
+}
+
+func (b *Bundle) data(name string) []byte {
+	switch name {
+	case ClusterNameFile:
+		return []byte(b.ClusterName)
+	case AgentNameFile:
+		return []byte(b.AgentName)
+	case KubeConfigFile:
+		return b.KubeConfig
+	case CertFile:
+		return b.Cert
+	case KeyFile:
+		return b.Key
+	}
+	return nil
+}
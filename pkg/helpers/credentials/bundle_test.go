@@ -0,0 +1,85 @@
+// Copyright Contributors to the Open Cluster Management project
+package credentials
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"testing"
+)
+
+func TestNewKeyAndCSR(t *testing.T) {
+	_, csrPEM, err := NewKeyAndCSR("cluster1", "agent1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(csrPEM)
+	if block == nil {
+		t.Fatalf("invalid csr pem")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if csr.Subject.CommonName != "system:open-cluster-management:cluster1:agent1" {
+		t.Errorf("unexpected common name %s", csr.Subject.CommonName)
+	}
+	expected := []string{"system:open-cluster-management:cluster1", "system:open-cluster-management:managed-clusters"}
+	if !reflect.DeepEqual(csr.Subject.Organization, expected) {
+		t.Errorf("unexpected organizations %v", csr.Subject.Organization)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	kubeConfig, err := HubKubeConfig("https://hub:6443", []byte("ca"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := &Bundle{
+		ClusterName: "cluster1",
+		AgentName:   "agent1",
+		KubeConfig:  kubeConfig,
+		Cert:        []byte("cert"),
+		Key:         []byte("key"),
+	}
+	buf := &bytes.Buffer{}
+	if err := Write(buf, bundle); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bundle, read) {
+		t.Errorf("expected %v, got %v", bundle, read)
+	}
+
+	config, err := read.EmbeddedKubeConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authInfo := config.AuthInfos[0].AuthInfo
+	if len(authInfo.ClientCertificate) > 0 || string(authInfo.ClientCertificateData) != "cert" || string(authInfo.ClientKeyData) != "key" {
+		t.Errorf("the client certificate is not embedded: %v", authInfo)
+	}
+	if config.Clusters[0].Cluster.Server != "https://hub:6443" {
+		t.Errorf("unexpected server %s", config.Clusters[0].Cluster.Server)
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(&tar.Header{Name: ClusterNameFile, Mode: 0600, Size: 8}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("cluster1")); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	if _, err := Read(buf); err == nil {
+		t.Errorf("expected an error for the missing files")
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package drift compares the objects rendered from the templates of clusteradm with the objects of the
+// clusters, so the changes made on the clusters since they were applied are reported.
+package drift
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Missing is printed for the fields rendered by the templates which are not set on the cluster
+const Missing = "<missing>"
+
+// Difference is a field set by the templates whose value on the cluster is different
+type Difference struct {
+	Path     string
+	Rendered string
+	Live     string
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: rendered %s, live %s", d.Path, d.Rendered, d.Live)
+}
+
+// Compare returns the fields of rendered which are missing or different in live. The fields of live which
+// are not in rendered, like the defaulted fields and the status, are ignored. The lists are compared item
+// by item if they have the same length, as a whole otherwise.
+func Compare(rendered, live map[string]interface{}) []Difference {
+	return compare(rendered, live, "")
+}
+
+func compare(rendered, live interface{}, path string) []Difference {
+	switch r := rendered.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return []Difference{{Path: path, Rendered: format(rendered), Live: format(live)}}
+		}
+		keys := make([]string, 0, len(r))
+		for key := range r {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		differences := []Difference{}
+		for _, key := range keys {
+			field := key
+			if len(path) > 0 {
+				field = path + "." + key
+			}
+			differences = append(differences, compare(r[key], l[key], field)...)
+		}
+		return differences
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(r) {
+			return []Difference{{Path: path, Rendered: format(rendered), Live: format(live)}}
+		}
+		differences := []Difference{}
+		for i := range r {
+			differences = append(differences, compare(r[i], l[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return differences
+	case nil:
+		return nil
+	}
+	if live == nil || !equal(rendered, live) {
+		return []Difference{{Path: path, Rendered: format(rendered), Live: format(live)}}
+	}
+	return nil
+}
+
+// equal compares the scalars, the numbers of the templates are float64 while the numbers of the cluster are int64
+func equal(rendered, live interface{}) bool {
+	return reflect.DeepEqual(rendered, live) || fmt.Sprint(rendered) == fmt.Sprint(live)
+}
+
+func format(value interface{}) string {
+	if value == nil {
+		return Missing
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,76 @@
+// Copyright Contributors to the Open Cluster Management project
+package drift
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	rendered := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "cluster-manager"},
+		"spec": map[string]interface{}{
+			"replicas": float64(1),
+			"args":     []interface{}{"/registration-operator", "hub"},
+			"image":    "quay.io/open-cluster-management/registration-operator:v0.9.1",
+		},
+	}
+	cases := []struct {
+		name     string
+		live     map[string]interface{}
+		expected []Difference
+	}{
+		{
+			name: "unchanged with defaulted fields",
+			live: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "cluster-manager", "uid": "1234"},
+				"spec": map[string]interface{}{
+					"replicas":                int64(1),
+					"args":                    []interface{}{"/registration-operator", "hub"},
+					"image":                   "quay.io/open-cluster-management/registration-operator:v0.9.1",
+					"revisionHistoryLimit":    int64(10),
+					"progressDeadlineSeconds": int64(600),
+				},
+				"status": map[string]interface{}{"replicas": int64(1)},
+			},
+			expected: []Difference{},
+		},
+		{
+			name: "changed and removed fields",
+			live: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "cluster-manager"},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"args":     []interface{}{"/registration-operator", "hub", "--v=4"},
+				},
+			},
+			expected: []Difference{
+				{Path: "spec.args", Rendered: "[/registration-operator hub]", Live: "[/registration-operator hub --v=4]"},
+				{Path: "spec.image", Rendered: "quay.io/open-cluster-management/registration-operator:v0.9.1", Live: Missing},
+				{Path: "spec.replicas", Rendered: "1", Live: "3"},
+			},
+		},
+		{
+			name: "changed list item",
+			live: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "cluster-manager"},
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+					"args":     []interface{}{"/registration-operator", "klusterlet"},
+					"image":    "quay.io/open-cluster-management/registration-operator:v0.9.1",
+				},
+			},
+			expected: []Difference{
+				{Path: "spec.args[1]", Rendered: "hub", Live: "klusterlet"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := Compare(rendered, c.live)
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package endpoint normalizes the urls of the API servers, so their IPv6 literals are bracketed, and checks
+// the addresses of the dual-stack API servers against the preferred IP family.
+package endpoint
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// ValidateFamily checks the family is ipv4, ipv6 or empty for any family
+func ValidateFamily(family string) error {
+	switch family {
+	case "", FamilyIPv4, FamilyIPv6:
+		return nil
+	}
+	return fmt.Errorf("the IP family should be %s or %s, got %s", FamilyIPv4, FamilyIPv6, family)
+}
+
+// Network returns the network dialing the addresses of the family
+func Network(family string) string {
+	switch family {
+	case FamilyIPv4:
+		return "tcp4"
+	case FamilyIPv6:
+		return "tcp6"
+	}
+	return "tcp"
+}
+
+// Normalize brackets the IPv6 literal of the host of the server url, https://2001:db8::1 becomes
+// https://[2001:db8::1]. An unbracketed literal followed by a port is only accepted if it is not
+// ambiguous, https://2001:db8::1:6443 is also a valid address without port so it is rejected.
+func Normalize(server string) (string, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("invalid API server url %s: %v", server, err)
+	}
+	if strings.HasPrefix(u.Host, "[") || strings.Count(u.Host, ":") < 2 {
+		return server, nil
+	}
+	hint := fmt.Errorf("the IPv6 address of the API server url %s should be bracketed, like %s://[2001:db8::1]:6443", server, u.Scheme)
+	withoutPort, port := splitLastGroup(u.Host)
+	isAddress := net.ParseIP(u.Host) != nil
+	isAddressWithPort := net.ParseIP(withoutPort) != nil && validPort(port)
+	switch {
+	case isAddress && isAddressWithPort:
+		return "", hint
+	case isAddress:
+		u.Host = "[" + u.Host + "]"
+	case isAddressWithPort:
+		u.Host = net.JoinHostPort(withoutPort, port)
+	default:
+		return "", hint
+	}
+	return u.String(), nil
+}
+
+// Addresses returns the addresses of the family of the host of the server url, the host is resolved with
+// the lookup if it is not an IP. It returns an error if the host has no address of the family.
+func Addresses(server, family string, lookup func(host string) ([]net.IP, error)) ([]net.IP, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	ips := []net.IP{net.ParseIP(u.Hostname())}
+	if ips[0] == nil {
+		if ips, err = lookup(u.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	addresses := []net.IP{}
+	for _, ip := range ips {
+		if family == "" || Family(ip) == family {
+			addresses = append(addresses, ip)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s has no %s address", u.Hostname(), family)
+	}
+	return addresses, nil
+}
+
+// Family returns the family of the IP
+func Family(ip net.IP) string {
+	if ip.To4() != nil {
+		return FamilyIPv4
+	}
+	return FamilyIPv6
+}
+
+func splitLastGroup(host string) (string, string) {
+	i := strings.LastIndex(host, ":")
+	return host[:i], host[i+1:]
+}
+
+func validPort(port string) bool {
+	p, err := strconv.Atoi(port)
+	return err == nil && p > 0 && p < 65536
+}
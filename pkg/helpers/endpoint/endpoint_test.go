@@ -0,0 +1,70 @@
+// Copyright Contributors to the Open Cluster Management project
+package endpoint
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		server      string
+		expected    string
+		expectedErr bool
+	}{
+		{server: "https://hub.example.com:6443", expected: "https://hub.example.com:6443"},
+		{server: "https://192.0.2.10:6443", expected: "https://192.0.2.10:6443"},
+		{server: "https://[2001:db8::1]:6443", expected: "https://[2001:db8::1]:6443"},
+		{server: "https://2001:db8::1", expected: "https://[2001:db8::1]"},
+		{server: "https://2001:db8:0:0:0:0:0:1:6443", expected: "https://[2001:db8:0:0:0:0:0:1]:6443"},
+		{server: "https://2001:db8::1:6443", expectedErr: true},
+		{server: "https://2001:zz::1", expectedErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.server, func(t *testing.T) {
+			got, err := Normalize(c.server)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	lookup := func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.0.2.10"), net.ParseIP("2001:db8::1")}, nil
+	}
+	ips, err := Addresses("https://hub.example.com:6443", FamilyIPv6, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ips) != 1 || ips[0].String() != "2001:db8::1" {
+		t.Errorf("expected the IPv6 address, got %v", ips)
+	}
+	if ips, err := Addresses("https://hub.example.com:6443", "", lookup); err != nil || len(ips) != 2 {
+		t.Errorf("expected both addresses, got %v %v", ips, err)
+	}
+	if _, err := Addresses("https://192.0.2.10:6443", FamilyIPv6, lookup); err == nil {
+		t.Errorf("expected an error for an IPv4 literal")
+	}
+}
+
+func TestValidateFamily(t *testing.T) {
+	for _, family := range []string{"", FamilyIPv4, FamilyIPv6} {
+		if err := ValidateFamily(family); err != nil {
+			t.Errorf("unexpected error for %q: %v", family, err)
+		}
+	}
+	if err := ValidateFamily("ipv5"); err == nil {
+		t.Errorf("expected an error for ipv5")
+	}
+}
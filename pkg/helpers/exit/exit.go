@@ -0,0 +1,96 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package exit defines the exit codes of clusteradm, so automation can branch on
+// the class of a failure instead of parsing the error messages.
+package exit
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Code is the process exit code of a failure class
+type Code int
+
+const (
+	// CodeOK is returned when the command succeeds
+	CodeOK Code = 0
+	// CodeGeneric is returned for the failures without a specific class
+	CodeGeneric Code = 1
+	// CodePreflight is returned when a preflight check fails
+	CodePreflight Code = 2
+	// CodeTimeout is returned when the command times out waiting for a resource to be ready
+	CodeTimeout Code = 3
+	// CodePartial is returned when the command fails for some of the clusters only
+	CodePartial Code = 4
+	// CodeValidation is returned when the options or arguments are invalid
+	CodeValidation Code = 5
+)
+
+// Error is an error classified with an exit code
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New classifies the error with the code, an error already classified keeps its code.
+func New(code Code, err error) error {
+	if err == nil || CodeOf(err) != CodeGeneric {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Preflight classifies the error as a preflight failure
+func Preflight(err error) error {
+	return New(CodePreflight, err)
+}
+
+// Timeout classifies the error as a timeout waiting for readiness
+func Timeout(err error) error {
+	return New(CodeTimeout, err)
+}
+
+// Partial classifies the error as a failure of some of the clusters only, it
+// overrides the classes of the errors of the failed clusters.
+func Partial(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: CodePartial, Err: err}
+}
+
+// Validation classifies the error as invalid options or arguments
+func Validation(err error) error {
+	return New(CodeValidation, err)
+}
+
+// CodeOf returns the exit code of the error. The errors not explicitly classified are
+// classified by their type, e.g. the preflight errors and the poll timeouts.
+func CodeOf(err error) Code {
+	if err == nil {
+		return CodeOK
+	}
+	classified := &Error{}
+	if errors.As(err, &classified) {
+		return classified.Code
+	}
+	var preflightErr interface{ Preflight() bool }
+	if errors.As(err, &preflightErr) && preflightErr.Preflight() {
+		return CodePreflight
+	}
+	if errors.Is(err, wait.ErrWaitTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+	return CodeGeneric
+}
@@ -0,0 +1,37 @@
+// Copyright Contributors to the Open Cluster Management project
+package exit
+
+import (
+	"fmt"
+	"testing"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"open-cluster-management.io/clusteradm/pkg/helpers/preflight"
+)
+
+func TestCodeOf(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{name: "nil", err: nil, expected: CodeOK},
+		{name: "generic", err: fmt.Errorf("failed"), expected: CodeGeneric},
+		{name: "validation", err: Validation(fmt.Errorf("invalid")), expected: CodeValidation},
+		{name: "preflight", err: &preflight.Error{Msg: "failed"}, expected: CodePreflight},
+		{name: "preflight in validation", err: Validation(Preflight(fmt.Errorf("failed"))), expected: CodePreflight},
+		{name: "preflight error in validation", err: Validation(&preflight.Error{Msg: "failed"}), expected: CodePreflight},
+		{name: "wrapped preflight", err: fmt.Errorf("init: %w", &preflight.Error{Msg: "failed"}), expected: CodePreflight},
+		{name: "poll timeout", err: wait.ErrWaitTimeout, expected: CodeTimeout},
+		{name: "poll timeout in aggregate", err: utilerrors.NewAggregate([]error{wait.ErrWaitTimeout}), expected: CodeTimeout},
+		{name: "partial", err: Partial(utilerrors.NewAggregate([]error{wait.ErrWaitTimeout})), expected: CodePartial},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if code := CodeOf(c.err); code != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package hive reads the cluster pools and claims of hive, the clusters are provisioned
+// by hive and registered to the hub under the name of their namespace.
+package hive
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+// ClusterSetLabel is the label of the cluster pool holding the clusterset of its clusters
+const ClusterSetLabel = clusterv1beta1.ClusterSetLabel
+
+var (
+	ClusterPoolGVR = schema.GroupVersionResource{
+		Group:    "hive.openshift.io",
+		Version:  "v1",
+		Resource: "clusterpools",
+	}
+	ClusterClaimGVR = schema.GroupVersionResource{
+		Group:    "hive.openshift.io",
+		Version:  "v1",
+		Resource: "clusterclaims",
+	}
+)
+
+// PoolStatus returns the size of the pool and the numbers of its ready and standby clusters
+func PoolStatus(pool unstructured.Unstructured) (size, ready, standby int64) {
+	size, _, _ = unstructured.NestedInt64(pool.Object, "spec", "size")
+	ready, _, _ = unstructured.NestedInt64(pool.Object, "status", "ready")
+	standby, _, _ = unstructured.NestedInt64(pool.Object, "status", "standby")
+	return
+}
+
+// ClaimPool returns the name of the pool the claim is claiming a cluster from
+func ClaimPool(claim unstructured.Unstructured) string {
+	pool, _, _ := unstructured.NestedString(claim.Object, "spec", "clusterPoolName")
+	return pool
+}
+
+// ClaimedCluster returns the name of the cluster assigned to the claim, it is empty
+// until the claim is fulfilled.
+func ClaimedCluster(claim unstructured.Unstructured) string {
+	cluster, _, _ := unstructured.NestedString(claim.Object, "spec", "namespace")
+	return cluster
+}
+
+// NewClaim returns a claim of a cluster of the pool
+func NewClaim(namespace, name, pool, lifetime string) *unstructured.Unstructured {
+	claim := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ClusterClaimGVR.GroupVersion().String(),
+		"kind":       "ClusterClaim",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"clusterPoolName": pool,
+		},
+	}}
+	if len(name) > 0 {
+		claim.SetName(name)
+	} else {
+		claim.SetGenerateName(pool + "-")
+	}
+	if len(lifetime) > 0 {
+		_ = unstructured.SetNestedField(claim.Object, lifetime, "spec", "lifetime")
+	}
+	return claim
+}
@@ -0,0 +1,162 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package hubs stores the named connections to the hubs, the commands target one of them with --hub
+// or the current one set by `clusteradm hub use`.
+package hubs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// SkipAnnotation is set on the commands which do not target a hub, like the commands managing the hubs
+const SkipAnnotation = "clusteradm.open-cluster-management.io/skip-hub"
+
+// Skipped returns true if the command or one of its parents does not target a hub
+func Skipped(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[SkipAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Hub is a named connection to a hub and the defaults of the commands targeting it
+type Hub struct {
+	Name string `json:"name"`
+	// Kubeconfig is the path of the kubeconfig of the hub, the default loading rules apply if empty
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Context is the context of the hub in the kubeconfig, the current context if empty
+	Context string `json:"context,omitempty"`
+	// ClusterSet is the default of the --clusterset flags
+	ClusterSet string `json:"clusterSet,omitempty"`
+	// Registry is the default of the --image-registry flags
+	Registry string `json:"registry,omitempty"`
+	// Issuer is the OIDC issuer of the credentials stored by `clusteradm login`, the kubeconfig of the hub is
+	// then owned by clusteradm and removed with the hub
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// Config is the file of the hubs
+type Config struct {
+	// Current is the hub targeted by the commands without --hub
+	Current string `json:"current,omitempty"`
+	Hubs    []Hub  `json:"hubs"`
+}
+
+// DefaultPath returns the file of the hubs in the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "clusteradm", "hubs.yaml"), nil
+}
+
+// KubeconfigPath returns the kubeconfig of the credentials of the hub stored by `clusteradm login`, next to the
+// file of the hubs
+func KubeconfigPath(path, name string) string {
+	return filepath.Join(filepath.Dir(path), "kubeconfigs", name+".kubeconfig")
+}
+
+// Load reads the hubs, the config is empty if the file does not exist
+func Load(path string) (*Config, error) {
+	config := &Config{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the hubs in %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Save writes the hubs, only the user can read the file
+func (c *Config) Save(path string) error {
+	sort.Slice(c.Hubs, func(i, j int) bool { return c.Hubs[i].Name < c.Hubs[j].Name })
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Get returns the hub of the name, nil if it does not exist
+func (c *Config) Get(name string) *Hub {
+	for i := range c.Hubs {
+		if c.Hubs[i].Name == name {
+			return &c.Hubs[i]
+		}
+	}
+	return nil
+}
+
+// Set adds the hub or replaces the hub of the same name
+func (c *Config) Set(hub Hub) {
+	if existing := c.Get(hub.Name); existing != nil {
+		*existing = hub
+		return
+	}
+	c.Hubs = append(c.Hubs, hub)
+}
+
+// Remove deletes the hub, it returns false if the hub does not exist
+func (c *Config) Remove(name string) bool {
+	for i := range c.Hubs {
+		if c.Hubs[i].Name == name {
+			c.Hubs = append(c.Hubs[:i], c.Hubs[i+1:]...)
+			if c.Current == name {
+				c.Current = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the hub targeted by the command, the hub of the name or the current hub if the name is empty.
+// It returns nil if no hub is targeted.
+func (c *Config) Resolve(name string) (*Hub, error) {
+	if len(name) == 0 {
+		name = c.Current
+	}
+	if len(name) == 0 {
+		return nil, nil
+	}
+	hub := c.Get(name)
+	if hub == nil {
+		return nil, fmt.Errorf("hub %s is not found, add it with `clusteradm hub add %s`", name, name)
+	}
+	return hub, nil
+}
+
+// ApplyDefaults sets the flags of the command the user did not set to the defaults of the hub
+func (h *Hub) ApplyDefaults(cmd *cobra.Command) error {
+	defaults := map[string]string{
+		"clusterset":     h.ClusterSet,
+		"image-registry": h.Registry,
+	}
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || len(value) == 0 {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("failed to set --%s to the default %s of hub %s: %v", name, value, h.Name, err)
+		}
+	}
+	return nil
+}
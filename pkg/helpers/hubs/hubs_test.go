@@ -0,0 +1,98 @@
+// Copyright Contributors to the Open Cluster Management project
+package hubs
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clusteradm", "hubs.yaml")
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Hubs) != 0 || len(config.Current) != 0 {
+		t.Errorf("expected an empty config, got %+v", config)
+	}
+
+	config.Set(Hub{Name: "staging", Context: "staging"})
+	config.Set(Hub{Name: "prod", Context: "prod"})
+	config.Set(Hub{Name: "staging", Context: "staging-hub", ClusterSet: "staging"})
+	config.Current = "prod"
+	if err := config.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Config{
+		Current: "prod",
+		Hubs: []Hub{
+			{Name: "prod", Context: "prod"},
+			{Name: "staging", Context: "staging-hub", ClusterSet: "staging"},
+		},
+	}
+	if !reflect.DeepEqual(loaded, expected) {
+		t.Errorf("expected %+v, got %+v", expected, loaded)
+	}
+
+	if loaded.Remove("unknown") {
+		t.Errorf("expected unknown not to be removed")
+	}
+	if !loaded.Remove("prod") || len(loaded.Current) != 0 {
+		t.Errorf("expected prod to be removed and the current hub to be unset, got %+v", loaded)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	config := &Config{Hubs: []Hub{{Name: "prod"}, {Name: "staging"}}}
+	if hub, err := config.Resolve(""); hub != nil || err != nil {
+		t.Errorf("expected no hub, got %v %v", hub, err)
+	}
+	config.Current = "prod"
+	if hub, err := config.Resolve(""); err != nil || hub.Name != "prod" {
+		t.Errorf("expected the current hub, got %v %v", hub, err)
+	}
+	if hub, err := config.Resolve("staging"); err != nil || hub.Name != "staging" {
+		t.Errorf("expected hub staging, got %v %v", hub, err)
+	}
+	if _, err := config.Resolve("unknown"); err == nil {
+		t.Errorf("expected an error for an unknown hub")
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("clusterset", "", "")
+	cmd.Flags().String("image-registry", "quay.io/open-cluster-management", "")
+	if err := cmd.Flags().Parse([]string{"--image-registry", "quay.io/mine"}); err != nil {
+		t.Fatal(err)
+	}
+	hub := &Hub{Name: "prod", ClusterSet: "prod", Registry: "quay.io/mirror"}
+	if err := hub.ApplyDefaults(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cmd.Flags().GetString("clusterset"); got != "prod" {
+		t.Errorf("expected the clusterset of the hub, got %s", got)
+	}
+	if got, _ := cmd.Flags().GetString("image-registry"); got != "quay.io/mine" {
+		t.Errorf("expected the registry set by the user, got %s", got)
+	}
+}
+
+func TestSkipped(t *testing.T) {
+	parent := &cobra.Command{Use: "hub", Annotations: map[string]string{SkipAnnotation: ""}}
+	child := &cobra.Command{Use: "list"}
+	parent.AddCommand(child)
+	if !Skipped(child) {
+		t.Errorf("expected the child of a skipped command to be skipped")
+	}
+	if Skipped(&cobra.Command{Use: "get"}) {
+		t.Errorf("expected get not to be skipped")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package i18n is the catalog of the messages printed to the users, the prompts, the spinner messages and
+// the next step banners, in the language of --lang or of the locale environment variables.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultLanguage is the language of the messages missing from the other catalogs
+const DefaultLanguage = "en"
+
+const catalogDir = "catalog"
+
+//go:embed catalog
+var files embed.FS
+
+// localeEnvs are the environment variables of the locale, by precedence
+var localeEnvs = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+var (
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+	loadErr  error
+
+	// current is the language of the running command
+	current = DefaultLanguage
+)
+
+func load() (map[string]map[string]string, error) {
+	loadOnce.Do(func() {
+		catalogs = map[string]map[string]string{}
+		entries, err := fs.ReadDir(files, catalogDir)
+		if err != nil {
+			loadErr = err
+			return
+		}
+		for _, entry := range entries {
+			data, err := files.ReadFile(path.Join(catalogDir, entry.Name()))
+			if err != nil {
+				loadErr = err
+				return
+			}
+			messages := map[string]string{}
+			if err := yaml.Unmarshal(data, &messages); err != nil {
+				loadErr = fmt.Errorf("invalid catalog %s: %v", entry.Name(), err)
+				return
+			}
+			catalogs[strings.TrimSuffix(entry.Name(), ".yaml")] = messages
+		}
+	})
+	return catalogs, loadErr
+}
+
+// Languages returns the languages of the catalog
+func Languages() []string {
+	catalogs, _ := load()
+	languages := []string{}
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Setup sets the language of the messages to the one of the --lang flag, or of the locale environment
+// variables if the flag is not set. An unknown --lang is an error, an unknown locale falls back to English.
+func Setup(lang string) error {
+	catalogs, err := load()
+	if err != nil {
+		return err
+	}
+	if len(lang) > 0 {
+		language := baseLanguage(lang)
+		if _, ok := catalogs[language]; !ok {
+			return fmt.Errorf("unsupported --lang %q, the supported languages are %s", lang, strings.Join(Languages(), ", "))
+		}
+		current = language
+		return nil
+	}
+	current = DefaultLanguage
+	for _, env := range localeEnvs {
+		value := os.Getenv(env)
+		if len(value) == 0 {
+			continue
+		}
+		if language := baseLanguage(value); len(catalogs[language]) > 0 {
+			current = language
+		}
+		return nil
+	}
+	return nil
+}
+
+// baseLanguage returns the language of a locale or language tag, e.g. fr of fr_CA.UTF-8 or fr-CA
+func baseLanguage(locale string) string {
+	locale = strings.SplitN(locale, ".", 2)[0]
+	locale = strings.SplitN(locale, "@", 2)[0]
+	locale = strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)[0]
+	return strings.ToLower(locale)
+}
+
+// T returns the message of the id in the current language formatted with the args. The English message is
+// returned if the current catalog misses it, and the id if no catalog has it.
+func T(id string, args ...interface{}) string {
+	catalogs, _ := load()
+	format, ok := catalogs[current][id]
+	if !ok {
+		if format, ok = catalogs[DefaultLanguage][id]; !ok {
+			format = id
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,89 @@
+// Copyright Contributors to the Open Cluster Management project
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbs = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogs(t *testing.T) {
+	catalogs, err := load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	english := catalogs[DefaultLanguage]
+	for language, messages := range catalogs {
+		for id, message := range messages {
+			original, ok := english[id]
+			if !ok {
+				t.Errorf("%s message %s is missing from the %s catalog", language, id, DefaultLanguage)
+				continue
+			}
+			if a, b := verbs.FindAllString(original, -1), verbs.FindAllString(message, -1); len(a) != len(b) {
+				t.Errorf("%s message %s has the verbs %v, expected %v", language, id, b, a)
+			}
+		}
+		if len(messages) != len(english) {
+			t.Errorf("%s catalog has %d messages, expected %d", language, len(messages), len(english))
+		}
+	}
+}
+
+func TestSetup(t *testing.T) {
+	defer func() { current = DefaultLanguage }()
+	cases := []struct {
+		name             string
+		lang             string
+		env              map[string]string
+		expectedLanguage string
+		expectedErr      bool
+	}{
+		{name: "default", expectedLanguage: "en"},
+		{name: "flag", lang: "fr", env: map[string]string{"LANG": "en_US.UTF-8"}, expectedLanguage: "fr"},
+		{name: "flag with region", lang: "fr-CA", expectedLanguage: "fr"},
+		{name: "unknown flag", lang: "xx", expectedErr: true},
+		{name: "locale", env: map[string]string{"LANG": "fr_FR.UTF-8"}, expectedLanguage: "fr"},
+		{name: "locale precedence", env: map[string]string{"LC_ALL": "C", "LANG": "fr_FR.UTF-8"}, expectedLanguage: "en"},
+		{name: "unknown locale", env: map[string]string{"LANG": "xx_XX"}, expectedLanguage: "en"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, env := range localeEnvs {
+				t.Setenv(env, c.env[env])
+			}
+			err := Setup(c.lang)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if current != c.expectedLanguage {
+				t.Errorf("expected language %s, got %s", c.expectedLanguage, current)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer func() { current = DefaultLanguage }()
+	current = DefaultLanguage
+	if message := T("join.preApproved", "cluster1"); message != "The cluster cluster1 joins with pre-approved credentials, no accept is needed on the hub.\n" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if message := T("accept.joined", "cluster1"); message[:7] != "\n Your " {
+		t.Errorf("unexpected message %q", message)
+	}
+	current = "fr"
+	if message := T("wait.klusterlet.done"); message != "Le klusterlet est maintenant disponible.\n" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if message := T("unknown.message"); message != "unknown.message" {
+		t.Errorf("unexpected message %q", message)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright Contributors to the Open Cluster Management project
+package image
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ArchitectureLabel is the well known label of the node architecture
+const ArchitectureLabel = "kubernetes.io/arch"
+
+// GetNodeArchitectures returns the architectures of the nodes in the cluster
+func GetNodeArchitectures(kubeClient kubernetes.Interface) (sets.String, error) {
+	nodes, err := kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	architectures := sets.NewString()
+	for _, node := range nodes.Items {
+		if arch, ok := node.Labels[ArchitectureLabel]; ok {
+			architectures.Insert(arch)
+		}
+	}
+	return architectures, nil
+}
+
+// ArchitectureCheck verifies the images exist and support the architectures of
+// the nodes. After the check, Architectures is set to the node architectures
+// supported by all the images if the images can only run on part of the nodes.
+type ArchitectureCheck struct {
+	Images     []string
+	KubeClient kubernetes.Interface
+	// Skip does not look up the images in the registry, e.g. if the registry is mirrored on the nodes
+	// or not reachable from here. The pods are scheduled to all the nodes.
+	Skip bool
+
+	// Architectures is the result of the check, the pods should be scheduled to
+	// the nodes of these architectures. It is empty if no restriction is needed.
+	Architectures []string
+
+	getImageArchitectures func(image string) (sets.String, error)
+}
+
+func (c *ArchitectureCheck) Check() (warnings []string, errorList []error) {
+	if c.Skip {
+		return []string{"the images and their architectures are not verified in the registry"}, nil
+	}
+	nodeArchitectures, err := GetNodeArchitectures(c.KubeClient)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to get the architectures of the nodes: %v", err)}, nil
+	}
+	if nodeArchitectures.Len() == 0 {
+		return nil, nil
+	}
+
+	getImageArchitectures := c.getImageArchitectures
+	if getImageArchitectures == nil {
+		getImageArchitectures = GetImageArchitectures
+	}
+
+	supported := sets.NewString(nodeArchitectures.List()...)
+	// the registries which are not reachable, their other images are not looked up
+	unreachable := map[string]error{}
+	for _, image := range c.Images {
+		registry := ""
+		if ref, err := parseReference(image); err == nil {
+			registry = ref.registry
+		}
+		err, ok := unreachable[registry]
+		var architectures sets.String
+		if !ok {
+			architectures, err = getImageArchitectures(image)
+		}
+		if _, ok := err.(ErrImageNotFound); ok {
+			errorList = append(errorList, fmt.Errorf("%v, set --skip-image-check if the registry is mirrored on the nodes", err))
+			continue
+		}
+		if err != nil {
+			// the registry may not be reachable from here, e.g. a disconnected environment
+			warnings = append(warnings, fmt.Sprintf("unable to verify image %s: %v", image, err))
+			if len(registry) > 0 {
+				unreachable[registry] = err
+			}
+			continue
+		}
+		supported = supported.Intersection(architectures)
+	}
+	if len(errorList) > 0 {
+		return warnings, errorList
+	}
+
+	switch {
+	case supported.Len() == 0:
+		errorList = append(errorList, fmt.Errorf("the images do not support any of the node architectures %v", nodeArchitectures.List()))
+	case supported.Len() < nodeArchitectures.Len():
+		warnings = append(warnings, fmt.Sprintf("the images do not support the node architectures %v, the pods are only scheduled to the %v nodes",
+			nodeArchitectures.Difference(supported).List(), supported.List()))
+		c.Architectures = supported.List()
+	}
+	return warnings, errorList
+}
+
+func (c *ArchitectureCheck) Name() string {
+	return "ImageArchitecture check"
+}
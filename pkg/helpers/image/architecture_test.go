@@ -0,0 +1,113 @@
+// Copyright Contributors to the Open Cluster Management project
+package image
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	testinghelper "open-cluster-management.io/clusteradm/pkg/helpers/testing"
+)
+
+func newNode(name, arch string) runtime.Object {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{ArchitectureLabel: arch},
+		},
+	}
+}
+
+func TestArchitectureCheck(t *testing.T) {
+	imageArchitectures := map[string]sets.String{
+		"multiarch": sets.NewString("amd64", "arm64"),
+		"amd64":     sets.NewString("amd64"),
+	}
+	getImageArchitectures := func(image string) (sets.String, error) {
+		if image == "unreachable" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		architectures, ok := imageArchitectures[image]
+		if !ok {
+			return nil, ErrImageNotFound{Image: image}
+		}
+		return architectures, nil
+	}
+
+	tests := []struct {
+		name              string
+		nodes             []runtime.Object
+		images            []string
+		skip              bool
+		wantWarnings      []string
+		wantErrors        []error
+		wantArchitectures []string
+	}{
+		{
+			name:   "images support all the nodes",
+			nodes:  []runtime.Object{newNode("node1", "amd64"), newNode("node2", "arm64")},
+			images: []string{"multiarch"},
+		},
+		{
+			name:              "images support part of the nodes",
+			nodes:             []runtime.Object{newNode("node1", "amd64"), newNode("node2", "arm64")},
+			images:            []string{"multiarch", "amd64"},
+			wantWarnings:      []string{"the images do not support the node architectures [arm64], the pods are only scheduled to the [amd64] nodes"},
+			wantArchitectures: []string{"amd64"},
+		},
+		{
+			name:       "images support none of the nodes",
+			nodes:      []runtime.Object{newNode("node1", "arm64")},
+			images:     []string{"amd64"},
+			wantErrors: []error{errors.New("the images do not support any of the node architectures [arm64]")},
+		},
+		{
+			name:       "image not found",
+			nodes:      []runtime.Object{newNode("node1", "amd64")},
+			images:     []string{"missing"},
+			wantErrors: []error{errors.New("image missing is not found, set --skip-image-check if the registry is mirrored on the nodes")},
+		},
+		{
+			name:         "registry unreachable",
+			nodes:        []runtime.Object{newNode("node1", "amd64")},
+			images:       []string{"unreachable"},
+			wantWarnings: []string{"unable to verify image unreachable: connection refused"},
+		},
+		{
+			name:   "registry unreachable is looked up once",
+			nodes:  []runtime.Object{newNode("node1", "amd64")},
+			images: []string{"unreachable", "amd64"},
+			wantWarnings: []string{"unable to verify image unreachable: connection refused",
+				"unable to verify image amd64: connection refused"},
+		},
+		{
+			name:         "skipped",
+			nodes:        []runtime.Object{newNode("node1", "arm64")},
+			images:       []string{"missing", "amd64"},
+			skip:         true,
+			wantWarnings: []string{"the images and their architectures are not verified in the registry"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ArchitectureCheck{
+				Images:                tt.images,
+				KubeClient:            fakekube.NewSimpleClientset(tt.nodes...),
+				Skip:                  tt.skip,
+				getImageArchitectures: getImageArchitectures,
+			}
+			warnings, errs := c.Check()
+			testinghelper.AssertWarnings(t, warnings, tt.wantWarnings)
+			testinghelper.AssertErrors(t, errs, tt.wantErrors)
+			if !reflect.DeepEqual(c.Architectures, tt.wantArchitectures) {
+				t.Errorf("expected architectures %v but got %v", tt.wantArchitectures, c.Architectures)
+			}
+		})
+	}
+}
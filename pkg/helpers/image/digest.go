@@ -0,0 +1,63 @@
+// Copyright Contributors to the Open Cluster Management project
+package image
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// The names of the OCM images in the image registry
+const (
+	OperatorImageName     = "registration-operator"
+	RegistrationImageName = "registration"
+	WorkImageName         = "work"
+	PlacementImageName    = "placement"
+)
+
+var digestRegexp = regexp.MustCompile(`^sha(256:[a-f0-9]{64}|512:[a-f0-9]{128})$`)
+
+// Digests maps the image names to their digests, e.g. registration: sha256:...
+type Digests map[string]string
+
+// LoadDigestFile reads the digests of the images from a yaml or json file
+func LoadDigestFile(path string) (Digests, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	digests := Digests{}
+	if err := yaml.Unmarshal(data, &digests); err != nil {
+		return nil, fmt.Errorf("failed to parse the image digest file %s: %v", path, err)
+	}
+	for name, d := range digests {
+		if !digestRegexp.MatchString(d) {
+			return nil, fmt.Errorf("invalid digest %q of image %s", d, name)
+		}
+	}
+	return digests, nil
+}
+
+// withDigest returns the pull spec of the image referenced by the digest instead of its tag
+func withDigest(image, digest string) string {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name + "@" + digest
+}
+
+// PullSpec returns the pull spec of the image in the registry, the image is
+// referenced by its digest if it is in the digests, otherwise by the tag.
+func PullSpec(registry, name, tag string, digests Digests) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if d, ok := digests[name]; ok {
+		return fmt.Sprintf("%s/%s@%s", registry, name, d)
+	}
+	return fmt.Sprintf("%s/%s:%s", registry, name, tag)
+}
@@ -0,0 +1,277 @@
+// Copyright Contributors to the Open Cluster Management project
+package image
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	defaultRegistry = "registry-1.docker.io"
+
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// ErrImageNotFound is returned if the image does not exist in the registry
+type ErrImageNotFound struct {
+	Image string
+}
+
+func (e ErrImageNotFound) Error() string {
+	return fmt.Sprintf("image %s is not found", e.Image)
+}
+
+type reference struct {
+	registry   string
+	repository string
+	// a tag or a digest
+	reference string
+}
+
+// parseReference splits the image pull spec into the registry, the repository
+// and the tag or digest
+func parseReference(image string) (reference, error) {
+	ref := reference{registry: defaultRegistry}
+
+	name := image
+	if i := strings.Index(name, "/"); i > 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.registry = host
+			name = name[i+1:]
+		}
+	}
+
+	switch {
+	case strings.Contains(name, "@"):
+		parts := strings.SplitN(name, "@", 2)
+		ref.repository, ref.reference = parts[0], parts[1]
+	case strings.LastIndex(name, ":") > strings.LastIndex(name, "/"):
+		i := strings.LastIndex(name, ":")
+		ref.repository, ref.reference = name[:i], name[i+1:]
+	default:
+		ref.repository, ref.reference = name, "latest"
+	}
+
+	if len(ref.repository) == 0 || len(ref.reference) == 0 {
+		return ref, fmt.Errorf("invalid image %s", image)
+	}
+	if ref.registry == defaultRegistry && !strings.Contains(ref.repository, "/") {
+		ref.repository = "library/" + ref.repository
+	}
+	return ref, nil
+}
+
+type manifest struct {
+	MediaType string `json:"mediaType"`
+	// set for a manifest list or an image index
+	Manifests []struct {
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	// set for an image manifest
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// registryClient reads the image manifests with the registry v2 api
+type registryClient struct {
+	client *http.Client
+	scheme string
+}
+
+func newRegistryClient() *registryClient {
+	return &registryClient{
+		client: &http.Client{Timeout: 10 * time.Second},
+		scheme: "https",
+	}
+}
+
+// GetImageArchitectures returns the linux architectures supported by the image
+func GetImageArchitectures(image string) (sets.String, error) {
+	return newRegistryClient().getImageArchitectures(image)
+}
+
+func (c *registryClient) getImageArchitectures(image string) (sets.String, error) {
+	ref, err := parseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.get(ref, "manifests/"+ref.reference, strings.Join([]string{
+		mediaTypeDockerManifestList, mediaTypeOCIIndex, mediaTypeDockerManifest, mediaTypeOCIManifest}, ","))
+	if err != nil {
+		if _, ok := err.(ErrImageNotFound); ok {
+			return nil, ErrImageNotFound{Image: image}
+		}
+		return nil, err
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(body, m); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of image %s: %v", image, err)
+	}
+
+	architectures := sets.NewString()
+	if len(m.Manifests) > 0 {
+		for _, item := range m.Manifests {
+			if item.Platform.OS == "linux" {
+				architectures.Insert(item.Platform.Architecture)
+			}
+		}
+		return architectures, nil
+	}
+
+	// a single arch image, the architecture is in the image config
+	body, err = c.get(ref, "blobs/"+m.Config.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	config := &struct {
+		Architecture string `json:"architecture"`
+	}{}
+	if err := json.Unmarshal(body, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the config of image %s: %v", image, err)
+	}
+	return architectures.Insert(config.Architecture), nil
+}
+
+// GetDigest returns the digest of the image, the digest of its manifest list or index if it is a multi-arch image
+func GetDigest(image string) (string, error) {
+	return newRegistryClient().getDigest(image)
+}
+
+func (c *registryClient) getDigest(image string) (string, error) {
+	ref, err := parseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if digestRegexp.MatchString(ref.reference) {
+		return ref.reference, nil
+	}
+
+	body, header, err := c.getWithHeader(ref, "manifests/"+ref.reference, strings.Join([]string{
+		mediaTypeDockerManifestList, mediaTypeOCIIndex, mediaTypeDockerManifest, mediaTypeOCIManifest}, ","))
+	if err != nil {
+		if _, ok := err.(ErrImageNotFound); ok {
+			return "", ErrImageNotFound{Image: image}
+		}
+		return "", err
+	}
+	if digest := header.Get("Docker-Content-Digest"); digestRegexp.MatchString(digest) {
+		return digest, nil
+	}
+	// the digest is the hash of the manifest if the registry does not return it
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(body)), nil
+}
+
+// get requests the path of the repository, an anonymous token is requested if the
+// registry requires it
+func (c *registryClient) get(ref reference, path, accept string) ([]byte, error) {
+	body, _, err := c.getWithHeader(ref, path, accept)
+	return body, err
+}
+
+// getWithHeader requests the path of the repository and returns the headers of the response too
+func (c *registryClient) getWithHeader(ref reference, path, accept string) ([]byte, http.Header, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", c.scheme, ref.registry, ref.repository, path)
+	resp, err := c.do(u, accept, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		token, err := c.token(challenge)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp, err = c.do(u, accept, token); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		return body, resp.Header, err
+	case http.StatusNotFound:
+		return nil, nil, ErrImageNotFound{}
+	default:
+		return nil, nil, fmt.Errorf("unexpected status %s from %s", resp.Status, u)
+	}
+}
+
+func (c *registryClient) do(u, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.client.Do(req)
+}
+
+// token requests an anonymous token from the realm of the bearer challenge, e.g.
+// Bearer realm="https://quay.io/v2/auth",service="quay.io",scope="repository:foo/bar:pull"
+func (c *registryClient) token(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || len(params["realm"]) == 0 {
+		return "", fmt.Errorf("invalid realm in the authentication challenge %q", challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if len(params[key]) > 0 {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.do(realm.String(), "", "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get a token from %s: %s", realm.Host, resp.Status)
+	}
+	token := &struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return "", err
+	}
+	if len(token.Token) > 0 {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
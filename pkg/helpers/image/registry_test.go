@@ -0,0 +1,158 @@
+// Copyright Contributors to the Open Cluster Management project
+package image
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image   string
+		want    reference
+		wantErr bool
+	}{
+		{
+			image: "quay.io/open-cluster-management/registration:v0.9.0",
+			want:  reference{registry: "quay.io", repository: "open-cluster-management/registration", reference: "v0.9.0"},
+		},
+		{
+			image: "localhost:5000/registration@sha256:abc",
+			want:  reference{registry: "localhost:5000", repository: "registration", reference: "sha256:abc"},
+		},
+		{
+			image: "busybox",
+			want:  reference{registry: defaultRegistry, repository: "library/busybox", reference: "latest"},
+		},
+		{
+			image: "ocm/work:latest",
+			want:  reference{registry: defaultRegistry, repository: "ocm/work", reference: "latest"},
+		},
+		{
+			image:   "quay.io/ocm/work:",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := parseReference(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullSpec(t *testing.T) {
+	digests := Digests{"work": "sha256:" + strings.Repeat("a", 64)}
+	if got := PullSpec("quay.io/ocm/", "work", "v0.9.0", digests); got != "quay.io/ocm/work@"+digests["work"] {
+		t.Errorf("unexpected pull spec %s", got)
+	}
+	if got := PullSpec("quay.io/ocm", "placement", "v0.9.0", digests); got != "quay.io/ocm/placement:v0.9.0" {
+		t.Errorf("unexpected pull spec %s", got)
+	}
+}
+
+func TestGetImageArchitectures(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, `{"token": "anonymous"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/ocm/multiarch/manifests/latest":
+			fmt.Fprint(w, `{"mediaType": "`+mediaTypeOCIIndex+`", "manifests": [
+				{"platform": {"architecture": "amd64", "os": "linux"}},
+				{"platform": {"architecture": "arm64", "os": "linux"}},
+				{"platform": {"architecture": "amd64", "os": "windows"}}]}`)
+		case "/v2/ocm/singlearch/manifests/latest":
+			fmt.Fprint(w, `{"mediaType": "`+mediaTypeDockerManifest+`", "config": {"digest": "sha256:config"}}`)
+		case "/v2/ocm/singlearch/blobs/sha256:config":
+			fmt.Fprint(w, `{"architecture": "s390x", "os": "linux"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	c := &registryClient{client: server.Client(), scheme: "http"}
+
+	tests := []struct {
+		image   string
+		want    []string
+		wantErr error
+	}{
+		{image: host + "/ocm/multiarch:latest", want: []string{"amd64", "arm64"}},
+		{image: host + "/ocm/singlearch:latest", want: []string{"s390x"}},
+		{image: host + "/ocm/missing:latest", wantErr: ErrImageNotFound{Image: host + "/ocm/missing:latest"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := c.getImageArchitectures(tt.image)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("expected error %v but got %v", tt.wantErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(got.List(), tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got.List())
+			}
+		})
+	}
+}
+
+func TestGetDigest(t *testing.T) {
+	index := `{"mediaType": "` + mediaTypeOCIIndex + `", "manifests": []}`
+	headerDigest := "sha256:" + strings.Repeat("b", 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/ocm/work/manifests/v0.9.0":
+			w.Header().Set("Docker-Content-Digest", headerDigest)
+			fmt.Fprint(w, index)
+		case "/v2/ocm/placement/manifests/v0.9.0":
+			fmt.Fprint(w, index)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	c := &registryClient{client: server.Client(), scheme: "http"}
+	pinned := "sha256:" + strings.Repeat("c", 64)
+
+	tests := []struct {
+		image   string
+		want    string
+		wantErr error
+	}{
+		{image: host + "/ocm/work:v0.9.0", want: headerDigest},
+		{image: host + "/ocm/placement:v0.9.0", want: fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(index)))},
+		{image: host + "/ocm/registration@" + pinned, want: pinned},
+		{image: host + "/ocm/missing:v0.9.0", wantErr: ErrImageNotFound{Image: host + "/ocm/missing:v0.9.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := c.getDigest(tt.image)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("expected error %v but got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s but got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright Contributors to the Open Cluster Management project
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+const cosignBinary = "cosign"
+
+// VerifyOptions verifies the signatures and the provenance of the images with cosign
+// before they are deployed
+type VerifyOptions struct {
+	// Enabled is true if the images should be verified
+	Enabled bool
+	// Key is the public key to verify the signatures, it can be a file, an url or a kms uri
+	Key string
+	// CertificateIdentity and CertificateOIDCIssuer are used for keyless verification
+	CertificateIdentity   string
+	CertificateOIDCIssuer string
+	// Provenance is true if the SLSA provenance attestation should be verified as well
+	Provenance bool
+
+	getDigest func(image string) (string, error)
+	cosign    func(args []string) error
+}
+
+func (v *VerifyOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&v.Enabled, "verify-images", false, "If true, verify the signatures of the images with cosign before deploying them, "+
+		"the images are deployed by the verified digests and the deployment fails if the verification fails. Not run with --dry-run")
+	fs.StringVar(&v.Key, "verify-key", "", "The public key used to verify the image signatures, can be a file, an url or a kms uri")
+	fs.StringVar(&v.CertificateIdentity, "verify-certificate-identity", "", "The identity expected in the signing certificate for keyless verification")
+	fs.StringVar(&v.CertificateOIDCIssuer, "verify-certificate-oidc-issuer", "", "The OIDC issuer expected in the signing certificate for keyless verification")
+	fs.BoolVar(&v.Provenance, "verify-provenance", false, "If true, verify the SLSA provenance attestation of the images as well")
+}
+
+func (v *VerifyOptions) Validate() error {
+	if !v.Enabled {
+		return nil
+	}
+	keyless := len(v.CertificateIdentity) > 0 || len(v.CertificateOIDCIssuer) > 0
+	switch {
+	case len(v.Key) > 0 && keyless:
+		return fmt.Errorf("--verify-key can not be used with the keyless verification flags")
+	case len(v.Key) == 0 && !keyless:
+		return fmt.Errorf("--verify-key or --verify-certificate-identity and --verify-certificate-oidc-issuer are required to verify the images")
+	case keyless && (len(v.CertificateIdentity) == 0 || len(v.CertificateOIDCIssuer) == 0):
+		return fmt.Errorf("both --verify-certificate-identity and --verify-certificate-oidc-issuer are required for the keyless verification")
+	}
+	if _, err := exec.LookPath(cosignBinary); err != nil {
+		return fmt.Errorf("%s is required to verify the images, see https://docs.sigstore.dev/cosign/installation: %v", cosignBinary, err)
+	}
+	return nil
+}
+
+// Verify verifies each image by the digest its tag is resolved to, and returns the images referenced by these
+// digests so the images deployed are the verified ones even if the tags are moved. An error is returned on the
+// first image failing the verification.
+func (v *VerifyOptions) Verify(images []string) ([]string, error) {
+	if !v.Enabled {
+		return images, nil
+	}
+	getDigest, cosign := v.getDigest, v.cosign
+	if getDigest == nil {
+		getDigest = GetDigest
+	}
+	if cosign == nil {
+		cosign = runCosign
+	}
+	verified := make([]string, 0, len(images))
+	for _, image := range images {
+		digest, err := getDigest(image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the digest of image %s: %v", image, err)
+		}
+		pinned := withDigest(image, digest)
+		for _, args := range v.commands(pinned) {
+			klog.V(1).InfoS("verifying image:", "image", pinned, "args", args)
+			if err := cosign(args); err != nil {
+				return nil, fmt.Errorf("failed to verify image %s: %v", pinned, err)
+			}
+		}
+		verified = append(verified, pinned)
+	}
+	return verified, nil
+}
+
+// runCosign runs the cosign command, its error output is returned in the error
+func runCosign(args []string) error {
+	stderr := &bytes.Buffer{}
+	c := exec.Command(cosignBinary, args...)
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// commands returns the arguments of the cosign commands verifying the image
+func (v *VerifyOptions) commands(image string) [][]string {
+	identity := []string{}
+	if len(v.Key) > 0 {
+		identity = append(identity, "--key", v.Key)
+	} else {
+		identity = append(identity,
+			"--certificate-identity", v.CertificateIdentity,
+			"--certificate-oidc-issuer", v.CertificateOIDCIssuer)
+	}
+
+	commands := [][]string{append(append([]string{"verify"}, identity...), image)}
+	if v.Provenance {
+		commands = append(commands, append(append([]string{"verify-attestation", "--type", "slsaprovenance"}, identity...), image))
+	}
+	return commands
+}
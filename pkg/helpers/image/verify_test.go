@@ -0,0 +1,129 @@
+// Copyright Contributors to the Open Cluster Management project
+package image
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerifyCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		options VerifyOptions
+		want    [][]string
+	}{
+		{
+			name:    "key",
+			options: VerifyOptions{Key: "cosign.pub"},
+			want:    [][]string{{"verify", "--key", "cosign.pub", "quay.io/ocm/work:v0.9.0"}},
+		},
+		{
+			name: "keyless with provenance",
+			options: VerifyOptions{
+				CertificateIdentity:   "https://github.com/ocm/work/.github/workflows/release.yml@refs/tags/v0.9.0",
+				CertificateOIDCIssuer: "https://token.actions.githubusercontent.com",
+				Provenance:            true,
+			},
+			want: [][]string{
+				{"verify",
+					"--certificate-identity", "https://github.com/ocm/work/.github/workflows/release.yml@refs/tags/v0.9.0",
+					"--certificate-oidc-issuer", "https://token.actions.githubusercontent.com",
+					"quay.io/ocm/work:v0.9.0"},
+				{"verify-attestation", "--type", "slsaprovenance",
+					"--certificate-identity", "https://github.com/ocm/work/.github/workflows/release.yml@refs/tags/v0.9.0",
+					"--certificate-oidc-issuer", "https://token.actions.githubusercontent.com",
+					"quay.io/ocm/work:v0.9.0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.commands("quay.io/ocm/work:v0.9.0"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestVerifyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options VerifyOptions
+		wantErr string
+	}{
+		{name: "disabled", options: VerifyOptions{}},
+		{
+			name:    "missing key",
+			options: VerifyOptions{Enabled: true},
+			wantErr: "--verify-key or --verify-certificate-identity and --verify-certificate-oidc-issuer are required to verify the images",
+		},
+		{
+			name:    "key and keyless",
+			options: VerifyOptions{Enabled: true, Key: "cosign.pub", CertificateIdentity: "id"},
+			wantErr: "--verify-key can not be used with the keyless verification flags",
+		},
+		{
+			name:    "incomplete keyless",
+			options: VerifyOptions{Enabled: true, CertificateIdentity: "id"},
+			wantErr: "both --verify-certificate-identity and --verify-certificate-oidc-issuer are required for the keyless verification",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			switch {
+			case len(tt.wantErr) == 0 && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case len(tt.wantErr) > 0 && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("expected error %q but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	var verified []string
+	v := &VerifyOptions{
+		Enabled: true,
+		Key:     "cosign.pub",
+		getDigest: func(image string) (string, error) {
+			if strings.Contains(image, "missing") {
+				return "", ErrImageNotFound{Image: image}
+			}
+			return digest, nil
+		},
+		cosign: func(args []string) error {
+			image := args[len(args)-1]
+			if strings.Contains(image, "unsigned") {
+				return fmt.Errorf("no matching signatures")
+			}
+			verified = append(verified, image)
+			return nil
+		},
+	}
+
+	images, err := v.Verify([]string{"quay.io/ocm/work:v0.9.0", "localhost:5000/ocm/registration@" + digest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the digests are verified and deployed instead of the tags
+	want := []string{"quay.io/ocm/work@" + digest, "localhost:5000/ocm/registration@" + digest}
+	if !reflect.DeepEqual(images, want) || !reflect.DeepEqual(verified, want) {
+		t.Errorf("expected the images %v to be verified, got %v verified and %v returned", want, verified, images)
+	}
+
+	if _, err := v.Verify([]string{"quay.io/ocm/unsigned:v0.9.0"}); err == nil || !strings.Contains(err.Error(), "quay.io/ocm/unsigned@"+digest) {
+		t.Errorf("expected the verification of the digest to fail, got %v", err)
+	}
+	if _, err := v.Verify([]string{"quay.io/ocm/missing:v0.9.0"}); err == nil || !strings.Contains(err.Error(), "failed to resolve the digest") {
+		t.Errorf("expected the digest resolution to fail, got %v", err)
+	}
+
+	disabled := &VerifyOptions{}
+	if images, err := disabled.Verify([]string{"quay.io/ocm/work:v0.9.0"}); err != nil || images[0] != "quay.io/ocm/work:v0.9.0" {
+		t.Errorf("expected the images to be returned as is, got %v %v", images, err)
+	}
+}
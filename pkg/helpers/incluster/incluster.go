@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package incluster detects clusteradm running in a pod without kubeconfig, e.g. in the jobs of
+// 'clusteradm generate operator-job', where the clients are built from the service account of the pod.
+package incluster
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	// tokenFile is the token of the service account mounted in the pods
+	tokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// inClusterConfig returns the config of the service account of the pod
+	inClusterConfig = rest.InClusterConfig
+)
+
+// Detect returns true if the kubeconfig loaded by the client config has no context and the in-cluster
+// config is available, the clients of the client config are then built from the service account.
+func Detect(clientConfig clientcmd.ClientConfig) bool {
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil || len(rawConfig.Contexts) > 0 || len(rawConfig.Clusters) > 0 {
+		return false
+	}
+	_, err = inClusterConfig()
+	return err == nil
+}
+
+// ServiceAccount returns the user name of the service account of the pod, in the format of
+// system:serviceaccount:<namespace>:<name>, from the subject of its token.
+func ServiceAccount() (string, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(strings.TrimSpace(string(token)), ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("the token of the service account is not a jwt")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid token of the service account: %v", err)
+	}
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("invalid token of the service account: %v", err)
+	}
+	if len(claims.Subject) == 0 {
+		return "", fmt.Errorf("the token of the service account has no subject")
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,62 @@
+// Copyright Contributors to the Open Cluster Management project
+package incluster
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestDetect(t *testing.T) {
+	defer func() { inClusterConfig = rest.InClusterConfig }()
+	empty := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{}, nil)
+	kubeconfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{
+		CurrentContext: "hub",
+		Clusters:       map[string]*clientcmdapi.Cluster{"hub": {Server: "https://hub:6443"}},
+		Contexts:       map[string]*clientcmdapi.Context{"hub": {Cluster: "hub"}},
+	}, nil)
+
+	inClusterConfig = func() (*rest.Config, error) { return &rest.Config{}, nil }
+	if !Detect(empty) {
+		t.Errorf("expected in-cluster without kubeconfig")
+	}
+	if Detect(kubeconfig) {
+		t.Errorf("expected the kubeconfig to be used")
+	}
+
+	inClusterConfig = func() (*rest.Config, error) { return nil, rest.ErrNotInCluster }
+	if Detect(empty) {
+		t.Errorf("expected not in-cluster out of a pod")
+	}
+}
+
+func TestServiceAccount(t *testing.T) {
+	defer func(file string) { tokenFile = file }(tokenFile)
+	tokenFile = filepath.Join(t.TempDir(), "token")
+
+	if _, err := ServiceAccount(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing token, got %v", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"system:serviceaccount:open-cluster-management:clusteradm-accept"}`))
+	if err := os.WriteFile(tokenFile, []byte("header."+payload+".signature\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	user, err := ServiceAccount()
+	if err != nil || user != "system:serviceaccount:open-cluster-management:clusteradm-accept" {
+		t.Errorf("unexpected service account %q %v", user, err)
+	}
+
+	if err := os.WriteFile(tokenFile, []byte("not-a-jwt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ServiceAccount(); err == nil {
+		t.Errorf("expected an invalid token")
+	}
+}
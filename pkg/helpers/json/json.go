@@ -11,6 +11,8 @@ import (
 type HubInfo struct {
 	HubToken     string `json:"hub-token"`
 	HubApiserver string `json:"hub-apiserver"`
+	// CAHashes are the hashes of the hub CA verified by join with --discovery-token-ca-cert-hash
+	CAHashes []string `json:"discovery-token-ca-cert-hash,omitempty"`
 }
 
 func WriteJsonOutput(w io.Writer, val interface{}) error {
@@ -0,0 +1,23 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package keyvalues parses the key=value pairs of the flags, it has no dependency so the round trippers
+// of the rest configs can parse their flags.
+package keyvalues
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse parses a list of key=value pairs, e.g. the labels or annotations provided by a flag
+func Parse(pairs []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("error parsing '%s'. Expected to be of the form: key=value", pair)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values, nil
+}
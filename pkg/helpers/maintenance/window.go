@@ -0,0 +1,182 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package maintenance restricts the destructive commands to the maintenance window of the change-management
+// policy, e.g. "Sat 02:00-04:00 UTC". The commands refuse to run outside the window unless --force is set.
+package maintenance
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// WindowEnv is the environment variable of the default maintenance window
+const WindowEnv = "CLUSTERADM_MAINTENANCE_WINDOW"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Window is a daily time range on some days of the week, a range ending before its start ends the next day
+type Window struct {
+	// Days are the days the window opens on, every day if empty
+	Days map[time.Weekday]bool
+	// Start and End are the minutes of the day the window opens and closes at
+	Start, End int
+	Location   *time.Location
+}
+
+// Parse parses a window in the format [DAYS] HH:MM-HH:MM [TIMEZONE]. The days are a comma separated list
+// of days or day ranges, e.g. Sat,Sun or Mon-Fri, and the timezone is UTC or an IANA name, UTC by default.
+func Parse(spec string) (*Window, error) {
+	fields := strings.Fields(spec)
+	w := &Window{Location: time.UTC}
+	invalid := fmt.Errorf("invalid maintenance window %q, it should be in the format [DAYS] HH:MM-HH:MM [TIMEZONE], e.g. \"Sat 02:00-04:00 UTC\"", spec)
+	if len(fields) == 0 || len(fields) > 3 {
+		return nil, invalid
+	}
+	i := 0
+	if !strings.Contains(fields[0], ":") {
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", invalid, err)
+		}
+		w.Days = days
+		i++
+	}
+	if i >= len(fields) {
+		return nil, invalid
+	}
+	times := strings.Split(fields[i], "-")
+	if len(times) != 2 {
+		return nil, invalid
+	}
+	var err error
+	if w.Start, err = parseMinutes(times[0]); err != nil {
+		return nil, fmt.Errorf("%v: %v", invalid, err)
+	}
+	if w.End, err = parseMinutes(times[1]); err != nil {
+		return nil, fmt.Errorf("%v: %v", invalid, err)
+	}
+	if w.Start == w.End {
+		return nil, fmt.Errorf("%v: the window is empty", invalid)
+	}
+	i++
+	if i < len(fields) {
+		if w.Location, err = time.LoadLocation(fields[i]); err != nil {
+			return nil, fmt.Errorf("%v: unknown timezone %s", invalid, fields[i])
+		}
+		i++
+	}
+	if i != len(fields) {
+		return nil, invalid
+	}
+	return w, nil
+}
+
+func parseDays(s string) (map[time.Weekday]bool, error) {
+	days := map[time.Weekday]bool{}
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.Split(part, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid days %s", part)
+		}
+		first, ok := weekdays[strings.ToLower(bounds[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %s", bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = weekdays[strings.ToLower(bounds[1])]; !ok {
+				return nil, fmt.Errorf("unknown day %s", bounds[1])
+			}
+		}
+		// a range may wrap around the week, e.g. Fri-Mon
+		for day := first; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w *Window) opensOn(day time.Weekday) bool {
+	return len(w.Days) == 0 || w.Days[day]
+}
+
+// Contains returns true if the window is open at t
+func (w *Window) Contains(t time.Time) bool {
+	t = t.In(w.Location)
+	minutes := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return w.opensOn(t.Weekday()) && minutes >= w.Start && minutes < w.End
+	}
+	// the window ends the day after it opens
+	return (w.opensOn(t.Weekday()) && minutes >= w.Start) || (w.opensOn((t.Weekday()+6)%7) && minutes < w.End)
+}
+
+// Next returns the time the window opens next after t
+func (w *Window) Next(t time.Time) time.Time {
+	t = t.In(w.Location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.Location)
+	for i := 0; i <= 7; i++ {
+		day := midnight.AddDate(0, 0, i)
+		open := day.Add(time.Duration(w.Start) * time.Minute)
+		if w.opensOn(day.Weekday()) && open.After(t) {
+			return open
+		}
+	}
+	return t
+}
+
+// Options is the maintenance window of a command
+type Options struct {
+	// Window is the maintenance window, the command runs at any time if empty
+	Window string
+	// Force runs the command outside the window
+	Force bool
+}
+
+func (m *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&m.Window, "maintenance-window", os.Getenv(WindowEnv),
+		"The maintenance window the command is allowed to run in, e.g. \"Sat 02:00-04:00 UTC\" or \"Mon-Fri 22:00-02:00 Europe/Paris\", "+
+			"defaults to the "+WindowEnv+" environment variable. The command refuses to run outside the window unless --force is set")
+	fs.BoolVar(&m.Force, "force", false, "If true, the command runs outside the --maintenance-window")
+}
+
+// Validate returns an error if the window is invalid, or if now is outside the window and the command is
+// not forced. A warning is printed if the command is forced outside the window.
+func (m *Options) Validate(now time.Time, w io.Writer) error {
+	if len(m.Window) == 0 {
+		return nil
+	}
+	window, err := Parse(m.Window)
+	if err != nil {
+		return err
+	}
+	if window.Contains(now) {
+		return nil
+	}
+	next := window.Next(now).Format("Mon 2006-01-02 15:04 MST")
+	if m.Force {
+		fmt.Fprintf(w, "WARNING: running outside the maintenance window %q with --force, the next window opens on %s\n", m.Window, next)
+		return nil
+	}
+	return fmt.Errorf("the command is not allowed outside the maintenance window %q, the next window opens on %s. "+
+		"Run it with --force to override", m.Window, next)
+}
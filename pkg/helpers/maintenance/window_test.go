@@ -0,0 +1,98 @@
+// Copyright Contributors to the Open Cluster Management project
+package maintenance
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		spec        string
+		expectedErr bool
+	}{
+		{spec: "Sat 02:00-04:00 UTC"},
+		{spec: "Mon-Fri 22:00-02:00 Europe/Paris"},
+		{spec: "Fri-Mon,Wed 01:00-03:00"},
+		{spec: "02:00-04:00"},
+		{spec: "", expectedErr: true},
+		{spec: "Sat", expectedErr: true},
+		{spec: "Sat 02:00", expectedErr: true},
+		{spec: "Someday 02:00-04:00", expectedErr: true},
+		{spec: "Sat 02:00-02:00", expectedErr: true},
+		{spec: "Sat 25:00-04:00", expectedErr: true},
+		{spec: "Sat 02:00-04:00 Mars/Olympus", expectedErr: true},
+		{spec: "Sat 02:00-04:00 UTC extra", expectedErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			if _, err := Parse(c.spec); (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	// 2022-06-04 is a Saturday
+	at := func(day, hour, minute int) time.Time { return time.Date(2022, 6, day, hour, minute, 0, 0, time.UTC) }
+	cases := []struct {
+		name     string
+		spec     string
+		time     time.Time
+		expected bool
+	}{
+		{name: "in the window", spec: "Sat 02:00-04:00 UTC", time: at(4, 3, 0), expected: true},
+		{name: "at the end", spec: "Sat 02:00-04:00 UTC", time: at(4, 4, 0)},
+		{name: "another day", spec: "Sat 02:00-04:00 UTC", time: at(5, 3, 0)},
+		{name: "every day", spec: "02:00-04:00", time: at(7, 2, 0), expected: true},
+		{name: "overnight before midnight", spec: "Fri 22:00-02:00", time: at(3, 23, 0), expected: true},
+		{name: "overnight after midnight", spec: "Fri 22:00-02:00", time: at(4, 1, 59), expected: true},
+		{name: "overnight closed", spec: "Fri 22:00-02:00", time: at(4, 22, 30)},
+		{name: "range wrapping the week", spec: "Sat-Mon 02:00-04:00", time: at(6, 3, 0), expected: true},
+		// 03:00 UTC is 05:00 in Paris in summer
+		{name: "timezone", spec: "Sat 04:00-06:00 Europe/Paris", time: at(4, 3, 0), expected: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w, err := Parse(c.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := w.Contains(c.time); got != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	w, err := Parse("Sat 02:00-04:00 UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Date(2022, 6, 11, 2, 0, 0, 0, time.UTC)
+	if got := w.Next(time.Date(2022, 6, 4, 5, 0, 0, 0, time.UTC)); !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	outside := time.Date(2022, 6, 6, 12, 0, 0, 0, time.UTC)
+	out := &bytes.Buffer{}
+	if err := (&Options{}).Validate(outside, out); err != nil {
+		t.Errorf("expected no error without window, got %v", err)
+	}
+	err := (&Options{Window: "Sat 02:00-04:00 UTC"}).Validate(outside, out)
+	if err == nil || !strings.Contains(err.Error(), "Sat 2022-06-11 02:00 UTC") {
+		t.Errorf("expected an error with the next window, got %v", err)
+	}
+	if err := (&Options{Window: "Sat 02:00-04:00 UTC", Force: true}).Validate(outside, out); err != nil {
+		t.Errorf("expected no error with force, got %v", err)
+	}
+	if !strings.Contains(out.String(), "WARNING") {
+		t.Errorf("expected a warning with force, got %q", out.String())
+	}
+}
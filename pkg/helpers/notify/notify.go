@@ -0,0 +1,84 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package notify posts the onboarding events of the clusters to a webhook, e.g. a Slack incoming webhook or
+// the endpoint of an incident tool. The payload carries a text summary displayed by the chat tools along
+// with the fields of the event.
+package notify
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+	"open-cluster-management.io/clusteradm/pkg/helpers/webhook"
+)
+
+const (
+	EventAccepted = "accepted"
+	EventRejected = "rejected"
+
+	sendTimeout = 5 * time.Second
+)
+
+// Event is the payload posted to the webhook
+type Event struct {
+	// Text is the summary of the event, it is the message displayed by Slack
+	Text    string    `json:"text"`
+	Event   string    `json:"event"`
+	Cluster string    `json:"cluster"`
+	Hub     string    `json:"hub,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// NewEvent returns the event of the cluster on the hub, the reason is the cause of a rejection
+func NewEvent(event, cluster, hub, reason string) Event {
+	text := fmt.Sprintf("Cluster %s is %s", cluster, event)
+	if len(hub) > 0 {
+		text += " by the hub " + hub
+	}
+	if len(reason) > 0 {
+		text += ": " + reason
+	}
+	return Event{Text: text, Event: event, Cluster: cluster, Hub: hub, Reason: reason, Time: time.Now().UTC()}
+}
+
+// Options posts the events to the url if set
+type Options struct {
+	URL string
+}
+
+func (n *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&n.URL, "notify-url", "", "The http(s) url a JSON payload is posted to when a cluster is accepted or rejected, "+
+		"e.g. a Slack incoming webhook. The payload has the text, event, cluster, hub, reason and time fields")
+}
+
+func (n *Options) Validate() error {
+	if len(n.URL) == 0 {
+		return nil
+	}
+	u, err := url.Parse(n.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("invalid --notify-url %s, it must be an http(s) url", webhook.Redact(n.URL))
+	}
+	return nil
+}
+
+// Notify posts the event if the url is set, a failure is printed as a warning as the clusters are
+// already changed
+func (n *Options) Notify(event Event, errOut io.Writer) {
+	if len(n.URL) == 0 {
+		return
+	}
+	if err := Send(n.URL, event); err != nil {
+		// the url of a Slack incoming webhook is its credential
+		fmt.Fprintf(errOut, "WARNING: failed to notify %s that cluster %s is %s: %v\n", webhook.Redact(n.URL), event.Cluster, event.Event, err)
+	}
+}
+
+// Send posts the event to the url
+func Send(endpoint string, event Event) error {
+	return webhook.Post(endpoint, event, sendTimeout)
+}
@@ -0,0 +1,69 @@
+// Copyright Contributors to the Open Cluster Management project
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNotify(t *testing.T) {
+	received := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	errOut := &bytes.Buffer{}
+	n := &Options{URL: server.URL}
+	n.Notify(NewEvent(EventRejected, "cluster1", "https://hub:6443", "unexpected api server"), errOut)
+	if errOut.Len() > 0 {
+		t.Fatalf("unexpected warning %s", errOut.String())
+	}
+	if received["text"] != "Cluster cluster1 is rejected by the hub https://hub:6443: unexpected api server" {
+		t.Errorf("unexpected text %v", received["text"])
+	}
+	if received["event"] != EventRejected || received["cluster"] != "cluster1" || received["reason"] != "unexpected api server" {
+		t.Errorf("unexpected payload %v", received)
+	}
+}
+
+func TestNotifyFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	errOut := &bytes.Buffer{}
+	n := &Options{URL: server.URL + "/services/T000/B000/secret"}
+	n.Notify(NewEvent(EventAccepted, "cluster1", "", ""), errOut)
+	if !strings.Contains(errOut.String(), "WARNING: failed to notify") {
+		t.Errorf("expected a warning, got %q", errOut.String())
+	}
+	// the url of the webhook is its credential
+	if strings.Contains(errOut.String(), "secret") {
+		t.Errorf("expected the url of the webhook to be redacted, got %q", errOut.String())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for url, valid := range map[string]bool{
+		"":                                   true,
+		"https://hooks.slack.com/services/x": true,
+		"ftp://example.com":                  false,
+		"hooks.slack.com":                    false,
+	} {
+		if err := (&Options{URL: url}).Validate(); (err == nil) != valid {
+			t.Errorf("url %q: expected valid %v, got %v", url, valid, err)
+		}
+	}
+}
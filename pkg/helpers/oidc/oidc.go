@@ -0,0 +1,235 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package oidc logs in to an OpenID Connect issuer with the device authorization grant (RFC 8628), for the
+// hubs fronted by an enterprise SSO. The tokens are stored in a kubeconfig with the oidc auth provider of
+// client-go, which refreshes the id token with the refresh token when it expires.
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// defaultInterval is the polling interval in seconds of the token endpoint if the issuer does not set one
+	defaultInterval = 5
+	// slowDownInterval is added to the interval when the issuer asks to slow down
+	slowDownInterval = 5
+)
+
+// second is the unit of the intervals and expiry of the device authorization, it is shortened in the tests
+var second = time.Second
+
+// Provider is the endpoints of the issuer
+type Provider struct {
+	Issuer                      string `json:"issuer"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// Client is the OAuth client of clusteradm registered in the issuer
+type Client struct {
+	ID string
+	// Secret is empty for a public client
+	Secret string
+	Scopes []string
+	HTTP   *http.Client
+}
+
+// DeviceAuthorization is the code the user enters in the verification page of the issuer
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// Token is the response of the token endpoint
+type Token struct {
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Error        string `json:"error,omitempty"`
+	// ErrorDescription is set by the issuer with Error
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// Discover reads the endpoints of the issuer from its discovery document
+func (c *Client) Discover(ctx context.Context, issuer string) (*Provider, error) {
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	provider := &Provider{}
+	if err := c.do(req, provider); err != nil {
+		return nil, fmt.Errorf("failed to discover the issuer %s: %v", issuer, err)
+	}
+	if strings.TrimSuffix(provider.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, fmt.Errorf("the issuer %s returns the discovery document of another issuer %s", issuer, provider.Issuer)
+	}
+	if len(provider.DeviceAuthorizationEndpoint) == 0 {
+		return nil, fmt.Errorf("the issuer %s does not support the device authorization grant", issuer)
+	}
+	return provider, nil
+}
+
+// Authorize starts the device authorization, the user enters the code returned in the verification page
+func (c *Client) Authorize(ctx context.Context, provider *Provider) (*DeviceAuthorization, error) {
+	form := c.form()
+	form.Set("scope", strings.Join(c.Scopes, " "))
+	req, err := c.post(ctx, provider.DeviceAuthorizationEndpoint, form)
+	if err != nil {
+		return nil, err
+	}
+	auth := &DeviceAuthorization{}
+	if err := c.do(req, auth); err != nil {
+		return nil, fmt.Errorf("failed to start the device authorization: %v", err)
+	}
+	if len(auth.DeviceCode) == 0 || len(auth.UserCode) == 0 || len(auth.VerificationURI) == 0 {
+		return nil, fmt.Errorf("the issuer returns an invalid device authorization")
+	}
+	return auth, nil
+}
+
+// PollToken polls the token endpoint until the user approves or denies the device authorization, or until
+// the device code expires
+func (c *Client) PollToken(ctx context.Context, provider *Provider, auth *DeviceAuthorization) (*Token, error) {
+	interval := time.Duration(auth.Interval) * second
+	if interval <= 0 {
+		interval = defaultInterval * second
+	}
+	if auth.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(auth.ExpiresIn)*second)
+		defer cancel()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("the device code expired before the login was approved")
+		case <-time.After(interval):
+		}
+
+		form := c.form()
+		form.Set("grant_type", deviceCodeGrantType)
+		form.Set("device_code", auth.DeviceCode)
+		req, err := c.post(ctx, provider.TokenEndpoint, form)
+		if err != nil {
+			return nil, err
+		}
+		token := &Token{}
+		// the pending authorizations are errors of the token endpoint
+		err = c.do(req, token)
+		switch token.Error {
+		case "":
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += slowDownInterval * second
+			continue
+		case "access_denied":
+			return nil, fmt.Errorf("the login was denied")
+		case "expired_token":
+			return nil, fmt.Errorf("the device code expired before the login was approved")
+		default:
+			return nil, fmt.Errorf("the issuer returns %s: %s", token.Error, token.ErrorDescription)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(token.IDToken) == 0 {
+			return nil, fmt.Errorf("the issuer returns no id token, check the openid scope is requested")
+		}
+		return token, nil
+	}
+}
+
+func (c *Client) form() url.Values {
+	form := url.Values{}
+	form.Set("client_id", c.ID)
+	if len(c.Secret) > 0 {
+		form.Set("client_secret", c.Secret)
+	}
+	return form
+}
+
+func (c *Client) post(ctx context.Context, endpoint string, form url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// do sends the request and decodes the json response, the response is decoded even if the status is an
+// error as the OAuth errors are returned in the body
+func (c *Client) do(req *http.Request, into interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	decodeErr := json.NewDecoder(resp.Body).Decode(into)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", req.URL, resp.Status)
+	}
+	return decodeErr
+}
+
+// AuthProviderConfig returns the config of the oidc auth provider of client-go logged in with the token
+func AuthProviderConfig(issuer string, client *Client, issuerCA []byte, token *Token) map[string]string {
+	config := map[string]string{
+		"idp-issuer-url": issuer,
+		"client-id":      client.ID,
+		"id-token":       token.IDToken,
+	}
+	if len(client.Secret) > 0 {
+		config["client-secret"] = client.Secret
+	}
+	if len(token.RefreshToken) > 0 {
+		config["refresh-token"] = token.RefreshToken
+	}
+	if len(issuerCA) > 0 {
+		config["idp-certificate-authority-data"] = base64.StdEncoding.EncodeToString(issuerCA)
+	}
+	return config
+}
+
+// Subject returns the email, or the subject if there is no email, of the id token. The token is not
+// verified, the subject is only printed to the user.
+func Subject(idToken string) string {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return ""
+	}
+	claims := struct {
+		Subject string `json:"sub"`
+		Email   string `json:"email"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	if len(claims.Email) > 0 {
+		return claims.Email
+	}
+	return claims.Subject
+}
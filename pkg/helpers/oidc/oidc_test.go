@@ -0,0 +1,150 @@
+// Copyright Contributors to the Open Cluster Management project
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// issuer is a fake OIDC issuer answering the token requests with the responses in order
+type issuer struct {
+	*httptest.Server
+	tokenResponses []map[string]string
+	polls          int
+}
+
+func newIssuer(t *testing.T, deviceAuthorization bool, tokenResponses ...map[string]string) *issuer {
+	i := &issuer{tokenResponses: tokenResponses}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		provider := Provider{Issuer: i.URL, TokenEndpoint: i.URL + "/token"}
+		if deviceAuthorization {
+			provider.DeviceAuthorizationEndpoint = i.URL + "/device"
+		}
+		_ = json.NewEncoder(w).Encode(provider)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "clusteradm" || r.FormValue("scope") != "openid offline_access" {
+			t.Errorf("unexpected device authorization request %v", r.Form)
+		}
+		_ = json.NewEncoder(w).Encode(DeviceAuthorization{
+			DeviceCode: "device-code", UserCode: "ABCD-EFGH", VerificationURI: i.URL + "/activate", ExpiresIn: 60, Interval: 1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != deviceCodeGrantType || r.FormValue("device_code") != "device-code" {
+			t.Errorf("unexpected token request %v", r.Form)
+		}
+		response := i.tokenResponses[i.polls]
+		i.polls++
+		if _, ok := response["error"]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	i.Server = httptest.NewServer(mux)
+	return i
+}
+
+func TestLogin(t *testing.T) {
+	second = time.Millisecond
+	defer func() { second = time.Second }()
+
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1234","email":"alice@example.com"}`))
+	idToken := "header." + claims + ".signature"
+
+	cases := []struct {
+		name                string
+		deviceAuthorization bool
+		tokenResponses      []map[string]string
+		expectedErr         bool
+		expectedPolls       int
+	}{
+		{
+			name:                "approved",
+			deviceAuthorization: true,
+			tokenResponses: []map[string]string{
+				{"error": "authorization_pending"},
+				{"error": "slow_down"},
+				{"id_token": idToken, "refresh_token": "refresh"},
+			},
+			expectedPolls: 3,
+		},
+		{
+			name:                "denied",
+			deviceAuthorization: true,
+			tokenResponses:      []map[string]string{{"error": "authorization_pending"}, {"error": "access_denied"}},
+			expectedErr:         true,
+			expectedPolls:       2,
+		},
+		{
+			name:                "no id token",
+			deviceAuthorization: true,
+			tokenResponses:      []map[string]string{{"access_token": "access"}},
+			expectedErr:         true,
+			expectedPolls:       1,
+		},
+		{
+			name:        "device authorization not supported",
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			i := newIssuer(t, c.deviceAuthorization, c.tokenResponses...)
+			defer i.Close()
+			client := &Client{ID: "clusteradm", Scopes: []string{"openid", "offline_access"}}
+
+			token, err := func() (*Token, error) {
+				provider, err := client.Discover(context.TODO(), i.URL)
+				if err != nil {
+					return nil, err
+				}
+				auth, err := client.Authorize(context.TODO(), provider)
+				if err != nil {
+					return nil, err
+				}
+				return client.PollToken(context.TODO(), provider, auth)
+			}()
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("expected error %t, got %v", c.expectedErr, err)
+			}
+			if i.polls != c.expectedPolls {
+				t.Errorf("expected %d polls, got %d", c.expectedPolls, i.polls)
+			}
+			if err != nil {
+				return
+			}
+			if token.RefreshToken != "refresh" || Subject(token.IDToken) != "alice@example.com" {
+				t.Errorf("unexpected token %+v", token)
+			}
+			config := AuthProviderConfig(i.URL, client, nil, token)
+			if config["idp-issuer-url"] != i.URL || config["client-id"] != "clusteradm" || config["id-token"] != idToken ||
+				config["refresh-token"] != "refresh" || len(config["client-secret"]) > 0 {
+				t.Errorf("unexpected auth provider config %v", config)
+			}
+		})
+	}
+}
+
+func TestSubject(t *testing.T) {
+	encode := func(claims string) string {
+		return "header." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+	}
+	cases := map[string]string{
+		encode(`{"sub":"1234","email":"alice@example.com"}`): "alice@example.com",
+		encode(`{"sub":"1234"}`):                             "1234",
+		"not-a-jwt":                                          "",
+		"header.!!!.signature":                               "",
+	}
+	for idToken, expected := range cases {
+		if subject := Subject(idToken); subject != expected {
+			t.Errorf("expected subject %q of %s, got %q", expected, idToken, subject)
+		}
+	}
+}
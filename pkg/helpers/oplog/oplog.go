@@ -0,0 +1,151 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package oplog keeps a log of the join, unjoin and upgrade runs in a ConfigMap of the agent namespace of the
+// managed cluster, to find out later who joined the cluster with which settings.
+package oplog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// ConfigMapName is the ConfigMap of the log in the agent namespace
+	ConfigMapName = "clusteradm-operations"
+	// dataKey is the key of the operations in the ConfigMap
+	dataKey = "operations"
+	// maxOperations is the number of operations kept, the oldest ones are dropped
+	maxOperations = 20
+
+	ResultSucceeded = "Succeeded"
+	ResultFailed    = "Failed"
+)
+
+// Operation is a run of join, unjoin or upgrade on the managed cluster
+type Operation struct {
+	Command       string `json:"command"`
+	BundleVersion string `json:"bundleVersion,omitempty"`
+	// Flags are the flags set on the command line, the values of the secrets are masked
+	Flags     map[string]string `json:"flags,omitempty"`
+	Result    string            `json:"result"`
+	Error     string            `json:"error,omitempty"`
+	StartTime time.Time         `json:"startTime"`
+	EndTime   time.Time         `json:"endTime"`
+}
+
+// NewOperation returns the operation of the command started at start, which ended now with err
+func NewOperation(cmd *cobra.Command, bundleVersion string, start time.Time, err error) Operation {
+	op := Operation{
+		Command:       cmd.CommandPath(),
+		BundleVersion: bundleVersion,
+		Flags:         map[string]string{},
+		Result:        ResultSucceeded,
+		StartTime:     start.UTC(),
+		EndTime:       time.Now().UTC(),
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		op.Flags[flag.Name] = flagValue(flag)
+	})
+	if err != nil {
+		op.Result = ResultFailed
+		op.Error = err.Error()
+	}
+	return op
+}
+
+// flagValue masks the values of the tokens, passwords and secrets
+func flagValue(flag *pflag.Flag) string {
+	for _, sensitive := range []string{"token", "password", "secret"} {
+		if strings.Contains(flag.Name, sensitive) {
+			return "<masked>"
+		}
+	}
+	return flag.Value.String()
+}
+
+// Append adds the operation to the log of the namespace, it is skipped if the namespace does not
+// exist, e.g. after the klusterlet is removed or when the join fails before creating it.
+func Append(kubeClient kubernetes.Interface, namespace string, op Operation) error {
+	if _, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{}); errors.IsNotFound(err) {
+		return nil
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	exists := true
+	switch {
+	case errors.IsNotFound(err):
+		exists = false
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: namespace},
+		}
+	case err != nil:
+		return err
+	}
+
+	ops, err := parse(cm)
+	if err != nil {
+		return err
+	}
+	ops = append(ops, op)
+	if len(ops) > maxOperations {
+		ops = ops[len(ops)-maxOperations:]
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	cm.Data = map[string]string{dataKey: string(data)}
+
+	if !exists {
+		_, err = kubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+		return err
+	}
+	_, err = kubeClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	return err
+}
+
+// Record appends the operation to the log of the namespace of the cluster of the factory, a failure is
+// printed as a warning as the command already ran
+func Record(f cmdutil.Factory, namespace string, op Operation, errOut io.Writer) {
+	kubeClient, err := f.KubernetesClientSet()
+	if err == nil {
+		err = Append(kubeClient, namespace, op)
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "WARNING: failed to record the operation in ConfigMap %s/%s: %v\n", namespace, ConfigMapName, err)
+	}
+}
+
+// List returns the operations of the log of the namespace from the oldest one, nil if there is no log
+func List(kubeClient kubernetes.Interface, namespace string) ([]Operation, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return parse(cm)
+}
+
+func parse(cm *corev1.ConfigMap) ([]Operation, error) {
+	ops := []Operation{}
+	if len(cm.Data[dataKey]) == 0 {
+		return ops, nil
+	}
+	if err := json.Unmarshal([]byte(cm.Data[dataKey]), &ops); err != nil {
+		return nil, fmt.Errorf("invalid operations in ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	return ops, nil
+}
@@ -0,0 +1,65 @@
+// Copyright Contributors to the Open Cluster Management project
+package oplog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewOperation(t *testing.T) {
+	cmd := &cobra.Command{Use: "join"}
+	cmd.Flags().String("hub-token", "", "")
+	cmd.Flags().String("cluster-name", "", "")
+	cmd.Flags().Bool("wait", false, "")
+	_ = cmd.Flags().Set("hub-token", "abc.def")
+	_ = cmd.Flags().Set("cluster-name", "cluster1")
+
+	op := NewOperation(cmd, "v0.6.0", time.Now(), errors.New("timeout"))
+	if op.Command != "join" || op.BundleVersion != "v0.6.0" || op.Result != ResultFailed || op.Error != "timeout" {
+		t.Errorf("unexpected operation %+v", op)
+	}
+	expectedFlags := map[string]string{"hub-token": "<masked>", "cluster-name": "cluster1"}
+	if fmt.Sprint(op.Flags) != fmt.Sprint(expectedFlags) {
+		t.Errorf("expected flags %v, got %v", expectedFlags, op.Flags)
+	}
+	if op := NewOperation(cmd, "", time.Now(), nil); op.Result != ResultSucceeded {
+		t.Errorf("expected a succeeded operation, got %+v", op)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	namespace := "open-cluster-management-agent"
+
+	// the namespace does not exist
+	kubeClient := fakekube.NewSimpleClientset()
+	if err := Append(kubeClient, namespace, Operation{Command: "clusteradm unjoin"}); err != nil {
+		t.Fatal(err)
+	}
+	if ops, err := List(kubeClient, namespace); err != nil || ops != nil {
+		t.Errorf("expected no log, got %v %v", ops, err)
+	}
+
+	kubeClient = fakekube.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+	for i := 0; i < maxOperations+2; i++ {
+		if err := Append(kubeClient, namespace, Operation{Command: fmt.Sprintf("clusteradm join %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ops, err := List(kubeClient, namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != maxOperations {
+		t.Fatalf("expected %d operations, got %d", maxOperations, len(ops))
+	}
+	if ops[0].Command != "clusteradm join 2" || ops[maxOperations-1].Command != fmt.Sprintf("clusteradm join %d", maxOperations+1) {
+		t.Errorf("expected the oldest operations to be dropped, got %s to %s", ops[0].Command, ops[maxOperations-1].Command)
+	}
+}
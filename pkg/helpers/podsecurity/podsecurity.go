@@ -0,0 +1,142 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package podsecurity checks the pod security restrictions of the namespaces the pods are deployed to,
+// the Pod Security Admission levels and the security context constraints of OpenShift.
+package podsecurity
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// EnforceLabel is the label of the pod security level enforced on the pods of a namespace
+	EnforceLabel = "pod-security.kubernetes.io/enforce"
+
+	LevelPrivileged = "privileged"
+	LevelBaseline   = "baseline"
+	LevelRestricted = "restricted"
+
+	// restrictedV2SCC is the security context constraints allowing the RuntimeDefault seccomp
+	// profile, the ones of OpenShift before 4.11 reject any seccomp profile.
+	restrictedV2SCC = "restricted-v2"
+)
+
+var sccGVR = schema.GroupVersionResource{
+	Group:    "security.openshift.io",
+	Version:  "v1",
+	Resource: "securitycontextconstraints",
+}
+
+// Check verifies the pods satisfying the restricted level can be deployed to the namespaces.
+// After the check, SeccompProfile is set if the seccomp profile of the pods can be set.
+type Check struct {
+	KubeClient    kubernetes.Interface
+	DynamicClient dynamic.Interface
+	// Namespaces the pods are deployed to, the missing ones are ignored
+	Namespaces []string
+
+	// SeccompProfile is the result of the check, the RuntimeDefault seccomp profile is set on the pods
+	// if it is true. It is false on OpenShift clusters whose constraints reject the seccomp profiles.
+	SeccompProfile bool
+	// OpenShift is the result of the check, the user of the pods is set by the constraints if it is true
+	OpenShift bool
+}
+
+func (c *Check) Check() (warnings []string, errorList []error) {
+	var err error
+	c.OpenShift, c.SeccompProfile, err = Detect(c.KubeClient, c.DynamicClient)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to detect the security context constraints: %v", err)}, nil
+	}
+
+	for _, name := range c.Namespaces {
+		ns, err := c.KubeClient.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to get namespace %s: %v", name, err))
+			continue
+		}
+		warning, err := CheckLevel(name, ns.Labels[EnforceLabel], c.SeccompProfile)
+		if len(warning) > 0 {
+			warnings = append(warnings, warning)
+		}
+		if err != nil {
+			errorList = append(errorList, err)
+		}
+	}
+	return warnings, errorList
+}
+
+func (c *Check) Name() string {
+	return "PodSecurity check"
+}
+
+// SecurityContext returns the security context of a container satisfying the restricted level, the user is
+// set by the constraints on OpenShift clusters and by the image otherwise.
+func (c *Check) SecurityContext(runAsUser int64) *corev1.SecurityContext {
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: boolPtr(false),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		Privileged:   boolPtr(false),
+		RunAsNonRoot: boolPtr(true),
+	}
+	if !c.OpenShift {
+		securityContext.RunAsUser = &runAsUser
+	}
+	if c.SeccompProfile {
+		securityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	return securityContext
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// CheckLevel returns an error if the pods can not satisfy the level enforced on the namespace,
+// they satisfy the restricted level only with the seccomp profile.
+func CheckLevel(namespace, level string, seccompProfile bool) (warning string, err error) {
+	switch level {
+	case "", LevelPrivileged, LevelBaseline:
+		return "", nil
+	case LevelRestricted:
+		if seccompProfile {
+			return "", nil
+		}
+		return "", fmt.Errorf("namespace %s enforces the %s pod security level which requires a seccomp profile, but the security "+
+			"context constraints of the cluster reject it. Label the namespace with %s=%s", namespace, LevelRestricted, EnforceLabel, LevelBaseline)
+	}
+	return fmt.Sprintf("namespace %s enforces the unknown pod security level %q", namespace, level), nil
+}
+
+// Detect returns if the cluster is an OpenShift cluster and if the pods can set the RuntimeDefault seccomp profile
+func Detect(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface) (openShift, seccompProfile bool, err error) {
+	_, err = kubeClient.Discovery().ServerResourcesForGroupVersion(sccGVR.GroupVersion().String())
+	if errors.IsNotFound(err) {
+		return false, true, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	_, err = dynamicClient.Resource(sccGVR).Get(context.TODO(), restrictedV2SCC, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return true, false, nil
+	}
+	if err != nil {
+		return true, false, err
+	}
+	return true, true, nil
+}
@@ -0,0 +1,110 @@
+// Copyright Contributors to the Open Cluster Management project
+package podsecurity
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	testinghelper "open-cluster-management.io/clusteradm/pkg/helpers/testing"
+)
+
+func newNamespace(name, level string) runtime.Object {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if len(level) > 0 {
+		ns.Labels = map[string]string{EnforceLabel: level}
+	}
+	return ns
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		namespaces   []runtime.Object
+		wantWarnings []string
+	}{
+		{
+			name: "missing namespaces",
+		},
+		{
+			name:       "restricted namespaces",
+			namespaces: []runtime.Object{newNamespace("ns1", LevelRestricted), newNamespace("ns2", "")},
+		},
+		{
+			name:         "unknown level",
+			namespaces:   []runtime.Object{newNamespace("ns1", "strict")},
+			wantWarnings: []string{`namespace ns1 enforces the unknown pod security level "strict"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &Check{
+				KubeClient: fakekube.NewSimpleClientset(tt.namespaces...),
+				Namespaces: []string{"ns1", "ns2"},
+			}
+			warnings, errs := check.Check()
+			testinghelper.AssertWarnings(t, warnings, tt.wantWarnings)
+			testinghelper.AssertErrors(t, errs, nil)
+			if check.OpenShift || !check.SeccompProfile {
+				t.Errorf("expected a cluster without security context constraints, got openshift %v, seccomp profile %v",
+					check.OpenShift, check.SeccompProfile)
+			}
+		})
+	}
+}
+
+func TestCheckLevel(t *testing.T) {
+	tests := []struct {
+		level          string
+		seccompProfile bool
+		wantWarning    string
+		wantErr        error
+	}{
+		{level: "", seccompProfile: false},
+		{level: LevelPrivileged, seccompProfile: false},
+		{level: LevelBaseline, seccompProfile: false},
+		{level: LevelRestricted, seccompProfile: true},
+		{
+			level:          LevelRestricted,
+			seccompProfile: false,
+			wantErr: fmt.Errorf("namespace ns enforces the restricted pod security level which requires a seccomp profile, but the security " +
+				"context constraints of the cluster reject it. Label the namespace with pod-security.kubernetes.io/enforce=baseline"),
+		},
+		{level: "strict", seccompProfile: true, wantWarning: `namespace ns enforces the unknown pod security level "strict"`},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%v", tt.level, tt.seccompProfile), func(t *testing.T) {
+			warning, err := CheckLevel("ns", tt.level, tt.seccompProfile)
+			if warning != tt.wantWarning {
+				t.Errorf("expected warning %q, got %q", tt.wantWarning, warning)
+			}
+			var errs []error
+			if err != nil {
+				errs = append(errs, err)
+			}
+			var wantErrs []error
+			if tt.wantErr != nil {
+				wantErrs = append(wantErrs, tt.wantErr)
+			}
+			testinghelper.AssertErrors(t, errs, wantErrs)
+		})
+	}
+}
+
+func TestSecurityContext(t *testing.T) {
+	securityContext := (&Check{SeccompProfile: true}).SecurityContext(100)
+	if securityContext.RunAsUser == nil || *securityContext.RunAsUser != 100 {
+		t.Errorf("expected the user 100, got %v", securityContext.RunAsUser)
+	}
+	if securityContext.SeccompProfile == nil || securityContext.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Errorf("expected the RuntimeDefault seccomp profile, got %v", securityContext.SeccompProfile)
+	}
+
+	securityContext = (&Check{OpenShift: true}).SecurityContext(100)
+	if securityContext.RunAsUser != nil || securityContext.SeccompProfile != nil {
+		t.Errorf("expected no user and seccomp profile on OpenShift, got %v %v", securityContext.RunAsUser, securityContext.SeccompProfile)
+	}
+}
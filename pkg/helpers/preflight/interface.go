@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"time"
 )
 
 // Checker validates the state of the cluster to ensure
@@ -26,18 +27,44 @@ func (e *Error) Preflight() bool {
 	return true
 }
 
+// Result is the outcome of a check
+type Result struct {
+	Name     string
+	Warnings []string
+	Errors   []string
+	Duration time.Duration
+}
+
+// Run runs each check and returns their results in order
+func Run(checks []Checker) []Result {
+	results := []Result{}
+	for _, check := range checks {
+		start := time.Now()
+		warnings, errs := check.Check()
+		result := Result{Name: check.Name(), Warnings: warnings, Duration: time.Since(start)}
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 // RunChecks runs each check, display it's check/errors,
 // and once all are processed will exist if any errors occured.
 func RunChecks(checks []Checker, ww io.Writer) error {
+	return printResults(Run(checks), ww)
+}
+
+// printResults displays the warnings of the results and returns an error listing their errors, if any
+func printResults(results []Result, ww io.Writer) error {
 	var errsBuffer bytes.Buffer
-	for _, check := range checks {
-		name := check.Name()
-		warnings, errs := check.Check()
-		for _, warning := range warnings {
-			_, _ = io.WriteString(ww, fmt.Sprintf("\t[WARNING %s]: %v\n", name, warning))
+	for _, result := range results {
+		for _, warning := range result.Warnings {
+			_, _ = io.WriteString(ww, fmt.Sprintf("\t[WARNING %s]: %v\n", result.Name, warning))
 		}
-		for _, err := range errs {
-			_, _ = errsBuffer.WriteString(fmt.Sprintf("\t[ERROR %s]: %v\n", name, err.Error()))
+		for _, err := range result.Errors {
+			_, _ = errsBuffer.WriteString(fmt.Sprintf("\t[ERROR %s]: %v\n", result.Name, err))
 		}
 	}
 	if errsBuffer.Len() > 0 {
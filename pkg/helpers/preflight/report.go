@@ -0,0 +1,214 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"open-cluster-management.io/clusteradm"
+)
+
+const (
+	ReportFormatJUnit = "junit"
+	ReportFormatSARIF = "sarif"
+
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "clusteradm"
+	toolURI      = "https://github.com/open-cluster-management-io/clusteradm"
+)
+
+// ReportOptions writes the results of the preflight checks to a file, for the CI dashboards
+type ReportOptions struct {
+	// Format is junit or sarif, junit by default if File is set
+	Format string
+	File   string
+}
+
+func (r *ReportOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&r.Format, "report-format", "", "The format of the report of the preflight checks, junit or sarif. Only used with --report-file")
+	fs.StringVar(&r.File, "report-file", "", "The file the results of the preflight checks are written to, in --report-format, "+
+		"for the test dashboards of the CI pipelines")
+}
+
+func (r *ReportOptions) Validate() error {
+	switch {
+	case len(r.Format) > 0 && len(r.File) == 0:
+		return fmt.Errorf("--report-file is required with --report-format")
+	case len(r.Format) == 0 && len(r.File) > 0:
+		r.Format = ReportFormatJUnit
+	}
+	if len(r.Format) > 0 && r.Format != ReportFormatJUnit && r.Format != ReportFormatSARIF {
+		return fmt.Errorf("invalid --report-format %s, it should be junit or sarif", r.Format)
+	}
+	return nil
+}
+
+// RunChecks runs the checks of the command like RunChecks, and writes their results to the report file if set
+func (r *ReportOptions) RunChecks(command string, checks []Checker, ww io.Writer) error {
+	results := Run(checks)
+	if len(r.File) > 0 {
+		f, err := os.OpenFile(r.File, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if err := WriteReport(f, r.Format, command, results); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return printResults(results, ww)
+}
+
+// WriteReport writes the results of the preflight checks of the command in the format
+func WriteReport(w io.Writer, format, command string, results []Result) error {
+	switch format {
+	case ReportFormatJUnit:
+		return writeJUnit(w, command, results)
+	case ReportFormatSARIF:
+		return writeSARIF(w, results)
+	}
+	return fmt.Errorf("unknown report format %s", format)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	// SystemOut holds the warnings of the check
+	SystemOut string `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a test suite named after the command, with a test case per check
+func writeJUnit(w io.Writer, command string, results []Result) error {
+	suite := junitTestSuite{Name: fmt.Sprintf("%s %s preflight", toolName, command)}
+	total := 0.0
+	for _, result := range results {
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: "preflight." + command,
+			Time:      fmt.Sprintf("%.3f", result.Duration.Seconds()),
+			SystemOut: strings.Join(result.Warnings, "\n"),
+		}
+		if len(result.Errors) > 0 {
+			testCase.Failure = &junitFailure{Message: result.Errors[0], Text: strings.Join(result.Errors, "\n")}
+			suite.Failures++
+		}
+		total += result.Duration.Seconds()
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(results)
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID string `json:"ruleId"`
+	// Kind is pass for the checks without warnings and errors, fail otherwise
+	Kind    string       `json:"kind"`
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// writeSARIF writes a run with a rule per check and a result per warning and error, the checks without
+// any have a pass result
+func writeSARIF(w io.Writer, results []Result) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			Version:        strings.TrimSpace(clusteradm.GetVersion()),
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, result := range results {
+		id := ruleID(result.Name)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, Name: result.Name})
+		for _, err := range result.Errors {
+			run.Results = append(run.Results, sarifResult{RuleID: id, Kind: "fail", Level: "error", Message: sarifMessage{Text: err}})
+		}
+		for _, warning := range result.Warnings {
+			run.Results = append(run.Results, sarifResult{RuleID: id, Kind: "fail", Level: "warning", Message: sarifMessage{Text: warning}})
+		}
+		if len(result.Errors) == 0 && len(result.Warnings) == 0 {
+			run.Results = append(run.Results, sarifResult{RuleID: id, Kind: "pass", Level: "none", Message: sarifMessage{Text: result.Name + " passed"}})
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// ruleID returns the id of the rule of the check, e.g. HubApiServer check is HubApiServerCheck
+func ruleID(name string) string {
+	return strings.Join(strings.Fields(strings.Title(name)), "")
+}
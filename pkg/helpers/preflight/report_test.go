@@ -0,0 +1,103 @@
+// Copyright Contributors to the Open Cluster Management project
+package preflight
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type fakeCheck struct {
+	name     string
+	warnings []string
+	errs     []error
+}
+
+func (c fakeCheck) Check() ([]string, []error) { return c.warnings, c.errs }
+func (c fakeCheck) Name() string               { return c.name }
+
+var checks = []Checker{
+	fakeCheck{name: "HubApiServer check"},
+	fakeCheck{name: "Architecture check", warnings: []string{"no node runs arm64"}},
+	fakeCheck{name: "ClusterInfo check", errs: []error{fmt.Errorf("cluster-info not found")}},
+}
+
+func TestWriteJUnit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteReport(buf, ReportFormatJUnit, "join", Run(checks)); err != nil {
+		t.Fatal(err)
+	}
+	suites := junitTestSuites{}
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid junit report: %v\n%s", err, buf.String())
+	}
+	suite := suites.Suites[0]
+	if suite.Name != "clusteradm join preflight" || suite.Tests != 3 || suite.Failures != 1 {
+		t.Errorf("unexpected suite %+v", suite)
+	}
+	if suite.Cases[0].Failure != nil || suite.Cases[1].SystemOut != "no node runs arm64" {
+		t.Errorf("unexpected test cases %+v", suite.Cases)
+	}
+	if failure := suite.Cases[2].Failure; failure == nil || failure.Message != "cluster-info not found" {
+		t.Errorf("expected the failure of the ClusterInfo check, got %+v", failure)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteReport(buf, ReportFormatSARIF, "join", Run(checks)); err != nil {
+		t.Fatal(err)
+	}
+	log := sarifLog{}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid sarif report: %v\n%s", err, buf.String())
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != 3 {
+		t.Fatalf("unexpected sarif log %+v", log)
+	}
+	levels := []string{}
+	for _, result := range log.Runs[0].Results {
+		levels = append(levels, result.RuleID+":"+result.Level)
+	}
+	expected := "HubApiServerCheck:none ArchitectureCheck:warning ClusterInfoCheck:error"
+	if got := strings.Join(levels, " "); got != expected {
+		t.Errorf("expected results %q, got %q", expected, got)
+	}
+}
+
+func TestReportRunChecks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "report.xml")
+	r := &ReportOptions{File: file}
+	if err := r.Validate(); err != nil || r.Format != ReportFormatJUnit {
+		t.Fatalf("expected junit by default, got %q, %v", r.Format, err)
+	}
+	out := &bytes.Buffer{}
+	err := r.RunChecks("init", checks, out)
+	if _, ok := err.(*Error); !ok {
+		t.Errorf("expected a preflight error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "[WARNING Architecture check]: no node runs arm64") {
+		t.Errorf("expected the warnings to be printed, got %q", out.String())
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `<testsuite name="clusteradm init preflight"`) {
+		t.Errorf("expected the junit report to be written, got %s", data)
+	}
+}
+
+func TestReportValidate(t *testing.T) {
+	if err := (&ReportOptions{Format: ReportFormatSARIF}).Validate(); err == nil {
+		t.Errorf("expected an error without --report-file")
+	}
+	if err := (&ReportOptions{Format: "html", File: "out.html"}).Validate(); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright Contributors to the Open Cluster Management project
+package printer
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+var statusColors = map[string]*color.Color{
+	string(metav1.ConditionTrue):    color.New(color.FgGreen),
+	string(metav1.ConditionFalse):   color.New(color.FgRed),
+	string(metav1.ConditionUnknown): color.New(color.FgYellow),
+}
+
+// SetupColor disables the colors if --no-color is set. They are also disabled if the NO_COLOR environment
+// variable is set, TERM is dumb or the output is not a terminal, as in the CI jobs.
+func SetupColor(noColor bool) {
+	if noColor {
+		color.NoColor = true
+	}
+}
+
+// ColorStatus returns the condition status colored, green True, red False and yellow Unknown. Other values
+// are returned as is.
+func ColorStatus(status string) string {
+	if c, ok := statusColors[status]; ok {
+		return c.Sprint(status)
+	}
+	return status
+}
+
+func isStatus(value interface{}) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	_, ok = statusColors[s]
+	return ok
+}
+
+// statusColumns returns the indexes of the printed columns of the table which only have condition statuses
+func statusColumns(table *metav1.Table, wide bool) []int {
+	columns := []int{}
+	for i, column := range table.ColumnDefinitions {
+		if column.Priority != 0 && !wide {
+			continue
+		}
+		found, others := false, false
+		for _, row := range table.Rows {
+			if i >= len(row.Cells) {
+				continue
+			}
+			switch {
+			case isStatus(row.Cells[i]):
+				found = true
+			case row.Cells[i] != "":
+				others = true
+			}
+		}
+		if found && !others {
+			columns = append(columns, i)
+		}
+	}
+	return columns
+}
+
+// printTable prints the table with the cells of its condition status columns colored. The cells are colored
+// once the table is printed, the escape sequences would otherwise be counted in the widths of the columns.
+func (p *PrinterOption) printTable(out io.Writer, table *metav1.Table) error {
+	columns := statusColumns(table, p.Options.Wide)
+	if color.NoColor || p.Options.NoHeaders || p.Options.WithKind || len(columns) == 0 {
+		return p.table.PrintObj(table, out)
+	}
+	buf := &bytes.Buffer{}
+	if err := p.table.PrintObj(table, buf); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, colorColumns(buf.String(), table, columns, p.Options))
+	return err
+}
+
+// colorColumns colors the status cells of the columns, their offsets are the ones of the headers
+func colorColumns(printed string, table *metav1.Table, columns []int, options printers.PrintOptions) string {
+	lines := strings.Split(printed, "\n")
+	if len(lines) < 2 {
+		return printed
+	}
+	header := lines[0]
+	offset := 0
+	if options.WithNamespace {
+		offset = len("NAMESPACE")
+	}
+	starts := map[int]int{}
+	for i, column := range table.ColumnDefinitions {
+		if column.Priority != 0 && !options.Wide {
+			continue
+		}
+		index := strings.Index(header[offset:], strings.ToUpper(column.Name))
+		if index < 0 {
+			return printed
+		}
+		starts[i] = len([]rune(header[:offset+index]))
+		offset += index + len(column.Name)
+	}
+	for l := 1; l < len(lines); l++ {
+		line := []rune(lines[l])
+		// the columns are colored from the last one, the offsets of the others are kept
+		for c := len(columns) - 1; c >= 0; c-- {
+			start := starts[columns[c]]
+			if start >= len(line) {
+				continue
+			}
+			end := start
+			for end < len(line) && line[end] != ' ' {
+				end++
+			}
+			if cell := string(line[start:end]); isStatus(cell) {
+				line = append(line[:start], append([]rune(ColorStatus(cell)), line[end:]...)...)
+			}
+		}
+		lines[l] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
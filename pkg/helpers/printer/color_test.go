@@ -0,0 +1,89 @@
+// Copyright Contributors to the Open Cluster Management project
+package printer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+func newTable() *metav1.Table {
+	return &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Accepted", Type: "boolean"},
+			{Name: "Available", Type: "string"},
+			{Name: "Kubernetes Version", Type: "string"},
+		},
+		Rows: []metav1.TableRow{
+			{Cells: []interface{}{"cluster1", true, "True", "v1.24.0"}},
+			{Cells: []interface{}{"cluster-with-long-name", false, "False", "True"}},
+			{Cells: []interface{}{"cluster3", true, "", "v1.25.1"}},
+		},
+	}
+}
+
+func TestStatusColumns(t *testing.T) {
+	if columns := statusColumns(newTable(), false); !reflect.DeepEqual(columns, []int{2}) {
+		t.Errorf("expected the status columns [2], got %v", columns)
+	}
+}
+
+func TestPrintTable(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+
+	p := NewPrinterOption(printers.PrintOptions{})
+	p.Competele()
+
+	color.NoColor = true
+	plain := &bytes.Buffer{}
+	if err := p.printTable(plain, newTable()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("expected no color, got %q", plain.String())
+	}
+
+	color.NoColor = false
+	// the headers are printed once by a table printer
+	p.Competele()
+	colored := &bytes.Buffer{}
+	if err := p.printTable(colored, newTable()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(colored.String(), "\n")
+	if !strings.Contains(lines[1], ColorStatus("True")+"   ") || !strings.Contains(lines[2], ColorStatus("False")+"  ") {
+		t.Errorf("expected the available cells to be colored, got %q", colored.String())
+	}
+	// the version column is not a status column
+	if strings.Contains(lines[2], ColorStatus("True")) {
+		t.Errorf("expected the version cell not to be colored, got %q", lines[2])
+	}
+	// the cells are aligned as without colors
+	stripped := strings.NewReplacer("\x1b[32m", "", "\x1b[31m", "", "\x1b[0m", "").Replace(colored.String())
+	if stripped != plain.String() {
+		t.Errorf("expected the colored table to be aligned as %q, got %q", plain.String(), stripped)
+	}
+}
+
+func TestTreeColor(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+	color.NoColor = false
+
+	tree := NewTreePrinter("ManagedCluster")
+	tree.AddFileds("cluster1", &map[string]interface{}{".Available": "Unknown", ".Version": "v1.24.0"})
+	out := &bytes.Buffer{}
+	if err := tree.Print(out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "<Available> "+ColorStatus("Unknown")) || strings.Contains(out.String(), "\x1b[33mv1") {
+		t.Errorf("unexpected tree %q", out.String())
+	}
+}
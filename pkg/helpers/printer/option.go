@@ -30,17 +30,19 @@ func NewPrinterOption(o printers.PrintOptions) *PrinterOption {
 }
 
 func (p *PrinterOption) AddFlag(fs *pflag.FlagSet) {
-	fs.StringVarP(&p.Format, "output", "o", "tree", "output format can be tree, table or yaml")
+	fs.StringVarP(&p.Format, "output", "o", "tree", "output format can be tree, table, wide or yaml")
 }
 
 func (p *PrinterOption) Competele() {
+	// wide is the table with the columns of non zero priority
+	p.Options.Wide = p.Format == "wide"
 	p.tree = NewTreePrinter(p.Options.Kind.Kind)
 	p.table = printers.NewTablePrinter(p.Options)
 	p.yaml = printers.YAMLPrinter{}
 }
 
 func (p *PrinterOption) Validate() error {
-	if p.Format != "tree" && p.Format != "table" && p.Format != "yaml" {
+	if p.Format != "tree" && p.Format != "table" && p.Format != "wide" && p.Format != "yaml" {
 		return fmt.Errorf("invalid output format")
 	}
 	return nil
@@ -60,8 +62,8 @@ func (p *PrinterOption) Print(stream genericclioptions.IOStreams, obj runtime.Ob
 	case "tree":
 		p.tree = *p.treeConverter(obj, &p.tree)
 		return p.tree.Print(stream.Out)
-	case "table":
-		return p.table.PrintObj(p.tableConverter(obj), stream.Out)
+	case "table", "wide":
+		return p.printTable(stream.Out, p.tableConverter(obj))
 	case "yaml":
 		objs, err := meta.ExtractList(obj)
 		if err != nil {
@@ -0,0 +1,110 @@
+// Copyright Contributors to the Open Cluster Management project
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/moby/term"
+)
+
+// Progress is a table of rows updated while a command runs. On a terminal the table is printed again
+// in place on each update, otherwise a row is printed each time it changes.
+type Progress struct {
+	mu      sync.Mutex
+	out     io.Writer
+	live    bool
+	headers []string
+	keys    []string
+	rows    map[string][]string
+	// lines is the number of lines of the last printed table
+	lines int
+}
+
+// NewProgress returns a progress table of the headers printed on out
+func NewProgress(out io.Writer, headers ...string) *Progress {
+	_, live := term.GetFdInfo(out)
+	return &Progress{
+		out:     out,
+		live:    live,
+		headers: headers,
+		rows:    map[string][]string{},
+	}
+}
+
+// Update sets the cells of the row of the key, the rows are printed in the order they are first updated
+func (p *Progress) Update(key string, cells ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	previous, ok := p.rows[key]
+	if !ok {
+		p.keys = append(p.keys, key)
+	} else if equal(previous, cells) {
+		return
+	}
+	p.rows[key] = cells
+
+	widths := p.widths()
+	if !p.live {
+		if p.lines == 0 {
+			fmt.Fprintln(p.out, format(p.headers, widths))
+			p.lines = 1
+		}
+		fmt.Fprintln(p.out, format(cells, widths))
+		return
+	}
+	if p.lines > 0 {
+		// moves the cursor up to the first line of the table and clears it
+		fmt.Fprintf(p.out, "\x1b[%dA\x1b[J", p.lines)
+	}
+	fmt.Fprintln(p.out, format(p.headers, widths))
+	for _, k := range p.keys {
+		fmt.Fprintln(p.out, format(p.rows[k], widths))
+	}
+	p.lines = len(p.keys) + 1
+}
+
+func (p *Progress) widths() []int {
+	widths := make([]int, len(p.headers))
+	for i, header := range p.headers {
+		widths[i] = len(header)
+	}
+	for _, row := range p.rows {
+		for i, cell := range row {
+			if i < len(widths) && len([]rune(cell)) > widths[i] {
+				widths[i] = len([]rune(cell))
+			}
+		}
+	}
+	return widths
+}
+
+// format pads the cells to the widths of their columns, the condition statuses are colored once padded
+func format(cells []string, widths []int) string {
+	columns := make([]string, len(cells))
+	for i, cell := range cells {
+		padding := 0
+		if i < len(widths) && i < len(cells)-1 {
+			padding = widths[i] - len([]rune(cell))
+		}
+		if isStatus(cell) {
+			cell = ColorStatus(cell)
+		}
+		columns[i] = cell + strings.Repeat(" ", padding)
+	}
+	return strings.Join(columns, "   ")
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+// Copyright Contributors to the Open Cluster Management project
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	out := &bytes.Buffer{}
+	progress := NewProgress(out, "CLUSTER", "STATUS")
+	progress.Update("cluster1", "cluster1", "Applying")
+	progress.Update("cluster10", "cluster10", "Applying")
+	progress.Update("cluster10", "cluster10", "Applying")
+	progress.Update("cluster1", "cluster1", "Applied")
+
+	// the output is not a terminal, the rows are printed when they change
+	expected := "CLUSTER    STATUS\n" +
+		"cluster1   Applying\n" +
+		"cluster10   Applying\n" +
+		"cluster1    Applied\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestProgressLive(t *testing.T) {
+	out := &bytes.Buffer{}
+	progress := &Progress{out: out, live: true, headers: []string{"CLUSTER", "STATUS"}, rows: map[string][]string{}}
+	progress.Update("cluster1", "cluster1", "Applying")
+	progress.Update("cluster2", "cluster2", "Applied")
+
+	// the table is printed again in place
+	expected := "CLUSTER    STATUS\ncluster1   Applying\n" +
+		"\x1b[2A\x1b[JCLUSTER    STATUS\ncluster1   Applying\ncluster2   Applied\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
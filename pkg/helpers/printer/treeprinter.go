@@ -4,6 +4,7 @@ package printer
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/disiqueira/gotree"
@@ -51,15 +52,25 @@ func (t *TreePrinter) build() gotree.Tree {
 		if root.value == nil {
 			root.value = ""
 		}
-		line := fmt.Sprintf("<%s> %v", part, root.value)
+		value := root.value
+		if isStatus(value) {
+			value = ColorStatus(value.(string))
+		}
+		line := fmt.Sprintf("<%s> %v", part, value)
 		level0 := gotree.New(line)
 
 		if root.isLeaf() {
 			return level0
 		}
 
-		for key, value := range root.children {
-			level0.AddTree(dfs(strings.TrimPrefix(key, "."), value))
+		// the children are printed in order, the output is the same on each run
+		keys := make([]string, 0, len(root.children))
+		for key := range root.children {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			level0.AddTree(dfs(strings.TrimPrefix(key, "."), root.children[key]))
 		}
 		return level0
 	}
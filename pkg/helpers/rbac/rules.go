@@ -0,0 +1,275 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package rbac holds the rbac rules of the clusteradm commands run on the hub, to grant a
+// serviceaccount the permissions of the commands it runs instead of cluster-admin.
+package rbac
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"open-cluster-management.io/clusteradm/pkg/config"
+	"sigs.k8s.io/yaml"
+)
+
+// operationRules are the rbac rules of the operations on the hub, an operation is a command and its
+// subcommands. The rules are maintained with the api calls of the commands.
+var operationRules = map[string][]rbacv1.PolicyRule{
+	"accept": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclusters"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+		{APIGroups: []string{"register.open-cluster-management.io"}, Resources: []string{"managedclusters/accept"}, Verbs: []string{"update"}},
+		{APIGroups: []string{"certificates.k8s.io"}, Resources: []string{"certificatesigningrequests"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"certificates.k8s.io"}, Resources: []string{"certificatesigningrequests/approval"}, Verbs: []string{"update"}},
+		{APIGroups: []string{"certificates.k8s.io"}, Resources: []string{"signers"}, ResourceNames: []string{"kubernetes.io/kube-apiserver-client"}, Verbs: []string{"approve"}},
+		{APIGroups: []string{"work.open-cluster-management.io"}, Resources: []string{"manifestworks"}, Verbs: []string{"get", "create", "update"}},
+		// the namespace of the bootstrap serviceaccount is read from its binding
+		{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterrolebindings"},
+			ResourceNames: []string{config.BootstrapClusterRoleBindingSAName}, Verbs: []string{"get"}},
+	},
+	"clean orphans": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclusters"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"get", "list", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list", "delete"}},
+		{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"}, Verbs: []string{"get", "list", "delete"}},
+		{APIGroups: []string{"certificates.k8s.io"}, Resources: []string{"certificatesigningrequests"}, Verbs: []string{"get", "list", "delete"}},
+		{APIGroups: []string{"work.open-cluster-management.io"}, Resources: []string{"manifestworks"}, Verbs: []string{"get", "list", "delete"}},
+		{APIGroups: []string{"addon.open-cluster-management.io"}, Resources: []string{"managedclusteraddons"}, Verbs: []string{"get", "list", "delete"}},
+	},
+	"clusterset bind": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersets"}, Verbs: []string{"get"}},
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersets/bind"}, Verbs: []string{"create"}},
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersetbindings"}, Verbs: []string{"create"}},
+	},
+	"clusterset set": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersets"}, Verbs: []string{"get"}},
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersets/join"}, Verbs: []string{"create"}},
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclusters"}, Verbs: []string{"get", "update"}},
+	},
+	"clusterset unbind": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersets"}, Verbs: []string{"get"}},
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersetbindings"}, Verbs: []string{"delete"}},
+	},
+	"create clusterset": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersets"}, Verbs: []string{"get", "create"}},
+	},
+	"create work": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclusters", "placements", "placementdecisions"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{"work.open-cluster-management.io"}, Resources: []string{"manifestworks"}, Verbs: []string{"get", "list", "create", "update", "delete"}},
+	},
+	"delete clusterset": {
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersets"}, Verbs: []string{"get", "watch", "delete"}},
+		{APIGroups: []string{"cluster.open-cluster-management.io"}, Resources: []string{"managedclustersetbindings"}, Verbs: []string{"list"}},
+	},
+	"delete work": {
+		{APIGroups: []string{"work.open-cluster-management.io"}, Resources: []string{"manifestworks"}, Verbs: []string{"get", "list", "watch", "update", "delete"}},
+	},
+	"get access": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles", "clusterrolebindings"}, Verbs: []string{"get", "list"}},
+	),
+	"get addon":                  withReadRules(),
+	"get addon-placement-scores": withReadRules(),
+	"get applications": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"apps.open-cluster-management.io"}, Resources: []string{"*"}, Verbs: []string{"get", "list", "watch"}},
+		rbacv1.PolicyRule{APIGroups: []string{"argoproj.io"}, Resources: []string{"applicationsets"}, Verbs: []string{"get", "list", "watch"}},
+	),
+	"get clusterclaims": withReadRules(),
+	"get clusterpools": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"hive.openshift.io"}, Resources: []string{"clusterpools", "clusterclaims"}, Verbs: []string{"get", "list", "watch"}},
+	),
+	"get clusters": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"get", "list"}},
+	),
+	"get clusterset-usage": withReadRules(),
+	"get clustersets":      withReadRules(),
+	"get csr":              withReadRules(),
+	"get hub-info": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "list"}},
+		rbacv1.PolicyRule{APIGroups: []string{"apiextensions.k8s.io"}, Resources: []string{"customresourcedefinitions"}, Verbs: []string{"get", "list"}},
+	),
+	"get hub-resources": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "list"}},
+		rbacv1.PolicyRule{APIGroups: []string{"apiextensions.k8s.io"}, Resources: []string{"customresourcedefinitions"}, Verbs: []string{"get", "list"}},
+		rbacv1.PolicyRule{APIGroups: []string{"admissionregistration.k8s.io"}, Resources: []string{"validatingwebhookconfigurations", "mutatingwebhookconfigurations"}, Verbs: []string{"get", "list"}},
+		rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets", "serviceaccounts", "services"}, Verbs: []string{"get", "list"}},
+		rbacv1.PolicyRule{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"}, Verbs: []string{"get"}},
+	),
+	"get klusterlet-info": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"work.open-cluster-management.io"}, Resources: []string{"manifestworks"}, Verbs: []string{"get", "create", "delete"}},
+		rbacv1.PolicyRule{APIGroups: []string{"proxy.open-cluster-management.io"}, Resources: []string{"managedproxyconfigurations"}, Verbs: []string{"get"}},
+		rbacv1.PolicyRule{APIGroups: []string{"authentication.open-cluster-management.io"}, Resources: []string{"managedserviceaccounts"}, Verbs: []string{"get"}},
+		rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+	),
+	"get leases": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"get", "list"}},
+	),
+	"get placements": withReadRules(),
+	"get policies": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{"policy.open-cluster-management.io"}, Resources: []string{"policies"}, Verbs: []string{"get", "list", "watch"}},
+	),
+	// get token creates the bootstrap token or serviceaccount and its clusterrole if they do not exist
+	"get token": withReadRules(
+		rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"create", "update"}},
+		rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets", "serviceaccounts"}, Verbs: []string{"get", "list", "create", "update"}},
+		rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+		rbacv1.PolicyRule{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles", "clusterrolebindings"}, Verbs: []string{"get", "create", "update"}},
+		rbacv1.PolicyRule{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"},
+			ResourceNames: []string{"system:open-cluster-management:bootstrap"}, Verbs: []string{"bind", "escalate"}},
+	),
+	"get works": withReadRules(),
+}
+
+// readRules are the rules of the resources read by all the get commands
+var readRules = []rbacv1.PolicyRule{
+	{APIGroups: []string{
+		"cluster.open-cluster-management.io",
+		"work.open-cluster-management.io",
+		"addon.open-cluster-management.io",
+		"operator.open-cluster-management.io",
+	}, Resources: []string{"*"}, Verbs: []string{"get", "list", "watch"}},
+	{APIGroups: []string{"internal.open-cluster-management.io"}, Resources: []string{"managedclusterinfos"}, Verbs: []string{"get", "list", "watch"}},
+	{APIGroups: []string{""}, Resources: []string{"namespaces", "configmaps", "resourcequotas"}, Verbs: []string{"get", "list", "watch"}},
+	{APIGroups: []string{"certificates.k8s.io"}, Resources: []string{"certificatesigningrequests"}, Verbs: []string{"get", "list", "watch"}},
+}
+
+func withReadRules(rules ...rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	return append(append([]rbacv1.PolicyRule{}, readRules...), rules...)
+}
+
+// commandAliases are the aliases of the commands the rules are known for
+var commandAliases = map[string]string{
+	"get addon-placement-score": "get addon-placement-scores",
+	"get addonplacementscores":  "get addon-placement-scores",
+	"get addonplacementscore":   "get addon-placement-scores",
+	"get clusterclaim":          "get clusterclaims",
+	"get clusterpool":           "get clusterpools",
+	"get csrs":                  "get csr",
+}
+
+func init() {
+	// get alone covers all the get commands
+	var rules []rbacv1.PolicyRule
+	for _, operation := range Operations() {
+		if !strings.HasPrefix(operation, "get ") {
+			continue
+		}
+		for _, rule := range operationRules[operation] {
+			if !containsRule(rules, rule) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	operationRules["get"] = rules
+}
+
+// Known returns true if the rules of the command are known, not only the ones of a parent command
+func Known(command string) bool {
+	if alias, ok := commandAliases[command]; ok {
+		command = alias
+	}
+	_, ok := operationRules[command]
+	return ok
+}
+
+// Operations returns the sorted operations the rules are known for
+func Operations() []string {
+	operations := make([]string, 0, len(operationRules))
+	for operation := range operationRules {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+// Subcommands returns the subcommands of the arguments, before the first flag
+func Subcommands(args []string) []string {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i]
+		}
+	}
+	return args
+}
+
+// RulesOf returns the rbac rules of the command run with the arguments, nil if they are unknown
+func RulesOf(args []string) []rbacv1.PolicyRule {
+	subs := Subcommands(args)
+	// the rules of the longest known command prefix
+	for i := len(subs); i > 0; i-- {
+		command := strings.Join(subs[:i], " ")
+		if alias, ok := commandAliases[command]; ok {
+			command = alias
+		}
+		if rules, ok := operationRules[command]; ok {
+			return rules
+		}
+	}
+	return nil
+}
+
+// Rules returns the rbac rules covering all the operations, without the duplicated rules
+func Rules(operations []string) ([]rbacv1.PolicyRule, error) {
+	var rules []rbacv1.PolicyRule
+	for _, operation := range operations {
+		operationRules := RulesOf(strings.Fields(operation))
+		if operationRules == nil {
+			return nil, fmt.Errorf("the rbac of operation %q is unknown, the known operations are %s",
+				operation, strings.Join(Operations(), ", "))
+		}
+		for _, rule := range operationRules {
+			if !containsRule(rules, rule) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules, nil
+}
+
+func containsRule(rules []rbacv1.PolicyRule, rule rbacv1.PolicyRule) bool {
+	for _, r := range rules {
+		if reflect.DeepEqual(r, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterRole returns the clusterrole granting the rules of the command run with the arguments
+func ClusterRole(args []string) (*rbacv1.ClusterRole, error) {
+	subs := Subcommands(args)
+	rules := RulesOf(subs)
+	if rules == nil {
+		return nil, fmt.Errorf("the rbac of command %q is unknown, the known commands are %s",
+			strings.Join(subs, " "), strings.Join(Operations(), ", "))
+	}
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: "clusteradm-" + strings.Join(subs, "-")},
+		Rules:      rules,
+	}, nil
+}
+
+// ShowRequired prints the clusterrole of the command and replaces its run, so the command is not run
+func ShowRequired(cmd *cobra.Command, out io.Writer) error {
+	// the command path starts with the root command
+	clusterRole, err := ClusterRole(strings.Fields(cmd.CommandPath())[1:])
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(clusterRole)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(data); err != nil {
+		return err
+	}
+
+	cmd.PreRun, cmd.PreRunE, cmd.Run = nil, nil, nil
+	cmd.RunE = func(*cobra.Command, []string) error { return nil }
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright Contributors to the Open Cluster Management project
+package rbac
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRulesOf(t *testing.T) {
+	cases := []struct {
+		command  string
+		expected bool
+	}{
+		{command: "accept --clusters cluster1", expected: true},
+		{command: "get clusters -o table", expected: true},
+		{command: "clean orphans --confirm", expected: true},
+		{command: "create work w1 --clusters c1", expected: true},
+		{command: "clean", expected: false},
+		{command: "renew --clusters cluster1", expected: false},
+	}
+	for _, c := range cases {
+		if got := RulesOf(strings.Fields(c.command)) != nil; got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.command, c.expected, got)
+		}
+	}
+}
+
+func TestRulesOfAlias(t *testing.T) {
+	rules := RulesOf([]string{"get", "clusterpool", "--all-namespaces"})
+	if !reflect.DeepEqual(rules, operationRules["get clusterpools"]) {
+		t.Errorf("expected the rules of get clusterpools, got %v", rules)
+	}
+	// get covers all the get commands
+	for _, rule := range operationRules["get token"] {
+		if !containsRule(operationRules["get"], rule) {
+			t.Errorf("expected the rules of get to contain %v", rule)
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	rules, err := Rules([]string{"accept", "get", "accept"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// accept and get share the rule reading the csrs
+	if expected := len(operationRules["accept"]) + len(operationRules["get"]) - 1; len(rules) != expected {
+		t.Errorf("expected %d rules, got %d", expected, len(rules))
+	}
+
+	if _, err := Rules([]string{"accept", "renew"}); err == nil || !strings.Contains(err.Error(), `"renew"`) {
+		t.Errorf("expected an error of the unknown operation, got %v", err)
+	}
+}
+
+func TestShowRequired(t *testing.T) {
+	run := false
+	root := &cobra.Command{Use: "clusteradm"}
+	clusterset := &cobra.Command{Use: "clusterset"}
+	bind := &cobra.Command{Use: "bind", RunE: func(*cobra.Command, []string) error {
+		run = true
+		return nil
+	}}
+	renew := &cobra.Command{Use: "renew", Run: func(*cobra.Command, []string) { run = true }}
+	clusterset.AddCommand(bind)
+	root.AddCommand(clusterset, renew)
+
+	out := &bytes.Buffer{}
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return ShowRequired(cmd, out)
+	}
+	root.SetArgs([]string{"clusterset", "bind", "set1"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if run {
+		t.Errorf("expected the command not to run")
+	}
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(out.Bytes(), clusterRole); err != nil {
+		t.Fatal(err)
+	}
+	if clusterRole.Name != "clusteradm-clusterset-bind" || len(clusterRole.Rules) != len(operationRules["clusterset bind"]) {
+		t.Errorf("unexpected clusterrole %v", clusterRole)
+	}
+
+	root.SetArgs([]string{"renew"})
+	root.SilenceErrors, root.SilenceUsage = true, true
+	if err := root.Execute(); err == nil || run {
+		t.Errorf("expected an error of the unknown rbac, got %v", err)
+	}
+}
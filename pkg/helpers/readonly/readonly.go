@@ -0,0 +1,165 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package readonly refuses the API calls changing the clusters when --read-only is set, to explore a
+// production hub safely or to verify the RBAC of a user impersonated with --as.
+package readonly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clierror"
+)
+
+// enabled is true if the changes of the running command are refused
+var enabled bool
+
+// reviewGroups are the groups of the reviews, they are created without changing the cluster
+var reviewGroups = []string{"authorization.k8s.io", "authentication.k8s.io"}
+
+var verbs = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "patch",
+	http.MethodDelete: "delete",
+}
+
+// Setup refuses the changes of the clients wrapped by WrapConfig if readOnly is true
+func Setup(readOnly bool) {
+	enabled = readOnly
+}
+
+// Error is the change refused
+type Error struct {
+	// Change is the summary of the change, e.g. create configmaps default/config
+	Change string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("read-only mode, refused to %s", e.Change)
+}
+
+// WrapConfig refuses the changes made with the rest config if the read-only mode is set up
+func WrapConfig(config *rest.Config) *rest.Config {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{delegate: rt}
+	})
+	return config
+}
+
+type roundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if enabled && mutating(req) {
+		return nil, clierror.New(clierror.CodeReadOnly, &Error{Change: summary(req)})
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+// mutating returns true if the request changes the cluster, the dry-run requests and the reviews do not
+func mutating(req *http.Request) bool {
+	if _, ok := verbs[req.Method]; !ok {
+		return false
+	}
+	if len(req.URL.Query()["dryRun"]) > 0 {
+		return false
+	}
+	group, _, _, _, _ := parsePath(req.URL.Path)
+	for _, review := range reviewGroups {
+		if req.Method == http.MethodPost && group == review {
+			return false
+		}
+	}
+	return true
+}
+
+// summary returns the change of the request, e.g. create configmaps default/config
+func summary(req *http.Request) string {
+	group, namespace, resource, name, subresource := parsePath(req.URL.Path)
+	if len(resource) == 0 {
+		return fmt.Sprintf("%s %s", req.Method, req.URL.Path)
+	}
+	if len(group) > 0 {
+		resource = resource + "." + group
+	}
+	if len(name) == 0 && req.Method == http.MethodPost {
+		name = nameOfBody(req)
+	}
+	switch {
+	case len(name) == 0 && req.Method == http.MethodDelete:
+		name = "all"
+	case len(name) == 0:
+		name = "<generated>"
+	}
+	if len(namespace) > 0 {
+		name = namespace + "/" + name
+	}
+	change := fmt.Sprintf("%s %s %s", verbs[req.Method], resource, name)
+	if len(subresource) > 0 {
+		change = fmt.Sprintf("%s (%s)", change, subresource)
+	}
+	return change
+}
+
+// parsePath returns the group, namespace, resource, name and subresource of the path of an API request
+func parsePath(path string) (group, namespace, resource, name, subresource string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		group, parts = parts[1], parts[3:]
+	default:
+		return
+	}
+	// the namespace of a namespaced resource, not a namespace itself
+	if len(parts) > 2 && parts[0] == "namespaces" {
+		namespace, parts = parts[1], parts[2:]
+	}
+	if len(parts) > 0 {
+		resource = parts[0]
+	}
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+	if len(parts) > 2 {
+		subresource = strings.Join(parts[2:], "/")
+	}
+	return
+}
+
+// nameOfBody returns the name of the object created, empty if it is not known
+func nameOfBody(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil || !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return ""
+	}
+	object := struct {
+		Metadata struct {
+			Name         string `json:"name"`
+			GenerateName string `json:"generateName"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return ""
+	}
+	if len(object.Metadata.Name) == 0 && len(object.Metadata.GenerateName) > 0 {
+		return object.Metadata.GenerateName + "<generated>"
+	}
+	return object.Metadata.Name
+}
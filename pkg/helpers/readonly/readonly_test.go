@@ -0,0 +1,100 @@
+// Copyright Contributors to the Open Cluster Management project
+package readonly
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"open-cluster-management.io/clusteradm/pkg/helpers/clierror"
+)
+
+func TestSummary(t *testing.T) {
+	cases := []struct {
+		method   string
+		path     string
+		body     string
+		mutating bool
+		expected string
+	}{
+		{method: http.MethodGet, path: "/api/v1/namespaces/default/configmaps/config"},
+		{method: http.MethodPost, path: "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", body: `{}`},
+		{method: http.MethodPatch, path: "/api/v1/namespaces/default/configmaps/config?dryRun=All"},
+		{
+			method: http.MethodPost, path: "/api/v1/namespaces/default/configmaps", body: `{"metadata":{"name":"config"}}`,
+			mutating: true, expected: "create configmaps default/config",
+		},
+		{
+			method: http.MethodPost, path: "/api/v1/namespaces", body: `{"metadata":{"generateName":"ns-"}}`,
+			mutating: true, expected: "create namespaces ns-<generated>",
+		},
+		{
+			method: http.MethodDelete, path: "/api/v1/namespaces/cluster1",
+			mutating: true, expected: "delete namespaces cluster1",
+		},
+		{
+			method: http.MethodPut, path: "/apis/cluster.open-cluster-management.io/v1/managedclusters/cluster1/status",
+			mutating: true, expected: "update managedclusters.cluster.open-cluster-management.io cluster1 (status)",
+		},
+		{
+			method: http.MethodDelete, path: "/apis/work.open-cluster-management.io/v1/namespaces/cluster1/manifestworks",
+			mutating: true, expected: "delete manifestworks.work.open-cluster-management.io cluster1/all",
+		},
+	}
+	for _, c := range cases {
+		req := httptestRequest(t, c.method, c.path, c.body)
+		if mutating(req) != c.mutating {
+			t.Errorf("%s %s: expected mutating %t", c.method, c.path, c.mutating)
+			continue
+		}
+		if c.mutating {
+			if change := summary(req); change != c.expected {
+				t.Errorf("%s %s: expected %q, got %q", c.method, c.path, c.expected, change)
+			}
+		}
+	}
+}
+
+func httptestRequest(t *testing.T, method, path, body string) *http.Request {
+	req, err := http.NewRequest(method, "https://hub:6443"+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestWrapConfig(t *testing.T) {
+	defer Setup(false)
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &roundTripper{delegate: http.DefaultTransport}}
+	_ = WrapConfig(&rest.Config{})
+
+	Setup(false)
+	if _, err := client.Post(server.URL+"/api/v1/namespaces/default/configmaps", "application/json", strings.NewReader(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	Setup(true)
+	if _, err := client.Get(server.URL + "/api/v1/namespaces/default/configmaps"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Post(server.URL+"/api/v1/namespaces/default/configmaps", "application/json", strings.NewReader(`{"metadata":{"name":"config"}}`))
+	readOnlyErr := &Error{}
+	if !errors.As(err, &readOnlyErr) || readOnlyErr.Change != "create configmaps default/config" {
+		t.Fatalf("expected the change to be refused, got %v", err)
+	}
+	typed := &clierror.Error{}
+	if !errors.As(err, &typed) || typed.Code != clierror.CodeReadOnly {
+		t.Errorf("expected the code %s, got %v", clierror.CodeReadOnly, err)
+	}
+	if received != 2 {
+		t.Errorf("expected 2 requests received, got %d", received)
+	}
+}
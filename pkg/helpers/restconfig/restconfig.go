@@ -0,0 +1,21 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package restconfig wraps the rest configs of clusteradm, the ones of the kubeconfig flags as well as the
+// ones built from another kubeconfig, e.g. of the hub, so --read-only, the audit, the stamping and the TLS
+// policy apply to all the clusters.
+package restconfig
+
+import (
+	"k8s.io/client-go/rest"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/readonly"
+	"open-cluster-management.io/clusteradm/pkg/helpers/stamp"
+	"open-cluster-management.io/clusteradm/pkg/helpers/tlspolicy"
+	"open-cluster-management.io/clusteradm/pkg/helpers/tracing"
+)
+
+// Wrap wraps the rest config with the round trippers of clusteradm
+func Wrap(config *rest.Config) *rest.Config {
+	// the TLS policy needs the transport, it is wrapped first
+	return tracing.WrapConfig(readonly.WrapConfig(audit.WrapConfig(stamp.WrapConfig(tlspolicy.WrapConfig(config)))))
+}
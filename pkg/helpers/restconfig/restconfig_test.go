@@ -0,0 +1,29 @@
+// Copyright Contributors to the Open Cluster Management project
+package restconfig
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"open-cluster-management.io/clusteradm/pkg/helpers/readonly"
+)
+
+// TestWrapReadOnly checks the config built from another kubeconfig, e.g. of an external hub, is read-only
+func TestWrapReadOnly(t *testing.T) {
+	readonly.Setup(true)
+	defer readonly.Setup(false)
+
+	transport, err := rest.TransportFor(Wrap(&rest.Config{Host: "https://hub:6443"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://hub:6443/api/v1/namespaces", strings.NewReader(`{"metadata":{"name":"cluster1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transport.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected the write to be refused by --read-only, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package rollback tracks the resources applied by a command, so they can be deleted in reverse
+// order when the command fails and the next attempt starts from a clean cluster.
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// Tracker records the applied resources in the order they are applied
+type Tracker struct {
+	objects []*unstructured.Unstructured
+	// existing are the resources which existed before they were applied, they are never tracked
+	existing map[string]bool
+}
+
+// Track records the resources of the applier output, each output is the yaml of a resource. The resources
+// excluded as existing are not recorded.
+func (t *Tracker) Track(output ...string) error {
+	objects, err := parse(output)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if t.existing[key(obj)] {
+			continue
+		}
+		t.objects = append(t.objects, obj)
+	}
+	return nil
+}
+
+// Exclude records the resources of the manifests which exist before they are applied, so they are not tracked
+// and the rollback never deletes them, e.g. a namespace shared with the hub. The resources already tracked,
+// e.g. by an interrupted run, are still tracked.
+func (t *Tracker) Exclude(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper, manifests ...string) error {
+	objects, err := parse(manifests)
+	if err != nil {
+		return err
+	}
+	tracked := map[string]bool{}
+	for _, obj := range t.objects {
+		tracked[key(obj)] = true
+	}
+	for _, obj := range objects {
+		if tracked[key(obj)] {
+			continue
+		}
+		client, err := resourceClient(dynamicClient, mapper, obj)
+		if meta.IsNoMatchError(err) {
+			// the crd of the resource is not created, the resource does not exist
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			continue
+		case err != nil:
+			return err
+		}
+		if t.existing == nil {
+			t.existing = map[string]bool{}
+		}
+		t.existing[key(obj)] = true
+	}
+	return nil
+}
+
+// parse returns the resources of the yaml manifests, the empty manifests are skipped
+func parse(manifests []string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for _, m := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(m), &obj.Object); err != nil {
+			return nil, err
+		}
+		if len(obj.Object) == 0 || len(obj.GetKind()) == 0 {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// Objects returns the tracked resources in the order they are applied
+func (t *Tracker) Objects() []*unstructured.Unstructured {
+	return t.objects
+}
+
+// Rollback deletes the tracked resources in reverse order. It waits for each resource but the namespaces
+// to be deleted, so the controllers removing the finalizers are deleted after the resources they manage.
+// The finalizers of a resource not deleted within the timeout are removed.
+func (t *Tracker) Rollback(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper, timeout time.Duration, out io.Writer) error {
+	var errs []error
+	for i := len(t.objects) - 1; i >= 0; i-- {
+		obj := t.objects[i]
+		deleted, err := deleteObject(ctx, dynamicClient, mapper, obj, timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s %s: %v", obj.GetKind(), name(obj), err))
+			continue
+		}
+		if deleted {
+			fmt.Fprintf(out, "%s %s is deleted\n", obj.GetKind(), name(obj))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// resourceClient returns the client of the resource, a no match error if its crd is not created
+func resourceClient(dynamicClient dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// the discovery may be cached before the crd of the resource is created
+		if resettable, ok := mapper.(meta.ResettableRESTMapper); ok {
+			resettable.Reset()
+			mapping, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+	return dynamicClient.Resource(mapping.Resource), nil
+}
+
+func deleteObject(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper,
+	obj *unstructured.Unstructured, timeout time.Duration) (bool, error) {
+	gvk := obj.GroupVersionKind()
+	client, err := resourceClient(dynamicClient, mapper, obj)
+	if meta.IsNoMatchError(err) {
+		// the crd of the resource is not created
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	err = client.Delete(ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if gvk.Kind == "Namespace" {
+		return true, nil
+	}
+
+	err = wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		_, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != wait.ErrWaitTimeout {
+		return true, err
+	}
+
+	klog.Warningf("%s %s is not deleted after %s, removing its finalizers", gvk.Kind, name(obj), timeout)
+	_, err = client.Patch(ctx, obj.GetName(), types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	return true, err
+}
+
+// key identifies the resource
+func key(obj *unstructured.Unstructured) string {
+	return obj.GroupVersionKind().GroupKind().String() + "/" + name(obj)
+}
+
+func name(obj *unstructured.Unstructured) string {
+	return strings.TrimPrefix(obj.GetNamespace()+"/"+obj.GetName(), "/")
+}
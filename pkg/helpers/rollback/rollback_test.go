@@ -0,0 +1,151 @@
+// Copyright Contributors to the Open Cluster Management project
+package rollback
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const (
+	namespace = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: open-cluster-management
+`
+	serviceAccount = `
+# Copyright Contributors to the Open Cluster Management project
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: klusterlet
+  namespace: open-cluster-management
+`
+	klusterlet = `
+apiVersion: operator.open-cluster-management.io/v1
+kind: Klusterlet
+metadata:
+  name: klusterlet
+`
+)
+
+func TestTrack(t *testing.T) {
+	tracker := &Tracker{}
+	if err := tracker.Track(namespace, "", "# only a comment\n", serviceAccount); err != nil {
+		t.Fatal(err)
+	}
+	objects := tracker.Objects()
+	if len(objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objects))
+	}
+	if objects[1].GetKind() != "ServiceAccount" || objects[1].GetNamespace() != "open-cluster-management" {
+		t.Errorf("unexpected object %v", objects[1])
+	}
+}
+
+func TestRollback(t *testing.T) {
+	tracker := &Tracker{}
+	if err := tracker.Track(namespace, serviceAccount, klusterlet); err != nil {
+		t.Fatal(err)
+	}
+
+	namespaces := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	serviceAccounts := schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			namespaces:      "NamespaceList",
+			serviceAccounts: "ServiceAccountList",
+		},
+		tracker.Objects()[0].DeepCopy(), tracker.Objects()[1].DeepCopy())
+
+	// the klusterlet crd is not installed
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"}, meta.RESTScopeNamespace)
+
+	out := &bytes.Buffer{}
+	if err := tracker.Rollback(context.TODO(), dynamicClient, mapper, time.Second, out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "ServiceAccount open-cluster-management/klusterlet is deleted\nNamespace open-cluster-management is deleted\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	for _, obj := range []struct {
+		gvr       schema.GroupVersionResource
+		namespace string
+		name      string
+	}{
+		{gvr: namespaces, name: "open-cluster-management"},
+		{gvr: serviceAccounts, namespace: "open-cluster-management", name: "klusterlet"},
+	} {
+		_, err := dynamicClient.Resource(obj.gvr).Namespace(obj.namespace).Get(context.TODO(), obj.name, metav1.GetOptions{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("expected %s %s to be deleted, got %v", obj.gvr.Resource, obj.name, err)
+		}
+	}
+
+	// the rollback of the deleted resources is a no-op
+	out.Reset()
+	if err := tracker.Rollback(context.TODO(), dynamicClient, mapper, time.Second, out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("expected no deletion, got %q", out.String())
+	}
+}
+
+
+func TestRollbackExisting(t *testing.T) {
+	existing := &Tracker{}
+	if err := existing.Track(namespace); err != nil {
+		t.Fatal(err)
+	}
+	namespaces := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	serviceAccounts := schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	// the namespace exists before the join, e.g. the namespace of the cluster manager on a self-managed hub
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			namespaces:      "NamespaceList",
+			serviceAccounts: "ServiceAccountList",
+		},
+		existing.Objects()[0].DeepCopy())
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"}, meta.RESTScopeNamespace)
+
+	tracker := &Tracker{}
+	if err := tracker.Exclude(context.TODO(), dynamicClient, mapper, namespace, serviceAccount, klusterlet); err != nil {
+		t.Fatal(err)
+	}
+	// the join creates the service account
+	if err := tracker.Track(namespace, serviceAccount); err != nil {
+		t.Fatal(err)
+	}
+	if err := dynamicClient.Tracker().Add(tracker.Objects()[0].DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	if objects := tracker.Objects(); len(objects) != 1 || objects[0].GetKind() != "ServiceAccount" {
+		t.Fatalf("expected only the service account to be tracked, got %v", objects)
+	}
+
+	out := &bytes.Buffer{}
+	if err := tracker.Rollback(context.TODO(), dynamicClient, mapper, time.Second, out); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "ServiceAccount open-cluster-management/klusterlet is deleted\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	if _, err := dynamicClient.Resource(namespaces).Get(context.TODO(), "open-cluster-management", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the existing namespace to be left, got %v", err)
+	}
+}
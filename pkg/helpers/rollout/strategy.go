@@ -0,0 +1,167 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package rollout defines the rollout strategies of the works deployed by clusteradm to several
+// clusters. The ManifestWork API has no rollout strategy, so the strategy is kept in annotations of
+// the works and honored by the clusteradm commands fanning them out.
+package rollout
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// TypeAll rolls out to all the clusters at once
+	TypeAll = "All"
+	// TypeProgressive rolls out to max concurrency clusters at a time
+	TypeProgressive = "Progressive"
+	// TypeProgressivePerGroup rolls out to a group of clusters at a time, the groups are the values of the group label
+	TypeProgressivePerGroup = "ProgressivePerGroup"
+
+	StrategyAnnotation       = "clusteradm.open-cluster-management.io/rollout-strategy"
+	MaxConcurrencyAnnotation = "clusteradm.open-cluster-management.io/rollout-max-concurrency"
+	GroupLabelAnnotation     = "clusteradm.open-cluster-management.io/rollout-group-label"
+	PausedAnnotation         = "clusteradm.open-cluster-management.io/rollout-paused"
+
+	// defaultMaxConcurrency is the max concurrency of the progressive rollouts if not set
+	defaultMaxConcurrency = "25%"
+)
+
+// Types are the supported rollout strategy types
+var Types = []string{TypeAll, TypeProgressive, TypeProgressivePerGroup}
+
+// Strategy is the rollout strategy of a work
+type Strategy struct {
+	Type string
+	// MaxConcurrency is the number or percentage of the clusters rolled out at a time
+	MaxConcurrency intstr.IntOrString
+	// GroupLabel is the label of the clusters grouping them, defaults to the clusterset label
+	GroupLabel string
+	// Paused stops the rollout to the clusters not rolled out yet
+	Paused bool
+}
+
+// FromAnnotations returns the strategy in the annotations, the strategy is All if not set
+func FromAnnotations(annotations map[string]string) (*Strategy, error) {
+	s := &Strategy{Type: TypeAll}
+	if t, ok := annotations[StrategyAnnotation]; ok {
+		s.Type = t
+	}
+	if maxConcurrency, ok := annotations[MaxConcurrencyAnnotation]; ok {
+		s.MaxConcurrency = intstr.Parse(maxConcurrency)
+	}
+	s.GroupLabel = annotations[GroupLabelAnnotation]
+	if paused, ok := annotations[PausedAnnotation]; ok {
+		p, err := strconv.ParseBool(paused)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation %q: %v", PausedAnnotation, paused, err)
+		}
+		s.Paused = p
+	}
+	return s, s.Validate()
+}
+
+// Validate returns an error if the type or max concurrency are invalid
+func (s *Strategy) Validate() error {
+	switch s.Type {
+	case TypeAll, TypeProgressive, TypeProgressivePerGroup:
+	default:
+		return fmt.Errorf("unsupported rollout strategy %q, should be one of %v", s.Type, Types)
+	}
+	if !s.hasMaxConcurrency() {
+		return nil
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(&s.MaxConcurrency, 100, true)
+	if err != nil {
+		return fmt.Errorf("invalid max concurrency %q: %v", s.MaxConcurrency.String(), err)
+	}
+	if value <= 0 {
+		return fmt.Errorf("the max concurrency %q should be positive", s.MaxConcurrency.String())
+	}
+	return nil
+}
+
+// IsPaused returns true if the rollout of the work is paused
+func IsPaused(obj metav1.Object) bool {
+	paused, _ := strconv.ParseBool(obj.GetAnnotations()[PausedAnnotation])
+	return paused
+}
+
+// Batches returns the names of the clusters rolled out at a time, in order
+func (s *Strategy) Batches(clusters []clusterv1.ManagedCluster) [][]string {
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil
+	}
+
+	switch s.Type {
+	case TypeProgressive:
+		return chunk(names, s.maxConcurrency(len(names), defaultMaxConcurrency))
+	case TypeProgressivePerGroup:
+		groupLabel := s.GroupLabel
+		if len(groupLabel) == 0 {
+			groupLabel = clusterv1beta1.ClusterSetLabel
+		}
+		groups := map[string][]string{}
+		for _, cluster := range clusters {
+			group := cluster.Labels[groupLabel]
+			groups[group] = append(groups[group], cluster.Name)
+		}
+		values := make([]string, 0, len(groups))
+		for value := range groups {
+			values = append(values, value)
+		}
+		// the clusters without the label are rolled out last
+		sort.Slice(values, func(i, j int) bool {
+			if values[i] == "" || values[j] == "" {
+				return values[j] == ""
+			}
+			return values[i] < values[j]
+		})
+		var batches [][]string
+		for _, value := range values {
+			group := groups[value]
+			sort.Strings(group)
+			// the max concurrency applies within each group, the whole group by default
+			batches = append(batches, chunk(group, s.maxConcurrency(len(group), "100%"))...)
+		}
+		return batches
+	}
+	return [][]string{names}
+}
+
+func (s *Strategy) maxConcurrency(total int, defaultValue string) int {
+	maxConcurrency := s.MaxConcurrency
+	if !s.hasMaxConcurrency() {
+		maxConcurrency = intstr.FromString(defaultValue)
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(&maxConcurrency, total, true)
+	if err != nil || value <= 0 {
+		return 1
+	}
+	return value
+}
+
+// hasMaxConcurrency returns false if the max concurrency is not set, its zero value is not valid
+func (s *Strategy) hasMaxConcurrency() bool {
+	return s.MaxConcurrency.String() != "" && s.MaxConcurrency.String() != "0"
+}
+
+func chunk(names []string, size int) [][]string {
+	var batches [][]string
+	for len(names) > size {
+		batches = append(batches, names[:size])
+		names = names[size:]
+	}
+	return append(batches, names)
+}
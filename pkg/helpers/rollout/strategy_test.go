@@ -0,0 +1,94 @@
+// Copyright Contributors to the Open Cluster Management project
+package rollout
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestFromAnnotations(t *testing.T) {
+	s, err := FromAnnotations(nil)
+	if err != nil || s.Type != TypeAll || s.Paused {
+		t.Errorf("unexpected default strategy %v, error %v", s, err)
+	}
+
+	s, err = FromAnnotations(map[string]string{
+		StrategyAnnotation:       TypeProgressive,
+		MaxConcurrencyAnnotation: "2",
+		PausedAnnotation:         "true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Type != TypeProgressive || s.MaxConcurrency != intstr.FromInt(2) || !s.Paused {
+		t.Errorf("unexpected strategy %v", s)
+	}
+
+	invalid := []map[string]string{
+		{StrategyAnnotation: "Canary"},
+		{MaxConcurrencyAnnotation: "-1"},
+		{MaxConcurrencyAnnotation: "half"},
+		{PausedAnnotation: "maybe"},
+	}
+	for _, annotations := range invalid {
+		if _, err := FromAnnotations(annotations); err == nil {
+			t.Errorf("expected an error for %v", annotations)
+		}
+	}
+}
+
+func TestBatches(t *testing.T) {
+	cluster := func(name, group string) clusterv1.ManagedCluster {
+		c := clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if len(group) > 0 {
+			c.Labels = map[string]string{"region": group}
+		}
+		return c
+	}
+	clusters := []clusterv1.ManagedCluster{
+		cluster("c5", ""), cluster("c4", "west"), cluster("c3", "east"), cluster("c2", "west"), cluster("c1", "east"),
+	}
+
+	cases := []struct {
+		name     string
+		strategy Strategy
+		expected [][]string
+	}{
+		{
+			name:     "all",
+			strategy: Strategy{Type: TypeAll},
+			expected: [][]string{{"c1", "c2", "c3", "c4", "c5"}},
+		},
+		{
+			name:     "progressive",
+			strategy: Strategy{Type: TypeProgressive, MaxConcurrency: intstr.FromInt(2)},
+			expected: [][]string{{"c1", "c2"}, {"c3", "c4"}, {"c5"}},
+		},
+		{
+			name:     "progressive default 25%",
+			strategy: Strategy{Type: TypeProgressive},
+			expected: [][]string{{"c1", "c2"}, {"c3", "c4"}, {"c5"}},
+		},
+		{
+			name:     "per group",
+			strategy: Strategy{Type: TypeProgressivePerGroup, GroupLabel: "region"},
+			expected: [][]string{{"c1", "c3"}, {"c2", "c4"}, {"c5"}},
+		},
+		{
+			name:     "per group with max concurrency",
+			strategy: Strategy{Type: TypeProgressivePerGroup, GroupLabel: "region", MaxConcurrency: intstr.FromInt(1)},
+			expected: [][]string{{"c1"}, {"c3"}, {"c2"}, {"c4"}, {"c5"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if batches := c.strategy.Batches(clusters); !reflect.DeepEqual(batches, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, batches)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package runner holds the flags and streams of the clusteradm commands run from Go by the library packages,
+// e.g. pkg/join, with the clients of a RESTClientGetter instead of the kubeconfig flags of the CLI.
+package runner
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	genericclioptionsclusteradm "open-cluster-management.io/clusteradm/pkg/genericclioptions"
+)
+
+// DefaultTimeout is the timeout of the commands, the default of --timeout
+const DefaultTimeout = 300 * time.Second
+
+// NewFlags returns the clusteradm flags of a command building its clients from the getter, e.g. the
+// genericclioptions.ConfigFlags of a kubeconfig
+func NewFlags(getter genericclioptions.RESTClientGetter, dryRun bool, timeout time.Duration) *genericclioptionsclusteradm.ClusteradmFlags {
+	flags := genericclioptionsclusteradm.NewClusteradmFlags(cmdutil.NewFactory(getter))
+	flags.DryRun = dryRun
+	flags.Timeout = int(timeout.Seconds())
+	return flags
+}
+
+// Timeout returns the timeout if set, else the time left to the deadline of the context, else the default
+func Timeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return DefaultTimeout
+}
+
+// Streams returns the streams of a command writing to out and errOut, the output is discarded if nil
+func Streams(out, errOut io.Writer) genericclioptions.IOStreams {
+	if out == nil {
+		out = io.Discard
+	}
+	if errOut == nil {
+		errOut = io.Discard
+	}
+	return genericclioptions.IOStreams{Out: out, ErrOut: errOut}
+}
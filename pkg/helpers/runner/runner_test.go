@@ -0,0 +1,22 @@
+// Copyright Contributors to the Open Cluster Management project
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	if got := Timeout(context.Background(), 0); got != DefaultTimeout {
+		t.Errorf("expected the default timeout, got %s", got)
+	}
+	if got := Timeout(context.Background(), time.Minute); got != time.Minute {
+		t.Errorf("expected 1m, got %s", got)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if got := Timeout(ctx, 0); got <= 59*time.Minute || got > time.Hour {
+		t.Errorf("expected the time left to the deadline, got %s", got)
+	}
+}
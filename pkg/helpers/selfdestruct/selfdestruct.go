@@ -0,0 +1,107 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package selfdestruct removes the klusterlet of a managed cluster from the hub, without the kubeconfig
+// of the cluster: a manifestwork takes the ownership of the klusterlet and deletes it when it is deleted,
+// the klusterlet operator then removes the agents, including the work agent itself.
+package selfdestruct
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// fieldManager owns the klusterlet applied server side, no field of its spec is set
+	fieldManager = "clusteradm"
+	pollInterval = 2 * time.Second
+)
+
+// WorkName returns the name of the manifestwork removing the klusterlet, a cluster registered to several
+// hubs runs a klusterlet per hub
+func WorkName(klusterletName string) string {
+	return klusterletName + "-self-destruct"
+}
+
+// Work returns the manifestwork of the klusterlet of the cluster. It only owns the klusterlet with server
+// side apply, the spec of the klusterlet is kept, and deletes it when the manifestwork is deleted.
+func Work(cluster, klusterletName string) *workapiv1.ManifestWork {
+	klusterlet := []byte(fmt.Sprintf(`{"apiVersion":"operator.open-cluster-management.io/v1","kind":"Klusterlet","metadata":{"name":%q}}`,
+		klusterletName))
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: WorkName(klusterletName), Namespace: cluster},
+		Spec: workapiv1.ManifestWorkSpec{
+			Workload: workapiv1.ManifestsTemplate{
+				Manifests: []workapiv1.Manifest{{RawExtension: runtime.RawExtension{Raw: klusterlet}}},
+			},
+			DeleteOption: &workapiv1.DeleteOption{PropagationPolicy: workapiv1.DeletePropagationPolicyTypeForeground},
+			ManifestConfigs: []workapiv1.ManifestConfigOption{
+				{
+					ResourceIdentifier: workapiv1.ResourceIdentifier{
+						Group:    "operator.open-cluster-management.io",
+						Resource: "klusterlets",
+						Name:     klusterletName,
+					},
+					UpdateStrategy: &workapiv1.UpdateStrategy{
+						Type:            workapiv1.UpdateStrategyTypeServerSideApply,
+						ServerSideApply: &workapiv1.ServerSideApplyConfig{FieldManager: fieldManager},
+					},
+				},
+			},
+		},
+	}
+}
+
+// RemoveKlusterlet applies the manifestwork of the klusterlet and deletes it once applied. The work agent is
+// removed before it can remove the finalizer of the manifestwork, which is removed once the timeout is reached.
+// The manifestwork is deleted if it is not applied within the timeout, e.g. the agent is not running.
+func RemoveKlusterlet(ctx context.Context, workClient workclientset.Interface, cluster, klusterletName string,
+	timeout time.Duration, errOut io.Writer) error {
+	works := workClient.WorkV1().ManifestWorks(cluster)
+	name := WorkName(klusterletName)
+	if _, err := works.Create(ctx, Work(cluster, klusterletName), metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	err := wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		work, err := works.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkApplied), nil
+	})
+	if err != nil {
+		_ = works.Delete(ctx, name, metav1.DeleteOptions{})
+		return fmt.Errorf("the manifestwork %s is not applied on cluster %s, its agent may not be running: %v", name, cluster, err)
+	}
+
+	if err := works.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	err = wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		_, err := works.Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != wait.ErrWaitTimeout {
+		return err
+	}
+	fmt.Fprintf(errOut, "the work agent of cluster %s did not release the manifestwork %s, its finalizers are removed\n", cluster, name)
+	_, err = works.Patch(ctx, name, types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+// Copyright Contributors to the Open Cluster Management project
+package selfdestruct
+
+import (
+	"encoding/json"
+	"testing"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestWork(t *testing.T) {
+	work := Work("cluster1", "hub2")
+	if work.Namespace != "cluster1" || work.Name != "hub2-self-destruct" {
+		t.Errorf("unexpected manifestwork %s/%s", work.Namespace, work.Name)
+	}
+	if work.Spec.DeleteOption == nil || work.Spec.DeleteOption.PropagationPolicy != workapiv1.DeletePropagationPolicyTypeForeground {
+		t.Errorf("expected the klusterlet to be deleted with the manifestwork")
+	}
+	klusterlet := map[string]interface{}{}
+	if err := json.Unmarshal(work.Spec.Workload.Manifests[0].Raw, &klusterlet); err != nil {
+		t.Fatal(err)
+	}
+	// the spec of the klusterlet is not owned by the manifestwork
+	if _, ok := klusterlet["spec"]; ok || klusterlet["kind"] != "Klusterlet" {
+		t.Errorf("expected a klusterlet without spec, got %v", klusterlet)
+	}
+	metadata, _ := klusterlet["metadata"].(map[string]interface{})
+	if metadata["name"] != "hub2" {
+		t.Errorf("expected the klusterlet hub2, got %v", klusterlet)
+	}
+	config := work.Spec.ManifestConfigs[0]
+	if config.ResourceIdentifier.Name != "hub2" || config.UpdateStrategy.Type != workapiv1.UpdateStrategyTypeServerSideApply ||
+		config.UpdateStrategy.ServerSideApply.FieldManager != fieldManager {
+		t.Errorf("unexpected manifest config %v", config)
+	}
+}
+
+func TestWorkName(t *testing.T) {
+	// the name of the manifestwork of the default klusterlet is unchanged
+	if name := WorkName("klusterlet"); name != "klusterlet-self-destruct" {
+		t.Errorf("unexpected name %s", name)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package stamp sets the labels and annotations of --labels and --annotations on the objects created,
+// updated or patched by clusteradm, e.g. for cost allocation, backup selection or policy exemptions.
+package stamp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"open-cluster-management.io/clusteradm/pkg/helpers/audit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/keyvalues"
+)
+
+var (
+	// labels and annotations are the values of the flags, in the format of key=value
+	labels      []string
+	annotations []string
+
+	// current is the metadata set on the objects, nil if none
+	current *metadata
+)
+
+type metadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// AddFlags adds --labels and --annotations to the flags of a command
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVar(&labels, "labels", []string{},
+		"The labels set on every resource created or updated by the command, in the format of key=value. The labels of the resources are kept")
+	flags.StringSliceVar(&annotations, "annotations", []string{},
+		"The annotations set on every resource created or updated by the command, in the format of key=value. The annotations of the resources are kept")
+}
+
+// Setup parses the labels and annotations of the flags, the objects created, updated or patched afterwards
+// by the clients wrapped by WrapConfig are stamped with them.
+func Setup() error {
+	current = nil
+	if len(labels) == 0 && len(annotations) == 0 {
+		return nil
+	}
+	m := &metadata{}
+	var err error
+	if m.labels, err = keyvalues.Parse(labels); err != nil {
+		return fmt.Errorf("invalid --labels: %v", err)
+	}
+	for key, value := range m.labels {
+		if errs := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...); len(errs) > 0 {
+			return fmt.Errorf("invalid label %s=%s: %s", key, value, strings.Join(errs, ", "))
+		}
+	}
+	if m.annotations, err = keyvalues.Parse(annotations); err != nil {
+		return fmt.Errorf("invalid --annotations: %v", err)
+	}
+	for key := range m.annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation %s: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	current = m
+	return nil
+}
+
+// WrapConfig stamps the objects created, updated or patched with the rest config with the labels and
+// annotations set up. The subresources, e.g. status, are not stamped.
+func WrapConfig(config *rest.Config) *rest.Config {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{delegate: rt}
+	})
+	return config
+}
+
+type roundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	m := current
+	if m == nil || req.Body == nil || audit.IsSubresource(req.URL.Path) {
+		return rt.delegate.RoundTrip(req)
+	}
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return rt.delegate.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if stamped, ok := stamp(body, m); ok {
+		body = stamped
+	}
+	// a round tripper must not modify the request
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	req.ContentLength = int64(len(body))
+	return rt.delegate.RoundTrip(req)
+}
+
+// stamp adds the labels and annotations missing on the JSON object of the body, the values of the object
+// are kept so the selectors of the templates are not changed. It returns false if the body is not a JSON
+// object, e.g. a protobuf object or a JSON patch
+func stamp(body []byte, m *metadata) ([]byte, bool) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, false
+	}
+	objMeta, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		objMeta = map[string]interface{}{}
+		obj["metadata"] = objMeta
+	}
+	merge(objMeta, "labels", m.labels)
+	merge(objMeta, "annotations", m.annotations)
+	stamped, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false
+	}
+	return stamped, true
+}
+
+func merge(objMeta map[string]interface{}, field string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	existing, ok := objMeta[field].(map[string]interface{})
+	if !ok {
+		existing = map[string]interface{}{}
+		objMeta[field] = existing
+	}
+	for key, value := range values {
+		if _, ok := existing[key]; !ok {
+			existing[key] = value
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright Contributors to the Open Cluster Management project
+package stamp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestSetup(t *testing.T) {
+	defer func() { labels, annotations, current = nil, nil, nil }()
+	cases := []struct {
+		labels      []string
+		annotations []string
+		expectedErr bool
+	}{
+		{},
+		{labels: []string{"cost-center=platform"}, annotations: []string{"example.com/owner=platform team"}},
+		{labels: []string{"cost-center"}, expectedErr: true},
+		{labels: []string{"cost-center=platform team"}, expectedErr: true},
+		{annotations: []string{"owner/of/it=team"}, expectedErr: true},
+	}
+	for _, c := range cases {
+		labels, annotations = c.labels, c.annotations
+		err := Setup()
+		if c.expectedErr != (err != nil) {
+			t.Errorf("%v %v: unexpected error %v", c.labels, c.annotations, err)
+		}
+		if err == nil && (current != nil) != (len(c.labels)+len(c.annotations) > 0) {
+			t.Errorf("%v %v: unexpected metadata %v", c.labels, c.annotations, current)
+		}
+	}
+}
+
+func TestWrapConfig(t *testing.T) {
+	defer func() { labels, annotations, current = nil, nil, nil }()
+	labels, annotations = []string{"cost-center=platform", "app=other"}, []string{"owner=platform-team"}
+	if err := Setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	bodies := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies[r.Method+" "+r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+	config := WrapConfig(&rest.Config{Host: server.URL})
+	client := &http.Client{Transport: config.WrapTransport(http.DefaultTransport)}
+
+	send := func(method, path, body string) {
+		req, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	send(http.MethodPost, "/apis/apps/v1/namespaces/open-cluster-management/deployments",
+		`{"metadata":{"name":"cluster-manager","labels":{"app":"cluster-manager"}}}`)
+	send(http.MethodPatch, "/api/v1/namespaces/open-cluster-management/configmaps/config", `[{"op":"remove","path":"/data"}]`)
+	send(http.MethodPut, "/api/v1/namespaces/open-cluster-management/pods/pod1/status", `{"metadata":{"name":"pod1"}}`)
+
+	obj := struct {
+		Metadata struct {
+			Labels      map[string]string `json:"labels"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal([]byte(bodies["POST /apis/apps/v1/namespaces/open-cluster-management/deployments"]), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"app": "cluster-manager", "cost-center": "platform"}; !reflect.DeepEqual(obj.Metadata.Labels, expected) {
+		t.Errorf("expected the labels %v, got %v", expected, obj.Metadata.Labels)
+	}
+	if expected := map[string]string{"owner": "platform-team"}; !reflect.DeepEqual(obj.Metadata.Annotations, expected) {
+		t.Errorf("expected the annotations %v, got %v", expected, obj.Metadata.Annotations)
+	}
+	if body := bodies["PATCH /api/v1/namespaces/open-cluster-management/configmaps/config"]; body != `[{"op":"remove","path":"/data"}]` {
+		t.Errorf("expected the JSON patch to be kept, got %s", body)
+	}
+	if body := bodies["PUT /api/v1/namespaces/open-cluster-management/pods/pod1/status"]; body != `{"metadata":{"name":"pod1"}}` {
+		t.Errorf("expected the status to be kept, got %s", body)
+	}
+}
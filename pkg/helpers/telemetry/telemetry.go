@@ -0,0 +1,168 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package telemetry reports the result of the init and join runs to an endpoint chosen by the user, to track the
+// reliability of the rollouts. It is opt-in and the reports carry no identifying data: no names, addresses, flags
+// or error messages, only the class of the error and a one-way fingerprint of the cluster.
+package telemetry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"open-cluster-management.io/clusteradm"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+	"open-cluster-management.io/clusteradm/pkg/helpers/webhook"
+)
+
+const (
+	// EndpointEnv is the environment variable of the default endpoint
+	EndpointEnv = "CLUSTERADM_TELEMETRY_ENDPOINT"
+	// fingerprintSalt makes the fingerprint specific to the telemetry of clusteradm
+	fingerprintSalt = "clusteradm-telemetry"
+	sendTimeout     = 5 * time.Second
+
+	ResultSucceeded = "Succeeded"
+	ResultFailed    = "Failed"
+)
+
+// Options reports the result of the command to the endpoint if enabled
+type Options struct {
+	// Enabled is true if the result is reported, it is false by default
+	Enabled bool
+	// Endpoint is the http(s) url the report is posted to
+	Endpoint string
+}
+
+func (t *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&t.Enabled, "telemetry", false, "If true, an anonymized report of the result, the Kubernetes version and the class of "+
+		"the error is posted to --telemetry-endpoint. No names, addresses, flags or error messages are reported")
+	fs.StringVar(&t.Endpoint, "telemetry-endpoint", os.Getenv(EndpointEnv),
+		"The http(s) url the telemetry report is posted to, defaults to the "+EndpointEnv+" environment variable. Only used with --telemetry")
+}
+
+func (t *Options) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if len(t.Endpoint) == 0 {
+		return fmt.Errorf("--telemetry-endpoint or the %s environment variable is required with --telemetry", EndpointEnv)
+	}
+	u, err := url.Parse(t.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("invalid --telemetry-endpoint %s, it must be an http(s) url", t.Endpoint)
+	}
+	return nil
+}
+
+// Report is the anonymized result of a run
+type Report struct {
+	Command           string `json:"command"`
+	Result            string `json:"result"`
+	ErrorClass        string `json:"errorClass,omitempty"`
+	ClusteradmVersion string `json:"clusteradmVersion"`
+	BundleVersion     string `json:"bundleVersion,omitempty"`
+	// KubernetesVersion is the major and minor version of the cluster, without the build and vendor suffixes
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Fingerprint is a salted hash of the UID of the kube-system namespace, it identifies the runs on the same
+	// cluster without identifying the cluster
+	Fingerprint     string  `json:"fingerprint,omitempty"`
+	OS              string  `json:"os"`
+	Arch            string  `json:"arch"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// NewReport returns the report of the command started at start, which ended now with err. The version and the
+// fingerprint of the cluster are left empty if they can not be read.
+func NewReport(kubeClient kubernetes.Interface, command, bundleVersion string, start time.Time, err error) Report {
+	report := Report{
+		Command:           command,
+		Result:            ResultSucceeded,
+		ClusteradmVersion: strings.TrimSpace(clusteradm.GetVersion()),
+		BundleVersion:     bundleVersion,
+		OS:                runtime.GOOS,
+		Arch:              runtime.GOARCH,
+		DurationSeconds:   time.Since(start).Round(time.Second).Seconds(),
+	}
+	if err != nil {
+		report.Result = ResultFailed
+		report.ErrorClass = ErrorClass(err)
+	}
+	if kubeClient == nil {
+		return report
+	}
+	if info, err := kubeClient.Discovery().ServerVersion(); err == nil {
+		report.KubernetesVersion = minorVersion(info.Major, info.Minor)
+	}
+	if ns, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), metav1.NamespaceSystem, metav1.GetOptions{}); err == nil {
+		report.Fingerprint = fingerprint(string(ns.UID))
+	}
+	return report
+}
+
+// ErrorClass returns the class of the error from its exit code, the message is never reported
+func ErrorClass(err error) string {
+	switch exit.CodeOf(err) {
+	case exit.CodeOK:
+		return ""
+	case exit.CodePreflight:
+		return "Preflight"
+	case exit.CodeTimeout:
+		return "Timeout"
+	case exit.CodePartial:
+		return "Partial"
+	case exit.CodeValidation:
+		return "Validation"
+	default:
+		return "Generic"
+	}
+}
+
+// minorVersion returns <major>.<minor>, the minor of some vendors has a suffix, e.g. 24+
+func minorVersion(major, minor string) string {
+	minor = strings.TrimRight(minor, "+")
+	if len(major) == 0 || len(minor) == 0 {
+		return ""
+	}
+	return major + "." + minor
+}
+
+func fingerprint(uid string) string {
+	if len(uid) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(fingerprintSalt + ":" + uid))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Send posts the report to the endpoint
+func Send(endpoint string, report Report) error {
+	return webhook.Post(endpoint, report, sendTimeout)
+}
+
+// Record reports the result of the command if the telemetry is enabled, a failure is printed as a warning
+// as the command already ran
+func (t *Options) Record(f cmdutil.Factory, command, bundleVersion string, start time.Time, err error, errOut io.Writer) {
+	if !t.Enabled {
+		return
+	}
+	// the report is sent without the cluster data if the client can not be built
+	var client kubernetes.Interface
+	if kubeClient, clientErr := f.KubernetesClientSet(); clientErr == nil {
+		client = kubeClient
+	}
+	if err := Send(t.Endpoint, NewReport(client, command, bundleVersion, start, err)); err != nil {
+		fmt.Fprintf(errOut, "WARNING: failed to send the telemetry report to %s: %v\n", webhook.Redact(t.Endpoint), err)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright Contributors to the Open Cluster Management project
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"open-cluster-management.io/clusteradm/pkg/helpers/exit"
+)
+
+func TestNewReport(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceSystem, UID: "5f0e1a2b-uid"},
+	})
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{
+		Major: "1", Minor: "24+", GitVersion: "v1.24.3-eks-private.example.com",
+	}
+
+	report := NewReport(kubeClient, "join", "v0.9.0", time.Now(), exit.Timeout(fmt.Errorf("cluster1.example.com not ready")))
+	if report.Result != ResultFailed || report.ErrorClass != "Timeout" || report.KubernetesVersion != "1.24" || report.BundleVersion != "v0.9.0" {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if report.Fingerprint != fingerprint("5f0e1a2b-uid") || len(report.Fingerprint) != 16 {
+		t.Errorf("unexpected fingerprint %s", report.Fingerprint)
+	}
+	data, _ := json.Marshal(report)
+	for _, identifying := range []string{"example.com", "5f0e1a2b-uid", "cluster1"} {
+		if strings.Contains(string(data), identifying) {
+			t.Errorf("the report %s contains %s", data, identifying)
+		}
+	}
+
+	report = NewReport(nil, "init", "", time.Now(), nil)
+	if report.Result != ResultSucceeded || len(report.ErrorClass) > 0 || len(report.Fingerprint) > 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		options Options
+		valid   bool
+	}{
+		{options: Options{}, valid: true},
+		{options: Options{Endpoint: "not an url"}, valid: true},
+		{options: Options{Enabled: true}, valid: false},
+		{options: Options{Enabled: true, Endpoint: "example.com/report"}, valid: false},
+		{options: Options{Enabled: true, Endpoint: "https://telemetry.example.com/report"}, valid: true},
+	}
+	for _, c := range cases {
+		if err := c.options.Validate(); (err == nil) != c.valid {
+			t.Errorf("%+v: expected valid %t, got %v", c.options, c.valid, err)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	if err := Send(server.URL, Report{Command: "join", Result: ResultSucceeded}); err != nil {
+		t.Fatal(err)
+	}
+	if received.Command != "join" || received.Result != ResultSucceeded {
+		t.Errorf("unexpected report received %+v", received)
+	}
+	server.Config.Handler = http.NotFoundHandler()
+	if err := Send(server.URL, Report{}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright Contributors to the Open Cluster Management project
+package testing
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// RestrictToRules makes the fake client forbid the actions which are not allowed by the rbac rules,
+// as the hub forbids the api calls of a serviceaccount bound to the rules.
+func RestrictToRules(fake *clienttesting.Fake, rules []rbacv1.PolicyRule) {
+	fake.PrependReactor("*", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		for _, rule := range rules {
+			if ruleAllows(rule, action) {
+				return false, nil, nil
+			}
+		}
+		gvr := action.GetResource()
+		resource := gvr.Resource
+		if len(action.GetSubresource()) > 0 {
+			resource += "/" + action.GetSubresource()
+		}
+		return true, nil, errors.NewForbidden(gvr.GroupResource(), actionName(action),
+			fmt.Errorf("%s %s is not allowed by the rules", action.GetVerb(), resource))
+	})
+}
+
+func ruleAllows(rule rbacv1.PolicyRule, action clienttesting.Action) bool {
+	gvr := action.GetResource()
+	resource := gvr.Resource
+	if len(action.GetSubresource()) > 0 {
+		resource += "/" + action.GetSubresource()
+	}
+	if !matches(rule.Verbs, action.GetVerb()) || !matches(rule.APIGroups, gvr.Group) || !matches(rule.Resources, resource) {
+		return false
+	}
+	return len(rule.ResourceNames) == 0 || matches(rule.ResourceNames, actionName(action))
+}
+
+func matches(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// actionName returns the name of the object of the action, it is empty for the list and watch actions
+func actionName(action clienttesting.Action) string {
+	switch a := action.(type) {
+	case clienttesting.GetAction:
+		return a.GetName()
+	case clienttesting.DeleteAction:
+		return a.GetName()
+	case clienttesting.PatchAction:
+		return a.GetName()
+	case clienttesting.CreateAction:
+		if accessor, err := meta.Accessor(a.GetObject()); err == nil {
+			return accessor.GetName()
+		}
+	case clienttesting.UpdateAction:
+		if accessor, err := meta.Accessor(a.GetObject()); err == nil {
+			return accessor.GetName()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package tlspolicy restricts the TLS versions and cipher suites of the connections to the clusters,
+// for the environments where only FIPS approved algorithms are allowed.
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/client-go/rest"
+	cliflag "k8s.io/component-base/cli/flag"
+)
+
+// Policy is the minimum TLS version and the cipher suites allowed
+type Policy struct {
+	MinVersion uint16
+	// CipherSuites are the cipher suites of TLS 1.2 and below, all are allowed if empty
+	CipherSuites []uint16
+}
+
+// current is the policy of the running command, nil if no policy is set
+var current *Policy
+
+// Setup parses the policy of the --tls-min-version and --tls-cipher-suites flags, the connections
+// are not restricted if both are empty.
+func Setup(minVersion string, cipherSuites []string) error {
+	if len(minVersion) == 0 && len(cipherSuites) == 0 {
+		current = nil
+		return nil
+	}
+	policy := &Policy{}
+	if len(minVersion) > 0 {
+		version, err := cliflag.TLSVersion(minVersion)
+		if err != nil {
+			return fmt.Errorf("invalid --tls-min-version: %v", err)
+		}
+		policy.MinVersion = version
+	}
+	if len(cipherSuites) > 0 {
+		suites, err := cliflag.TLSCipherSuites(cipherSuites)
+		if err != nil {
+			return fmt.Errorf("invalid --tls-cipher-suites: %v", err)
+		}
+		if policy.MinVersion == tls.VersionTLS13 {
+			return fmt.Errorf("--tls-cipher-suites can not be set with --tls-min-version VersionTLS13, the TLS 1.3 cipher suites are not configurable")
+		}
+		policy.CipherSuites = suites
+	}
+	current = policy
+	return nil
+}
+
+// Current returns the policy of the running command, nil if no policy is set
+func Current() *Policy {
+	return current
+}
+
+// Apply restricts the TLS config to the policy
+func (p *Policy) Apply(config *tls.Config) {
+	if p.MinVersion > 0 {
+		config.MinVersion = p.MinVersion
+	}
+	if len(p.CipherSuites) > 0 {
+		config.CipherSuites = p.CipherSuites
+	}
+}
+
+// WrapConfig restricts the connections of the rest config to the policy of the running command. It must
+// wrap the config before the other wrappers, as it needs the transport of the config.
+func WrapConfig(config *rest.Config) *rest.Config {
+	policy := current
+	if policy == nil {
+		return config
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		transport, ok := rt.(*http.Transport)
+		if !ok {
+			return rt
+		}
+		// the transports are shared, the default transport included
+		transport = transport.Clone()
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		policy.Apply(transport.TLSClientConfig)
+		return transport
+	})
+	return config
+}
+
+// Check verifies the API server of the rest config accepts a TLS handshake restricted to the policy
+type Check struct {
+	Config *rest.Config
+}
+
+func (c Check) Check() (warnings []string, errorList []error) {
+	policy := current
+	if policy == nil {
+		return nil, nil
+	}
+	tlsConfig, err := rest.TLSConfigFor(c.Config)
+	if err != nil {
+		return nil, []error{err}
+	}
+	if tlsConfig == nil {
+		return []string{fmt.Sprintf("%s is not served over TLS", c.Config.Host)}, nil
+	}
+	policy.Apply(tlsConfig)
+	u, err := url.Parse(c.Config.Host)
+	if err != nil {
+		return nil, []error{err}
+	}
+	host := u.Host
+	if len(u.Port()) == 0 {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+	if len(tlsConfig.ServerName) == 0 {
+		tlsConfig.ServerName = u.Hostname()
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", host, tlsConfig)
+	if err != nil {
+		return nil, []error{fmt.Errorf("the API server %s does not support the TLS policy: %v", c.Config.Host, err)}
+	}
+	defer conn.Close()
+	return nil, nil
+}
+
+func (c Check) Name() string {
+	return "TLSPolicy"
+}
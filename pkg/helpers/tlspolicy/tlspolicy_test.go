@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	testinghelper "open-cluster-management.io/clusteradm/pkg/helpers/testing"
+)
+
+func TestSetup(t *testing.T) {
+	defer func() { current = nil }()
+	if err := Setup("", nil); err != nil || Current() != nil {
+		t.Errorf("expected no policy, got %v %v", Current(), err)
+	}
+	if err := Setup("VersionTLS12", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}); err != nil {
+		t.Fatal(err)
+	}
+	if Current().MinVersion != tls.VersionTLS12 || len(Current().CipherSuites) != 1 {
+		t.Errorf("unexpected policy %+v", Current())
+	}
+	if err := Setup("VersionTLS99", nil); err == nil {
+		t.Errorf("expected an error for an unknown version")
+	}
+	if err := Setup("VersionTLS13", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}); err == nil {
+		t.Errorf("expected an error for cipher suites with TLS 1.3")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer func() { current = nil }()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+	config := &rest.Config{
+		Host: server.URL,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+		},
+	}
+
+	if err := Setup("VersionTLS12", nil); err != nil {
+		t.Fatal(err)
+	}
+	warnings, errs := Check{Config: config}.Check()
+	testinghelper.AssertWarnings(t, warnings, nil)
+	testinghelper.AssertErrors(t, errs, nil)
+
+	if err := Setup("VersionTLS13", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := (Check{Config: config}).Check(); len(errs) != 1 {
+		t.Errorf("expected an error for a server without TLS 1.3, got %v", errs)
+	}
+}
+
+func TestWrapConfig(t *testing.T) {
+	defer func() { current = nil }()
+	if err := Setup("VersionTLS13", nil); err != nil {
+		t.Fatal(err)
+	}
+	config := WrapConfig(&rest.Config{Host: "https://127.0.0.1:6443"})
+	rt := config.WrapTransport(http.DefaultTransport)
+	transport, ok := rt.(*http.Transport)
+	if !ok || transport == http.DefaultTransport {
+		t.Fatalf("expected a copy of the transport, got %T", rt)
+	}
+	if transport.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("expected TLS 1.3, got %d", transport.TLSClientConfig.MinVersion)
+	}
+	if defaultTLS := http.DefaultTransport.(*http.Transport).TLSClientConfig; defaultTLS != nil && defaultTLS.MinVersion == tls.VersionTLS13 {
+		t.Errorf("expected the default transport to be unchanged")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright Contributors to the Open Cluster Management project
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/rest"
+)
+
+const tracerName = "open-cluster-management.io/clusteradm"
+
+// rootCtx is the context of the span of the running command, the spans started
+// with a context without span, e.g. the API calls made with context.TODO(), are
+// attached to it.
+var rootCtx = context.Background()
+
+// Setup registers an OTLP/HTTP exporter sending the spans to the endpoint.
+// The endpoint is a host:port or an URL, the spans are not exported if it is empty.
+// The returned function flushes the pending spans and must be called before exiting.
+func Setup(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	if len(endpoint) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{}
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --otel-endpoint %s: %v", endpoint, err)
+		}
+		opts = append(opts, otlptracehttp.WithEndpoint(u.Host))
+		if len(u.Path) > 0 && u.Path != "/" {
+			opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+		}
+		if u.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the trace exporter: %v", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("clusteradm"))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// StartCommand starts the root span of the command, the spans started afterwards
+// without a parent are attached to it. The returned function ends the span.
+func StartCommand(name string) func() {
+	var span trace.Span
+	rootCtx, span = otel.Tracer(tracerName).Start(context.Background(), name)
+	return func() { span.End() }
+}
+
+// Start starts a span, it is a child of the command span if the context has no span.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpan(ctx, trace.SpanFromContext(rootCtx))
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error if any and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WrapConfig instruments the rest config, a span is created for each API call
+func WrapConfig(config *rest.Config) *rest.Config {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{delegate: rt}
+	})
+	return config
+}
+
+type roundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Start(req.Context(), fmt.Sprintf("%s %s", req.Method, req.URL.Path),
+		semconv.HTTPMethodKey.String(req.Method),
+		semconv.HTTPURLKey.String(req.URL.String()),
+	)
+	resp, err := rt.delegate.RoundTrip(req.WithContext(ctx))
+	if resp != nil {
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	End(span, err)
+	return resp, err
+}
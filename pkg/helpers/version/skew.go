@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ImageVersion returns the tag of the image pull spec, it is empty if the image is referenced by digest
+func ImageVersion(pullSpec string) string {
+	if strings.Contains(pullSpec, "@") {
+		return ""
+	}
+	i := strings.LastIndex(pullSpec, ":")
+	// the colon of a registry port is followed by the path
+	if i < 0 || strings.Contains(pullSpec[i+1:], "/") {
+		return ""
+	}
+	return pullSpec[i+1:]
+}
+
+// CheckSkew returns an error if the agents of agentVersion are not supported by the hub of hubVersion.
+// The agents are supported by the hubs of the same minor version and of the next minor version, the
+// versions which are not released versions, like latest, are not checked.
+func CheckSkew(hubVersion, agentVersion string) error {
+	hubMajor, hubMinor, ok := parseMinor(hubVersion)
+	if !ok {
+		return nil
+	}
+	agentMajor, agentMinor, ok := parseMinor(agentVersion)
+	if !ok {
+		return nil
+	}
+	switch {
+	case hubMajor != agentMajor || agentMinor > hubMinor:
+		return fmt.Errorf("the agents %s are newer than the hub %s", agentVersion, hubVersion)
+	case agentMinor < hubMinor-1:
+		return fmt.Errorf("the agents %s are more than one minor version older than the hub %s", agentVersion, hubVersion)
+	}
+	return nil
+}
+
+// Compare returns -1, 0 or 1 if the version a is older than, equal to or newer than the version b,
+// the versions which are not released versions are equal.
+func Compare(a, b string) int {
+	aMajor, aMinor, aPatch, ok := parseVersion(a)
+	if !ok {
+		return 0
+	}
+	bMajor, bMinor, bPatch, ok := parseVersion(b)
+	if !ok {
+		return 0
+	}
+	for _, d := range []int{aMajor - bMajor, aMinor - bMinor, aPatch - bPatch} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseMinor returns the major and minor numbers of a version in the format of [v]x.y[.z]
+func parseMinor(version string) (major, minor int, ok bool) {
+	major, minor, _, ok = parseVersion(version)
+	return major, minor, ok
+}
+
+// parseVersion returns the numbers of a version in the format of [v]x.y[.z], the pre-release and
+// build metadata are ignored
+func parseVersion(version string) (major, minor, patch int, ok bool) {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		numbers[i] = n
+	}
+	return numbers[0], numbers[1], numbers[2], true
+}
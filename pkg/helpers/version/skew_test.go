@@ -0,0 +1,96 @@
+// Copyright Contributors to the Open Cluster Management project
+package version
+
+import "testing"
+
+func TestImageVersion(t *testing.T) {
+	cases := map[string]string{
+		"quay.io/open-cluster-management/registration:v0.9.0":        "v0.9.0",
+		"localhost:5000/open-cluster-management/registration":        "",
+		"localhost:5000/open-cluster-management/registration:latest": "latest",
+		"quay.io/open-cluster-management/registration@sha256:abcdef": "",
+	}
+	for pullSpec, expected := range cases {
+		if got := ImageVersion(pullSpec); got != expected {
+			t.Errorf("expected %q for %s, got %q", expected, pullSpec, got)
+		}
+	}
+}
+
+func TestCheckSkew(t *testing.T) {
+	cases := []struct {
+		hub, agent  string
+		expectedErr bool
+	}{
+		{hub: "v0.9.0", agent: "v0.9.1"},
+		{hub: "v0.10.0", agent: "v0.9.0"},
+		{hub: "v0.11.0", agent: "v0.9.0", expectedErr: true},
+		{hub: "v0.9.0", agent: "v0.10.0", expectedErr: true},
+		{hub: "v1.0.0", agent: "v0.9.0", expectedErr: true},
+		{hub: "latest", agent: "v0.5.0"},
+		{hub: "v0.9.0", agent: ""},
+	}
+	for _, c := range cases {
+		err := CheckSkew(c.hub, c.agent)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("hub %s, agent %s: expected error %v, got %v", c.hub, c.agent, c.expectedErr, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "v0.9.0", b: "0.9.0", expected: 0},
+		{a: "0.9.0", b: "0.9.1", expected: -1},
+		{a: "0.10.0", b: "0.9.1", expected: 1},
+		{a: "v0.10.0-rc.1", b: "v0.10.0", expected: 0},
+		{a: "latest", b: "0.9.1", expected: 0},
+	}
+	for _, c := range cases {
+		if got := Compare(c.a, c.b); got != c.expected {
+			t.Errorf("expected %d comparing %s to %s, got %d", c.expected, c.a, c.b, got)
+		}
+	}
+}
+
+func TestListBundleVersions(t *testing.T) {
+	versions := ListBundleVersions()
+	if versions[0] != "0.5.0" {
+		t.Errorf("expected the oldest version 0.5.0, got %v", versions)
+	}
+	for i := 1; i < len(versions); i++ {
+		if Compare(versions[i-1], versions[i]) >= 0 {
+			t.Errorf("expected the versions in order, got %v", versions)
+		}
+	}
+}
+
+func TestBundleVersionOf(t *testing.T) {
+	if bundle, ok := BundleVersionOf("v0.7.0"); !ok || bundle != "0.7.0" {
+		t.Errorf("expected the bundle 0.7.0, got %s %v", bundle, ok)
+	}
+	if bundle, ok := BundleVersionOf("latest"); !ok || bundle != "latest" {
+		t.Errorf("expected the bundle latest, got %s %v", bundle, ok)
+	}
+	if _, ok := BundleVersionOf("v0.1.0"); ok {
+		t.Errorf("expected no bundle of v0.1.0")
+	}
+}
+
+func TestReleasedBundlesArePinned(t *testing.T) {
+	for _, v := range ListBundleVersions() {
+		bundle, err := GetVersionBundle(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, image := range []string{bundle.Registration, bundle.Placement, bundle.Work, bundle.Operator,
+			bundle.AppAddon, bundle.PolicyAddon, bundle.ObservabilityAddon} {
+			if _, _, _, ok := parseVersion(image); !ok {
+				t.Errorf("expected the images of the bundle %s to be released versions, got %q", v, image)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package version
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -13,6 +14,8 @@ type VersionBundle struct {
 	Operator     string
 	AppAddon     string
 	PolicyAddon  string
+	//ObservabilityAddon: the tag of the stolostron observability operator, which is not released with the OCM bundles
+	ObservabilityAddon string
 }
 
 var defaultBundleVersion = "0.9.1"
@@ -26,79 +29,116 @@ func GetVersionBundle(version string) (VersionBundle, error) {
 	// supporting either "x.y.z" or "vx.y.z" format version
 	version = strings.TrimPrefix(version, "v")
 
+	versionBundleList := versionBundles()
+	if val, ok := versionBundleList[version]; ok {
+		return val, nil
+	}
+	return VersionBundle{}, fmt.Errorf("couldn't find the requested version bundle: %v", version)
+}
+
+// ListBundleVersions returns the released bundle versions, from the oldest to the newest
+func ListBundleVersions() []string {
+	versions := []string{}
+	for version := range versionBundles() {
+		if _, _, _, ok := parseVersion(version); ok {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+func versionBundles() map[string]VersionBundle {
 	versionBundleList := map[string]VersionBundle{}
 
 	// latest
 	versionBundleList["latest"] = VersionBundle{
-		Registration: "latest",
-		Placement:    "latest",
-		Work:         "latest",
-		Operator:     "latest",
-		AppAddon:     "latest",
-		PolicyAddon:  "latest",
+		Registration:       "latest",
+		Placement:          "latest",
+		Work:               "latest",
+		Operator:           "latest",
+		AppAddon:           "latest",
+		PolicyAddon:        "latest",
+		ObservabilityAddon: "latest",
 	}
 
 	// predefined bundle version
 	// TODO: automated version tracking
 	versionBundleList["0.5.0"] = VersionBundle{
-		Registration: "v0.5.0",
-		Placement:    "v0.2.0",
-		Work:         "v0.5.0",
-		Operator:     "v0.5.0",
-		AppAddon:     "v0.5.0",
-		PolicyAddon:  "v0.8.0",
+		Registration:       "v0.5.0",
+		Placement:          "v0.2.0",
+		Work:               "v0.5.0",
+		Operator:           "v0.5.0",
+		AppAddon:           "v0.5.0",
+		PolicyAddon:        "v0.8.0",
+		ObservabilityAddon: "2.4.0",
 	}
 
 	versionBundleList["0.6.0"] = VersionBundle{
-		Registration: "v0.6.0",
-		Placement:    "v0.3.0",
-		Work:         "v0.6.0",
-		Operator:     "v0.6.0",
-		AppAddon:     "v0.6.0",
-		PolicyAddon:  "v0.8.0",
+		Registration:       "v0.6.0",
+		Placement:          "v0.3.0",
+		Work:               "v0.6.0",
+		Operator:           "v0.6.0",
+		AppAddon:           "v0.6.0",
+		PolicyAddon:        "v0.8.0",
+		ObservabilityAddon: "2.4.0",
 	}
 
 	versionBundleList["0.7.0"] = VersionBundle{
-		Registration: "v0.7.0",
-		Placement:    "v0.4.0",
-		Work:         "v0.7.0",
-		Operator:     "v0.7.0",
-		AppAddon:     "v0.7.0",
-		PolicyAddon:  "v0.8.0",
+		Registration:       "v0.7.0",
+		Placement:          "v0.4.0",
+		Work:               "v0.7.0",
+		Operator:           "v0.7.0",
+		AppAddon:           "v0.7.0",
+		PolicyAddon:        "v0.8.0",
+		ObservabilityAddon: "2.5.0",
 	}
 
 	versionBundleList["0.8.0"] = VersionBundle{
-		Registration: "v0.8.0",
-		Placement:    "v0.8.0",
-		Work:         "v0.8.0",
-		Operator:     "v0.8.0",
-		AppAddon:     "v0.8.0",
-		PolicyAddon:  "v0.8.0",
+		Registration:       "v0.8.0",
+		Placement:          "v0.8.0",
+		Work:               "v0.8.0",
+		Operator:           "v0.8.0",
+		AppAddon:           "v0.8.0",
+		PolicyAddon:        "v0.8.0",
+		ObservabilityAddon: "2.5.0",
 	}
 
 	versionBundleList["0.9.0"] = VersionBundle{
-		Registration: "v0.9.0",
-		Placement:    "v0.9.0",
-		Work:         "v0.9.0",
-		Operator:     "v0.9.0",
-		AppAddon:     "v0.9.0",
-		PolicyAddon:  "v0.9.0",
+		Registration:       "v0.9.0",
+		Placement:          "v0.9.0",
+		Work:               "v0.9.0",
+		Operator:           "v0.9.0",
+		AppAddon:           "v0.9.0",
+		PolicyAddon:        "v0.9.0",
+		ObservabilityAddon: "2.6.0",
 	}
 
 	versionBundleList["0.9.1"] = VersionBundle{
-		Registration: "v0.9.0",
-		Placement:    "v0.9.0",
-		Work:         "v0.9.0",
-		Operator:     "v0.9.1",
-		AppAddon:     "v0.9.0",
-		PolicyAddon:  "v0.9.0",
+		Registration:       "v0.9.0",
+		Placement:          "v0.9.0",
+		Work:               "v0.9.0",
+		Operator:           "v0.9.1",
+		AppAddon:           "v0.9.0",
+		PolicyAddon:        "v0.9.0",
+		ObservabilityAddon: "2.6.0",
 	}
 
 	// default
 	versionBundleList["default"] = versionBundleList[defaultBundleVersion]
 
-	if val, ok := versionBundleList[version]; ok {
-		return val, nil
+	return versionBundleList
+}
+
+// BundleVersionOf returns the newest bundle version of the registration image version, false if no bundle has it
+func BundleVersionOf(registrationVersion string) (string, bool) {
+	versions := append(ListBundleVersions(), "latest")
+	for i := len(versions) - 1; i >= 0; i-- {
+		if bundle, err := GetVersionBundle(versions[i]); err == nil && bundle.Registration == registrationVersion {
+			return versions[i], true
+		}
 	}
-	return VersionBundle{}, fmt.Errorf("couldn't find the requested version bundle: %v", version)
+	return "", false
 }
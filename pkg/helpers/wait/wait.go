@@ -16,6 +16,7 @@ import (
 	"k8s.io/client-go/util/retry"
 	"k8s.io/kubectl/pkg/cmd/util"
 	"open-cluster-management.io/clusteradm/pkg/helpers"
+	"open-cluster-management.io/clusteradm/pkg/helpers/i18n"
 	"open-cluster-management.io/clusteradm/pkg/helpers/printer"
 )
 
@@ -24,8 +25,8 @@ func WaitUntilCRDReady(apiExtensionsClient apiextensionsclient.Interface, crdNam
 	b.Duration = 200 * time.Millisecond
 
 	if wait {
-		crdSpinner := printer.NewSpinner("Waiting for CRD to be ready...", time.Second)
-		crdSpinner.FinalMSG = "CRD successfully registered.\n"
+		crdSpinner := printer.NewSpinner(i18n.T("wait.crd"), time.Second)
+		crdSpinner.FinalMSG = i18n.T("wait.crd.done")
 		crdSpinner.Start()
 		defer crdSpinner.Stop()
 	}
@@ -45,11 +46,11 @@ func WaitUntilRegistrationOperatorReady(f util.Factory, timeout int64) error {
 
 	phase := &atomic.Value{}
 	phase.Store("")
-	text := "Waiting for registration operator to become ready..."
+	text := i18n.T("wait.registrationOperator")
 	operatorSpinner := printer.NewSpinnerWithStatus(
 		text,
 		time.Second,
-		"Registration operator is now available.\n",
+		i18n.T("wait.registrationOperator.done"),
 		func() string {
 			return phase.Load().(string)
 		})
@@ -83,7 +84,7 @@ func WaitUntilRegistrationOperatorReady(f util.Factory, timeout int64) error {
 		})
 }
 
-func WaitUntilClusterManagerRegistrationReady(f util.Factory, timeout int64) error {
+func WaitUntilClusterManagerRegistrationReady(f util.Factory, namespace string, timeout int64) error {
 	var restConfig *rest.Config
 	restConfig, err := f.ToRESTConfig()
 	if err != nil {
@@ -96,11 +97,11 @@ func WaitUntilClusterManagerRegistrationReady(f util.Factory, timeout int64) err
 
 	phase := &atomic.Value{}
 	phase.Store("")
-	text := "Waiting for cluster manager registration to become ready..."
+	text := i18n.T("wait.clusterManagerRegistration")
 	clusterManagerSpinner := printer.NewSpinnerWithStatus(
 		text,
 		time.Second,
-		"ClusterManager registration is now available.\n",
+		i18n.T("wait.clusterManagerRegistration.done"),
 		func() string {
 			return phase.Load().(string)
 		})
@@ -109,7 +110,7 @@ func WaitUntilClusterManagerRegistrationReady(f util.Factory, timeout int64) err
 
 	return helpers.WatchUntil(
 		func() (watch.Interface, error) {
-			return client.CoreV1().Pods("open-cluster-management-hub").
+			return client.CoreV1().Pods(namespace).
 				Watch(context.TODO(), metav1.ListOptions{
 					TimeoutSeconds: &timeout,
 					LabelSelector:  "app=clustermanager-registration-controller",
@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package webhook posts JSON payloads to the http(s) endpoints set by the users, e.g. the telemetry endpoint
+// or a Slack incoming webhook. The url of an endpoint may be its credential, it is never printed in full.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Post posts the payload encoded in JSON to the endpoint, the errors never contain the url of the endpoint
+func Post(endpoint string, payload interface{}, timeout time.Duration) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %s", Redact(endpoint))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// the url errors of the client contain the full url
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("the endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Redact returns the scheme and the host of the endpoint, its path and query are dropped
+func Redact(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || len(u.Host) == 0 {
+		return "<invalid url>"
+	}
+	return u.Scheme + "://" + u.Host
+}
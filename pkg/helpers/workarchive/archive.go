@@ -0,0 +1,108 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package workarchive writes the ManifestWorks of a cluster to a gzipped tar archive and reads them back, the
+// archive holds a <name>.yaml file per ManifestWork without its status and server-set metadata, so it can be
+// applied to any cluster namespace of a hub.
+package workarchive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const fileSuffix = ".yaml"
+
+// Sanitize returns the ManifestWork without its status and the metadata set by the hub, the namespace is kept
+// as the cluster the work was archived from
+func Sanitize(work workapiv1.ManifestWork) workapiv1.ManifestWork {
+	return workapiv1.ManifestWork{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: workapiv1.GroupVersion.String(),
+			Kind:       "ManifestWork",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        work.Name,
+			Namespace:   work.Namespace,
+			Labels:      work.Labels,
+			Annotations: work.Annotations,
+		},
+		Spec: work.Spec,
+	}
+}
+
+// Write writes the sanitized works as a gzipped tar archive, sorted by name
+func Write(w io.Writer, works []workapiv1.ManifestWork) error {
+	sorted := append([]workapiv1.ManifestWork{}, works...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, work := range sorted {
+		data, err := yaml.Marshal(Sanitize(work))
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    work.Name + fileSuffix,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// Read reads the works of a gzipped tar archive written by Write
+func Read(r io.Reader) ([]workapiv1.ManifestWork, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the snapshot: %v", err)
+	}
+	defer gr.Close()
+
+	works := []workapiv1.ManifestWork{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the snapshot: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, fileSuffix) {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		work := workapiv1.ManifestWork{}
+		if err := yaml.Unmarshal(data, &work); err != nil {
+			return nil, fmt.Errorf("invalid manifestwork %s in the snapshot: %v", path.Base(header.Name), err)
+		}
+		if work.Kind != "ManifestWork" || len(work.Name) == 0 {
+			return nil, fmt.Errorf("the file %s of the snapshot is not a manifestwork", header.Name)
+		}
+		works = append(works, work)
+	}
+	return works, nil
+}
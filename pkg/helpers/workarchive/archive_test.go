@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+package workarchive
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestWriteRead(t *testing.T) {
+	configmap := []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"}}`)
+	works := []workapiv1.ManifestWork{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "web",
+				Namespace:       "cluster1",
+				Labels:          map[string]string{"app": "web"},
+				ResourceVersion: "42",
+				UID:             "abc",
+				Finalizers:      []string{"cluster.open-cluster-management.io/manifest-work-cleanup"},
+			},
+			Spec: workapiv1.ManifestWorkSpec{Workload: workapiv1.ManifestsTemplate{
+				Manifests: []workapiv1.Manifest{{RawExtension: runtime.RawExtension{Raw: configmap}}},
+			}},
+			Status: workapiv1.ManifestWorkStatus{Conditions: []metav1.Condition{{Type: workapiv1.WorkApplied}}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "cluster1"}},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, works); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 || read[0].Name != "db" || read[1].Name != "web" {
+		t.Fatalf("expected the works sorted by name, got %v", read)
+	}
+	web := read[1]
+	if web.Namespace != "cluster1" || !reflect.DeepEqual(web.Labels, map[string]string{"app": "web"}) {
+		t.Errorf("expected the namespace and labels to be kept, got %v", web.ObjectMeta)
+	}
+	if len(web.ResourceVersion) > 0 || len(web.UID) > 0 || len(web.Finalizers) > 0 || len(web.Status.Conditions) > 0 {
+		t.Errorf("expected the server-set fields to be removed, got %v", web)
+	}
+	if len(web.Spec.Workload.Manifests) != 1 {
+		t.Errorf("expected the manifests to be kept, got %v", web.Spec.Workload.Manifests)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	if _, err := Read(bytes.NewBufferString("not a snapshot")); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package init is the Go API of 'clusteradm init', it deploys the cluster manager on a cluster to
+// make it a hub, for the controllers and provisioning services embedding clusteradm.
+package init
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	initcmd "open-cluster-management.io/clusteradm/pkg/cmd/init"
+	"open-cluster-management.io/clusteradm/pkg/helpers/runner"
+)
+
+// Options are the options of the init, the zero values are the defaults of the CLI
+type Options struct {
+	// BundleVersion is the version of the cluster manager, the default bundle if not set
+	BundleVersion string
+	// Registry is the image registry of the cluster manager, quay.io/open-cluster-management if not set
+	Registry string
+	// UseBootstrapToken returns a bootstrap token instead of the token of the bootstrap service account
+	UseBootstrapToken bool
+	// Wait waits for the cluster manager to be available until the timeout
+	Wait bool
+	// Timeout of Wait, the time left to the deadline of the context if not set, else 300s
+	Timeout time.Duration
+	// ErrOut receives the messages of the CLI, they are discarded if nil
+	ErrOut io.Writer
+}
+
+// Result is what the clusters need to join the hub, e.g. with join.Run
+type Result struct {
+	HubToken     string
+	HubAPIServer string
+	// CAHashes are the hashes of the public key of the hub CA
+	CAHashes []string
+}
+
+// Validate checks the options without connecting to the cluster
+func (o Options) Validate() error {
+	if o.Timeout < 0 {
+		return fmt.Errorf("the timeout should not be negative")
+	}
+	return nil
+}
+
+// config returns the configuration of the init command of the options
+func (o Options) config() initcmd.Config {
+	return initcmd.Config{
+		BundleVersion:     o.BundleVersion,
+		Registry:          o.Registry,
+		UseBootstrapToken: o.UseBootstrapToken,
+		Wait:              o.Wait,
+	}
+}
+
+// Run initializes the hub on the cluster of the getter
+func Run(ctx context.Context, getter genericclioptions.RESTClientGetter, o Options) (*Result, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	flags := runner.NewFlags(getter, false, runner.Timeout(ctx, o.Timeout))
+	result, err := initcmd.NewOptionsOf(flags, runner.Streams(nil, o.ErrOut), o.config()).Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{HubToken: result.HubToken, HubAPIServer: result.HubAPIServer, CAHashes: result.CAHashes}, nil
+}
@@ -0,0 +1,17 @@
+// Copyright Contributors to the Open Cluster Management project
+package init
+
+import (
+	"reflect"
+	"testing"
+
+	initcmd "open-cluster-management.io/clusteradm/pkg/cmd/init"
+)
+
+func TestConfig(t *testing.T) {
+	o := Options{BundleVersion: "v0.6.0", UseBootstrapToken: true}
+	expected := initcmd.Config{BundleVersion: "v0.6.0", UseBootstrapToken: true}
+	if got := o.config(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
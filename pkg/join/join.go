@@ -0,0 +1,116 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package join is the Go API of 'clusteradm join', it deploys the klusterlet on a cluster to register it
+// to a hub, for the controllers and provisioning services embedding clusteradm.
+package join
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	joincmd "open-cluster-management.io/clusteradm/pkg/cmd/join"
+	"open-cluster-management.io/clusteradm/pkg/helpers/runner"
+)
+
+// Options are the options of the join, the zero values are the defaults of the CLI
+type Options struct {
+	// ClusterName is the name of the cluster on the hub
+	ClusterName string
+	// HubAPIServer is the url of the API server of the hub, https://<host>:<port>
+	HubAPIServer string
+	// HubToken is the bootstrap token printed by init or get token on the hub
+	HubToken string
+	// CAFile is the file of the CA of the hub, it is read from the cluster-info of the hub if not set
+	CAFile string
+	// DiscoveryTokenCACertHashes are the sha256:<hex> hashes of the public key of the hub CA the
+	// cluster-info is verified against
+	DiscoveryTokenCACertHashes []string
+	// BundleVersion is the version of the agents, the default bundle if not set
+	BundleVersion string
+	// Registry is the image registry of the agents, quay.io/open-cluster-management if not set
+	Registry string
+	// KlusterletName is the name of the klusterlet of the hub, the clusters registered to several hubs run a
+	// klusterlet per hub. The default klusterlet if not set
+	KlusterletName string
+	// Wait waits for the klusterlet to be available until the timeout
+	Wait bool
+	// Timeout of Wait, the time left to the deadline of the context if not set, else 300s
+	Timeout time.Duration
+	// Out and ErrOut receive the messages of the CLI, they are discarded if nil
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// Result is the registration requested by the join, the cluster must then be accepted on the hub,
+// e.g. with accept.Run
+type Result struct {
+	ClusterName string
+	// KlusterletName is the name of the klusterlet registering the cluster to the hub
+	KlusterletName string
+	// AgentNamespace is the namespace of the registration and work agents on the cluster
+	AgentNamespace string
+	// Images are the images of the klusterlet operator and agents, pinned by their digests once verified
+	Images []string
+	// Available is true if the registration agent is ready, it is only waited for with Wait
+	Available bool
+}
+
+// Validate checks the options without connecting to the clusters
+func (o Options) Validate() error {
+	if errs := validation.IsDNS1123Label(o.ClusterName); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster name %q: %v", o.ClusterName, errs)
+	}
+	u, err := url.Parse(o.HubAPIServer)
+	if err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+		return fmt.Errorf("invalid hub api server %q, it should be https://<host>:<port>", o.HubAPIServer)
+	}
+	if len(o.HubToken) == 0 {
+		return fmt.Errorf("the hub token must be specified")
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("the timeout should not be negative")
+	}
+	return nil
+}
+
+// config returns the configuration of the join command of the options
+func (o Options) config() joincmd.Config {
+	return joincmd.Config{
+		ClusterName:                o.ClusterName,
+		HubAPIServer:               o.HubAPIServer,
+		HubToken:                   o.HubToken,
+		CAFile:                     o.CAFile,
+		DiscoveryTokenCACertHashes: o.DiscoveryTokenCACertHashes,
+		BundleVersion:              o.BundleVersion,
+		Registry:                   o.Registry,
+		KlusterletName:             o.KlusterletName,
+		Wait:                       o.Wait,
+	}
+}
+
+// Run joins the cluster of the getter to the hub
+func Run(ctx context.Context, getter genericclioptions.RESTClientGetter, o Options) (*Result, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	flags := runner.NewFlags(getter, false, runner.Timeout(ctx, o.Timeout))
+	result, err := joincmd.NewOptionsOf(flags, runner.Streams(o.Out, o.ErrOut), o.config()).Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		ClusterName:    result.ClusterName,
+		KlusterletName: result.KlusterletName,
+		AgentNamespace: result.AgentNamespace,
+		Images:         result.Images,
+		Available:      result.Available,
+	}, nil
+}
@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+package join
+
+import (
+	"reflect"
+	"testing"
+
+	joincmd "open-cluster-management.io/clusteradm/pkg/cmd/join"
+)
+
+func TestValidate(t *testing.T) {
+	valid := Options{ClusterName: "cluster1", HubAPIServer: "https://hub:6443", HubToken: "abc.def"}
+	cases := []struct {
+		name        string
+		mutate      func(o *Options)
+		expectedErr bool
+	}{
+		{name: "valid", mutate: func(o *Options) {}},
+		{name: "invalid cluster name", mutate: func(o *Options) { o.ClusterName = "" }, expectedErr: true},
+		{name: "http hub", mutate: func(o *Options) { o.HubAPIServer = "http://hub:6443" }, expectedErr: true},
+		{name: "no token", mutate: func(o *Options) { o.HubToken = "" }, expectedErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := valid
+			c.mutate(&o)
+			if err := o.Validate(); (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	o := Options{
+		ClusterName:                "cluster1",
+		HubAPIServer:               "https://hub:6443",
+		HubToken:                   "abc.def",
+		DiscoveryTokenCACertHashes: []string{"sha256:aa", "sha256:bb"},
+		KlusterletName:             "hub2",
+		Wait:                       true,
+	}
+	expected := joincmd.Config{
+		ClusterName:                "cluster1",
+		HubAPIServer:               "https://hub:6443",
+		HubToken:                   "abc.def",
+		DiscoveryTokenCACertHashes: []string{"sha256:aa", "sha256:bb"},
+		KlusterletName:             "hub2",
+		Wait:                       true,
+	}
+	if got := o.config(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}